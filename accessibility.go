@@ -0,0 +1,30 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+// Announce makes the screen reader of the OS read the given text, e.g., the label of the focused menu item.
+//
+// On browsers, the text is announced via an ARIA live region.
+// On macOS, the text is announced via NSAccessibility.
+// On Windows, the text is announced via UI Automation. This requires Windows 10 version 1709 or later.
+//
+// Announce does nothing on Linux and the other Unix-like systems, as AT-SPI is not supported.
+// Announce does nothing on mobiles so far.
+// Announce does nothing before the main loop starts on desktops.
+//
+// Announce is concurrent-safe.
+func Announce(text string) {
+	uiDriver().Announce(text)
+}
@@ -0,0 +1,257 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/internal/packing"
+)
+
+// atlasPadding is the gap between images on an atlas.
+const atlasPadding = 1
+
+// Atlas is a texture atlas that packs many small images into one backing image.
+//
+// Ebiten already puts small images on internal texture atlases automatically, but which images share a texture is
+// up to Ebiten. Atlas is useful when you want to control it, e.g., to keep thousands of sprites of a scene on one
+// texture so that drawing them never switches textures.
+//
+// Note that this API is experimental.
+type Atlas struct {
+	image   *Image
+	page    *packing.Page
+	size    int
+	entries map[*AtlasImage]struct{}
+}
+
+// AtlasImage is an image packed on an Atlas.
+type AtlasImage struct {
+	atlas  *Atlas
+	node   *packing.Node
+	width  int
+	height int
+
+	// sub is the cache of the sub-image on the backing image.
+	sub *Image
+}
+
+// NewAtlas returns a new empty atlas whose backing image is size x size pixels.
+//
+// If size is less than 1 or more than device-dependent maximum size, NewAtlas panics.
+func NewAtlas(size int) *Atlas {
+	img, _ := NewImage(size, size, FilterDefault)
+	return &Atlas{
+		image:   img,
+		page:    packing.NewPage(size, size),
+		size:    size,
+		entries: map[*AtlasImage]struct{}{},
+	}
+}
+
+// Size returns the width and the height of the backing image.
+func (a *Atlas) Size() int {
+	return a.size
+}
+
+// Image returns the backing image.
+//
+// The backing image is replaced by Defragment.
+func (a *Atlas) Image() *Image {
+	return a.image
+}
+
+// Len returns the number of the images on the atlas.
+func (a *Atlas) Len() int {
+	return len(a.entries)
+}
+
+// Add copies img onto the atlas.
+//
+// If img is an *Image, the pixels are copied on GPU. Otherwise, the pixels are uploaded via a temporary image.
+//
+// Add returns false when the atlas doesn't have enough space for img. Defragment might make space.
+func (a *Atlas) Add(img image.Image) (*AtlasImage, bool) {
+	if a.image == nil {
+		panic("ebiten: the atlas is already disposed (Add)")
+	}
+	s := img.Bounds().Size()
+	if s.X <= 0 || s.Y <= 0 {
+		panic("ebiten: the image to add to an atlas must not be empty")
+	}
+	n := a.page.Alloc(s.X+atlasPadding, s.Y+atlasPadding)
+	if n == nil {
+		return nil, false
+	}
+	e := &AtlasImage{
+		atlas:  a,
+		node:   n,
+		width:  s.X,
+		height: s.Y,
+	}
+	a.entries[e] = struct{}{}
+
+	src, ok := img.(*Image)
+	if !ok {
+		// Replacing pixels for a part of the backing image is forbidden after rendering. Upload the pixels to a
+		// temporary image and copy them on GPU instead.
+		s, err := NewImageFromImage(img, FilterDefault)
+		if err != nil {
+			theUIContext.setError(err)
+			return e, true
+		}
+		defer s.Dispose()
+		src = s
+	}
+	a.draw(src, e.Bounds(), CompositeModeCopy)
+	return e, true
+}
+
+// draw draws src on the region of the backing image with the composite mode.
+func (a *Atlas) draw(src *Image, region image.Rectangle, mode CompositeMode) {
+	w, h := src.Size()
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(float64(region.Dx())/float64(w), float64(region.Dy())/float64(h))
+	op.GeoM.Translate(float64(region.Min.X), float64(region.Min.Y))
+	op.CompositeMode = mode
+	if err := a.image.DrawImage(src, op); err != nil {
+		theUIContext.setError(err)
+	}
+}
+
+// Remove removes img from the atlas, and the region becomes available for other images.
+//
+// If img is already removed, Remove does nothing.
+func (a *Atlas) Remove(img *AtlasImage) {
+	if img.atlas != a {
+		return
+	}
+	if _, ok := a.entries[img]; !ok {
+		return
+	}
+
+	// Clear the region so that the stale pixels don't remain in the padding of a later image.
+	// The source pixels don't matter with CompositeModeClear.
+	src, _ := NewImage(1, 1, FilterDefault)
+	a.draw(src, img.Bounds(), CompositeModeClear)
+	_ = src.Dispose()
+
+	a.page.Free(img.node)
+	delete(a.entries, img)
+	img.atlas = nil
+	img.node = nil
+	img.sub = nil
+}
+
+// Defragment repacks all the images on the atlas so that the free space is merged.
+//
+// Defragment replaces the backing image with a new one, and the images are copied on GPU. The images returned by
+// Image and AtlasImage.Image before Defragment must not be used after Defragment.
+//
+// Defragment returns false when the images cannot be repacked. In this case, the atlas is not changed.
+func (a *Atlas) Defragment() bool {
+	if a.image == nil {
+		panic("ebiten: the atlas is already disposed (Defragment)")
+	}
+
+	es := make([]*AtlasImage, 0, len(a.entries))
+	for e := range a.entries {
+		es = append(es, e)
+	}
+	// Packing larger images first makes the result denser.
+	sort.Slice(es, func(i, j int) bool {
+		ai, aj := es[i].width*es[i].height, es[j].width*es[j].height
+		if ai != aj {
+			return ai > aj
+		}
+		if es[i].height != es[j].height {
+			return es[i].height > es[j].height
+		}
+		return es[i].width > es[j].width
+	})
+
+	page := packing.NewPage(a.size, a.size)
+	nodes := make([]*packing.Node, len(es))
+	for i, e := range es {
+		n := page.Alloc(e.width+atlasPadding, e.height+atlasPadding)
+		if n == nil {
+			return false
+		}
+		nodes[i] = n
+	}
+
+	img, _ := NewImage(a.size, a.size, FilterDefault)
+	for i, e := range es {
+		src := e.Bounds()
+		x, y, _, _ := nodes[i].Region()
+		op := &DrawImageOptions{}
+		op.GeoM.Translate(float64(x), float64(y))
+		op.CompositeMode = CompositeModeCopy
+		if err := img.DrawImage(a.image.SubImage(src).(*Image), op); err != nil {
+			theUIContext.setError(err)
+		}
+		e.node = nodes[i]
+		e.sub = nil
+	}
+	_ = a.image.Dispose()
+	a.image = img
+	a.page = page
+	return true
+}
+
+// Dispose disposes the atlas and its backing image.
+//
+// After Dispose, the images on the atlas must not be used.
+func (a *Atlas) Dispose() {
+	if a.image == nil {
+		return
+	}
+	_ = a.image.Dispose()
+	a.image = nil
+	for e := range a.entries {
+		e.atlas = nil
+		e.node = nil
+		e.sub = nil
+	}
+	a.entries = nil
+}
+
+// Bounds returns the region of the image on the backing image.
+//
+// If the image is removed from the atlas, Bounds returns an empty rectangle.
+func (i *AtlasImage) Bounds() image.Rectangle {
+	if i.node == nil {
+		return image.Rectangle{}
+	}
+	x, y, _, _ := i.node.Region()
+	return image.Rect(x, y, x+i.width, y+i.height)
+}
+
+// Image returns the sub-image of the backing image for the image.
+//
+// The returned image can be used as a rendering source, but not as a rendering destination.
+// The returned image is valid until the atlas is defragmented or disposed, or the image is removed.
+//
+// If the image is removed from the atlas, Image returns nil.
+func (i *AtlasImage) Image() *Image {
+	if i.atlas == nil {
+		return nil
+	}
+	if i.sub == nil {
+		i.sub = i.atlas.image.SubImage(i.Bounds()).(*Image)
+	}
+	return i.sub
+}
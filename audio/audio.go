@@ -39,6 +39,8 @@ import (
 	"runtime"
 	"sync"
 	"time"
+
+	"github.com/hajimehoshi/ebiten/internal/hooks"
 )
 
 const (
@@ -111,6 +113,8 @@ func NewContext(sampleRate int) (*Context, error) {
 		<-c.semaphore
 	})
 
+	hooks.AppendResourceReporter(c.resources)
+
 	h.AppendHookOnBeforeUpdate(func() error {
 		c.initedOnce.Do(func() {
 			close(c.inited)
@@ -180,6 +184,34 @@ func (c *Context) removePlayer(p *playerImpl) {
 	c.m.Unlock()
 }
 
+// resources returns the live resources of the context for debugging.
+func (c *Context) resources() []hooks.Resource {
+	c.m.Lock()
+	players := make([]*playerImpl, 0, len(c.players))
+	for p := range c.players {
+		players = append(players, p)
+	}
+	c.m.Unlock()
+
+	rs := []hooks.Resource{
+		{
+			Kind: "audio context",
+			Name: fmt.Sprintf("%d Hz", c.sampleRate),
+		},
+	}
+	// Lock the players after unlocking the context, or this might be deadlocked with Play.
+	for _, p := range players {
+		p.m.Lock()
+		rs = append(rs, hooks.Resource{
+			Kind:  "audio player",
+			Name:  fmt.Sprintf("playing: %t, position: %d bytes", p.playing, p.pos),
+			Bytes: int64(len(p.buf)),
+		})
+		p.m.Unlock()
+	}
+	return rs
+}
+
 // IsReady returns a boolean value indicating whether the audio is ready or not.
 //
 // On some browsers, user interaction like click or pressing keys is required to start audio.
@@ -106,10 +106,15 @@ func newContext(sampleRate int) context {
 		})
 		return nil
 	})
-	return &otoContext{
+	c := &otoContext{
 		sampleRate: sampleRate,
 		initCh:     ch,
 	}
+	// Release the audio device when the game panics, or the device might be held by the dying process.
+	hooks.AppendHookOnPanic(func() {
+		_ = c.Close()
+	})
+	return c
 }
 
 type hook interface {
@@ -0,0 +1,242 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package capture provides camera frames as images.
+//
+// Only Linux (Video4Linux2) and browsers (getUserMedia) are supported. Windows (Media Foundation), macOS and iOS
+// (AVFoundation) and Android are out of the scope of this package, and Devices and Open return ErrNotSupported there.
+//
+// Here is an example:
+//
+//     c, err := capture.Open(nil)
+//     if err != nil {
+//         return err
+//     }
+//
+//     func update(screen *ebiten.Image) error {
+//         if err := c.Update(); err != nil {
+//             return err
+//         }
+//         if img := c.Image(); img != nil {
+//             screen.DrawImage(img, nil)
+//         }
+//         return nil
+//     }
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package capture
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/yuv"
+)
+
+// ErrNotSupported is returned by Devices and Open on the platforms where camera capture is not supported.
+var ErrNotSupported = errors.New("capture: camera capture is not supported on this platform")
+
+// Device represents a camera device.
+type Device struct {
+	// ID is the identifier of the device, that is specified at Options.DeviceID.
+	ID string
+
+	// Name is the human-readable name of the device. Name might be empty.
+	Name string
+}
+
+// Devices returns the camera devices available on the environment.
+//
+// On browsers, the devices are enumerated asynchronously. Devices returns the devices enumerated so far, and starts a
+// new enumeration for the later calls. The names are empty until the user allows the access to the cameras.
+func Devices() ([]Device, error) {
+	return devices()
+}
+
+// Options represents options for Open.
+type Options struct {
+	// DeviceID is the ID of the device to open.
+	// The default (zero) value means the default device.
+	DeviceID string
+
+	// Width and Height are the preferred size of the frames in pixels.
+	// The actual size depends on the device. The default (zero) values mean 640 x 480.
+	Width  int
+	Height int
+}
+
+// backend is a platform-specific camera stream.
+type backend interface {
+	// frame returns the latest frame, and reports whether the frame is new since the last call.
+	frame() (image.Image, bool, error)
+
+	close() error
+}
+
+// Camera is a camera stream.
+type Camera struct {
+	backend backend
+
+	image *ebiten.Image
+	rgba  *image.RGBA
+
+	// yuvShader reports whether Y'CbCr frames are converted into RGB on GPU.
+	yuvShader bool
+	ycbcr     *yuv.Image
+
+	closed bool
+}
+
+// Open opens a camera device and starts capturing.
+//
+// options can be nil. If options is nil, the default options are used.
+//
+// Open doesn't wait for the first frame. On browsers, the user is asked for the permission after Open, and the
+// error is reported by Update when the permission is denied.
+func Open(options *Options) (*Camera, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	w, h := options.Width, options.Height
+	if w <= 0 || h <= 0 {
+		w, h = 640, 480
+	}
+	b, err := openBackend(options.DeviceID, w, h)
+	if err != nil {
+		return nil, err
+	}
+	return &Camera{
+		backend: b,
+	}, nil
+}
+
+// Update updates the image with the latest frame if a new frame has arrived.
+//
+// Update returns an error when capturing fails.
+func (c *Camera) Update() error {
+	if c.closed {
+		return nil
+	}
+	f, ok, err := c.backend.frame()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	return c.render(f)
+}
+
+func (c *Camera) render(frame image.Image) error {
+	s := frame.Bounds().Size()
+	if c.image != nil {
+		if w, h := c.image.Size(); w != s.X || h != s.Y {
+			_ = c.image.Dispose()
+			c.image = nil
+			c.rgba = nil
+		}
+	}
+	if c.image == nil {
+		img, err := ebiten.NewImage(s.X, s.Y, ebiten.FilterDefault)
+		if err != nil {
+			return err
+		}
+		c.image = img
+	}
+
+	if y, ok := frame.(*image.YCbCr); ok && c.yuvShader {
+		return c.renderYCbCr(y)
+	}
+	if f, ok := frame.(*image.RGBA); ok && f.Rect.Min == (image.Point{}) && f.Stride == 4*s.X {
+		return c.image.ReplacePixels(f.Pix)
+	}
+	if c.rgba == nil {
+		c.rgba = image.NewRGBA(image.Rect(0, 0, s.X, s.Y))
+	}
+	draw.Draw(c.rgba, c.rgba.Bounds(), frame, frame.Bounds().Min, draw.Src)
+	return c.image.ReplacePixels(c.rgba.Pix)
+}
+
+func (c *Camera) renderYCbCr(img *image.YCbCr) error {
+	if c.ycbcr != nil {
+		w, h := c.ycbcr.Size()
+		if c.ycbcr.SubsampleRatio() != img.SubsampleRatio || w != img.Rect.Dx() || h != img.Rect.Dy() {
+			c.ycbcr.Dispose()
+			c.ycbcr = nil
+		}
+	}
+	if c.ycbcr == nil {
+		y, err := yuv.NewImage(img.Rect.Dx(), img.Rect.Dy(), img.SubsampleRatio)
+		if err != nil {
+			return err
+		}
+		c.ycbcr = y
+	}
+	if err := c.ycbcr.ReplacePixels(img); err != nil {
+		return err
+	}
+	c.ycbcr.Draw(c.image, &yuv.DrawOptions{
+		CompositeMode: ebiten.CompositeModeCopy,
+	})
+	return nil
+}
+
+// Image returns the image of the latest frame.
+//
+// Image returns nil until the first frame arrives. The image might be replaced when the frame size changes.
+func (c *Camera) Image() *ebiten.Image {
+	return c.image
+}
+
+// Size returns the size of the latest frame. Size returns (0, 0) until the first frame arrives.
+func (c *Camera) Size() (width, height int) {
+	if c.image == nil {
+		return 0, 0
+	}
+	return c.image.Size()
+}
+
+// IsYUVShaderEnabled reports whether the frames are converted into RGB by a shader.
+func (c *Camera) IsYUVShaderEnabled() bool {
+	return c.yuvShader
+}
+
+// SetYUVShaderEnabled sets whether Y'CbCr frames are converted into RGB by a shader on GPU.
+//
+// When enabled, the captured Y'CbCr planes are uploaded as they are and no color conversion is done on CPU.
+// See the yuv package for details. Custom shaders are available only with OpenGL for now.
+//
+// The initial value is false.
+func (c *Camera) SetYUVShaderEnabled(enabled bool) {
+	c.yuvShader = enabled
+}
+
+// Close stops capturing and releases the resources.
+func (c *Camera) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	if c.image != nil {
+		_ = c.image.Dispose()
+		c.image = nil
+	}
+	if c.ycbcr != nil {
+		c.ycbcr.Dispose()
+		c.ycbcr = nil
+	}
+	return c.backend.close()
+}
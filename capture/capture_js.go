@@ -0,0 +1,227 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"syscall/js"
+
+	"github.com/hajimehoshi/ebiten/internal/jsutil"
+)
+
+var (
+	devicesM         sync.Mutex
+	enumeratedDevs   []Device
+	enumeratingDevs  bool
+	enumerationError error
+)
+
+func mediaDevices() (js.Value, error) {
+	md := js.Global().Get("navigator").Get("mediaDevices")
+	if jsutil.Equal(md, js.Undefined()) || jsutil.Equal(md, js.Null()) {
+		return js.Value{}, errors.New("capture: navigator.mediaDevices is not available; note that a secure context (HTTPS) is required")
+	}
+	return md, nil
+}
+
+func devices() ([]Device, error) {
+	md, err := mediaDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	devicesM.Lock()
+	defer devicesM.Unlock()
+
+	if err := enumerationError; err != nil {
+		enumerationError = nil
+		return nil, err
+	}
+	ds := make([]Device, len(enumeratedDevs))
+	copy(ds, enumeratedDevs)
+
+	// enumerateDevices returns a promise. Don't wait for the promise here, as Devices might be called in the game
+	// loop, that is a callback of requestAnimationFrame.
+	if !enumeratingDevs {
+		enumeratingDevs = true
+		var then, catch js.Func
+		then = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			defer then.Release()
+			defer catch.Release()
+
+			var ds []Device
+			infos := args[0]
+			for i := 0; i < infos.Length(); i++ {
+				info := infos.Index(i)
+				if info.Get("kind").String() != "videoinput" {
+					continue
+				}
+				ds = append(ds, Device{
+					ID:   info.Get("deviceId").String(),
+					Name: info.Get("label").String(),
+				})
+			}
+
+			devicesM.Lock()
+			defer devicesM.Unlock()
+			enumeratedDevs = ds
+			enumeratingDevs = false
+			return nil
+		})
+		catch = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			defer then.Release()
+			defer catch.Release()
+
+			devicesM.Lock()
+			defer devicesM.Unlock()
+			enumerationError = fmt.Errorf("capture: enumerating devices failed: %s", args[0].Call("toString").String())
+			enumeratingDevs = false
+			return nil
+		})
+		md.Call("enumerateDevices").Call("then", then).Call("catch", catch)
+	}
+	return ds, nil
+}
+
+type jsBackend struct {
+	video   js.Value
+	canvas  js.Value
+	context js.Value
+	stream  js.Value
+
+	// streaming reports whether the stream is available.
+	streaming bool
+
+	lastTime float64
+
+	m   sync.Mutex
+	err error
+}
+
+func openBackend(deviceID string, width, height int) (backend, error) {
+	md, err := mediaDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := js.Global().Get("document")
+	b := &jsBackend{
+		video:    doc.Call("createElement", "video"),
+		canvas:   doc.Call("createElement", "canvas"),
+		lastTime: -1,
+	}
+	b.video.Set("muted", true)
+	b.video.Call("setAttribute", "playsinline", "")
+	b.context = b.canvas.Call("getContext", "2d")
+
+	video := map[string]interface{}{
+		"width":  width,
+		"height": height,
+	}
+	if deviceID != "" {
+		video["deviceId"] = map[string]interface{}{
+			"exact": deviceID,
+		}
+	}
+
+	// getUserMedia returns a promise that is resolved after the user allows the access. Don't wait for the promise.
+	var then, catch js.Func
+	then = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer then.Release()
+		defer catch.Release()
+
+		b.m.Lock()
+		defer b.m.Unlock()
+		b.stream = args[0]
+		b.streaming = true
+		b.video.Set("srcObject", b.stream)
+		b.video.Call("play")
+		return nil
+	})
+	catch = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer then.Release()
+		defer catch.Release()
+
+		b.m.Lock()
+		defer b.m.Unlock()
+		b.err = fmt.Errorf("capture: getUserMedia failed: %s", args[0].Call("toString").String())
+		return nil
+	})
+	md.Call("getUserMedia", map[string]interface{}{
+		"audio": false,
+		"video": video,
+	}).Call("then", then).Call("catch", catch)
+
+	return b, nil
+}
+
+func (b *jsBackend) frame() (image.Image, bool, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.err != nil {
+		return nil, false, b.err
+	}
+	if !b.streaming {
+		return nil, false, nil
+	}
+
+	// HAVE_CURRENT_DATA is 2.
+	if b.video.Get("readyState").Int() < 2 {
+		return nil, false, nil
+	}
+	t := b.video.Get("currentTime").Float()
+	if t == b.lastTime {
+		return nil, false, nil
+	}
+	b.lastTime = t
+
+	w := b.video.Get("videoWidth").Int()
+	h := b.video.Get("videoHeight").Int()
+	if w == 0 || h == 0 {
+		return nil, false, nil
+	}
+	if b.canvas.Get("width").Int() != w || b.canvas.Get("height").Int() != h {
+		b.canvas.Set("width", w)
+		b.canvas.Set("height", h)
+	}
+	b.context.Call("drawImage", b.video, 0, 0)
+	data := b.context.Call("getImageData", 0, 0, w, h).Get("data")
+
+	return &image.RGBA{
+		Pix:    jsutil.ArrayBufferToSlice(data.Get("buffer")),
+		Stride: 4 * w,
+		Rect:   image.Rect(0, 0, w, h),
+	}, true, nil
+}
+
+func (b *jsBackend) close() error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.streaming {
+		tracks := b.stream.Call("getTracks")
+		for i := 0; i < tracks.Length(); i++ {
+			tracks.Index(i).Call("stop")
+		}
+		b.stream = js.Value{}
+		b.streaming = false
+	}
+	b.video.Set("srcObject", js.Null())
+	return nil
+}
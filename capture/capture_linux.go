@@ -0,0 +1,428 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !android
+
+package capture
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// The definitions below are from linux/videodev2.h.
+
+const (
+	v4l2CapVideoCapture = 0x00000001
+	v4l2CapStreaming    = 0x04000000
+	v4l2CapDeviceCaps   = 0x80000000
+
+	v4l2BufTypeVideoCapture = 1
+	v4l2MemoryMMAP          = 1
+	v4l2FieldAny            = 0
+)
+
+func fourcc(a, b, c, d byte) uint32 {
+	return uint32(a) | uint32(b)<<8 | uint32(c)<<16 | uint32(d)<<24
+}
+
+var (
+	v4l2PixFmtYUYV  = fourcc('Y', 'U', 'Y', 'V')
+	v4l2PixFmtMJPEG = fourcc('M', 'J', 'P', 'G')
+)
+
+type v4l2Capability struct {
+	driver       [16]byte
+	card         [32]byte
+	busInfo      [32]byte
+	version      uint32
+	capabilities uint32
+	deviceCaps   uint32
+	reserved     [3]uint32
+}
+
+type v4l2PixFormat struct {
+	width        uint32
+	height       uint32
+	pixelformat  uint32
+	field        uint32
+	bytesperline uint32
+	sizeimage    uint32
+	colorspace   uint32
+	priv         uint32
+	flags        uint32
+	ycbcrEnc     uint32
+	quantization uint32
+	xferFunc     uint32
+}
+
+type v4l2Format struct {
+	typ uint32
+
+	// The union in v4l2_format includes pointers and is aligned as a pointer.
+	_   [0]uintptr
+	pix v4l2PixFormat
+	_   [200 - unsafe.Sizeof(v4l2PixFormat{})]byte
+}
+
+type v4l2RequestBuffers struct {
+	count        uint32
+	typ          uint32
+	memory       uint32
+	capabilities uint32
+	reserved     uint32
+}
+
+type v4l2Timecode struct {
+	typ      uint32
+	flags    uint32
+	frames   uint8
+	seconds  uint8
+	minutes  uint8
+	hours    uint8
+	userbits [4]uint8
+}
+
+type v4l2Buffer struct {
+	index     uint32
+	typ       uint32
+	bytesused uint32
+	flags     uint32
+	field     uint32
+	timestamp syscall.Timeval
+	timecode  v4l2Timecode
+	sequence  uint32
+	memory    uint32
+
+	// m is a union. For MMAP, the first 32 bits are the offset.
+	m         uintptr
+	length    uint32
+	reserved2 uint32
+	requestFD int32
+}
+
+func (b *v4l2Buffer) offset() uint32 {
+	return *(*uint32)(unsafe.Pointer(&b.m))
+}
+
+const (
+	iocWrite = 1
+	iocRead  = 2
+)
+
+func ioc(dir, nr, size uintptr) uintptr {
+	return dir<<30 | size<<16 | 'V'<<8 | nr
+}
+
+var (
+	vidiocQueryCap  = ioc(iocRead, 0, unsafe.Sizeof(v4l2Capability{}))
+	vidiocSFmt      = ioc(iocRead|iocWrite, 5, unsafe.Sizeof(v4l2Format{}))
+	vidiocReqBufs   = ioc(iocRead|iocWrite, 8, unsafe.Sizeof(v4l2RequestBuffers{}))
+	vidiocQueryBuf  = ioc(iocRead|iocWrite, 9, unsafe.Sizeof(v4l2Buffer{}))
+	vidiocQBuf      = ioc(iocRead|iocWrite, 15, unsafe.Sizeof(v4l2Buffer{}))
+	vidiocDQBuf     = ioc(iocRead|iocWrite, 17, unsafe.Sizeof(v4l2Buffer{}))
+	vidiocStreamOn  = ioc(iocWrite, 18, unsafe.Sizeof(int32(0)))
+	vidiocStreamOff = ioc(iocWrite, 19, unsafe.Sizeof(int32(0)))
+)
+
+func ioctl(fd int, req uintptr, arg unsafe.Pointer) error {
+	for {
+		_, _, e := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), req, uintptr(arg))
+		if e == syscall.EINTR {
+			continue
+		}
+		if e != 0 {
+			return e
+		}
+		return nil
+	}
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// isCaptureDevice reports whether the device at path can stream video frames.
+//
+// Recent kernels create extra device nodes for metadata, and they are excluded here.
+func isCaptureDevice(path string) (string, bool) {
+	fd, err := syscall.Open(path, syscall.O_RDWR|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return "", false
+	}
+	defer syscall.Close(fd)
+
+	var c v4l2Capability
+	if err := ioctl(fd, vidiocQueryCap, unsafe.Pointer(&c)); err != nil {
+		return "", false
+	}
+	caps := c.capabilities
+	if caps&v4l2CapDeviceCaps != 0 {
+		caps = c.deviceCaps
+	}
+	if caps&v4l2CapVideoCapture == 0 || caps&v4l2CapStreaming == 0 {
+		return "", false
+	}
+	return cString(c.card[:]), true
+}
+
+func devices() ([]Device, error) {
+	paths, err := filepath.Glob("/dev/video*")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var ds []Device
+	for _, p := range paths {
+		name, ok := isCaptureDevice(p)
+		if !ok {
+			continue
+		}
+		if b, err := ioutil.ReadFile(filepath.Join("/sys/class/video4linux", filepath.Base(p), "name")); err == nil {
+			name = strings.TrimSpace(string(b))
+		}
+		ds = append(ds, Device{
+			ID:   p,
+			Name: name,
+		})
+	}
+	return ds, nil
+}
+
+const bufferNum = 4
+
+type v4l2Backend struct {
+	fd      int
+	buffers [][]byte
+
+	format v4l2PixFormat
+
+	// queued reports whether the buffers are queued to the driver.
+	queued [bufferNum]bool
+}
+
+func openBackend(deviceID string, width, height int) (backend, error) {
+	if deviceID == "" {
+		ds, err := devices()
+		if err != nil {
+			return nil, err
+		}
+		if len(ds) == 0 {
+			return nil, fmt.Errorf("capture: no camera device is found")
+		}
+		deviceID = ds[0].ID
+	}
+
+	fd, err := syscall.Open(deviceID, syscall.O_RDWR|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, fmt.Errorf("capture: opening %s failed: %v", deviceID, err)
+	}
+	b := &v4l2Backend{
+		fd: fd,
+	}
+	if err := b.init(width, height); err != nil {
+		_ = b.close()
+		return nil, fmt.Errorf("capture: initializing %s failed: %v", deviceID, err)
+	}
+	return b, nil
+}
+
+func (b *v4l2Backend) init(width, height int) error {
+	f := v4l2Format{
+		typ: v4l2BufTypeVideoCapture,
+		pix: v4l2PixFormat{
+			width:       uint32(width),
+			height:      uint32(height),
+			pixelformat: v4l2PixFmtYUYV,
+			field:       v4l2FieldAny,
+		},
+	}
+	if err := ioctl(b.fd, vidiocSFmt, unsafe.Pointer(&f)); err != nil {
+		return err
+	}
+	// The driver might choose a different format and size.
+	if f.pix.pixelformat != v4l2PixFmtYUYV && f.pix.pixelformat != v4l2PixFmtMJPEG {
+		return fmt.Errorf("unsupported pixel format: %q", string([]byte{byte(f.pix.pixelformat), byte(f.pix.pixelformat >> 8), byte(f.pix.pixelformat >> 16), byte(f.pix.pixelformat >> 24)}))
+	}
+	b.format = f.pix
+
+	r := v4l2RequestBuffers{
+		count:  bufferNum,
+		typ:    v4l2BufTypeVideoCapture,
+		memory: v4l2MemoryMMAP,
+	}
+	if err := ioctl(b.fd, vidiocReqBufs, unsafe.Pointer(&r)); err != nil {
+		return err
+	}
+	if r.count == 0 || r.count > bufferNum {
+		return fmt.Errorf("unexpected number of buffers: %d", r.count)
+	}
+
+	for i := 0; i < int(r.count); i++ {
+		buf := v4l2Buffer{
+			index:  uint32(i),
+			typ:    v4l2BufTypeVideoCapture,
+			memory: v4l2MemoryMMAP,
+		}
+		if err := ioctl(b.fd, vidiocQueryBuf, unsafe.Pointer(&buf)); err != nil {
+			return err
+		}
+		m, err := syscall.Mmap(b.fd, int64(buf.offset()), int(buf.length), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+		if err != nil {
+			return err
+		}
+		b.buffers = append(b.buffers, m)
+		if err := b.queue(i); err != nil {
+			return err
+		}
+	}
+
+	t := int32(v4l2BufTypeVideoCapture)
+	if err := ioctl(b.fd, vidiocStreamOn, unsafe.Pointer(&t)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *v4l2Backend) queue(index int) error {
+	buf := v4l2Buffer{
+		index:  uint32(index),
+		typ:    v4l2BufTypeVideoCapture,
+		memory: v4l2MemoryMMAP,
+	}
+	if err := ioctl(b.fd, vidiocQBuf, unsafe.Pointer(&buf)); err != nil {
+		return err
+	}
+	b.queued[index] = true
+	return nil
+}
+
+// dequeue dequeues a filled buffer. dequeue returns false when no buffer is filled yet.
+func (b *v4l2Backend) dequeue() (v4l2Buffer, bool, error) {
+	buf := v4l2Buffer{
+		typ:    v4l2BufTypeVideoCapture,
+		memory: v4l2MemoryMMAP,
+	}
+	if err := ioctl(b.fd, vidiocDQBuf, unsafe.Pointer(&buf)); err != nil {
+		if err == syscall.EAGAIN {
+			return v4l2Buffer{}, false, nil
+		}
+		return v4l2Buffer{}, false, err
+	}
+	b.queued[buf.index] = false
+	return buf, true, nil
+}
+
+func (b *v4l2Backend) frame() (image.Image, bool, error) {
+	// Drain all the filled buffers and use only the latest one, or the frames would be delayed.
+	var latest v4l2Buffer
+	found := false
+	for {
+		buf, ok, err := b.dequeue()
+		if err != nil {
+			return nil, false, fmt.Errorf("capture: dequeuing a buffer failed: %v", err)
+		}
+		if !ok {
+			break
+		}
+		if found {
+			if err := b.queue(int(latest.index)); err != nil {
+				return nil, false, fmt.Errorf("capture: queuing a buffer failed: %v", err)
+			}
+		}
+		latest = buf
+		found = true
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	img, err := b.decode(b.buffers[latest.index][:latest.bytesused])
+	if err := b.queue(int(latest.index)); err != nil {
+		return nil, false, fmt.Errorf("capture: queuing a buffer failed: %v", err)
+	}
+	if err != nil {
+		// A broken frame can arrive e.g. just after the stream starts. Just skip it.
+		return nil, false, nil
+	}
+	return img, true, nil
+}
+
+func (b *v4l2Backend) decode(data []byte) (image.Image, error) {
+	switch b.format.pixelformat {
+	case v4l2PixFmtYUYV:
+		return yuyvToYCbCr(data, int(b.format.width), int(b.format.height), int(b.format.bytesperline))
+	case v4l2PixFmtMJPEG:
+		return jpeg.Decode(bytes.NewReader(data))
+	}
+	panic(fmt.Sprintf("capture: unexpected pixel format: %d", b.format.pixelformat))
+}
+
+// yuyvToYCbCr converts packed YUYV (YUY2) pixels into a 4:2:2 Y'CbCr image.
+func yuyvToYCbCr(data []byte, width, height, stride int) (*image.YCbCr, error) {
+	if stride == 0 {
+		stride = width * 2
+	}
+	if len(data) < stride*(height-1)+width*2 {
+		return nil, fmt.Errorf("capture: too short frame data: %d", len(data))
+	}
+
+	img := image.NewYCbCr(image.Rect(0, 0, width, height), image.YCbCrSubsampleRatio422)
+	for j := 0; j < height; j++ {
+		src := data[j*stride:]
+		y := img.Y[j*img.YStride:]
+		cb := img.Cb[j*img.CStride:]
+		cr := img.Cr[j*img.CStride:]
+		for i := 0; i < width/2; i++ {
+			y[2*i] = src[4*i]
+			cb[i] = src[4*i+1]
+			y[2*i+1] = src[4*i+2]
+			cr[i] = src[4*i+3]
+		}
+	}
+	return img, nil
+}
+
+func (b *v4l2Backend) close() error {
+	var err error
+	if len(b.buffers) > 0 {
+		t := int32(v4l2BufTypeVideoCapture)
+		err = ioctl(b.fd, vidiocStreamOff, unsafe.Pointer(&t))
+	}
+	for _, m := range b.buffers {
+		if e := syscall.Munmap(m); e != nil && err == nil {
+			err = e
+		}
+	}
+	b.buffers = nil
+	if e := syscall.Close(b.fd); e != nil && err == nil {
+		err = e
+	}
+	if err != nil {
+		return fmt.Errorf("capture: closing the device failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !android
+
+package capture
+
+import (
+	"image"
+	"testing"
+)
+
+func TestYUYVToYCbCr(t *testing.T) {
+	// 4x2 pixels with 2 bytes of padding at each row.
+	data := []byte{
+		0, 1, 2, 3, 4, 5, 6, 7, 0xff, 0xff,
+		8, 9, 10, 11, 12, 13, 14, 15, 0xff, 0xff,
+	}
+	img, err := yuyvToYCbCr(data, 4, 2, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := img.SubsampleRatio, image.YCbCrSubsampleRatio422; got != want {
+		t.Errorf("SubsampleRatio: got: %v, want: %v", got, want)
+	}
+	for _, c := range []struct {
+		X, Y       int
+		Y0, Cb, Cr uint8
+	}{
+		{0, 0, 0, 1, 3},
+		{1, 0, 2, 1, 3},
+		{2, 0, 4, 5, 7},
+		{3, 0, 6, 5, 7},
+		{0, 1, 8, 9, 11},
+		{3, 1, 14, 13, 15},
+	} {
+		got := img.YCbCrAt(c.X, c.Y)
+		if got.Y != c.Y0 || got.Cb != c.Cb || got.Cr != c.Cr {
+			t.Errorf("YCbCrAt(%d, %d): got: %v, want: {%d %d %d}", c.X, c.Y, got, c.Y0, c.Cb, c.Cr)
+		}
+	}
+
+	if _, err := yuyvToYCbCr(data[:15], 4, 2, 10); err == nil {
+		t.Errorf("yuyvToYCbCr with short data must return an error")
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// ebitenbench renders standardized scenes and reports the performance.
+//
+// Usage:
+//
+//     ebitenbench [-scene all|sprites|text|overdraw|particles] [-duration 5s] [-vsync]
+//
+// For each scene, ebitenbench reports the average FPS, the frame times and the number of draw calls per frame.
+// This is useful to detect performance regressions and to compare the graphics backends.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/bitmapfont"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/text"
+)
+
+const (
+	screenWidth  = 640
+	screenHeight = 480
+)
+
+var (
+	flagScene    = flag.String("scene", "all", "scene to run: all, "+strings.Join(sceneNames(), ", "))
+	flagDuration = flag.Duration("duration", 5*time.Second, "duration of each scene")
+	flagVsync    = flag.Bool("vsync", false, "enable vsync")
+)
+
+type scene interface {
+	Update()
+	Draw(screen *ebiten.Image)
+}
+
+type sceneEntry struct {
+	name string
+	new  func() scene
+}
+
+var scenes = []sceneEntry{
+	{"sprites", newSpritesScene},
+	{"text", newTextScene},
+	{"overdraw", newOverdrawScene},
+	{"particles", newParticlesScene},
+}
+
+func sceneNames() []string {
+	var names []string
+	for _, s := range scenes {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+func newBallImage(size int) *ebiten.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	r := float64(size) / 2
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			x := float64(i) + 0.5 - r
+			y := float64(j) + 0.5 - r
+			if x*x+y*y <= r*r {
+				img.Set(i, j, color.White)
+			}
+		}
+	}
+	eimg, _ := ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+	return eimg
+}
+
+type sprite struct {
+	x, y   float64
+	vx, vy float64
+	clr    [3]float64
+}
+
+func (s *sprite) move(size float64) {
+	s.x += s.vx
+	s.y += s.vy
+	if s.x < 0 || s.x > screenWidth-size {
+		s.vx = -s.vx
+	}
+	if s.y < 0 || s.y > screenHeight-size {
+		s.vy = -s.vy
+	}
+}
+
+func newSprite(size float64) sprite {
+	return sprite{
+		x:   rand.Float64() * (screenWidth - size),
+		y:   rand.Float64() * (screenHeight - size),
+		vx:  rand.Float64()*4 - 2,
+		vy:  rand.Float64()*4 - 2,
+		clr: [3]float64{rand.Float64(), rand.Float64(), rand.Float64()},
+	}
+}
+
+// spritesScene draws 10,000 small sprites from the same image.
+type spritesScene struct {
+	img     *ebiten.Image
+	sprites []sprite
+	op      ebiten.DrawImageOptions
+}
+
+func newSpritesScene() scene {
+	s := &spritesScene{
+		img: newBallImage(16),
+	}
+	for i := 0; i < 10000; i++ {
+		s.sprites = append(s.sprites, newSprite(16))
+	}
+	return s
+}
+
+func (s *spritesScene) Update() {
+	for i := range s.sprites {
+		s.sprites[i].move(16)
+	}
+}
+
+func (s *spritesScene) Draw(screen *ebiten.Image) {
+	for _, sp := range s.sprites {
+		s.op.GeoM.Reset()
+		s.op.GeoM.Translate(sp.x, sp.y)
+		s.op.ColorM.Reset()
+		s.op.ColorM.Scale(sp.clr[0], sp.clr[1], sp.clr[2], 1)
+		_ = screen.DrawImage(s.img, &s.op)
+	}
+}
+
+// textScene draws walls of text that change every frame.
+type textScene struct {
+	count int
+}
+
+func newTextScene() scene {
+	return &textScene{}
+}
+
+func (s *textScene) Update() {
+	s.count++
+}
+
+func (s *textScene) Draw(screen *ebiten.Image) {
+	const lineHeight = 12
+	for j := 0; j < screenHeight/lineHeight; j++ {
+		var b strings.Builder
+		for i := 0; i < 60; i++ {
+			b.WriteByte(byte('!' + (i+j+s.count/10)%94))
+		}
+		clr := color.RGBA{0x80 + byte(j*4), 0xff, 0xff - byte(j*4), 0xff}
+		text.Draw(screen, b.String(), bitmapfont.Gothic12r, 4, lineHeight*(j+1), clr)
+	}
+}
+
+// overdrawScene draws many translucent full-screen layers.
+type overdrawScene struct {
+	img   *ebiten.Image
+	count int
+}
+
+func newOverdrawScene() scene {
+	img, _ := ebiten.NewImage(screenWidth, screenHeight, ebiten.FilterDefault)
+	_ = img.Fill(color.RGBA{0x10, 0x08, 0x04, 0x10})
+	return &overdrawScene{
+		img: img,
+	}
+}
+
+func (s *overdrawScene) Update() {
+	s.count++
+}
+
+func (s *overdrawScene) Draw(screen *ebiten.Image) {
+	for i := 0; i < 50; i++ {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(math.Sin(float64(s.count+i)/10)*8, math.Cos(float64(s.count+i)/10)*8)
+		_ = screen.DrawImage(s.img, op)
+	}
+}
+
+// particlesScene draws many additive particles that are spawned and die continuously.
+type particlesScene struct {
+	img       *ebiten.Image
+	particles []particle
+	op        ebiten.DrawImageOptions
+}
+
+type particle struct {
+	sprite
+	life int
+}
+
+func newParticlesScene() scene {
+	s := &particlesScene{
+		img: newBallImage(4),
+	}
+	s.op.CompositeMode = ebiten.CompositeModeLighter
+	return s
+}
+
+func (s *particlesScene) Update() {
+	const max = 20000
+	for i := 0; i < 500 && len(s.particles) < max; i++ {
+		p := particle{
+			sprite: newSprite(4),
+			life:   40 + rand.Intn(40),
+		}
+		p.x, p.y = screenWidth/2, screenHeight/2
+		s.particles = append(s.particles, p)
+	}
+	n := 0
+	for _, p := range s.particles {
+		p.life--
+		if p.life <= 0 {
+			continue
+		}
+		p.move(4)
+		s.particles[n] = p
+		n++
+	}
+	s.particles = s.particles[:n]
+}
+
+func (s *particlesScene) Draw(screen *ebiten.Image) {
+	for _, p := range s.particles {
+		s.op.GeoM.Reset()
+		s.op.GeoM.Translate(p.x, p.y)
+		s.op.ColorM.Reset()
+		s.op.ColorM.Scale(p.clr[0], p.clr[1], p.clr[2], float64(p.life)/80)
+		_ = screen.DrawImage(s.img, &s.op)
+	}
+}
+
+type result struct {
+	name       string
+	frames     int
+	elapsed    time.Duration
+	frameTimes []time.Duration
+	drawCalls  int64
+}
+
+func (r *result) percentile(p float64) time.Duration {
+	if len(r.frameTimes) == 0 {
+		return 0
+	}
+	ts := append([]time.Duration{}, r.frameTimes...)
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i] < ts[j]
+	})
+	return ts[int(float64(len(ts)-1)*p)]
+}
+
+func (r *result) String() string {
+	fps := float64(r.frames) / r.elapsed.Seconds()
+	calls := float64(r.drawCalls) / float64(r.frames)
+	return fmt.Sprintf("%-10s %8.2f %10.2f %10.2f %10.2f %10.1f", r.name, fps,
+		ms(r.percentile(0.5)), ms(r.percentile(0.99)), ms(r.percentile(1)), calls)
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+type game struct {
+	entries []sceneEntry
+	current scene
+	index   int
+
+	result    *result
+	results   []*result
+	start     time.Time
+	last      time.Time
+	drawCalls int64
+}
+
+func (g *game) next() bool {
+	if g.result != nil {
+		g.result.elapsed = time.Since(g.start)
+		g.result.drawCalls = graphicscommand.DrawCallCount() - g.drawCalls
+		g.results = append(g.results, g.result)
+	}
+	if g.index >= len(g.entries) {
+		return false
+	}
+	e := g.entries[g.index]
+	g.index++
+	g.current = e.new()
+	g.result = &result{
+		name: e.name,
+	}
+	return true
+}
+
+func (g *game) Update(screen *ebiten.Image) error {
+	now := time.Now()
+	if g.current == nil || now.Sub(g.start) >= *flagDuration {
+		if !g.next() {
+			return ebiten.Termination
+		}
+		// Skip the first frame of the scene to exclude the initialization.
+		g.start = time.Now()
+		g.last = g.start
+		g.drawCalls = graphicscommand.DrawCallCount()
+	} else {
+		g.result.frames++
+		g.result.frameTimes = append(g.result.frameTimes, now.Sub(g.last))
+		g.last = now
+	}
+
+	g.current.Update()
+	return nil
+}
+
+func (g *game) Draw(screen *ebiten.Image) {
+	g.current.Draw(screen)
+}
+
+func (g *game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
+
+func main() {
+	flag.Parse()
+
+	var entries []sceneEntry
+	for _, s := range scenes {
+		if *flagScene == "all" || *flagScene == s.name {
+			entries = append(entries, s)
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "unknown scene: %s\n", *flagScene)
+		os.Exit(2)
+	}
+
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+	ebiten.SetWindowTitle("ebitenbench")
+	ebiten.SetVsyncEnabled(*flagVsync)
+	ebiten.SetMaxTPS(ebiten.UncappedTPS)
+	ebiten.SetRunnableInBackground(true)
+
+	g := &game{
+		entries: entries,
+	}
+	if err := ebiten.RunGame(g); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%-10s %8s %10s %10s %10s %10s\n", "scene", "fps", "p50 (ms)", "p99 (ms)", "max (ms)", "draws")
+	for _, r := range g.results {
+		fmt.Println(r)
+	}
+}
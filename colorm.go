@@ -113,6 +113,29 @@ func (c *ColorM) SetElement(i, j int, element float64) {
 	c.impl = c.impl.SetElement(i, j, float32(element))
 }
 
+// InvertColor returns a color matrix to invert the RGB values of the colors. The alpha values are not changed.
+func InvertColor() ColorM {
+	c := ColorM{}
+	c.Scale(-1, -1, -1, 1)
+	c.Translate(1, 1, 1, 0)
+	return c
+}
+
+// SepiaColor returns a color matrix to convert the colors into sepia tones. The alpha values are not changed.
+func SepiaColor() ColorM {
+	c := ColorM{}
+	for i, row := range [3][3]float64{
+		{0.393, 0.769, 0.189},
+		{0.349, 0.686, 0.168},
+		{0.272, 0.534, 0.131},
+	} {
+		for j, e := range row {
+			c.SetElement(i, j, e)
+		}
+	}
+	return c
+}
+
 // Monochrome is deprecated as of 1.6.0-alpha. Use ChangeHSV(0, 0, 1) instead.
 func Monochrome() ColorM {
 	c := ColorM{}
@@ -234,3 +234,50 @@ func TestColorMApply(t *testing.T) {
 		}
 	}
 }
+
+func TestColorMPresets(t *testing.T) {
+	gray := Monochrome()
+	invert := InvertColor()
+	sepia := SepiaColor()
+
+	cases := []struct {
+		ColorM ColorM
+		In     color.Color
+		Out    color.Color
+		Delta  uint32
+	}{
+		{
+			ColorM: gray,
+			In:     color.RGBA{0xff, 0, 0, 0xff},
+			Out:    color.RGBA{0x4c, 0x4c, 0x4c, 0xff},
+			Delta:  0x101,
+		},
+		{
+			ColorM: invert,
+			In:     color.RGBA{0xff, 0x80, 0, 0xff},
+			Out:    color.RGBA{0, 0x7f, 0xff, 0xff},
+			Delta:  0x101,
+		},
+		{
+			ColorM: invert,
+			In:     color.RGBA{0x40, 0x20, 0, 0x80},
+			Out:    color.RGBA{0x40, 0x60, 0x80, 0x80},
+			Delta:  0x101,
+		},
+		{
+			ColorM: sepia,
+			In:     color.RGBA{0x80, 0x80, 0x80, 0xff},
+			Out:    color.RGBA{0xad, 0x9a, 0x78, 0xff},
+			Delta:  0x101,
+		},
+	}
+	for _, c := range cases {
+		out := c.ColorM.Apply(c.In)
+		r0, g0, b0, a0 := out.RGBA()
+		r1, g1, b1, a1 := c.Out.RGBA()
+		if absDiffU32(r0, r1) > c.Delta || absDiffU32(g0, g1) > c.Delta ||
+			absDiffU32(b0, b1) > c.Delta || absDiffU32(a0, a1) > c.Delta {
+			t.Errorf("%v.Apply(%v) = {%d, %d, %d, %d}, want {%d, %d, %d, %d}", c.ColorM, c.In, r0, g0, b0, a0, r1, g1, b1, a1)
+		}
+	}
+}
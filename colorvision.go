@@ -0,0 +1,148 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"sync/atomic"
+)
+
+// A ColorVisionFilterType represents a filter applied to the final screen for color vision deficiencies.
+type ColorVisionFilterType int
+
+// Color Vision Filters
+const (
+	// ColorVisionFilterNone represents no filter.
+	ColorVisionFilterNone ColorVisionFilterType = iota
+
+	// ColorVisionFilterProtanopia simulates protanopia (lack of red cones).
+	// This is for testing your game by developers.
+	ColorVisionFilterProtanopia
+
+	// ColorVisionFilterDeuteranopia simulates deuteranopia (lack of green cones).
+	// This is for testing your game by developers.
+	ColorVisionFilterDeuteranopia
+
+	// ColorVisionFilterTritanopia simulates tritanopia (lack of blue cones).
+	// This is for testing your game by developers.
+	ColorVisionFilterTritanopia
+
+	// ColorVisionFilterProtanopiaCorrection corrects colors for players with protanopia by daltonization.
+	ColorVisionFilterProtanopiaCorrection
+
+	// ColorVisionFilterDeuteranopiaCorrection corrects colors for players with deuteranopia by daltonization.
+	ColorVisionFilterDeuteranopiaCorrection
+
+	// ColorVisionFilterTritanopiaCorrection corrects colors for players with tritanopia by daltonization.
+	ColorVisionFilterTritanopiaCorrection
+)
+
+// The simulation matrices are from Machado, Oliveira and Fernandes, "A Physiologically-based Model for
+// Simulation of Color Vision Deficiency" (2009) with the severity 1.0.
+var (
+	protanopiaMatrix = [3][3]float64{
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	}
+	deuteranopiaMatrix = [3][3]float64{
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	}
+	tritanopiaMatrix = [3][3]float64{
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	}
+
+	// daltonizationErrorMatrix shifts the lost information to the colors that can be distinguished.
+	daltonizationErrorMatrix = [3][3]float64{
+		{0, 0, 0},
+		{0.7, 1, 0},
+		{0.7, 0, 1},
+	}
+)
+
+// daltonize returns the matrix I + E(I - S), where S is the simulation matrix and E is the error matrix.
+func daltonize(s [3][3]float64) [3][3]float64 {
+	var d [3][3]float64
+	for i := 0; i < 3; i++ {
+		d[i][i] = 1
+	}
+	var diff [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			diff[i][j] = d[i][j] - s[i][j]
+		}
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				d[i][j] += daltonizationErrorMatrix[i][k] * diff[k][j]
+			}
+		}
+	}
+	return d
+}
+
+func colorMFromMatrix(m [3][3]float64) ColorM {
+	var c ColorM
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			c.SetElement(i, j, m[i][j])
+		}
+	}
+	return c
+}
+
+func (f ColorVisionFilterType) colorM() ColorM {
+	switch f {
+	case ColorVisionFilterProtanopia:
+		return colorMFromMatrix(protanopiaMatrix)
+	case ColorVisionFilterDeuteranopia:
+		return colorMFromMatrix(deuteranopiaMatrix)
+	case ColorVisionFilterTritanopia:
+		return colorMFromMatrix(tritanopiaMatrix)
+	case ColorVisionFilterProtanopiaCorrection:
+		return colorMFromMatrix(daltonize(protanopiaMatrix))
+	case ColorVisionFilterDeuteranopiaCorrection:
+		return colorMFromMatrix(daltonize(deuteranopiaMatrix))
+	case ColorVisionFilterTritanopiaCorrection:
+		return colorMFromMatrix(daltonize(tritanopiaMatrix))
+	}
+	return ColorM{}
+}
+
+var currentColorVisionFilter int32 = int32(ColorVisionFilterNone)
+
+// ColorVisionFilter returns the current color vision filter.
+//
+// ColorVisionFilter is concurrent-safe.
+func ColorVisionFilter() ColorVisionFilterType {
+	return ColorVisionFilterType(atomic.LoadInt32(&currentColorVisionFilter))
+}
+
+// SetColorVisionFilter sets the filter applied to the final screen for color vision deficiencies.
+//
+// The simulation filters like ColorVisionFilterProtanopia show how the game screen looks for players with
+// color vision deficiencies. The correction filters like ColorVisionFilterProtanopiaCorrection adjust the
+// colors so that the players can distinguish them more easily. This is useful as an option for players.
+//
+// The filter is applied to the whole screen at the end of each frame. The initial value is ColorVisionFilterNone.
+//
+// SetColorVisionFilter is concurrent-safe.
+func SetColorVisionFilter(filter ColorVisionFilterType) {
+	atomic.StoreInt32(&currentColorVisionFilter, int32(filter))
+}
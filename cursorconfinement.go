@@ -0,0 +1,77 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+	"math"
+	"sync"
+)
+
+var (
+	cursorConfinement  image.Rectangle
+	cursorConfinementM sync.Mutex
+)
+
+// CursorConfinement returns the current region where the mouse cursor is confined.
+//
+// CursorConfinement returns an empty rectangle if the cursor is not confined.
+//
+// CursorConfinement is concurrent-safe.
+func CursorConfinement() image.Rectangle {
+	cursorConfinementM.Lock()
+	defer cursorConfinementM.Unlock()
+	return cursorConfinement
+}
+
+// SetCursorConfinement confines the mouse cursor to the given region.
+//
+// region is in the screen coordinates, the same as CursorPosition. To confine the cursor to the whole window, specify
+// the whole screen, e.g., image.Rect(0, 0, screenWidth, screenHeight). If region is empty, the cursor is released.
+//
+// Unlike CursorModeCaptured, the cursor is still visible and moves as usual inside the region.
+// This is useful for edge scrolling of strategy games.
+// The cursor is confined only while the window is focused.
+//
+// The cursor is confined by the OS: ClipCursor on Windows, a pointer grab on X11 and an event monitor on macOS.
+// On other environments like Wayland, the cursor is moved back into the region every frame instead.
+//
+// SetCursorConfinement does nothing on browsers and mobiles.
+//
+// SetCursorConfinement is concurrent-safe.
+func SetCursorConfinement(region image.Rectangle) {
+	cursorConfinementM.Lock()
+	defer cursorConfinementM.Unlock()
+	cursorConfinement = region.Canon()
+}
+
+// updateCursorConfinement converts the cursor confinement region to the window's coordinates and notifies it to the
+// UI driver.
+//
+// The conversion is needed every frame since the layout of the screen can be changed.
+func (c *uiContext) updateCursorConfinement() {
+	r := CursorConfinement()
+	if r.Empty() || c.offscreen == nil {
+		uiDriver().SetCursorConfinement(image.Rectangle{})
+		return
+	}
+	// framebufferPosition returns the position in device-dependent pixels.
+	d := uiDriver().DeviceScaleFactor()
+	x0, y0 := c.framebufferPosition(float64(r.Min.X), float64(r.Min.Y))
+	x1, y1 := c.framebufferPosition(float64(r.Max.X), float64(r.Max.Y))
+	uiDriver().SetCursorConfinement(image.Rect(
+		int(math.Ceil(x0/d)), int(math.Ceil(y0/d)),
+		int(math.Floor(x1/d)), int(math.Floor(y1/d))))
+}
@@ -0,0 +1,294 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image/color"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+)
+
+var (
+	wireframeVisualizationEnabled int32
+	batchVisualizationEnabled     int32
+)
+
+// IsWireframeVisualizationEnabled reports whether the wireframe visualization is enabled.
+//
+// IsWireframeVisualizationEnabled is concurrent-safe.
+func IsWireframeVisualizationEnabled() bool {
+	return atomic.LoadInt32(&wireframeVisualizationEnabled) != 0
+}
+
+// SetWireframeVisualizationEnabled sets whether the wireframe visualization is enabled.
+//
+// When the wireframe visualization is enabled, the outlines of all the triangles drawn on the screen image in the
+// frame, e.g., two triangles for each DrawImage, are drawn over the screen.
+//
+// This is a debug feature. The initial value is false.
+//
+// SetWireframeVisualizationEnabled is concurrent-safe.
+func SetWireframeVisualizationEnabled(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&wireframeVisualizationEnabled, v)
+}
+
+// IsBatchVisualizationEnabled reports whether the batch visualization is enabled.
+//
+// IsBatchVisualizationEnabled is concurrent-safe.
+func IsBatchVisualizationEnabled() bool {
+	return atomic.LoadInt32(&batchVisualizationEnabled) != 0
+}
+
+// SetBatchVisualizationEnabled sets whether the batch visualization is enabled.
+//
+// When the batch visualization is enabled, the triangles drawn on the screen image in the frame are tinted over
+// the screen with a color for each batch. Successive draw calls merged into one batch have the same color, and a
+// change of the color shows where batching breaks, e.g., by a different source texture, a different composite mode,
+// a ColorM that is not batchable, or a draw call to another image in between. See the document of
+// (*Image).DrawImage for the batching conditions.
+//
+// This is a debug feature. The initial value is false.
+//
+// SetBatchVisualizationEnabled is concurrent-safe.
+func SetBatchVisualizationEnabled(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&batchVisualizationEnabled, v)
+}
+
+func isDrawRecordingEnabled() bool {
+	return atomic.LoadInt32(&overdrawVisualizationEnabled) != 0 ||
+		atomic.LoadInt32(&wireframeVisualizationEnabled) != 0 ||
+		atomic.LoadInt32(&batchVisualizationEnabled) != 0
+}
+
+// recordFill records a write to the entire image of dst for the debug visualizations.
+func recordFill(dst *Image) {
+	if !isDrawRecordingEnabled() {
+		return
+	}
+	w, h := dst.Size()
+	recordQuad(dst, float32(w), float32(h), 1, 0, 0, 1, 0, 0)
+}
+
+// recordQuad records a write to the rectangle (0, 0)-(width, height) of dst transformed by the given matrix for the
+// debug visualizations.
+func recordQuad(dst *Image, width, height float32, a, b, c, d, tx, ty float32) {
+	if !isDrawRecordingEnabled() {
+		return
+	}
+
+	vs := make([]float32, 4*graphics.VertexFloatNum)
+	for idx, p := range [][2]float32{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		x, y := p[0]*width, p[1]*height
+		vs[idx*graphics.VertexFloatNum] = a*x + b*y + tx
+		vs[idx*graphics.VertexFloatNum+1] = c*x + d*y + ty
+	}
+	recordTriangles(dst, vs, graphics.QuadIndices())
+}
+
+// recordTriangles records writes to dst by the given triangles for the debug visualizations.
+//
+// Only the destination positions of vertices are used.
+func recordTriangles(dst *Image, vertices []float32, indices []uint16) {
+	if !isDrawRecordingEnabled() {
+		return
+	}
+	theOverdraw.record(dst, vertices, indices)
+	theDrawDebugger.record(dst, vertices, indices)
+}
+
+// batchColors is the palette to distinguish batches. Adjacent batches have different colors.
+var batchColors = []color.RGBA{
+	{0xff, 0x40, 0x40, 0xff},
+	{0x40, 0xff, 0x40, 0xff},
+	{0x40, 0x80, 0xff, 0xff},
+	{0xff, 0xff, 0x40, 0xff},
+	{0xff, 0x40, 0xff, 0xff},
+	{0x40, 0xff, 0xff, 0xff},
+	{0xff, 0x80, 0x20, 0xff},
+	{0x80, 0x40, 0xff, 0xff},
+}
+
+type debugTriangle struct {
+	x, y  [3]float32
+	batch int64
+}
+
+type drawDebugger struct {
+	target    *Image
+	triangles []debugTriangle
+	composite *Image
+	source    *Image
+	m         sync.Mutex
+}
+
+var theDrawDebugger = &drawDebugger{}
+
+// prepare starts recording the triangles drawn on target, which is the screen image just cleared.
+func (d *drawDebugger) prepare(target *Image) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	d.triangles = d.triangles[:0]
+	if !IsWireframeVisualizationEnabled() && !IsBatchVisualizationEnabled() {
+		d.target = nil
+		return
+	}
+	d.target = target
+}
+
+func (d *drawDebugger) record(dst *Image, vertices []float32, indices []uint16) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if d.target == nil || d.target != dst {
+		return
+	}
+
+	// The triangles are drawn at the end of the frame. Drawing them here would break the batches to visualize.
+	batch := graphicscommand.CommandCount()
+	for i := 0; i+2 < len(indices); i += 3 {
+		var t debugTriangle
+		for j := 0; j < 3; j++ {
+			idx := int(indices[i+j])
+			t.x[j] = vertices[idx*graphics.VertexFloatNum]
+			t.y[j] = vertices[idx*graphics.VertexFloatNum+1]
+		}
+		t.batch = batch
+		d.triangles = append(d.triangles, t)
+	}
+}
+
+// compose returns the image of src with the recorded triangles visualized. compose returns src as it is when the
+// visualizations are disabled.
+func (d *drawDebugger) compose(src *Image) *Image {
+	d.m.Lock()
+	target := d.target
+	ts := make([]debugTriangle, len(d.triangles))
+	copy(ts, d.triangles)
+	d.m.Unlock()
+
+	if target == nil {
+		if d.composite != nil {
+			_ = d.composite.Dispose()
+			d.composite = nil
+		}
+		return src
+	}
+
+	w, h := src.Size()
+	if d.composite != nil {
+		if cw, ch := d.composite.Size(); cw != w || ch != h {
+			_ = d.composite.Dispose()
+			d.composite = nil
+		}
+	}
+	if d.composite == nil {
+		d.composite = newImage(w, h, FilterDefault, true)
+	}
+	if d.source == nil {
+		d.source, _ = NewImage(16, 16, FilterDefault)
+		_ = d.source.Fill(color.White)
+	}
+
+	op := &DrawImageOptions{}
+	op.CompositeMode = CompositeModeCopy
+	_ = d.composite.DrawImage(src, op)
+
+	wireframe := IsWireframeVisualizationEnabled()
+	batches := IsBatchVisualizationEnabled()
+
+	var vs []Vertex
+	var is []uint16
+	flush := func() {
+		if len(is) == 0 {
+			return
+		}
+		d.composite.DrawTriangles(vs, is, d.source, nil)
+		vs = vs[:0]
+		is = is[:0]
+	}
+	appendVertices := func(xs, ys []float32, clr color.RGBA, alpha float32, indices []uint16) {
+		// Keep 16-bit indices and MaxIndicesNum.
+		if len(vs)+len(xs) > math.MaxUint16 || len(is)+len(indices) > MaxIndicesNum {
+			flush()
+		}
+		base := uint16(len(vs))
+		for i := range xs {
+			vs = append(vs, Vertex{
+				DstX: xs[i],
+				DstY: ys[i],
+				SrcX: 8,
+				SrcY: 8,
+				// Colors are premultiplied by alpha.
+				ColorR: float32(clr.R) / 0xff * alpha,
+				ColorG: float32(clr.G) / 0xff * alpha,
+				ColorB: float32(clr.B) / 0xff * alpha,
+				ColorA: alpha,
+			})
+		}
+		for _, i := range indices {
+			is = append(is, base+i)
+		}
+	}
+
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	batchIndex := -1
+	var lastBatch int64
+	for _, t := range ts {
+		if batchIndex < 0 || t.batch != lastBatch {
+			batchIndex++
+			lastBatch = t.batch
+		}
+		clr := batchColors[batchIndex%len(batchColors)]
+		if batches {
+			appendVertices(t.x[:], t.y[:], clr, 0.25, []uint16{0, 1, 2})
+		}
+		if wireframe {
+			lineClr := white
+			if batches {
+				lineClr = clr
+			}
+			for j := 0; j < 3; j++ {
+				x0, y0 := t.x[j], t.y[j]
+				x1, y1 := t.x[(j+1)%3], t.y[(j+1)%3]
+				dx, dy := x1-x0, y1-y0
+				l := float32(math.Hypot(float64(dx), float64(dy)))
+				if l == 0 {
+					continue
+				}
+				// The line width is 1 pixel.
+				nx, ny := -dy/l/2, dx/l/2
+				xs := []float32{x0 + nx, x1 + nx, x0 - nx, x1 - nx}
+				ys := []float32{y0 + ny, y1 + ny, y0 - ny, y1 - ny}
+				appendVertices(xs, ys, lineClr, 1, []uint16{0, 1, 2, 1, 2, 3})
+			}
+		}
+	}
+	flush()
+
+	return d.composite
+}
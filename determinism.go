@@ -0,0 +1,392 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DeterminismAuditMode represents a mode of the determinism audit.
+type DeterminismAuditMode int
+
+const (
+	// DeterminismAuditModeRecord represents a mode to record the hashes of the game state into a log.
+	DeterminismAuditModeRecord DeterminismAuditMode = iota
+
+	// DeterminismAuditModeVerify represents a mode to compare the hashes of the game state with a recorded log.
+	DeterminismAuditModeVerify
+)
+
+// determinismLogMagic is the header of a serialized DeterminismLog.
+const determinismLogMagic = "EBDL\x01"
+
+type determinismEntry struct {
+	tick   int64
+	names  []string
+	hashes []uint64
+}
+
+// DeterminismLog is a log of the hashes of the game state per tick.
+//
+// DeterminismLog is concurrent-safe.
+//
+// Note that this API is experimental.
+type DeterminismLog struct {
+	// entries are sorted by the ticks.
+	entries []determinismEntry
+
+	m sync.Mutex
+}
+
+// NewDeterminismLog returns an empty DeterminismLog.
+//
+// Note that this API is experimental.
+func NewDeterminismLog() *DeterminismLog {
+	return &DeterminismLog{}
+}
+
+// ReadDeterminismLog reads a DeterminismLog written by (*DeterminismLog).Write.
+//
+// Note that this API is experimental.
+func ReadDeterminismLog(r io.Reader) (*DeterminismLog, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(determinismLogMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != determinismLogMagic {
+		return nil, errors.New("ebiten: invalid determinism log header")
+	}
+
+	l := &DeterminismLog{}
+	var num uint32
+	if err := binary.Read(br, binary.LittleEndian, &num); err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < num; i++ {
+		var e determinismEntry
+		var n uint32
+		if err := binary.Read(br, binary.LittleEndian, &e.tick); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		for j := uint32(0); j < n; j++ {
+			var nameLen uint16
+			if err := binary.Read(br, binary.LittleEndian, &nameLen); err != nil {
+				return nil, err
+			}
+			name := make([]byte, nameLen)
+			if _, err := io.ReadFull(br, name); err != nil {
+				return nil, err
+			}
+			var h uint64
+			if err := binary.Read(br, binary.LittleEndian, &h); err != nil {
+				return nil, err
+			}
+			e.names = append(e.names, string(name))
+			e.hashes = append(e.hashes, h)
+		}
+		if len(l.entries) > 0 && l.entries[len(l.entries)-1].tick >= e.tick {
+			return nil, fmt.Errorf("ebiten: the ticks in the determinism log are not sorted at %d", e.tick)
+		}
+		l.entries = append(l.entries, e)
+	}
+	return l, nil
+}
+
+// Write writes the log to w.
+//
+// Note that this API is experimental.
+func (l *DeterminismLog) Write(w io.Writer) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(determinismLogMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(bw, binary.LittleEndian, uint32(len(l.entries))); err != nil {
+		return err
+	}
+	for _, e := range l.entries {
+		if err := binary.Write(bw, binary.LittleEndian, e.tick); err != nil {
+			return err
+		}
+		if err := binary.Write(bw, binary.LittleEndian, uint32(len(e.names))); err != nil {
+			return err
+		}
+		for i, name := range e.names {
+			if err := binary.Write(bw, binary.LittleEndian, uint16(len(name))); err != nil {
+				return err
+			}
+			if _, err := bw.WriteString(name); err != nil {
+				return err
+			}
+			if err := binary.Write(bw, binary.LittleEndian, e.hashes[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// Len returns the number of the ticks in the log.
+//
+// Note that this API is experimental.
+func (l *DeterminismLog) Len() int {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return len(l.entries)
+}
+
+// record records the entry. The entries at the same or later ticks are discarded, e.g. after a rewind.
+func (l *DeterminismLog) record(e determinismEntry) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	i := sort.Search(len(l.entries), func(i int) bool {
+		return l.entries[i].tick >= e.tick
+	})
+	l.entries = append(l.entries[:i], e)
+}
+
+// entry returns the entry at the given tick.
+func (l *DeterminismLog) entry(tick int64) (determinismEntry, bool) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	i := sort.Search(len(l.entries), func(i int) bool {
+		return l.entries[i].tick >= tick
+	})
+	if i == len(l.entries) || l.entries[i].tick != tick {
+		return determinismEntry{}, false
+	}
+	return l.entries[i], true
+}
+
+// Divergence represents the first tick where the game state differs from the recorded log.
+//
+// Note that this API is experimental.
+type Divergence struct {
+	// Tick is the tick where the game state diverged. The state at Tick is the state after the Update calls of the
+	// previous ticks, so the cause is in the Update just before Tick or earlier.
+	Tick int64
+
+	// States is the names of the registered Snapshotters whose states diverged, in order.
+	// States includes the names that exist only in either the log or the current game.
+	States []string
+
+	// Data is the current snapshots of the diverged states at Tick, keyed by the names. This is useful to compare
+	// with the states dumped in the recording run.
+	Data map[string][]byte
+}
+
+// String returns a human-readable description of the divergence.
+func (d *Divergence) String() string {
+	return fmt.Sprintf("ebiten: the game state diverged at tick %d: %s", d.Tick, strings.Join(d.States, ", "))
+}
+
+type determinismAuditor struct {
+	log        *DeterminismLog
+	mode       DeterminismAuditMode
+	auditing   bool
+	divergence *Divergence
+	m          sync.Mutex
+}
+
+var theDeterminismAuditor = &determinismAuditor{}
+
+// StartDeterminismAudit starts hashing the states of the registered Snapshotters every tick.
+//
+// The states are hashed at the start of every tick, before Update is called and after a rewind requested by
+// Rewind is applied. With DeterminismAuditModeRecord, the hashes are recorded into log. With
+// DeterminismAuditModeVerify, the hashes are compared with the ones in log, that is typically recorded in another
+// run with the same inputs, and the first divergent tick is reported by FirstDivergence. The ticks that are not in
+// log are not compared.
+//
+// This is useful to find desync bugs of lockstep networking or replay systems. Hashing every tick is not cheap,
+// so this is for debugging.
+//
+// StartDeterminismAudit returns an error if the determinism audit is already started.
+//
+// StartDeterminismAudit is concurrent-safe.
+//
+// Note that this API is experimental.
+func StartDeterminismAudit(log *DeterminismLog, mode DeterminismAuditMode) error {
+	if log == nil {
+		return errors.New("ebiten: the determinism log must not be nil")
+	}
+	if mode != DeterminismAuditModeRecord && mode != DeterminismAuditModeVerify {
+		return fmt.Errorf("ebiten: invalid determinism audit mode: %d", mode)
+	}
+
+	a := theDeterminismAuditor
+	a.m.Lock()
+	defer a.m.Unlock()
+	if a.auditing {
+		return errors.New("ebiten: the determinism audit is already started")
+	}
+	a.log = log
+	a.mode = mode
+	a.auditing = true
+	a.divergence = nil
+	return nil
+}
+
+// IsDeterminismAuditing reports whether the determinism audit is started by StartDeterminismAudit.
+//
+// IsDeterminismAuditing is concurrent-safe.
+//
+// Note that this API is experimental.
+func IsDeterminismAuditing() bool {
+	a := theDeterminismAuditor
+	a.m.Lock()
+	defer a.m.Unlock()
+	return a.auditing
+}
+
+// StopDeterminismAudit stops the determinism audit started by StartDeterminismAudit.
+// The first divergence is kept and still returned by FirstDivergence.
+//
+// StopDeterminismAudit returns an error if the determinism audit is not started.
+//
+// StopDeterminismAudit is concurrent-safe.
+//
+// Note that this API is experimental.
+func StopDeterminismAudit() error {
+	a := theDeterminismAuditor
+	a.m.Lock()
+	defer a.m.Unlock()
+	if !a.auditing {
+		return errors.New("ebiten: the determinism audit is not started")
+	}
+	a.auditing = false
+	a.log = nil
+	return nil
+}
+
+// FirstDivergence returns the first divergence found by the determinism audit with DeterminismAuditModeVerify.
+// FirstDivergence returns nil if no divergence is found.
+//
+// After a divergence is found, the states are no longer hashed until the next StartDeterminismAudit.
+//
+// FirstDivergence is concurrent-safe.
+//
+// Note that this API is experimental.
+func FirstDivergence() *Divergence {
+	a := theDeterminismAuditor
+	a.m.Lock()
+	defer a.m.Unlock()
+	return a.divergence
+}
+
+// beginTick hashes the states at the start of a tick, before the game's Update.
+func (a *determinismAuditor) beginTick(c *uiContext) error {
+	a.m.Lock()
+	log, mode := a.log, a.mode
+	if !a.auditing || a.divergence != nil {
+		a.m.Unlock()
+		return nil
+	}
+	a.m.Unlock()
+
+	tick := atomic.LoadInt64(&c.tick)
+
+	var recorded determinismEntry
+	if mode == DeterminismAuditModeVerify {
+		e, ok := log.entry(tick)
+		if !ok {
+			return nil
+		}
+		recorded = e
+	}
+
+	names, snapshotters := theRewinder.registeredSnapshotters()
+	e := determinismEntry{
+		tick:   tick,
+		names:  names,
+		hashes: make([]uint64, len(names)),
+	}
+	data := make([][]byte, len(names))
+	for i, s := range snapshotters {
+		d, err := s.Snapshot()
+		if err != nil {
+			return err
+		}
+		h := fnv.New64a()
+		_, _ = h.Write(d)
+		e.hashes[i] = h.Sum64()
+		data[i] = d
+	}
+
+	if mode == DeterminismAuditModeRecord {
+		log.record(e)
+		return nil
+	}
+
+	if d := diverge(recorded, e, data); d != nil {
+		a.m.Lock()
+		a.divergence = d
+		a.m.Unlock()
+	}
+	return nil
+}
+
+// diverge compares the recorded entry and the current entry, and returns the divergence if they differ.
+// Both names of the entries are sorted.
+func diverge(recorded, current determinismEntry, data [][]byte) *Divergence {
+	var states []string
+	i, j := 0, 0
+	for i < len(recorded.names) || j < len(current.names) {
+		switch {
+		case j == len(current.names) || (i < len(recorded.names) && recorded.names[i] < current.names[j]):
+			states = append(states, recorded.names[i])
+			i++
+		case i == len(recorded.names) || current.names[j] < recorded.names[i]:
+			states = append(states, current.names[j])
+			j++
+		default:
+			if recorded.hashes[i] != current.hashes[j] {
+				states = append(states, current.names[j])
+			}
+			i++
+			j++
+		}
+	}
+	if len(states) == 0 {
+		return nil
+	}
+
+	d := &Divergence{
+		Tick:   current.tick,
+		States: states,
+		Data:   map[string][]byte{},
+	}
+	for _, name := range states {
+		if k := sort.SearchStrings(current.names, name); k < len(current.names) && current.names[k] == name {
+			d.Data[name] = data[k]
+		}
+	}
+	return d
+}
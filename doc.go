@@ -90,10 +90,19 @@
 // `EBITEN_SCREENSHOT_KEY=q`, you can take a game screen's screenshot
 // by pressing Q key. This works only on desktops.
 //
+// `EBITEN_RECORDING_KEY` environment variable specifies the key
+// to start and stop recording the screen. When recording is stopped,
+// the recorded clip is saved as an animated GIF. This works only on desktops.
+//
 // `EBITEN_INTERNAL_IMAGES_KEY` environment variable specifies the key
 // to dump all the internal images. This is valid only when the build tag
 // 'ebitendebug' is specified. This works only on desktops.
 //
+// `EBITEN_ATLASES_KEY` environment variable specifies the key
+// to dump the internal texture atlases with images showing their occupancies.
+// This is useful to know the fragmentation of the atlases. This is valid only
+// when the build tag 'ebitendebug' is specified. This works only on desktops.
+//
 // Build tags
 //
 // `ebitendebug` outputs a log of graphics commands. This is useful to know what happens in Ebiten. In general, the
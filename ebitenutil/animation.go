@@ -0,0 +1,103 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"io"
+	"time"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil/internal/anim"
+)
+
+// Animation represents the frames of an animated image.
+type Animation struct {
+	// Frames is the frame images. All the frames have the same size.
+	Frames []*ebiten.Image
+
+	// Delays is the durations for the frames to be displayed.
+	Delays []time.Duration
+
+	// LoopCount is the number of times the animation is played. 0 means infinite.
+	LoopCount int
+}
+
+// DecodeAnimation decodes an animated GIF or APNG image and returns the frames as Animation.
+//
+// Each frame is composited with the previous frames, then the frames can be rendered as they are.
+// A non-animated GIF or PNG image is decoded as an animation with one frame.
+//
+// DecodeAnimation is intended to be used mainly for prototyping purpose. Decoding all the frames takes memory in
+// proportion to the number of frames.
+func DecodeAnimation(r io.Reader, filter ebiten.Filter) (*Animation, error) {
+	a, err := anim.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	r2 := &Animation{
+		LoopCount: a.LoopCount,
+	}
+	for _, f := range a.Frames {
+		img, err := ebiten.NewImageFromImage(f.Image, filter)
+		if err != nil {
+			r2.Dispose()
+			return nil, err
+		}
+		r2.Frames = append(r2.Frames, img)
+		r2.Delays = append(r2.Delays, f.Delay)
+	}
+	return r2, nil
+}
+
+// Duration returns the duration of one loop of the animation.
+func (a *Animation) Duration() time.Duration {
+	var d time.Duration
+	for _, delay := range a.Delays {
+		d += delay
+	}
+	return d
+}
+
+// FrameAt returns the frame image at the given elapsed time t from the start.
+//
+// FrameAt takes LoopCount into account. After the animation ends, FrameAt returns the last frame.
+func (a *Animation) FrameAt(t time.Duration) *ebiten.Image {
+	if len(a.Frames) == 0 {
+		return nil
+	}
+	d := a.Duration()
+	if t < 0 || d == 0 {
+		return a.Frames[0]
+	}
+	if a.LoopCount > 0 && t >= d*time.Duration(a.LoopCount) {
+		return a.Frames[len(a.Frames)-1]
+	}
+	t %= d
+	for i, delay := range a.Delays {
+		if t < delay {
+			return a.Frames[i]
+		}
+		t -= delay
+	}
+	return a.Frames[len(a.Frames)-1]
+}
+
+// Dispose disposes all the frame images.
+func (a *Animation) Dispose() {
+	for _, f := range a.Frames {
+		f.Dispose()
+	}
+}
@@ -0,0 +1,375 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package debugui provides a small immediate-mode GUI for debugging and tuning.
+//
+// The widgets are rendered with the debug font of ebitenutil. This package is intended to be used mainly for
+// debugging or prototyping purpose, e.g., tuning panels.
+//
+// Here is an example:
+//
+//     var ui = debugui.New()
+//
+//     func update(screen *ebiten.Image) error {
+//         ui.Begin()
+//         ui.Window("Debug", 10, 10, 200, func() {
+//             ui.Text(fmt.Sprintf("TPS: %0.2f", ebiten.CurrentTPS()))
+//             ui.Checkbox("Show grid", &showGrid)
+//             ui.SliderFloat("Speed", &speed, 0, 10)
+//             if ui.Button("Reset") {
+//                 reset()
+//             }
+//         })
+//
+//         // Draw the game here.
+//
+//         ui.Draw(screen)
+//         return nil
+//     }
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package debugui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/ebitenutil/internal/assets"
+	"github.com/hajimehoshi/ebiten/inpututil"
+)
+
+const (
+	charWidth  = assets.CharWidth
+	charHeight = assets.CharHeight
+
+	padding    = 4
+	lineHeight = charHeight + padding
+)
+
+var (
+	windowColor      = color.RGBA{0x20, 0x20, 0x20, 0xe0}
+	titleBarColor    = color.RGBA{0x40, 0x40, 0x80, 0xff}
+	widgetColor      = color.RGBA{0x50, 0x50, 0x50, 0xff}
+	widgetHoverColor = color.RGBA{0x70, 0x70, 0x70, 0xff}
+	widgetFocusColor = color.RGBA{0x60, 0x60, 0xa0, 0xff}
+)
+
+type command struct {
+	rect image.Rectangle
+	clr  color.Color
+	text string
+}
+
+type windowState struct {
+	x         int
+	y         int
+	collapsed bool
+}
+
+// UI represents an immediate-mode GUI.
+type UI struct {
+	windows map[string]*windowState
+
+	// rects is the regions of the windows in the last frame.
+	rects     []image.Rectangle
+	nextRects []image.Rectangle
+
+	commands []command
+
+	// The states of the current window.
+	window      string
+	windowX     int
+	windowY     int
+	windowWidth int
+	cursorY     int
+
+	// active is the ID of the widget being dragged.
+	active string
+
+	mouseX           int
+	mouseY           int
+	mousePressed     bool
+	mouseJustPressed bool
+	prevMouseX       int
+	prevMouseY       int
+}
+
+// New returns a new UI.
+func New() *UI {
+	return &UI{
+		windows: map[string]*windowState{},
+	}
+}
+
+// Begin starts a new frame of the UI.
+//
+// Begin must be called once every frame before any other functions.
+func (u *UI) Begin() {
+	u.commands = u.commands[:0]
+	u.rects, u.nextRects = u.nextRects, u.rects[:0]
+
+	u.prevMouseX, u.prevMouseY = u.mouseX, u.mouseY
+	u.mouseX, u.mouseY = ebiten.CursorPosition()
+	u.mousePressed = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	u.mouseJustPressed = inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	if !u.mousePressed {
+		u.active = ""
+	}
+}
+
+// IsCapturingInput reports whether the mouse cursor is on the UI or the UI is being operated.
+//
+// Use IsCapturingInput to ignore the mouse inputs for your game.
+func (u *UI) IsCapturingInput() bool {
+	if u.active != "" {
+		return true
+	}
+	p := image.Pt(u.mouseX, u.mouseY)
+	for _, r := range u.rects {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *UI) isHovered(r image.Rectangle) bool {
+	return image.Pt(u.mouseX, u.mouseY).In(r)
+}
+
+func (u *UI) drawRect(r image.Rectangle, clr color.Color) {
+	u.commands = append(u.commands, command{rect: r, clr: clr})
+}
+
+func (u *UI) drawText(str string, x, y int) {
+	u.commands = append(u.commands, command{rect: image.Rect(x, y, x, y), text: str})
+}
+
+// Window adds a window with the given title at (x, y) with the given width.
+// f adds the widgets of the window.
+//
+// The window can be moved by dragging its title bar, and can be collapsed by clicking the mark on the title bar.
+// The title is used as the ID of the window, and must be unique.
+func (u *UI) Window(title string, x, y, width int, f func()) {
+	s, ok := u.windows[title]
+	if !ok {
+		s = &windowState{
+			x: x,
+			y: y,
+		}
+		u.windows[title] = s
+	}
+
+	id := title + "#title"
+	titleRect := image.Rect(s.x, s.y, s.x+width, s.y+lineHeight)
+	collapseRect := image.Rect(s.x, s.y, s.x+lineHeight, s.y+lineHeight)
+	if u.mouseJustPressed && u.isHovered(titleRect) && u.active == "" {
+		u.active = id
+		if u.isHovered(collapseRect) {
+			s.collapsed = !s.collapsed
+		}
+	}
+	if u.active == id {
+		s.x += u.mouseX - u.prevMouseX
+		s.y += u.mouseY - u.prevMouseY
+		titleRect = image.Rect(s.x, s.y, s.x+width, s.y+lineHeight)
+	}
+
+	// The height of the background is determined after f is called.
+	bg := len(u.commands)
+	u.drawRect(image.Rectangle{}, windowColor)
+	u.drawRect(titleRect, titleBarColor)
+	mark := "-"
+	if s.collapsed {
+		mark = "+"
+	}
+	u.drawText(mark, s.x+(lineHeight-charWidth)/2, s.y+padding/2)
+	u.drawText(title, s.x+lineHeight, s.y+padding/2)
+
+	u.window = title
+	u.windowX = s.x
+	u.windowY = s.y
+	u.windowWidth = width
+	u.cursorY = s.y + lineHeight + padding
+	if !s.collapsed {
+		f()
+	}
+	u.window = ""
+
+	r := image.Rect(s.x, s.y, s.x+width, u.cursorY)
+	u.commands[bg].rect = r
+	u.nextRects = append(u.nextRects, r)
+}
+
+// nextRow returns the region for the next widget in the current window.
+func (u *UI) nextRow() image.Rectangle {
+	if u.window == "" {
+		panic("debugui: widgets must be added in Window")
+	}
+	r := image.Rect(u.windowX+padding, u.cursorY, u.windowX+u.windowWidth-padding, u.cursorY+lineHeight)
+	u.cursorY += lineHeight + padding
+	return r
+}
+
+func (u *UI) widgetID(label string) string {
+	return u.window + "#" + label
+}
+
+// Text adds a text.
+func (u *UI) Text(str string) {
+	r := u.nextRow()
+	u.drawText(str, r.Min.X, r.Min.Y+padding/2)
+}
+
+// Button adds a button with the given label.
+//
+// Button returns true when the button is clicked.
+func (u *UI) Button(label string) bool {
+	r := u.nextRow()
+	clicked := false
+	clr := color.Color(widgetColor)
+	if u.isHovered(r) {
+		clr = widgetHoverColor
+		if u.mouseJustPressed && u.active == "" {
+			u.active = u.widgetID(label)
+			clicked = true
+		}
+	}
+	if u.active == u.widgetID(label) {
+		clr = widgetFocusColor
+	}
+	u.drawRect(r, clr)
+	u.drawText(label, r.Min.X+(r.Dx()-len(label)*charWidth)/2, r.Min.Y+padding/2)
+	return clicked
+}
+
+// Checkbox adds a checkbox with the given label.
+//
+// Checkbox returns true when the value is changed.
+func (u *UI) Checkbox(label string, value *bool) bool {
+	r := u.nextRow()
+	box := image.Rect(r.Min.X, r.Min.Y, r.Min.X+lineHeight, r.Max.Y)
+	changed := false
+	clr := color.Color(widgetColor)
+	if u.isHovered(r) {
+		clr = widgetHoverColor
+		if u.mouseJustPressed && u.active == "" {
+			u.active = u.widgetID(label)
+			*value = !*value
+			changed = true
+		}
+	}
+	u.drawRect(box, clr)
+	if *value {
+		u.drawRect(box.Inset(padding), widgetFocusColor)
+	}
+	u.drawText(label, box.Max.X+padding, r.Min.Y+padding/2)
+	return changed
+}
+
+// slider adds a slider and returns the rate in [0, 1] when the slider is dragged.
+func (u *UI) slider(label string, rate float64, valueStr string) (float64, bool) {
+	r := u.nextRow()
+	id := u.widgetID(label)
+
+	// The left half is for the bar and the right half is for the label.
+	bar := image.Rect(r.Min.X, r.Min.Y, r.Min.X+r.Dx()/2, r.Max.Y)
+	if u.isHovered(bar) && u.mouseJustPressed && u.active == "" {
+		u.active = id
+	}
+	changed := false
+	if u.active == id {
+		// A bar without width, e.g., in a too narrow window, can't have a position. Treat it as the minimum.
+		rate = 0
+		if bar.Dx() > 0 {
+			rate = float64(u.mouseX-bar.Min.X) / float64(bar.Dx())
+		}
+		if rate < 0 {
+			rate = 0
+		}
+		if rate > 1 {
+			rate = 1
+		}
+		changed = true
+	}
+
+	clr := color.Color(widgetColor)
+	if u.isHovered(bar) || u.active == id {
+		clr = widgetHoverColor
+	}
+	u.drawRect(bar, clr)
+	knobX := bar.Min.X + int(rate*float64(bar.Dx()-padding))
+	u.drawRect(image.Rect(knobX, bar.Min.Y, knobX+padding, bar.Max.Y), widgetFocusColor)
+	u.drawText(valueStr, bar.Min.X+(bar.Dx()-len(valueStr)*charWidth)/2, r.Min.Y+padding/2)
+	u.drawText(label, bar.Max.X+padding, r.Min.Y+padding/2)
+	return rate, changed
+}
+
+// SliderFloat adds a slider with the given label for a float value in [min, max].
+//
+// SliderFloat returns true when the value is changed.
+func (u *UI) SliderFloat(label string, value *float64, min, max float64) bool {
+	rate := 0.0
+	if max > min {
+		rate = (*value - min) / (max - min)
+	}
+	rate, changed := u.slider(label, rate, strconv.FormatFloat(*value, 'f', 2, 64))
+	if !changed {
+		return false
+	}
+	v := min + rate*(max-min)
+	if v == *value {
+		return false
+	}
+	*value = v
+	return true
+}
+
+// SliderInt adds a slider with the given label for an integer value in [min, max].
+//
+// SliderInt returns true when the value is changed.
+func (u *UI) SliderInt(label string, value *int, min, max int) bool {
+	rate := 0.0
+	if max > min {
+		rate = float64(*value-min) / float64(max-min)
+	}
+	rate, changed := u.slider(label, rate, fmt.Sprint(*value))
+	if !changed {
+		return false
+	}
+	v := min + int(rate*float64(max-min)+0.5)
+	if v == *value {
+		return false
+	}
+	*value = v
+	return true
+}
+
+// Draw renders the UI on the given screen.
+//
+// Draw should be called after all the windows are added in the frame.
+func (u *UI) Draw(screen *ebiten.Image) {
+	for _, c := range u.commands {
+		if c.text != "" {
+			ebitenutil.DebugPrintAt(screen, c.text, c.rect.Min.X, c.rect.Min.Y)
+			continue
+		}
+		r := c.rect
+		ebitenutil.DrawRect(screen, float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy()), c.clr)
+	}
+}
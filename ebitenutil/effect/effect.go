@@ -0,0 +1,225 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package effect provides small visual effects for games, like screen shakes, flashes, hit-stops and scene
+// transitions.
+//
+// All the effects are driven by ticks. Call Update of each effect once in your game's Update.
+//
+// Here is an example:
+//
+//     var (
+//         shake   effect.Shake
+//         flash   effect.Flash
+//         hitStop effect.HitStop
+//     )
+//
+//     func update(screen *ebiten.Image) error {
+//         shake.Update()
+//         flash.Update()
+//         if hitStop.Update() {
+//             // Update the game world here.
+//         }
+//         if hit {
+//             shake.Start(8, 20)
+//             flash.Start(color.White, 10)
+//             hitStop.Start(6, 0)
+//         }
+//
+//         op := &ebiten.DrawImageOptions{}
+//         shake.Apply(&op.GeoM)
+//         screen.DrawImage(world, op)
+//         flash.Draw(screen)
+//         return nil
+//     }
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package effect
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+// Shake is a screen shake effect. The zero value is ready to use.
+type Shake struct {
+	amplitude float64
+	duration  int
+	tick      int
+	phases    [4]float64
+
+	// Frequency is the number of oscillations per tick. The default (zero) value is 0.3.
+	Frequency float64
+}
+
+// Start starts shaking with the given amplitude in pixels for the given duration in ticks.
+//
+// If a stronger shake is in progress, Start does nothing.
+func (s *Shake) Start(amplitude float64, duration int) {
+	if duration <= 0 {
+		return
+	}
+	if s.IsActive() && s.currentAmplitude() > amplitude {
+		return
+	}
+	s.amplitude = amplitude
+	s.duration = duration
+	s.tick = 0
+	for i := range s.phases {
+		s.phases[i] = rand.Float64() * 2 * math.Pi
+	}
+}
+
+// Update proceeds the shake by one tick.
+func (s *Shake) Update() {
+	if s.tick < s.duration {
+		s.tick++
+	}
+}
+
+// IsActive reports whether the shake is in progress.
+func (s *Shake) IsActive() bool {
+	return s.tick < s.duration
+}
+
+func (s *Shake) currentAmplitude() float64 {
+	if !s.IsActive() {
+		return 0
+	}
+	// Decay quadratically for a natural look.
+	r := 1 - float64(s.tick)/float64(s.duration)
+	return s.amplitude * r * r
+}
+
+// Offset returns the current offset of the shake in pixels.
+func (s *Shake) Offset() (x, y float64) {
+	a := s.currentAmplitude()
+	if a == 0 {
+		return 0, 0
+	}
+	f := s.Frequency
+	if f == 0 {
+		f = 0.3
+	}
+	t := float64(s.tick) * f * 2 * math.Pi
+	// Mix two sine waves with different frequencies on each axis to avoid a regular motion.
+	x = a * (math.Sin(t+s.phases[0])*0.7 + math.Sin(t*1.7+s.phases[1])*0.3)
+	y = a * (math.Sin(t*1.1+s.phases[2])*0.7 + math.Sin(t*1.9+s.phases[3])*0.3)
+	return x, y
+}
+
+// Apply translates the given GeoM by the current offset. Apply this to the camera's GeoM.
+func (s *Shake) Apply(geoM *ebiten.GeoM) {
+	geoM.Translate(s.Offset())
+}
+
+// Flash is a full-screen flash effect. The zero value is ready to use.
+type Flash struct {
+	color    color.Color
+	duration int
+	tick     int
+}
+
+// Start starts a flash with the given color for the given duration in ticks. The flash fades out linearly.
+func (f *Flash) Start(clr color.Color, duration int) {
+	if duration <= 0 {
+		return
+	}
+	f.color = clr
+	f.duration = duration
+	f.tick = 0
+}
+
+// Update proceeds the flash by one tick.
+func (f *Flash) Update() {
+	if f.tick < f.duration {
+		f.tick++
+	}
+}
+
+// IsActive reports whether the flash is in progress.
+func (f *Flash) IsActive() bool {
+	return f.tick < f.duration
+}
+
+// Alpha returns the current opacity of the flash in [0, 1].
+func (f *Flash) Alpha() float64 {
+	if !f.IsActive() {
+		return 0
+	}
+	return 1 - float64(f.tick)/float64(f.duration)
+}
+
+// Draw draws the flash overlay on the whole screen. Draw does nothing when the flash is not active.
+func (f *Flash) Draw(screen *ebiten.Image) {
+	a := f.Alpha()
+	if a == 0 {
+		return
+	}
+	w, h := screen.Size()
+	r, g, b, ca := f.color.RGBA()
+	// The color is premultiplied. Scale all the components by the alpha to keep it premultiplied.
+	clr := color.RGBA64{
+		R: uint16(float64(r) * a),
+		G: uint16(float64(g) * a),
+		B: uint16(float64(b) * a),
+		A: uint16(float64(ca) * a),
+	}
+	ebitenutil.DrawRect(screen, 0, 0, float64(w), float64(h), clr)
+}
+
+// HitStop is a hit-stop effect that freezes or slows down the game for a moment. The zero value is ready to use.
+type HitStop struct {
+	duration int
+	scale    float64
+	acc      float64
+}
+
+// Start starts a hit-stop for the given duration in ticks.
+//
+// scale is the speed of the game during the hit-stop in [0, 1]. 0 means the game is frozen, and 0.5 means the game
+// is updated every other tick.
+func (h *HitStop) Start(duration int, scale float64) {
+	if duration <= 0 {
+		return
+	}
+	if duration > h.duration {
+		h.duration = duration
+	}
+	h.scale = math.Max(0, math.Min(1, scale))
+	h.acc = 0
+}
+
+// Update proceeds the hit-stop by one tick, and reports whether the game world should be updated in this tick.
+func (h *HitStop) Update() bool {
+	if h.duration <= 0 {
+		return true
+	}
+	h.duration--
+	h.acc += h.scale
+	if h.acc >= 1 {
+		h.acc--
+		return true
+	}
+	return false
+}
+
+// IsActive reports whether the hit-stop is in progress.
+func (h *HitStop) IsActive() bool {
+	return h.duration > 0
+}
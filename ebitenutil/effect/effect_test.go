@@ -0,0 +1,70 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package effect_test
+
+import (
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/ebitenutil/effect"
+)
+
+func TestShake(t *testing.T) {
+	var s Shake
+	s.Start(10, 30)
+	moved := false
+	for i := 0; i < 30; i++ {
+		x, y := s.Offset()
+		if x*x+y*y > 10*10*2 {
+			t.Errorf("the offset (%f, %f) must be within the amplitude", x, y)
+		}
+		if x != 0 || y != 0 {
+			moved = true
+		}
+		s.Update()
+	}
+	if !moved {
+		t.Errorf("the shake must move")
+	}
+	if s.IsActive() {
+		t.Errorf("the shake must end")
+	}
+	if x, y := s.Offset(); x != 0 || y != 0 {
+		t.Errorf("the offset must be zero after the shake: (%f, %f)", x, y)
+	}
+}
+
+func TestHitStop(t *testing.T) {
+	var h HitStop
+	h.Start(8, 0.25)
+	n := 0
+	for i := 0; i < 8; i++ {
+		if h.Update() {
+			n++
+		}
+	}
+	if got, want := n, 2; got != want {
+		t.Errorf("the number of updates during the hit-stop: got: %d, want: %d", got, want)
+	}
+	if !h.Update() {
+		t.Errorf("the game must be updated after the hit-stop")
+	}
+
+	h.Start(3, 0)
+	for i := 0; i < 3; i++ {
+		if h.Update() {
+			t.Errorf("the game must be frozen")
+		}
+	}
+}
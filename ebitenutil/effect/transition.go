@@ -0,0 +1,201 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package effect
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Transition is a renderer of a transition between two scenes.
+type Transition interface {
+	// Draw draws the transition from the image from to the image to on dst.
+	// rate is the progress of the transition in [0, 1].
+	Draw(dst, from, to *ebiten.Image, rate float64)
+}
+
+// Crossfade is a transition that fades the incoming scene in over the outgoing scene.
+type Crossfade struct{}
+
+// Draw implements Transition.
+func (Crossfade) Draw(dst, from, to *ebiten.Image, rate float64) {
+	_ = dst.DrawImage(from, nil)
+	op := &ebiten.DrawImageOptions{}
+	op.ColorM.Scale(1, 1, 1, rate)
+	_ = dst.DrawImage(to, op)
+}
+
+// WipeDirection represents the direction of a wipe.
+type WipeDirection int
+
+const (
+	WipeLeftToRight WipeDirection = iota
+	WipeRightToLeft
+	WipeTopToBottom
+	WipeBottomToTop
+)
+
+// Wipe is a transition that reveals the incoming scene by moving an edge across the screen.
+type Wipe struct {
+	Direction WipeDirection
+}
+
+// Draw implements Transition.
+func (w Wipe) Draw(dst, from, to *ebiten.Image, rate float64) {
+	_ = dst.DrawImage(from, nil)
+
+	b := to.Bounds()
+	dx := int(math.Round(float64(b.Dx()) * rate))
+	dy := int(math.Round(float64(b.Dy()) * rate))
+	r := b
+	switch w.Direction {
+	case WipeLeftToRight:
+		r.Max.X = b.Min.X + dx
+	case WipeRightToLeft:
+		r.Min.X = b.Max.X - dx
+	case WipeTopToBottom:
+		r.Max.Y = b.Min.Y + dy
+	case WipeBottomToTop:
+		r.Min.Y = b.Max.Y - dy
+	default:
+		panic("effect: invalid wipe direction")
+	}
+	if r.Empty() {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(r.Min.X-b.Min.X), float64(r.Min.Y-b.Min.Y))
+	_ = dst.DrawImage(to.SubImage(r).(*ebiten.Image), op)
+}
+
+// Pixelate is a transition that pixelates the outgoing scene, and then depixelates the incoming scene.
+type Pixelate struct {
+	// MaxBlockSize is the size of a block in pixels at the middle of the transition.
+	// The default (zero) value is 32.
+	MaxBlockSize int
+
+	offscreen *ebiten.Image
+}
+
+// Draw implements Transition.
+func (p *Pixelate) Draw(dst, from, to *ebiten.Image, rate float64) {
+	src := from
+	t := rate * 2
+	if rate >= 0.5 {
+		src = to
+		t = 2 - rate*2
+	}
+
+	max := p.MaxBlockSize
+	if max == 0 {
+		max = 32
+	}
+	size := 1 + int(float64(max-1)*t)
+	if size <= 1 {
+		_ = dst.DrawImage(src, nil)
+		return
+	}
+
+	w, h := src.Size()
+	p.offscreen = ensureOffscreen(p.offscreen, w, h)
+
+	// Shrink the source into the offscreen, and enlarge it with the nearest filter.
+	sw := (w + size - 1) / size
+	sh := (h + size - 1) / size
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(sw)/float64(w), float64(sh)/float64(h))
+	op.Filter = ebiten.FilterLinear
+	_ = p.offscreen.DrawImage(src, op)
+
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(size), float64(size))
+	op.Filter = ebiten.FilterNearest
+	_ = dst.DrawImage(p.offscreen.SubImage(image.Rect(0, 0, sw, sh)).(*ebiten.Image), op)
+}
+
+// SceneTransition runs a transition between two scenes over ticks. The zero value is ready to use.
+//
+// SceneTransition captures the outgoing and incoming scenes into offscreen images every frame, so both scenes can
+// keep animating during the transition.
+type SceneTransition struct {
+	transition Transition
+	duration   int
+	tick       int
+
+	from *ebiten.Image
+	to   *ebiten.Image
+}
+
+// Start starts the transition for the given duration in ticks.
+func (s *SceneTransition) Start(transition Transition, duration int) {
+	if duration <= 0 {
+		return
+	}
+	s.transition = transition
+	s.duration = duration
+	s.tick = 0
+}
+
+// Update proceeds the transition by one tick.
+func (s *SceneTransition) Update() {
+	if s.tick < s.duration {
+		s.tick++
+	}
+}
+
+// IsActive reports whether the transition is in progress.
+func (s *SceneTransition) IsActive() bool {
+	return s.tick < s.duration
+}
+
+// Rate returns the progress of the transition in [0, 1].
+func (s *SceneTransition) Rate() float64 {
+	if !s.IsActive() {
+		return 1
+	}
+	return float64(s.tick) / float64(s.duration)
+}
+
+func ensureOffscreen(img *ebiten.Image, w, h int) *ebiten.Image {
+	if img != nil {
+		if iw, ih := img.Size(); iw == w && ih == h {
+			_ = img.Clear()
+			return img
+		}
+		_ = img.Dispose()
+	}
+	img, _ = ebiten.NewImage(w, h, ebiten.FilterDefault)
+	return img
+}
+
+// Draw draws the transition on dst. drawFrom and drawTo are called to draw the outgoing and incoming scenes into
+// offscreen images with the same size as dst.
+//
+// If the transition is not active, Draw only calls drawTo with dst.
+func (s *SceneTransition) Draw(dst *ebiten.Image, drawFrom, drawTo func(screen *ebiten.Image)) {
+	if !s.IsActive() {
+		drawTo(dst)
+		return
+	}
+
+	w, h := dst.Size()
+	s.from = ensureOffscreen(s.from, w, h)
+	s.to = ensureOffscreen(s.to, w, h)
+	drawFrom(s.from)
+	drawTo(s.to)
+	s.transition.Draw(dst, s.from, s.to, s.Rate())
+}
@@ -0,0 +1,220 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gamepadcursor provides a virtual mouse cursor operated by a gamepad.
+//
+// The cursor is moved by a stick of a gamepad, and the position and the button states are injected as the mouse's
+// ones with ebiten.InjectCursorPosition and ebiten.InjectMouseButtonEvent. Then UIs that are operated by a mouse
+// can be operated by a gamepad without any changes.
+//
+// Here is an example:
+//
+//     var cursor = gamepadcursor.New(0)
+//
+//     func update(screen *ebiten.Image) error {
+//         cursor.Bounds = screen.Bounds()
+//         cursor.Update()
+//
+//         // Update the game here. ebiten.CursorPosition and ebiten.IsMouseButtonPressed reflect the gamepad.
+//
+//         return nil
+//     }
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package gamepadcursor
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+const (
+	defaultAxisX             = 2
+	defaultAxisY             = 3
+	defaultDeadZone          = 0.2
+	defaultSpeed             = 4
+	defaultMaxSpeed          = 12
+	defaultAccelerationTicks = 60
+)
+
+// Cursor represents a virtual mouse cursor operated by a gamepad.
+//
+// The zero values of the fields mean the default values.
+type Cursor struct {
+	// GamepadID is the ID of the gamepad to operate the cursor.
+	GamepadID int
+
+	// AxisX and AxisY are the axes of the stick to move the cursor.
+	// The defaults (both zero) are 2 and 3, which are the right stick on most gamepads.
+	AxisX int
+	AxisY int
+
+	// Buttons maps the buttons of the gamepad to the mouse buttons.
+	// The default (nil) maps GamepadButton0 to the left button and GamepadButton1 to the right button.
+	Buttons map[ebiten.GamepadButton]ebiten.MouseButton
+
+	// DeadZone is the magnitude of the stick under which the stick is treated as neutral.
+	// The default (zero) value is 0.2.
+	DeadZone float64
+
+	// Speed is the speed of the cursor in pixels per tick when the stick is fully tilted.
+	// The default (zero) value is 4.
+	Speed float64
+
+	// MaxSpeed is the speed after the stick is kept tilted for AccelerationTicks ticks.
+	// The speed increases linearly from Speed to MaxSpeed. The default (zero) value is 12.
+	MaxSpeed float64
+
+	// AccelerationTicks is the number of ticks to reach MaxSpeed. The default (zero) value is 60.
+	AccelerationTicks int
+
+	// Bounds is the region that the cursor can move in the game screen coordinates.
+	// If Bounds is empty, the cursor is not confined.
+	Bounds image.Rectangle
+
+	// Snap is called with the new position of the cursor when the cursor is moved by the stick, and returns the
+	// adjusted position. Snap is useful e.g. to stick the cursor to the nearest button. Snap can be nil.
+	Snap func(x, y float64) (float64, float64)
+
+	x float64
+	y float64
+
+	movingTicks int
+	pressed     map[ebiten.MouseButton]bool
+	active      bool
+}
+
+// New returns a new Cursor operated by the gamepad (id).
+func New(gamepadID int) *Cursor {
+	return &Cursor{
+		GamepadID: gamepadID,
+	}
+}
+
+func (c *Cursor) axes() (int, int) {
+	if c.AxisX == 0 && c.AxisY == 0 {
+		return defaultAxisX, defaultAxisY
+	}
+	return c.AxisX, c.AxisY
+}
+
+func (c *Cursor) buttons() map[ebiten.GamepadButton]ebiten.MouseButton {
+	if c.Buttons != nil {
+		return c.Buttons
+	}
+	return map[ebiten.GamepadButton]ebiten.MouseButton{
+		ebiten.GamepadButton0: ebiten.MouseButtonLeft,
+		ebiten.GamepadButton1: ebiten.MouseButtonRight,
+	}
+}
+
+func orDefault(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// speed returns the current speed of the cursor with the acceleration.
+func (c *Cursor) speed() float64 {
+	s := orDefault(c.Speed, defaultSpeed)
+	ms := orDefault(c.MaxSpeed, defaultMaxSpeed)
+	n := c.AccelerationTicks
+	if n == 0 {
+		n = defaultAccelerationTicks
+	}
+	if c.movingTicks >= n {
+		return ms
+	}
+	return s + (ms-s)*float64(c.movingTicks)/float64(n)
+}
+
+// Update moves the cursor and injects the mouse inputs. Update must be called once every tick.
+func (c *Cursor) Update() {
+	ax, ay := c.axes()
+	dx := ebiten.GamepadAxis(c.GamepadID, ax)
+	dy := ebiten.GamepadAxis(c.GamepadID, ay)
+
+	mag := math.Hypot(dx, dy)
+	if mag <= orDefault(c.DeadZone, defaultDeadZone) {
+		c.movingTicks = 0
+		// Follow the actual mouse while the stick is neutral.
+		x, y := ebiten.CursorPosition()
+		if !c.active || int(c.x) != x || int(c.y) != y {
+			c.x, c.y = float64(x), float64(y)
+			c.active = false
+		}
+	} else {
+		if mag > 1 {
+			dx /= mag
+			dy /= mag
+		}
+		s := c.speed()
+		c.x += dx * s
+		c.y += dy * s
+		if c.Snap != nil {
+			c.x, c.y = c.Snap(c.x, c.y)
+		}
+		c.clamp()
+		c.movingTicks++
+		c.active = true
+		ebiten.InjectCursorPosition(int(c.x), int(c.y))
+	}
+
+	if c.pressed == nil {
+		c.pressed = map[ebiten.MouseButton]bool{}
+	}
+	for gb, mb := range c.buttons() {
+		p := ebiten.IsGamepadButtonPressed(c.GamepadID, gb)
+		if p == c.pressed[mb] {
+			continue
+		}
+		c.pressed[mb] = p
+		ebiten.InjectMouseButtonEvent(mb, p)
+		if p {
+			c.active = true
+		}
+	}
+}
+
+func (c *Cursor) clamp() {
+	b := c.Bounds
+	if b.Empty() {
+		return
+	}
+	c.x = math.Max(float64(b.Min.X), math.Min(c.x, float64(b.Max.X-1)))
+	c.y = math.Max(float64(b.Min.Y), math.Min(c.y, float64(b.Max.Y-1)))
+}
+
+// Position returns the position of the cursor in the game screen coordinates.
+func (c *Cursor) Position() (int, int) {
+	return int(c.x), int(c.y)
+}
+
+// SetPosition moves the cursor to the given position in the game screen coordinates, and injects the position.
+func (c *Cursor) SetPosition(x, y int) {
+	c.x, c.y = float64(x), float64(y)
+	c.clamp()
+	c.active = true
+	ebiten.InjectCursorPosition(int(c.x), int(c.y))
+}
+
+// IsActive reports whether the cursor is operated by the gamepad. IsActive becomes false when the actual mouse moves.
+//
+// IsActive is useful e.g. to draw the cursor only when the gamepad is used.
+func (c *Cursor) IsActive() bool {
+	return c.active
+}
@@ -0,0 +1,247 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil/internal/assets"
+)
+
+// GamepadGlyphButton represents a button of a gamepad to show its glyph.
+//
+// The face buttons are named by their positions since the labels vary among the gamepad types.
+type GamepadGlyphButton int
+
+const (
+	GamepadGlyphButtonFaceBottom GamepadGlyphButton = iota
+	GamepadGlyphButtonFaceRight
+	GamepadGlyphButtonFaceLeft
+	GamepadGlyphButtonFaceTop
+	GamepadGlyphButtonLeftShoulder
+	GamepadGlyphButtonRightShoulder
+	GamepadGlyphButtonStart
+	GamepadGlyphButtonBack
+
+	gamepadGlyphButtonNum
+)
+
+const (
+	// GamepadGlyphSize is the width and the height of a gamepad glyph image.
+	GamepadGlyphSize = 16
+)
+
+type glyphShape int
+
+const (
+	glyphShapeCircle glyphShape = iota
+	glyphShapeRect
+	glyphShapeCross
+	glyphShapeRing
+	glyphShapeSquare
+	glyphShapeTriangle
+)
+
+type glyphDesc struct {
+	shape glyphShape
+	label string
+	clr   color.RGBA
+}
+
+var (
+	glyphGray   = color.RGBA{0x60, 0x60, 0x60, 0xff}
+	glyphGreen  = color.RGBA{0x40, 0xa0, 0x40, 0xff}
+	glyphRed    = color.RGBA{0xc0, 0x30, 0x30, 0xff}
+	glyphBlue   = color.RGBA{0x30, 0x60, 0xc0, 0xff}
+	glyphYellow = color.RGBA{0xc0, 0xa0, 0x20, 0xff}
+	glyphPink   = color.RGBA{0xd0, 0x70, 0xb0, 0xff}
+	glyphTeal   = color.RGBA{0x40, 0xb0, 0xa0, 0xff}
+	glyphSky    = color.RGBA{0x70, 0x90, 0xe0, 0xff}
+)
+
+// gamepadGlyphDescs is indexed by ebiten.GamepadType and GamepadGlyphButton.
+var gamepadGlyphDescs = map[ebiten.GamepadType][gamepadGlyphButtonNum]glyphDesc{
+	ebiten.GamepadTypeUnknown: {
+		{glyphShapeCircle, "1", glyphGray},
+		{glyphShapeCircle, "2", glyphGray},
+		{glyphShapeCircle, "3", glyphGray},
+		{glyphShapeCircle, "4", glyphGray},
+		{glyphShapeRect, "L", glyphGray},
+		{glyphShapeRect, "R", glyphGray},
+		{glyphShapeRect, "ST", glyphGray},
+		{glyphShapeRect, "BK", glyphGray},
+	},
+	ebiten.GamepadTypeXbox: {
+		{glyphShapeCircle, "A", glyphGreen},
+		{glyphShapeCircle, "B", glyphRed},
+		{glyphShapeCircle, "X", glyphBlue},
+		{glyphShapeCircle, "Y", glyphYellow},
+		{glyphShapeRect, "LB", glyphGray},
+		{glyphShapeRect, "RB", glyphGray},
+		{glyphShapeRect, "M", glyphGray},
+		{glyphShapeRect, "V", glyphGray},
+	},
+	ebiten.GamepadTypePlayStation: {
+		{glyphShapeCross, "", glyphSky},
+		{glyphShapeRing, "", glyphRed},
+		{glyphShapeSquare, "", glyphPink},
+		{glyphShapeTriangle, "", glyphTeal},
+		{glyphShapeRect, "L1", glyphGray},
+		{glyphShapeRect, "R1", glyphGray},
+		{glyphShapeRect, "OP", glyphGray},
+		{glyphShapeRect, "SH", glyphGray},
+	},
+	ebiten.GamepadTypeNintendoSwitchPro: {
+		{glyphShapeCircle, "B", glyphGray},
+		{glyphShapeCircle, "A", glyphGray},
+		{glyphShapeCircle, "Y", glyphGray},
+		{glyphShapeCircle, "X", glyphGray},
+		{glyphShapeRect, "L", glyphGray},
+		{glyphShapeRect, "R", glyphGray},
+		{glyphShapeCircle, "+", glyphGray},
+		{glyphShapeCircle, "-", glyphGray},
+	},
+}
+
+var gamepadGlyphTypes = []ebiten.GamepadType{
+	ebiten.GamepadTypeUnknown,
+	ebiten.GamepadTypeXbox,
+	ebiten.GamepadTypePlayStation,
+	ebiten.GamepadTypeNintendoSwitchPro,
+}
+
+var (
+	gamepadGlyphImage     *ebiten.Image
+	gamepadGlyphSubImages = map[ebiten.GamepadType][]*ebiten.Image{}
+)
+
+func init() {
+	const s = GamepadGlyphSize
+	sheet := image.NewRGBA(image.Rect(0, 0, s*int(gamepadGlyphButtonNum), s*len(gamepadGlyphTypes)))
+	font := assets.CreateTextImage()
+	for j, t := range gamepadGlyphTypes {
+		for i, d := range gamepadGlyphDescs[t] {
+			drawGlyph(sheet, font, image.Pt(i*s, j*s), d)
+		}
+	}
+	gamepadGlyphImage, _ = ebiten.NewImageFromImage(sheet, ebiten.FilterDefault)
+	for j, t := range gamepadGlyphTypes {
+		for i := 0; i < int(gamepadGlyphButtonNum); i++ {
+			r := image.Rect(i*s, j*s, (i+1)*s, (j+1)*s)
+			gamepadGlyphSubImages[t] = append(gamepadGlyphSubImages[t], gamepadGlyphImage.SubImage(r).(*ebiten.Image))
+		}
+	}
+}
+
+func drawGlyph(dst *image.RGBA, font image.Image, origin image.Point, d glyphDesc) {
+	const s = GamepadGlyphSize
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	dark := color.RGBA{0x20, 0x20, 0x20, 0xff}
+
+	// x and y are relative to the center of the glyph.
+	for j := 0; j < s; j++ {
+		for i := 0; i < s; i++ {
+			x := float64(i) - s/2 + 0.5
+			y := float64(j) - s/2 + 0.5
+			in := x*x+y*y <= (s/2)*(s/2)
+			var clr color.RGBA
+			switch d.shape {
+			case glyphShapeCircle:
+				if in {
+					clr = d.clr
+				}
+			case glyphShapeRect:
+				if -5 <= y && y <= 5 {
+					clr = d.clr
+				}
+			case glyphShapeCross, glyphShapeRing, glyphShapeSquare, glyphShapeTriangle:
+				if !in {
+					continue
+				}
+				clr = dark
+				ax, ay := x, y
+				if ax < 0 {
+					ax = -ax
+				}
+				if ay < 0 {
+					ay = -ay
+				}
+				var on bool
+				switch d.shape {
+				case glyphShapeCross:
+					diff := ax - ay
+					on = -1 <= diff && diff <= 1 && ax <= 4.5
+				case glyphShapeRing:
+					r2 := x*x + y*y
+					on = 3.5*3.5 <= r2 && r2 <= 5*5
+				case glyphShapeSquare:
+					on = ax <= 4.5 && ay <= 4.5 && (ax >= 3.5 || ay >= 3.5)
+				case glyphShapeTriangle:
+					// The triangle's apex is at the top.
+					top, bottom := -4.5, 3.5
+					if top <= y && y <= bottom {
+						half := (y - top) * 0.6
+						on = ax <= half && (ax >= half-1.2 || y >= bottom-1)
+					}
+				}
+				if on {
+					clr = d.clr
+				}
+			}
+			dst.SetRGBA(origin.X+i, origin.Y+j, clr)
+		}
+	}
+
+	// Draw the label with the debug font.
+	const (
+		cw = assets.CharWidth
+		ch = assets.CharHeight
+	)
+	fw := font.Bounds().Dx()
+	n := fw / cw
+	ox := origin.X + (s-len(d.label)*cw)/2
+	for k, c := range d.label {
+		sx := (int(c) % n) * cw
+		sy := (int(c) / n) * ch
+		for j := 0; j < ch && j < s; j++ {
+			for i := 0; i < cw; i++ {
+				if _, _, _, a := font.At(sx+i, sy+j).RGBA(); a == 0 {
+					continue
+				}
+				dst.SetRGBA(ox+k*cw+i, origin.Y+j, white)
+			}
+		}
+	}
+}
+
+// GamepadGlyph returns an image of the glyph of the button for the gamepad type.
+// The size of the image is GamepadGlyphSize x GamepadGlyphSize.
+//
+// Use ebiten.DetectGamepadType to get the gamepad type.
+// GamepadGlyph returns generic glyphs for unknown gamepad types.
+//
+// GamepadGlyph is intended to be used mainly for debugging or prototyping purpose.
+func GamepadGlyph(gamepadType ebiten.GamepadType, button GamepadGlyphButton) *ebiten.Image {
+	if button < 0 || button >= gamepadGlyphButtonNum {
+		panic("ebitenutil: invalid gamepad glyph button")
+	}
+	imgs, ok := gamepadGlyphSubImages[gamepadType]
+	if !ok {
+		imgs = gamepadGlyphSubImages[ebiten.GamepadTypeUnknown]
+	}
+	return imgs[button]
+}
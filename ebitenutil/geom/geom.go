@@ -0,0 +1,339 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package geom provides 2D geometry utilities for collision detection like intersection tests and swept AABB.
+//
+// Shapes can be transformed with ebiten.GeoM via Transformer.
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package geom
+
+import (
+	"math"
+)
+
+// Transformer is an affine transformation. ebiten.GeoM implements Transformer.
+type Transformer interface {
+	Apply(x, y float64) (float64, float64)
+}
+
+// Vec is a 2D vector.
+type Vec struct {
+	X float64
+	Y float64
+}
+
+// V is shorthand for Vec{x, y}.
+func V(x, y float64) Vec {
+	return Vec{x, y}
+}
+
+// Add returns v + u.
+func (v Vec) Add(u Vec) Vec {
+	return Vec{v.X + u.X, v.Y + u.Y}
+}
+
+// Sub returns v - u.
+func (v Vec) Sub(u Vec) Vec {
+	return Vec{v.X - u.X, v.Y - u.Y}
+}
+
+// Scale returns v * s.
+func (v Vec) Scale(s float64) Vec {
+	return Vec{v.X * s, v.Y * s}
+}
+
+// Dot returns the dot product of v and u.
+func (v Vec) Dot(u Vec) float64 {
+	return v.X*u.X + v.Y*u.Y
+}
+
+// Cross returns the z component of the cross product of v and u.
+func (v Vec) Cross(u Vec) float64 {
+	return v.X*u.Y - v.Y*u.X
+}
+
+// Len returns the length of v.
+func (v Vec) Len() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Transform returns v transformed by t.
+func (v Vec) Transform(t Transformer) Vec {
+	x, y := t.Apply(v.X, v.Y)
+	return Vec{x, y}
+}
+
+// Rect is an axis-aligned rectangle. Min is inclusive and Max is exclusive as well as image.Rectangle.
+type Rect struct {
+	Min Vec
+	Max Vec
+}
+
+// R is shorthand for Rect{Vec{x0, y0}, Vec{x1, y1}}. The returned rectangle has the minimum and the maximum
+// coordinates swapped if necessary.
+func R(x0, y0, x1, y1 float64) Rect {
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	return Rect{Vec{x0, y0}, Vec{x1, y1}}
+}
+
+// Dx returns r's width.
+func (r Rect) Dx() float64 {
+	return r.Max.X - r.Min.X
+}
+
+// Dy returns r's height.
+func (r Rect) Dy() float64 {
+	return r.Max.Y - r.Min.Y
+}
+
+// Empty reports whether the rectangle contains no points.
+func (r Rect) Empty() bool {
+	return r.Min.X >= r.Max.X || r.Min.Y >= r.Max.Y
+}
+
+// Center returns the center of r.
+func (r Rect) Center() Vec {
+	return Vec{(r.Min.X + r.Max.X) / 2, (r.Min.Y + r.Max.Y) / 2}
+}
+
+// Add returns r translated by v.
+func (r Rect) Add(v Vec) Rect {
+	return Rect{r.Min.Add(v), r.Max.Add(v)}
+}
+
+// Contains reports whether p is in r.
+func (r Rect) Contains(p Vec) bool {
+	return r.Min.X <= p.X && p.X < r.Max.X && r.Min.Y <= p.Y && p.Y < r.Max.Y
+}
+
+// Intersects reports whether r and s have a non-empty intersection.
+func (r Rect) Intersects(s Rect) bool {
+	return !r.Empty() && !s.Empty() &&
+		r.Min.X < s.Max.X && s.Min.X < r.Max.X &&
+		r.Min.Y < s.Max.Y && s.Min.Y < r.Max.Y
+}
+
+// Intersect returns the largest rectangle contained by both r and s. If the two rectangles do not overlap then the
+// zero rectangle will be returned.
+func (r Rect) Intersect(s Rect) Rect {
+	if !r.Intersects(s) {
+		return Rect{}
+	}
+	return Rect{
+		Vec{math.Max(r.Min.X, s.Min.X), math.Max(r.Min.Y, s.Min.Y)},
+		Vec{math.Min(r.Max.X, s.Max.X), math.Min(r.Max.Y, s.Max.Y)},
+	}
+}
+
+// Polygon returns r as a polygon in clockwise order in the screen coordinates.
+func (r Rect) Polygon() Polygon {
+	return Polygon{r.Min, {r.Max.X, r.Min.Y}, r.Max, {r.Min.X, r.Max.Y}}
+}
+
+// Transform returns r transformed by t as a polygon since a rotated rectangle is not axis-aligned any longer.
+func (r Rect) Transform(t Transformer) Polygon {
+	return r.Polygon().Transform(t)
+}
+
+// Circle is a circle.
+type Circle struct {
+	Center Vec
+	Radius float64
+}
+
+// Contains reports whether p is in c.
+func (c Circle) Contains(p Vec) bool {
+	d := p.Sub(c.Center)
+	return d.Dot(d) <= c.Radius*c.Radius
+}
+
+// IntersectsCircle reports whether c and d overlap.
+func (c Circle) IntersectsCircle(d Circle) bool {
+	v := c.Center.Sub(d.Center)
+	r := c.Radius + d.Radius
+	return v.Dot(v) <= r*r
+}
+
+// IntersectsRect reports whether c and r overlap.
+func (c Circle) IntersectsRect(r Rect) bool {
+	if r.Empty() {
+		return false
+	}
+	// The nearest point in r to the center.
+	p := Vec{
+		math.Max(r.Min.X, math.Min(c.Center.X, r.Max.X)),
+		math.Max(r.Min.Y, math.Min(c.Center.Y, r.Max.Y)),
+	}
+	return c.Contains(p)
+}
+
+// IntersectsPolygon reports whether c and p overlap. p must be convex.
+func (c Circle) IntersectsPolygon(p Polygon) bool {
+	if len(p) == 0 {
+		return false
+	}
+	if p.Contains(c.Center) {
+		return true
+	}
+	for i := range p {
+		if c.Contains(nearestOnSegment(p[i], p[(i+1)%len(p)], c.Center)) {
+			return true
+		}
+	}
+	return false
+}
+
+func nearestOnSegment(a, b, p Vec) Vec {
+	ab := b.Sub(a)
+	l := ab.Dot(ab)
+	if l == 0 {
+		return a
+	}
+	t := math.Max(0, math.Min(1, p.Sub(a).Dot(ab)/l))
+	return a.Add(ab.Scale(t))
+}
+
+// Polygon is a simple polygon represented by its vertices.
+type Polygon []Vec
+
+// Transform returns p transformed by t.
+func (p Polygon) Transform(t Transformer) Polygon {
+	r := make(Polygon, len(p))
+	for i, v := range p {
+		r[i] = v.Transform(t)
+	}
+	return r
+}
+
+// Bounds returns the bounding box of p.
+func (p Polygon) Bounds() Rect {
+	if len(p) == 0 {
+		return Rect{}
+	}
+	r := Rect{p[0], p[0]}
+	for _, v := range p[1:] {
+		r.Min.X = math.Min(r.Min.X, v.X)
+		r.Min.Y = math.Min(r.Min.Y, v.Y)
+		r.Max.X = math.Max(r.Max.X, v.X)
+		r.Max.Y = math.Max(r.Max.Y, v.Y)
+	}
+	return r
+}
+
+// Contains reports whether v is in p. p can be concave.
+func (p Polygon) Contains(v Vec) bool {
+	in := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Y > v.Y) != (b.Y > v.Y) && v.X < (b.X-a.X)*(v.Y-a.Y)/(b.Y-a.Y)+a.X {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Intersects reports whether p and q overlap by the separating axis theorem. p and q must be convex.
+func (p Polygon) Intersects(q Polygon) bool {
+	if len(p) == 0 || len(q) == 0 {
+		return false
+	}
+	return !hasSeparatingAxis(p, q) && !hasSeparatingAxis(q, p)
+}
+
+func hasSeparatingAxis(p, q Polygon) bool {
+	for i := range p {
+		e := p[(i+1)%len(p)].Sub(p[i])
+		axis := Vec{-e.Y, e.X}
+		pmin, pmax := project(p, axis)
+		qmin, qmax := project(q, axis)
+		if pmax < qmin || qmax < pmin {
+			return true
+		}
+	}
+	return false
+}
+
+func project(p Polygon, axis Vec) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range p {
+		d := v.Dot(axis)
+		min = math.Min(min, d)
+		max = math.Max(max, d)
+	}
+	return min, max
+}
+
+// IntersectsRect reports whether p and r overlap. p must be convex.
+func (p Polygon) IntersectsRect(r Rect) bool {
+	if r.Empty() {
+		return false
+	}
+	return p.Intersects(r.Polygon())
+}
+
+// Sweep returns the time of impact when the rectangle r moves by the vector v against the rectangle target.
+//
+// t is in [0, 1], where 0 means the start and 1 means the end of the movement. normal is the normal vector of the hit
+// side of target, like (0, -1) for the top side. hit is false if r doesn't hit target during the movement.
+// If r and target already overlap at the start, Sweep returns 0, the zero vector and true.
+//
+// Sweep is useful to prevent fast objects from tunneling through thin walls.
+func Sweep(r Rect, v Vec, target Rect) (t float64, normal Vec, hit bool) {
+	if r.Intersects(target) {
+		return 0, Vec{}, true
+	}
+
+	// The entry and the exit times for each axis.
+	entry := func(rmin, rmax, tmin, tmax, v float64) (float64, float64) {
+		switch {
+		case v > 0:
+			return (tmin - rmax) / v, (tmax - rmin) / v
+		case v < 0:
+			return (tmax - rmin) / v, (tmin - rmax) / v
+		}
+		if rmax <= tmin || tmax <= rmin {
+			return math.Inf(1), math.Inf(-1)
+		}
+		return math.Inf(-1), math.Inf(1)
+	}
+	xEntry, xExit := entry(r.Min.X, r.Max.X, target.Min.X, target.Max.X, v.X)
+	yEntry, yExit := entry(r.Min.Y, r.Max.Y, target.Min.Y, target.Max.Y, v.Y)
+
+	tEntry := math.Max(xEntry, yEntry)
+	tExit := math.Min(xExit, yExit)
+	if tEntry > tExit || tEntry < 0 || tEntry > 1 {
+		return 0, Vec{}, false
+	}
+
+	if xEntry > yEntry {
+		if v.X > 0 {
+			normal = Vec{-1, 0}
+		} else {
+			normal = Vec{1, 0}
+		}
+	} else {
+		if v.Y > 0 {
+			normal = Vec{0, -1}
+		} else {
+			normal = Vec{0, 1}
+		}
+	}
+	return tEntry, normal, true
+}
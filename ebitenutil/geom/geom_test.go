@@ -0,0 +1,133 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geom_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/ebitenutil/geom"
+)
+
+// rotation is a Transformer that rotates points around the origin.
+type rotation struct {
+	theta float64
+}
+
+func (r rotation) Apply(x, y float64) (float64, float64) {
+	s, c := math.Sincos(r.theta)
+	return x*c - y*s, x*s + y*c
+}
+
+func TestRectIntersects(t *testing.T) {
+	cases := []struct {
+		R0   Rect
+		R1   Rect
+		Want bool
+	}{
+		{R(0, 0, 10, 10), R(5, 5, 15, 15), true},
+		{R(0, 0, 10, 10), R(10, 0, 20, 10), false},
+		{R(0, 0, 10, 10), R(2, 2, 3, 3), true},
+		{R(0, 0, 10, 10), R(11, 11, 12, 12), false},
+		{R(0, 0, 0, 10), R(0, 0, 10, 10), false},
+	}
+	for _, c := range cases {
+		if got := c.R0.Intersects(c.R1); got != c.Want {
+			t.Errorf("%v.Intersects(%v): got: %v, want: %v", c.R0, c.R1, got, c.Want)
+		}
+	}
+}
+
+func TestCircle(t *testing.T) {
+	c := Circle{Center: V(0, 0), Radius: 5}
+	if !c.Contains(V(3, 4)) {
+		t.Errorf("(3, 4) must be in the circle")
+	}
+	if c.Contains(V(4, 4)) {
+		t.Errorf("(4, 4) must not be in the circle")
+	}
+	if !c.IntersectsRect(R(4, -1, 10, 1)) {
+		t.Errorf("the circle must intersect with the rectangle")
+	}
+	// The corner (4, 4) is out of the circle.
+	if c.IntersectsRect(R(4, 4, 10, 10)) {
+		t.Errorf("the circle must not intersect with the rectangle")
+	}
+	if !c.IntersectsCircle(Circle{Center: V(9, 0), Radius: 4}) {
+		t.Errorf("the circles must intersect")
+	}
+	tri := Polygon{V(6, -10), V(6, 10), V(20, 0)}
+	if c.IntersectsPolygon(tri) {
+		t.Errorf("the circle must not intersect with the triangle")
+	}
+	if !(Circle{Center: V(2, 0), Radius: 5}).IntersectsPolygon(tri) {
+		t.Errorf("the circle must intersect with the triangle")
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	// A concave polygon like 'L'.
+	l := Polygon{V(0, 0), V(2, 0), V(2, 8), V(6, 8), V(6, 10), V(0, 10)}
+	if !l.Contains(V(1, 5)) {
+		t.Errorf("(1, 5) must be in the polygon")
+	}
+	if l.Contains(V(4, 5)) {
+		t.Errorf("(4, 5) must not be in the polygon")
+	}
+
+	// A rectangle rotated by 45 degrees is a diamond.
+	d := R(-1, -1, 1, 1).Transform(rotation{math.Pi / 4})
+	if !d.Intersects(R(1.2, -0.1, 2, 0.1).Polygon()) {
+		t.Errorf("the diamond must intersect with the rectangle at the corner")
+	}
+	if d.IntersectsRect(R(1, 1, 2, 2)) {
+		t.Errorf("the diamond must not intersect with the rectangle")
+	}
+	if !R(1, 1, 2, 2).Polygon().Intersects(R(1.5, 1.5, 3, 3).Polygon()) {
+		t.Errorf("the overlapping rectangles must intersect")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	wall := R(10, -10, 11, 10)
+	cases := []struct {
+		Rect   Rect
+		V      Vec
+		Hit    bool
+		T      float64
+		Normal Vec
+	}{
+		// The object tunnels through the thin wall without swept tests.
+		{R(0, 0, 2, 2), V(20, 0), true, 0.4, V(-1, 0)},
+		{R(0, 0, 2, 2), V(4, 0), false, 0, V(0, 0)},
+		{R(0, 20, 2, 22), V(20, 0), false, 0, V(0, 0)},
+		{R(20, 0, 22, 2), V(-20, 0), true, 0.45, V(1, 0)},
+		{R(10, -20, 11, -18), V(0, 10), true, 0.8, V(0, -1)},
+		{R(9, 0, 12, 2), V(1, 0), true, 0, V(0, 0)},
+	}
+	for _, c := range cases {
+		tt, n, hit := Sweep(c.Rect, c.V, wall)
+		if hit != c.Hit {
+			t.Errorf("Sweep(%v, %v): hit: got: %v, want: %v", c.Rect, c.V, hit, c.Hit)
+			continue
+		}
+		if !hit {
+			continue
+		}
+		if math.Abs(tt-c.T) > 1e-9 || n != c.Normal {
+			t.Errorf("Sweep(%v, %v): got: (%v, %v), want: (%v, %v)", c.Rect, c.V, tt, n, c.T, c.Normal)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geom
+
+import (
+	"math"
+)
+
+type cellKey struct {
+	x int
+	y int
+}
+
+type cellRange struct {
+	min cellKey
+	max cellKey
+}
+
+type spatialEntry struct {
+	item  interface{}
+	rect  Rect
+	cells cellRange
+
+	// stamp is the last query ID to avoid reporting the same item twice in one query.
+	stamp uint64
+}
+
+// SpatialHash is a spatial index for broad-phase queries, which divides the space into square cells.
+//
+// An item can be any comparable value like a pointer to an entity. Each operation costs time in proportion to the
+// number of cells the rectangle covers, then choose the cell size around the typical size of items.
+//
+// SpatialHash is not concurrent-safe.
+type SpatialHash struct {
+	cellSize float64
+	cells    map[cellKey][]*spatialEntry
+	entries  map[interface{}]*spatialEntry
+	stamp    uint64
+}
+
+// NewSpatialHash returns a new SpatialHash with the given cell size.
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	if cellSize <= 0 {
+		panic("geom: cellSize must be positive")
+	}
+	return &SpatialHash{
+		cellSize: cellSize,
+		cells:    map[cellKey][]*spatialEntry{},
+		entries:  map[interface{}]*spatialEntry{},
+	}
+}
+
+func (s *SpatialHash) cellRangeOf(r Rect) cellRange {
+	return cellRange{
+		min: cellKey{int(math.Floor(r.Min.X / s.cellSize)), int(math.Floor(r.Min.Y / s.cellSize))},
+		max: cellKey{int(math.Floor(r.Max.X / s.cellSize)), int(math.Floor(r.Max.Y / s.cellSize))},
+	}
+}
+
+func (s *SpatialHash) addToCells(e *spatialEntry) {
+	for y := e.cells.min.y; y <= e.cells.max.y; y++ {
+		for x := e.cells.min.x; x <= e.cells.max.x; x++ {
+			k := cellKey{x, y}
+			s.cells[k] = append(s.cells[k], e)
+		}
+	}
+}
+
+func (s *SpatialHash) removeFromCells(e *spatialEntry) {
+	for y := e.cells.min.y; y <= e.cells.max.y; y++ {
+		for x := e.cells.min.x; x <= e.cells.max.x; x++ {
+			k := cellKey{x, y}
+			es := s.cells[k]
+			for i, e2 := range es {
+				if e2 != e {
+					continue
+				}
+				es[i] = es[len(es)-1]
+				es[len(es)-1] = nil
+				es = es[:len(es)-1]
+				break
+			}
+			if len(es) == 0 {
+				delete(s.cells, k)
+			} else {
+				s.cells[k] = es
+			}
+		}
+	}
+}
+
+// Insert inserts the item with the rectangle. If the item already exists, Insert works as Move.
+func (s *SpatialHash) Insert(item interface{}, r Rect) {
+	if _, ok := s.entries[item]; ok {
+		s.Move(item, r)
+		return
+	}
+	e := &spatialEntry{
+		item:  item,
+		rect:  r,
+		cells: s.cellRangeOf(r),
+	}
+	s.entries[item] = e
+	s.addToCells(e)
+}
+
+// Move updates the rectangle of the item. If the item doesn't exist, Move works as Insert.
+func (s *SpatialHash) Move(item interface{}, r Rect) {
+	e, ok := s.entries[item]
+	if !ok {
+		s.Insert(item, r)
+		return
+	}
+	e.rect = r
+	c := s.cellRangeOf(r)
+	if c == e.cells {
+		return
+	}
+	s.removeFromCells(e)
+	e.cells = c
+	s.addToCells(e)
+}
+
+// Remove removes the item. Remove does nothing if the item doesn't exist.
+func (s *SpatialHash) Remove(item interface{}) {
+	e, ok := s.entries[item]
+	if !ok {
+		return
+	}
+	s.removeFromCells(e)
+	delete(s.entries, item)
+}
+
+// Rect returns the rectangle of the item.
+func (s *SpatialHash) Rect(item interface{}) (Rect, bool) {
+	e, ok := s.entries[item]
+	if !ok {
+		return Rect{}, false
+	}
+	return e.rect, true
+}
+
+// Len returns the number of the items.
+func (s *SpatialHash) Len() int {
+	return len(s.entries)
+}
+
+// Query calls f for each item whose rectangle intersects with r. Each item is reported once.
+// If f returns false, Query stops the iteration.
+//
+// The order of the items is not specified. The index must not be modified in f.
+func (s *SpatialHash) Query(r Rect, f func(item interface{}, rect Rect) bool) {
+	s.stamp++
+	c := s.cellRangeOf(r)
+	for y := c.min.y; y <= c.max.y; y++ {
+		for x := c.min.x; x <= c.max.x; x++ {
+			for _, e := range s.cells[cellKey{x, y}] {
+				if e.stamp == s.stamp {
+					continue
+				}
+				e.stamp = s.stamp
+				if !e.rect.Intersects(r) {
+					continue
+				}
+				if !f(e.item, e.rect) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geom_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/ebitenutil/geom"
+)
+
+func query(s *SpatialHash, r Rect) []int {
+	var items []int
+	s.Query(r, func(item interface{}, rect Rect) bool {
+		items = append(items, item.(int))
+		return true
+	})
+	sort.Ints(items)
+	return items
+}
+
+func TestSpatialHash(t *testing.T) {
+	s := NewSpatialHash(10)
+	s.Insert(1, R(0, 0, 5, 5))
+	// Item 2 covers multiple cells.
+	s.Insert(2, R(8, 8, 25, 25))
+	s.Insert(3, R(-30, -30, -20, -20))
+
+	if got, want := query(s, R(0, 0, 30, 30)), []int{1, 2}; !equalInts(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if got, want := query(s, R(-25, -25, -24, -24)), []int{3}; !equalInts(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	// The query rectangle is in the same cell but doesn't intersect.
+	if got := query(s, R(6, 6, 7, 7)); len(got) != 0 {
+		t.Errorf("got: %v, want: []", got)
+	}
+
+	s.Move(1, R(100, 100, 105, 105))
+	if got, want := query(s, R(0, 0, 30, 30)), []int{2}; !equalInts(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if got, want := query(s, R(90, 90, 110, 110)), []int{1}; !equalInts(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	s.Remove(2)
+	if got, want := s.Len(), 2; got != want {
+		t.Errorf("Len(): got: %d, want: %d", got, want)
+	}
+	if got := query(s, R(0, 0, 30, 30)); len(got) != 0 {
+		t.Errorf("got: %v, want: []", got)
+	}
+}
+
+func TestSpatialHashRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randRect := func() Rect {
+		x, y := r.Float64()*1000, r.Float64()*1000
+		return R(x, y, x+r.Float64()*50, y+r.Float64()*50)
+	}
+
+	rects := map[int]Rect{}
+	s := NewSpatialHash(32)
+	for i := 0; i < 500; i++ {
+		rects[i] = randRect()
+		s.Insert(i, rects[i])
+	}
+	for i := 0; i < 250; i++ {
+		rects[i] = randRect()
+		s.Move(i, rects[i])
+	}
+
+	for i := 0; i < 100; i++ {
+		q := randRect()
+		var want []int
+		for id, rect := range rects {
+			if rect.Intersects(q) {
+				want = append(want, id)
+			}
+		}
+		sort.Ints(want)
+		if got := query(s, q); !equalInts(got, want) {
+			t.Errorf("Query(%v): got: %v, want: %v", q, got, want)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkSpatialHashMove(b *testing.B) {
+	const n = 50000
+	r := rand.New(rand.NewSource(1))
+	s := NewSpatialHash(32)
+	pos := make([]Vec, n)
+	for i := range pos {
+		pos[i] = V(r.Float64()*4096, r.Float64()*4096)
+		s.Insert(i, R(pos[i].X, pos[i].Y, pos[i].X+16, pos[i].Y+16))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		id := i % n
+		pos[id] = pos[id].Add(V(r.Float64()*8-4, r.Float64()*8-4))
+		s.Move(id, R(pos[id].X, pos[id].Y, pos[id].X+16, pos[id].Y+16))
+	}
+}
+
+func BenchmarkSpatialHashQuery(b *testing.B) {
+	const n = 50000
+	r := rand.New(rand.NewSource(1))
+	s := NewSpatialHash(32)
+	for i := 0; i < n; i++ {
+		x, y := r.Float64()*4096, r.Float64()*4096
+		s.Insert(i, R(x, y, x+16, y+16))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x, y := r.Float64()*4096, r.Float64()*4096
+		s.Query(R(x, y, x+64, y+64), func(item interface{}, rect Rect) bool {
+			return true
+		})
+	}
+}
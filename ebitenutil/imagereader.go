@@ -0,0 +1,132 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil/internal/imgdecode"
+)
+
+// NewImageFromReader decodes the image from r and returns ebiten.Image and image.Image.
+//
+// Image decoders must be imported when using NewImageFromReader. For example,
+// if you want to load a PNG image, you'd need to add `_ "image/png"` to the import section.
+//
+// The EXIF orientation of JPEG images is applied. If the image has an ICC profile of an RGB color space like
+// Display P3 or Adobe RGB, the colors are converted into sRGB. Other profiles like CMYK ones are ignored.
+func NewImageFromReader(r io.Reader, filter ebiten.Filter) (*ebiten.Image, image.Image, error) {
+	img, err := imgdecode.Decode(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	img2, err := ebiten.NewImageFromImage(img, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return img2, img, nil
+}
+
+// AsyncImage represents an image being decoded on a background worker.
+type AsyncImage struct {
+	filter ebiten.Filter
+
+	img  image.Image
+	eimg *ebiten.Image
+	err  error
+	done chan struct{}
+	once sync.Once
+}
+
+type decodeJob struct {
+	r   io.Reader
+	dst *AsyncImage
+}
+
+var (
+	decodeJobs     chan decodeJob
+	decodeJobsOnce sync.Once
+)
+
+func decodeWorker() {
+	for j := range decodeJobs {
+		j.dst.img, j.dst.err = imgdecode.Decode(j.r)
+		if c, ok := j.r.(io.Closer); ok {
+			if err := c.Close(); err != nil && j.dst.err == nil {
+				j.dst.err = err
+			}
+		}
+		close(j.dst.done)
+	}
+}
+
+// NewImageFromReaderAsync starts decoding the image from r on a background worker, and returns AsyncImage
+// immediately.
+//
+// The number of the workers is limited to the number of CPUs. The decoding is done in the same way as
+// NewImageFromReader.
+//
+// NewImageFromReaderAsync takes the ownership of r. If r is an io.Closer, r is closed after decoding.
+func NewImageFromReaderAsync(r io.Reader, filter ebiten.Filter) *AsyncImage {
+	decodeJobsOnce.Do(func() {
+		decodeJobs = make(chan decodeJob, 64)
+		for i := 0; i < runtime.NumCPU(); i++ {
+			go decodeWorker()
+		}
+	})
+
+	a := &AsyncImage{
+		filter: filter,
+		done:   make(chan struct{}),
+	}
+	// Sending a job might block when there are too many jobs. Send it on another goroutine not to block the caller.
+	go func() {
+		decodeJobs <- decodeJob{r: r, dst: a}
+	}()
+	return a
+}
+
+// IsDone reports whether the decoding is done.
+//
+// IsDone doesn't block.
+func (a *AsyncImage) IsDone() bool {
+	select {
+	case <-a.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Image returns ebiten.Image and image.Image of the decoded image.
+//
+// Image blocks until the decoding is done. Use IsDone not to block the game loop.
+func (a *AsyncImage) Image() (*ebiten.Image, image.Image, error) {
+	<-a.done
+	a.once.Do(func() {
+		if a.err != nil {
+			return
+		}
+		a.eimg, a.err = ebiten.NewImageFromImage(a.img, a.filter)
+	})
+	if a.err != nil {
+		return nil, nil, a.err
+	}
+	return a.eimg, a.img, nil
+}
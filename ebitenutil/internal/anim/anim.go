@@ -0,0 +1,62 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package anim provides decoders of animated images.
+package anim
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"time"
+)
+
+// Frame represents a frame of an animated image.
+type Frame struct {
+	// Image is the composited image of the frame. The size is the same as the whole animation.
+	Image *image.RGBA
+
+	// Delay is the duration for the frame to be displayed.
+	Delay time.Duration
+}
+
+// Animation represents a decoded animated image.
+type Animation struct {
+	Frames []Frame
+
+	// LoopCount is the number of times the animation is played. 0 means infinite.
+	LoopCount int
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Decode decodes an animated GIF or APNG image.
+//
+// A non-animated GIF or PNG image is decoded as an animation with one frame.
+func Decode(r io.Reader) (*Animation, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(8)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(head, []byte("GIF8")):
+		return DecodeGIF(br)
+	case bytes.HasPrefix(head, pngSignature):
+		return DecodeAPNG(br)
+	}
+	return nil, fmt.Errorf("anim: unknown format")
+}
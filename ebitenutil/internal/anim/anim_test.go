@@ -0,0 +1,188 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+)
+
+var (
+	red  = color.RGBA{0xff, 0, 0, 0xff}
+	blue = color.RGBA{0, 0, 0xff, 0xff}
+)
+
+func fill(img interface{ Set(x, y int, c color.Color) }, r image.Rectangle, clr color.Color) {
+	for j := r.Min.Y; j < r.Max.Y; j++ {
+		for i := r.Min.X; i < r.Max.X; i++ {
+			img.Set(i, j, clr)
+		}
+	}
+}
+
+func TestDecodeGIF(t *testing.T) {
+	bounds := image.Rect(0, 0, 4, 4)
+	f0 := image.NewPaletted(bounds, palette.Plan9)
+	fill(f0, bounds, red)
+	// The second frame covers only the top-left part.
+	f1 := image.NewPaletted(image.Rect(0, 0, 2, 2), palette.Plan9)
+	fill(f1, f1.Bounds(), blue)
+
+	var b bytes.Buffer
+	if err := gif.EncodeAll(&b, &gif.GIF{
+		Image:     []*image.Paletted{f0, f1},
+		Delay:     []int{5, 0},
+		Disposal:  []byte{gif.DisposalNone, gif.DisposalBackground},
+		LoopCount: 2,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(a.Frames), 2; got != want {
+		t.Fatalf("len(Frames): got: %d, want: %d", got, want)
+	}
+	if got, want := a.LoopCount, 3; got != want {
+		t.Errorf("LoopCount: got: %d, want: %d", got, want)
+	}
+	if got, want := a.Frames[0].Delay, 50*time.Millisecond; got != want {
+		t.Errorf("Frames[0].Delay: got: %v, want: %v", got, want)
+	}
+	if got, want := a.Frames[1].Delay, 100*time.Millisecond; got != want {
+		t.Errorf("Frames[1].Delay: got: %v, want: %v", got, want)
+	}
+	if got, want := a.Frames[1].Image.Bounds(), bounds; got != want {
+		t.Errorf("Frames[1].Image.Bounds(): got: %v, want: %v", got, want)
+	}
+	if got, want := a.Frames[1].Image.RGBAAt(0, 0), blue; got != want {
+		t.Errorf("Frames[1] at (0, 0): got: %v, want: %v", got, want)
+	}
+	// The part not covered by the second frame must be kept.
+	if got, want := a.Frames[1].Image.RGBAAt(3, 3), red; got != want {
+		t.Errorf("Frames[1] at (3, 3): got: %v, want: %v", got, want)
+	}
+}
+
+func encodePNG(t *testing.T, w, h int, clr color.Color) (ihdr []byte, idat []byte) {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	fill(img, img.Bounds(), clr)
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := readPNGChunks(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range chunks {
+		switch c.typ {
+		case "IHDR":
+			ihdr = c.data
+		case "IDAT":
+			idat = append(idat, c.data...)
+		}
+	}
+	return
+}
+
+func fcTL(seq uint32, r image.Rectangle, num, den uint16, disposeOp, blendOp byte) []byte {
+	b := make([]byte, 26)
+	binary.BigEndian.PutUint32(b[0:], seq)
+	binary.BigEndian.PutUint32(b[4:], uint32(r.Dx()))
+	binary.BigEndian.PutUint32(b[8:], uint32(r.Dy()))
+	binary.BigEndian.PutUint32(b[12:], uint32(r.Min.X))
+	binary.BigEndian.PutUint32(b[16:], uint32(r.Min.Y))
+	binary.BigEndian.PutUint16(b[20:], num)
+	binary.BigEndian.PutUint16(b[22:], den)
+	b[24] = disposeOp
+	b[25] = blendOp
+	return b
+}
+
+func TestDecodeAPNG(t *testing.T) {
+	ihdr, idat0 := encodePNG(t, 4, 4, red)
+	_, idat1 := encodePNG(t, 2, 2, blue)
+
+	var b bytes.Buffer
+	b.Write(pngSignature)
+	writePNGChunk(&b, "IHDR", ihdr)
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], 2)
+	binary.BigEndian.PutUint32(actl[4:], 0)
+	writePNGChunk(&b, "acTL", actl)
+	writePNGChunk(&b, "fcTL", fcTL(0, image.Rect(0, 0, 4, 4), 1, 10, apngDisposeOpNone, apngBlendOpSource))
+	writePNGChunk(&b, "IDAT", idat0)
+	writePNGChunk(&b, "fcTL", fcTL(1, image.Rect(2, 2, 4, 4), 0, 0, apngDisposeOpPrevious, apngBlendOpOver))
+	fdat := make([]byte, 4, 4+len(idat1))
+	binary.BigEndian.PutUint32(fdat, 2)
+	writePNGChunk(&b, "fdAT", append(fdat, idat1...))
+	writePNGChunk(&b, "IEND", nil)
+
+	a, err := Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(a.Frames), 2; got != want {
+		t.Fatalf("len(Frames): got: %d, want: %d", got, want)
+	}
+	if got, want := a.LoopCount, 0; got != want {
+		t.Errorf("LoopCount: got: %d, want: %d", got, want)
+	}
+	if got, want := a.Frames[0].Delay, 100*time.Millisecond; got != want {
+		t.Errorf("Frames[0].Delay: got: %v, want: %v", got, want)
+	}
+	if got, want := a.Frames[1].Delay, time.Duration(0); got != want {
+		t.Errorf("Frames[1].Delay: got: %v, want: %v", got, want)
+	}
+	if got, want := a.Frames[1].Image.RGBAAt(0, 0), red; got != want {
+		t.Errorf("Frames[1] at (0, 0): got: %v, want: %v", got, want)
+	}
+	if got, want := a.Frames[1].Image.RGBAAt(3, 3), blue; got != want {
+		t.Errorf("Frames[1] at (3, 3): got: %v, want: %v", got, want)
+	}
+	// Disposing the previous frame must not affect the already decoded frames.
+	if got, want := a.Frames[0].Image.RGBAAt(3, 3), red; got != want {
+		t.Errorf("Frames[0] at (3, 3): got: %v, want: %v", got, want)
+	}
+}
+
+func TestDecodePNG(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 3))
+	fill(img, img.Bounds(), blue)
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	a, err := Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(a.Frames), 1; got != want {
+		t.Fatalf("len(Frames): got: %d, want: %d", got, want)
+	}
+	if got, want := a.Frames[0].Image.RGBAAt(1, 1), blue; got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+const (
+	apngDisposeOpNone       = 0
+	apngDisposeOpBackground = 1
+	apngDisposeOpPrevious   = 2
+
+	apngBlendOpSource = 0
+	apngBlendOpOver   = 1
+)
+
+type apngFrame struct {
+	rect      image.Rectangle
+	delay     time.Duration
+	disposeOp byte
+	blendOp   byte
+	data      [][]byte
+}
+
+func readPNGChunks(r io.Reader) ([]pngChunk, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(buf, pngSignature) {
+		return nil, fmt.Errorf("anim: invalid PNG signature")
+	}
+	buf = buf[len(pngSignature):]
+
+	var chunks []pngChunk
+	for len(buf) >= 12 {
+		size := int(binary.BigEndian.Uint32(buf[0:4]))
+		if size < 0 || len(buf) < 12+size {
+			return nil, fmt.Errorf("anim: invalid PNG chunk")
+		}
+		c := pngChunk{
+			typ:  string(buf[4:8]),
+			data: buf[8 : 8+size],
+		}
+		chunks = append(chunks, c)
+		buf = buf[12+size:]
+		if c.typ == "IEND" {
+			break
+		}
+	}
+	return chunks, nil
+}
+
+func writePNGChunk(w *bytes.Buffer, typ string, data []byte) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(data)))
+	w.Write(buf[:])
+	h := crc32.NewIEEE()
+	h.Write([]byte(typ))
+	h.Write(data)
+	w.WriteString(typ)
+	w.Write(data)
+	binary.BigEndian.PutUint32(buf[:], h.Sum32())
+	w.Write(buf[:])
+}
+
+// DecodeAPNG decodes an APNG image.
+func DecodeAPNG(r io.Reader) (*Animation, error) {
+	chunks, err := readPNGChunks(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 || chunks[0].typ != "IHDR" || len(chunks[0].data) != 13 {
+		return nil, fmt.Errorf("anim: IHDR chunk not found")
+	}
+	ihdr := chunks[0].data
+	width := int(binary.BigEndian.Uint32(ihdr[0:4]))
+	height := int(binary.BigEndian.Uint32(ihdr[4:8]))
+
+	var (
+		// common is the chunks shared by all the frames, like PLTE or tRNS.
+		common   []pngChunk
+		frames   []*apngFrame
+		current  *apngFrame
+		animated bool
+		idatSeen bool
+		plays    int
+	)
+	for _, c := range chunks[1:] {
+		switch c.typ {
+		case "acTL":
+			if len(c.data) != 8 {
+				return nil, fmt.Errorf("anim: invalid acTL chunk")
+			}
+			animated = true
+			plays = int(binary.BigEndian.Uint32(c.data[4:8]))
+		case "fcTL":
+			if len(c.data) != 26 {
+				return nil, fmt.Errorf("anim: invalid fcTL chunk")
+			}
+			w := int(binary.BigEndian.Uint32(c.data[4:8]))
+			h := int(binary.BigEndian.Uint32(c.data[8:12]))
+			x := int(binary.BigEndian.Uint32(c.data[12:16]))
+			y := int(binary.BigEndian.Uint32(c.data[16:20]))
+			num := binary.BigEndian.Uint16(c.data[20:22])
+			den := binary.BigEndian.Uint16(c.data[22:24])
+			if den == 0 {
+				den = 100
+			}
+			rect := image.Rect(x, y, x+w, y+h)
+			if w <= 0 || h <= 0 || !rect.In(image.Rect(0, 0, width, height)) {
+				return nil, fmt.Errorf("anim: invalid frame region: %v", rect)
+			}
+			current = &apngFrame{
+				rect:      rect,
+				delay:     time.Duration(num) * time.Second / time.Duration(den),
+				disposeOp: c.data[24],
+				blendOp:   c.data[25],
+			}
+			frames = append(frames, current)
+		case "IDAT":
+			idatSeen = true
+			// IDAT is a part of the animation only when fcTL precedes IDAT.
+			if current != nil {
+				current.data = append(current.data, c.data)
+			}
+			if !animated {
+				if len(frames) == 0 {
+					frames = append(frames, &apngFrame{rect: image.Rect(0, 0, width, height)})
+				}
+				frames[0].data = append(frames[0].data, c.data)
+			}
+		case "fdAT":
+			if len(c.data) < 4 {
+				return nil, fmt.Errorf("anim: invalid fdAT chunk")
+			}
+			if current == nil {
+				return nil, fmt.Errorf("anim: fdAT chunk without fcTL chunk")
+			}
+			current.data = append(current.data, c.data[4:])
+		case "IEND":
+		default:
+			if !idatSeen {
+				common = append(common, c)
+			}
+		}
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	a := &Animation{
+		LoopCount: plays,
+	}
+	if !animated {
+		a.LoopCount = 1
+	}
+	for _, f := range frames {
+		if len(f.data) == 0 {
+			continue
+		}
+		img, err := decodeAPNGFrame(ihdr, common, f)
+		if err != nil {
+			return nil, err
+		}
+
+		var prev *image.RGBA
+		if f.disposeOp == apngDisposeOpPrevious {
+			prev = cloneRGBA(canvas)
+		}
+
+		op := draw.Over
+		if f.blendOp == apngBlendOpSource {
+			op = draw.Src
+		}
+		draw.Draw(canvas, f.rect, img, img.Bounds().Min, op)
+
+		a.Frames = append(a.Frames, Frame{
+			Image: cloneRGBA(canvas),
+			Delay: f.delay,
+		})
+
+		switch f.disposeOp {
+		case apngDisposeOpBackground:
+			draw.Draw(canvas, f.rect, image.Transparent, image.Point{}, draw.Src)
+		case apngDisposeOpPrevious:
+			canvas = prev
+		}
+	}
+	if len(a.Frames) == 0 {
+		return nil, fmt.Errorf("anim: no frames")
+	}
+	return a, nil
+}
+
+// decodeAPNGFrame decodes a frame by constructing a standalone PNG image.
+func decodeAPNGFrame(ihdr []byte, common []pngChunk, f *apngFrame) (image.Image, error) {
+	var b bytes.Buffer
+	b.Write(pngSignature)
+
+	h := make([]byte, len(ihdr))
+	copy(h, ihdr)
+	binary.BigEndian.PutUint32(h[0:4], uint32(f.rect.Dx()))
+	binary.BigEndian.PutUint32(h[4:8], uint32(f.rect.Dy()))
+	writePNGChunk(&b, "IHDR", h)
+
+	for _, c := range common {
+		writePNGChunk(&b, c.typ, c.data)
+	}
+	for _, d := range f.data {
+		writePNGChunk(&b, "IDAT", d)
+	}
+	writePNGChunk(&b, "IEND", nil)
+
+	return png.Decode(&b)
+}
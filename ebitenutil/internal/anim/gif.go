@@ -0,0 +1,85 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anim
+
+import (
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// DecodeGIF decodes an animated GIF image.
+func DecodeGIF(r io.Reader) (*Animation, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := image.NewRGBA(bounds)
+	var prev *image.RGBA
+
+	a := &Animation{}
+	for i, img := range g.Image {
+		disposal := byte(gif.DisposalNone)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			prev = cloneRGBA(canvas)
+		}
+
+		draw.Draw(canvas, img.Bounds(), img, img.Bounds().Min, draw.Over)
+
+		delay := 0
+		if i < len(g.Delay) {
+			delay = g.Delay[i]
+		}
+		// Most viewers treat a too short delay as 100ms.
+		if delay <= 1 {
+			delay = 10
+		}
+		a.Frames = append(a.Frames, Frame{
+			Image: cloneRGBA(canvas),
+			Delay: time.Duration(delay) * 10 * time.Millisecond,
+		})
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, img.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = prev
+		}
+	}
+
+	// LoopCount of image/gif is the number of times the animation is restarted.
+	switch {
+	case g.LoopCount == 0:
+		a.LoopCount = 0
+	case g.LoopCount < 0:
+		a.LoopCount = 1
+	default:
+		a.LoopCount = g.LoopCount + 1
+	}
+	return a, nil
+}
+
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	r := image.NewRGBA(img.Bounds())
+	copy(r.Pix, img.Pix)
+	return r
+}
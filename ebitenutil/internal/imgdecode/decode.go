@@ -0,0 +1,107 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imgdecode provides an image decoder that handles the metadata like EXIF orientation and ICC profiles.
+package imgdecode
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"io"
+	"io/ioutil"
+)
+
+// Decode decodes an image with the registered decoders.
+//
+// If the image is JPEG, the EXIF orientation is applied. If the image has an ICC profile of a matrix/TRC-based RGB
+// color space, the colors are converted into sRGB.
+func Decode(r io.Reader) (image.Image, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	orientation := 1
+	var profile []byte
+	switch format {
+	case "jpeg":
+		orientation = jpegOrientation(data)
+		profile = jpegICCProfile(data)
+	case "png":
+		profile = pngICCProfile(data)
+	}
+
+	if profile != nil {
+		if p, ok := parseICCProfile(profile); ok && !p.isSRGB() {
+			dst := toNRGBA(img)
+			p.convertToSRGB(dst)
+			img = dst
+		}
+	}
+	if orientation != 1 {
+		img = applyOrientation(img, orientation)
+	}
+	return img, nil
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// applyOrientation returns a new image transformed by the EXIF orientation value.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	src := toNRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		// The orientations 5-8 swap the width and the height.
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+	return dst
+}
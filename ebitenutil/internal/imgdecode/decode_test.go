@@ -0,0 +1,195 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imgdecode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func near(c0, c1 color.Color) bool {
+	r0, g0, b0, _ := c0.RGBA()
+	r1, g1, b1, _ := c1.RGBA()
+	const d = 8
+	return abs(int(r0>>8)-int(r1>>8)) <= d && abs(int(g0>>8)-int(g1>>8)) <= d && abs(int(b0>>8)-int(b1>>8)) <= d
+}
+
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = append(tiff, 0, 1)
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], 0x0112)
+	binary.BigEndian.PutUint16(entry[2:], 3)
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	body := append([]byte("Exif\x00\x00"), tiff...)
+	seg := []byte{0xff, 0xe1, byte((len(body) + 2) >> 8), byte(len(body) + 2)}
+	return append(seg, body...)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+
+	// The left half is red and the right half is blue.
+	src := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 32; i++ {
+			if i < 16 {
+				src.Set(i, j, red)
+			} else {
+				src.Set(i, j, blue)
+			}
+		}
+	}
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, src, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		orientation uint16
+		size        image.Point
+		redAt       image.Point
+		blueAt      image.Point
+	}{
+		{1, image.Pt(32, 16), image.Pt(4, 8), image.Pt(28, 8)},
+		{2, image.Pt(32, 16), image.Pt(28, 8), image.Pt(4, 8)},
+		{3, image.Pt(32, 16), image.Pt(28, 8), image.Pt(4, 8)},
+		{6, image.Pt(16, 32), image.Pt(8, 4), image.Pt(8, 28)},
+		{8, image.Pt(16, 32), image.Pt(8, 28), image.Pt(8, 4)},
+	}
+	for _, c := range cases {
+		data := append([]byte{0xff, 0xd8}, exifSegment(c.orientation)...)
+		data = append(data, b.Bytes()[2:]...)
+
+		img, err := Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := img.Bounds().Size(), c.size; got != want {
+			t.Errorf("orientation %d: size: got: %v, want: %v", c.orientation, got, want)
+			continue
+		}
+		if got := img.At(c.redAt.X, c.redAt.Y); !near(got, red) {
+			t.Errorf("orientation %d: at %v: got: %v, want: %v", c.orientation, c.redAt, got, red)
+		}
+		if got := img.At(c.blueAt.X, c.blueAt.Y); !near(got, blue) {
+			t.Errorf("orientation %d: at %v: got: %v, want: %v", c.orientation, c.blueAt, got, blue)
+		}
+	}
+}
+
+// linearSRGBProfile returns an ICC profile with the sRGB primaries and the linear tone curves.
+func linearSRGBProfile() []byte {
+	var tags [][]byte
+	var names []string
+	for i, n := range []string{"r", "g", "b"} {
+		xyz := []byte("XYZ \x00\x00\x00\x00")
+		for j := 0; j < 3; j++ {
+			xyz = append(xyz, make([]byte, 4)...)
+			binary.BigEndian.PutUint32(xyz[len(xyz)-4:], uint32(int32(srgbMatrix[j][i]*65536)))
+		}
+		tags = append(tags, xyz)
+		names = append(names, n+"XYZ")
+
+		// A gamma of 1.0 in u8Fixed8.
+		tags = append(tags, []byte("curv\x00\x00\x00\x00\x00\x00\x00\x01\x01\x00"))
+		names = append(names, n+"TRC")
+	}
+
+	header := make([]byte, 128)
+	copy(header[16:], "RGB XYZ ")
+	table := make([]byte, 4+12*len(tags))
+	binary.BigEndian.PutUint32(table, uint32(len(tags)))
+	offset := len(header) + len(table)
+	var data []byte
+	for i, tag := range tags {
+		e := table[4+12*i:]
+		copy(e, names[i])
+		binary.BigEndian.PutUint32(e[4:], uint32(offset+len(data)))
+		binary.BigEndian.PutUint32(e[8:], uint32(len(tag)))
+		data = append(data, tag...)
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+	}
+	p := append(header, table...)
+	return append(p, data...)
+}
+
+func pngChunk(typ string, data []byte) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(len(data)))
+	b = append(b, typ...)
+	b = append(b, data...)
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32.ChecksumIEEE(append([]byte(typ), data...)))
+	return append(b, crc...)
+}
+
+func TestPNGICCProfile(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for i := 0; i < 4; i++ {
+		copy(src.Pix[i*4:], []byte{0x80, 0x80, 0x80, 0xff})
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, src); err != nil {
+		t.Fatal(err)
+	}
+
+	var z bytes.Buffer
+	w := zlib.NewWriter(&z)
+	w.Write(linearSRGBProfile())
+	w.Close()
+	iccp := append([]byte("linear\x00\x00"), z.Bytes()...)
+
+	// Insert iCCP after IHDR.
+	orig := b.Bytes()
+	const ihdrEnd = 8 + 12 + 13
+	data := append([]byte{}, orig[:ihdrEnd]...)
+	data = append(data, pngChunk("iCCP", iccp)...)
+	data = append(data, orig[ihdrEnd:]...)
+
+	img, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The linear value 0x80 is 0xbc in sRGB.
+	want := color.NRGBA{0xbc, 0xbc, 0xbc, 0xff}
+	if got := img.At(0, 0); !near(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if got := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); abs(int(got.R)-0xbc) > 1 {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
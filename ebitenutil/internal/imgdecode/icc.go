@@ -0,0 +1,261 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imgdecode
+
+import (
+	"encoding/binary"
+	"image"
+	"math"
+)
+
+// curve is a tone reproduction curve that converts an encoded value into a linear value.
+type curve func(x float64) float64
+
+// iccProfile represents a matrix/TRC-based RGB ICC profile.
+type iccProfile struct {
+	// matrix converts linear RGB values into XYZ values of the D50 PCS. The columns are rXYZ, gXYZ and bXYZ.
+	matrix [3][3]float64
+	trcs   [3]curve
+}
+
+// srgbMatrix is the matrix of the sRGB color space adapted to D50.
+var srgbMatrix = [3][3]float64{
+	{0.4360747, 0.3850649, 0.1430804},
+	{0.2225045, 0.7168786, 0.0606169},
+	{0.0139322, 0.0971045, 0.7141733},
+}
+
+func s15Fixed16(b []byte) float64 {
+	return float64(int32(binary.BigEndian.Uint32(b))) / 65536
+}
+
+// parseICCProfile parses an ICC profile. parseICCProfile returns false if the profile is not a matrix/TRC-based RGB
+// profile.
+func parseICCProfile(data []byte) (*iccProfile, bool) {
+	if len(data) < 132 {
+		return nil, false
+	}
+	if string(data[16:20]) != "RGB " || string(data[20:24]) != "XYZ " {
+		return nil, false
+	}
+
+	tags := map[string][]byte{}
+	n := int(binary.BigEndian.Uint32(data[128:132]))
+	for i := 0; i < n; i++ {
+		e := 132 + i*12
+		if e+12 > len(data) {
+			return nil, false
+		}
+		offset := int(binary.BigEndian.Uint32(data[e+4 : e+8]))
+		size := int(binary.BigEndian.Uint32(data[e+8 : e+12]))
+		if offset < 0 || size < 0 || offset+size > len(data) {
+			return nil, false
+		}
+		tags[string(data[e:e+4])] = data[offset : offset+size]
+	}
+
+	p := &iccProfile{}
+	for i, name := range []string{"r", "g", "b"} {
+		xyz, ok := tags[name+"XYZ"]
+		if !ok || len(xyz) < 20 || string(xyz[:4]) != "XYZ " {
+			return nil, false
+		}
+		for j := 0; j < 3; j++ {
+			p.matrix[j][i] = s15Fixed16(xyz[8+j*4:])
+		}
+		trc, ok := parseCurve(tags[name+"TRC"])
+		if !ok {
+			return nil, false
+		}
+		p.trcs[i] = trc
+	}
+	return p, true
+}
+
+func parseCurve(data []byte) (curve, bool) {
+	if len(data) < 12 {
+		return nil, false
+	}
+	switch string(data[:4]) {
+	case "curv":
+		n := int(binary.BigEndian.Uint32(data[8:12]))
+		if len(data) < 12+n*2 {
+			return nil, false
+		}
+		switch n {
+		case 0:
+			return func(x float64) float64 { return x }, true
+		case 1:
+			g := float64(binary.BigEndian.Uint16(data[12:14])) / 256
+			return func(x float64) float64 { return math.Pow(x, g) }, true
+		}
+		table := make([]float64, n)
+		for i := range table {
+			table[i] = float64(binary.BigEndian.Uint16(data[12+i*2:])) / 65535
+		}
+		return func(x float64) float64 {
+			f := x * float64(n-1)
+			i := int(f)
+			if i >= n-1 {
+				return table[n-1]
+			}
+			t := f - float64(i)
+			return table[i]*(1-t) + table[i+1]*t
+		}, true
+	case "para":
+		typ := binary.BigEndian.Uint16(data[8:10])
+		num := []int{1, 3, 4, 5, 7}
+		if int(typ) >= len(num) || len(data) < 12+num[typ]*4 {
+			return nil, false
+		}
+		ps := make([]float64, 7)
+		for i := 0; i < num[typ]; i++ {
+			ps[i] = s15Fixed16(data[12+i*4:])
+		}
+		g, a, b, c, d, e, f := ps[0], ps[1], ps[2], ps[3], ps[4], ps[5], ps[6]
+		switch typ {
+		case 0:
+			return func(x float64) float64 { return math.Pow(x, g) }, true
+		case 1:
+			return func(x float64) float64 {
+				if x >= -b/a {
+					return math.Pow(a*x+b, g)
+				}
+				return 0
+			}, true
+		case 2:
+			return func(x float64) float64 {
+				if x >= -b/a {
+					return math.Pow(a*x+b, g) + c
+				}
+				return c
+			}, true
+		case 3:
+			return func(x float64) float64 {
+				if x >= d {
+					return math.Pow(a*x+b, g)
+				}
+				return c * x
+			}, true
+		case 4:
+			return func(x float64) float64 {
+				if x >= d {
+					return math.Pow(a*x+b, g) + e
+				}
+				return c*x + f
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// isSRGB reports whether the profile is close enough to sRGB so that the conversion can be skipped.
+func (p *iccProfile) isSRGB() bool {
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			if math.Abs(p.matrix[j][i]-srgbMatrix[j][i]) > 1e-3 {
+				return false
+			}
+		}
+	}
+	for _, trc := range p.trcs {
+		for _, x := range []float64{0.1, 0.25, 0.5, 0.75, 1} {
+			if math.Abs(trc(x)-decodeSRGB(x)) > 1e-2 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func invert(m [3][3]float64) [3][3]float64 {
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+	var r [3][3]float64
+	r[0][0] = (m[1][1]*m[2][2] - m[1][2]*m[2][1]) / det
+	r[0][1] = (m[0][2]*m[2][1] - m[0][1]*m[2][2]) / det
+	r[0][2] = (m[0][1]*m[1][2] - m[0][2]*m[1][1]) / det
+	r[1][0] = (m[1][2]*m[2][0] - m[1][0]*m[2][2]) / det
+	r[1][1] = (m[0][0]*m[2][2] - m[0][2]*m[2][0]) / det
+	r[1][2] = (m[0][2]*m[1][0] - m[0][0]*m[1][2]) / det
+	r[2][0] = (m[1][0]*m[2][1] - m[1][1]*m[2][0]) / det
+	r[2][1] = (m[0][1]*m[2][0] - m[0][0]*m[2][1]) / det
+	r[2][2] = (m[0][0]*m[1][1] - m[0][1]*m[1][0]) / det
+	return r
+}
+
+func mul(a, b [3][3]float64) [3][3]float64 {
+	var r [3][3]float64
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			for k := 0; k < 3; k++ {
+				r[j][i] += a[j][k] * b[k][i]
+			}
+		}
+	}
+	return r
+}
+
+func decodeSRGB(x float64) float64 {
+	if x <= 0.04045 {
+		return x / 12.92
+	}
+	return math.Pow((x+0.055)/1.055, 2.4)
+}
+
+func encodeSRGB(x float64) float64 {
+	if x <= 0.0031308 {
+		return 12.92 * x
+	}
+	return 1.055*math.Pow(x, 1/2.4) - 0.055
+}
+
+// convertToSRGB converts the colors of img from the profile's color space into sRGB in place.
+func (p *iccProfile) convertToSRGB(img *image.NRGBA) {
+	// Linearize the 8-bit values with the lookup tables.
+	var lin [3][256]float64
+	for c := 0; c < 3; c++ {
+		for i := 0; i < 256; i++ {
+			lin[c][i] = p.trcs[c](float64(i) / 255)
+		}
+	}
+
+	const outN = 4096
+	var out [outN + 1]uint8
+	for i := range out {
+		out[i] = uint8(math.Round(encodeSRGB(float64(i)/outN) * 255))
+	}
+
+	m := mul(invert(srgbMatrix), p.matrix)
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			s := img.Pix[i : i+3 : i+3]
+			r, g, bl := lin[0][s[0]], lin[1][s[1]], lin[2][s[2]]
+			for c := 0; c < 3; c++ {
+				v := m[c][0]*r + m[c][1]*g + m[c][2]*bl
+				if v < 0 {
+					v = 0
+				}
+				if v > 1 {
+					v = 1
+				}
+				s[c] = out[int(v*outN+0.5)]
+			}
+		}
+	}
+}
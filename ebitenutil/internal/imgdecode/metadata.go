@@ -0,0 +1,170 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imgdecode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io/ioutil"
+	"sort"
+)
+
+// jpegSegments calls f for each marker segment before the SOS marker.
+func jpegSegments(data []byte, f func(marker byte, body []byte)) {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return
+	}
+	for i := 2; i+4 <= len(data) && data[i] == 0xff; {
+		marker := data[i+1]
+		if marker == 0xda {
+			return
+		}
+		next := i + 2 + (int(data[i+2])<<8 | int(data[i+3]))
+		if next > len(data) {
+			return
+		}
+		f(marker, data[i+4:next])
+		i = next
+	}
+}
+
+// jpegOrientation returns the EXIF orientation value. jpegOrientation returns 1 when the value is not found.
+func jpegOrientation(data []byte) int {
+	orientation := 1
+	jpegSegments(data, func(marker byte, body []byte) {
+		if marker != 0xe1 || !bytes.HasPrefix(body, []byte("Exif\x00\x00")) {
+			return
+		}
+		if o, ok := exifOrientation(body[6:]); ok {
+			orientation = o
+		}
+	})
+	return orientation
+}
+
+// exifOrientation returns the orientation value from the TIFF structure of EXIF.
+func exifOrientation(tiff []byte) (int, bool) {
+	if len(tiff) < 8 {
+		return 0, false
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return 0, false
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 0 || ifd+2 > len(tiff) {
+		return 0, false
+	}
+	n := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < n; i++ {
+		e := ifd + 2 + i*12
+		if e+12 > len(tiff) {
+			return 0, false
+		}
+		const (
+			tagOrientation = 0x0112
+			typeShort      = 3
+		)
+		if order.Uint16(tiff[e:e+2]) != tagOrientation {
+			continue
+		}
+		if order.Uint16(tiff[e+2:e+4]) != typeShort {
+			return 0, false
+		}
+		return int(order.Uint16(tiff[e+8 : e+10])), true
+	}
+	return 0, false
+}
+
+// jpegICCProfile returns the ICC profile embedded in the APP2 segments, or nil if not found.
+func jpegICCProfile(data []byte) []byte {
+	type chunk struct {
+		seq  int
+		data []byte
+	}
+	var chunks []chunk
+	jpegSegments(data, func(marker byte, body []byte) {
+		const sig = "ICC_PROFILE\x00"
+		if marker != 0xe2 || !bytes.HasPrefix(body, []byte(sig)) || len(body) < len(sig)+2 {
+			return
+		}
+		chunks = append(chunks, chunk{
+			seq:  int(body[len(sig)]),
+			data: body[len(sig)+2:],
+		})
+	})
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	// A profile can be split into multiple segments with sequence numbers.
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].seq < chunks[j].seq
+	})
+	var p []byte
+	for _, c := range chunks {
+		p = append(p, c.data...)
+	}
+	return p
+}
+
+// pngICCProfile returns the ICC profile in the iCCP chunk, or nil if not found.
+func pngICCProfile(data []byte) []byte {
+	const sig = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(data, []byte(sig)) {
+		return nil
+	}
+	data = data[len(sig):]
+	for len(data) >= 12 {
+		size := int(binary.BigEndian.Uint32(data[0:4]))
+		if size < 0 || len(data) < 12+size {
+			return nil
+		}
+		typ := string(data[4:8])
+		body := data[8 : 8+size]
+		data = data[12+size:]
+
+		switch typ {
+		case "iCCP":
+			// The profile name is null-terminated, and followed by the compression method.
+			i := bytes.IndexByte(body, 0)
+			if i < 0 || i+2 > len(body) || body[i+1] != 0 {
+				return nil
+			}
+			r, err := zlib.NewReader(bytes.NewReader(body[i+2:]))
+			if err != nil {
+				return nil
+			}
+			p, err := ioutil.ReadAll(r)
+			if err != nil {
+				return nil
+			}
+			return p
+		case "IDAT", "IEND":
+			return nil
+		}
+	}
+	return nil
+}
@@ -29,6 +29,8 @@ import (
 // Image decoders must be imported when using NewImageFromFile. For example,
 // if you want to load a PNG image, you'd need to add `_ "image/png"` to the import section.
 //
+// The EXIF orientation and the ICC profile are handled in the same way as NewImageFromReader.
+//
 // How to solve path depends on your environment. This varies on your desktop or web browser.
 // Note that this doesn't work on mobiles.
 //
@@ -41,13 +43,5 @@ func NewImageFromFile(path string, filter ebiten.Filter) (*ebiten.Image, image.I
 	defer func() {
 		_ = file.Close()
 	}()
-	img, _, err := image.Decode(file)
-	if err != nil {
-		return nil, nil, err
-	}
-	img2, err := ebiten.NewImageFromImage(img, filter)
-	if err != nil {
-		return nil, nil, err
-	}
-	return img2, img, err
+	return NewImageFromReader(file, filter)
 }
@@ -0,0 +1,136 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Minimap is a secondary view that renders the world at a reduced scale, like a minimap or a rear-view mirror.
+//
+// The world is rendered into an offscreen image once every interval ticks and the cached image is drawn in the
+// other frames, so drawing a minimap costs little even when the world is large.
+type Minimap struct {
+	width    int
+	height   int
+	scale    float64
+	interval int
+	draw     func(dst *ebiten.Image, geoM ebiten.GeoM)
+
+	offscreen *ebiten.Image
+	tick      int
+	dirty     bool
+
+	// OffsetX and OffsetY are the position in the world shown at the upper-left corner of the minimap.
+	OffsetX float64
+	OffsetY float64
+
+	// BackgroundColor is the color to fill the minimap with before rendering the world.
+	// The default (nil) value means transparent.
+	BackgroundColor color.Color
+
+	// FrameColor is the color of the frame around the minimap.
+	// The default (nil) value means no frame.
+	FrameColor color.Color
+
+	// FrameWidth is the width of the frame in pixels.
+	FrameWidth int
+}
+
+// NewMinimap creates a new Minimap with the given size in pixels.
+//
+// scale is the scale of the world in the minimap, e.g., 0.1 shows the world at 1/10.
+// draw is called to render the world into the offscreen of the minimap once every interval ticks.
+// draw must apply the given geoM to the GeoMs of all the images it draws.
+// If interval is 1 or less, the minimap is rendered every frame.
+func NewMinimap(width, height int, scale float64, interval int, draw func(dst *ebiten.Image, geoM ebiten.GeoM)) *Minimap {
+	if width <= 0 || height <= 0 {
+		panic("ebitenutil: width and height must be positive")
+	}
+	if interval < 1 {
+		interval = 1
+	}
+	return &Minimap{
+		width:    width,
+		height:   height,
+		scale:    scale,
+		interval: interval,
+		draw:     draw,
+		dirty:    true,
+	}
+}
+
+// Update proceeds the minimap by one tick. Update schedules re-rendering once every interval ticks.
+func (m *Minimap) Update() {
+	m.tick++
+	if m.tick >= m.interval {
+		m.tick = 0
+		m.dirty = true
+	}
+}
+
+// Invalidate schedules re-rendering at the next Draw regardless of the interval.
+func (m *Minimap) Invalidate() {
+	m.dirty = true
+}
+
+func (m *Minimap) render() {
+	if m.offscreen == nil {
+		m.offscreen, _ = ebiten.NewImage(m.width, m.height, ebiten.FilterLinear)
+	}
+	if m.BackgroundColor != nil {
+		_ = m.offscreen.Fill(m.BackgroundColor)
+	} else {
+		_ = m.offscreen.Clear()
+	}
+
+	var g ebiten.GeoM
+	g.Translate(-m.OffsetX, -m.OffsetY)
+	g.Scale(m.scale, m.scale)
+	m.draw(m.offscreen, g)
+	m.dirty = false
+}
+
+// Draw draws the minimap at (x, y) on dst. The minimap is re-rendered before drawing if needed.
+func (m *Minimap) Draw(dst *ebiten.Image, x, y float64) {
+	// Render the offscreen first so that the draw calls to dst are not interrupted and can be batched.
+	if m.dirty || m.offscreen == nil {
+		m.render()
+	}
+
+	if m.FrameColor != nil && m.FrameWidth > 0 {
+		fw := float64(m.FrameWidth)
+		w, h := float64(m.width), float64(m.height)
+		DrawRect(dst, x-fw, y-fw, w+2*fw, fw, m.FrameColor)
+		DrawRect(dst, x-fw, y+h, w+2*fw, fw, m.FrameColor)
+		DrawRect(dst, x-fw, y, fw, h, m.FrameColor)
+		DrawRect(dst, x+w, y, fw, h, m.FrameColor)
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(x, y)
+	_ = dst.DrawImage(m.offscreen, op)
+}
+
+// Dispose disposes the offscreen of the minimap. The minimap can still be used after Dispose, and the offscreen is
+// created again at the next Draw.
+func (m *Minimap) Dispose() {
+	if m.offscreen == nil {
+		return
+	}
+	_ = m.offscreen.Dispose()
+	m.offscreen = nil
+}
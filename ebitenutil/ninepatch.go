@@ -0,0 +1,86 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"github.com/hajimehoshi/ebiten"
+)
+
+// DrawNinePatch draws the source image src on the rectangle (x, y, width, height) of the destination dst as a
+// nine-patch, which is also known as 9-slice scaling.
+//
+// The source image is sliced into nine parts by the insets left, top, right and bottom in pixels. The four corners
+// keep their sizes, the four edges are stretched along the edge, and the center is stretched in both directions.
+// If the rectangle is smaller than the corners, the corners are shrunk to fit.
+//
+// The nine parts are drawn with one DrawTriangles call, so that the draw calls are batched. src can be a sub-image,
+// e.g., a part of a texture atlas.
+//
+// op can be nil.
+func DrawNinePatch(dst, src *ebiten.Image, left, top, right, bottom int, x, y, width, height float64, op *ebiten.DrawTrianglesOptions) {
+	b := src.Bounds()
+	if left < 0 || top < 0 || right < 0 || bottom < 0 || left+right > b.Dx() || top+bottom > b.Dy() {
+		panic("ebitenutil: the insets must be non-negative and within the source image at DrawNinePatch")
+	}
+
+	sxs := [4]float32{float32(b.Min.X), float32(b.Min.X + left), float32(b.Max.X - right), float32(b.Max.X)}
+	sys := [4]float32{float32(b.Min.Y), float32(b.Min.Y + top), float32(b.Max.Y - bottom), float32(b.Max.Y)}
+	dxs := ninePatchPositions(x, width, float64(left), float64(right))
+	dys := ninePatchPositions(y, height, float64(top), float64(bottom))
+
+	vs := make([]ebiten.Vertex, 0, 16)
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			vs = append(vs, ebiten.Vertex{
+				DstX:   dxs[i],
+				DstY:   dys[j],
+				SrcX:   sxs[i],
+				SrcY:   sys[j],
+				ColorR: 1,
+				ColorG: 1,
+				ColorB: 1,
+				ColorA: 1,
+			})
+		}
+	}
+
+	is := make([]uint16, 0, 54)
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			// Skip the empty parts, e.g., the edges when the insets are 0.
+			if sxs[i] == sxs[i+1] || sys[j] == sys[j+1] {
+				continue
+			}
+			n := uint16(j*4 + i)
+			is = append(is, n, n+1, n+4, n+1, n+4, n+5)
+		}
+	}
+
+	dst.DrawTriangles(vs, is, src, op)
+}
+
+// ninePatchPositions returns the destination positions of the slices along one axis.
+func ninePatchPositions(pos, size, inset0, inset1 float64) [4]float32 {
+	if s := inset0 + inset1; s > size && s > 0 {
+		inset0 = inset0 * size / s
+		inset1 = inset1 * size / s
+	}
+	return [4]float32{
+		float32(pos),
+		float32(pos + inset0),
+		float32(pos + size - inset1),
+		float32(pos + size),
+	}
+}
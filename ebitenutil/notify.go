@@ -0,0 +1,26 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+// Notify shows a desktop notification with the given title and body, e.g., to tell that a long task is finished.
+//
+// Notify doesn't wait for the notification to be shown. Notify does nothing and returns nil when notifications are
+// not available on the environment, e.g., on mobiles or on Linux without notify-send.
+//
+// On browsers, the user is asked for the permission at the first call. Browsers might ignore the request unless
+// Notify is called in a user interaction.
+func Notify(title, body string) error {
+	return notify(title, body)
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"syscall/js"
+
+	"github.com/hajimehoshi/ebiten/internal/jsutil"
+)
+
+func notify(title, body string) error {
+	n := js.Global().Get("Notification")
+	if jsutil.Equal(n, js.Undefined()) {
+		return nil
+	}
+
+	show := func() {
+		n.New(title, map[string]interface{}{
+			"body": body,
+		})
+	}
+
+	switch n.Get("permission").String() {
+	case "granted":
+		show()
+	case "default":
+		// Don't wait for the result as Notify might be called in the game loop. The callback form is used rather than
+		// the promise, since old Safari doesn't return a promise.
+		var f js.Func
+		f = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			defer f.Release()
+			if len(args) > 0 && args[0].String() == "granted" {
+				show()
+			}
+			return nil
+		})
+		n.Call("requestPermission", f)
+	}
+	return nil
+}
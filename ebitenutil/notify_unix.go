@@ -0,0 +1,37 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build dragonfly freebsd linux netbsd openbsd solaris
+// +build !js
+// +build !android
+
+package ebitenutil
+
+import (
+	"os/exec"
+)
+
+func notify(title, body string) error {
+	// notify-send is the command of libnotify, that is available on most desktop environments.
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return nil
+	}
+	// "--" is needed so that a title starting with "-" is not treated as an option.
+	cmd := exec.Command("notify-send", "--", title, body)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
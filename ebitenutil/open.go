@@ -0,0 +1,54 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+)
+
+// OpenURL opens the given URL with the default browser or the default application for the scheme.
+//
+// Only the http, https and mailto schemes are allowed to avoid executing arbitrary files.
+//
+// On browsers, OpenURL opens the URL in a new tab. Browsers might block this unless OpenURL is called in a
+// user interaction.
+//
+// OpenURL returns an error on mobiles.
+func OpenURL(rawurl string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return fmt.Errorf("ebitenutil: invalid URL: %v", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "mailto":
+	default:
+		return fmt.Errorf("ebitenutil: unsupported URL scheme: %q", u.Scheme)
+	}
+	return openURL(u.String())
+}
+
+// RevealFile shows the given file or directory in the file manager, e.g., to show the folder of save data.
+// The file is selected if the file manager supports it.
+//
+// RevealFile returns an error on browsers and mobiles.
+func RevealFile(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("ebitenutil: invalid path: %v", err)
+	}
+	return revealFile(abs)
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"errors"
+	"syscall/js"
+
+	"github.com/hajimehoshi/ebiten/internal/jsutil"
+)
+
+func openURL(url string) error {
+	if jsutil.Equal(js.Global().Get("open"), js.Undefined()) {
+		return errors.New("ebitenutil: window.open is not available")
+	}
+	// window.open returns null when noopener is specified, then the result is not checked.
+	js.Global().Call("open", url, "_blank", "noopener")
+	return nil
+}
+
+func revealFile(path string) error {
+	return errors.New("ebitenutil: RevealFile is not supported on browsers")
+}
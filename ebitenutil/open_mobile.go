@@ -0,0 +1,30 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build android ios
+
+package ebitenutil
+
+import (
+	"errors"
+)
+
+func openURL(url string) error {
+	// TODO: Implement this with Intent.ACTION_VIEW on Android and UIApplication on iOS.
+	return errors.New("ebitenutil: OpenURL is not supported on mobiles yet")
+}
+
+func revealFile(path string) error {
+	return errors.New("ebitenutil: RevealFile is not supported on mobiles")
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build dragonfly freebsd linux netbsd openbsd solaris
+// +build !js
+// +build !android
+
+package ebitenutil
+
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// start starts the command without waiting for its end.
+//
+// xdg-open might not end until the launched application ends. The command is waited in background so that the
+// process doesn't remain as a zombie.
+func start(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func openURL(url string) error {
+	return start(exec.Command("xdg-open", url))
+}
+
+func revealFile(path string) error {
+	u := (&url.URL{Scheme: "file", Path: path}).String()
+
+	// Try the file manager interface of freedesktop.org first, which can select the file.
+	if err := exec.Command("dbus-send", "--session", "--print-reply",
+		"--dest=org.freedesktop.FileManager1",
+		"--type=method_call",
+		"/org/freedesktop/FileManager1",
+		"org.freedesktop.FileManager1.ShowItems",
+		"array:string:"+u, "string:").Run(); err == nil {
+		return nil
+	}
+
+	// Fall back to opening the directory.
+	dir := path
+	if fi, err := os.Stat(path); err != nil || !fi.IsDir() {
+		dir = filepath.Dir(path)
+	}
+	return start(exec.Command("xdg-open", dir))
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"os/exec"
+)
+
+// start starts the command and waits for its end in background so that the resources of the process are released.
+func start(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func openURL(url string) error {
+	return start(exec.Command("rundll32", "url.dll,FileProtocolHandler", url))
+}
+
+func revealFile(path string) error {
+	// explorer's exit code is not reliable, then only starting the process is checked.
+	return start(exec.Command("explorer", "/select,", path))
+}
@@ -0,0 +1,259 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pathfind provides A* pathfinding on grid maps.
+//
+// A map is represented by Grid, which can be implemented by a collision layer of a tile map.
+// For large maps, Search can spread the computation over multiple ticks.
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package pathfind
+
+import (
+	"container/heap"
+	"image"
+	"math"
+)
+
+// Grid represents a grid map.
+type Grid interface {
+	// Size returns the width and the height of the grid in cells.
+	Size() (width, height int)
+
+	// IsWalkable reports whether the cell at (x, y) can be walked through.
+	IsWalkable(x, y int) bool
+}
+
+// CostGrid is a Grid with costs of cells.
+//
+// If a Grid implements CostGrid, the costs are taken into account.
+type CostGrid interface {
+	Grid
+
+	// Cost returns the cost to enter the cell at (x, y). The cost must be 1 or more.
+	Cost(x, y int) float64
+}
+
+// Options represents options for pathfinding.
+type Options struct {
+	// Diagonal represents whether diagonal moves are allowed.
+	// A diagonal move is not allowed when either of the adjacent orthogonal cells is not walkable.
+	Diagonal bool
+}
+
+type node struct {
+	index int
+	f     float64
+}
+
+type nodeHeap []node
+
+func (h nodeHeap) Len() int            { return len(h) }
+func (h nodeHeap) Less(i, j int) bool  { return h[i].f < h[j].f }
+func (h nodeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x interface{}) { *h = append(*h, x.(node)) }
+func (h *nodeHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// Search is an A* search that can be computed incrementally.
+type Search struct {
+	grid     Grid
+	costGrid CostGrid
+	diagonal bool
+
+	width  int
+	height int
+	goal   image.Point
+
+	g      []float64
+	parent []int32
+	closed []bool
+	open   nodeHeap
+
+	done  bool
+	found bool
+	path  []image.Point
+}
+
+// NewSearch creates a new search from the cell from to the cell to.
+//
+// The search doesn't progress until Step is called.
+func NewSearch(grid Grid, from, to image.Point, options *Options) *Search {
+	if options == nil {
+		options = &Options{}
+	}
+	w, h := grid.Size()
+	s := &Search{
+		grid:     grid,
+		diagonal: options.Diagonal,
+		width:    w,
+		height:   h,
+		goal:     to,
+	}
+	if c, ok := grid.(CostGrid); ok {
+		s.costGrid = c
+	}
+
+	if !s.isWalkable(from.X, from.Y) || !s.isWalkable(to.X, to.Y) {
+		s.done = true
+		return s
+	}
+
+	s.g = make([]float64, w*h)
+	for i := range s.g {
+		s.g[i] = math.Inf(1)
+	}
+	s.parent = make([]int32, w*h)
+	s.closed = make([]bool, w*h)
+
+	start := s.index(from.X, from.Y)
+	s.g[start] = 0
+	s.parent[start] = -1
+	heap.Push(&s.open, node{index: start, f: s.heuristic(from.X, from.Y)})
+	return s
+}
+
+func (s *Search) index(x, y int) int {
+	return y*s.width + x
+}
+
+func (s *Search) isWalkable(x, y int) bool {
+	if x < 0 || y < 0 || x >= s.width || y >= s.height {
+		return false
+	}
+	return s.grid.IsWalkable(x, y)
+}
+
+func (s *Search) cost(x, y int) float64 {
+	if s.costGrid == nil {
+		return 1
+	}
+	return s.costGrid.Cost(x, y)
+}
+
+func (s *Search) heuristic(x, y int) float64 {
+	dx := math.Abs(float64(x - s.goal.X))
+	dy := math.Abs(float64(y - s.goal.Y))
+	if !s.diagonal {
+		return dx + dy
+	}
+	// The octile distance.
+	return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+}
+
+var (
+	orthogonalDirs = []image.Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	diagonalDirs   = []image.Point{{1, 1}, {-1, 1}, {1, -1}, {-1, -1}}
+)
+
+// Step expands up to n nodes of the search, and reports whether the search is done.
+//
+// Call Step every tick with an appropriate n to spread the computation for a large map.
+func (s *Search) Step(n int) bool {
+	for i := 0; i < n && !s.done; i++ {
+		s.expand()
+	}
+	return s.done
+}
+
+func (s *Search) expand() {
+	if len(s.open) == 0 {
+		s.done = true
+		return
+	}
+	cur := heap.Pop(&s.open).(node)
+	if s.closed[cur.index] {
+		return
+	}
+	s.closed[cur.index] = true
+
+	x, y := cur.index%s.width, cur.index/s.width
+	if x == s.goal.X && y == s.goal.Y {
+		s.done = true
+		s.found = true
+		s.path = s.buildPath(cur.index)
+		return
+	}
+
+	visit := func(nx, ny int, dist float64) {
+		if !s.isWalkable(nx, ny) {
+			return
+		}
+		ni := s.index(nx, ny)
+		if s.closed[ni] {
+			return
+		}
+		g := s.g[cur.index] + dist*s.cost(nx, ny)
+		if g >= s.g[ni] {
+			return
+		}
+		s.g[ni] = g
+		s.parent[ni] = int32(cur.index)
+		heap.Push(&s.open, node{index: ni, f: g + s.heuristic(nx, ny)})
+	}
+
+	for _, d := range orthogonalDirs {
+		visit(x+d.X, y+d.Y, 1)
+	}
+	if s.diagonal {
+		for _, d := range diagonalDirs {
+			// Don't cut corners.
+			if !s.isWalkable(x+d.X, y) || !s.isWalkable(x, y+d.Y) {
+				continue
+			}
+			visit(x+d.X, y+d.Y, math.Sqrt2)
+		}
+	}
+}
+
+func (s *Search) buildPath(index int) []image.Point {
+	var path []image.Point
+	for i := index; i >= 0; i = int(s.parent[i]) {
+		path = append(path, image.Pt(i%s.width, i/s.width))
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// IsDone reports whether the search is done.
+func (s *Search) IsDone() bool {
+	return s.done
+}
+
+// Path returns the found path including the start and the goal cells.
+//
+// Path returns false if the search is not done yet or no path is found.
+func (s *Search) Path() ([]image.Point, bool) {
+	if !s.found {
+		return nil, false
+	}
+	return s.path, true
+}
+
+// FindPath finds the shortest path from the cell from to the cell to, and returns the path including the start and
+// the goal cells.
+//
+// FindPath returns false if no path is found.
+func FindPath(grid Grid, from, to image.Point, options *Options) ([]image.Point, bool) {
+	s := NewSearch(grid, from, to, options)
+	for !s.Step(1024) {
+	}
+	return s.Path()
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathfind_test
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/ebitenutil/pathfind"
+)
+
+// stringGrid is a grid where '#' is a wall and digits are costs.
+type stringGrid []string
+
+func newStringGrid(s string) stringGrid {
+	return stringGrid(strings.Split(strings.TrimSpace(s), "\n"))
+}
+
+func (g stringGrid) Size() (int, int) {
+	return len(g[0]), len(g)
+}
+
+func (g stringGrid) IsWalkable(x, y int) bool {
+	return g[y][x] != '#'
+}
+
+type costGrid struct {
+	stringGrid
+}
+
+func (g costGrid) Cost(x, y int) float64 {
+	if c := g.stringGrid[y][x]; '1' <= c && c <= '9' {
+		return float64(c - '0')
+	}
+	return 1
+}
+
+func TestFindPath(t *testing.T) {
+	g := newStringGrid(`
+.....
+.###.
+...#.
+.#...
+`)
+	path, ok := FindPath(g, image.Pt(0, 2), image.Pt(4, 2), nil)
+	if !ok {
+		t.Fatal("a path must be found")
+	}
+	if got, want := path[0], image.Pt(0, 2); got != want {
+		t.Errorf("start: got: %v, want: %v", got, want)
+	}
+	if got, want := path[len(path)-1], image.Pt(4, 2); got != want {
+		t.Errorf("goal: got: %v, want: %v", got, want)
+	}
+	// The shortest path goes through the bottom: (0,2) (1,2) (2,2) (2,3) (3,3) (4,3) (4,2).
+	if got, want := len(path), 7; got != want {
+		t.Errorf("len(path): got: %d, want: %d (%v)", got, want, path)
+	}
+	for i := 1; i < len(path); i++ {
+		d := path[i].Sub(path[i-1])
+		if d.X*d.X+d.Y*d.Y != 1 {
+			t.Errorf("the path must be continuous: %v", path)
+		}
+		if !g.IsWalkable(path[i].X, path[i].Y) {
+			t.Errorf("the path must not go through walls: %v", path)
+		}
+	}
+}
+
+func TestFindPathNotFound(t *testing.T) {
+	g := newStringGrid(`
+..#..
+..#..
+..#..
+`)
+	if _, ok := FindPath(g, image.Pt(0, 0), image.Pt(4, 0), nil); ok {
+		t.Errorf("a path must not be found")
+	}
+	// The goal is a wall.
+	if _, ok := FindPath(g, image.Pt(0, 0), image.Pt(2, 0), nil); ok {
+		t.Errorf("a path must not be found")
+	}
+}
+
+func TestFindPathDiagonal(t *testing.T) {
+	g := newStringGrid(`
+....
+....
+....
+....
+`)
+	path, ok := FindPath(g, image.Pt(0, 0), image.Pt(3, 3), &Options{Diagonal: true})
+	if !ok {
+		t.Fatal("a path must be found")
+	}
+	if got, want := len(path), 4; got != want {
+		t.Errorf("len(path): got: %d, want: %d (%v)", got, want, path)
+	}
+
+	// Corners must not be cut.
+	g = newStringGrid(`
+.#
+..
+`)
+	path, ok = FindPath(g, image.Pt(0, 0), image.Pt(1, 1), &Options{Diagonal: true})
+	if !ok {
+		t.Fatal("a path must be found")
+	}
+	if got, want := len(path), 3; got != want {
+		t.Errorf("len(path): got: %d, want: %d (%v)", got, want, path)
+	}
+}
+
+func TestFindPathCost(t *testing.T) {
+	g := costGrid{newStringGrid(`
+.9.
+...
+`)}
+	path, ok := FindPath(g, image.Pt(0, 0), image.Pt(2, 0), nil)
+	if !ok {
+		t.Fatal("a path must be found")
+	}
+	// The expensive cell must be avoided.
+	for _, p := range path {
+		if p == image.Pt(1, 0) {
+			t.Errorf("the path must avoid the expensive cell: %v", path)
+		}
+	}
+}
+
+func TestSearchStep(t *testing.T) {
+	g := newStringGrid(strings.Repeat(strings.Repeat(".", 64)+"\n", 64))
+	s := NewSearch(g, image.Pt(0, 0), image.Pt(63, 63), nil)
+	steps := 0
+	for !s.Step(10) {
+		if _, ok := s.Path(); ok {
+			t.Fatal("Path must not be available before the search is done")
+		}
+		steps++
+	}
+	if steps == 0 {
+		t.Errorf("the search must be spread over multiple steps")
+	}
+	path, ok := s.Path()
+	if !ok {
+		t.Fatal("a path must be found")
+	}
+	if got, want := len(path), 127; got != want {
+		t.Errorf("len(path): got: %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteplay
+
+// page is the HTML page to play the game. The key names are converted from KeyboardEvent.code to the names of
+// ebiten.Key.
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Ebiten Remote Play</title>
+<style>
+html, body { margin: 0; height: 100%; background: #000; }
+body { display: flex; align-items: center; justify-content: center; }
+img { max-width: 100%; max-height: 100%; image-rendering: pixelated; cursor: crosshair; }
+</style>
+</head>
+<body>
+<img id="screen" src="/stream" draggable="false">
+<script>
+'use strict';
+
+const screen = document.getElementById('screen');
+const special = {
+  'AltLeft': 'Alt', 'AltRight': 'Alt',
+  'ControlLeft': 'Control', 'ControlRight': 'Control',
+  'ShiftLeft': 'Shift', 'ShiftRight': 'Shift',
+  'Quote': 'Apostrophe', 'Backquote': 'GraveAccent',
+  'BracketLeft': 'LeftBracket', 'BracketRight': 'RightBracket',
+  'ContextMenu': 'Menu',
+};
+
+function keyName(code) {
+  if (code in special) {
+    return special[code];
+  }
+  let m;
+  if ((m = code.match(/^Key([A-Z])$/)) || (m = code.match(/^Digit([0-9])$/)) || (m = code.match(/^Arrow(.+)$/))) {
+    return m[1];
+  }
+  if ((m = code.match(/^Numpad(.+)$/))) {
+    return 'KP' + m[1];
+  }
+  return code;
+}
+
+let pending = [];
+let sending = false;
+
+function send(line) {
+  pending.push(line);
+  flush();
+}
+
+function flush() {
+  if (sending || pending.length === 0) {
+    return;
+  }
+  sending = true;
+  const body = pending.join('\n');
+  pending = [];
+  fetch('/input', {method: 'POST', body: body}).finally(() => {
+    sending = false;
+    flush();
+  });
+}
+
+document.addEventListener('keydown', (e) => {
+  e.preventDefault();
+  if (!e.repeat) {
+    send('keydown ' + keyName(e.code));
+  }
+});
+document.addEventListener('keyup', (e) => {
+  e.preventDefault();
+  send('keyup ' + keyName(e.code));
+});
+
+function cursor(e) {
+  const r = screen.getBoundingClientRect();
+  const x = Math.floor((e.clientX - r.left) * screen.naturalWidth / r.width);
+  const y = Math.floor((e.clientY - r.top) * screen.naturalHeight / r.height);
+  return x + ' ' + y;
+}
+
+screen.addEventListener('mousemove', (e) => {
+  send('mousemove ' + cursor(e));
+});
+screen.addEventListener('mousedown', (e) => {
+  e.preventDefault();
+  send('mousemove ' + cursor(e));
+  send('mousedown ' + e.button);
+});
+document.addEventListener('mouseup', (e) => {
+  send('mouseup ' + e.button);
+});
+screen.addEventListener('contextmenu', (e) => {
+  e.preventDefault();
+});
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,338 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package remoteplay provides a server to play a game remotely with a browser.
+//
+// The server streams the game screen to browsers as Motion JPEG over HTTP, and receives the keyboard and mouse
+// inputs from them. The inputs are injected into Ebiten's input states by the input injection functions like
+// ebiten.InjectKeyEvent, so the game can handle them without any changes. This is useful to playtest builds
+// running on a remote machine.
+//
+// Here is an example:
+//
+//     s := remoteplay.NewServer(nil)
+//     ebiten.SetPresentHook(s.PresentHook)
+//     go func() {
+//         log.Fatal(http.ListenAndServe(":8080", s))
+//     }()
+//
+// Then, open http://<host>:8080/ with a browser.
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package remoteplay
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Options represents options for a Server.
+type Options struct {
+	// Quality is the quality of JPEG in [1, 100].
+	// The default (zero) value is 75.
+	Quality int
+
+	// FrameInterval is the number of frames between streamed frames.
+	// The default (zero) value is 4, which means 15 frames per second at 60 TPS.
+	FrameInterval int
+
+	// DisableInputInjection disables injecting the remote inputs into Ebiten's input states.
+	// The remote inputs are still available via the Server's functions like IsKeyPressed.
+	DisableInputInjection bool
+}
+
+// Server is an HTTP handler that streams the game screen and receives inputs from browsers.
+//
+// Server serves these paths:
+//
+//   * /: An HTML page to play the game.
+//   * /stream: The game screen as Motion JPEG.
+//   * /input: The endpoint to post inputs.
+type Server struct {
+	quality  int
+	interval int
+	inject   bool
+
+	count   int
+	encodeC chan *image.RGBA
+
+	frame   []byte
+	updated chan struct{}
+	clients int
+
+	keys    map[ebiten.Key]bool
+	buttons map[ebiten.MouseButton]bool
+	cursorX int
+	cursorY int
+
+	m sync.Mutex
+}
+
+// NewServer creates a new Server. opts can be nil.
+func NewServer(opts *Options) *Server {
+	s := &Server{
+		quality:  75,
+		interval: 4,
+		inject:   true,
+		encodeC:  make(chan *image.RGBA, 1),
+		updated:  make(chan struct{}),
+		keys:     map[ebiten.Key]bool{},
+		buttons:  map[ebiten.MouseButton]bool{},
+	}
+	if opts != nil {
+		if opts.Quality != 0 {
+			s.quality = opts.Quality
+		}
+		if opts.FrameInterval != 0 {
+			s.interval = opts.FrameInterval
+		}
+		s.inject = !opts.DisableInputInjection
+	}
+	go s.encodeLoop()
+	return s
+}
+
+// PresentHook captures the screen for streaming. Pass this to ebiten.SetPresentHook.
+//
+// PresentHook doesn't modify the screen.
+func (s *Server) PresentHook(screen *ebiten.Image) (*ebiten.Image, error) {
+	s.m.Lock()
+	s.count++
+	capture := s.clients > 0 && s.count%s.interval == 0
+	s.m.Unlock()
+
+	if !capture {
+		return nil, nil
+	}
+
+	w, h := screen.Size()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			img.Set(i, j, screen.At(i, j))
+		}
+	}
+
+	// Drop the frame if the previous frame is still being encoded. Streaming prefers the latest frame.
+	select {
+	case s.encodeC <- img:
+	default:
+	}
+	return nil, nil
+}
+
+func (s *Server) encodeLoop() {
+	for img := range s.encodeC {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: s.quality}); err != nil {
+			continue
+		}
+
+		s.m.Lock()
+		s.frame = buf.Bytes()
+		close(s.updated)
+		s.updated = make(chan struct{})
+		s.m.Unlock()
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	case "/stream":
+		s.serveStream(w, r)
+	case "/input":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := s.readInput(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+const boundary = "ebitenframe"
+
+func (s *Server) serveStream(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	s.clients++
+	s.m.Unlock()
+	defer func() {
+		s.m.Lock()
+		s.clients--
+		s.m.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+boundary)
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+
+	for {
+		s.m.Lock()
+		updated := s.updated
+		s.m.Unlock()
+
+		select {
+		case <-updated:
+		case <-r.Context().Done():
+			return
+		}
+
+		s.m.Lock()
+		frame := s.frame
+		s.m.Unlock()
+
+		if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", boundary, len(frame)); err != nil {
+			return
+		}
+		if _, err := w.Write(frame); err != nil {
+			return
+		}
+		if _, err := w.Write([]byte("\r\n")); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+var nameToKey = map[string]ebiten.Key{}
+
+func init() {
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		nameToKey[k.String()] = k
+	}
+}
+
+// readInput reads the inputs posted by the page. Each line is one of these:
+//
+//   * keydown <key name>
+//   * keyup <key name>
+//   * mousemove <x> <y>
+//   * mousedown <button>
+//   * mouseup <button>
+func (s *Server) readInput(r *http.Request) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	sc := bufio.NewScanner(r.Body)
+	for sc.Scan() {
+		tokens := strings.Fields(sc.Text())
+		if len(tokens) == 0 {
+			continue
+		}
+		switch tokens[0] {
+		case "keydown", "keyup":
+			if len(tokens) != 2 {
+				return fmt.Errorf("remoteplay: invalid input: %q", sc.Text())
+			}
+			k, ok := nameToKey[tokens[1]]
+			if !ok {
+				// Ignore unknown keys.
+				continue
+			}
+			s.keys[k] = tokens[0] == "keydown"
+			if s.inject {
+				ebiten.InjectKeyEvent(k, s.keys[k])
+			}
+		case "mousemove":
+			if len(tokens) != 3 {
+				return fmt.Errorf("remoteplay: invalid input: %q", sc.Text())
+			}
+			x, err := strconv.Atoi(tokens[1])
+			if err != nil {
+				return err
+			}
+			y, err := strconv.Atoi(tokens[2])
+			if err != nil {
+				return err
+			}
+			s.cursorX, s.cursorY = x, y
+			if s.inject {
+				ebiten.InjectCursorPosition(x, y)
+			}
+		case "mousedown", "mouseup":
+			if len(tokens) != 2 {
+				return fmt.Errorf("remoteplay: invalid input: %q", sc.Text())
+			}
+			b, err := strconv.Atoi(tokens[1])
+			if err != nil {
+				return err
+			}
+			var button ebiten.MouseButton
+			switch b {
+			case 0:
+				button = ebiten.MouseButtonLeft
+			case 1:
+				button = ebiten.MouseButtonMiddle
+			case 2:
+				button = ebiten.MouseButtonRight
+			default:
+				continue
+			}
+			s.buttons[button] = tokens[0] == "mousedown"
+			if s.inject {
+				ebiten.InjectMouseButtonEvent(button, s.buttons[button])
+			}
+		default:
+			return fmt.Errorf("remoteplay: invalid input: %q", sc.Text())
+		}
+	}
+	return sc.Err()
+}
+
+// IsKeyPressed reports whether the key is pressed on the remote browser.
+//
+// IsKeyPressed is concurrent-safe.
+func (s *Server) IsKeyPressed(key ebiten.Key) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.keys[key]
+}
+
+// IsMouseButtonPressed reports whether the mouse button is pressed on the remote browser.
+//
+// IsMouseButtonPressed is concurrent-safe.
+func (s *Server) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.buttons[button]
+}
+
+// CursorPosition returns the position of the mouse cursor on the remote browser in the game screen coordinates.
+//
+// CursorPosition is concurrent-safe.
+func (s *Server) CursorPosition() (x, y int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.cursorX, s.cursorY
+}
@@ -0,0 +1,462 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package textfield provides a single-line text field as a reference implementation of text input with Ebiten.
+//
+// The text is rendered with the debug font of ebitenutil. The field supports a caret, a selection by the keyboard and
+// the mouse, copying, cutting and pasting via a clipboard, and a composition text of an input method.
+//
+// Here is an example:
+//
+//     var field = textfield.New(10, 10, 200)
+//
+//     func update(screen *ebiten.Image) error {
+//         field.Update()
+//         if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+//             submit(field.Text())
+//         }
+//
+//         // Draw the game here.
+//
+//         field.Draw(screen)
+//         return nil
+//     }
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package textfield
+
+import (
+	"image"
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/ebitenutil/internal/assets"
+	"github.com/hajimehoshi/ebiten/inpututil"
+)
+
+const (
+	charWidth  = assets.CharWidth
+	charHeight = assets.CharHeight
+
+	padding = 4
+	height  = charHeight + 2*padding
+
+	// The unit of the key repeat is ticks.
+	repeatDelay    = 30
+	repeatInterval = 3
+
+	blinkInterval = 30
+)
+
+var (
+	backgroundColor      = color.RGBA{0x30, 0x30, 0x30, 0xff}
+	focusBackgroundColor = color.RGBA{0x40, 0x40, 0x50, 0xff}
+	borderColor          = color.RGBA{0x80, 0x80, 0x80, 0xff}
+	selectionColor       = color.RGBA{0x40, 0x60, 0xc0, 0xff}
+	caretColor           = color.White
+)
+
+// Clipboard represents a clipboard to copy and paste texts.
+type Clipboard interface {
+	// ReadText returns the text in the clipboard.
+	ReadText() string
+
+	// WriteText stores the text in the clipboard.
+	WriteText(text string)
+}
+
+type localClipboard struct {
+	text string
+	m    sync.Mutex
+}
+
+func (c *localClipboard) ReadText() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.text
+}
+
+func (c *localClipboard) WriteText(text string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.text = text
+}
+
+// DefaultClipboard is the clipboard used when Field's Clipboard is nil.
+//
+// As Ebiten doesn't provide an access to the system clipboard, DefaultClipboard is shared only in the process.
+var DefaultClipboard Clipboard = &localClipboard{}
+
+// Field represents a single-line text field.
+type Field struct {
+	// X and Y are the position of the upper-left corner of the field on the screen.
+	X int
+	Y int
+
+	// Width is the width of the field. The height is determined by the font.
+	Width int
+
+	// MaxLength is the maximum number of the characters. 0 means no limit.
+	MaxLength int
+
+	// Clipboard is the clipboard for copying and pasting. If Clipboard is nil, DefaultClipboard is used.
+	Clipboard Clipboard
+
+	text []rune
+
+	// caret is the index of the character after the caret.
+	caret int
+
+	// anchor is the other end of the selection. There is no selection when anchor equals to caret.
+	anchor int
+
+	// composition is the text being composed by an input method.
+	composition []rune
+
+	// scroll is the index of the first visible character.
+	scroll int
+
+	focused  bool
+	dragging bool
+	ticks    int
+}
+
+// New returns a new text field at (x, y) with the given width.
+func New(x, y, width int) *Field {
+	return &Field{
+		X:     x,
+		Y:     y,
+		Width: width,
+	}
+}
+
+// Text returns the text of the field.
+func (f *Field) Text() string {
+	return string(f.text)
+}
+
+// SetText sets the text of the field and moves the caret to the end.
+func (f *Field) SetText(text string) {
+	f.text = []rune(text)
+	if f.MaxLength > 0 && len(f.text) > f.MaxLength {
+		f.text = f.text[:f.MaxLength]
+	}
+	f.caret = len(f.text)
+	f.anchor = f.caret
+	f.composition = nil
+}
+
+// Bounds returns the region of the field on the screen.
+func (f *Field) Bounds() image.Rectangle {
+	return image.Rect(f.X, f.Y, f.X+f.Width, f.Y+height)
+}
+
+// Focus gives the input focus to the field.
+func (f *Field) Focus() {
+	f.focused = true
+	f.ticks = 0
+}
+
+// Blur removes the input focus from the field.
+func (f *Field) Blur() {
+	f.focused = false
+	f.dragging = false
+	f.composition = nil
+}
+
+// IsFocused reports whether the field has the input focus.
+func (f *Field) IsFocused() bool {
+	return f.focused
+}
+
+// Selection returns the selected range [start, end) in characters. start equals to end when nothing is selected.
+func (f *Field) Selection() (start, end int) {
+	if f.anchor < f.caret {
+		return f.anchor, f.caret
+	}
+	return f.caret, f.anchor
+}
+
+// SetComposition sets the text being composed by an input method. The composition text is shown at the caret with
+// an underline, and is not a part of Text until it is committed.
+//
+// Ebiten doesn't expose compositions of input methods yet. SetComposition is for the cases when the game gets the
+// composition in other ways. Committed texts should come from ebiten.InputChars.
+func (f *Field) SetComposition(text string) {
+	f.composition = []rune(text)
+}
+
+func (f *Field) clipboard() Clipboard {
+	if f.Clipboard != nil {
+		return f.Clipboard
+	}
+	return DefaultClipboard
+}
+
+func isKeyRepeated(key ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(key)
+	if d == 1 {
+		return true
+	}
+	return d >= repeatDelay && (d-repeatDelay)%repeatInterval == 0
+}
+
+// Update processes the inputs. Update must be called once every tick.
+//
+// Update returns true when the text is changed.
+func (f *Field) Update() bool {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		if image.Pt(x, y).In(f.Bounds()) {
+			f.Focus()
+			f.caret = f.indexAt(x)
+			if !ebiten.IsKeyPressed(ebiten.KeyShift) {
+				f.anchor = f.caret
+			}
+			f.dragging = true
+		} else {
+			f.Blur()
+		}
+	}
+	if f.dragging {
+		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			x, _ := ebiten.CursorPosition()
+			f.caret = f.indexAt(x)
+		} else {
+			f.dragging = false
+		}
+	}
+
+	if !f.focused {
+		return false
+	}
+	f.ticks++
+
+	changed := false
+	if cs := ebiten.InputChars(); len(cs) > 0 {
+		f.composition = nil
+		f.insert(cs)
+		changed = true
+	}
+
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl)
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	move := func(to int) {
+		if to < 0 {
+			to = 0
+		}
+		if to > len(f.text) {
+			to = len(f.text)
+		}
+		f.caret = to
+		if !shift {
+			f.anchor = f.caret
+		}
+		f.ticks = 0
+	}
+
+	switch {
+	case isKeyRepeated(ebiten.KeyLeft):
+		if s, e := f.Selection(); s != e && !shift {
+			move(s)
+		} else {
+			move(f.caret - 1)
+		}
+	case isKeyRepeated(ebiten.KeyRight):
+		if s, e := f.Selection(); s != e && !shift {
+			move(e)
+		} else {
+			move(f.caret + 1)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		move(0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		move(len(f.text))
+	case isKeyRepeated(ebiten.KeyBackspace):
+		if s, e := f.Selection(); s == e && f.caret > 0 {
+			f.anchor = f.caret - 1
+		}
+		changed = f.deleteSelection() || changed
+	case isKeyRepeated(ebiten.KeyDelete):
+		if s, e := f.Selection(); s == e && f.caret < len(f.text) {
+			f.anchor = f.caret + 1
+		}
+		changed = f.deleteSelection() || changed
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyA):
+		f.anchor = 0
+		f.caret = len(f.text)
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyC):
+		if s, e := f.Selection(); s != e {
+			f.clipboard().WriteText(string(f.text[s:e]))
+		}
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyX):
+		if s, e := f.Selection(); s != e {
+			f.clipboard().WriteText(string(f.text[s:e]))
+			changed = f.deleteSelection() || changed
+		}
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyV):
+		if t := f.clipboard().ReadText(); t != "" {
+			f.insert([]rune(t))
+			changed = true
+		}
+	}
+
+	f.updateScroll()
+
+	// Let the candidate window of the input method appear at the caret.
+	cx, cy := f.caretPosition()
+	ebiten.SetInputMethodCaretPosition(cx, cy, charHeight)
+
+	return changed
+}
+
+// insert replaces the selection with rs. Control characters like new lines are ignored.
+func (f *Field) insert(rs []rune) {
+	f.deleteSelection()
+
+	var valid []rune
+	for _, r := range rs {
+		if r < 0x20 || r == 0x7f {
+			continue
+		}
+		valid = append(valid, r)
+	}
+	if f.MaxLength > 0 {
+		if n := f.MaxLength - len(f.text); len(valid) > n {
+			valid = valid[:n]
+		}
+	}
+
+	text := make([]rune, 0, len(f.text)+len(valid))
+	text = append(text, f.text[:f.caret]...)
+	text = append(text, valid...)
+	text = append(text, f.text[f.caret:]...)
+	f.text = text
+	f.caret += len(valid)
+	f.anchor = f.caret
+	f.ticks = 0
+}
+
+// deleteSelection deletes the selected text, and reports whether the text is changed.
+func (f *Field) deleteSelection() bool {
+	s, e := f.Selection()
+	if s == e {
+		return false
+	}
+	f.text = append(f.text[:s], f.text[e:]...)
+	f.caret = s
+	f.anchor = s
+	f.ticks = 0
+	return true
+}
+
+func (f *Field) visibleChars() int {
+	n := (f.Width - 2*padding) / charWidth
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// updateScroll scrolls the text so that the caret and the composition are visible.
+func (f *Field) updateScroll() {
+	n := f.visibleChars()
+	end := f.caret + len(f.composition)
+	if f.caret < f.scroll {
+		f.scroll = f.caret
+	}
+	if end >= f.scroll+n {
+		f.scroll = end - n + 1
+	}
+	if f.scroll > len(f.text) {
+		f.scroll = len(f.text)
+	}
+	if f.scroll < 0 {
+		f.scroll = 0
+	}
+}
+
+// indexAt returns the character index at the given x position on the screen.
+func (f *Field) indexAt(x int) int {
+	i := f.scroll + (x-f.X-padding+charWidth/2)/charWidth
+	if i < 0 {
+		return 0
+	}
+	if i > len(f.text) {
+		return len(f.text)
+	}
+	return i
+}
+
+// charX returns the x position of the left side of the i-th visible character.
+func (f *Field) charX(i int) int {
+	return f.X + padding + (i-f.scroll)*charWidth
+}
+
+// caretPosition returns the position of the top of the caret on the screen.
+func (f *Field) caretPosition() (int, int) {
+	return f.charX(f.caret + len(f.composition)), f.Y + padding
+}
+
+func drawRect(screen *ebiten.Image, r image.Rectangle, clr color.Color) {
+	ebitenutil.DrawRect(screen, float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy()), clr)
+}
+
+// Draw renders the field on the given screen.
+func (f *Field) Draw(screen *ebiten.Image) {
+	b := f.Bounds()
+	drawRect(screen, b, borderColor)
+	bg := backgroundColor
+	if f.focused {
+		bg = focusBackgroundColor
+	}
+	drawRect(screen, b.Inset(1), bg)
+
+	inner := b.Inset(padding)
+	clip := func(r image.Rectangle) image.Rectangle {
+		return r.Intersect(image.Rect(inner.Min.X, b.Min.Y, inner.Max.X, b.Max.Y))
+	}
+
+	if s, e := f.Selection(); s != e && f.focused {
+		drawRect(screen, clip(image.Rect(f.charX(s), inner.Min.Y, f.charX(e), inner.Max.Y)), selectionColor)
+	}
+
+	// The visible text consists of the text before the caret, the composition and the text after the caret.
+	visible := make([]rune, 0, len(f.text)+len(f.composition))
+	visible = append(visible, f.text[:f.caret]...)
+	visible = append(visible, f.composition...)
+	visible = append(visible, f.text[f.caret:]...)
+	n := f.visibleChars()
+	start := f.scroll
+	end := start + n
+	if end > len(visible) {
+		end = len(visible)
+	}
+	if start < end {
+		ebitenutil.DebugPrintAt(screen, string(visible[start:end]), inner.Min.X, inner.Min.Y)
+	}
+
+	if len(f.composition) > 0 {
+		x0 := f.charX(f.caret)
+		x1 := f.charX(f.caret + len(f.composition))
+		drawRect(screen, clip(image.Rect(x0, inner.Max.Y-1, x1, inner.Max.Y)), caretColor)
+	}
+
+	if f.focused && (f.ticks/blinkInterval)%2 == 0 {
+		x, y := f.caretPosition()
+		drawRect(screen, clip(image.Rect(x, y, x+1, y+charHeight)), caretColor)
+	}
+}
@@ -16,18 +16,9 @@ package blocks
 
 import (
 	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil/effect"
 )
 
-var (
-	transitionFrom *ebiten.Image
-	transitionTo   *ebiten.Image
-)
-
-func init() {
-	transitionFrom, _ = ebiten.NewImage(ScreenWidth, ScreenHeight, ebiten.FilterDefault)
-	transitionTo, _ = ebiten.NewImage(ScreenWidth, ScreenHeight, ebiten.FilterDefault)
-}
-
 type Scene interface {
 	Update(state *GameState) error
 	Draw(screen *ebiten.Image)
@@ -36,9 +27,9 @@ type Scene interface {
 const transitionMaxCount = 20
 
 type SceneManager struct {
-	current         Scene
-	next            Scene
-	transitionCount int
+	current    Scene
+	next       Scene
+	transition effect.SceneTransition
 }
 
 type GameState struct {
@@ -47,15 +38,15 @@ type GameState struct {
 }
 
 func (s *SceneManager) Update(input *Input) error {
-	if s.transitionCount == 0 {
+	if !s.transition.IsActive() {
 		return s.current.Update(&GameState{
 			SceneManager: s,
 			Input:        input,
 		})
 	}
 
-	s.transitionCount--
-	if s.transitionCount > 0 {
+	s.transition.Update()
+	if s.transition.IsActive() {
 		return nil
 	}
 
@@ -65,23 +56,11 @@ func (s *SceneManager) Update(input *Input) error {
 }
 
 func (s *SceneManager) Draw(r *ebiten.Image) {
-	if s.transitionCount == 0 {
+	if !s.transition.IsActive() {
 		s.current.Draw(r)
 		return
 	}
-
-	transitionFrom.Clear()
-	s.current.Draw(transitionFrom)
-
-	transitionTo.Clear()
-	s.next.Draw(transitionTo)
-
-	r.DrawImage(transitionFrom, nil)
-
-	alpha := 1 - float64(s.transitionCount)/float64(transitionMaxCount)
-	op := &ebiten.DrawImageOptions{}
-	op.ColorM.Scale(1, 1, 1, alpha)
-	r.DrawImage(transitionTo, op)
+	s.transition.Draw(r, s.current.Draw, s.next.Draw)
 }
 
 func (s *SceneManager) GoTo(scene Scene) {
@@ -89,6 +68,6 @@ func (s *SceneManager) GoTo(scene Scene) {
 		s.current = scene
 	} else {
 		s.next = scene
-		s.transitionCount = transitionMaxCount
+		s.transition.Start(effect.Crossfade{}, transitionMaxCount)
 	}
 }
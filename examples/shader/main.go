@@ -0,0 +1,100 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build example jsgo
+
+package main
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg"
+	"log"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/examples/resources/images"
+)
+
+const (
+	screenWidth  = 640
+	screenHeight = 480
+)
+
+const waveShader = `package main
+
+var Time float
+var Cursor vec2
+
+func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+	size := texture0Size()
+	offset := vec2(sin(position.y/16+Time*4)*4, 0)
+	c := texture0At(texCoord + offset/size)
+
+	// Dissolve the image around the cursor.
+	if d := distance(position, Cursor); d < 64 {
+		c *= smoothstep(32, 64, d)
+	}
+	return c * color
+}
+`
+
+type Game struct {
+	shader       *ebiten.Shader
+	gophersImage *ebiten.Image
+	time         int
+}
+
+func (g *Game) Update(screen *ebiten.Image) error {
+	g.time++
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	w, h := g.gophersImage.Size()
+	cx, cy := ebiten.CursorPosition()
+	tx, ty := (screenWidth-w)/2, (screenHeight-h)/2
+
+	op := &ebiten.DrawRectShaderOptions{}
+	op.GeoM.Translate(float64(tx), float64(ty))
+	op.Image = g.gophersImage
+	op.Uniforms = map[string]interface{}{
+		"Time":   float32(g.time) / 60,
+		"Cursor": []float32{float32(cx - tx), float32(cy - ty)},
+	}
+	screen.DrawRectShader(w, h, g.shader, op)
+	return nil
+}
+
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
+
+func main() {
+	img, _, err := image.Decode(bytes.NewReader(images.Gophers_jpg))
+	if err != nil {
+		log.Fatal(err)
+	}
+	gophersImage, _ := ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+
+	s, err := ebiten.NewShader([]byte(waveShader))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+	ebiten.SetWindowTitle("Shader (Ebiten Demo)")
+	if err := ebiten.RunGame(&Game{shader: s, gophersImage: gophersImage}); err != nil {
+		log.Fatal(err)
+	}
+}
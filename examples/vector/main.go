@@ -162,6 +162,29 @@ func drawWave(screen *ebiten.Image, counter int) {
 	path.Fill(screen, color.RGBA{0x33, 0x66, 0xff, 0xff})
 }
 
+func drawRing(screen *ebiten.Image, counter int) {
+	const cx, cy, r = 400, 130, 32
+
+	// A ring is a circle with a hole, filled with the non-zero rule.
+	var path vector.Path
+	path.Arc(cx, cy, r, 0, 2*math.Pi, vector.Clockwise)
+	path.Close()
+	path.Arc(cx, cy, r/2, 0, 2*math.Pi, vector.CounterClockwise)
+	path.Close()
+	path.FillWithOptions(screen, color.RGBA{0x33, 0xcc, 0x66, 0xff}, &vector.FillOptions{
+		Antialias: true,
+	})
+
+	var stroke vector.Path
+	theta := float32(counter) * 2 * math.Pi / 120
+	stroke.Arc(cx, cy, r+8, theta, theta+math.Pi, vector.Clockwise)
+	stroke.Stroke(screen, color.RGBA{0xdb, 0x56, 0x20, 0xff}, &vector.StrokeOptions{
+		Width:     4,
+		LineCap:   vector.LineCapRound,
+		Antialias: true,
+	})
+}
+
 var counter = 0
 
 func update(screen *ebiten.Image) error {
@@ -174,6 +197,7 @@ func update(screen *ebiten.Image) error {
 	drawEbitenText(screen)
 	drawEbitenLogo(screen, 20, 90)
 	drawWave(screen, counter)
+	drawRing(screen, counter)
 
 	ebitenutil.DebugPrint(screen, fmt.Sprintf("TPS: %0.2f\nFPS: %0.2f", ebiten.CurrentTPS(), ebiten.CurrentFPS()))
 	return nil
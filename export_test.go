@@ -14,6 +14,25 @@
 
 package ebiten
 
+import (
+	"github.com/hajimehoshi/ebiten/internal/buffered"
+)
+
 var (
-	CopyImage = copyImage
+	CopyImage                   = copyImage
+	GamepadTypeFromSDLIDAndName = gamepadType
+	TempImageMaxIdleFrames      = tempImageMaxIdleFrames
 )
+
+// EndTempImageFrameForTesting ends a frame of the temporary image pool.
+func EndTempImageFrameForTesting() {
+	theTempImagePool.endFrame()
+}
+
+// EndFrameForTesting flushes the commands and the pending pixels of the current frame, and begins a new frame.
+func EndFrameForTesting() error {
+	if err := buffered.EndFrame(); err != nil {
+		return err
+	}
+	return buffered.BeginFrame()
+}
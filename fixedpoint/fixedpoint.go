@@ -0,0 +1,267 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fixedpoint provides Q32.32 fixed-point numbers and math functions for deterministic simulations.
+//
+// Floating-point results can differ across architectures and compilers, e.g. by fused multiply-add instructions,
+// which breaks lockstep multiplayer games and replays. The functions in this package use only integer operations,
+// so the results are exactly the same on all the platforms.
+//
+// As + and - work on Fixed values as they are, use them for addition and subtraction:
+//
+//     v := fixedpoint.FromInt(3)
+//     v += fixedpoint.FromRatio(1, 2) // 3.5
+//     v = v.Mul(v)                    // 12.25
+//
+// Fixed and Vec2 implement encoding.BinaryMarshaler and encoding.BinaryUnmarshaler. The encoded bytes depend only
+// on the values, so they can be used for ebiten.Snapshotter to rewind and audit the game state with
+// ebiten.StartDeterminismAudit.
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package fixedpoint
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+// Fixed is a signed fixed-point number with 32 integer bits and 32 fractional bits (Q32.32).
+//
+// The range is about [-2147483648, 2147483648) and the precision is 1/4294967296. Results out of the range wrap
+// around like int64.
+type Fixed int64
+
+const fracBits = 32
+
+const (
+	// Zero is 0.
+	Zero Fixed = 0
+
+	// One is 1.
+	One Fixed = 1 << fracBits
+
+	// Half is 0.5.
+	Half Fixed = 1 << (fracBits - 1)
+
+	// Epsilon is the smallest positive value.
+	Epsilon Fixed = 1
+
+	// MaxValue is the maximum value.
+	MaxValue Fixed = math.MaxInt64
+
+	// MinValue is the minimum value.
+	MinValue Fixed = math.MinInt64
+)
+
+// FromInt returns the Fixed value of the integer n.
+func FromInt(n int) Fixed {
+	return Fixed(int64(n) << fracBits)
+}
+
+// FromRatio returns the Fixed value of n / d, rounded toward zero.
+//
+// FromRatio panics if d is 0.
+func FromRatio(n, d int) Fixed {
+	return FromInt(n).Div(FromInt(d))
+}
+
+// FromFloat64 returns the Fixed value nearest to f.
+//
+// The conversion itself is deterministic, but the floating-point value might not be. Use FromFloat64 only for
+// constants or for values not shared by the simulation.
+func FromFloat64(f float64) Fixed {
+	return Fixed(math.Round(math.Ldexp(f, fracBits)))
+}
+
+// Float64 returns the floating-point value of x.
+func (x Fixed) Float64() float64 {
+	return math.Ldexp(float64(x), -fracBits)
+}
+
+// Int returns the integer part of x, rounded toward negative infinity.
+func (x Fixed) Int() int {
+	return int(x >> fracBits)
+}
+
+// Floor returns the greatest integer value less than or equal to x.
+func (x Fixed) Floor() Fixed {
+	return x &^ (One - 1)
+}
+
+// Ceil returns the least integer value greater than or equal to x.
+func (x Fixed) Ceil() Fixed {
+	return (x + One - 1).Floor()
+}
+
+// Round returns the nearest integer value, rounding half away from zero.
+func (x Fixed) Round() Fixed {
+	if x < 0 {
+		return -(-x + Half).Floor()
+	}
+	return (x + Half).Floor()
+}
+
+// Frac returns the fractional part of x, that is x - x.Floor().
+func (x Fixed) Frac() Fixed {
+	return x & (One - 1)
+}
+
+// Abs returns the absolute value of x.
+func (x Fixed) Abs() Fixed {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Sign returns -1, 0 or 1 for a negative value, zero or a positive value.
+func (x Fixed) Sign() int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Mul returns x * y, rounded toward negative infinity.
+func (x Fixed) Mul(y Fixed) Fixed {
+	hi, lo := bits.Mul64(uint64(x), uint64(y))
+	// Convert the unsigned product into the signed product.
+	if x < 0 {
+		hi -= uint64(y)
+	}
+	if y < 0 {
+		hi -= uint64(x)
+	}
+	return Fixed(hi<<(64-fracBits) | lo>>fracBits)
+}
+
+// Div returns x / y, rounded toward zero.
+//
+// Div panics if y is 0 or the quotient overflows.
+func (x Fixed) Div(y Fixed) Fixed {
+	if y == 0 {
+		panic("fixedpoint: division by zero")
+	}
+	neg := (x < 0) != (y < 0)
+	ux, uy := uint64(x.Abs()), uint64(y.Abs())
+	hi, lo := ux>>(64-fracBits), ux<<fracBits
+	if hi >= uy {
+		panic("fixedpoint: division overflow")
+	}
+	q, _ := bits.Div64(hi, lo, uy)
+	if neg {
+		return -Fixed(q)
+	}
+	return Fixed(q)
+}
+
+// MulInt returns x * n.
+func (x Fixed) MulInt(n int) Fixed {
+	return x * Fixed(n)
+}
+
+// DivInt returns x / n, rounded toward zero.
+//
+// DivInt panics if n is 0.
+func (x Fixed) DivInt(n int) Fixed {
+	return x / Fixed(n)
+}
+
+// Lerp returns the linear interpolation between x and y by t, that is x + (y - x) * t.
+func (x Fixed) Lerp(y, t Fixed) Fixed {
+	return x + (y - x).Mul(t)
+}
+
+// Clamp returns x clamped to [min, max].
+func (x Fixed) Clamp(min, max Fixed) Fixed {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
+}
+
+// String returns a decimal representation of x.
+func (x Fixed) String() string {
+	return strconv.FormatFloat(x.Float64(), 'f', -1, 64)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The result is 8 bytes in big endian.
+func (x Fixed) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(x))
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (x *Fixed) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return errors.New("fixedpoint: invalid length of the data")
+	}
+	*x = Fixed(binary.BigEndian.Uint64(data))
+	return nil
+}
+
+// Min returns the smaller of x and y.
+func (x Fixed) Min(y Fixed) Fixed {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+// Max returns the larger of x and y.
+func (x Fixed) Max(y Fixed) Fixed {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+// Sqrt returns the square root of x, rounded toward zero.
+//
+// Sqrt panics if x is negative.
+func Sqrt(x Fixed) Fixed {
+	if x < 0 {
+		panic("fixedpoint: square root of a negative number")
+	}
+	if x == 0 {
+		return 0
+	}
+	// The result is the integer square root of x * 2^32 as a 128-bit integer.
+	hi, lo := uint64(x)>>(64-fracBits), uint64(x)<<fracBits
+	n := 64 - bits.LeadingZeros64(lo)
+	if hi != 0 {
+		n = 128 - bits.LeadingZeros64(hi)
+	}
+	// Start from a value not less than the square root, and apply Newton's method.
+	y := uint64(1) << uint((n+1)/2)
+	for {
+		q, _ := bits.Div64(hi, lo, y)
+		z := (y + q) / 2
+		if z >= y {
+			return Fixed(y)
+		}
+		y = z
+	}
+}
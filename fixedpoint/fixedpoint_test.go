@@ -0,0 +1,144 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixedpoint_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/fixedpoint"
+)
+
+func TestMulDiv(t *testing.T) {
+	cases := []struct {
+		X   float64
+		Y   float64
+		Mul float64
+		Div float64
+	}{
+		{X: 1.5, Y: 2, Mul: 3, Div: 0.75},
+		{X: -1.5, Y: 2, Mul: -3, Div: -0.75},
+		{X: -1.5, Y: -0.5, Mul: 0.75, Div: 3},
+		{X: 12345.25, Y: 0.125, Mul: 1543.15625, Div: 98762},
+		{X: 0, Y: -3, Mul: 0, Div: 0},
+	}
+	for _, c := range cases {
+		x, y := FromFloat64(c.X), FromFloat64(c.Y)
+		if got, want := x.Mul(y), FromFloat64(c.Mul); got != want {
+			t.Errorf("%v.Mul(%v): got: %v, want: %v", x, y, got, want)
+		}
+		if got, want := x.Div(y), FromFloat64(c.Div); got != want {
+			t.Errorf("%v.Div(%v): got: %v, want: %v", x, y, got, want)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	cases := []struct {
+		In    float64
+		Floor float64
+		Ceil  float64
+		Round float64
+	}{
+		{In: 1.25, Floor: 1, Ceil: 2, Round: 1},
+		{In: 1.5, Floor: 1, Ceil: 2, Round: 2},
+		{In: -1.5, Floor: -2, Ceil: -1, Round: -2},
+		{In: -1.25, Floor: -2, Ceil: -1, Round: -1},
+		{In: 3, Floor: 3, Ceil: 3, Round: 3},
+	}
+	for _, c := range cases {
+		x := FromFloat64(c.In)
+		if got, want := x.Floor(), FromFloat64(c.Floor); got != want {
+			t.Errorf("%v.Floor(): got: %v, want: %v", x, got, want)
+		}
+		if got, want := x.Ceil(), FromFloat64(c.Ceil); got != want {
+			t.Errorf("%v.Ceil(): got: %v, want: %v", x, got, want)
+		}
+		if got, want := x.Round(), FromFloat64(c.Round); got != want {
+			t.Errorf("%v.Round(): got: %v, want: %v", x, got, want)
+		}
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	for _, f := range []float64{0, 1e-9, 0.25, 1, 2, 3, 100, 12345.678, 2147483647} {
+		x := FromFloat64(f)
+		got := Sqrt(x).Float64()
+		want := math.Sqrt(x.Float64())
+		if math.Abs(got-want) > 1e-9*math.Max(1, want) {
+			t.Errorf("Sqrt(%v): got: %v, want: %v", f, got, want)
+		}
+	}
+}
+
+func TestTrig(t *testing.T) {
+	const eps = 1e-8
+	for f := -20.0; f <= 20; f += 0.01 {
+		x := FromFloat64(f)
+		if got, want := Sin(x).Float64(), math.Sin(x.Float64()); math.Abs(got-want) > eps {
+			t.Errorf("Sin(%v): got: %v, want: %v", x, got, want)
+		}
+		if got, want := Cos(x).Float64(), math.Cos(x.Float64()); math.Abs(got-want) > eps {
+			t.Errorf("Cos(%v): got: %v, want: %v", x, got, want)
+		}
+	}
+}
+
+func TestAtan2(t *testing.T) {
+	const eps = 1e-8
+	for _, y := range []float64{-1000, -3, -1, -0.001, 0, 0.001, 1, 3, 1000} {
+		for _, x := range []float64{-1000, -3, -1, -0.001, 0, 0.001, 1, 3, 1000} {
+			got := Atan2(FromFloat64(y), FromFloat64(x)).Float64()
+			want := math.Atan2(y, x)
+			if y == 0 && x == 0 {
+				want = 0
+			}
+			if math.Abs(got-want) > eps {
+				t.Errorf("Atan2(%v, %v): got: %v, want: %v", y, x, got, want)
+			}
+		}
+	}
+}
+
+func TestVec2(t *testing.T) {
+	v := V(FromInt(3), FromInt(4))
+	if got, want := v.Len(), FromInt(5); got != want {
+		t.Errorf("Len(): got: %v, want: %v", got, want)
+	}
+	// The squared length overflows, but Len doesn't.
+	big := V(FromInt(300000), FromInt(400000))
+	if got, want := big.Len(), FromInt(500000); (got - want).Abs() > FromFloat64(1e-3) {
+		t.Errorf("Len(): got: %v, want: %v", got, want)
+	}
+	r := V(One, 0).Rotate(Pi / 2)
+	if r.X.Abs() > FromFloat64(1e-8) || (r.Y-One).Abs() > FromFloat64(1e-8) {
+		t.Errorf("Rotate(Pi / 2): got: %v", r)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	v := V(FromFloat64(-1.25), FromFloat64(3.5))
+	b, err := v.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Vec2
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if got != v {
+		t.Errorf("got: %v, want: %v", got, v)
+	}
+}
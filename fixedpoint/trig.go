@@ -0,0 +1,168 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixedpoint
+
+import (
+	"math/bits"
+)
+
+const (
+	// Pi is π, rounded to the nearest.
+	Pi Fixed = 13493037705
+
+	twoPi     Fixed = 26986075409
+	halfPi    Fixed = 6746518852
+	quarterPi Fixed = 3373259426
+)
+
+// atanTable is atan(2^-i) for the CORDIC iterations.
+var atanTable = [...]Fixed{
+	3373259426, 1991351318, 1052175346, 534100635, 268086748, 134174063, 67103403, 33553749,
+	16777131, 8388597, 4194303, 2097152, 1048576, 524288, 262144, 131072,
+	65536, 32768, 16384, 8192, 4096, 2048, 1024, 512,
+	256, 128, 64, 32, 16, 8, 4, 2,
+	1,
+}
+
+// sinReduced returns sin(x) for x in [-π/4, π/4] by the Taylor series in Horner's form.
+func sinReduced(x Fixed) Fixed {
+	x2 := x.Mul(x)
+	s := One
+	for _, n := range [...]int{10 * 11, 8 * 9, 6 * 7, 4 * 5, 2 * 3} {
+		s = One - x2.Mul(s).DivInt(n)
+	}
+	return x.Mul(s)
+}
+
+// cosReduced returns cos(x) for x in [-π/4, π/4] by the Taylor series in Horner's form.
+func cosReduced(x Fixed) Fixed {
+	x2 := x.Mul(x)
+	c := One
+	for _, n := range [...]int{11 * 12, 9 * 10, 7 * 8, 5 * 6, 3 * 4, 1 * 2} {
+		c = One - x2.Mul(c).DivInt(n)
+	}
+	return c
+}
+
+// reduce returns the quadrant n in [0, 4) and r in [-π/4, π/4] where x = 2πk + nπ/2 + r.
+func reduce(x Fixed) (int, Fixed) {
+	x %= twoPi
+	if x < 0 {
+		x += twoPi
+	}
+	n := int((x + quarterPi) / halfPi)
+	return n % 4, x - halfPi.MulInt(n)
+}
+
+// Sin returns the sine of the radian argument x.
+//
+// The error is about 1e-9. The error increases for a large |x| since π has a limited precision.
+func Sin(x Fixed) Fixed {
+	n, r := reduce(x)
+	switch n {
+	case 0:
+		return sinReduced(r)
+	case 1:
+		return cosReduced(r)
+	case 2:
+		return -sinReduced(r)
+	default:
+		return -cosReduced(r)
+	}
+}
+
+// Cos returns the cosine of the radian argument x.
+//
+// The error is about 1e-9. The error increases for a large |x| since π has a limited precision.
+func Cos(x Fixed) Fixed {
+	n, r := reduce(x)
+	switch n {
+	case 0:
+		return cosReduced(r)
+	case 1:
+		return -sinReduced(r)
+	case 2:
+		return -cosReduced(r)
+	default:
+		return sinReduced(r)
+	}
+}
+
+// Tan returns the tangent of the radian argument x.
+//
+// Tan panics if the result overflows.
+func Tan(x Fixed) Fixed {
+	return Sin(x).Div(Cos(x))
+}
+
+// Atan2 returns the arc tangent of y/x in [-π, π], using the signs of the two to determine the quadrant.
+// Atan2 returns 0 if both x and y are 0.
+//
+// The error is about 1e-9.
+func Atan2(y, x Fixed) Fixed {
+	if y == 0 {
+		if x < 0 {
+			return Pi
+		}
+		return 0
+	}
+
+	var offset Fixed
+	if x < 0 {
+		// Rotate the vector by π so that x is positive.
+		if y < 0 {
+			offset = -Pi
+		} else {
+			offset = Pi
+		}
+		x, y = -x, -y
+	}
+
+	// Scale the vector so that the CORDIC iterations keep the precision without overflows.
+	// The iterations make the length about 1.65 times longer.
+	m := uint64(x.Abs())
+	if my := uint64(y.Abs()); my > m {
+		m = my
+	}
+	if s := bits.LeadingZeros64(m) - 3; s > 0 {
+		x <<= uint(s)
+		y <<= uint(s)
+	} else if s < 0 {
+		x >>= uint(-s)
+		y >>= uint(-s)
+	}
+
+	// Rotate the vector toward the x axis with the CORDIC vectoring mode, accumulating the angle.
+	var z Fixed
+	for i, a := range atanTable {
+		if y > 0 {
+			x, y = x+y>>uint(i), y-x>>uint(i)
+			z += a
+		} else {
+			x, y = x-y>>uint(i), y+x>>uint(i)
+			z -= a
+		}
+	}
+	z += offset
+	if z > Pi {
+		z -= twoPi
+	}
+	return z
+}
+
+// Atan returns the arc tangent of x in [-π/2, π/2].
+func Atan(x Fixed) Fixed {
+	return Atan2(x, One)
+}
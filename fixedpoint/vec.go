@@ -0,0 +1,130 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixedpoint
+
+import (
+	"errors"
+)
+
+// Vec2 is a 2D vector of Fixed values.
+type Vec2 struct {
+	X Fixed
+	Y Fixed
+}
+
+// V returns a Vec2 of (x, y).
+func V(x, y Fixed) Vec2 {
+	return Vec2{X: x, Y: y}
+}
+
+// Add returns v + w.
+func (v Vec2) Add(w Vec2) Vec2 {
+	return Vec2{v.X + w.X, v.Y + w.Y}
+}
+
+// Sub returns v - w.
+func (v Vec2) Sub(w Vec2) Vec2 {
+	return Vec2{v.X - w.X, v.Y - w.Y}
+}
+
+// Neg returns -v.
+func (v Vec2) Neg() Vec2 {
+	return Vec2{-v.X, -v.Y}
+}
+
+// Scale returns v scaled by s.
+func (v Vec2) Scale(s Fixed) Vec2 {
+	return Vec2{v.X.Mul(s), v.Y.Mul(s)}
+}
+
+// Dot returns the dot product of v and w.
+func (v Vec2) Dot(w Vec2) Fixed {
+	return v.X.Mul(w.X) + v.Y.Mul(w.Y)
+}
+
+// Cross returns the z component of the cross product of v and w.
+func (v Vec2) Cross(w Vec2) Fixed {
+	return v.X.Mul(w.Y) - v.Y.Mul(w.X)
+}
+
+// LenSq returns the squared length of v.
+//
+// Note that the squared length overflows when the length is about 46340 or more.
+func (v Vec2) LenSq() Fixed {
+	return v.Dot(v)
+}
+
+// Len returns the length of v.
+//
+// Len doesn't overflow as long as the length is in the range of Fixed.
+func (v Vec2) Len() Fixed {
+	// Scale the vector down to avoid overflows of the squared length.
+	m := v.X.Abs().Max(v.Y.Abs())
+	if m == 0 {
+		return 0
+	}
+	if m < One {
+		return Sqrt(v.LenSq())
+	}
+	x, y := v.X.Div(m), v.Y.Div(m)
+	return Sqrt(x.Mul(x) + y.Mul(y)).Mul(m)
+}
+
+// Normalize returns the unit vector in the direction of v. Normalize returns a zero vector if v is a zero vector.
+func (v Vec2) Normalize() Vec2 {
+	l := v.Len()
+	if l == 0 {
+		return Vec2{}
+	}
+	return Vec2{v.X.Div(l), v.Y.Div(l)}
+}
+
+// Rotate returns v rotated by the radian angle counterclockwise in the Y-up coordinates, that is clockwise on
+// the screen.
+func (v Vec2) Rotate(angle Fixed) Vec2 {
+	s, c := Sin(angle), Cos(angle)
+	return Vec2{
+		X: v.X.Mul(c) - v.Y.Mul(s),
+		Y: v.X.Mul(s) + v.Y.Mul(c),
+	}
+}
+
+// Angle returns the angle of v from the X axis in [-π, π].
+func (v Vec2) Angle() Fixed {
+	return Atan2(v.Y, v.X)
+}
+
+// Lerp returns the linear interpolation between v and w by t.
+func (v Vec2) Lerp(w Vec2, t Fixed) Vec2 {
+	return Vec2{v.X.Lerp(w.X, t), v.Y.Lerp(w.Y, t)}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The result is 16 bytes of X and Y in big endian.
+func (v Vec2) MarshalBinary() ([]byte, error) {
+	x, _ := v.X.MarshalBinary()
+	y, _ := v.Y.MarshalBinary()
+	return append(x, y...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (v *Vec2) UnmarshalBinary(data []byte) error {
+	if len(data) != 16 {
+		return errors.New("fixedpoint: invalid length of the data")
+	}
+	if err := v.X.UnmarshalBinary(data[:8]); err != nil {
+		return err
+	}
+	return v.Y.UnmarshalBinary(data[8:])
+}
@@ -0,0 +1,342 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package focusutil provides a minimal focus manager for UI widgets.
+//
+// The focus manager handles the tab order, the spatial navigation by arrow keys or gamepads,
+// and the activation and cancellation of the focused widget.
+// UI libraries can share the same navigation behavior by using this package.
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package focusutil
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/inpututil"
+)
+
+// Focusable represents a widget that can be focused.
+type Focusable interface {
+	// FocusRect returns the region of the widget on the screen.
+	// FocusRect is used for the spatial navigation and for rendering a focus indicator.
+	FocusRect() image.Rectangle
+}
+
+// Direction represents a direction of the spatial navigation.
+type Direction int
+
+const (
+	DirectionUp Direction = iota
+	DirectionDown
+	DirectionLeft
+	DirectionRight
+)
+
+// Event represents an event that happens on the focused widget.
+type Event int
+
+const (
+	// EventNone represents no event.
+	EventNone Event = iota
+
+	// EventFocusChanged represents that the focused widget is changed.
+	EventFocusChanged
+
+	// EventActivate represents that the focused widget is activated, e.g., by Enter key.
+	EventActivate
+
+	// EventCancel represents that the navigation is canceled, e.g., by Escape key.
+	EventCancel
+)
+
+const (
+	repeatDelay    = 30
+	repeatInterval = 5
+
+	// axisThreshold is the threshold of gamepad axes to be treated as pressed.
+	axisThreshold = 0.5
+)
+
+// Manager manages the focus of widgets.
+//
+// Use NewManager to create a Manager.
+type Manager struct {
+	widgets []Focusable
+	focused int
+
+	// axisDurations is the durations of a gamepad axis tilted in each direction.
+	axisDurations [4]int
+
+	// GamepadID is the ID of the gamepad used for the navigation.
+	// If GamepadID is negative, gamepads are not used.
+	GamepadID int
+
+	// GamepadActivateButton and GamepadCancelButton are the gamepad buttons to activate the focused
+	// widget and to cancel the navigation.
+	GamepadActivateButton ebiten.GamepadButton
+	GamepadCancelButton   ebiten.GamepadButton
+}
+
+// NewManager returns a new Manager.
+//
+// The gamepad 0 is used for the navigation with its axes 0 and 1, the button 0 for activation and the button 1
+// for cancellation by default.
+func NewManager() *Manager {
+	return &Manager{
+		GamepadID:             0,
+		GamepadActivateButton: ebiten.GamepadButton0,
+		GamepadCancelButton:   ebiten.GamepadButton1,
+	}
+}
+
+// Add appends the widget at the end of the tab order.
+//
+// The first added widget is focused initially.
+func (m *Manager) Add(widget Focusable) {
+	m.widgets = append(m.widgets, widget)
+}
+
+// Remove removes the widget.
+//
+// If the removed widget is focused, the next widget in the tab order is focused.
+func (m *Manager) Remove(widget Focusable) {
+	for i, w := range m.widgets {
+		if w != widget {
+			continue
+		}
+		m.widgets = append(m.widgets[:i], m.widgets[i+1:]...)
+		if i < m.focused {
+			m.focused--
+		}
+		if m.focused >= len(m.widgets) {
+			m.focused = 0
+		}
+		return
+	}
+}
+
+// Clear removes all the widgets.
+func (m *Manager) Clear() {
+	m.widgets = nil
+	m.focused = 0
+}
+
+// Focused returns the focused widget.
+//
+// Focused returns nil if there are no widgets.
+func (m *Manager) Focused() Focusable {
+	if len(m.widgets) == 0 {
+		return nil
+	}
+	return m.widgets[m.focused]
+}
+
+// IsFocused reports whether the widget is focused.
+func (m *Manager) IsFocused(widget Focusable) bool {
+	return m.Focused() == widget
+}
+
+// FocusRect returns the region of the focused widget.
+//
+// FocusRect returns false as the second value if there are no widgets.
+func (m *Manager) FocusRect() (image.Rectangle, bool) {
+	w := m.Focused()
+	if w == nil {
+		return image.Rectangle{}, false
+	}
+	return w.FocusRect(), true
+}
+
+// SetFocus focuses the given widget.
+//
+// SetFocus does nothing if the widget is not added.
+func (m *Manager) SetFocus(widget Focusable) {
+	for i, w := range m.widgets {
+		if w == widget {
+			m.focused = i
+			return
+		}
+	}
+}
+
+// FocusNext focuses the next widget in the tab order.
+func (m *Manager) FocusNext() {
+	if len(m.widgets) == 0 {
+		return
+	}
+	m.focused = (m.focused + 1) % len(m.widgets)
+}
+
+// FocusPrev focuses the previous widget in the tab order.
+func (m *Manager) FocusPrev() {
+	if len(m.widgets) == 0 {
+		return
+	}
+	m.focused = (m.focused + len(m.widgets) - 1) % len(m.widgets)
+}
+
+// Move focuses the nearest widget in the given direction from the focused widget.
+//
+// Move returns false if there is no widget in the direction.
+func (m *Manager) Move(dir Direction) bool {
+	cur := m.Focused()
+	if cur == nil {
+		return false
+	}
+	r := cur.FocusRect()
+	cx, cy := r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2
+
+	next := -1
+	var nextScore int
+	for i, w := range m.widgets {
+		if i == m.focused {
+			continue
+		}
+		r := w.FocusRect()
+		x, y := r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2
+
+		// main is the distance along the direction, and cross is the distance across the direction.
+		var main, cross int
+		switch dir {
+		case DirectionUp:
+			main, cross = cy-y, x-cx
+		case DirectionDown:
+			main, cross = y-cy, x-cx
+		case DirectionLeft:
+			main, cross = cx-x, y-cy
+		case DirectionRight:
+			main, cross = x-cx, y-cy
+		}
+		if main <= 0 {
+			continue
+		}
+		if cross < 0 {
+			cross = -cross
+		}
+		// Prefer widgets on the same line.
+		score := main + 2*cross
+		if next == -1 || score < nextScore {
+			next = i
+			nextScore = score
+		}
+	}
+	if next == -1 {
+		return false
+	}
+	m.focused = next
+	return true
+}
+
+func repeatingKeyPressed(key ebiten.Key) bool {
+	return isRepeating(inpututil.KeyPressDuration(key))
+}
+
+func isRepeating(duration int) bool {
+	if duration == 1 {
+		return true
+	}
+	if duration >= repeatDelay && (duration-repeatDelay)%repeatInterval == 0 {
+		return true
+	}
+	return false
+}
+
+func (m *Manager) updateAxes() {
+	var x, y float64
+	if m.GamepadID >= 0 {
+		x = ebiten.GamepadAxis(m.GamepadID, 0)
+		y = ebiten.GamepadAxis(m.GamepadID, 1)
+	}
+	tilted := [4]bool{
+		DirectionUp:    y < -axisThreshold,
+		DirectionDown:  y > axisThreshold,
+		DirectionLeft:  x < -axisThreshold,
+		DirectionRight: x > axisThreshold,
+	}
+	for i, t := range tilted {
+		if t {
+			m.axisDurations[i]++
+		} else {
+			m.axisDurations[i] = 0
+		}
+	}
+}
+
+func (m *Manager) isDirectionPressed(dir Direction) bool {
+	var key ebiten.Key
+	switch dir {
+	case DirectionUp:
+		key = ebiten.KeyUp
+	case DirectionDown:
+		key = ebiten.KeyDown
+	case DirectionLeft:
+		key = ebiten.KeyLeft
+	case DirectionRight:
+		key = ebiten.KeyRight
+	}
+	if repeatingKeyPressed(key) {
+		return true
+	}
+	return isRepeating(m.axisDurations[dir])
+}
+
+// Update updates the focus by the inputs and returns the event happened in this frame.
+//
+// Tab and Shift+Tab move the focus in the tab order. Arrow keys and the gamepad axes move the focus spatially.
+// Enter and Space activate the focused widget, and Escape cancels the navigation.
+//
+// Update must be called once every frame.
+func (m *Manager) Update() Event {
+	m.updateAxes()
+
+	if len(m.widgets) == 0 {
+		return EventNone
+	}
+
+	if m.GamepadID >= 0 {
+		if inpututil.IsGamepadButtonJustPressed(m.GamepadID, m.GamepadActivateButton) {
+			return EventActivate
+		}
+		if inpututil.IsGamepadButtonJustPressed(m.GamepadID, m.GamepadCancelButton) {
+			return EventCancel
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		return EventActivate
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return EventCancel
+	}
+
+	if repeatingKeyPressed(ebiten.KeyTab) {
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			m.FocusPrev()
+		} else {
+			m.FocusNext()
+		}
+		return EventFocusChanged
+	}
+
+	for _, dir := range []Direction{DirectionUp, DirectionDown, DirectionLeft, DirectionRight} {
+		if !m.isDirectionPressed(dir) {
+			continue
+		}
+		if m.Move(dir) {
+			return EventFocusChanged
+		}
+	}
+	return EventNone
+}
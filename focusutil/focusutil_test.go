@@ -0,0 +1,194 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package focusutil
+
+import (
+	"image"
+	"testing"
+)
+
+type testWidget struct {
+	name string
+	rect image.Rectangle
+}
+
+func (w *testWidget) FocusRect() image.Rectangle {
+	return w.rect
+}
+
+func newTestWidget(name string, x, y int) *testWidget {
+	return &testWidget{
+		name: name,
+		rect: image.Rect(x, y, x+10, y+10),
+	}
+}
+
+func focusedName(m *Manager) string {
+	w := m.Focused()
+	if w == nil {
+		return ""
+	}
+	return w.(*testWidget).name
+}
+
+func TestMove(t *testing.T) {
+	// The layout of the widgets:
+	//
+	//   a b c
+	//   d   e
+	//     f
+	widgets := []*testWidget{
+		newTestWidget("a", 0, 0),
+		newTestWidget("b", 20, 0),
+		newTestWidget("c", 40, 0),
+		newTestWidget("d", 0, 20),
+		newTestWidget("e", 40, 20),
+		newTestWidget("f", 20, 40),
+	}
+
+	cases := []struct {
+		From  string
+		Dir   Direction
+		To    string
+		Moved bool
+	}{
+		{"a", DirectionRight, "b", true},
+		{"b", DirectionRight, "c", true},
+		{"c", DirectionRight, "c", false},
+		{"a", DirectionDown, "d", true},
+		{"a", DirectionUp, "a", false},
+		{"d", DirectionRight, "e", true},
+		{"e", DirectionLeft, "d", true},
+		{"b", DirectionDown, "f", true},
+		{"f", DirectionUp, "b", true},
+		{"e", DirectionUp, "c", true},
+		{"f", DirectionDown, "f", false},
+	}
+	for _, c := range cases {
+		m := NewManager()
+		for _, w := range widgets {
+			m.Add(w)
+		}
+		for _, w := range widgets {
+			if w.name == c.From {
+				m.SetFocus(w)
+			}
+		}
+		moved := m.Move(c.Dir)
+		if got := focusedName(m); got != c.To || moved != c.Moved {
+			t.Errorf("Move(%d) from %q: got: (%q, %t), want: (%q, %t)", c.Dir, c.From, got, moved, c.To, c.Moved)
+		}
+	}
+}
+
+func TestTabOrder(t *testing.T) {
+	a, b, c := newTestWidget("a", 0, 0), newTestWidget("b", 20, 0), newTestWidget("c", 40, 0)
+
+	cases := []struct {
+		Name string
+		Op   func(m *Manager)
+		Want string
+	}{
+		{
+			Name: "initial",
+			Op:   func(m *Manager) {},
+			Want: "a",
+		},
+		{
+			Name: "next",
+			Op: func(m *Manager) {
+				m.FocusNext()
+			},
+			Want: "b",
+		},
+		{
+			Name: "next wraps",
+			Op: func(m *Manager) {
+				m.SetFocus(c)
+				m.FocusNext()
+			},
+			Want: "a",
+		},
+		{
+			Name: "prev wraps",
+			Op: func(m *Manager) {
+				m.FocusPrev()
+			},
+			Want: "c",
+		},
+		{
+			Name: "remove focused",
+			Op: func(m *Manager) {
+				m.SetFocus(b)
+				m.Remove(b)
+			},
+			Want: "c",
+		},
+		{
+			Name: "remove last focused",
+			Op: func(m *Manager) {
+				m.SetFocus(c)
+				m.Remove(c)
+			},
+			Want: "a",
+		},
+		{
+			Name: "remove before focused",
+			Op: func(m *Manager) {
+				m.SetFocus(c)
+				m.Remove(a)
+			},
+			Want: "c",
+		},
+		{
+			Name: "clear",
+			Op: func(m *Manager) {
+				m.Clear()
+			},
+			Want: "",
+		},
+	}
+	for _, tc := range cases {
+		m := NewManager()
+		m.Add(a)
+		m.Add(b)
+		m.Add(c)
+		tc.Op(m)
+		if got := focusedName(m); got != tc.Want {
+			t.Errorf("%s: got: %q, want: %q", tc.Name, got, tc.Want)
+		}
+	}
+}
+
+func TestIsRepeating(t *testing.T) {
+	cases := []struct {
+		Duration int
+		Want     bool
+	}{
+		{0, false},
+		{1, true},
+		{2, false},
+		{repeatDelay - 1, false},
+		{repeatDelay, true},
+		{repeatDelay + 1, false},
+		{repeatDelay + repeatInterval, true},
+		{repeatDelay + 2*repeatInterval, true},
+	}
+	for _, c := range cases {
+		if got := isRepeating(c.Duration); got != c.Want {
+			t.Errorf("isRepeating(%d): got: %t, want: %t", c.Duration, got, c.Want)
+		}
+	}
+}
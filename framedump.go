@@ -0,0 +1,42 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+// StartFrameDump starts dumping the screen into numbered PNG files in the directory dir once every everyNFrames
+// frames. This is useful to make high-quality videos like trailers without realtime encoders.
+//
+// The files are named like frame_000000.png, and the number is incremented for each dumped frame.
+// The directory is created if it doesn't exist. Frames whose drawing is skipped are not counted.
+//
+// The files are encoded and written asynchronously. If encoding can't catch up with the game, the game waits for
+// the queue so that no frame is dropped.
+//
+// StartFrameDump is not supported on browsers and mobiles, and returns an error there.
+//
+// StartFrameDump is concurrent-safe.
+func StartFrameDump(dir string, everyNFrames int) error {
+	return startFrameDump(dir, everyNFrames)
+}
+
+// StopFrameDump stops dumping the screen started by StartFrameDump.
+// StopFrameDump waits until all the queued frames are written, and returns the first error that happened while
+// writing files, if any.
+//
+// StopFrameDump does nothing if the frame dump is not started.
+//
+// StopFrameDump is concurrent-safe.
+func StopFrameDump() error {
+	return stopFrameDump()
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !android
+// +build !js
+// +build !ios
+
+package ebiten
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// frameDumpQueueSize is the number of the frames that can wait for encoding.
+const frameDumpQueueSize = 8
+
+type frameDumper struct {
+	dir   string
+	every int
+	count int
+	index int
+
+	frames chan frameDumpFrame
+	done   chan struct{}
+	err    error
+
+	m sync.Mutex
+}
+
+type frameDumpFrame struct {
+	img   *image.RGBA
+	index int
+}
+
+var theFrameDumper *frameDumper
+
+var frameDumperM sync.Mutex
+
+func startFrameDump(dir string, everyNFrames int) error {
+	if everyNFrames < 1 {
+		panic("ebiten: everyNFrames must be positive")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	frameDumperM.Lock()
+	defer frameDumperM.Unlock()
+	if theFrameDumper != nil {
+		return fmt.Errorf("ebiten: the frame dump is already started")
+	}
+	d := &frameDumper{
+		dir:    dir,
+		every:  everyNFrames,
+		frames: make(chan frameDumpFrame, frameDumpQueueSize),
+		done:   make(chan struct{}),
+	}
+	go d.loop()
+	theFrameDumper = d
+	return nil
+}
+
+func stopFrameDump() error {
+	frameDumperM.Lock()
+	d := theFrameDumper
+	theFrameDumper = nil
+	frameDumperM.Unlock()
+
+	if d == nil {
+		return nil
+	}
+	close(d.frames)
+	<-d.done
+
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.err
+}
+
+func (d *frameDumper) loop() {
+	defer close(d.done)
+	for f := range d.frames {
+		if err := d.write(f); err != nil {
+			d.m.Lock()
+			if d.err == nil {
+				d.err = err
+			}
+			d.m.Unlock()
+		}
+	}
+}
+
+func (d *frameDumper) write(f frameDumpFrame) error {
+	file, err := os.Create(filepath.Join(d.dir, fmt.Sprintf("frame_%06d.png", f.index)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Compressing PNGs is the bottleneck. Prefer speed since the files are intermediate.
+	e := &png.Encoder{CompressionLevel: png.BestSpeed}
+	return e.Encode(file, f.img)
+}
+
+// dumpFrame dumps the screen if needed. dumpFrame must be called after the screen is drawn.
+func dumpFrame(screen *Image) error {
+	frameDumperM.Lock()
+	defer frameDumperM.Unlock()
+
+	d := theFrameDumper
+	if d == nil {
+		return nil
+	}
+
+	d.count++
+	if (d.count-1)%d.every != 0 {
+		return nil
+	}
+
+	w, h := screen.Size()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	// Reading the first pixel reads all the pixels from GPU, and the rest are read from the cache.
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			r, g, b, a, err := screen.buffered.At(i, j)
+			if err != nil {
+				return err
+			}
+			p := img.Pix[4*(j*w+i):]
+			p[0], p[1], p[2], p[3] = r, g, b, a
+		}
+	}
+
+	// The queue is bounded. This blocks when encoding can't catch up so that no frame is dropped.
+	d.frames <- frameDumpFrame{
+		img:   img,
+		index: d.index,
+	}
+	d.index++
+	return nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build android js ios
+
+package ebiten
+
+import (
+	"errors"
+)
+
+func startFrameDump(dir string, everyNFrames int) error {
+	return errors.New("ebiten: StartFrameDump is not supported on this environment")
+}
+
+func stopFrameDump() error {
+	return nil
+}
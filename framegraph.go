@@ -0,0 +1,109 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"sync"
+	"time"
+)
+
+// FramePass represents a rendering pass of a frame.
+//
+// A frame consists of these passes in this order. Some passes are omitted when they are not needed.
+//
+//   - "update": The game's update function draws the logical screen (offscreen). This can run multiple times in a
+//     frame, or zero times when no tick is due.
+//   - "draw": The game's Draw draws the logical screen when the game implements GameDrawer.
+//   - "colorvision": The color vision filter is applied to an intermediate image.
+//   - "presenthook": The present hook set by SetPresentHook processes the screen.
+//   - "blit": The final image is scaled and drawn onto the screen framebuffer.
+//   - "flush": The queued commands are sent to the GPU.
+type FramePass struct {
+	// Name is the name of the pass.
+	Name string
+
+	// Inputs is the names of the passes whose results this pass reads.
+	Inputs []string
+
+	// Width and Height are the size of the destination of the pass in pixels.
+	Width  int
+	Height int
+
+	// Count is the number of times the pass ran in the frame.
+	Count int
+
+	// Duration is the total CPU time of the pass in the frame.
+	//
+	// As drawing commands are queued and sent to the GPU at the flush pass, Duration of the other passes doesn't
+	// include the GPU time.
+	Duration time.Duration
+}
+
+type frameGraph struct {
+	passes []FramePass
+	last   []FramePass
+	m      sync.Mutex
+}
+
+var theFrameGraph = &frameGraph{}
+
+func (f *frameGraph) begin() {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.passes = f.passes[:0]
+}
+
+func (f *frameGraph) record(name string, inputs []string, width, height int, start time.Time) {
+	d := time.Since(start)
+
+	f.m.Lock()
+	defer f.m.Unlock()
+	for i := range f.passes {
+		if f.passes[i].Name == name {
+			f.passes[i].Count++
+			f.passes[i].Duration += d
+			f.passes[i].Width, f.passes[i].Height = width, height
+			return
+		}
+	}
+	f.passes = append(f.passes, FramePass{
+		Name:     name,
+		Inputs:   inputs,
+		Width:    width,
+		Height:   height,
+		Count:    1,
+		Duration: d,
+	})
+}
+
+func (f *frameGraph) end() {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.last = append(f.last[:0], f.passes...)
+}
+
+// LastFrameGraph returns the rendering passes of the last frame in the order of execution.
+//
+// LastFrameGraph is useful to understand where the draws land and how long each pass takes.
+//
+// LastFrameGraph is concurrent-safe.
+func LastFrameGraph() []FramePass {
+	f := theFrameGraph
+	f.m.Lock()
+	defer f.m.Unlock()
+	ps := make([]FramePass, len(f.last))
+	copy(ps, f.last)
+	return ps
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"sync"
+)
+
+// GamepadProvider provides the states of gamepads instead of the platform's gamepad layer.
+//
+// GamepadProvider is an extension point for external input systems like Steam Input.
+// A provider can expose the physical controllers or the action sets of such a system as gamepads,
+// so that the gamepad functions like GamepadIDs and IsGamepadButtonPressed, and the inpututil package work as
+// they are.
+type GamepadProvider interface {
+	// Update is called every tick before the game's update function.
+	// Update is the place to poll the states, e.g., by SteamInput()->RunFrame().
+	//
+	// The error returned by Update terminates the game.
+	Update() error
+
+	GamepadIDs() []int
+	GamepadSDLID(id int) string
+	GamepadName(id int) string
+	GamepadAxisNum(id int) int
+	GamepadAxis(id int, axis int) float64
+	GamepadButtonNum(id int) int
+	IsGamepadButtonPressed(id int, button GamepadButton) bool
+}
+
+// GamepadSensorProvider is an optional interface of GamepadProvider to provide the motion sensors and the touchpads
+// of gamepads.
+//
+// See GamepadGyro, GamepadAccelerometer, GamepadTouchpadTouchIDs and GamepadTouchpadPosition for the units.
+type GamepadSensorProvider interface {
+	GamepadGyro(id int) (x, y, z float64, ok bool)
+	GamepadAccelerometer(id int) (x, y, z float64, ok bool)
+	GamepadTouchpadTouchIDs(id int) []int
+	GamepadTouchpadPosition(id int, touchID int) (x, y float64)
+}
+
+var (
+	theGamepadProvider GamepadProvider
+	gamepadProviderM   sync.Mutex
+)
+
+func gamepadProvider() GamepadProvider {
+	gamepadProviderM.Lock()
+	defer gamepadProviderM.Unlock()
+	return theGamepadProvider
+}
+
+// SetGamepadProvider sets the provider of the gamepad states.
+//
+// While a provider is set, the gamepad functions return the states from the provider and the platform's gamepad
+// layer is ignored. If provider is nil, the platform's gamepad layer is used again.
+//
+// SetGamepadProvider is concurrent-safe.
+func SetGamepadProvider(provider GamepadProvider) {
+	gamepadProviderM.Lock()
+	defer gamepadProviderM.Unlock()
+	theGamepadProvider = provider
+}
+
+func updateGamepadProvider() error {
+	p := gamepadProvider()
+	if p == nil {
+		return nil
+	}
+	return p.Update()
+}
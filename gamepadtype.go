@@ -0,0 +1,106 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GamepadType represents a type of a gamepad, which is useful to show the right button glyphs.
+type GamepadType int
+
+// GamepadTypes
+const (
+	GamepadTypeUnknown GamepadType = iota
+	GamepadTypeXbox
+	GamepadTypePlayStation
+	GamepadTypeNintendoSwitchPro
+)
+
+const (
+	vendorMicrosoft = 0x045e
+	vendorSony      = 0x054c
+	vendorNintendo  = 0x057e
+
+	productNintendoSwitchPro = 0x2009
+)
+
+// usbIDsFromSDLID returns the vendor ID and the product ID embedded in the SDL GUID.
+//
+// An SDL GUID consists of 16 bytes: the bus type (2 bytes), CRC (2 bytes), the vendor ID (2 bytes), zero (2 bytes),
+// the product ID (2 bytes), and so on. Each 2-byte value is little endian.
+func usbIDsFromSDLID(sdlID string) (vendor, product int, ok bool) {
+	if len(sdlID) != 32 {
+		return 0, 0, false
+	}
+	le16 := func(s string) (int, bool) {
+		v, err := strconv.ParseUint(s[2:4]+s[0:2], 16, 16)
+		if err != nil {
+			return 0, false
+		}
+		return int(v), true
+	}
+	vendor, ok1 := le16(sdlID[8:12])
+	product, ok2 := le16(sdlID[16:20])
+	if !ok1 || !ok2 {
+		return 0, 0, false
+	}
+	return vendor, product, true
+}
+
+func gamepadTypeFromName(name string) GamepadType {
+	name = strings.ToLower(name)
+	switch {
+	case strings.Contains(name, "xbox") || strings.Contains(name, "xinput"):
+		return GamepadTypeXbox
+	case strings.Contains(name, "playstation") || strings.Contains(name, "dualshock") ||
+		strings.Contains(name, "dualsense") || strings.Contains(name, "ps3") ||
+		strings.Contains(name, "ps4") || strings.Contains(name, "ps5") ||
+		strings.Contains(name, "054c"):
+		return GamepadTypePlayStation
+	case strings.Contains(name, "pro controller") || strings.Contains(name, "057e-2009"):
+		return GamepadTypeNintendoSwitchPro
+	}
+	return GamepadTypeUnknown
+}
+
+func gamepadType(sdlID, name string) GamepadType {
+	if vendor, product, ok := usbIDsFromSDLID(sdlID); ok {
+		switch vendor {
+		case vendorMicrosoft:
+			return GamepadTypeXbox
+		case vendorSony:
+			return GamepadTypePlayStation
+		case vendorNintendo:
+			if product == productNintendoSwitchPro {
+				return GamepadTypeNintendoSwitchPro
+			}
+		}
+	}
+	// On browsers, the SDL ID is not available. The name might include the vendor and the product IDs like
+	// "054c-05c4-Wireless Controller".
+	return gamepadTypeFromName(name)
+}
+
+// DetectGamepadType returns the type of the gamepad, which is detected by the USB vendor and product IDs or the
+// name of the gamepad.
+//
+// DetectGamepadType returns GamepadTypeUnknown when the type is not detected.
+//
+// DetectGamepadType is concurrent-safe.
+func DetectGamepadType(id int) GamepadType {
+	return gamepadType(GamepadSDLID(id), GamepadName(id))
+}
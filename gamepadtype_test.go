@@ -0,0 +1,65 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func TestGamepadType(t *testing.T) {
+	cases := []struct {
+		SDLID string
+		Name  string
+		Want  GamepadType
+	}{
+		{
+			SDLID: "030000005e0400008e02000010010000",
+			Name:  "Microsoft X-Box 360 pad",
+			Want:  GamepadTypeXbox,
+		},
+		{
+			SDLID: "030000004c050000c405000011010000",
+			Name:  "Sony Interactive Entertainment Wireless Controller",
+			Want:  GamepadTypePlayStation,
+		},
+		{
+			SDLID: "050000007e0500000920000001000000",
+			Name:  "Nintendo Switch Pro Controller",
+			Want:  GamepadTypeNintendoSwitchPro,
+		},
+		{
+			SDLID: "",
+			Name:  "054c-05c4-Wireless Controller",
+			Want:  GamepadTypePlayStation,
+		},
+		{
+			SDLID: "",
+			Name:  "Xbox 360 Controller (XInput STANDARD GAMEPAD)",
+			Want:  GamepadTypeXbox,
+		},
+		{
+			SDLID: "03000000790000000600000010010000",
+			Name:  "DragonRise Inc. Generic USB Joystick",
+			Want:  GamepadTypeUnknown,
+		},
+	}
+	for _, c := range cases {
+		if got := GamepadTypeFromSDLIDAndName(c.SDLID, c.Name); got != c.Want {
+			t.Errorf("gamepadType(%q, %q): got: %d, want: %d", c.SDLID, c.Name, got, c.Want)
+		}
+	}
+}
@@ -61,6 +61,32 @@ func (g *GeoM) apply32(x, y float32) (x2, y2 float32) {
 	return (g.a_1+1)*x + g.b*y + g.tx, g.c*x + (g.d_1+1)*y + g.ty
 }
 
+func round32(x float32) float32 {
+	return float32(math.Floor(float64(x) + 0.5))
+}
+
+// snapped returns a GeoM that maps the rectangle (0, 0)-(width, height) onto the nearest integer positions.
+//
+// If the GeoM rotates or skews, only the translation is snapped.
+func (g *GeoM) snapped(width, height float32) GeoM {
+	r := *g
+	if g.b != 0 || g.c != 0 || width == 0 || height == 0 {
+		r.tx = round32(g.tx)
+		r.ty = round32(g.ty)
+		return r
+	}
+
+	x0 := round32(g.tx)
+	x1 := round32(g.tx + (g.a_1+1)*width)
+	y0 := round32(g.ty)
+	y1 := round32(g.ty + (g.d_1+1)*height)
+	r.a_1 = (x1-x0)/width - 1
+	r.d_1 = (y1-y0)/height - 1
+	r.tx = x0
+	r.ty = y0
+	return r
+}
+
 func (g *GeoM) elements() (a, b, c, d, tx, ty float32) {
 	return g.a_1 + 1, g.b, g.c, g.d_1 + 1, g.tx, g.ty
 }
@@ -0,0 +1,251 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image/color"
+	"math"
+	"sync"
+)
+
+// GradientStop represents a color at a position of a gradient.
+type GradientStop struct {
+	// Offset is the position of the stop in [0, 1].
+	Offset float64
+
+	// Color is the color at the stop.
+	Color color.Color
+}
+
+// Gradient represents a gradient to fill an image with.
+//
+// Gradient is implemented by *LinearGradient and *RadialGradient.
+//
+// Note that this API is experimental.
+type Gradient interface {
+	appendBands(bands []gradientBand, width, height int) []gradientBand
+	stops() []GradientStop
+}
+
+// LinearGradient represents a linear gradient from (X0, Y0) to (X1, Y1).
+//
+// The colors before the first stop and after the last stop are the colors of the stops.
+//
+// Note that this API is experimental.
+type LinearGradient struct {
+	X0, Y0 float64
+	X1, Y1 float64
+	Stops  []GradientStop
+}
+
+// RadialGradient represents a radial gradient from the center (X, Y) to the circle of Radius.
+//
+// The colors before the first stop and after the last stop are the colors of the stops.
+//
+// Note that this API is experimental.
+type RadialGradient struct {
+	X, Y   float64
+	Radius float64
+	Stops  []GradientStop
+}
+
+// gradientBand is a quadrilateral whose colors are interpolated between the two edges.
+type gradientBand struct {
+	// xs and ys are the vertices in the order of p0, p1, p2 and p3.
+	// The edge (p0, p1) has the color c0 and the edge (p2, p3) has the color c1.
+	xs, ys [4]float64
+	c0, c1 color.Color
+}
+
+func (g *LinearGradient) stops() []GradientStop {
+	return g.Stops
+}
+
+func (g *LinearGradient) appendBands(bands []gradientBand, width, height int) []gradientBand {
+	dx, dy := g.X1-g.X0, g.Y1-g.Y0
+	if dx == 0 && dy == 0 {
+		return bands
+	}
+
+	// l is long enough to cover the whole image from any point in the image.
+	l := math.Hypot(float64(width), float64(height)) + math.Hypot(g.X0, g.Y0) + math.Hypot(g.X1, g.Y1)
+	d := math.Hypot(dx, dy)
+	// (nx, ny) is the normal of the gradient direction with the length l.
+	nx, ny := -dy/d*l, dx/d*l
+
+	pos := func(t float64) (float64, float64) {
+		return g.X0 + dx*t, g.Y0 + dy*t
+	}
+	band := func(t0, t1 float64, c0, c1 color.Color) gradientBand {
+		x0, y0 := pos(t0)
+		x1, y1 := pos(t1)
+		return gradientBand{
+			xs: [4]float64{x0 - nx, x0 + nx, x1 - nx, x1 + nx},
+			ys: [4]float64{y0 - ny, y0 + ny, y1 - ny, y1 + ny},
+			c0: c0,
+			c1: c1,
+		}
+	}
+
+	s := g.Stops
+	pad := l / d
+	bands = append(bands, band(s[0].Offset-pad, s[0].Offset, s[0].Color, s[0].Color))
+	for i := 0; i < len(s)-1; i++ {
+		bands = append(bands, band(s[i].Offset, s[i+1].Offset, s[i].Color, s[i+1].Color))
+	}
+	last := s[len(s)-1]
+	bands = append(bands, band(last.Offset, last.Offset+pad, last.Color, last.Color))
+	return bands
+}
+
+// radialGradientSegments is the number of the segments to approximate a circle.
+const radialGradientSegments = 64
+
+func (g *RadialGradient) stops() []GradientStop {
+	return g.Stops
+}
+
+func (g *RadialGradient) appendBands(bands []gradientBand, width, height int) []gradientBand {
+	if g.Radius <= 0 {
+		return bands
+	}
+
+	ring := func(t0, t1 float64, c0, c1 color.Color) {
+		r0, r1 := g.Radius*t0, g.Radius*t1
+		for i := 0; i < radialGradientSegments; i++ {
+			a0 := 2 * math.Pi * float64(i) / radialGradientSegments
+			a1 := 2 * math.Pi * float64(i+1) / radialGradientSegments
+			s0, c0a := math.Sincos(a0)
+			s1, c1a := math.Sincos(a1)
+			bands = append(bands, gradientBand{
+				xs: [4]float64{g.X + r0*c0a, g.X + r0*c1a, g.X + r1*c0a, g.X + r1*c1a},
+				ys: [4]float64{g.Y + r0*s0, g.Y + r0*s1, g.Y + r1*s0, g.Y + r1*s1},
+				c0: c0,
+				c1: c1,
+			})
+		}
+	}
+
+	s := g.Stops
+	if s[0].Offset > 0 {
+		ring(0, s[0].Offset, s[0].Color, s[0].Color)
+	}
+	for i := 0; i < len(s)-1; i++ {
+		if s[i].Offset == s[i+1].Offset {
+			continue
+		}
+		ring(s[i].Offset, s[i+1].Offset, s[i].Color, s[i+1].Color)
+	}
+	return bands
+}
+
+var (
+	gradientSource     *Image
+	gradientSourceOnce sync.Once
+)
+
+// FillGradient fills the image with the gradient g.
+//
+// The colors are interpolated in the non-premultiplied alpha space.
+//
+// If g has no stops, FillGradient fills the image with the transparent color.
+// If the offsets of the stops are not in [0, 1] or not in ascending order, FillGradient panics.
+//
+// When the image is disposed, FillGradient does nothing.
+//
+// Note that this API is experimental.
+func (i *Image) FillGradient(g Gradient) {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return
+	}
+
+	// TODO: Implement this.
+	if i.isSubImage() {
+		panic("ebiten: render to a subimage is not implemented (FillGradient)")
+	}
+
+	s := g.stops()
+	if len(s) == 0 {
+		_ = i.Clear()
+		return
+	}
+	for idx, stop := range s {
+		if stop.Offset < 0 || 1 < stop.Offset {
+			panic("ebiten: the offsets of gradient stops must be in [0, 1]")
+		}
+		if idx > 0 && stop.Offset < s[idx-1].Offset {
+			panic("ebiten: the offsets of gradient stops must be in ascending order")
+		}
+	}
+
+	// The region that no band covers has the color of the last stop.
+	_ = i.Fill(s[len(s)-1].Color)
+
+	gradientSourceOnce.Do(func() {
+		gradientSource, _ = NewImage(16, 16, FilterDefault)
+		_ = gradientSource.Fill(color.White)
+	})
+
+	w, h := i.Size()
+	bands := g.appendBands(nil, w, h)
+	if len(bands) == 0 {
+		return
+	}
+
+	op := &DrawTrianglesOptions{}
+	op.CompositeMode = CompositeModeCopy
+
+	const bandsPerDraw = MaxIndicesNum / 6
+	vs := make([]Vertex, 0, 4*bandsPerDraw)
+	is := make([]uint16, 0, 6*bandsPerDraw)
+	for len(bands) > 0 {
+		n := len(bands)
+		if n > bandsPerDraw {
+			n = bandsPerDraw
+		}
+		vs, is = vs[:0], is[:0]
+		for _, b := range bands[:n] {
+			base := uint16(len(vs))
+			r0, g0, b0, a0 := gradientColor(b.c0)
+			r1, g1, b1, a1 := gradientColor(b.c1)
+			for j := 0; j < 4; j++ {
+				v := Vertex{
+					DstX:   float32(b.xs[j]),
+					DstY:   float32(b.ys[j]),
+					SrcX:   8,
+					SrcY:   8,
+					ColorR: r0,
+					ColorG: g0,
+					ColorB: b0,
+					ColorA: a0,
+				}
+				if j >= 2 {
+					v.ColorR, v.ColorG, v.ColorB, v.ColorA = r1, g1, b1, a1
+				}
+				vs = append(vs, v)
+			}
+			is = append(is, base, base+1, base+2, base+1, base+2, base+3)
+		}
+		i.DrawTriangles(vs, is, gradientSource, op)
+		bands = bands[n:]
+	}
+}
+
+func gradientColor(clr color.Color) (float32, float32, float32, float32) {
+	c := color.NRGBA64Model.Convert(clr).(color.NRGBA64)
+	return float32(c.R) / 0xffff, float32(c.G) / 0xffff, float32(c.B) / 0xffff, float32(c.A) / 0xffff
+}
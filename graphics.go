@@ -18,6 +18,12 @@ import (
 	"github.com/hajimehoshi/ebiten/internal/driver"
 )
 
+// OutOfMemoryError is an error returned from Run or RunGame when the GPU runs out of memory to create an image.
+//
+// Before returning this error, Ebiten releases the textures that are no longer used and retries creating the image.
+// The game can detect this error with a type assertion, e.g., to suggest lower-quality assets at the next launch.
+type OutOfMemoryError = driver.OutOfMemoryError
+
 // Filter represents the type of texture filter to be used when an image is maginified or minified.
 type Filter int
 
@@ -85,4 +91,26 @@ const (
 	// Sum of source and destination (a.k.a. 'plus' or 'additive')
 	// c_out = c_src + c_dst
 	CompositeModeLighter CompositeMode = CompositeMode(driver.CompositeModeLighter)
+
+	// Difference of the destination and the source. This darkens the destination.
+	// c_out = c_dst - c_src
+	// α_out = α_dst + α_src
+	CompositeModeSubtract CompositeMode = CompositeMode(driver.CompositeModeSubtract)
+
+	// Difference of the source and the destination.
+	// c_out = c_src - c_dst
+	// α_out = α_src + α_dst
+	CompositeModeReverseSubtract CompositeMode = CompositeMode(driver.CompositeModeReverseSubtract)
+
+	// Minimum of source and destination for each component (a.k.a. 'darken')
+	// c_out = min(c_src, c_dst)
+	//
+	// On OpenGL ES 2.0 and WebGL 1 without EXT_blend_minmax, this falls back to CompositeModeSourceOver.
+	CompositeModeDarken CompositeMode = CompositeMode(driver.CompositeModeDarken)
+
+	// Maximum of source and destination for each component (a.k.a. 'lighten')
+	// c_out = max(c_src, c_dst)
+	//
+	// On OpenGL ES 2.0 and WebGL 1 without EXT_blend_minmax, this falls back to CompositeModeSourceOver.
+	CompositeModeLighten CompositeMode = CompositeMode(driver.CompositeModeLighten)
 )
@@ -22,6 +22,7 @@ import (
 	"github.com/hajimehoshi/ebiten/internal/buffered"
 	"github.com/hajimehoshi/ebiten/internal/driver"
 	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
 )
 
 // Image represents a rectangle set of pixels.
@@ -40,6 +41,10 @@ type Image struct {
 	original *Image
 
 	filter Filter
+
+	// leak is the tracker to warn when the image is not disposed explicitly. leak is nil unless the warning is
+	// enabled.
+	leak *imageLeakTracker
 }
 
 func (i *Image) copyCheck() {
@@ -90,9 +95,27 @@ func (i *Image) Fill(clr color.Color) error {
 	}
 
 	i.buffered.Fill(color.RGBAModel.Convert(clr).(color.RGBA))
+	recordFill(i)
 	return nil
 }
 
+// clipRect returns the region to render on the image for the given clipping rectangle r.
+// An empty rectangle means no clipping. clipRect returns false when nothing is rendered.
+func (i *Image) clipRect(r image.Rectangle) (image.Rectangle, bool) {
+	if r.Empty() {
+		return image.Rectangle{}, true
+	}
+	r = r.Intersect(i.Bounds())
+	if r.Empty() {
+		return image.Rectangle{}, false
+	}
+	if r == i.Bounds() {
+		// Clipping by the whole bounds is same as no clipping, and doesn't prevent merging draw commands.
+		return image.Rectangle{}, true
+	}
+	return r, true
+}
+
 // DrawImage draws the given image on the image i.
 //
 // DrawImage accepts the options. For details, see the document of
@@ -167,6 +190,9 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) error {
 				ColorM:        options.ColorM,
 				CompositeMode: options.CompositeMode,
 				Filter:        options.Filter,
+				SnapToPixels:  options.SnapToPixels,
+				ClipRect:      options.ClipRect,
+				Mask:          options.Mask,
 			}
 			op.GeoM.Scale(
 				float64(dx1-dx0)/float64(sx1-sx0),
@@ -178,6 +204,11 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) error {
 		return nil
 	}
 
+	clip, ok := i.clipRect(options.ClipRect)
+	if !ok {
+		return nil
+	}
+
 	bounds := img.Bounds()
 
 	// SourceRect is deprecated. This implementation is for backward compatibility.
@@ -189,6 +220,10 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) error {
 	}
 
 	geom := &options.GeoM
+	if options.SnapToPixels {
+		g := geom.snapped(float32(bounds.Dx()), float32(bounds.Dy()))
+		geom = &g
+	}
 	mode := driver.CompositeMode(options.CompositeMode)
 
 	filter := driver.FilterNearest
@@ -198,8 +233,22 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) error {
 		filter = driver.Filter(img.filter)
 	}
 
+	stencil := i.drawMask(options.Mask, img, clip)
+	depth := depthOf(options.DepthTest, options.Z)
+
+	var srcInset float32
+	if options.SnapToPixels && filter == driver.FilterLinear {
+		// Sample the centers of the edge texels so that the linear filter doesn't blend the texels outside of the
+		// source region, e.g., the adjacent images on the same texture atlas. This is not needed for the nearest
+		// filter, with which the snapped pixels never sample the outside.
+		srcInset = 0.5
+	}
+
 	a, b, c, d, tx, ty := geom.elements()
-	i.buffered.DrawImage(img.buffered, img.Bounds(), a, b, c, d, tx, ty, options.ColorM.impl, mode, filter)
+	if err := i.buffered.DrawImage(img.buffered, img.Bounds(), srcInset, a, b, c, d, tx, ty, options.ColorM.impl, mode, filter, clip, stencil, depth); err != nil {
+		theUIContext.setError(err)
+	}
+	recordQuad(i, float32(bounds.Dx()), float32(bounds.Dy()), a, b, c, d, tx, ty)
 	return nil
 }
 
@@ -257,6 +306,112 @@ type DrawTrianglesOptions struct {
 	// Address is a sampler address mode.
 	// The default (zero) value is AddressClampToZero.
 	Address Address
+
+	// ClipRect is the region of the destination image to render, in the destination's coordinates.
+	// Pixels outside ClipRect are never modified.
+	//
+	// The default (zero) value is an empty rectangle, which means no clipping.
+	ClipRect image.Rectangle
+
+	// Mask is the region of the destination image to render.
+	// Pixels outside Mask are never modified.
+	//
+	// The default (zero) value is nil, which means no masking.
+	Mask *Mask
+
+	// DepthTest reports whether the pixels are rendered in the order of Z instead of the order of the draw calls.
+	// See DrawImageOptions.DepthTest for details.
+	//
+	// The default (zero) value is false.
+	//
+	// Note that this API is experimental.
+	DepthTest bool
+
+	// Z is the depth of the rendered pixels in [0, 1]. A pixel with a larger Z is nearer. Z out of the range is
+	// clamped.
+	//
+	// Z is used only when DepthTest is true.
+	//
+	// The default (zero) value is 0.
+	Z float64
+}
+
+// depthOf returns the depth to render with the given options.
+func depthOf(test bool, z float64) driver.Depth {
+	if !test {
+		return driver.Depth{}
+	}
+	if z < 0 {
+		z = 0
+	}
+	if z > 1 {
+		z = 1
+	}
+	return driver.Depth{
+		Mode: driver.DepthModeTest,
+		Z:    float32(z),
+	}
+}
+
+// Mask represents an irregular-shaped region of a destination image, specified by triangles.
+//
+// Only DstX and DstY of Vertices are used, in the destination's coordinates.
+//
+// Mask is backed by a stencil buffer. Mask is not available when the destination is the screen framebuffer or
+// when Metal is used. When Metal is used, the image is rendered without the mask.
+//
+// Note that this API is experimental.
+type Mask struct {
+	// Vertices and Indices are the triangles of the region, in the same manner as DrawTriangles.
+	Vertices []Vertex
+	Indices  []uint16
+
+	// Inverted reports whether the region is outside of the triangles instead of inside.
+	Inverted bool
+}
+
+// drawMask writes the mask m to the stencil buffer of i, and returns the stencil mode to render with the mask.
+// src is an image used as a rendering source, whose pixels are never written.
+func (i *Image) drawMask(m *Mask, src *Image, clip image.Rectangle) driver.StencilMode {
+	if m == nil {
+		return driver.StencilModeNone
+	}
+	if len(m.Indices)%3 != 0 {
+		panic("ebiten: len(Mask.Indices) % 3 must be 0")
+	}
+	if len(m.Indices) > MaxIndicesNum {
+		panic("ebiten: len(Mask.Indices) must be <= MaxIndicesNum")
+	}
+
+	b := src.Bounds()
+	bx0 := float32(b.Min.X)
+	by0 := float32(b.Min.Y)
+	bx1 := float32(b.Max.X)
+	by1 := float32(b.Max.Y)
+
+	vs := make([]float32, len(m.Vertices)*graphics.VertexFloatNum)
+	for i, v := range m.Vertices {
+		vs[i*graphics.VertexFloatNum] = v.DstX
+		vs[i*graphics.VertexFloatNum+1] = v.DstY
+		vs[i*graphics.VertexFloatNum+2] = bx0
+		vs[i*graphics.VertexFloatNum+3] = by0
+		vs[i*graphics.VertexFloatNum+4] = bx0
+		vs[i*graphics.VertexFloatNum+5] = by0
+		vs[i*graphics.VertexFloatNum+6] = bx1
+		vs[i*graphics.VertexFloatNum+7] = by1
+	}
+	is := make([]uint16, len(m.Indices))
+	copy(is, m.Indices)
+
+	// The colors are not written here. Only the stencil buffer is updated.
+	if err := i.buffered.DrawTriangles(src.buffered, vs, is, nil, driver.CompositeModeSourceOver, driver.FilterNearest, driver.AddressClampToZero, clip, driver.StencilModeWrite, driver.Depth{}); err != nil {
+		theUIContext.setError(err)
+	}
+
+	if m.Inverted {
+		return driver.StencilModeTestInverted
+	}
+	return driver.StencilModeTest
 }
 
 // MaxIndicesNum is the maximum number of indices for DrawTriangles.
@@ -298,6 +453,11 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 		options = &DrawTrianglesOptions{}
 	}
 
+	clip, ok := i.clipRect(options.ClipRect)
+	if !ok {
+		return
+	}
+
 	mode := driver.CompositeMode(options.CompositeMode)
 
 	filter := driver.FilterNearest
@@ -332,7 +492,88 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 	is := make([]uint16, len(indices))
 	copy(is, indices)
 
-	i.buffered.DrawTriangles(img.buffered, vs, is, options.ColorM.impl, mode, filter, driver.Address(options.Address))
+	stencil := i.drawMask(options.Mask, img, clip)
+	depth := depthOf(options.DepthTest, options.Z)
+
+	if err := i.buffered.DrawTriangles(img.buffered, vs, is, options.ColorM.impl, mode, filter, driver.Address(options.Address), clip, stencil, depth); err != nil {
+		theUIContext.setError(err)
+	}
+	recordTriangles(i, vs, is)
+}
+
+// DrawTiledOptions represents options to render an image tiled on an image.
+//
+// Note that this API is experimental.
+type DrawTiledOptions struct {
+	// GeoM is a geometry matrix of the tiled pattern, relative to the upper-left corner of the destination
+	// rectangle. For example, a translation scrolls the pattern and a scale scales the tiles.
+	// The default (zero) value is identity, which draws the tiles in the original size from the corner.
+	GeoM GeoM
+
+	// ColorM is a color matrix to draw.
+	// The default (zero) value is identity, which doesn't change any color.
+	ColorM ColorM
+
+	// CompositeMode is a composite mode to draw.
+	// The default (zero) value is regular alpha blending.
+	CompositeMode CompositeMode
+
+	// Filter is a type of texture filter.
+	// The default (zero) value is FilterDefault.
+	Filter Filter
+}
+
+// DrawTiled draws the given image img repeatedly over the rectangle r of the image i.
+//
+// The tiles are drawn by one quadrilateral with the repeat address mode, then DrawTiled is as efficient as one
+// DrawImage call regardless of the number of the tiles. img can be a sub-image.
+//
+// If GeoM of the options is not invertible, DrawTiled does nothing.
+//
+// When the image i is disposed, DrawTiled does nothing.
+//
+// Note that this API is experimental.
+func (i *Image) DrawTiled(img *Image, r image.Rectangle, options *DrawTiledOptions) {
+	if options == nil {
+		options = &DrawTiledOptions{}
+	}
+	if r.Empty() {
+		return
+	}
+
+	g := options.GeoM
+	if !g.IsInvertible() {
+		return
+	}
+	g.Invert()
+
+	b := img.Bounds()
+	vertex := func(x, y int) Vertex {
+		sx, sy := g.Apply(float64(x-r.Min.X), float64(y-r.Min.Y))
+		return Vertex{
+			DstX:   float32(x),
+			DstY:   float32(y),
+			SrcX:   float32(sx) + float32(b.Min.X),
+			SrcY:   float32(sy) + float32(b.Min.Y),
+			ColorR: 1,
+			ColorG: 1,
+			ColorB: 1,
+			ColorA: 1,
+		}
+	}
+	vs := []Vertex{
+		vertex(r.Min.X, r.Min.Y),
+		vertex(r.Max.X, r.Min.Y),
+		vertex(r.Min.X, r.Max.Y),
+		vertex(r.Max.X, r.Max.Y),
+	}
+
+	op := &DrawTrianglesOptions{}
+	op.ColorM = options.ColorM
+	op.CompositeMode = options.CompositeMode
+	op.Filter = options.Filter
+	op.Address = AddressRepeat
+	i.DrawTriangles(vs, []uint16{0, 1, 2, 1, 2, 3}, img, op)
 }
 
 // SubImage returns an image representing the portion of the image p visible through r. The returned value shares pixels with the original image.
@@ -413,6 +654,11 @@ func (i *Image) At(x, y int) color.Color {
 // Set loads pixels from GPU to system memory if necessary, which means that Set can be slow.
 //
 // In the current implementation, successive calls of Set invokes loading pixels at most once, so this is efficient.
+// The pixels given by Set are accumulated, and only the modified region is uploaded to GPU at once before the image
+// is used next, or at the end of the frame. At returns the accumulated pixels without accessing GPU. This is useful
+// for games updating many pixels every frame, e.g., falling-sand games.
+//
+// Rendering to the image discards the loaded pixels, and the next Set loads pixels again.
 //
 // If the image is disposed, Set does nothing.
 func (i *Image) Set(x, y int, clr color.Color) {
@@ -452,6 +698,7 @@ func (i *Image) Dispose() error {
 	}
 	i.buffered.MarkDisposed()
 	i.buffered = nil
+	i.leak.markDisposed()
 	return nil
 }
 
@@ -485,6 +732,38 @@ func (i *Image) ReplacePixels(p []byte) error {
 	return nil
 }
 
+// ReplacePixelsRegion replaces the pixels of the given region of the image with p.
+//
+// The given p must represent RGBA pre-multiplied alpha values of the region. len(p) must equal to
+// 4 * (region width) * (region height). The region is in the coordinates of the image bounds, and must be within
+// the bounds.
+//
+// ReplacePixelsRegion uploads only the region, which is more efficient than ReplacePixels when a part of the image
+// is updated frequently, e.g., for dynamic tilemaps or streaming video.
+//
+// When len(p) is not appropriate or the region is out of the bounds, ReplacePixelsRegion panics.
+//
+// When the image is disposed, ReplacePixelsRegion does nothing.
+func (i *Image) ReplacePixelsRegion(p []byte, region image.Rectangle) error {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return nil
+	}
+	// TODO: Implement this.
+	if i.isSubImage() {
+		panic("ebiten: render to a subimage is not implemented (ReplacePixelsRegion)")
+	}
+	if region.Empty() || !region.In(i.Bounds()) {
+		panic(fmt.Sprintf("ebiten: region %v must be a non-empty rectangle within the bounds %v", region, i.Bounds()))
+	}
+	if l := 4 * region.Dx() * region.Dy(); len(p) != l {
+		panic(fmt.Sprintf("ebiten: len(p) was %d but must be %d", len(p), l))
+	}
+
+	return i.buffered.ReplacePixelsRegion(p, region.Min.X, region.Min.Y, region.Dx(), region.Dy())
+}
+
 // A DrawImageOptions represents options to render an image on an image.
 type DrawImageOptions struct {
 	// GeoM is a geometry matrix to draw.
@@ -511,6 +790,57 @@ type DrawImageOptions struct {
 	// Otherwise, Filter specified at DrawImageOptions is used.
 	Filter Filter
 
+	// SnapToPixels represents whether the image edges are snapped to the integer pixels of the destination.
+	//
+	// When SnapToPixels is true, the translation and the scale of GeoM are adjusted so that the rectangle of the
+	// source image is mapped onto integer pixel positions. This avoids seams and shimmering between adjacent tiles
+	// of pixel-art games at non-integer scales. If GeoM rotates or skews, only the translation is snapped.
+	//
+	// With FilterLinear, the source coordinates are also moved inward by half a texel so that the edge pixels
+	// don't blend the pixels outside of the source image, e.g., the adjacent parts of a sprite sheet.
+	//
+	// The default (zero) value is false.
+	SnapToPixels bool
+
+	// ClipRect is the region of the destination image to render, in the destination's coordinates.
+	// Pixels outside ClipRect are never modified.
+	//
+	// The default (zero) value is an empty rectangle, which means no clipping.
+	ClipRect image.Rectangle
+
+	// Mask is the region of the destination image to render.
+	// Pixels outside Mask are never modified.
+	//
+	// The default (zero) value is nil, which means no masking.
+	Mask *Mask
+
+	// DepthTest reports whether the pixels are rendered in the order of Z instead of the order of the draw calls.
+	//
+	// When DepthTest is true, a pixel is rendered only when Z is greater than or equal to the Z already written at
+	// the pixel, and then Z is written there. A depth buffer is attached to the destination image at the first draw
+	// with DepthTest, and Fill and Clear clear the depth buffer. Fully transparent pixels are not rendered and don't
+	// write Z.
+	//
+	// Translucent pixels write Z as well as opaque pixels, so translucent pixels might hide the pixels drawn later
+	// behind them. Draw opaque images first with DepthTest, and then draw translucent images from back to front.
+	// Draw calls with different Z values are not batched.
+	//
+	// The depth test is not available when the destination is the screen framebuffer or when Metal is used. When
+	// Metal is used, the pixels are rendered in the order of the draw calls and Z is ignored.
+	//
+	// The default (zero) value is false.
+	//
+	// Note that this API is experimental.
+	DepthTest bool
+
+	// Z is the depth of the rendered pixels in [0, 1]. A pixel with a larger Z is nearer. Z out of the range is
+	// clamped.
+	//
+	// Z is used only when DepthTest is true.
+	//
+	// The default (zero) value is 0.
+	Z float64
+
 	// Deprecated (as of 1.5.0-alpha): Use SubImage instead.
 	ImageParts ImageParts
 
@@ -530,7 +860,9 @@ type DrawImageOptions struct {
 //
 // Error returned by NewImage is always nil as of 1.5.0-alpha.
 func NewImage(width, height int, filter Filter) (*Image, error) {
-	return newImage(width, height, filter, false), nil
+	i := newImage(width, height, filter, false)
+	i.trackLeak()
+	return i, nil
 }
 
 func newImage(width, height int, filter Filter, volatile bool) *Image {
@@ -543,6 +875,162 @@ func newImage(width, height int, filter Filter, volatile bool) *Image {
 	return i
 }
 
+// NewMultisampledImage returns an empty image that is rendered with multisample anti-aliasing (MSAA).
+//
+// samples is the number of the samples per pixel, e.g., 4. samples is capped by the device-dependent maximum.
+// Edges of the triangles drawn onto the image, like vector graphics or rotated sprites, are antialiased.
+// The multisampled pixels are resolved when the image is used as a rendering source or its pixels are read.
+//
+// A multisampled image is never put on an automatic texture atlas, and rendering to it is slower than a regular
+// image. Use it as an offscreen only when antialiasing is needed.
+//
+// If multisampling is not available on the environment, e.g., browsers, mobiles or Metal, the returned image works
+// as a regular image.
+//
+// If width or height is less than 1 or more than device-dependent maximum size, NewMultisampledImage panics.
+// If samples is less than 1, NewMultisampledImage panics.
+//
+// Error returned by NewMultisampledImage is always nil.
+//
+// Note that this API is experimental.
+func NewMultisampledImage(width, height, samples int) (*Image, error) {
+	if samples < 1 {
+		panic("ebiten: samples must be positive at NewMultisampledImage")
+	}
+	i := &Image{
+		buffered: buffered.NewMultisampledImage(width, height, samples),
+		filter:   FilterDefault,
+		bounds:   image.Rect(0, 0, width, height),
+	}
+	i.addr = i
+	i.trackLeak()
+	return i, nil
+}
+
+// NewHDRImage returns an empty offscreen image whose pixels have 16-bit floating-point values (RGBA16F).
+//
+// An HDR image keeps values more than 1 and the precision finer than 8 bits. This is useful for effects like bloom,
+// HDR tone mapping and accumulation, that would band on a regular image. Values more than 1 are produced by
+// additive blending like CompositeModeLighter, or by custom shaders. When an HDR image is drawn onto a regular image,
+// the values are clamped, so scale the values with a color matrix or a shader to tone-map them.
+//
+// An HDR image is never put on an automatic texture atlas. The pixels read by At, or restored when the graphics
+// context is lost, have only 8-bit precision.
+//
+// If HDR images are not available on the environment, e.g., browsers, mobiles or Metal, the returned image works
+// as a regular image. See also IsHDRImageAvailable.
+//
+// If width or height is less than 1 or more than device-dependent maximum size, NewHDRImage panics.
+//
+// Error returned by NewHDRImage is always nil.
+//
+// Note that this API is experimental.
+func NewHDRImage(width, height int) (*Image, error) {
+	i := newHDRImage(width, height, false)
+	i.trackLeak()
+	return i, nil
+}
+
+func newHDRImage(width, height int, volatile bool) *Image {
+	i := &Image{
+		buffered: buffered.NewFloatImage(width, height, volatile),
+		filter:   FilterDefault,
+		bounds:   image.Rect(0, 0, width, height),
+	}
+	i.addr = i
+	return i
+}
+
+// IsHDRImageAvailable reports whether images created by NewHDRImage actually have floating-point values.
+//
+// IsHDRImageAvailable returns false until the first frame is rendered. Call this in the game's Update.
+//
+// Note that this API is experimental.
+//
+// IsHDRImageAvailable is concurrent-safe.
+func IsHDRImageAvailable() bool {
+	return graphicscommand.FloatImagesAvailable()
+}
+
+// NewAlphaImage returns an empty offscreen image whose pixels have only 8-bit alpha values.
+//
+// An alpha image takes a quarter of the GPU memory of a regular image. This is useful for masks and font glyphs.
+// An alpha image is sampled as white with the alpha values, so color it with ColorM or vertex colors. The color
+// values of the pixels given by Fill, ReplacePixels or rendering are discarded, and At returns white with the
+// alpha value.
+//
+// Composite modes depending on the destination alpha, like CompositeModeDestinationOver or CompositeModeSourceIn,
+// don't work correctly when the destination is an alpha image. Custom shaders get an alpha image as (a, 0, 0, 1).
+//
+// An alpha image is never put on an automatic texture atlas.
+//
+// If alpha images are not available on the environment, e.g., browsers, mobiles or Metal, the returned image works
+// as a regular image internally. The color values given by Fill and ReplacePixels are still discarded, but the
+// color values rendered by DrawImage or DrawTriangles are kept. See also IsAlphaImageAvailable.
+//
+// If width or height is less than 1 or more than device-dependent maximum size, NewAlphaImage panics.
+//
+// Error returned by NewAlphaImage is always nil.
+//
+// Note that this API is experimental.
+func NewAlphaImage(width, height int) (*Image, error) {
+	i := &Image{
+		buffered: buffered.NewAlphaImage(width, height),
+		filter:   FilterDefault,
+		bounds:   image.Rect(0, 0, width, height),
+	}
+	i.addr = i
+	i.trackLeak()
+	return i, nil
+}
+
+// IsAlphaImageAvailable reports whether images created by NewAlphaImage actually have only alpha values.
+//
+// IsAlphaImageAvailable returns false until the first frame is rendered. Call this in the game's Update.
+//
+// Note that this API is experimental.
+//
+// IsAlphaImageAvailable is concurrent-safe.
+func IsAlphaImageAvailable() bool {
+	return graphicscommand.AlphaImagesAvailable()
+}
+
+// SetSRGBEnabled sets whether the sRGB-correct rendering pipeline is used.
+//
+// By default, colors are blended in gamma (sRGB) space as they are, which darkens alpha-blended edges and linearly
+// filtered pixels. With the sRGB pipeline, images keep sRGB-encoded pixels as usual, but the pixels are decoded
+// into linear values when they are sampled, and blending and filtering are done in linear space.
+//
+// The pixels of ReplacePixels and At are sRGB-encoded regardless of the pipeline. The colors of Fill and the vertex
+// colors of DrawTriangles are treated as sRGB colors. On the other hand, color matrices and custom shaders work
+// with linear values. HDR images keep linear values.
+//
+// SetSRGBEnabled must be called before Run. Otherwise, SetSRGBEnabled does nothing.
+//
+// The sRGB pipeline works only with desktop OpenGL that supports sRGB framebuffers (OpenGL 3.0,
+// ARB_framebuffer_sRGB or EXT_framebuffer_sRGB). Otherwise, the regular pipeline is used. See also IsSRGBEnabled.
+//
+// The initial value is false.
+//
+// Note that this API is experimental.
+//
+// SetSRGBEnabled is concurrent-safe.
+func SetSRGBEnabled(enabled bool) {
+	graphicscommand.SetSRGBEnabled(enabled)
+}
+
+// IsSRGBEnabled reports whether the sRGB-correct rendering pipeline is actually used.
+//
+// IsSRGBEnabled returns false until the first frame is rendered, or when the pipeline is not available on the
+// environment. Call this in the game's Update.
+//
+// Note that this API is experimental.
+//
+// IsSRGBEnabled is concurrent-safe.
+func IsSRGBEnabled() bool {
+	return graphicscommand.SRGBEnabled()
+}
+
 // NewImageFromImage creates a new image with the given image (source).
 //
 // If source's width or height is less than 1 or more than device-dependent maximum size, NewImageFromImage panics.
@@ -562,6 +1050,7 @@ func NewImageFromImage(source image.Image, filter Filter) (*Image, error) {
 		bounds:   image.Rect(0, 0, width, height),
 	}
 	i.addr = i
+	i.trackLeak()
 
 	_ = i.ReplacePixels(copyImage(source))
 	return i, nil
@@ -1460,6 +1460,78 @@ func TestImageSet(t *testing.T) {
 	}
 }
 
+func TestImageSetAfterDrawImage(t *testing.T) {
+	const w, h = 4, 4
+	img, _ := NewImage(w, h, FilterDefault)
+	src, _ := NewImage(1, 1, FilterDefault)
+	src.Fill(color.White)
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	img.Set(0, 0, red)
+
+	// Rendering must not be overwritten by the pixels accumulated by Set.
+	op := &DrawImageOptions{}
+	op.GeoM.Translate(1, 1)
+	img.DrawImage(src, op)
+	img.Set(2, 2, blue)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := img.At(i, j).(color.RGBA)
+			var want color.RGBA
+			switch {
+			case i == 0 && j == 0:
+				want = red
+			case i == 1 && j == 1:
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			case i == 2 && j == 2:
+				want = blue
+			}
+			if got != want {
+				t.Errorf("img.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageSetAfterDrawImageAndEndFrame(t *testing.T) {
+	const w, h = 4, 4
+	img, _ := NewImage(w, h, FilterDefault)
+	src, _ := NewImage(1, 1, FilterDefault)
+	src.Fill(color.White)
+
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	op := &DrawImageOptions{}
+	op.GeoM.Translate(1, 1)
+	img.DrawImage(src, op)
+	img.Set(2, 2, blue)
+
+	// The pending pixels are uploaded to the rendered image at the end of the frame.
+	if err := EndFrameForTesting(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read the pixels on GPU via another image, as img.At reads the copy on CPU.
+	dst, _ := NewImage(w, h, FilterDefault)
+	dst.DrawImage(img, nil)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			var want color.RGBA
+			switch {
+			case i == 1 && j == 1:
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			case i == 2 && j == 2:
+				want = blue
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
 func TestImageSetAndDraw(t *testing.T) {
 	type Pt struct {
 		X, Y int
@@ -1852,3 +1924,467 @@ func TestImageDrawTrianglesAndMutateArgs(t *testing.T) {
 		}
 	}
 }
+
+func TestImageDrawImageSnapToPixels(t *testing.T) {
+	src, _ := NewImage(4, 4, FilterDefault)
+	src.Fill(color.White)
+	dst, _ := NewImage(16, 16, FilterDefault)
+
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(1.3, 1.3)
+	op.GeoM.Translate(2.4, 3.6)
+	op.SnapToPixels = true
+	dst.DrawImage(src, op)
+
+	// The rectangle (2.4, 3.6)-(7.6, 8.8) is snapped to (2, 4)-(8, 9).
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{}
+			if 2 <= i && i < 8 && 4 <= j && j < 9 {
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageClipRect(t *testing.T) {
+	src, _ := NewImage(16, 16, FilterDefault)
+	src.Fill(color.White)
+	dst, _ := NewImage(16, 16, FilterDefault)
+
+	op := &DrawImageOptions{}
+	op.ClipRect = image.Rect(4, 5, 10, 12)
+	dst.DrawImage(src, op)
+
+	vs := []Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 0, ColorB: 0, ColorA: 1},
+		{DstX: 16, DstY: 0, SrcX: 16, SrcY: 0, ColorR: 1, ColorG: 0, ColorB: 0, ColorA: 1},
+		{DstX: 0, DstY: 16, SrcX: 0, SrcY: 16, ColorR: 1, ColorG: 0, ColorB: 0, ColorA: 1},
+		{DstX: 16, DstY: 16, SrcX: 16, SrcY: 16, ColorR: 1, ColorG: 0, ColorB: 0, ColorA: 1},
+	}
+	top := &DrawTrianglesOptions{}
+	top.ClipRect = image.Rect(12, 0, 20, 2)
+	dst.DrawTriangles(vs, []uint16{0, 1, 2, 1, 2, 3}, src, top)
+
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{}
+			if 4 <= i && i < 10 && 5 <= j && j < 12 {
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			if 12 <= i && j < 2 {
+				want = color.RGBA{0xff, 0, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageMask(t *testing.T) {
+	src, _ := NewImage(16, 16, FilterDefault)
+	src.Fill(color.White)
+	dst, _ := NewImage(16, 16, FilterDefault)
+
+	// The mask is the rectangle (4, 4)-(12, 12) made of two triangles.
+	mask := &Mask{
+		Vertices: []Vertex{
+			{DstX: 4, DstY: 4},
+			{DstX: 12, DstY: 4},
+			{DstX: 4, DstY: 12},
+			{DstX: 12, DstY: 12},
+		},
+		Indices: []uint16{0, 1, 2, 1, 2, 3},
+	}
+	op := &DrawImageOptions{}
+	op.Mask = mask
+	dst.DrawImage(src, op)
+
+	vs := []Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 0, ColorB: 0, ColorA: 1},
+		{DstX: 16, DstY: 0, SrcX: 16, SrcY: 0, ColorR: 1, ColorG: 0, ColorB: 0, ColorA: 1},
+		{DstX: 0, DstY: 16, SrcX: 0, SrcY: 16, ColorR: 1, ColorG: 0, ColorB: 0, ColorA: 1},
+		{DstX: 16, DstY: 16, SrcX: 16, SrcY: 16, ColorR: 1, ColorG: 0, ColorB: 0, ColorA: 1},
+	}
+	inverted := *mask
+	inverted.Inverted = true
+	top := &DrawTrianglesOptions{}
+	top.Mask = &inverted
+	dst.DrawTriangles(vs, []uint16{0, 1, 2, 1, 2, 3}, src, top)
+
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if 4 <= i && i < 12 && 4 <= j && j < 12 {
+				want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageDepthTest(t *testing.T) {
+	red, _ := NewImage(16, 16, FilterDefault)
+	red.Fill(color.RGBA{0xff, 0, 0, 0xff})
+	green, _ := NewImage(8, 8, FilterDefault)
+	green.Fill(color.RGBA{0, 0xff, 0, 0xff})
+	blue, _ := NewImage(8, 8, FilterDefault)
+	blue.Fill(color.RGBA{0, 0, 0xff, 0xff})
+	dst, _ := NewImage(16, 16, FilterDefault)
+
+	op := &DrawImageOptions{}
+	op.DepthTest = true
+	op.Z = 0.5
+	dst.DrawImage(red, op)
+
+	// The blue image is behind the red image and hidden.
+	op = &DrawImageOptions{}
+	op.GeoM.Translate(8, 8)
+	op.DepthTest = true
+	op.Z = 0.25
+	dst.DrawImage(blue, op)
+
+	// The green image is in front of the red image.
+	op = &DrawImageOptions{}
+	op.DepthTest = true
+	op.Z = 0.75
+	dst.DrawImage(green, op)
+
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{0xff, 0, 0, 0xff}
+			if i < 8 && j < 8 {
+				want = color.RGBA{0, 0xff, 0, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	// Fill clears the depth buffer too.
+	dst.Fill(color.Black)
+	op = &DrawImageOptions{}
+	op.GeoM.Translate(8, 8)
+	op.DepthTest = true
+	op.Z = 0.25
+	dst.DrawImage(blue, op)
+
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{0, 0, 0, 0xff}
+			if 8 <= i && 8 <= j {
+				want = color.RGBA{0, 0, 0xff, 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawTiled(t *testing.T) {
+	pix := make([]byte, 4*4*4)
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			idx := 4 * (i + j*4)
+			if i == 1 && j == 1 {
+				pix[idx] = 0xff
+				pix[idx+3] = 0xff
+			} else {
+				pix[idx+2] = 0xff
+				pix[idx+3] = 0xff
+			}
+		}
+	}
+	src, _ := NewImage(4, 4, FilterDefault)
+	src.ReplacePixels(pix)
+	// Use a sub-image to test that the tiles wrap within the sub-image region.
+	sub := src.SubImage(image.Rect(1, 1, 3, 3)).(*Image)
+
+	dst, _ := NewImage(16, 16, FilterDefault)
+	op := &DrawTiledOptions{}
+	op.GeoM.Translate(1, 0)
+	dst.DrawTiled(sub, image.Rect(2, 2, 14, 14), op)
+
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			if 2 <= i && i < 14 && 2 <= j && j < 14 {
+				// The red pixel is at (1, 1) in the source, which is (0, 0) in the sub-image.
+				// The pattern is translated by (1, 0) from the rectangle's corner (2, 2).
+				if i%2 == 1 && j%2 == 0 {
+					want = color.RGBA{0xff, 0, 0, 0xff}
+				} else {
+					want = color.RGBA{0, 0, 0xff, 0xff}
+				}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageMultisampled(t *testing.T) {
+	src, _ := NewImage(4, 4, FilterDefault)
+	src.Fill(color.White)
+	dst, _ := NewMultisampledImage(16, 16, 4)
+
+	op := &DrawImageOptions{}
+	op.GeoM.Translate(2, 3)
+	dst.DrawImage(src, op)
+
+	// Rendering the multisampled image onto another image resolves the samples.
+	dst2, _ := NewImage(16, 16, FilterDefault)
+	dst2.DrawImage(dst, nil)
+
+	for _, img := range []*Image{dst, dst2} {
+		for j := 0; j < 16; j++ {
+			for i := 0; i < 16; i++ {
+				got := img.At(i, j)
+				want := color.RGBA{}
+				if 2 <= i && i < 6 && 3 <= j && j < 7 {
+					want = color.RGBA{0xff, 0xff, 0xff, 0xff}
+				}
+				if got != want {
+					t.Errorf("img.At(%d, %d): got: %v, want: %v", i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestImageFillGradient(t *testing.T) {
+	img, _ := NewImage(16, 16, FilterDefault)
+
+	img.FillGradient(&LinearGradient{
+		X0: 0,
+		Y0: 0,
+		X1: 16,
+		Y1: 0,
+		Stops: []GradientStop{
+			{Offset: 0, Color: color.Black},
+			{Offset: 1, Color: color.White},
+		},
+	})
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := img.At(i, j).(color.RGBA)
+			v := uint8((float64(i) + 0.5) / 16 * 0xff)
+			want := color.RGBA{v, v, v, 0xff}
+			if !sameColors(got, want, 2) {
+				t.Errorf("img.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	img.FillGradient(&RadialGradient{
+		X:      8,
+		Y:      8,
+		Radius: 4,
+		Stops: []GradientStop{
+			{Offset: 0.5, Color: red},
+			{Offset: 1, Color: blue},
+		},
+	})
+	for _, p := range []struct {
+		X, Y int
+		Want color.RGBA
+	}{
+		{8, 8, red},
+		{7, 7, red},
+		{0, 0, blue},
+		{15, 8, blue},
+	} {
+		got := img.At(p.X, p.Y).(color.RGBA)
+		if !sameColors(got, p.Want, 2) {
+			t.Errorf("img.At(%d, %d): got: %v, want: %v", p.X, p.Y, got, p.Want)
+		}
+	}
+}
+
+func TestAtlas(t *testing.T) {
+	a := NewAtlas(16)
+	defer a.Dispose()
+
+	newImage := func(w, h int, clr color.RGBA) image.Image {
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(img, img.Bounds(), &image.Uniform{clr}, image.ZP, draw.Src)
+		return img
+	}
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	green := color.RGBA{0, 0xff, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+
+	r, ok := a.Add(newImage(7, 7, red))
+	if !ok {
+		t.Fatal("Add must succeed")
+	}
+	g, ok := a.Add(newImage(7, 7, green))
+	if !ok {
+		t.Fatal("Add must succeed")
+	}
+	gimg, _ := NewImageFromImage(newImage(7, 7, blue), FilterDefault)
+	b, ok := a.Add(gimg)
+	if !ok {
+		t.Fatal("Add must succeed")
+	}
+	if _, ok := a.Add(newImage(15, 15, red)); ok {
+		t.Fatal("Add must fail when the atlas doesn't have enough space")
+	}
+
+	for _, e := range []struct {
+		img   *AtlasImage
+		color color.RGBA
+	}{
+		{r, red},
+		{g, green},
+		{b, blue},
+	} {
+		if w, h := e.img.Image().Size(); w != 7 || h != 7 {
+			t.Errorf("Size(): got: (%d, %d), want: (7, 7)", w, h)
+		}
+		bounds := e.img.Bounds()
+		if got, want := a.Image().At(bounds.Min.X, bounds.Min.Y), e.color; got != want {
+			t.Errorf("At: got: %v, want: %v", got, want)
+		}
+	}
+
+	a.Remove(r)
+	if r.Image() != nil {
+		t.Errorf("Image() of a removed image must be nil")
+	}
+	if got, want := a.Len(), 2; got != want {
+		t.Errorf("Len(): got: %d, want: %d", got, want)
+	}
+
+	if !a.Defragment() {
+		t.Fatal("Defragment must succeed")
+	}
+	for _, e := range []struct {
+		img   *AtlasImage
+		color color.RGBA
+	}{
+		{g, green},
+		{b, blue},
+	} {
+		bounds := e.img.Bounds()
+		for j := bounds.Min.Y; j < bounds.Max.Y; j++ {
+			for i := bounds.Min.X; i < bounds.Max.X; i++ {
+				if got, want := a.Image().At(i, j), e.color; got != want {
+					t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestImageHDR(t *testing.T) {
+	src, _ := NewImage(4, 4, FilterDefault)
+	src.Fill(color.RGBA{0x99, 0x99, 0x99, 0xff})
+
+	hdr, _ := NewHDRImage(4, 4)
+	defer hdr.Dispose()
+	op := &DrawImageOptions{}
+	op.CompositeMode = CompositeModeLighter
+	hdr.DrawImage(src, op)
+	hdr.DrawImage(src, op)
+
+	dst, _ := NewImage(4, 4, FilterDefault)
+	op = &DrawImageOptions{}
+	op.ColorM.Scale(0.5, 0.5, 0.5, 1)
+	dst.DrawImage(hdr, op)
+
+	// An HDR image keeps 0x99 * 2, which is more than 1. Otherwise, the value is clamped to 0xff.
+	want := color.RGBA{0x80, 0x80, 0x80, 0xff}
+	if IsHDRImageAvailable() {
+		want = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	}
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			if !sameColors(got, want, 1) {
+				t.Errorf("dst.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageAlpha(t *testing.T) {
+	a, _ := NewAlphaImage(4, 4)
+	defer a.Dispose()
+	a.Fill(color.RGBA{0x80, 0x40, 0x20, 0x80})
+
+	// An alpha image discards the color values.
+	want := color.RGBA{0x80, 0x80, 0x80, 0x80}
+	if got := a.At(1, 1).(color.RGBA); !sameColors(got, want, 1) {
+		t.Errorf("a.At(1, 1): got %v, want: %v", got, want)
+	}
+
+	dst, _ := NewImage(4, 4, FilterDefault)
+	op := &DrawImageOptions{}
+	op.ColorM.Scale(1, 0, 0, 1)
+	dst.DrawImage(a, op)
+
+	want = color.RGBA{0x80, 0, 0, 0x80}
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			if !sameColors(got, want, 1) {
+				t.Errorf("dst.At(%d, %d): got %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageSnapToPixelsLinear(t *testing.T) {
+	// The center 2x2 pixels are white and the others are red, like a sprite sheet.
+	src, _ := NewImage(4, 4, FilterDefault)
+	pix := make([]byte, 4*4*4)
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			idx := 4 * (i + j*4)
+			if 1 <= i && i < 3 && 1 <= j && j < 3 {
+				pix[idx], pix[idx+1], pix[idx+2], pix[idx+3] = 0xff, 0xff, 0xff, 0xff
+				continue
+			}
+			pix[idx], pix[idx+1], pix[idx+2], pix[idx+3] = 0xff, 0, 0, 0xff
+		}
+	}
+	src.ReplacePixels(pix)
+	dst, _ := NewImage(16, 16, FilterDefault)
+
+	op := &DrawImageOptions{}
+	op.GeoM.Scale(2.5, 2.5)
+	op.GeoM.Translate(1.2, 1.2)
+	op.Filter = FilterLinear
+	op.SnapToPixels = true
+	dst.DrawImage(src.SubImage(image.Rect(1, 1, 3, 3)).(*Image), op)
+
+	// The rectangle (1.2, 1.2)-(6.2, 6.2) is snapped to (1, 1)-(6, 6). The red pixels must not be blended.
+	for j := 1; j < 6; j++ {
+		for i := 1; i < 6; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{0xff, 0xff, 0xff, 0xff}
+			if !sameColors(got.(color.RGBA), want, 1) {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
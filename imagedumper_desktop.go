@@ -62,6 +62,38 @@ func takeScreenshot(screen *Image) error {
 	return nil
 }
 
+func toggleRecording() error {
+	if !IsRecording() {
+		if err := StartRecording(nil); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(os.Stderr, "Started recording\n"); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	newname, err := availableFilename("recording_", ".gif")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(newname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := StopRecording(f, RecordingFormatGIF); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(os.Stderr, "Saved recording: %s\n", newname); err != nil {
+		return err
+	}
+	return nil
+}
+
 func dumpInternalImages() error {
 	dir, err := availableFilename("internalimages_", "")
 	if err != nil {
@@ -82,6 +114,26 @@ func dumpInternalImages() error {
 	return nil
 }
 
+func dumpAtlases() error {
+	dir, err := availableFilename("atlases_", "")
+	if err != nil {
+		return err
+	}
+
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return err
+	}
+
+	if err := shareable.DumpAtlases(dir); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(os.Stderr, "Dumped the texture atlases at: %s\n", dir); err != nil {
+		return err
+	}
+	return nil
+}
+
 type imageDumper struct {
 	f func(screen *Image) error
 
@@ -91,15 +143,25 @@ type imageDumper struct {
 	screenshotKey    Key
 	toTakeScreenshot bool
 
+	hasRecordingKey   bool
+	recordingKey      Key
+	toToggleRecording bool
+
 	hasDumpInternalImagesKey bool
 	dumpInternalImagesKey    Key
 	toDumpInternalImages     bool
+
+	hasDumpAtlasesKey bool
+	dumpAtlasesKey    Key
+	toDumpAtlases     bool
 }
 
 func (i *imageDumper) update(screen *Image) error {
 	const (
 		envScreenshotKey     = "EBITEN_SCREENSHOT_KEY"
+		envRecordingKey      = "EBITEN_RECORDING_KEY"
 		envInternalImagesKey = "EBITEN_INTERNAL_IMAGES_KEY"
+		envAtlasesKey        = "EBITEN_ATLASES_KEY"
 	)
 
 	if err := i.f(screen); err != nil {
@@ -117,6 +179,13 @@ func (i *imageDumper) update(screen *Image) error {
 			}
 		}
 
+		if keyname := os.Getenv(envRecordingKey); keyname != "" {
+			if key, ok := keyNameToKey(keyname); ok {
+				i.hasRecordingKey = true
+				i.recordingKey = key
+			}
+		}
+
 		if keyname := os.Getenv(envInternalImagesKey); keyname != "" {
 			if isDebug() {
 				if key, ok := keyNameToKey(keyname); ok {
@@ -127,15 +196,32 @@ func (i *imageDumper) update(screen *Image) error {
 				fmt.Fprintf(os.Stderr, "%s is disabled. Specify a build tag 'ebitendebug' to enable it.\n", envInternalImagesKey)
 			}
 		}
+
+		if keyname := os.Getenv(envAtlasesKey); keyname != "" {
+			if isDebug() {
+				if key, ok := keyNameToKey(keyname); ok {
+					i.hasDumpAtlasesKey = true
+					i.dumpAtlasesKey = key
+				}
+			} else {
+				fmt.Fprintf(os.Stderr, "%s is disabled. Specify a build tag 'ebitendebug' to enable it.\n", envAtlasesKey)
+			}
+		}
 	}
 
 	keys := map[Key]struct{}{}
 	if i.hasScreenshotKey {
 		keys[i.screenshotKey] = struct{}{}
 	}
+	if i.hasRecordingKey {
+		keys[i.recordingKey] = struct{}{}
+	}
 	if i.hasDumpInternalImagesKey {
 		keys[i.dumpInternalImagesKey] = struct{}{}
 	}
+	if i.hasDumpAtlasesKey {
+		keys[i.dumpAtlasesKey] = struct{}{}
+	}
 
 	for key := range keys {
 		if IsKeyPressed(key) {
@@ -144,9 +230,15 @@ func (i *imageDumper) update(screen *Image) error {
 				if i.hasScreenshotKey && key == i.screenshotKey {
 					i.toTakeScreenshot = true
 				}
+				if i.hasRecordingKey && key == i.recordingKey {
+					i.toToggleRecording = true
+				}
 				if i.hasDumpInternalImagesKey && key == i.dumpInternalImagesKey {
 					i.toDumpInternalImages = true
 				}
+				if i.hasDumpAtlasesKey && key == i.dumpAtlasesKey {
+					i.toDumpAtlases = true
+				}
 			}
 		} else {
 			i.keyState[key] = 0
@@ -157,6 +249,10 @@ func (i *imageDumper) update(screen *Image) error {
 		return nil
 	}
 
+	if err := dumpFrame(screen); err != nil {
+		return err
+	}
+
 	if i.toTakeScreenshot {
 		i.toTakeScreenshot = false
 		if err := takeScreenshot(screen); err != nil {
@@ -164,6 +260,13 @@ func (i *imageDumper) update(screen *Image) error {
 		}
 	}
 
+	if i.toToggleRecording {
+		i.toToggleRecording = false
+		if err := toggleRecording(); err != nil {
+			return err
+		}
+	}
+
 	if i.toDumpInternalImages {
 		i.toDumpInternalImages = false
 		if err := dumpInternalImages(); err != nil {
@@ -171,5 +274,12 @@ func (i *imageDumper) update(screen *Image) error {
 		}
 	}
 
+	if i.toDumpAtlases {
+		i.toDumpAtlases = false
+		if err := dumpAtlases(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,87 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+var imageLeakWarningEnabled int32
+
+// IsImageLeakWarningEnabled reports whether the image leak warning is enabled.
+//
+// IsImageLeakWarningEnabled is concurrent-safe.
+func IsImageLeakWarningEnabled() bool {
+	return atomic.LoadInt32(&imageLeakWarningEnabled) != 0
+}
+
+// SetImageLeakWarningEnabled sets whether the image leak warning is enabled.
+//
+// An image that becomes unreachable without Dispose is reclaimed by the garbage collector and its GPU resources are
+// released at a later frame, but the resources are kept until then. When the warning is enabled, a warning with the
+// image size and the stack trace where the image was created is written to the standard error when such an image is
+// reclaimed. This is useful to find images that should have been disposed explicitly.
+//
+// Only images created by NewImage, NewImageFromImage and NewMultisampledImage while the warning is enabled are
+// tracked. As recording stack traces makes creating images slower, this is a debug feature. The initial value is
+// false.
+//
+// SetImageLeakWarningEnabled is concurrent-safe.
+func SetImageLeakWarningEnabled(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&imageLeakWarningEnabled, v)
+}
+
+// imageLeakTracker is an object to detect that an image is reclaimed without Dispose.
+//
+// The finalizer is set to the tracker instead of the image, since an Image refers to itself and a finalizer is not
+// guaranteed to run for an object in a cycle.
+type imageLeakTracker struct {
+	width  int
+	height int
+	stack  []byte
+}
+
+func (i *Image) trackLeak() {
+	if !IsImageLeakWarningEnabled() {
+		return
+	}
+	w, h := i.Size()
+	t := &imageLeakTracker{
+		width:  w,
+		height: h,
+		stack:  debug.Stack(),
+	}
+	runtime.SetFinalizer(t, (*imageLeakTracker).warn)
+	i.leak = t
+}
+
+func (t *imageLeakTracker) markDisposed() {
+	if t == nil {
+		return
+	}
+	runtime.SetFinalizer(t, nil)
+}
+
+func (t *imageLeakTracker) warn() {
+	fmt.Fprintf(os.Stderr, "ebiten: an image (%dx%d) was reclaimed by the garbage collector without Dispose; created at:\n%s\n", t.width, t.height, t.stack)
+}
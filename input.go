@@ -46,6 +46,9 @@ func IsKeyPressed(key Key) bool {
 	if !key.isValid() {
 		return false
 	}
+	if theInjectedInput.isKeyPressed(key) {
+		return true
+	}
 
 	var keys []driver.Key
 	switch key {
@@ -71,7 +74,33 @@ func IsKeyPressed(key Key) bool {
 //
 // CursorPosition is concurrent-safe.
 func CursorPosition() (x, y int) {
-	return uiDriver().Input().CursorPosition()
+	return theInjectedInput.cursorPosition(uiDriver().Input().CursorPosition())
+}
+
+// LatestCursorPosition returns a position of a mouse cursor relative to the game screen (window), which is
+// queried at the time LatestCursorPosition is called.
+//
+// While CursorPosition returns the position sampled before the game's Update is called and doesn't change during
+// the same frame, LatestCursorPosition samples the cursor position again. This is useful to render a cursor or an
+// aiming overlay at the end of rendering with the lowest latency. Use CursorPosition for the game logic.
+//
+// LatestCursorPosition returns the same value as CursorPosition on browsers and mobiles.
+//
+// LatestCursorPosition is concurrent-safe.
+func LatestCursorPosition() (x, y int) {
+	return theInjectedInput.cursorPosition(uiDriver().Input().LatestCursorPosition())
+}
+
+// SetInputMethodCaretPosition tells the OS the position of the text caret on the game screen, so that
+// the candidate window of an input method (IME) appears next to the text field in your game.
+//
+// (x, y) is the top of the caret, and height is the height of the caret. The unit is the same as the game screen.
+//
+// SetInputMethodCaretPosition works only on Windows so far, and does nothing on the other platforms.
+func SetInputMethodCaretPosition(x, y, height int) {
+	fx, fy := theUIContext.framebufferPosition(float64(x), float64(y))
+	_, fy2 := theUIContext.framebufferPosition(float64(x), float64(y+height))
+	uiDriver().SetInputMethodCaretPosition(int(fx), int(fy), int(fy2-fy))
 }
 
 // Wheel returns the x and y offset of the mouse wheel or touchpad scroll.
@@ -79,7 +108,9 @@ func CursorPosition() (x, y int) {
 //
 // Wheel is concurrent-safe.
 func Wheel() (xoff, yoff float64) {
-	return uiDriver().Input().Wheel()
+	xoff, yoff = uiDriver().Input().Wheel()
+	ix, iy := theInjectedInput.wheel()
+	return xoff + ix, yoff + iy
 }
 
 // IsMouseButtonPressed returns a boolean indicating whether mouseButton is pressed.
@@ -89,6 +120,9 @@ func Wheel() (xoff, yoff float64) {
 // Note that touch events not longer affect IsMouseButtonPressed's result as of 1.4.0-alpha.
 // Use Touches instead.
 func IsMouseButtonPressed(mouseButton MouseButton) bool {
+	if theInjectedInput.isMouseButtonPressed(mouseButton) {
+		return true
+	}
 	return uiDriver().Input().IsMouseButtonPressed(driver.MouseButton(mouseButton))
 }
 
@@ -99,6 +133,9 @@ func IsMouseButtonPressed(mouseButton MouseButton) bool {
 //
 // GamepadSDLID is concurrent-safe.
 func GamepadSDLID(id int) string {
+	if p := gamepadProvider(); p != nil {
+		return p.GamepadSDLID(id)
+	}
 	return uiDriver().Input().GamepadSDLID(id)
 }
 
@@ -114,6 +151,9 @@ func GamepadSDLID(id int) string {
 //
 // GamepadName is concurrent-safe.
 func GamepadName(id int) string {
+	if p := gamepadProvider(); p != nil {
+		return p.GamepadName(id)
+	}
 	return uiDriver().Input().GamepadName(id)
 }
 
@@ -123,7 +163,10 @@ func GamepadName(id int) string {
 //
 // GamepadIDs always returns an empty slice on mobiles.
 func GamepadIDs() []int {
-	return uiDriver().Input().GamepadIDs()
+	if p := gamepadProvider(); p != nil {
+		return theInjectedInput.gamepadIDs(p.GamepadIDs())
+	}
+	return theInjectedInput.gamepadIDs(uiDriver().Input().GamepadIDs())
 }
 
 // GamepadAxisNum returns the number of axes of the gamepad (id).
@@ -132,7 +175,10 @@ func GamepadIDs() []int {
 //
 // GamepadAxisNum always returns 0 on mobiles.
 func GamepadAxisNum(id int) int {
-	return uiDriver().Input().GamepadAxisNum(id)
+	if p := gamepadProvider(); p != nil {
+		return theInjectedInput.gamepadAxisNum(id, p.GamepadAxisNum(id))
+	}
+	return theInjectedInput.gamepadAxisNum(id, uiDriver().Input().GamepadAxisNum(id))
 }
 
 // GamepadAxis returns the float value [-1.0 - 1.0] of the given gamepad (id)'s axis (axis).
@@ -141,6 +187,12 @@ func GamepadAxisNum(id int) int {
 //
 // GamepadAxis always returns 0 on mobiles.
 func GamepadAxis(id int, axis int) float64 {
+	if v, ok := theInjectedInput.gamepadAxis(id, axis); ok {
+		return v
+	}
+	if p := gamepadProvider(); p != nil {
+		return p.GamepadAxis(id, axis)
+	}
 	return uiDriver().Input().GamepadAxis(id, axis)
 }
 
@@ -150,7 +202,10 @@ func GamepadAxis(id int, axis int) float64 {
 //
 // GamepadButtonNum always returns 0 on mobiles.
 func GamepadButtonNum(id int) int {
-	return uiDriver().Input().GamepadButtonNum(id)
+	if p := gamepadProvider(); p != nil {
+		return theInjectedInput.gamepadButtonNum(id, p.GamepadButtonNum(id))
+	}
+	return theInjectedInput.gamepadButtonNum(id, uiDriver().Input().GamepadButtonNum(id))
 }
 
 // IsGamepadButtonPressed returns the boolean indicating the given button of the gamepad (id) is pressed or not.
@@ -162,9 +217,82 @@ func GamepadButtonNum(id int) int {
 //
 // IsGamepadButtonPressed always returns false on mobiles.
 func IsGamepadButtonPressed(id int, button GamepadButton) bool {
+	if theInjectedInput.isGamepadButtonPressed(id, button) {
+		return true
+	}
+	if p := gamepadProvider(); p != nil {
+		return p.IsGamepadButtonPressed(id, button)
+	}
 	return uiDriver().Input().IsGamepadButtonPressed(id, driver.GamepadButton(button))
 }
 
+// GamepadGyro returns the angular velocity of the given gamepad (id) around the X, Y and Z axes in radians per second.
+//
+// GamepadGyro returns false as ok when the gamepad doesn't have a gyro sensor or the platform doesn't provide it.
+// Currently the gyro sensor is available only on browsers supporting the Gamepad extensions API like Firefox, or
+// via a GamepadProvider.
+//
+// GamepadGyro is concurrent-safe.
+func GamepadGyro(id int) (x, y, z float64, ok bool) {
+	if p := gamepadProvider(); p != nil {
+		if s, ok := p.(GamepadSensorProvider); ok {
+			return s.GamepadGyro(id)
+		}
+		return 0, 0, 0, false
+	}
+	return uiDriver().Input().GamepadGyro(id)
+}
+
+// GamepadAccelerometer returns the acceleration of the given gamepad (id) along the X, Y and Z axes in meters per
+// second squared.
+//
+// GamepadAccelerometer returns false as ok when the gamepad doesn't have an accelerometer or the platform doesn't
+// provide it. The availability is the same as GamepadGyro.
+//
+// GamepadAccelerometer is concurrent-safe.
+func GamepadAccelerometer(id int) (x, y, z float64, ok bool) {
+	if p := gamepadProvider(); p != nil {
+		if s, ok := p.(GamepadSensorProvider); ok {
+			return s.GamepadAccelerometer(id)
+		}
+		return 0, 0, 0, false
+	}
+	return uiDriver().Input().GamepadAccelerometer(id)
+}
+
+// GamepadTouchpadTouchIDs returns the IDs of the current touches on the touchpad of the given gamepad (id), like
+// the touchpad of DualShock 4.
+//
+// GamepadTouchpadTouchIDs returns nil when there are no touches or the platform doesn't provide the touchpad.
+// The availability is the same as GamepadGyro.
+//
+// GamepadTouchpadTouchIDs is concurrent-safe.
+func GamepadTouchpadTouchIDs(id int) []int {
+	if p := gamepadProvider(); p != nil {
+		if s, ok := p.(GamepadSensorProvider); ok {
+			return s.GamepadTouchpadTouchIDs(id)
+		}
+		return nil
+	}
+	return uiDriver().Input().GamepadTouchpadTouchIDs(id)
+}
+
+// GamepadTouchpadPosition returns the position of the touch (touchID) on the touchpad of the given gamepad (id).
+// x and y are in [-1.0, 1.0], and (0, 0) is the center of the touchpad.
+//
+// GamepadTouchpadPosition returns (0, 0) if the touch doesn't exist.
+//
+// GamepadTouchpadPosition is concurrent-safe.
+func GamepadTouchpadPosition(id int, touchID int) (x, y float64) {
+	if p := gamepadProvider(); p != nil {
+		if s, ok := p.(GamepadSensorProvider); ok {
+			return s.GamepadTouchpadPosition(id, touchID)
+		}
+		return 0, 0
+	}
+	return uiDriver().Input().GamepadTouchpadPosition(id, touchID)
+}
+
 // TouchIDs returns the current touch states.
 //
 // TouchIDs returns nil when there are no touches.
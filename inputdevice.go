@@ -0,0 +1,91 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/rawinput"
+)
+
+// KeyboardDeviceIDs returns the IDs of the keyboard devices.
+//
+// Unlike IsKeyPressed, which merges all the keyboards, the keyboard device functions distinguish individual devices.
+// This is useful for local multiplayer games with multiple keyboards.
+//
+// The keyboard devices are available on Windows (Raw Input) and Linux (evdev).
+// On Linux, the read permission of /dev/input/event* is required, e.g., by the input group.
+// KeyboardDeviceIDs returns nil on the other platforms.
+//
+// The states of the devices are updated even when the window is not focused.
+//
+// KeyboardDeviceIDs is concurrent-safe.
+func KeyboardDeviceIDs() []int {
+	return rawinput.KeyboardIDs()
+}
+
+// IsDeviceKeyPressed returns a boolean indicating whether key of the keyboard device (deviceID) is pressed.
+//
+// IsDeviceKeyPressed is concurrent-safe.
+func IsDeviceKeyPressed(deviceID int, key Key) bool {
+	if !key.isValid() {
+		return false
+	}
+
+	var keys []driver.Key
+	switch key {
+	case KeyAlt:
+		keys = append(keys, driver.KeyLeftAlt, driver.KeyRightAlt)
+	case KeyControl:
+		keys = append(keys, driver.KeyLeftControl, driver.KeyRightControl)
+	case KeyShift:
+		keys = append(keys, driver.KeyLeftShift, driver.KeyRightShift)
+	default:
+		keys = append(keys, driver.Key(key))
+	}
+	for _, k := range keys {
+		if rawinput.IsKeyPressed(deviceID, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// MouseDeviceIDs returns the IDs of the mouse devices.
+//
+// The availability is the same as KeyboardDeviceIDs.
+//
+// MouseDeviceIDs is concurrent-safe.
+func MouseDeviceIDs() []int {
+	return rawinput.MouseIDs()
+}
+
+// IsDeviceMouseButtonPressed returns a boolean indicating whether mouseButton of the mouse device (deviceID) is
+// pressed.
+//
+// IsDeviceMouseButtonPressed is concurrent-safe.
+func IsDeviceMouseButtonPressed(deviceID int, mouseButton MouseButton) bool {
+	return rawinput.IsMouseButtonPressed(deviceID, driver.MouseButton(mouseButton))
+}
+
+// MouseDeviceMovement returns the relative movement of the mouse device (deviceID) since the previous tick, i.e.,
+// the previous call of the game's Update.
+//
+// The unit is the device's own unit, which is not scaled by the OS's pointer speed settings.
+// The mouse devices don't have positions, and games should track the positions by themselves if needed.
+//
+// MouseDeviceMovement is concurrent-safe.
+func MouseDeviceMovement(deviceID int) (dx, dy int) {
+	return rawinput.MouseMovement(deviceID)
+}
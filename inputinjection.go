@@ -0,0 +1,286 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"sort"
+	"sync"
+)
+
+type injectedGamepad struct {
+	buttons map[GamepadButton]bool
+	axes    map[int]float64
+}
+
+type injectedInput struct {
+	keys    map[Key]bool
+	buttons map[MouseButton]bool
+
+	cursorInjected bool
+	cursorX        int
+	cursorY        int
+
+	// realCursorX and realCursorY are the actual cursor position when the cursor position is injected.
+	// When the actual cursor moves, the injected position is discarded.
+	realCursorX int
+	realCursorY int
+
+	pendingWheelX float64
+	pendingWheelY float64
+	wheelX        float64
+	wheelY        float64
+
+	gamepads map[int]*injectedGamepad
+
+	m sync.Mutex
+}
+
+var theInjectedInput = &injectedInput{
+	keys:     map[Key]bool{},
+	buttons:  map[MouseButton]bool{},
+	gamepads: map[int]*injectedGamepad{},
+}
+
+// InjectKeyEvent injects a key event as if the key is pressed or released.
+//
+// The injected states are combined with the actual states: IsKeyPressed returns true when the key is pressed
+// either actually or by injection. This is useful for bots, tutorials and accessibility tools like switch access.
+//
+// InjectKeyEvent is concurrent-safe.
+func InjectKeyEvent(key Key, pressed bool) {
+	if !key.isValid() {
+		return
+	}
+	i := theInjectedInput
+	i.m.Lock()
+	defer i.m.Unlock()
+	if pressed {
+		i.keys[key] = true
+	} else {
+		delete(i.keys, key)
+	}
+}
+
+// InjectMouseButtonEvent injects a mouse button event as if the button is pressed or released.
+//
+// InjectMouseButtonEvent is concurrent-safe.
+func InjectMouseButtonEvent(button MouseButton, pressed bool) {
+	i := theInjectedInput
+	i.m.Lock()
+	defer i.m.Unlock()
+	if pressed {
+		i.buttons[button] = true
+	} else {
+		delete(i.buttons, button)
+	}
+}
+
+// InjectCursorPosition injects the cursor position in the game screen coordinates.
+//
+// CursorPosition returns the injected position until the actual cursor moves or another position is injected.
+//
+// InjectCursorPosition is concurrent-safe.
+func InjectCursorPosition(x, y int) {
+	rx, ry := uiDriver().Input().CursorPosition()
+
+	i := theInjectedInput
+	i.m.Lock()
+	defer i.m.Unlock()
+	i.cursorInjected = true
+	i.cursorX, i.cursorY = x, y
+	i.realCursorX, i.realCursorY = rx, ry
+}
+
+// InjectWheel injects the offset of the mouse wheel. The offset is added to the actual one in the next tick.
+//
+// InjectWheel is concurrent-safe.
+func InjectWheel(xoff, yoff float64) {
+	i := theInjectedInput
+	i.m.Lock()
+	defer i.m.Unlock()
+	i.pendingWheelX += xoff
+	i.pendingWheelY += yoff
+}
+
+func (i *injectedInput) gamepad(id int) *injectedGamepad {
+	g, ok := i.gamepads[id]
+	if !ok {
+		g = &injectedGamepad{
+			buttons: map[GamepadButton]bool{},
+			axes:    map[int]float64{},
+		}
+		i.gamepads[id] = g
+	}
+	return g
+}
+
+// InjectGamepadButtonEvent injects a gamepad button event as if the button of the gamepad (id) is pressed or
+// released.
+//
+// If the gamepad (id) doesn't exist, a virtual gamepad with the ID appears in GamepadIDs.
+//
+// InjectGamepadButtonEvent is concurrent-safe.
+func InjectGamepadButtonEvent(id int, button GamepadButton, pressed bool) {
+	i := theInjectedInput
+	i.m.Lock()
+	defer i.m.Unlock()
+	g := i.gamepad(id)
+	if pressed {
+		g.buttons[button] = true
+	} else {
+		delete(g.buttons, button)
+	}
+}
+
+// InjectGamepadAxis injects the value of the axis of the gamepad (id). The injected value overrides the actual
+// value.
+//
+// If the gamepad (id) doesn't exist, a virtual gamepad with the ID appears in GamepadIDs.
+//
+// InjectGamepadAxis is concurrent-safe.
+func InjectGamepadAxis(id int, axis int, value float64) {
+	i := theInjectedInput
+	i.m.Lock()
+	defer i.m.Unlock()
+	i.gamepad(id).axes[axis] = value
+}
+
+// ResetInjectedInputs resets all the injected states.
+//
+// ResetInjectedInputs is concurrent-safe.
+func ResetInjectedInputs() {
+	i := theInjectedInput
+	i.m.Lock()
+	defer i.m.Unlock()
+	i.keys = map[Key]bool{}
+	i.buttons = map[MouseButton]bool{}
+	i.cursorInjected = false
+	i.pendingWheelX, i.pendingWheelY = 0, 0
+	i.wheelX, i.wheelY = 0, 0
+	i.gamepads = map[int]*injectedGamepad{}
+}
+
+// updateInjectedInput updates the injected states for the current tick.
+//
+// updateInjectedInput must be called once every tick before the game's update.
+func updateInjectedInput() {
+	i := theInjectedInput
+	i.m.Lock()
+	defer i.m.Unlock()
+	i.wheelX, i.wheelY = i.pendingWheelX, i.pendingWheelY
+	i.pendingWheelX, i.pendingWheelY = 0, 0
+}
+
+func (i *injectedInput) isKeyPressed(key Key) bool {
+	i.m.Lock()
+	defer i.m.Unlock()
+	return i.keys[key]
+}
+
+func (i *injectedInput) isMouseButtonPressed(button MouseButton) bool {
+	i.m.Lock()
+	defer i.m.Unlock()
+	return i.buttons[button]
+}
+
+func (i *injectedInput) cursorPosition(realX, realY int) (int, int) {
+	i.m.Lock()
+	defer i.m.Unlock()
+	if !i.cursorInjected {
+		return realX, realY
+	}
+	if realX != i.realCursorX || realY != i.realCursorY {
+		i.cursorInjected = false
+		return realX, realY
+	}
+	return i.cursorX, i.cursorY
+}
+
+func (i *injectedInput) wheel() (float64, float64) {
+	i.m.Lock()
+	defer i.m.Unlock()
+	return i.wheelX, i.wheelY
+}
+
+func (i *injectedInput) gamepadIDs(ids []int) []int {
+	i.m.Lock()
+	defer i.m.Unlock()
+	if len(i.gamepads) == 0 {
+		return ids
+	}
+
+	m := map[int]struct{}{}
+	for _, id := range ids {
+		m[id] = struct{}{}
+	}
+	for id := range i.gamepads {
+		if _, ok := m[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (i *injectedInput) gamepadAxisNum(id int, n int) int {
+	i.m.Lock()
+	defer i.m.Unlock()
+	g, ok := i.gamepads[id]
+	if !ok {
+		return n
+	}
+	for a := range g.axes {
+		if n < a+1 {
+			n = a + 1
+		}
+	}
+	return n
+}
+
+func (i *injectedInput) gamepadAxis(id int, axis int) (float64, bool) {
+	i.m.Lock()
+	defer i.m.Unlock()
+	g, ok := i.gamepads[id]
+	if !ok {
+		return 0, false
+	}
+	v, ok := g.axes[axis]
+	return v, ok
+}
+
+func (i *injectedInput) gamepadButtonNum(id int, n int) int {
+	i.m.Lock()
+	defer i.m.Unlock()
+	g, ok := i.gamepads[id]
+	if !ok {
+		return n
+	}
+	for b := range g.buttons {
+		if n < int(b)+1 {
+			n = int(b) + 1
+		}
+	}
+	return n
+}
+
+func (i *injectedInput) isGamepadButtonPressed(id int, button GamepadButton) bool {
+	i.m.Lock()
+	defer i.m.Unlock()
+	g, ok := i.gamepads[id]
+	if !ok {
+		return false
+	}
+	return g.buttons[button]
+}
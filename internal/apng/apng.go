@@ -0,0 +1,172 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apng provides an encoder of animated PNG (APNG) files.
+package apng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"time"
+)
+
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+// Encode writes the frames to w in the APNG format. delays are the display durations of the frames.
+//
+// All the frames must have the same size. The first frame is also the default image for decoders that don't
+// support APNG.
+func Encode(w io.Writer, frames []image.Image, delays []time.Duration) error {
+	if len(frames) == 0 {
+		return errors.New("apng: no frames")
+	}
+	if len(frames) != len(delays) {
+		return errors.New("apng: the numbers of the frames and the delays must be the same")
+	}
+
+	b := frames[0].Bounds()
+	e := &encoder{w: w}
+	e.write([]byte(pngHeader))
+
+	var seq uint32
+	for i, f := range frames {
+		if f.Bounds().Size() != b.Size() {
+			return errors.New("apng: all the frames must have the same size")
+		}
+		chunks, err := encodeFrame(f)
+		if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			for _, c := range chunks {
+				if c.typ != "IHDR" {
+					continue
+				}
+				e.writeChunk("IHDR", c.data)
+			}
+			acTL := make([]byte, 8)
+			binary.BigEndian.PutUint32(acTL[0:], uint32(len(frames)))
+			// 0 means infinite loops.
+			binary.BigEndian.PutUint32(acTL[4:], 0)
+			e.writeChunk("acTL", acTL)
+		}
+
+		e.writeChunk("fcTL", frameControl(seq, b.Dx(), b.Dy(), delays[i]))
+		seq++
+
+		for _, c := range chunks {
+			if c.typ != "IDAT" {
+				continue
+			}
+			if i == 0 {
+				e.writeChunk("IDAT", c.data)
+				continue
+			}
+			data := make([]byte, 4+len(c.data))
+			binary.BigEndian.PutUint32(data, seq)
+			copy(data[4:], c.data)
+			e.writeChunk("fdAT", data)
+			seq++
+		}
+	}
+
+	e.writeChunk("IEND", nil)
+	return e.err
+}
+
+type chunk struct {
+	typ  string
+	data []byte
+}
+
+// encodeFrame encodes img as a PNG and returns its chunks.
+func encodeFrame(img image.Image) ([]chunk, error) {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+
+	bs := buf.Bytes()[len(pngHeader):]
+	var chunks []chunk
+	for len(bs) > 0 {
+		if len(bs) < 12 {
+			return nil, errors.New("apng: invalid chunk")
+		}
+		n := int(binary.BigEndian.Uint32(bs))
+		if len(bs) < 12+n {
+			return nil, errors.New("apng: invalid chunk")
+		}
+		chunks = append(chunks, chunk{
+			typ:  string(bs[4:8]),
+			data: bs[8 : 8+n],
+		})
+		bs = bs[12+n:]
+	}
+	return chunks, nil
+}
+
+func frameControl(seq uint32, width, height int, delay time.Duration) []byte {
+	const (
+		disposeOpNone = 0
+		blendOpSource = 0
+	)
+
+	bs := make([]byte, 26)
+	binary.BigEndian.PutUint32(bs[0:], seq)
+	binary.BigEndian.PutUint32(bs[4:], uint32(width))
+	binary.BigEndian.PutUint32(bs[8:], uint32(height))
+	// The offsets at bs[12:20] are always zero.
+	ms := delay / time.Millisecond
+	if ms > 0xffff {
+		ms = 0xffff
+	}
+	binary.BigEndian.PutUint16(bs[20:], uint16(ms))
+	binary.BigEndian.PutUint16(bs[22:], 1000)
+	bs[24] = disposeOpNone
+	bs[25] = blendOpSource
+	return bs
+}
+
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) write(bs []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(bs)
+}
+
+func (e *encoder) writeChunk(typ string, data []byte) {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], typ)
+	e.write(header)
+	e.write(data)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+	e.write(footer)
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apng_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	. "github.com/hajimehoshi/ebiten/internal/apng"
+)
+
+func TestEncode(t *testing.T) {
+	var frames []image.Image
+	var delays []time.Duration
+	for _, c := range []color.RGBA{{0xff, 0, 0, 0xff}, {0, 0xff, 0, 0xff}, {0, 0, 0xff, 0xff}} {
+		img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		for i := 0; i < len(img.Pix); i += 4 {
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+		}
+		frames = append(frames, img)
+		delays = append(delays, 100*time.Millisecond)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, frames, delays); err != nil {
+		t.Fatal(err)
+	}
+
+	// Decoders that don't support APNG decode the first frame.
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 4, 4); got != want {
+		t.Errorf("bounds: got: %v, want: %v", got, want)
+	}
+	r, g, b, a := img.At(1, 1).RGBA()
+	if got, want := (color.RGBA{byte(r >> 8), byte(g >> 8), byte(b >> 8), byte(a >> 8)}), (color.RGBA{0xff, 0, 0, 0xff}); got != want {
+		t.Errorf("img.At(1, 1): got: %v, want: %v", got, want)
+	}
+
+	for _, typ := range []string{"acTL", "fcTL", "fdAT"} {
+		if !bytes.Contains(buf.Bytes(), []byte(typ)) {
+			t.Errorf("%s chunk is not found", typ)
+		}
+	}
+}
@@ -36,12 +36,31 @@ func flushDelayedCommands() error {
 		return nil
 	}
 
+	if err := execDelayedCommands(); err != nil {
+		return err
+	}
+	needsToDelayCommands = false
+	return nil
+}
+
+// flushDelayedCommandsKeepingDelayed executes the delayed commands, but the later commands are still delayed.
+func flushDelayedCommandsKeepingDelayed() error {
+	delayedCommandsM.Lock()
+	defer delayedCommandsM.Unlock()
+
+	if !needsToDelayCommands {
+		return nil
+	}
+	return execDelayedCommands()
+}
+
+// execDelayedCommands must be called with delayedCommandsM locked.
+func execDelayedCommands() error {
 	for _, c := range delayedCommands {
 		if err := c(); err != nil {
 			return err
 		}
 	}
 	delayedCommands = delayedCommands[:0]
-	needsToDelayCommands = false
 	return nil
 }
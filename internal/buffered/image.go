@@ -17,6 +17,7 @@ package buffered
 import (
 	"image"
 	"image/color"
+	"sync"
 
 	"github.com/hajimehoshi/ebiten/internal/affine"
 	"github.com/hajimehoshi/ebiten/internal/driver"
@@ -28,8 +29,42 @@ type Image struct {
 	width  int
 	height int
 
-	pixels               []byte
-	needsToResolvePixels bool
+	// alpha reports whether the image has only alpha values.
+	alpha bool
+
+	// pixels is the copy of the image's pixels on CPU to accumulate the pixels given by Set.
+	// pixels is nil when the copy doesn't exist.
+	pixels []byte
+
+	// dirty is the region of pixels that are modified by Set and not uploaded to GPU yet.
+	dirty image.Rectangle
+
+	// rendered reports whether the image is rendered by Fill, DrawImage, DrawTriangles or DrawShader after the
+	// pixels were replaced as a whole last time.
+	//
+	// ReplacePixels for a part is forbidden on a rendered image (#593, #758), and the dirty region is uploaded as
+	// the whole image in this case.
+	rendered bool
+}
+
+var (
+	// imagesWithDirtyPixels is the set of the images that have pixels not uploaded yet.
+	//
+	// The dirty pixels are uploaded at latest at the end of the frame.
+	imagesWithDirtyPixels  = map[*Image]struct{}{}
+	imagesWithDirtyPixelsM sync.Mutex
+)
+
+func addImageWithDirtyPixels(img *Image) {
+	imagesWithDirtyPixelsM.Lock()
+	imagesWithDirtyPixels[img] = struct{}{}
+	imagesWithDirtyPixelsM.Unlock()
+}
+
+func removeImageWithDirtyPixels(img *Image) {
+	imagesWithDirtyPixelsM.Lock()
+	delete(imagesWithDirtyPixels, img)
+	imagesWithDirtyPixelsM.Unlock()
 }
 
 func BeginFrame() error {
@@ -39,7 +74,38 @@ func BeginFrame() error {
 	return flushDelayedCommands()
 }
 
+// BeginFrameKeepingCommandsDelayed begins a frame like BeginFrame, but keeps the image operations delayed.
+//
+// This is used while another goroutine might use images, e.g., during the warmup. The delayed operations are executed
+// in order on the calling goroutine at BeginFrameKeepingCommandsDelayed and EndFrame, so that they don't race with
+// the rendering of the frame.
+func BeginFrameKeepingCommandsDelayed() error {
+	if err := mipmap.BeginFrame(); err != nil {
+		return err
+	}
+	return flushDelayedCommandsKeepingDelayed()
+}
+
 func EndFrame() error {
+	// Execute the operations delayed in this frame if BeginFrameKeepingCommandsDelayed began the frame.
+	if err := flushDelayedCommandsKeepingDelayed(); err != nil {
+		return err
+	}
+
+	// Upload the pixels given by Set in this frame at once.
+	// resolvePendingPixels removes the image from imagesWithDirtyPixels. Copy the images first.
+	imagesWithDirtyPixelsM.Lock()
+	imgs := make([]*Image, 0, len(imagesWithDirtyPixels))
+	for img := range imagesWithDirtyPixels {
+		imgs = append(imgs, img)
+	}
+	imagesWithDirtyPixelsM.Unlock()
+
+	for _, img := range imgs {
+		if err := img.resolvePendingPixels(true); err != nil {
+			return err
+		}
+	}
 	return mipmap.EndFrame()
 }
 
@@ -64,6 +130,75 @@ func NewImage(width, height int, volatile bool) *Image {
 	return i
 }
 
+// NewMultisampledImage returns an image that is rendered with multisample anti-aliasing.
+// samples is the number of the samples per pixel.
+func NewMultisampledImage(width, height, samples int) *Image {
+	i := &Image{}
+	delayedCommandsM.Lock()
+	if needsToDelayCommands {
+		delayedCommands = append(delayedCommands, func() error {
+			i.img = mipmap.NewMultisampled(width, height, samples)
+			i.width = width
+			i.height = height
+			return nil
+		})
+		delayedCommandsM.Unlock()
+		return i
+	}
+	delayedCommandsM.Unlock()
+
+	i.img = mipmap.NewMultisampled(width, height, samples)
+	i.width = width
+	i.height = height
+	return i
+}
+
+// NewFloatImage returns an image whose pixels have floating-point values.
+func NewFloatImage(width, height int, volatile bool) *Image {
+	i := &Image{}
+	delayedCommandsM.Lock()
+	if needsToDelayCommands {
+		delayedCommands = append(delayedCommands, func() error {
+			i.img = mipmap.NewFloat(width, height, volatile)
+			i.width = width
+			i.height = height
+			return nil
+		})
+		delayedCommandsM.Unlock()
+		return i
+	}
+	delayedCommandsM.Unlock()
+
+	i.img = mipmap.NewFloat(width, height, volatile)
+	i.width = width
+	i.height = height
+	return i
+}
+
+// NewAlphaImage returns an image whose pixels have only alpha values.
+func NewAlphaImage(width, height int) *Image {
+	i := &Image{
+		alpha: true,
+	}
+	delayedCommandsM.Lock()
+	if needsToDelayCommands {
+		delayedCommands = append(delayedCommands, func() error {
+			i.img = mipmap.NewAlpha(width, height)
+			i.width = width
+			i.height = height
+			return nil
+		})
+		delayedCommandsM.Unlock()
+		return i
+	}
+	delayedCommandsM.Unlock()
+
+	i.img = mipmap.NewAlpha(width, height)
+	i.width = width
+	i.height = height
+	return i
+}
+
 func NewScreenFramebufferImage(width, height int) *Image {
 	i := &Image{}
 	delayedCommandsM.Lock()
@@ -87,19 +222,40 @@ func NewScreenFramebufferImage(width, height int) *Image {
 
 func (i *Image) invalidatePendingPixels() {
 	i.pixels = nil
-	i.needsToResolvePixels = false
+	i.dirty = image.Rectangle{}
+	removeImageWithDirtyPixels(i)
 }
 
-func (i *Image) resolvePendingPixels(keepPendingPixels bool) {
-	if !i.needsToResolvePixels {
-		return
+// resolvePendingPixels uploads the dirty region of the pixels given by Set.
+//
+// If keepPendingPixels is false, the copy of the pixels on CPU is discarded, e.g., when the image is a render
+// target and the pixels on CPU will be out of date.
+func (i *Image) resolvePendingPixels(keepPendingPixels bool) error {
+	if !i.dirty.Empty() {
+		r := i.dirty
+		i.dirty = image.Rectangle{}
+		removeImageWithDirtyPixels(i)
+
+		if i.rendered || r == image.Rect(0, 0, i.width, i.height) {
+			// The pixels on CPU are modified later by Set. Copy them so that the uploading pixels are not changed.
+			pix := make([]byte, len(i.pixels))
+			copy(pix, i.pixels)
+			i.img.ReplacePixels(pix)
+			i.rendered = false
+		} else {
+			pix := make([]byte, 4*r.Dx()*r.Dy())
+			for j := r.Min.Y; j < r.Max.Y; j++ {
+				copy(pix[4*(j-r.Min.Y)*r.Dx():], i.pixels[4*(r.Min.X+j*i.width):4*(r.Max.X+j*i.width)])
+			}
+			if err := i.img.ReplacePixelsRegion(pix, r.Min.X, r.Min.Y, r.Dx(), r.Dy()); err != nil {
+				return err
+			}
+		}
 	}
-
-	i.img.ReplacePixels(i.pixels)
 	if !keepPendingPixels {
 		i.pixels = nil
 	}
-	i.needsToResolvePixels = false
+	return nil
 }
 
 func (i *Image) MarkDisposed() {
@@ -115,6 +271,7 @@ func (i *Image) MarkDisposed() {
 	delayedCommandsM.Unlock()
 
 	i.invalidatePendingPixels()
+	i.img.MarkDisposed()
 }
 
 func (i *Image) At(x, y int) (r, g, b, a byte, err error) {
@@ -123,8 +280,15 @@ func (i *Image) At(x, y int) (r, g, b, a byte, err error) {
 	if needsToDelayCommands {
 		panic("buffered: the command queue is not available yet at At")
 	}
-	// TODO: Use pending pixels
-	i.resolvePendingPixels(true)
+
+	// The copy on CPU has the latest pixels including the pending pixels.
+	if i.pixels != nil {
+		if x < 0 || y < 0 || i.width <= x || i.height <= y {
+			return 0, 0, 0, 0, nil
+		}
+		idx := 4 * (x + y*i.width)
+		return i.pixels[idx], i.pixels[idx+1], i.pixels[idx+2], i.pixels[idx+3], nil
+	}
 	return i.img.At(x, y)
 }
 
@@ -162,11 +326,19 @@ func (img *Image) set(x, y int, r, g, b, a byte) error {
 		}
 		img.pixels = pix
 	}
+	if img.alpha {
+		// Keep the same pixels as the GPU, where the color values are discarded.
+		r, g, b = a, a, a
+	}
 	img.pixels[4*(x+y*w)] = r
 	img.pixels[4*(x+y*w)+1] = g
 	img.pixels[4*(x+y*w)+2] = b
 	img.pixels[4*(x+y*w)+3] = a
-	img.needsToResolvePixels = true
+
+	// Accumulate the modified region. The region is uploaded once before the image is used next or at the end of
+	// the frame.
+	img.dirty = img.dirty.Union(image.Rect(x, y, x+1, y+1))
+	addImageWithDirtyPixels(img)
 	return nil
 }
 
@@ -176,6 +348,9 @@ func (i *Image) Dump(name string) error {
 	if needsToDelayCommands {
 		panic("buffered: the command queue is not available yet at Dump")
 	}
+	if err := i.resolvePendingPixels(true); err != nil {
+		return err
+	}
 	return i.img.Dump(name)
 }
 
@@ -184,6 +359,7 @@ func (i *Image) Fill(clr color.RGBA) {
 	if needsToDelayCommands {
 		delayedCommands = append(delayedCommands, func() error {
 			i.img.Fill(clr)
+			i.rendered = true
 			return nil
 		})
 		delayedCommandsM.Unlock()
@@ -193,6 +369,7 @@ func (i *Image) Fill(clr color.RGBA) {
 
 	i.invalidatePendingPixels()
 	i.img.Fill(clr)
+	i.rendered = true
 }
 
 func (i *Image) ReplacePixels(pix []byte) {
@@ -202,6 +379,7 @@ func (i *Image) ReplacePixels(pix []byte) {
 			copied := make([]byte, len(pix))
 			copy(copied, pix)
 			i.img.ReplacePixels(copied)
+			i.rendered = false
 			return nil
 		})
 		delayedCommandsM.Unlock()
@@ -211,9 +389,35 @@ func (i *Image) ReplacePixels(pix []byte) {
 
 	i.invalidatePendingPixels()
 	i.img.ReplacePixels(pix)
+	i.rendered = false
+}
+
+// ReplacePixelsRegion replaces the pixels of the region (x, y)-(x+width, y+height) with pix.
+func (i *Image) ReplacePixelsRegion(pix []byte, x, y, width, height int) error {
+	delayedCommandsM.Lock()
+	if needsToDelayCommands {
+		copied := make([]byte, len(pix))
+		copy(copied, pix)
+		delayedCommands = append(delayedCommands, func() error {
+			return i.replacePixelsRegion(copied, x, y, width, height)
+		})
+		delayedCommandsM.Unlock()
+		return nil
+	}
+	delayedCommandsM.Unlock()
+
+	return i.replacePixelsRegion(pix, x, y, width, height)
 }
 
-func (i *Image) DrawImage(src *Image, bounds image.Rectangle, a, b, c, d, tx, ty float32, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter) {
+func (i *Image) replacePixelsRegion(pix []byte, x, y, width, height int) error {
+	// The pixels set by Set outside the region must be kept.
+	if err := i.resolvePendingPixels(false); err != nil {
+		return err
+	}
+	return i.img.ReplacePixelsRegion(pix, x, y, width, height)
+}
+
+func (i *Image) DrawImage(src *Image, bounds image.Rectangle, srcInset float32, a, b, c, d, tx, ty float32, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) error {
 	if i == src {
 		panic("buffered: Image.DrawImage: src must be different from the receiver")
 	}
@@ -230,24 +434,29 @@ func (i *Image) DrawImage(src *Image, bounds image.Rectangle, a, b, c, d, tx, ty
 	delayedCommandsM.Lock()
 	if needsToDelayCommands {
 		delayedCommands = append(delayedCommands, func() error {
-			i.drawImage(src, bounds, g, colorm, mode, filter)
-			return nil
+			return i.drawImage(src, bounds, srcInset, g, colorm, mode, filter, clip, stencil, depth)
 		})
 		delayedCommandsM.Unlock()
-		return
+		return nil
 	}
 	delayedCommandsM.Unlock()
 
-	i.drawImage(src, bounds, g, colorm, mode, filter)
+	return i.drawImage(src, bounds, srcInset, g, colorm, mode, filter, clip, stencil, depth)
 }
 
-func (i *Image) drawImage(src *Image, bounds image.Rectangle, g *mipmap.GeoM, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter) {
-	src.resolvePendingPixels(true)
-	i.resolvePendingPixels(false)
-	i.img.DrawImage(src.img, bounds, g, colorm, mode, filter)
+func (i *Image) drawImage(src *Image, bounds image.Rectangle, srcInset float32, g *mipmap.GeoM, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) error {
+	if err := src.resolvePendingPixels(true); err != nil {
+		return err
+	}
+	if err := i.resolvePendingPixels(false); err != nil {
+		return err
+	}
+	i.img.DrawImage(src.img, bounds, srcInset, g, colorm, mode, filter, clip, stencil, depth)
+	i.rendered = true
+	return nil
 }
 
-func (i *Image) DrawTriangles(src *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) {
+func (i *Image) DrawTriangles(src *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) error {
 	if i == src {
 		panic("buffered: Image.DrawTriangles: src must be different from the receiver")
 	}
@@ -255,18 +464,23 @@ func (i *Image) DrawTriangles(src *Image, vertices []float32, indices []uint16,
 	delayedCommandsM.Lock()
 	if needsToDelayCommands {
 		delayedCommands = append(delayedCommands, func() error {
-			i.drawTriangles(src, vertices, indices, colorm, mode, filter, address)
-			return nil
+			return i.drawTriangles(src, vertices, indices, colorm, mode, filter, address, clip, stencil, depth)
 		})
 		delayedCommandsM.Unlock()
-		return
+		return nil
 	}
 	delayedCommandsM.Unlock()
-	i.drawTriangles(src, vertices, indices, colorm, mode, filter, address)
+	return i.drawTriangles(src, vertices, indices, colorm, mode, filter, address, clip, stencil, depth)
 }
 
-func (i *Image) drawTriangles(src *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) {
-	src.resolvePendingPixels(true)
-	i.resolvePendingPixels(false)
-	i.img.DrawTriangles(src.img, vertices, indices, colorm, mode, filter, address)
+func (i *Image) drawTriangles(src *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) error {
+	if err := src.resolvePendingPixels(true); err != nil {
+		return err
+	}
+	if err := i.resolvePendingPixels(false); err != nil {
+		return err
+	}
+	i.img.DrawTriangles(src.img, vertices, indices, colorm, mode, filter, address, clip, stencil, depth)
+	i.rendered = true
+	return nil
 }
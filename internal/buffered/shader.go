@@ -0,0 +1,95 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffered
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/mipmap"
+	"github.com/hajimehoshi/ebiten/internal/shader"
+)
+
+// Shader represents a custom shader program.
+type Shader struct {
+	shader *mipmap.Shader
+}
+
+// NewShader returns a new shader.
+func NewShader(program *shader.Program) *Shader {
+	s := &Shader{}
+	delayedCommandsM.Lock()
+	if needsToDelayCommands {
+		delayedCommands = append(delayedCommands, func() error {
+			s.shader = mipmap.NewShader(program)
+			return nil
+		})
+		delayedCommandsM.Unlock()
+		return s
+	}
+	delayedCommandsM.Unlock()
+
+	s.shader = mipmap.NewShader(program)
+	return s
+}
+
+// Dispose disposes the shader.
+func (s *Shader) Dispose() {
+	delayedCommandsM.Lock()
+	if needsToDelayCommands {
+		delayedCommands = append(delayedCommands, func() error {
+			s.dispose()
+			return nil
+		})
+		delayedCommandsM.Unlock()
+		return
+	}
+	delayedCommandsM.Unlock()
+
+	s.dispose()
+}
+
+func (s *Shader) dispose() {
+	s.shader.Dispose()
+	s.shader = nil
+}
+
+// DrawShader draws triangles with the given image and the shader.
+func (i *Image) DrawShader(src *Image, vertices []float32, indices []uint16, shader *Shader, uniforms [][]float32, mode driver.CompositeMode) error {
+	if i == src {
+		panic("buffered: Image.DrawShader: src must be different from the receiver")
+	}
+
+	delayedCommandsM.Lock()
+	if needsToDelayCommands {
+		delayedCommands = append(delayedCommands, func() error {
+			return i.drawShader(src, vertices, indices, shader, uniforms, mode)
+		})
+		delayedCommandsM.Unlock()
+		return nil
+	}
+	delayedCommandsM.Unlock()
+	return i.drawShader(src, vertices, indices, shader, uniforms, mode)
+}
+
+func (i *Image) drawShader(src *Image, vertices []float32, indices []uint16, shader *Shader, uniforms [][]float32, mode driver.CompositeMode) error {
+	if err := src.resolvePendingPixels(true); err != nil {
+		return err
+	}
+	if err := i.resolvePendingPixels(false); err != nil {
+		return err
+	}
+	i.img.DrawShader(src.img, vertices, indices, shader.shader, uniforms, mode)
+	i.rendered = true
+	return nil
+}
@@ -33,6 +33,18 @@ var (
 	fpsCount    = 0
 	tpsCount    = 0
 
+	// updated reports whether Update has been called.
+	updated bool
+
+	// lastSuspended is the duration of the suspension detected at the last Update.
+	lastSuspended time.Duration
+
+	// lastFrameEnd is the time when the last frame ended.
+	lastFrameEnd int64
+
+	// idle is the duration of the intentional waits since the last frame ended.
+	idle time.Duration
+
 	m sync.Mutex
 )
 
@@ -57,23 +69,28 @@ func CurrentTPS() float64 {
 	return v
 }
 
-func calcCountFromTPS(tps int64, now int64) int {
+// calcCountFromTPS returns the number of the updates and the number of the discarded ticks.
+func calcCountFromTPS(tps int64, maxCount int64, now int64) (int, int) {
 	if tps == 0 {
-		return 0
+		return 0, 0
 	}
 	if tps < 0 {
 		panic("clock: tps must >= 0")
 	}
+	if maxCount <= 0 {
+		panic("clock: maxCount must > 0")
+	}
 
 	diff := now - lastSystemTime
 	if diff < 0 {
-		return 0
+		return 0, 0
 	}
 
 	count := 0
+	discarded := 0
 	syncWithSystemClock := false
 
-	if diff > int64(time.Second)*5/60 {
+	if diff > int64(time.Second)*maxCount/tps {
 		// The previous time is too old.
 		// Let's force to sync the game time with the system clock.
 		syncWithSystemClock = true
@@ -92,11 +109,49 @@ func calcCountFromTPS(tps int64, now int64) int {
 
 	if syncWithSystemClock {
 		lastSystemTime = now
+		if d := int(diff*tps/int64(time.Second)) - count; d > 0 {
+			discarded = d
+		}
 	} else {
 		lastSystemTime += int64(count) * int64(time.Second) / tps
 	}
 
-	return count
+	return count, discarded
+}
+
+// suspensionThreshold is the gap between two frames to regard the process as suspended.
+const suspensionThreshold = time.Second
+
+// Suspended returns the duration of the suspension detected at the last Update, or 0 if there is no suspension.
+// A suspension is notified by the OS when the system sleeps, or is detected as a gap of the clock between two frames
+// when the process is suspended e.g. by a debugger. The time spent in the frames and the intentional waits reported
+// by AddIdleTime are not regarded as a suspension.
+func Suspended() time.Duration {
+	m.Lock()
+	v := lastSuspended
+	m.Unlock()
+	return v
+}
+
+// AddIdleTime reports that the process waited intentionally for d, e.g., while the window is unfocused.
+// d is not regarded as a suspension.
+//
+// AddIdleTime is concurrent-safe.
+func AddIdleTime(d time.Duration) {
+	m.Lock()
+	idle += d
+	m.Unlock()
+}
+
+// EndFrame reports that the current frame ends.
+// The gap to detect a suspension is measured from the end of the last frame, then a long single frame is not
+// regarded as a suspension.
+//
+// EndFrame is concurrent-safe.
+func EndFrame() {
+	m.Lock()
+	lastFrameEnd = now()
+	m.Unlock()
 }
 
 func updateFPSAndTPS(now int64, count int) {
@@ -117,14 +172,32 @@ func updateFPSAndTPS(now int64, count int) {
 
 const UncappedTPS = -1
 
+// StartWatchingSleep starts watching the system sleeps notified by the OS. Without watching, a system sleep is
+// detected only as a gap of the clock.
+//
+// StartWatchingSleep and StopWatchingSleep must be called from the same goroutine, e.g., the main thread of the UI
+// driver.
+func StartWatchingSleep() {
+	watchSleep()
+}
+
+// StopWatchingSleep stops watching the system sleeps and releases the resources for it.
+func StopWatchingSleep() {
+	unwatchSleep()
+}
+
 // Update updates the inner clock state and returns an integer value
 // indicating how many times the game should update based on given tps.
 // tps represents TPS (ticks per second).
 // If tps is UncappedTPS, Update always returns 1.
 // If tps <= 0 and not UncappedTPS, Update always returns 0.
 //
+// maxCount is the maximum number of the updates to catch up with the system clock. When the game is behind by more
+// than maxCount ticks, the game time is synced with the system clock and the ticks are discarded.
+// Update returns the number of the discarded ticks as the second value.
+//
 // Update is expected to be called per frame.
-func Update(tps int) int {
+func Update(tps int, maxCount int) (int, int) {
 	m.Lock()
 	defer m.Unlock()
 
@@ -133,15 +206,39 @@ func Update(tps int) int {
 		// This ensures that now() must be monotonic (#875).
 		panic("clock: lastNow must be older than n")
 	}
+
+	// The monotonic clock doesn't advance during the system sleep on some platforms like Linux and macOS.
+	// Prefer the sleep notified by the OS, and fall back to the gap of the clock.
+	lastSuspended = sleptDuration()
+	if lastSuspended == 0 && updated {
+		from := lastNow
+		if lastFrameEnd > from {
+			from = lastFrameEnd
+		}
+		if g := time.Duration(n-from) - idle; g > suspensionThreshold {
+			lastSuspended = g
+		}
+	}
+	idle = 0
+	if lastSuspended > 0 {
+		// The process was suspended. Resync the game time with the system clock so that the game doesn't try
+		// to catch up with the lost ticks, and reset the FPS and TPS counts not to be affected by the gap.
+		lastSystemTime = n
+		lastUpdated = n
+		fpsCount = 0
+		tpsCount = 0
+	}
 	lastNow = n
+	updated = true
 
 	c := 0
+	d := 0
 	if tps == UncappedTPS {
 		c = 1
 	} else if tps > 0 {
-		c = calcCountFromTPS(int64(tps), n)
+		c, d = calcCountFromTPS(int64(tps), int64(maxCount), n)
 	}
 	updateFPSAndTPS(n, c)
 
-	return c
+	return c, d
 }
@@ -0,0 +1,59 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// sleepRecorder records the system sleeps notified by the OS.
+type sleepRecorder struct {
+	sleptAt time.Time
+	slept   time.Duration
+	m       sync.Mutex
+}
+
+func (s *sleepRecorder) sleep() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Strip the monotonic clock reading since the monotonic clock might not advance during the system sleep.
+	s.sleptAt = time.Now().Round(0)
+}
+
+func (s *sleepRecorder) wake() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// The OS might notify a wake more than once for one sleep.
+	if s.sleptAt.IsZero() {
+		return
+	}
+	if d := time.Now().Round(0).Sub(s.sleptAt); d > 0 {
+		s.slept += d
+	}
+	s.sleptAt = time.Time{}
+}
+
+// take returns the total duration of the system sleeps since the last call of take.
+func (s *sleepRecorder) take() time.Duration {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	d := s.slept
+	s.slept = 0
+	return d
+}
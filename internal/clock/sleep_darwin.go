@@ -0,0 +1,116 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin
+// +build !js
+// +build !ios
+
+package clock
+
+// #cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+//
+// #include <pthread.h>
+// #include <stdint.h>
+// #include <sys/time.h>
+//
+// #include <CoreFoundation/CoreFoundation.h>
+// #include <IOKit/IOMessage.h>
+// #include <IOKit/pwr_mgt/IOPMLib.h>
+//
+// static pthread_mutex_t sleepMutex = PTHREAD_MUTEX_INITIALIZER;
+// static io_connect_t rootPort;
+// static int64_t sleptAt;
+// static int64_t slept;
+//
+// static int64_t wallNow() {
+//   struct timeval tv;
+//   gettimeofday(&tv, NULL);
+//   return (int64_t)tv.tv_sec * 1000000000 + (int64_t)tv.tv_usec * 1000;
+// }
+//
+// static void onPowerMessage(void* refcon, io_service_t service, natural_t type, void* arg) {
+//   switch (type) {
+//   case kIOMessageCanSystemSleep:
+//     IOAllowPowerChange(rootPort, (long)arg);
+//     break;
+//   case kIOMessageSystemWillSleep:
+//     pthread_mutex_lock(&sleepMutex);
+//     sleptAt = wallNow();
+//     pthread_mutex_unlock(&sleepMutex);
+//     IOAllowPowerChange(rootPort, (long)arg);
+//     break;
+//   case kIOMessageSystemHasPoweredOn:
+//     pthread_mutex_lock(&sleepMutex);
+//     if (sleptAt) {
+//       int64_t d = wallNow() - sleptAt;
+//       if (d > 0) {
+//         slept += d;
+//       }
+//       sleptAt = 0;
+//     }
+//     pthread_mutex_unlock(&sleepMutex);
+//     break;
+//   }
+// }
+//
+// static IONotificationPortRef notificationPort;
+// static io_object_t notifier;
+//
+// static void watchSleep() {
+//   if (rootPort) {
+//     return;
+//   }
+//   rootPort = IORegisterForSystemPower(NULL, &notificationPort, onPowerMessage, &notifier);
+//   if (!rootPort) {
+//     return;
+//   }
+//   // The notifications are delivered on the main run loop, which is run by the event loop of the window.
+//   CFRunLoopAddSource(CFRunLoopGetMain(), IONotificationPortGetRunLoopSource(notificationPort), kCFRunLoopCommonModes);
+// }
+//
+// static void unwatchSleep() {
+//   if (!rootPort) {
+//     return;
+//   }
+//   CFRunLoopRemoveSource(CFRunLoopGetMain(), IONotificationPortGetRunLoopSource(notificationPort), kCFRunLoopCommonModes);
+//   IODeregisterForSystemPower(&notifier);
+//   IOServiceClose(rootPort);
+//   IONotificationPortDestroy(notificationPort);
+//   rootPort = 0;
+// }
+//
+// static int64_t takeSlept() {
+//   pthread_mutex_lock(&sleepMutex);
+//   int64_t d = slept;
+//   slept = 0;
+//   pthread_mutex_unlock(&sleepMutex);
+//   return d;
+// }
+import "C"
+
+import (
+	"time"
+)
+
+func watchSleep() {
+	C.watchSleep()
+}
+
+func unwatchSleep() {
+	C.unwatchSleep()
+}
+
+func sleptDuration() time.Duration {
+	return time.Duration(C.takeSlept())
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build dragonfly freebsd linux netbsd openbsd solaris
+// +build !js
+// +build !android
+
+package clock
+
+import (
+	"bufio"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var (
+	theSleepRecorder sleepRecorder
+
+	// sleepWatcher is the gdbus process monitoring the signals. sleepWatcherDone is closed after the process is
+	// waited.
+	sleepWatcher     *exec.Cmd
+	sleepWatcherDone chan struct{}
+)
+
+// watchSleep monitors the PrepareForSleep signal of systemd-logind.
+// If gdbus or systemd-logind is not available, the system sleep is detected only as a gap of the clock.
+func watchSleep() {
+	if sleepWatcher != nil {
+		return
+	}
+	if _, err := exec.LookPath("gdbus"); err != nil {
+		return
+	}
+
+	cmd := exec.Command("gdbus", "monitor", "--system", "--dest", "org.freedesktop.login1", "--object-path", "/org/freedesktop/login1")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		return
+	}
+
+	done := make(chan struct{})
+	sleepWatcher = cmd
+	sleepWatcherDone = done
+
+	go func() {
+		defer close(done)
+
+		// A signal is printed like "/org/freedesktop/login1: org.freedesktop.login1.Manager.PrepareForSleep (true,)".
+		s := bufio.NewScanner(out)
+		for s.Scan() {
+			l := s.Text()
+			if !strings.Contains(l, ".PrepareForSleep (") {
+				continue
+			}
+			if strings.Contains(l, "(true") {
+				theSleepRecorder.sleep()
+			} else {
+				theSleepRecorder.wake()
+			}
+		}
+		// Wait for the process even when it exits by itself, or the process would be a zombie.
+		_ = cmd.Wait()
+	}()
+}
+
+func unwatchSleep() {
+	if sleepWatcher == nil {
+		return
+	}
+	// Killing the process closes the output, and then the goroutine waits for the process.
+	_ = sleepWatcher.Process.Kill()
+	<-sleepWatcherDone
+	sleepWatcher = nil
+	sleepWatcherDone = nil
+}
+
+func sleptDuration() time.Duration {
+	return theSleepRecorder.take()
+}
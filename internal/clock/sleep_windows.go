@@ -0,0 +1,86 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	deviceNotifyCallback = 2
+
+	pbtAPMSuspend         = 0x4
+	pbtAPMResumeSuspend   = 0x7
+	pbtAPMResumeAutomatic = 0x12
+)
+
+// deviceNotifySubscribeParameters is DEVICE_NOTIFY_SUBSCRIBE_PARAMETERS.
+type deviceNotifySubscribeParameters struct {
+	callback uintptr
+	context  uintptr
+}
+
+var (
+	powrprof                                     = windows.NewLazySystemDLL("powrprof.dll")
+	procPowerRegisterSuspendResumeNotification   = powrprof.NewProc("PowerRegisterSuspendResumeNotification")
+	procPowerUnregisterSuspendResumeNotification = powrprof.NewProc("PowerUnregisterSuspendResumeNotification")
+)
+
+var (
+	theSleepRecorder sleepRecorder
+
+	// sleepParams must live until the notification is unregistered since the OS refers it.
+	sleepParams deviceNotifySubscribeParameters
+	sleepNotify uintptr
+)
+
+func watchSleep() {
+	if sleepNotify != 0 {
+		return
+	}
+	// PowerRegisterSuspendResumeNotification is available as of Windows 8.
+	// On the older versions, the system sleep is detected only as a gap of the clock.
+	if procPowerRegisterSuspendResumeNotification.Find() != nil {
+		return
+	}
+	// A callback created by NewCallback is never released. Reuse it.
+	if sleepParams.callback == 0 {
+		sleepParams.callback = windows.NewCallback(func(context, typ, setting uintptr) uintptr {
+			switch typ {
+			case pbtAPMSuspend:
+				theSleepRecorder.sleep()
+			case pbtAPMResumeSuspend, pbtAPMResumeAutomatic:
+				theSleepRecorder.wake()
+			}
+			return 0
+		})
+	}
+	_, _, _ = procPowerRegisterSuspendResumeNotification.Call(deviceNotifyCallback, uintptr(unsafe.Pointer(&sleepParams)), uintptr(unsafe.Pointer(&sleepNotify)))
+}
+
+func unwatchSleep() {
+	if sleepNotify == 0 {
+		return
+	}
+	_, _, _ = procPowerUnregisterSuspendResumeNotification.Call(sleepNotify)
+	sleepNotify = 0
+}
+
+func sleptDuration() time.Duration {
+	return theSleepRecorder.take()
+}
@@ -35,8 +35,12 @@ const (
 	CompositeModeDestinationAtop
 	CompositeModeXor
 	CompositeModeLighter
+	CompositeModeSubtract
+	CompositeModeReverseSubtract
+	CompositeModeDarken
+	CompositeModeLighten
 
-	CompositeModeMax = CompositeModeLighter
+	CompositeModeMax = CompositeModeLighten
 )
 
 type Operation int
@@ -50,6 +54,39 @@ const (
 	OneMinusDstAlpha
 )
 
+type BlendEquation int
+
+const (
+	BlendEquationAdd BlendEquation = iota
+	BlendEquationSubtract
+	BlendEquationReverseSubtract
+	BlendEquationMin
+	BlendEquationMax
+)
+
+// Equations returns the equations to combine the source and the destination multiplied by the factors of
+// Operations, for the RGB components and the alpha component respectively.
+//
+// For BlendEquationMin and BlendEquationMax, the factors are ignored.
+func (c CompositeMode) Equations() (rgb BlendEquation, alpha BlendEquation) {
+	switch c {
+	case CompositeModeSubtract:
+		// Subtracting the alpha values would make the result transparent. The alpha values are added instead.
+		return BlendEquationReverseSubtract, BlendEquationAdd
+	case CompositeModeReverseSubtract:
+		return BlendEquationSubtract, BlendEquationAdd
+	case CompositeModeDarken:
+		return BlendEquationMin, BlendEquationMin
+	case CompositeModeLighten:
+		return BlendEquationMax, BlendEquationMax
+	default:
+		if c < CompositeModeSourceOver || CompositeModeMax < c {
+			panic(fmt.Sprintf("graphics: invalid composite mode: %d", c))
+		}
+		return BlendEquationAdd, BlendEquationAdd
+	}
+}
+
 func (c CompositeMode) Operations() (src Operation, dst Operation) {
 	switch c {
 	case CompositeModeSourceOver:
@@ -76,7 +113,7 @@ func (c CompositeMode) Operations() (src Operation, dst Operation) {
 		return OneMinusDstAlpha, SrcAlpha
 	case CompositeModeXor:
 		return OneMinusDstAlpha, OneMinusSrcAlpha
-	case CompositeModeLighter:
+	case CompositeModeLighter, CompositeModeSubtract, CompositeModeReverseSubtract, CompositeModeDarken, CompositeModeLighten:
 		return One, One
 	default:
 		panic(fmt.Sprintf("graphics: invalid composite mode: %d", c))
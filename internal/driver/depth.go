@@ -0,0 +1,37 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+// DepthMode represents how a draw call uses the depth buffer of the destination.
+type DepthMode int
+
+const (
+	// DepthModeNone doesn't use the depth buffer.
+	DepthModeNone DepthMode = iota
+
+	// DepthModeClear clears the depth buffer if the destination has it, and renders without the depth test.
+	DepthModeClear
+
+	// DepthModeTest renders only the pixels whose Z is equal to or larger than the Z already written, and writes Z.
+	DepthModeTest
+)
+
+// Depth represents the depth state of a draw call.
+type Depth struct {
+	Mode DepthMode
+
+	// Z is the depth of the triangles in [0, 1]. A larger Z is in front. Z is used only with DepthModeTest.
+	Z float32
+}
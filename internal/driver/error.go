@@ -0,0 +1,30 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+)
+
+// OutOfMemoryError is an error when the graphics driver runs out of the GPU memory to create an image.
+type OutOfMemoryError struct {
+	// Width and Height are the size of the image that cannot be created.
+	Width  int
+	Height int
+}
+
+func (e *OutOfMemoryError) Error() string {
+	return fmt.Sprintf("driver: out of GPU memory to create an image (%d, %d)", e.Width, e.Height)
+}
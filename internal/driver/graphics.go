@@ -15,7 +15,10 @@
 package driver
 
 import (
+	"image"
+
 	"github.com/hajimehoshi/ebiten/internal/affine"
+	"github.com/hajimehoshi/ebiten/internal/shader"
 	"github.com/hajimehoshi/ebiten/internal/thread"
 )
 
@@ -24,19 +27,44 @@ type Graphics interface {
 	Begin()
 	End()
 	SetTransparent(transparent bool)
-	SetVertices(vertices []float32, indices []uint16)
+
+	// SetVertices sets the vertices and the indices for the following draw calls.
+	// The indices never exceed 16 bits unless Has32BitIndices returns true.
+	SetVertices(vertices []float32, indices []uint32)
 	NewImage(width, height int) (Image, error)
 	NewScreenFramebufferImage(width, height int) (Image, error)
 	Reset() error
-	Draw(indexLen int, indexOffset int, mode CompositeMode, colorM *affine.ColorM, filter Filter, address Address) error
+
+	// Draw draws the triangles. clip is the region of the destination to render in pixels.
+	// If clip is empty, the whole destination is rendered. stencil specifies how the stencil buffer of the destination
+	// is used.
+	Draw(indexLen int, indexOffset int, mode CompositeMode, colorM *affine.ColorM, filter Filter, address Address, clip image.Rectangle, stencil StencilMode, depth Depth) error
+
+	NewShader(program *shader.Program) (Shader, error)
+
+	// DrawShader draws the triangles with the shader. uniforms are the values of the program's uniform variables
+	// in the declared order.
+	DrawShader(indexLen int, indexOffset int, shader Shader, uniforms [][]float32, mode CompositeMode) error
 	SetVsyncEnabled(enabled bool)
 	VDirection() VDirection
 	NeedsRestoring() bool
 	IsGL() bool
 	HasHighPrecisionFloat() bool
+
+	// Has32BitIndices reports whether the indices for SetVertices can exceed 16 bits.
+	Has32BitIndices() bool
 	MaxImageSize() int
 }
 
+// Fence is a sync point to tell whether the GPU has finished the commands submitted before the fence.
+type Fence interface {
+	// IsSignaled reports whether the GPU has finished the commands before the fence. IsSignaled doesn't block.
+	IsSignaled() bool
+
+	// Release releases the fence.
+	Release()
+}
+
 type Image interface {
 	Dispose()
 	IsInvalidated() bool
@@ -46,6 +74,10 @@ type Image interface {
 	ReplacePixels(args []*ReplacePixelsArgs)
 }
 
+type Shader interface {
+	Dispose()
+}
+
 type ReplacePixelsArgs struct {
 	Pixels []byte
 	X      int
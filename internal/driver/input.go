@@ -16,15 +16,20 @@ package driver
 
 type Input interface {
 	CursorPosition() (x, y int)
+	GamepadAccelerometer(id int) (x, y, z float64, ok bool)
+	GamepadGyro(id int) (x, y, z float64, ok bool)
 	GamepadSDLID(id int) string
 	GamepadName(id int) string
 	GamepadAxis(id int, axis int) float64
 	GamepadAxisNum(id int) int
 	GamepadButtonNum(id int) int
 	GamepadIDs() []int
+	GamepadTouchpadPosition(id int, touchID int) (x, y float64)
+	GamepadTouchpadTouchIDs(id int) []int
 	IsGamepadButtonPressed(id int, button GamepadButton) bool
 	IsKeyPressed(key Key) bool
 	IsMouseButtonPressed(button MouseButton) bool
+	LatestCursorPosition() (x, y int)
 	ResetForFrame()
 	RuneBuffer() []rune
 	TouchIDs() []int
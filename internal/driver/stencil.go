@@ -0,0 +1,32 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+// StencilMode represents how a draw call uses the stencil buffer of the destination.
+type StencilMode int
+
+const (
+	// StencilModeNone doesn't use the stencil buffer.
+	StencilModeNone StencilMode = iota
+
+	// StencilModeWrite clears the stencil buffer and marks the rendered region without updating the colors.
+	StencilModeWrite
+
+	// StencilModeTest renders only in the marked region.
+	StencilModeTest
+
+	// StencilModeTestInverted renders only outside of the marked region.
+	StencilModeTestInverted
+)
@@ -36,25 +36,82 @@ type UI interface {
 
 	DeviceScaleFactor() float64
 	CursorMode() CursorMode
+	CursorShape() CursorShape
 	IsFullscreen() bool
 	IsForeground() bool
 	IsRunnableInBackground() bool
 	IsVsyncEnabled() bool
+	SwapInterval() int
+	IsLowLatencyModeEnabled() bool
+	MaxQueuedFrames() int
+	IsBackgroundTextureUploadEnabled() bool
+	IsScreenSaverEnabled() bool
+	IsCaptureFriendlyModeEnabled() bool
 	ScreenSizeInFullscreen() (int, int)
 	IsScreenTransparent() bool
 	MonitorPosition() (int, int)
+	VideoModes() []VideoMode
+	FullscreenVideoMode() VideoMode
+	Monitors() []Monitor
+	FullscreenMonitor() int
+	IsFullscreenSpanningEnabled() bool
 
 	SetCursorMode(mode CursorMode)
+	SetCursorShape(shape CursorShape)
+
+	// SetCursorConfinement sets the region to confine the cursor.
+	// The unit is device-independent pixels on the window. An empty region releases the cursor.
+	SetCursorConfinement(region image.Rectangle)
 	SetFullscreen(fullscreen bool)
 	SetRunnableInBackground(runnableInBackground bool)
 	SetVsyncEnabled(enabled bool)
+
+	// SetSwapInterval sets the number of the display's refreshes to wait for before swapping buffers.
+	// 0 means that vsync is disabled.
+	SetSwapInterval(interval int)
+	SetLowLatencyModeEnabled(enabled bool)
+	SetMaxQueuedFrames(frames int)
+	SetBackgroundTextureUploadEnabled(enabled bool)
+	SetScreenSaverEnabled(enabled bool)
+	SetCaptureFriendlyModeEnabled(enabled bool)
 	SetScreenTransparent(transparent bool)
+	SetFullscreenVideoMode(mode VideoMode)
+
+	// SetFullscreenMonitor sets the index of the monitor used in fullscreen mode.
+	// A negative index means the monitor which the window belongs to.
+	SetFullscreenMonitor(index int)
+	SetFullscreenSpanningEnabled(enabled bool)
+
+	Announce(text string)
+
+	// SetInputMethodCaretPosition sets the caret position for input methods.
+	// The unit is device-dependent pixels on the screen framebuffer.
+	SetInputMethodCaretPosition(x, y, height int)
 
 	Input() Input
 	Window() Window
 	Graphics() Graphics
 }
 
+// VideoMode represents a display mode of a monitor.
+//
+// The unit of Width and Height is device-dependent pixels.
+type VideoMode struct {
+	Width       int
+	Height      int
+	RefreshRate int
+}
+
+// Monitor represents a monitor.
+//
+// The unit of X, Y, Width and Height is device-dependent pixels in the virtual screen coordinate.
+type Monitor struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
 type Window interface {
 	IsDecorated() bool
 	SetDecorated(decorated bool)
@@ -70,4 +127,8 @@ type Window interface {
 
 	SetIcon(iconImages []image.Image)
 	SetTitle(title string)
+
+	IsFocused() bool
+	IsMinimized() bool
+	IsHovered() bool
 }
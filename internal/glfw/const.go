@@ -28,6 +28,7 @@ type (
 	ModifierKey     int
 	MouseButton     int
 	PeripheralEvent int
+	StandardCursor  int
 )
 
 const (
@@ -78,6 +79,8 @@ const (
 	ContextVersionMinor    = Hint(0x00022003)
 	Decorated              = Hint(0x00020005)
 	Focused                = Hint(0x00020001)
+	Hovered                = Hint(0x0002000B)
+	Iconified              = Hint(0x00020002)
 	Resizable              = Hint(0x00020003)
 	TransparentFramebuffer = Hint(0x0002000A)
 	Visible                = Hint(0x00020004)
@@ -96,6 +99,15 @@ const (
 	NoAPI          = 0
 )
 
+const (
+	ArrowCursor     = StandardCursor(0x00036001)
+	IBeamCursor     = StandardCursor(0x00036002)
+	CrosshairCursor = StandardCursor(0x00036003)
+	HandCursor      = StandardCursor(0x00036004)
+	HResizeCursor   = StandardCursor(0x00036005)
+	VResizeCursor   = StandardCursor(0x00036006)
+)
+
 const (
 	NotInitialized     = ErrorCode(0x00010001)
 	NoCurrentContext   = ErrorCode(0x00010002)
@@ -81,6 +81,33 @@ func (m *Monitor) GetVideoMode() *VidMode {
 	}
 }
 
+func (m *Monitor) GetVideoModes() []*VidMode {
+	var vs []*VidMode
+	for _, v := range m.m.GetVideoModes() {
+		vs = append(vs, &VidMode{
+			Width:       v.Width,
+			Height:      v.Height,
+			RedBits:     v.RedBits,
+			GreenBits:   v.GreenBits,
+			BlueBits:    v.BlueBits,
+			RefreshRate: v.RefreshRate,
+		})
+	}
+	return vs
+}
+
+type Cursor struct {
+	c *glfw.Cursor
+}
+
+func CreateStandardCursor(shape StandardCursor) *Cursor {
+	c := glfw.CreateStandardCursor(glfw.StandardCursor(shape))
+	if c == nil {
+		return nil
+	}
+	return &Cursor{c}
+}
+
 type Window struct {
 	w *glfw.Window
 }
@@ -102,6 +129,10 @@ func (w *Window) GetCursorPos() (x, y float64) {
 	return w.w.GetCursorPos()
 }
 
+func (w *Window) SetCursorPos(xpos, ypos float64) {
+	w.w.SetCursorPos(xpos, ypos)
+}
+
 func (w *Window) GetInputMode(mode InputMode) int {
 	return w.w.GetInputMode(glfw.InputMode(mode))
 }
@@ -178,6 +209,14 @@ func (w *Window) SetSizeCallback(cbfun SizeCallback) (previous SizeCallback) {
 	return nil // TODO
 }
 
+func (w *Window) SetCursor(cursor *Cursor) {
+	var c *glfw.Cursor
+	if cursor != nil {
+		c = cursor.c
+	}
+	w.w.SetCursor(c)
+}
+
 func (w *Window) SetIcon(images []image.Image) {
 	w.w.SetIcon(images)
 }
@@ -255,6 +294,18 @@ func (j Joystick) GetButtons() []Action {
 	return bs
 }
 
+func DefaultWindowHints() {
+	glfw.DefaultWindowHints()
+}
+
+func DetachCurrentContext() {
+	glfw.DetachCurrentContext()
+}
+
+func ExtensionSupported(extension string) bool {
+	return glfw.ExtensionSupported(extension)
+}
+
 func GetMonitors() []*Monitor {
 	ms := []*Monitor{}
 	for _, m := range glfw.GetMonitors() {
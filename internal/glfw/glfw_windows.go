@@ -17,6 +17,7 @@ package glfw
 import (
 	"image"
 	"image/draw"
+	"math"
 	"runtime"
 	"sync"
 	"unsafe"
@@ -98,6 +99,43 @@ func (m *Monitor) GetVideoMode() *VidMode {
 	}
 }
 
+func (m *Monitor) GetVideoModes() []*VidMode {
+	var num int32
+	v := glfwDLL.call("glfwGetVideoModes", m.m, uintptr(unsafe.Pointer(&num)))
+	panicError()
+	if num == 0 {
+		return nil
+	}
+	// Convert the returned address via a pointer to avoid converting a uintptr value to unsafe.Pointer directly.
+	modes := (*[1 << 20]glfwVidMode)(*(*unsafe.Pointer)(unsafe.Pointer(&v)))[:num:num]
+	var vs []*VidMode
+	for i := range modes {
+		vv := &modes[i]
+		vs = append(vs, &VidMode{
+			Width:       int(vv.width),
+			Height:      int(vv.height),
+			RedBits:     int(vv.redBits),
+			GreenBits:   int(vv.greenBits),
+			BlueBits:    int(vv.blueBits),
+			RefreshRate: int(vv.refreshRate),
+		})
+	}
+	return vs
+}
+
+type Cursor struct {
+	c uintptr
+}
+
+func CreateStandardCursor(shape StandardCursor) *Cursor {
+	c := glfwDLL.call("glfwCreateStandardCursor", uintptr(shape))
+	panicError()
+	if c == 0 {
+		return nil
+	}
+	return &Cursor{c}
+}
+
 type Window struct {
 	w uintptr
 }
@@ -125,6 +163,19 @@ func (w *Window) GetCursorPos() (x, y float64) {
 	return
 }
 
+func (w *Window) SetCursorPos(xpos, ypos float64) {
+	x, y := math.Float64bits(xpos), math.Float64bits(ypos)
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		// On 64bit Windows, floating point arguments are passed via XMM registers, and syscall sets the
+		// arguments to both the general registers and the XMM registers.
+		glfwDLL.call("glfwSetCursorPos", w.w, uintptr(x), uintptr(y))
+	} else {
+		// On 32bit Windows, a float64 argument takes two slots of the stack.
+		glfwDLL.call("glfwSetCursorPos", w.w, uintptr(x), uintptr(x>>32), uintptr(y), uintptr(y>>32))
+	}
+	panicError()
+}
+
 func (w *Window) GetInputMode(mode InputMode) int {
 	r := glfwDLL.call("glfwGetInputMode", w.w, uintptr(mode))
 	panicError()
@@ -225,6 +276,15 @@ func (w *Window) SetSizeCallback(cbfun SizeCallback) (previous FramebufferSizeCa
 	return nil // TODO
 }
 
+func (w *Window) SetCursor(cursor *Cursor) {
+	var c uintptr
+	if cursor != nil {
+		c = cursor.c
+	}
+	glfwDLL.call("glfwSetCursor", w.w, c)
+	panicError()
+}
+
 func (w *Window) SetIcon(images []image.Image) {
 	gimgs := make([]glfwImage, len(images))
 	defer runtime.KeepAlive(gimgs)
@@ -369,6 +429,25 @@ func (j Joystick) GetButtons() []byte {
 	return bs
 }
 
+func DefaultWindowHints() {
+	glfwDLL.call("glfwDefaultWindowHints")
+	panicError()
+}
+
+func DetachCurrentContext() {
+	glfwDLL.call("glfwMakeContextCurrent", 0)
+	panicError()
+}
+
+func ExtensionSupported(extension string) bool {
+	s := []byte(extension)
+	s = append(s, 0)
+	defer runtime.KeepAlive(s)
+	r := glfwDLL.call("glfwExtensionSupported", uintptr(unsafe.Pointer(&s[0])))
+	panicError()
+	return r == True
+}
+
 func GetMonitors() []*Monitor {
 	var l int32
 	ptr := glfwDLL.call("glfwGetMonitors", uintptr(unsafe.Pointer(&l)))
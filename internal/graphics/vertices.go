@@ -17,6 +17,9 @@ package graphics
 const (
 	IndicesNum     = (1 << 16) / 3 * 3 // Adjust num for triangles.
 	VertexFloatNum = 12
+
+	// IndicesNum32 is the maximum number of indices in one batch when the graphics driver supports 32-bit indices.
+	IndicesNum32 = (1 << 18) / 3 * 3
 )
 
 var (
@@ -16,7 +16,10 @@ package graphicscommand
 
 import (
 	"fmt"
+	"image"
 	"math"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/internal/affine"
 	"github.com/hajimehoshi/ebiten/internal/driver"
@@ -52,7 +55,7 @@ type command interface {
 	NumIndices() int
 	AddNumVertices(n int)
 	AddNumIndices(n int)
-	CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) bool
+	CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool
 }
 
 type size struct {
@@ -78,7 +81,7 @@ type commandQueue struct {
 
 	srcSizes []size
 
-	indices  []uint16
+	indices  []uint32
 	nindices int
 
 	tmpNumIndices int
@@ -109,40 +112,28 @@ func (q *commandQueue) appendVertices(vertices []float32, width, height float32)
 	q.nvertices += len(vertices)
 }
 
-func (q *commandQueue) appendIndices(indices []uint16, offset uint16) {
+func (q *commandQueue) appendIndices(indices []uint16, offset uint32) {
 	if len(q.indices) < q.nindices+len(indices) {
 		n := q.nindices + len(indices) - len(q.indices)
-		q.indices = append(q.indices, make([]uint16, n)...)
+		q.indices = append(q.indices, make([]uint32, n)...)
 	}
 	for i := range indices {
-		q.indices[q.nindices+i] = indices[i] + offset
+		q.indices[q.nindices+i] = uint32(indices[i]) + offset
 	}
 	q.nindices += len(indices)
 }
 
 // EnqueueDrawTrianglesCommand enqueues a drawing-image command.
-func (q *commandQueue) EnqueueDrawTrianglesCommand(dst, src *Image, vertices []float32, indices []uint16, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) {
+func (q *commandQueue) EnqueueDrawTrianglesCommand(dst, src *Image, vertices []float32, indices []uint16, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) {
 	if len(indices) > graphics.IndicesNum {
 		panic(fmt.Sprintf("graphicscommand: len(indices) must be <= graphics.IndicesNum but not at EnqueueDrawTrianglesCommand: len(indices): %d, graphics.IndicesNum: %d", len(indices), graphics.IndicesNum))
 	}
 
-	split := false
-	if q.tmpNumIndices+len(indices) > graphics.IndicesNum {
-		q.tmpNumIndices = 0
-		q.nextIndex = 0
-		split = true
-	}
-
-	n := len(vertices) / graphics.VertexFloatNum
-	iw, ih := src.InternalSize()
-	q.appendVertices(vertices, float32(iw), float32(ih))
-	q.appendIndices(indices, uint16(q.nextIndex))
-	q.nextIndex += n
-	q.tmpNumIndices += len(indices)
+	split := q.appendTriangles(src, vertices, indices)
 
 	// TODO: If dst is the screen, reorder the command to be the last.
 	if !split && 0 < len(q.commands) {
-		if last := q.commands[len(q.commands)-1]; last.CanMergeWithDrawTrianglesCommand(dst, src, color, mode, filter, address) {
+		if last := q.commands[len(q.commands)-1]; last.CanMergeWithDrawTrianglesCommand(dst, src, color, mode, filter, address, clip, stencil, depth) {
 			last.AddNumVertices(len(vertices))
 			last.AddNumIndices(len(indices))
 			return
@@ -157,8 +148,61 @@ func (q *commandQueue) EnqueueDrawTrianglesCommand(dst, src *Image, vertices []f
 		mode:      mode,
 		filter:    filter,
 		address:   address,
+		clip:      clip,
+		stencil:   stencil,
+		depth:     depth,
 	}
-	q.commands = append(q.commands, c)
+	q.appendCommand(c)
+}
+
+// EnqueueDrawShaderCommand enqueues a command to draw triangles with a shader.
+func (q *commandQueue) EnqueueDrawShaderCommand(dst, src *Image, vertices []float32, indices []uint16, shader *Shader, uniforms [][]float32, mode driver.CompositeMode) {
+	if len(indices) > graphics.IndicesNum {
+		panic(fmt.Sprintf("graphicscommand: len(indices) must be <= graphics.IndicesNum but not at EnqueueDrawShaderCommand: len(indices): %d, graphics.IndicesNum: %d", len(indices), graphics.IndicesNum))
+	}
+
+	q.appendTriangles(src, vertices, indices)
+	c := &drawShaderCommand{
+		dst:       dst,
+		src:       src,
+		shader:    shader,
+		uniforms:  uniforms,
+		nvertices: len(vertices),
+		nindices:  len(indices),
+		mode:      mode,
+	}
+	q.appendCommand(c)
+}
+
+// indicesNum returns the maximum number of the indices in one batch of draw calls.
+//
+// The indices of one draw-triangles command are always within 16 bits, but a batch of commands can have 32-bit
+// indices if the graphics driver supports them.
+func indicesNum() int {
+	if theGraphicsDriver.Has32BitIndices() {
+		return graphics.IndicesNum32
+	}
+	return graphics.IndicesNum
+}
+
+// appendTriangles appends the vertices and the indices to the queue.
+//
+// appendTriangles returns true when the indices exceed the current index buffer and a new one is started.
+func (q *commandQueue) appendTriangles(src *Image, vertices []float32, indices []uint16) bool {
+	split := false
+	if q.tmpNumIndices+len(indices) > indicesNum() {
+		q.tmpNumIndices = 0
+		q.nextIndex = 0
+		split = true
+	}
+
+	n := len(vertices) / graphics.VertexFloatNum
+	iw, ih := src.InternalSize()
+	q.appendVertices(vertices, float32(iw), float32(ih))
+	q.appendIndices(indices, uint32(q.nextIndex))
+	q.nextIndex += n
+	q.tmpNumIndices += len(indices)
+	return split
 }
 
 // Enqueue enqueues a drawing command other than a draw-triangles command.
@@ -166,7 +210,12 @@ func (q *commandQueue) EnqueueDrawTrianglesCommand(dst, src *Image, vertices []f
 // For a draw-triangles command, use EnqueueDrawTrianglesCommand.
 func (q *commandQueue) Enqueue(command command) {
 	// TODO: If dst is the screen, reorder the command to be the last.
+	q.appendCommand(command)
+}
+
+func (q *commandQueue) appendCommand(command command) {
 	q.commands = append(q.commands, command)
+	atomic.AddInt64(&commandCount, 1)
 }
 
 func fract(x float32) float32 {
@@ -181,6 +230,27 @@ func (q *commandQueue) Flush() error {
 		fmt.Println("--")
 	}
 
+	floatImagesOnce.Do(func() {
+		if g, ok := theGraphicsDriver.(interface{ HasFloatImages() bool }); ok && g.HasFloatImages() {
+			atomic.StoreInt32(&floatImagesAvailable, 1)
+		}
+	})
+	alphaImagesOnce.Do(func() {
+		if g, ok := theGraphicsDriver.(interface{ HasAlphaImages() bool }); ok && g.HasAlphaImages() {
+			atomic.StoreInt32(&alphaImagesAvailable, 1)
+		}
+	})
+
+	// The sRGB pipeline must be enabled before any images are created at the graphics driver.
+	srgbOnce.Do(func() {
+		if atomic.LoadInt32(&srgbRequested) == 0 {
+			return
+		}
+		if g, ok := theGraphicsDriver.(interface{ EnableSRGB() bool }); ok && g.EnableSRGB() {
+			atomic.StoreInt32(&srgbEnabled, 1)
+		}
+	})
+
 	if theGraphicsDriver.HasHighPrecisionFloat() {
 		const dstAdjustmentFactor = 1.0 / 256.0
 		const texelAdjustmentFactor = 1.0 / 512.0
@@ -241,10 +311,10 @@ func (q *commandQueue) Flush() error {
 		ne := 0
 		nc := 0
 		for _, c := range cs {
-			if c.NumIndices() > graphics.IndicesNum {
-				panic(fmt.Sprintf("graphicscommand: c.NumIndices() must be <= graphics.IndicesNum but not at Flush: c.NumIndices(): %d, graphics.IndicesNum: %d", c.NumIndices(), graphics.IndicesNum))
+			if c.NumIndices() > indicesNum() {
+				panic(fmt.Sprintf("graphicscommand: c.NumIndices() must be <= indicesNum() but not at Flush: c.NumIndices(): %d, indicesNum(): %d", c.NumIndices(), indicesNum()))
 			}
-			if ne+c.NumIndices() > graphics.IndicesNum {
+			if ne+c.NumIndices() > indicesNum() {
 				break
 			}
 			nv += c.NumVertices()
@@ -280,6 +350,106 @@ func (q *commandQueue) Flush() error {
 	return nil
 }
 
+// drawCallCount is the number of the draw calls to the graphics driver so far.
+var drawCallCount int64
+
+// DrawCallCount returns the number of the draw calls to the graphics driver since the application started.
+//
+// DrawCallCount is concurrent-safe.
+func DrawCallCount() int64 {
+	return atomic.LoadInt64(&drawCallCount)
+}
+
+// commandCount is the number of the enqueued commands so far.
+var commandCount int64
+
+// CommandCount returns the number of the enqueued commands since the application started.
+//
+// A draw-triangles command merged into the previous command is not counted. Then, CommandCount is useful to know
+// whether a drawing operation breaks batching.
+//
+// CommandCount is concurrent-safe.
+func CommandCount() int64 {
+	return atomic.LoadInt64(&commandCount)
+}
+
+func countDrawCall() {
+	atomic.AddInt64(&drawCallCount, 1)
+}
+
+var (
+	floatImagesAvailable int32
+	floatImagesOnce      sync.Once
+)
+
+// FloatImagesAvailable reports whether the graphics driver can create images with floating-point values.
+//
+// FloatImagesAvailable returns false until the command queue is flushed first.
+//
+// FloatImagesAvailable is concurrent-safe.
+func FloatImagesAvailable() bool {
+	return atomic.LoadInt32(&floatImagesAvailable) != 0
+}
+
+var (
+	alphaImagesAvailable int32
+	alphaImagesOnce      sync.Once
+)
+
+// AlphaImagesAvailable reports whether the graphics driver can create images with only alpha values.
+//
+// AlphaImagesAvailable returns false until the command queue is flushed first.
+//
+// AlphaImagesAvailable is concurrent-safe.
+func AlphaImagesAvailable() bool {
+	return atomic.LoadInt32(&alphaImagesAvailable) != 0
+}
+
+var (
+	srgbRequested int32
+	srgbEnabled   int32
+	srgbOnce      sync.Once
+)
+
+// SetSRGBEnabled sets whether the sRGB-correct pipeline is requested.
+//
+// SetSRGBEnabled must be called before the command queue is flushed first. Otherwise, SetSRGBEnabled does nothing.
+//
+// SetSRGBEnabled is concurrent-safe.
+func SetSRGBEnabled(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&srgbRequested, v)
+}
+
+// SRGBEnabled reports whether the sRGB-correct pipeline is actually used by the graphics driver.
+//
+// SRGBEnabled returns false until the command queue is flushed first.
+//
+// SRGBEnabled is concurrent-safe.
+func SRGBEnabled() bool {
+	return atomic.LoadInt32(&srgbEnabled) != 0
+}
+
+// SRGBRequested reports whether the sRGB-correct pipeline is requested by SetSRGBEnabled.
+//
+// SRGBRequested is concurrent-safe.
+func SRGBRequested() bool {
+	return atomic.LoadInt32(&srgbRequested) != 0
+}
+
+// BackgroundUploadAvailable reports whether the graphics driver uploads large pixels on another thread.
+//
+// BackgroundUploadAvailable is concurrent-safe.
+func BackgroundUploadAvailable() bool {
+	if g, ok := theGraphicsDriver.(interface{ HasLoader() bool }); ok {
+		return g.HasLoader()
+	}
+	return false
+}
+
 // FlushCommands flushes the command queue.
 func FlushCommands() error {
 	return theCommandQueue.Flush()
@@ -295,6 +465,9 @@ type drawTrianglesCommand struct {
 	mode      driver.CompositeMode
 	filter    driver.Filter
 	address   driver.Address
+	clip      image.Rectangle
+	stencil   driver.StencilMode
+	depth     driver.Depth
 }
 
 func (c *drawTrianglesCommand) String() string {
@@ -326,6 +499,14 @@ func (c *drawTrianglesCommand) String() string {
 		mode = "xor"
 	case driver.CompositeModeLighter:
 		mode = "lighter"
+	case driver.CompositeModeSubtract:
+		mode = "subtract"
+	case driver.CompositeModeReverseSubtract:
+		mode = "reverse-subtract"
+	case driver.CompositeModeDarken:
+		mode = "darken"
+	case driver.CompositeModeLighten:
+		mode = "lighten"
 	default:
 		panic(fmt.Sprintf("graphicscommand: invalid composite mode: %d", c.mode))
 	}
@@ -361,7 +542,25 @@ func (c *drawTrianglesCommand) String() string {
 		src += " (screen)"
 	}
 
-	return fmt.Sprintf("draw-triangles: dst: %s <- src: %s, colorm: %v, mode %s, filter: %s, address: %s", dst, src, c.color, mode, filter, address)
+	str := fmt.Sprintf("draw-triangles: dst: %s <- src: %s, colorm: %v, mode %s, filter: %s, address: %s", dst, src, c.color, mode, filter, address)
+	if !c.clip.Empty() {
+		str += fmt.Sprintf(", clip: %v", c.clip)
+	}
+	switch c.stencil {
+	case driver.StencilModeWrite:
+		str += ", stencil: write"
+	case driver.StencilModeTest:
+		str += ", stencil: test"
+	case driver.StencilModeTestInverted:
+		str += ", stencil: test-inverted"
+	}
+	switch c.depth.Mode {
+	case driver.DepthModeClear:
+		str += ", depth: clear"
+	case driver.DepthModeTest:
+		str += fmt.Sprintf(", depth: test (z: %f)", c.depth.Z)
+	}
+	return str
 }
 
 // Exec executes the drawTrianglesCommand.
@@ -373,9 +572,10 @@ func (c *drawTrianglesCommand) Exec(indexOffset int) error {
 
 	c.dst.image.SetAsDestination()
 	c.src.image.SetAsSource()
-	if err := theGraphicsDriver.Draw(c.nindices, indexOffset, c.mode, c.color, c.filter, c.address); err != nil {
+	if err := theGraphicsDriver.Draw(c.nindices, indexOffset, c.mode, c.color, c.filter, c.address, c.clip, c.stencil, c.depth); err != nil {
 		return err
 	}
+	countDrawCall()
 	return nil
 }
 
@@ -397,7 +597,7 @@ func (c *drawTrianglesCommand) AddNumIndices(n int) {
 
 // CanMergeWithDrawTrianglesCommand returns a boolean value indicating whether the other drawTrianglesCommand can be merged
 // with the drawTrianglesCommand c.
-func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) bool {
+func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool {
 	if c.dst != dst {
 		return false
 	}
@@ -416,6 +616,20 @@ func (c *drawTrianglesCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image,
 	if c.address != address {
 		return false
 	}
+	if c.clip != clip {
+		return false
+	}
+	if c.stencil != stencil {
+		return false
+	}
+	// Every command writing the stencil buffer clears it first.
+	if stencil == driver.StencilModeWrite {
+		return false
+	}
+	// Commands clearing the depth buffer can be merged, as the depth test is not used after clearing.
+	if c.depth != depth {
+		return false
+	}
 	return true
 }
 
@@ -449,7 +663,7 @@ func (c *replacePixelsCommand) AddNumVertices(n int) {
 func (c *replacePixelsCommand) AddNumIndices(n int) {
 }
 
-func (c *replacePixelsCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) bool {
+func (c *replacePixelsCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool {
 	return false
 }
 
@@ -486,7 +700,7 @@ func (c *pixelsCommand) AddNumVertices(n int) {
 func (c *pixelsCommand) AddNumIndices(n int) {
 }
 
-func (c *pixelsCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) bool {
+func (c *pixelsCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool {
 	return false
 }
 
@@ -500,11 +714,128 @@ func (c *disposeCommand) String() string {
 }
 
 // Exec executes the disposeCommand.
+//
+// The actual disposal is deferred until the GPU finishes the frames that might refer to the image. See EndFrame.
 func (c *disposeCommand) Exec(indexOffset int) error {
-	c.target.image.Dispose()
+	theDeferredDisposals.add(c.target.image)
+	return nil
+}
+
+// framesInFlight is the number of the frames the GPU might still be processing after the CPU finishes them.
+// framesInFlight is used only when the graphics driver doesn't support fences.
+const framesInFlight = 3
+
+// deferredDisposal is the images whose disposal was requested in one frame.
+type deferredDisposal struct {
+	images []driver.Image
+	frame  int
+
+	// fence is signaled when the GPU finishes the frame. fence is nil if the graphics driver doesn't support fences.
+	fence driver.Fence
+}
+
+func (d *deferredDisposal) isFinished(currentFrame int) bool {
+	if d.fence != nil {
+		return d.fence.IsSignaled()
+	}
+	return currentFrame-d.frame >= framesInFlight
+}
+
+func (d *deferredDisposal) dispose() {
+	for _, img := range d.images {
+		img.Dispose()
+	}
+	if d.fence != nil {
+		d.fence.Release()
+	}
+}
+
+type deferredDisposals struct {
+	// current is the images whose disposal is requested in the current frame.
+	current []driver.Image
+
+	disposals []deferredDisposal
+	frame     int
+}
+
+var theDeferredDisposals = &deferredDisposals{}
+
+func (d *deferredDisposals) add(image driver.Image) {
+	d.current = append(d.current, image)
+}
+
+func newFence() driver.Fence {
+	if g, ok := theGraphicsDriver.(interface{ NewFence() driver.Fence }); ok {
+		return g.NewFence()
+	}
 	return nil
 }
 
+func (d *deferredDisposals) endFrame() {
+	if len(d.current) > 0 {
+		d.disposals = append(d.disposals, deferredDisposal{
+			images: d.current,
+			frame:  d.frame,
+			fence:  newFence(),
+		})
+		d.current = nil
+	}
+	d.frame++
+
+	n := 0
+	for _, dd := range d.disposals {
+		if !dd.isFinished(d.frame) {
+			d.disposals[n] = dd
+			n++
+			continue
+		}
+		dd.dispose()
+	}
+	for i := n; i < len(d.disposals); i++ {
+		d.disposals[i] = deferredDisposal{}
+	}
+	d.disposals = d.disposals[:n]
+}
+
+// disposeAll disposes all the deferred images immediately.
+//
+// If contextLost is true, the fences are dropped without being released since they belong to the lost context.
+func (d *deferredDisposals) disposeAll(contextLost bool) {
+	for _, img := range d.current {
+		img.Dispose()
+	}
+	d.current = nil
+	for i := range d.disposals {
+		if contextLost {
+			d.disposals[i].fence = nil
+		}
+		d.disposals[i].dispose()
+		d.disposals[i] = deferredDisposal{}
+	}
+	d.disposals = d.disposals[:0]
+}
+
+// EndFrame marks the end of a frame, and disposes the images whose disposal was requested in the frames that the GPU
+// has finished.
+//
+// Disposing a texture that an in-flight frame still refers to can cause glitches on some drivers. Then, Dispose
+// is deferred until the frames requesting the disposal are finished, so that Dispose is safe to call at any time.
+// The finish of the frames is detected by fences, e.g., sync objects on OpenGL and the command buffers on Metal.
+// If the graphics driver doesn't support fences, the images are disposed after a fixed number of the frames.
+//
+// EndFrame must be called after the commands are flushed at the end of every frame.
+func EndFrame() {
+	theDeferredDisposals.endFrame()
+}
+
+// DisposeDeferredImages disposes all the images whose disposal is deferred immediately.
+//
+// DisposeDeferredImages must be called only after the context is lost, when the GPU no longer refers to the images.
+// The pending fences are dropped since they belong to the lost context.
+func DisposeDeferredImages() {
+	theDeferredDisposals.disposeAll(true)
+}
+
 func (c *disposeCommand) NumVertices() int {
 	return 0
 }
@@ -519,24 +850,42 @@ func (c *disposeCommand) AddNumVertices(n int) {
 func (c *disposeCommand) AddNumIndices(n int) {
 }
 
-func (c *disposeCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) bool {
+func (c *disposeCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool {
 	return false
 }
 
 // newImageCommand represents a command to create an empty image with given width and height.
 type newImageCommand struct {
-	result *Image
-	width  int
-	height int
+	result  *Image
+	width   int
+	height  int
+	samples int
+	float   bool
+	alpha   bool
 }
 
 func (c *newImageCommand) String() string {
+	if c.samples > 0 {
+		return fmt.Sprintf("new-image: result: %d, width: %d, height: %d, samples: %d", c.result.id, c.width, c.height, c.samples)
+	}
+	if c.float {
+		return fmt.Sprintf("new-image: result: %d, width: %d, height: %d, float: true", c.result.id, c.width, c.height)
+	}
+	if c.alpha {
+		return fmt.Sprintf("new-image: result: %d, width: %d, height: %d, alpha: true", c.result.id, c.width, c.height)
+	}
 	return fmt.Sprintf("new-image: result: %d, width: %d, height: %d", c.result.id, c.width, c.height)
 }
 
 // Exec executes a newImageCommand.
 func (c *newImageCommand) Exec(indexOffset int) error {
-	i, err := theGraphicsDriver.NewImage(c.width, c.height)
+	i, err := c.newImage()
+	if _, ok := err.(*driver.OutOfMemoryError); ok {
+		// Release the GPU memory that is no longer used, and try again.
+		// If this still fails, the error is returned to the game.
+		evictImages()
+		i, err = c.newImage()
+	}
 	if err != nil {
 		return err
 	}
@@ -544,6 +893,45 @@ func (c *newImageCommand) Exec(indexOffset int) error {
 	return nil
 }
 
+func (c *newImageCommand) newImage() (driver.Image, error) {
+	if c.samples > 0 {
+		// Multisampling is optional for the graphics drivers. If not available, create a regular image.
+		if g, ok := theGraphicsDriver.(interface {
+			NewMultisampledImage(width, height, samples int) (driver.Image, error)
+		}); ok {
+			return g.NewMultisampledImage(c.width, c.height, c.samples)
+		}
+	}
+	if c.float {
+		// Floating-point images are optional for the graphics drivers. If not available, create a regular image.
+		if g, ok := theGraphicsDriver.(interface {
+			NewFloatImage(width, height int) (driver.Image, error)
+		}); ok {
+			return g.NewFloatImage(c.width, c.height)
+		}
+	}
+	if c.alpha {
+		// Alpha-only images are optional for the graphics drivers. If not available, create a regular image.
+		if g, ok := theGraphicsDriver.(interface {
+			NewAlphaImage(width, height int) (driver.Image, error)
+		}); ok {
+			return g.NewAlphaImage(c.width, c.height)
+		}
+	}
+	return theGraphicsDriver.NewImage(c.width, c.height)
+}
+
+// evictImages releases the GPU memory of the images whose disposal is deferred.
+//
+// evictImages waits for the GPU to finish the in-flight frames, so this is much more expensive than a regular
+// disposal. This should be called only when the GPU runs out of memory.
+func evictImages() {
+	if g, ok := theGraphicsDriver.(interface{ Finish() }); ok {
+		g.Finish()
+	}
+	theDeferredDisposals.disposeAll(false)
+}
+
 func (c *newImageCommand) NumVertices() int {
 	return 0
 }
@@ -558,7 +946,7 @@ func (c *newImageCommand) AddNumVertices(n int) {
 func (c *newImageCommand) AddNumIndices(n int) {
 }
 
-func (c *newImageCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) bool {
+func (c *newImageCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool {
 	return false
 }
 
@@ -594,11 +982,14 @@ func (c *newScreenFramebufferImageCommand) AddNumVertices(n int) {
 func (c *newScreenFramebufferImageCommand) AddNumIndices(n int) {
 }
 
-func (c *newScreenFramebufferImageCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) bool {
+func (c *newScreenFramebufferImageCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool {
 	return false
 }
 
 // ResetGraphicsDriverState resets or initializes the current graphics driver state.
 func ResetGraphicsDriverState() error {
-	return theGraphicsDriver.Reset()
+	if err := theGraphicsDriver.Reset(); err != nil {
+		return err
+	}
+	return resetShaders()
 }
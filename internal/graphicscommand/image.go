@@ -76,6 +76,64 @@ func NewImage(width, height int) *Image {
 	return i
 }
 
+// NewMultisampledImage returns a new image that is rendered with multisample anti-aliasing.
+// samples is the number of the samples per pixel.
+//
+// If the graphics driver doesn't support multisampling, the returned image works as a regular image.
+func NewMultisampledImage(width, height, samples int) *Image {
+	i := &Image{
+		width:  width,
+		height: height,
+		id:     genNextID(),
+	}
+	c := &newImageCommand{
+		result:  i,
+		width:   width,
+		height:  height,
+		samples: samples,
+	}
+	theCommandQueue.Enqueue(c)
+	return i
+}
+
+// NewFloatImage returns a new image whose pixels have 16-bit floating-point values.
+//
+// If the graphics driver doesn't support floating-point images, the returned image works as a regular image.
+func NewFloatImage(width, height int) *Image {
+	i := &Image{
+		width:  width,
+		height: height,
+		id:     genNextID(),
+	}
+	c := &newImageCommand{
+		result: i,
+		width:  width,
+		height: height,
+		float:  true,
+	}
+	theCommandQueue.Enqueue(c)
+	return i
+}
+
+// NewAlphaImage returns a new image whose pixels have only 8-bit alpha values.
+//
+// If the graphics driver doesn't support alpha-only images, the returned image works as a regular image.
+func NewAlphaImage(width, height int) *Image {
+	i := &Image{
+		width:  width,
+		height: height,
+		id:     genNextID(),
+	}
+	c := &newImageCommand{
+		result: i,
+		width:  width,
+		height: height,
+		alpha:  true,
+	}
+	theCommandQueue.Enqueue(c)
+	return i
+}
+
 func NewScreenFramebufferImage(width, height int) *Image {
 	i := &Image{
 		width:  width,
@@ -111,6 +169,11 @@ func (i *Image) Dispose() {
 	theCommandQueue.Enqueue(c)
 }
 
+// ID returns the unique ID of the image. This is for debugging usage.
+func (i *Image) ID() int {
+	return i.id
+}
+
 func (i *Image) InternalSize() (int, int) {
 	if i.internalWidth == 0 {
 		i.internalWidth = graphics.InternalImageSize(i.width)
@@ -137,7 +200,9 @@ func (i *Image) InternalSize() (int, int) {
 //   9:  Color G
 //   10: Color B
 //   11: Color Y
-func (i *Image) DrawTriangles(src *Image, vertices []float32, indices []uint16, clr *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) {
+//
+// clip is the region of the image to render in pixels. If clip is empty, the whole image is rendered.
+func (i *Image) DrawTriangles(src *Image, vertices []float32, indices []uint16, clr *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) {
 	if src.screen {
 		panic("graphicscommand: the screen image cannot be the rendering source")
 	}
@@ -151,7 +216,33 @@ func (i *Image) DrawTriangles(src *Image, vertices []float32, indices []uint16,
 	src.resolveBufferedReplacePixels()
 	i.resolveBufferedReplacePixels()
 
-	theCommandQueue.EnqueueDrawTrianglesCommand(i, src, vertices, indices, clr, mode, filter, address)
+	theCommandQueue.EnqueueDrawTrianglesCommand(i, src, vertices, indices, clr, mode, filter, address, clip, stencil, depth)
+
+	if i.lastCommand == lastCommandNone && !i.screen {
+		i.lastCommand = lastCommandClear
+	} else {
+		i.lastCommand = lastCommandDrawTriangles
+	}
+}
+
+// DrawShader draws triangles with the given image and the shader.
+//
+// The vertex floats are the same as DrawTriangles. uniforms are the values of the shader's uniform variables.
+func (i *Image) DrawShader(src *Image, vertices []float32, indices []uint16, shader *Shader, uniforms [][]float32, mode driver.CompositeMode) {
+	if src.screen {
+		panic("graphicscommand: the screen image cannot be the rendering source")
+	}
+
+	if i.lastCommand == lastCommandNone {
+		if !i.screen && mode != driver.CompositeModeClear {
+			panic("graphicscommand: the image must be cleared first")
+		}
+	}
+
+	src.resolveBufferedReplacePixels()
+	i.resolveBufferedReplacePixels()
+
+	theCommandQueue.EnqueueDrawShaderCommand(i, src, vertices, indices, shader, uniforms, mode)
 
 	if i.lastCommand == lastCommandNone && !i.screen {
 		i.lastCommand = lastCommandClear
@@ -192,6 +283,14 @@ func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 	i.lastCommand = lastCommandReplacePixels
 }
 
+// IsRenderedByDrawTriangles reports whether the last command for the image is DrawTriangles or DrawShader except
+// for the first clearing.
+//
+// ReplacePixels for a part is forbidden in this case.
+func (i *Image) IsRenderedByDrawTriangles() bool {
+	return i.lastCommand == lastCommandDrawTriangles
+}
+
 func (i *Image) IsInvalidated() bool {
 	if i.screen {
 		// The screen image might not have a texture, and in this case it is impossible to detect whether
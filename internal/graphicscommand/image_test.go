@@ -16,6 +16,7 @@ package graphicscommand_test
 
 import (
 	"errors"
+	"image"
 	"image/color"
 	"os"
 	"testing"
@@ -59,7 +60,7 @@ func TestClear(t *testing.T) {
 
 	vs := quadVertices(w/2, h/2)
 	is := graphics.QuadIndices()
-	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeClear, driver.FilterNearest, driver.AddressClampToZero)
+	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeClear, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 
 	pix, err := dst.Pixels()
 	if err != nil {
@@ -89,7 +90,7 @@ func TestReplacePixelsPartAfterDrawTriangles(t *testing.T) {
 	dst := NewImage(w, h)
 	vs := quadVertices(w/2, h/2)
 	is := graphics.QuadIndices()
-	dst.DrawTriangles(clr, vs, is, nil, driver.CompositeModeClear, driver.FilterNearest, driver.AddressClampToZero)
-	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeSourceOver, driver.FilterNearest, driver.AddressClampToZero)
+	dst.DrawTriangles(clr, vs, is, nil, driver.CompositeModeClear, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
+	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeSourceOver, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 	dst.ReplacePixels(make([]byte, 4), 0, 0, 1, 1)
 }
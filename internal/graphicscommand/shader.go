@@ -0,0 +1,216 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"fmt"
+	"image"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/internal/affine"
+	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/shader"
+)
+
+// Shader represents a custom shader program.
+type Shader struct {
+	shader  driver.Shader
+	program *shader.Program
+	id      int
+}
+
+// theShaders is the set of the shaders that are not disposed.
+var theShaders = map[*Shader]struct{}{}
+
+// ShadersAvailable reports whether the graphics driver can compile and use custom shaders.
+//
+// ShadersAvailable is concurrent-safe.
+func ShadersAvailable() bool {
+	g, ok := theGraphicsDriver.(interface{ HasShaders() bool })
+	return ok && g.HasShaders()
+}
+
+// NewShader returns a new shader.
+//
+// Note that the shader is not compiled for the graphics driver yet.
+//
+// NewShader panics when the graphics driver doesn't support shaders. Check ShadersAvailable in advance.
+func NewShader(program *shader.Program) *Shader {
+	if !ShadersAvailable() {
+		panic("graphicscommand: custom shaders are not available with the graphics driver")
+	}
+	s := &Shader{
+		program: program,
+		id:      genNextID(),
+	}
+	theShaders[s] = struct{}{}
+	c := &newShaderCommand{
+		result: s,
+	}
+	theCommandQueue.Enqueue(c)
+	return s
+}
+
+func (s *Shader) Dispose() {
+	delete(theShaders, s)
+	c := &disposeShaderCommand{
+		target: s,
+	}
+	theCommandQueue.Enqueue(c)
+}
+
+// ShaderIDs returns the IDs of the shaders that are not disposed. This is for debugging usage.
+func ShaderIDs() []int {
+	ids := make([]int, 0, len(theShaders))
+	for s := range theShaders {
+		ids = append(ids, s.id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// resetShaders compiles all the shaders again after the graphics driver state is reset.
+func resetShaders() error {
+	for s := range theShaders {
+		d, err := theGraphicsDriver.NewShader(s.program)
+		if err != nil {
+			return err
+		}
+		s.shader = d
+	}
+	return nil
+}
+
+// newShaderCommand represents a command to compile a shader.
+type newShaderCommand struct {
+	result *Shader
+}
+
+func (c *newShaderCommand) String() string {
+	return fmt.Sprintf("new-shader: result: %d", c.result.id)
+}
+
+// Exec executes a newShaderCommand.
+func (c *newShaderCommand) Exec(indexOffset int) error {
+	s, err := theGraphicsDriver.NewShader(c.result.program)
+	if err != nil {
+		return err
+	}
+	c.result.shader = s
+	return nil
+}
+
+func (c *newShaderCommand) NumVertices() int {
+	return 0
+}
+
+func (c *newShaderCommand) NumIndices() int {
+	return 0
+}
+
+func (c *newShaderCommand) AddNumVertices(n int) {
+}
+
+func (c *newShaderCommand) AddNumIndices(n int) {
+}
+
+func (c *newShaderCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool {
+	return false
+}
+
+// disposeShaderCommand represents a command to dispose a shader.
+type disposeShaderCommand struct {
+	target *Shader
+}
+
+func (c *disposeShaderCommand) String() string {
+	return fmt.Sprintf("dispose-shader: target: %d", c.target.id)
+}
+
+// Exec executes a disposeShaderCommand.
+func (c *disposeShaderCommand) Exec(indexOffset int) error {
+	if c.target.shader != nil {
+		c.target.shader.Dispose()
+	}
+	return nil
+}
+
+func (c *disposeShaderCommand) NumVertices() int {
+	return 0
+}
+
+func (c *disposeShaderCommand) NumIndices() int {
+	return 0
+}
+
+func (c *disposeShaderCommand) AddNumVertices(n int) {
+}
+
+func (c *disposeShaderCommand) AddNumIndices(n int) {
+}
+
+func (c *disposeShaderCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool {
+	return false
+}
+
+// drawShaderCommand represents a command to draw triangles with a shader.
+type drawShaderCommand struct {
+	dst       *Image
+	src       *Image
+	shader    *Shader
+	uniforms  [][]float32
+	nvertices int
+	nindices  int
+	mode      driver.CompositeMode
+}
+
+func (c *drawShaderCommand) String() string {
+	return fmt.Sprintf("draw-shader: dst: %d <- src: %d, shader: %d, uniforms: %v, mode %d", c.dst.id, c.src.id, c.shader.id, c.uniforms, c.mode)
+}
+
+// Exec executes the drawShaderCommand.
+func (c *drawShaderCommand) Exec(indexOffset int) error {
+	if c.nindices == 0 {
+		return nil
+	}
+
+	c.dst.image.SetAsDestination()
+	c.src.image.SetAsSource()
+	if err := theGraphicsDriver.DrawShader(c.nindices, indexOffset, c.shader.shader, c.uniforms, c.mode); err != nil {
+		return err
+	}
+	countDrawCall()
+	return nil
+}
+
+func (c *drawShaderCommand) NumVertices() int {
+	return c.nvertices
+}
+
+func (c *drawShaderCommand) NumIndices() int {
+	return c.nindices
+}
+
+func (c *drawShaderCommand) AddNumVertices(n int) {
+	c.nvertices += n
+}
+
+func (c *drawShaderCommand) AddNumIndices(n int) {
+	c.nindices += n
+}
+
+func (c *drawShaderCommand) CanMergeWithDrawTrianglesCommand(dst, src *Image, color *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) bool {
+	return false
+}
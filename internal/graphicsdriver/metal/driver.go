@@ -18,6 +18,7 @@ package metal
 
 import (
 	"fmt"
+	"image"
 	"strings"
 	"unsafe"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/hajimehoshi/ebiten/internal/graphics"
 	"github.com/hajimehoshi/ebiten/internal/graphicsdriver/metal/ca"
 	"github.com/hajimehoshi/ebiten/internal/graphicsdriver/metal/mtl"
+	"github.com/hajimehoshi/ebiten/internal/shader"
 	"github.com/hajimehoshi/ebiten/internal/thread"
 )
 
@@ -291,6 +293,10 @@ type Driver struct {
 	cq        mtl.CommandQueue
 	cb        mtl.CommandBuffer
 
+	// lastCB is the last committed command buffer. lastCB is retained so that a fence can refer to it after the
+	// autorelease pool is drained.
+	lastCB mtl.CommandBuffer
+
 	screenDrawable ca.MetalDrawable
 
 	vb  mtl.Buffer
@@ -350,7 +356,7 @@ func (d *Driver) SetUIView(uiview uintptr) {
 	d.view.setUIView(uiview)
 }
 
-func (d *Driver) SetVertices(vertices []float32, indices []uint16) {
+func (d *Driver) SetVertices(vertices []float32, indices []uint32) {
 	d.t.Call(func() error {
 		if d.vb != (mtl.Buffer{}) {
 			d.vb.Release()
@@ -378,12 +384,59 @@ func (d *Driver) flush(wait bool, present bool) {
 			d.cb.WaitUntilCompleted()
 		}
 
+		if d.lastCB != (mtl.CommandBuffer{}) {
+			d.lastCB.Release()
+		}
+		d.cb.Retain()
+		d.lastCB = d.cb
 		d.cb = mtl.CommandBuffer{}
 
 		return nil
 	})
 }
 
+// fence is a fence with a committed command buffer.
+type fence struct {
+	cb mtl.CommandBuffer
+	t  *thread.Thread
+}
+
+func (f *fence) IsSignaled() bool {
+	var s mtl.CommandBufferStatus
+	f.t.Call(func() error {
+		s = f.cb.Status()
+		return nil
+	})
+	return s == mtl.CommandBufferStatusCompleted || s == mtl.CommandBufferStatusError
+}
+
+func (f *fence) Release() {
+	f.t.Call(func() error {
+		f.cb.Release()
+		return nil
+	})
+}
+
+// NewFence commits the pending commands and returns a fence that is signaled when the command buffer completes.
+// NewFence returns nil if no command buffer has been committed.
+func (d *Driver) NewFence() driver.Fence {
+	d.flush(false, false)
+
+	var f driver.Fence
+	d.t.Call(func() error {
+		if d.lastCB == (mtl.CommandBuffer{}) {
+			return nil
+		}
+		d.lastCB.Retain()
+		f = &fence{
+			cb: d.lastCB,
+			t:  d.t,
+		}
+		return nil
+	})
+	return f
+}
+
 func (d *Driver) checkSize(width, height int) {
 	if width < 1 {
 		panic(fmt.Sprintf("metal: width (%d) must be equal or more than %d", width, 1))
@@ -414,6 +467,9 @@ func (d *Driver) NewImage(width, height int) (driver.Image, error) {
 		t = d.view.getMTLDevice().MakeTexture(td)
 		return nil
 	})
+	if t == (mtl.Texture{}) {
+		return nil, &driver.OutOfMemoryError{Width: width, Height: height}
+	}
 	return &Image{
 		driver:  d,
 		width:   width,
@@ -518,6 +574,23 @@ func (d *Driver) Reset() error {
 			}
 		}
 
+		convEquation := func(e driver.BlendEquation) mtl.BlendOperation {
+			switch e {
+			case driver.BlendEquationAdd:
+				return mtl.BlendOperationAdd
+			case driver.BlendEquationSubtract:
+				return mtl.BlendOperationSubtract
+			case driver.BlendEquationReverseSubtract:
+				return mtl.BlendOperationReverseSubtract
+			case driver.BlendEquationMin:
+				return mtl.BlendOperationMin
+			case driver.BlendEquationMax:
+				return mtl.BlendOperationMax
+			default:
+				panic(fmt.Sprintf("metal: invalid blend equation: %d", e))
+			}
+		}
+
 		for _, screen := range []bool{false, true} {
 			for _, cm := range []bool{false, true} {
 				for _, a := range []driver.Address{
@@ -554,6 +627,9 @@ func (d *Driver) Reset() error {
 							rpld.ColorAttachments[0].DestinationRGBBlendFactor = conv(dst)
 							rpld.ColorAttachments[0].SourceAlphaBlendFactor = conv(src)
 							rpld.ColorAttachments[0].SourceRGBBlendFactor = conv(src)
+							rgbEq, alphaEq := c.Equations()
+							rpld.ColorAttachments[0].AlphaBlendOperation = convEquation(alphaEq)
+							rpld.ColorAttachments[0].RGBBlendOperation = convEquation(rgbEq)
 							rps, err := d.view.getMTLDevice().MakeRenderPipelineState(rpld)
 							if err != nil {
 								return err
@@ -580,7 +656,14 @@ func (d *Driver) Reset() error {
 	return nil
 }
 
-func (d *Driver) Draw(indexLen int, indexOffset int, mode driver.CompositeMode, colorM *affine.ColorM, filter driver.Filter, address driver.Address) error {
+func (d *Driver) Draw(indexLen int, indexOffset int, mode driver.CompositeMode, colorM *affine.ColorM, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) error {
+	// Stencil buffers are not supported yet. Draw without masks, like multisampling falls back to a regular image.
+	// Marking a region doesn't update the colors, then the draw call can be skipped.
+	if stencil == driver.StencilModeWrite {
+		return nil
+	}
+	// Depth buffers are not supported yet. The triangles are rendered in the order of the draw calls without the
+	// depth test.
 	d.drawCalled = true
 
 	if err := d.t.Call(func() error {
@@ -636,6 +719,14 @@ func (d *Driver) Draw(indexLen int, indexOffset int, mode driver.CompositeMode,
 			ZNear:   -1,
 			ZFar:    1,
 		})
+		if !clip.Empty() {
+			rce.SetScissorRect(mtl.ScissorRect{
+				X:      clip.Min.X,
+				Y:      clip.Min.Y,
+				Width:  clip.Dx(),
+				Height: clip.Dy(),
+			})
+		}
 		rce.SetVertexBuffer(d.vb, 0, 0)
 
 		viewportSize := [...]float32{float32(w), float32(h)}
@@ -659,7 +750,7 @@ func (d *Driver) Draw(indexLen int, indexOffset int, mode driver.CompositeMode,
 		} else {
 			rce.SetFragmentTexture(mtl.Texture{}, 0)
 		}
-		rce.DrawIndexedPrimitives(mtl.PrimitiveTypeTriangle, indexLen, mtl.IndexTypeUInt16, d.ib, indexOffset*2)
+		rce.DrawIndexedPrimitives(mtl.PrimitiveTypeTriangle, indexLen, mtl.IndexTypeUInt32, d.ib, indexOffset*4)
 		rce.EndEncoding()
 
 		return nil
@@ -677,6 +768,17 @@ func (d *Driver) ResetSource() {
 	})
 }
 
+// NewShader is never called since Driver doesn't have HasShaders, and graphicscommand rejects custom shaders
+// in advance.
+func (d *Driver) NewShader(program *shader.Program) (driver.Shader, error) {
+	// TODO: Compile the program into Metal Shading Language and add HasShaders.
+	return nil, fmt.Errorf("metal: custom shaders are not supported yet")
+}
+
+func (d *Driver) DrawShader(indexLen int, indexOffset int, shader driver.Shader, uniforms [][]float32, mode driver.CompositeMode) error {
+	return fmt.Errorf("metal: custom shaders are not supported yet")
+}
+
 func (d *Driver) SetVsyncEnabled(enabled bool) {
 	d.view.setDisplaySyncEnabled(enabled)
 }
@@ -697,6 +799,10 @@ func (d *Driver) HasHighPrecisionFloat() bool {
 	return true
 }
 
+func (d *Driver) Has32BitIndices() bool {
+	return true
+}
+
 func (d *Driver) MaxImageSize() int {
 	m := 0
 	d.t.Call(func() error {
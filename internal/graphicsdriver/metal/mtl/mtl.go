@@ -284,6 +284,19 @@ const (
 	BlendFactorOneMinusSource1Alpha     BlendFactor = 18
 )
 
+// BlendOperation defines how the source and the destination are combined with the blend factors.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlblendoperation.
+type BlendOperation uint8
+
+const (
+	BlendOperationAdd             BlendOperation = 0
+	BlendOperationSubtract        BlendOperation = 1
+	BlendOperationReverseSubtract BlendOperation = 2
+	BlendOperationMin             BlendOperation = 3
+	BlendOperationMax             BlendOperation = 4
+)
+
 // Resource represents a memory allocation for storing specialized data
 // that is accessible to the GPU.
 //
@@ -321,6 +334,9 @@ type RenderPipelineColorAttachmentDescriptor struct {
 	DestinationRGBBlendFactor   BlendFactor
 	SourceAlphaBlendFactor      BlendFactor
 	SourceRGBBlendFactor        BlendFactor
+
+	AlphaBlendOperation BlendOperation
+	RGBBlendOperation   BlendOperation
 }
 
 // RenderPassDescriptor describes a group of render targets that serve as
@@ -451,6 +467,8 @@ func (d Device) MakeRenderPipelineState(rpd RenderPipelineDescriptor) (RenderPip
 		ColorAttachment0DestinationRGBBlendFactor:   C.uint8_t(c.DestinationRGBBlendFactor),
 		ColorAttachment0SourceAlphaBlendFactor:      C.uint8_t(c.SourceAlphaBlendFactor),
 		ColorAttachment0SourceRGBBlendFactor:        C.uint8_t(c.SourceRGBBlendFactor),
+		ColorAttachment0AlphaBlendOperation:         C.uint8_t(c.AlphaBlendOperation),
+		ColorAttachment0RGBBlendOperation:           C.uint8_t(c.RGBBlendOperation),
 	}
 	rps := C.Device_MakeRenderPipelineState(d.device, descriptor)
 	if rps.RenderPipelineState == nil {
@@ -478,6 +496,8 @@ func (d Device) MakeBufferWithLength(length uintptr, opt ResourceOptions) Buffer
 // MakeTexture creates a texture object with privately owned storage
 // that contains texture state.
 //
+// MakeTexture returns a zero Texture when the texture cannot be created, e.g., due to the lack of memory.
+//
 // Reference: https://developer.apple.com/documentation/metal/mtldevice/1433425-maketexture.
 func (d Device) MakeTexture(td TextureDescriptor) Texture {
 	descriptor := C.struct_TextureDescriptor{
@@ -487,8 +507,12 @@ func (d Device) MakeTexture(td TextureDescriptor) Texture {
 		StorageMode: C.uint8_t(td.StorageMode),
 		Usage:       C.uint8_t(td.Usage),
 	}
+	t := C.Device_MakeTexture(d.device, descriptor)
+	if t == nil {
+		return Texture{}
+	}
 	return Texture{
-		texture: C.Device_MakeTexture(d.device, descriptor),
+		texture: t,
 		Width:   td.Width,  // TODO: Fetch dimensions of actually created texture.
 		Height:  td.Height, // TODO: Fetch dimensions of actually created texture.
 	}
@@ -537,6 +561,37 @@ type CommandBuffer struct {
 	commandBuffer unsafe.Pointer
 }
 
+// CommandBufferStatus is the discrete states for a command buffer that indicate where it is in its lifecycle.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlcommandbufferstatus.
+type CommandBufferStatus uint8
+
+const (
+	CommandBufferStatusNotEnqueued CommandBufferStatus = 0 // The command buffer is not enqueued yet.
+	CommandBufferStatusEnqueued    CommandBufferStatus = 1 // The command buffer is enqueued.
+	CommandBufferStatusCommitted   CommandBufferStatus = 2 // The command buffer is committed for execution.
+	CommandBufferStatusScheduled   CommandBufferStatus = 3 // The command buffer is scheduled.
+	CommandBufferStatusCompleted   CommandBufferStatus = 4 // The command buffer completed execution successfully.
+	CommandBufferStatusError       CommandBufferStatus = 5 // Execution of the command buffer was aborted due to an error.
+)
+
+// Retain increments the reference count of the command buffer.
+func (cb CommandBuffer) Retain() {
+	C.CommandBuffer_Retain(cb.commandBuffer)
+}
+
+// Release decrements the reference count of the command buffer.
+func (cb CommandBuffer) Release() {
+	C.CommandBuffer_Release(cb.commandBuffer)
+}
+
+// Status reports the current stage in the lifetime of the command buffer.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlcommandbuffer/1443048-status.
+func (cb CommandBuffer) Status() CommandBufferStatus {
+	return CommandBufferStatus(C.CommandBuffer_Status(cb.commandBuffer))
+}
+
 // PresentDrawable registers a drawable presentation to occur as soon as possible.
 //
 // Reference: https://developer.apple.com/documentation/metal/mtlcommandbuffer/1443029-presentdrawable.
@@ -623,6 +678,13 @@ func (rce RenderCommandEncoder) SetViewport(viewport Viewport) {
 	C.RenderCommandEncoder_SetViewport(rce.commandEncoder, viewport.c())
 }
 
+// SetScissorRect sets a scissor rectangle for fragment scissor tests.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlrendercommandencoder/1515583-setscissorrect.
+func (rce RenderCommandEncoder) SetScissorRect(rect ScissorRect) {
+	C.RenderCommandEncoder_SetScissorRect(rce.commandEncoder, rect.c())
+}
+
 // SetVertexBuffer sets a buffer for the vertex shader function at an index
 // in the buffer argument table with an offset that specifies the start of the data.
 //
@@ -846,6 +908,25 @@ type Viewport struct {
 	ZFar    float64
 }
 
+// ScissorRect is a rectangle for the scissor fragment test.
+//
+// Reference: https://developer.apple.com/documentation/metal/mtlscissorrect.
+type ScissorRect struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+func (s *ScissorRect) c() C.struct_ScissorRect {
+	return C.struct_ScissorRect{
+		X:      C.uint_t(s.X),
+		Y:      C.uint_t(s.Y),
+		Width:  C.uint_t(s.Width),
+		Height: C.uint_t(s.Height),
+	}
+}
+
 func (v *Viewport) c() C.struct_Viewport {
 	return C.struct_Viewport{
 		OriginX: C.double(v.OriginX),
@@ -47,6 +47,8 @@ struct RenderPipelineDescriptor {
   uint8_t ColorAttachment0DestinationRGBBlendFactor;
   uint8_t ColorAttachment0SourceAlphaBlendFactor;
   uint8_t ColorAttachment0SourceRGBBlendFactor;
+  uint8_t ColorAttachment0AlphaBlendOperation;
+  uint8_t ColorAttachment0RGBBlendOperation;
 };
 
 struct RenderPipelineState {
@@ -102,6 +104,13 @@ struct Viewport {
   double ZFar;
 };
 
+struct ScissorRect {
+  uint_t X;
+  uint_t Y;
+  uint_t Width;
+  uint_t Height;
+};
+
 struct Device CreateSystemDefaultDevice();
 struct Devices CopyAllDevices();
 
@@ -121,7 +130,9 @@ void *Device_MakeTexture(void *device, struct TextureDescriptor descriptor);
 void CommandQueue_Release(void *commandQueue);
 void *CommandQueue_MakeCommandBuffer(void *commandQueue);
 
+void CommandBuffer_Retain(void *commandBuffer);
 void CommandBuffer_Release(void *commandBuffer);
+uint8_t CommandBuffer_Status(void *commandBuffer);
 void CommandBuffer_PresentDrawable(void *commandBuffer, void *drawable);
 void CommandBuffer_Commit(void *commandBuffer);
 void CommandBuffer_WaitUntilCompleted(void *commandBuffer);
@@ -137,6 +148,8 @@ void RenderCommandEncoder_SetRenderPipelineState(void *renderCommandEncoder,
                                                  void *renderPipelineState);
 void RenderCommandEncoder_SetViewport(void *renderCommandEncoder,
                                       struct Viewport viewport);
+void RenderCommandEncoder_SetScissorRect(void *renderCommandEncoder,
+                                         struct ScissorRect rect);
 void RenderCommandEncoder_SetVertexBuffer(void *renderCommandEncoder,
                                           void *buffer, uint_t offset,
                                           uint_t index);
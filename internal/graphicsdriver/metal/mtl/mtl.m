@@ -84,6 +84,10 @@ Device_MakeRenderPipelineState(void *device,
       descriptor.ColorAttachment0SourceAlphaBlendFactor;
   renderPipelineDescriptor.colorAttachments[0].sourceRGBBlendFactor =
       descriptor.ColorAttachment0SourceRGBBlendFactor;
+  renderPipelineDescriptor.colorAttachments[0].alphaBlendOperation =
+      descriptor.ColorAttachment0AlphaBlendOperation;
+  renderPipelineDescriptor.colorAttachments[0].rgbBlendOperation =
+      descriptor.ColorAttachment0RGBBlendOperation;
   NSError *error;
   id<MTLRenderPipelineState> renderPipelineState = [(id<MTLDevice>)device
       newRenderPipelineStateWithDescriptor:renderPipelineDescriptor
@@ -132,6 +136,18 @@ void *CommandQueue_MakeCommandBuffer(void *commandQueue) {
   return [(id<MTLCommandQueue>)commandQueue commandBuffer];
 }
 
+void CommandBuffer_Retain(void *commandBuffer) {
+  [(id<MTLCommandBuffer>)commandBuffer retain];
+}
+
+void CommandBuffer_Release(void *commandBuffer) {
+  [(id<MTLCommandBuffer>)commandBuffer release];
+}
+
+uint8_t CommandBuffer_Status(void *commandBuffer) {
+  return (uint8_t)((id<MTLCommandBuffer>)commandBuffer).status;
+}
+
 void CommandBuffer_PresentDrawable(void *commandBuffer, void *drawable) {
   [(id<MTLCommandBuffer>)commandBuffer
       presentDrawable:(id<MTLDrawable>)drawable];
@@ -198,6 +214,17 @@ void RenderCommandEncoder_SetViewport(void *renderCommandEncoder,
                   }];
 }
 
+void RenderCommandEncoder_SetScissorRect(void *renderCommandEncoder,
+                                         struct ScissorRect rect) {
+  [(id<MTLRenderCommandEncoder>)renderCommandEncoder
+      setScissorRect:(MTLScissorRect){
+                         rect.X,
+                         rect.Y,
+                         rect.Width,
+                         rect.Height,
+                     }];
+}
+
 void RenderCommandEncoder_SetVertexBuffer(void *renderCommandEncoder,
                                           void *buffer, uint_t offset,
                                           uint_t index) {
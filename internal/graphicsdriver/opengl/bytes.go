@@ -30,6 +30,17 @@ func float32sToBytes(v []float32) []byte {
 	return b
 }
 
+func uint32sToBytes(v []uint32) []byte {
+	u32h := (*reflect.SliceHeader)(unsafe.Pointer(&v))
+
+	var b []byte
+	bh := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	bh.Data = u32h.Data
+	bh.Len = len(v) * 4
+	bh.Cap = len(v) * 4
+	return b
+}
+
 func uint16sToBytes(v []uint16) []byte {
 	u16h := (*reflect.SliceHeader)(unsafe.Pointer(&v))
 
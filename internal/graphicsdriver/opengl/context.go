@@ -16,9 +16,11 @@ package opengl
 
 import (
 	"fmt"
+	"image"
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
 	"github.com/hajimehoshi/ebiten/internal/thread"
 )
 
@@ -41,6 +43,23 @@ func convertOperation(op driver.Operation) operation {
 	}
 }
 
+func convertEquation(eq driver.BlendEquation) equation {
+	switch eq {
+	case driver.BlendEquationAdd:
+		return funcAdd
+	case driver.BlendEquationSubtract:
+		return funcSubtract
+	case driver.BlendEquationReverseSubtract:
+		return funcReverseSubtract
+	case driver.BlendEquationMin:
+		return funcMin
+	case driver.BlendEquationMax:
+		return funcMax
+	default:
+		panic(fmt.Sprintf("opengl: invalid blend equation %d at convertEquation", eq))
+	}
+}
+
 type context struct {
 	locationCache      *locationCache
 	screenFramebuffer  framebufferNative // This might not be the default frame buffer '0' (e.g. iOS).
@@ -49,10 +68,28 @@ type context struct {
 	lastViewportWidth  int
 	lastViewportHeight int
 	lastCompositeMode  driver.CompositeMode
+	lastScissor        *image.Rectangle
+	lastStencilMode    driver.StencilMode
+	lastDepthMode      driver.DepthMode
 	maxTextureSize     int
 	maxTextureSizeOnce sync.Once
 	highp              bool
 	highpOnce          sync.Once
+	indices32          bool
+	indices32Once      sync.Once
+	floatTextures      bool
+	floatTexturesOnce  sync.Once
+	alphaTextures      bool
+	alphaTexturesOnce  sync.Once
+	srgbAvailable      bool
+	srgbAvailableOnce  sync.Once
+	blendMinMax        bool
+	blendMinMaxOnce    sync.Once
+
+	// srgb reports whether textures are created in sRGB and blending is done in linear space.
+	srgb bool
+
+	lastFramebufferSRGB bool
 
 	t *thread.Thread
 
@@ -67,6 +104,43 @@ func (c *context) bindTexture(t textureNative) {
 	c.lastTexture = t
 }
 
+// setScissor sets the scissor rectangle. If clip is empty, the scissor test is disabled.
+func (c *context) setScissor(clip image.Rectangle) {
+	if c.lastScissor != nil && c.lastScissor.Eq(clip) {
+		return
+	}
+	c.setScissorImpl(clip)
+	c.lastScissor = &clip
+}
+
+// setStencilMode sets how the stencil buffer of the current framebuffer is used.
+// StencilModeWrite clears the stencil buffer every time.
+func (c *context) setStencilMode(mode driver.StencilMode) {
+	if c.lastStencilMode == mode && mode != driver.StencilModeWrite {
+		return
+	}
+	if mode == driver.StencilModeWrite {
+		// glClear is affected by the scissor test.
+		c.setScissor(image.Rectangle{})
+	}
+	c.setStencilModeImpl(mode)
+	c.lastStencilMode = mode
+}
+
+// setDepthMode sets how the depth buffer of the current framebuffer is used.
+// DepthModeClear clears the depth buffer every time.
+func (c *context) setDepthMode(mode driver.DepthMode) {
+	if c.lastDepthMode == mode && mode != driver.DepthModeClear {
+		return
+	}
+	if mode == driver.DepthModeClear {
+		// glClear is affected by the scissor test.
+		c.setScissor(image.Rectangle{})
+	}
+	c.setDepthModeImpl(mode)
+	c.lastDepthMode = mode
+}
+
 func (c *context) bindFramebuffer(f framebufferNative) {
 	if c.lastFramebuffer.equal(f) {
 		return
@@ -116,3 +190,76 @@ func (c *context) hasHighPrecisionFloat() bool {
 	})
 	return c.highp
 }
+
+// has32BitIndices reports whether an element array buffer can have 32-bit indices.
+func (c *context) has32BitIndices() bool {
+	c.indices32Once.Do(func() {
+		c.indices32 = c.has32BitIndicesImpl()
+	})
+	return c.indices32
+}
+
+// hasFloatTextures reports whether textures with 16-bit floating-point values can be created and rendered.
+func (c *context) hasFloatTextures() bool {
+	c.floatTexturesOnce.Do(func() {
+		c.floatTextures = c.hasFloatTexturesImpl()
+	})
+	return c.floatTextures
+}
+
+// hasAlphaTextures reports whether textures with only one 8-bit channel can be created and rendered.
+func (c *context) hasAlphaTextures() bool {
+	c.alphaTexturesOnce.Do(func() {
+		c.alphaTextures = c.hasAlphaTexturesImpl()
+	})
+	return c.alphaTextures
+}
+
+// hasSRGB reports whether sRGB textures can be created and rendered with sRGB encoding.
+func (c *context) hasSRGB() bool {
+	c.srgbAvailableOnce.Do(func() {
+		c.srgbAvailable = c.hasSRGBImpl()
+	})
+	return c.srgbAvailable
+}
+
+// hasBlendMinMax reports whether MIN and MAX blend equations are available.
+func (c *context) hasBlendMinMax() bool {
+	c.blendMinMaxOnce.Do(func() {
+		c.blendMinMax = c.hasBlendMinMaxImpl()
+	})
+	return c.blendMinMax
+}
+
+// blendCompositeMode returns the composite mode to use for blending instead of mode.
+func (c *context) blendCompositeMode(mode driver.CompositeMode) driver.CompositeMode {
+	if rgb, _ := mode.Equations(); (rgb == driver.BlendEquationMin || rgb == driver.BlendEquationMax) && !c.hasBlendMinMax() {
+		return driver.CompositeModeSourceOver
+	}
+	return mode
+}
+
+// setFramebufferSRGB sets whether the colors rendered to sRGB framebuffers are encoded and blended in linear space.
+func (c *context) setFramebufferSRGB(enabled bool) {
+	if c.lastFramebufferSRGB == enabled {
+		return
+	}
+	c.setFramebufferSRGBImpl(enabled)
+	c.lastFramebufferSRGB = enabled
+}
+
+// indicesNum returns the maximum number of the indices in an element array buffer.
+func (c *context) indicesNum() int {
+	if c.has32BitIndices() {
+		return graphics.IndicesNum32
+	}
+	return graphics.IndicesNum
+}
+
+// indexSize returns the size of an index in bytes.
+func (c *context) indexSize() int {
+	if c.has32BitIndices() {
+		return 4
+	}
+	return 2
+}
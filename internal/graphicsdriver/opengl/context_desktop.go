@@ -22,6 +22,8 @@ package opengl
 import (
 	"errors"
 	"fmt"
+	"image"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/internal/driver"
 	"github.com/hajimehoshi/ebiten/internal/graphicsdriver/opengl/gl"
@@ -30,6 +32,7 @@ import (
 type (
 	textureNative     uint32
 	framebufferNative uint32
+	renderbuffer      uint32
 	shader            uint32
 	program           uint32
 	buffer            uint32
@@ -92,6 +95,12 @@ const (
 	dstAlpha         = operation(gl.DST_ALPHA)
 	oneMinusSrcAlpha = operation(gl.ONE_MINUS_SRC_ALPHA)
 	oneMinusDstAlpha = operation(gl.ONE_MINUS_DST_ALPHA)
+
+	funcAdd             = equation(gl.FUNC_ADD)
+	funcSubtract        = equation(gl.FUNC_SUBTRACT)
+	funcReverseSubtract = equation(gl.FUNC_REVERSE_SUBTRACT)
+	funcMin             = equation(gl.MIN)
+	funcMax             = equation(gl.MAX)
 )
 
 type contextImpl struct {
@@ -118,8 +127,15 @@ func (c *context) reset() error {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = driver.CompositeModeUnknown
+	c.lastScissor = nil
+	c.lastStencilMode = driver.StencilModeNone
+	c.lastDepthMode = driver.DepthModeNone
+	c.lastFramebufferSRGB = false
 	_ = c.t.Call(func() error {
 		gl.Enable(gl.BLEND)
+		if c.srgb {
+			gl.Disable(gl.FRAMEBUFFER_SRGB)
+		}
 		return nil
 	})
 	c.blendFunc(driver.CompositeModeSourceOver)
@@ -133,6 +149,7 @@ func (c *context) reset() error {
 }
 
 func (c *context) blendFunc(mode driver.CompositeMode) {
+	mode = c.blendCompositeMode(mode)
 	_ = c.t.Call(func() error {
 		if c.lastCompositeMode == mode {
 			return nil
@@ -141,11 +158,32 @@ func (c *context) blendFunc(mode driver.CompositeMode) {
 		s, d := mode.Operations()
 		s2, d2 := convertOperation(s), convertOperation(d)
 		gl.BlendFunc(uint32(s2), uint32(d2))
+		rgb, alpha := mode.Equations()
+		gl.BlendEquationSeparate(uint32(convertEquation(rgb)), uint32(convertEquation(alpha)))
 		return nil
 	})
 }
 
 func (c *context) newTexture(width, height int) (textureNative, error) {
+	if c.srgb {
+		return c.newTextureWithFormat(width, height, gl.SRGB8_ALPHA8, gl.UNSIGNED_BYTE)
+	}
+	return c.newTextureWithFormat(width, height, gl.RGBA, gl.UNSIGNED_BYTE)
+}
+
+// newFloatTexture creates a texture with 16-bit floating-point values.
+// Use this only when hasFloatTextures returns true.
+func (c *context) newFloatTexture(width, height int) (textureNative, error) {
+	return c.newTextureWithFormat(width, height, gl.RGBA16F, gl.FLOAT)
+}
+
+// newAlphaTexture creates a texture with only the red channel, that is used for alpha values.
+// Use this only when hasAlphaTextures returns true.
+func (c *context) newAlphaTexture(width, height int) (textureNative, error) {
+	return c.newTextureWithFormat(width, height, gl.R8, gl.UNSIGNED_BYTE)
+}
+
+func (c *context) newTextureWithFormat(width, height int, internalFormat int32, typ uint32) (textureNative, error) {
 	var texture textureNative
 	if err := c.t.Call(func() error {
 		var t uint32
@@ -168,9 +206,13 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
 		// If data is nil, this just allocates memory and the content is undefined.
 		// https://www.khronos.org/registry/OpenGL-Refpages/gl4/html/glTexImage2D.xhtml
-		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, int32(width), int32(height), 0, gl.RGBA, typ, nil)
 		return nil
 	})
+	if c.isOutOfMemory() {
+		c.deleteTexture(texture)
+		return 0, &driver.OutOfMemoryError{Width: width, Height: height}
+	}
 	return texture, nil
 }
 
@@ -269,6 +311,135 @@ func (c *context) setViewportImpl(width, height int) {
 	})
 }
 
+func (c *context) setScissorImpl(clip image.Rectangle) {
+	_ = c.t.Call(func() error {
+		if clip.Empty() {
+			gl.Disable(gl.SCISSOR_TEST)
+			return nil
+		}
+		gl.Enable(gl.SCISSOR_TEST)
+		gl.Scissor(int32(clip.Min.X), int32(clip.Min.Y), int32(clip.Dx()), int32(clip.Dy()))
+		return nil
+	})
+}
+
+func (c *context) newStencilBuffer(width, height, samples int) (renderbuffer, error) {
+	if !gl.IsMultisampleSupported() {
+		return 0, errors.New("opengl: stencil buffers are not supported")
+	}
+	var r uint32
+	if err := c.t.Call(func() error {
+		gl.GenRenderbuffers(1, &r)
+		if r <= 0 {
+			return errors.New("opengl: creating renderbuffer failed")
+		}
+		gl.BindRenderbuffer(gl.RENDERBUFFER, r)
+		// glRenderbufferStorageMultisample with 0 samples is the same as glRenderbufferStorage.
+		gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, int32(samples), gl.STENCIL_INDEX8, int32(width), int32(height))
+		gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return renderbuffer(r), nil
+}
+
+func (c *context) attachStencilBuffer(f framebufferNative, r renderbuffer) error {
+	c.bindFramebuffer(f)
+	return c.t.Call(func() error {
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.STENCIL_ATTACHMENT, gl.RENDERBUFFER, uint32(r))
+		if s := gl.CheckFramebufferStatusEXT(gl.FRAMEBUFFER); s != gl.FRAMEBUFFER_COMPLETE {
+			return fmt.Errorf("opengl: attaching stencil buffer failed: %v", s)
+		}
+		return nil
+	})
+}
+
+func (c *context) newDepthBuffer(width, height, samples int) (renderbuffer, error) {
+	if !gl.IsMultisampleSupported() {
+		return 0, errors.New("opengl: depth buffers are not supported")
+	}
+	var r uint32
+	if err := c.t.Call(func() error {
+		gl.GenRenderbuffers(1, &r)
+		if r <= 0 {
+			return errors.New("opengl: creating renderbuffer failed")
+		}
+		gl.BindRenderbuffer(gl.RENDERBUFFER, r)
+		// glRenderbufferStorageMultisample with 0 samples is the same as glRenderbufferStorage.
+		gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, int32(samples), gl.DEPTH_COMPONENT16, int32(width), int32(height))
+		gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return renderbuffer(r), nil
+}
+
+func (c *context) attachDepthBuffer(f framebufferNative, r renderbuffer) error {
+	c.bindFramebuffer(f)
+	return c.t.Call(func() error {
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, uint32(r))
+		if s := gl.CheckFramebufferStatusEXT(gl.FRAMEBUFFER); s != gl.FRAMEBUFFER_COMPLETE {
+			return fmt.Errorf("opengl: attaching depth buffer failed: %v", s)
+		}
+		return nil
+	})
+}
+
+func (c *context) deleteRenderbuffer(r renderbuffer) {
+	_ = c.t.Call(func() error {
+		rr := uint32(r)
+		gl.DeleteRenderbuffers(1, &rr)
+		return nil
+	})
+}
+
+func (c *context) setStencilModeImpl(mode driver.StencilMode) {
+	_ = c.t.Call(func() error {
+		switch mode {
+		case driver.StencilModeNone:
+			gl.Disable(gl.STENCIL_TEST)
+			gl.ColorMask(true, true, true, true)
+		case driver.StencilModeWrite:
+			gl.Enable(gl.STENCIL_TEST)
+			gl.Clear(gl.STENCIL_BUFFER_BIT)
+			gl.StencilFunc(gl.ALWAYS, 1, 0xff)
+			gl.StencilOp(gl.KEEP, gl.KEEP, gl.REPLACE)
+			gl.ColorMask(false, false, false, false)
+		case driver.StencilModeTest:
+			gl.Enable(gl.STENCIL_TEST)
+			gl.StencilFunc(gl.EQUAL, 1, 0xff)
+			gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+			gl.ColorMask(true, true, true, true)
+		case driver.StencilModeTestInverted:
+			gl.Enable(gl.STENCIL_TEST)
+			gl.StencilFunc(gl.NOTEQUAL, 1, 0xff)
+			gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+			gl.ColorMask(true, true, true, true)
+		}
+		return nil
+	})
+}
+
+func (c *context) setDepthModeImpl(mode driver.DepthMode) {
+	_ = c.t.Call(func() error {
+		switch mode {
+		case driver.DepthModeNone:
+			gl.Disable(gl.DEPTH_TEST)
+		case driver.DepthModeClear:
+			gl.Disable(gl.DEPTH_TEST)
+			gl.DepthMask(true)
+			gl.Clear(gl.DEPTH_BUFFER_BIT)
+		case driver.DepthModeTest:
+			gl.Enable(gl.DEPTH_TEST)
+			gl.DepthFunc(gl.LEQUAL)
+			gl.DepthMask(true)
+		}
+		return nil
+	})
+}
+
 func (c *context) deleteFramebuffer(f framebufferNative) {
 	_ = c.t.Call(func() error {
 		ff := uint32(f)
@@ -376,9 +547,8 @@ func (c *context) getUniformLocationImpl(p program, location string) uniformLoca
 	l, free := gl.Strs(location + "\x00")
 	uniform := uniformLocation(gl.GetUniformLocation(uint32(p), *l))
 	free()
-	if uniform == -1 {
-		panic("opengl: invalid uniform location: " + location)
-	}
+	// The location is -1 when the uniform variable is not used in the program and is optimized out, which is
+	// possible with custom shaders. glUniform* functions silently ignore the location -1.
 	return uniform
 }
 
@@ -404,6 +574,8 @@ func (c *context) uniformFloats(p program, location string, v []float32) {
 		switch len(v) {
 		case 2:
 			gl.Uniform2fv(l, 1, (*float32)(gl.Ptr(v)))
+		case 3:
+			gl.Uniform3fv(l, 1, (*float32)(gl.Ptr(v)))
 		case 4:
 			gl.Uniform4fv(l, 1, (*float32)(gl.Ptr(v)))
 		case 16:
@@ -476,9 +648,9 @@ func (c *context) arrayBufferSubData(data []float32) {
 	})
 }
 
-func (c *context) elementArrayBufferSubData(data []uint16) {
+func (c *context) elementArrayBufferSubData(data []byte) {
 	_ = c.t.Call(func() error {
-		gl.BufferSubData(uint32(elementArrayBuffer), 0, len(data)*2, gl.Ptr(data))
+		gl.BufferSubData(uint32(elementArrayBuffer), 0, len(data), gl.Ptr(data))
 		return nil
 	})
 }
@@ -493,11 +665,21 @@ func (c *context) deleteBuffer(b buffer) {
 
 func (c *context) drawElements(len int, offsetInBytes int) {
 	_ = c.t.Call(func() error {
-		gl.DrawElements(gl.TRIANGLES, int32(len), gl.UNSIGNED_SHORT, uintptr(offsetInBytes))
+		gl.DrawElements(gl.TRIANGLES, int32(len), gl.UNSIGNED_INT, uintptr(offsetInBytes))
 		return nil
 	})
 }
 
+// isOutOfMemory reports whether the last commands failed due to the lack of memory.
+func (c *context) isOutOfMemory() bool {
+	r := false
+	_ = c.t.Call(func() error {
+		r = gl.GetError() == gl.OUT_OF_MEMORY
+		return nil
+	})
+	return r
+}
+
 func (c *context) maxTextureSizeImpl() int {
 	size := 0
 	_ = c.t.Call(func() error {
@@ -509,6 +691,57 @@ func (c *context) maxTextureSizeImpl() int {
 	return size
 }
 
+func (c *context) has32BitIndicesImpl() bool {
+	// 32-bit indices are always available on desktop OpenGL.
+	return true
+}
+
+func (c *context) hasFloatTexturesImpl() bool {
+	var exts string
+	_ = c.t.Call(func() error {
+		exts = gl.GoStr(gl.GetString(gl.EXTENSIONS))
+		return nil
+	})
+	// RGBA16F is a core format as of OpenGL 3.0. With OpenGL 2.1, the extension is required.
+	return strings.Contains(exts, "GL_ARB_texture_float")
+}
+
+func (c *context) hasBlendMinMaxImpl() bool {
+	// MIN and MAX are core features as of OpenGL 1.4.
+	return true
+}
+
+func (c *context) hasAlphaTexturesImpl() bool {
+	var exts string
+	_ = c.t.Call(func() error {
+		exts = gl.GoStr(gl.GetString(gl.EXTENSIONS))
+		return nil
+	})
+	// R8 is a core format as of OpenGL 3.0. With OpenGL 2.1, the extension is required.
+	return strings.Contains(exts, "GL_ARB_texture_rg")
+}
+
+func (c *context) hasSRGBImpl() bool {
+	var exts string
+	_ = c.t.Call(func() error {
+		exts = gl.GoStr(gl.GetString(gl.EXTENSIONS))
+		return nil
+	})
+	// sRGB textures are a core feature as of OpenGL 2.1, but sRGB framebuffers require OpenGL 3.0 or the extensions.
+	return strings.Contains(exts, "GL_ARB_framebuffer_sRGB") || strings.Contains(exts, "GL_EXT_framebuffer_sRGB")
+}
+
+func (c *context) setFramebufferSRGBImpl(enabled bool) {
+	_ = c.t.Call(func() error {
+		if enabled {
+			gl.Enable(gl.FRAMEBUFFER_SRGB)
+			return nil
+		}
+		gl.Disable(gl.FRAMEBUFFER_SRGB)
+		return nil
+	})
+}
+
 func (c *context) getShaderPrecisionFormatPrecision() int {
 	// glGetShaderPrecisionFormat is not defined at OpenGL 2.0. Assume that desktop environments always have
 	// enough highp precision.
@@ -522,6 +755,61 @@ func (c *context) flush() {
 	})
 }
 
+func (c *context) finish() {
+	_ = c.t.Call(func() error {
+		gl.Finish()
+		return nil
+	})
+}
+
+func (c *context) isSyncSupported() bool {
+	return gl.IsSyncSupported()
+}
+
+func (c *context) fenceSync() uintptr {
+	var s uintptr
+	_ = c.t.Call(func() error {
+		s = gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0)
+		return nil
+	})
+	return s
+}
+
+func (c *context) clientWaitSync(s uintptr) {
+	_ = c.t.Call(func() error {
+		// Flush the commands at the first wait so that the fence is surely signaled.
+		flags := uint32(gl.SYNC_FLUSH_COMMANDS_BIT)
+		for {
+			const timeout = 1000 * 1000 * 1000 // 1 [s] in nanoseconds
+			switch gl.ClientWaitSync(s, flags, timeout) {
+			case gl.ALREADY_SIGNALED, gl.CONDITION_SATISFIED, gl.WAIT_FAILED:
+				return nil
+			}
+			flags = 0
+		}
+	})
+}
+
+// isSyncSignaled reports whether the sync object is signaled without blocking.
+func (c *context) isSyncSignaled(s uintptr) bool {
+	var signaled bool
+	_ = c.t.Call(func() error {
+		switch gl.ClientWaitSync(s, 0, 0) {
+		case gl.ALREADY_SIGNALED, gl.CONDITION_SATISFIED, gl.WAIT_FAILED:
+			signaled = true
+		}
+		return nil
+	})
+	return signaled
+}
+
+func (c *context) deleteSync(s uintptr) {
+	_ = c.t.Call(func() error {
+		gl.DeleteSync(s)
+		return nil
+	})
+}
+
 func (c *context) needsRestoring() bool {
 	return false
 }
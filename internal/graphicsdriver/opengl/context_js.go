@@ -19,6 +19,7 @@ package opengl
 import (
 	"errors"
 	"fmt"
+	"image"
 	"syscall/js"
 
 	"github.com/hajimehoshi/ebiten/internal/driver"
@@ -29,6 +30,7 @@ import (
 type (
 	textureNative     js.Value
 	framebufferNative js.Value
+	renderbuffer      js.Value
 	shader            js.Value
 	buffer            js.Value
 	uniformLocation   js.Value
@@ -89,19 +91,42 @@ var (
 	oneMinusSrcAlpha operation
 	oneMinusDstAlpha operation
 
+	funcAdd             equation
+	funcSubtract        equation
+	funcReverseSubtract equation
+	funcMin             equation
+	funcMax             equation
+
+	always              js.Value
 	blend               js.Value
 	clampToEdge         js.Value
 	compileStatus       js.Value
 	colorAttachment0    js.Value
+	depthAttachment     js.Value
+	depthBufferBit      js.Value
+	depthComponent16    js.Value
+	depthTest           js.Value
+	equal               js.Value
 	framebuffer_        js.Value
 	framebufferBinding  js.Value
 	framebufferComplete js.Value
 	highFloat           js.Value
+	keep                js.Value
+	lequal              js.Value
 	linkStatus          js.Value
 	maxTextureSize      js.Value
 	nearest             js.Value
 	noError             js.Value
+	notEqual            js.Value
+	outOfMemory         js.Value
+	renderbuffer_       js.Value
+	replace             js.Value
 	rgba                js.Value
+	scissorTest         js.Value
+	stencilAttachment   js.Value
+	stencilBufferBit    js.Value
+	stencilIndex8       js.Value
+	stencilTest         js.Value
 	texture2d           js.Value
 	textureMagFilter    js.Value
 	textureMinFilter    js.Value
@@ -110,6 +135,7 @@ var (
 	triangles           js.Value
 	unpackAlignment     js.Value
 	unsignedByte        js.Value
+	unsignedInt         js.Value
 	unsignedShort       js.Value
 
 	isWebGL2Available bool
@@ -141,19 +167,48 @@ func init() {
 	oneMinusSrcAlpha = operation(contextPrototype.Get("ONE_MINUS_SRC_ALPHA").Int())
 	oneMinusDstAlpha = operation(contextPrototype.Get("ONE_MINUS_DST_ALPHA").Int())
 
+	funcAdd = equation(contextPrototype.Get("FUNC_ADD").Int())
+	funcSubtract = equation(contextPrototype.Get("FUNC_SUBTRACT").Int())
+	funcReverseSubtract = equation(contextPrototype.Get("FUNC_REVERSE_SUBTRACT").Int())
+	if isWebGL2Available {
+		funcMin = equation(contextPrototype.Get("MIN").Int())
+		funcMax = equation(contextPrototype.Get("MAX").Int())
+	} else {
+		// These are the values of MIN_EXT and MAX_EXT of EXT_blend_minmax.
+		funcMin = equation(0x8007)
+		funcMax = equation(0x8008)
+	}
+
+	always = contextPrototype.Get("ALWAYS")
 	blend = contextPrototype.Get("BLEND")
 	clampToEdge = contextPrototype.Get("CLAMP_TO_EDGE")
 	compileStatus = contextPrototype.Get("COMPILE_STATUS")
 	colorAttachment0 = contextPrototype.Get("COLOR_ATTACHMENT0")
+	depthAttachment = contextPrototype.Get("DEPTH_ATTACHMENT")
+	depthBufferBit = contextPrototype.Get("DEPTH_BUFFER_BIT")
+	depthComponent16 = contextPrototype.Get("DEPTH_COMPONENT16")
+	depthTest = contextPrototype.Get("DEPTH_TEST")
+	equal = contextPrototype.Get("EQUAL")
 	framebuffer_ = contextPrototype.Get("FRAMEBUFFER")
 	framebufferBinding = contextPrototype.Get("FRAMEBUFFER_BINDING")
 	framebufferComplete = contextPrototype.Get("FRAMEBUFFER_COMPLETE")
 	highFloat = contextPrototype.Get("HIGH_FLOAT")
+	keep = contextPrototype.Get("KEEP")
+	lequal = contextPrototype.Get("LEQUAL")
 	linkStatus = contextPrototype.Get("LINK_STATUS")
 	maxTextureSize = contextPrototype.Get("MAX_TEXTURE_SIZE")
 	nearest = contextPrototype.Get("NEAREST")
 	noError = contextPrototype.Get("NO_ERROR")
+	notEqual = contextPrototype.Get("NOTEQUAL")
+	outOfMemory = contextPrototype.Get("OUT_OF_MEMORY")
+	renderbuffer_ = contextPrototype.Get("RENDERBUFFER")
+	replace = contextPrototype.Get("REPLACE")
 	rgba = contextPrototype.Get("RGBA")
+	scissorTest = contextPrototype.Get("SCISSOR_TEST")
+	stencilAttachment = contextPrototype.Get("STENCIL_ATTACHMENT")
+	stencilBufferBit = contextPrototype.Get("STENCIL_BUFFER_BIT")
+	stencilIndex8 = contextPrototype.Get("STENCIL_INDEX8")
+	stencilTest = contextPrototype.Get("STENCIL_TEST")
 	texture2d = contextPrototype.Get("TEXTURE_2D")
 	textureMagFilter = contextPrototype.Get("TEXTURE_MAG_FILTER")
 	textureMinFilter = contextPrototype.Get("TEXTURE_MIN_FILTER")
@@ -162,6 +217,7 @@ func init() {
 	triangles = contextPrototype.Get("TRIANGLES")
 	unpackAlignment = contextPrototype.Get("UNPACK_ALIGNMENT")
 	unsignedByte = contextPrototype.Get("UNSIGNED_BYTE")
+	unsignedInt = contextPrototype.Get("UNSIGNED_INT")
 	unsignedShort = contextPrototype.Get("UNSIGNED_SHORT")
 
 	if isWebGL2Available {
@@ -198,6 +254,11 @@ func (c *context) ensureGL() {
 		}
 	}
 
+	if !isWebGL2Available {
+		// Enable MIN_EXT and MAX_EXT for blend equations.
+		gl.Call("getExtension", "EXT_blend_minmax")
+	}
+
 	c.gl = gl
 }
 
@@ -208,6 +269,9 @@ func (c *context) reset() error {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = driver.CompositeModeUnknown
+	c.lastScissor = nil
+	c.lastStencilMode = driver.StencilModeNone
+	c.lastDepthMode = driver.DepthModeNone
 
 	c.gl = js.Value{}
 	c.ensureGL()
@@ -223,6 +287,7 @@ func (c *context) reset() error {
 }
 
 func (c *context) blendFunc(mode driver.CompositeMode) {
+	mode = c.blendCompositeMode(mode)
 	if c.lastCompositeMode == mode {
 		return
 	}
@@ -232,6 +297,8 @@ func (c *context) blendFunc(mode driver.CompositeMode) {
 	c.ensureGL()
 	gl := c.gl
 	gl.Call("blendFunc", int(s2), int(d2))
+	rgb, alpha := mode.Equations()
+	gl.Call("blendEquationSeparate", int(convertEquation(rgb)), int(convertEquation(alpha)))
 }
 
 func (c *context) newTexture(width, height int) (textureNative, error) {
@@ -257,6 +324,10 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	// to leave textures as uninitialized here. Rather, extra memory allocating for initialization should be
 	// avoided.
 	gl.Call("texImage2D", texture2d, 0, rgba, width, height, 0, rgba, unsignedByte, nil)
+	if c.isOutOfMemory() {
+		c.deleteTexture(textureNative(t))
+		return textureNative(js.Null()), &driver.OutOfMemoryError{Width: width, Height: height}
+	}
 
 	return textureNative(t), nil
 }
@@ -323,6 +394,114 @@ func (c *context) setViewportImpl(width, height int) {
 	gl.Call("viewport", 0, 0, width, height)
 }
 
+func (c *context) setScissorImpl(clip image.Rectangle) {
+	c.ensureGL()
+	gl := c.gl
+	if clip.Empty() {
+		gl.Call("disable", scissorTest)
+		return
+	}
+	gl.Call("enable", scissorTest)
+	gl.Call("scissor", clip.Min.X, clip.Min.Y, clip.Dx(), clip.Dy())
+}
+
+func (c *context) newStencilBuffer(width, height, samples int) (renderbuffer, error) {
+	c.ensureGL()
+	gl := c.gl
+	r := gl.Call("createRenderbuffer")
+	if jsutil.Equal(r, js.Null()) {
+		return renderbuffer(js.Null()), errors.New("opengl: creating renderbuffer failed")
+	}
+	gl.Call("bindRenderbuffer", renderbuffer_, r)
+	gl.Call("renderbufferStorage", renderbuffer_, stencilIndex8, width, height)
+	gl.Call("bindRenderbuffer", renderbuffer_, nil)
+	return renderbuffer(r), nil
+}
+
+func (c *context) attachStencilBuffer(f framebufferNative, r renderbuffer) error {
+	c.ensureGL()
+	c.bindFramebuffer(f)
+	gl := c.gl
+	gl.Call("framebufferRenderbuffer", framebuffer_, stencilAttachment, renderbuffer_, js.Value(r))
+	if s := gl.Call("checkFramebufferStatus", framebuffer_); s.Int() != framebufferComplete.Int() {
+		return fmt.Errorf("opengl: attaching stencil buffer failed: %d", s.Int())
+	}
+	return nil
+}
+
+func (c *context) newDepthBuffer(width, height, samples int) (renderbuffer, error) {
+	c.ensureGL()
+	gl := c.gl
+	r := gl.Call("createRenderbuffer")
+	if jsutil.Equal(r, js.Null()) {
+		return renderbuffer(js.Null()), errors.New("opengl: creating renderbuffer failed")
+	}
+	gl.Call("bindRenderbuffer", renderbuffer_, r)
+	gl.Call("renderbufferStorage", renderbuffer_, depthComponent16, width, height)
+	gl.Call("bindRenderbuffer", renderbuffer_, nil)
+	return renderbuffer(r), nil
+}
+
+func (c *context) attachDepthBuffer(f framebufferNative, r renderbuffer) error {
+	c.ensureGL()
+	c.bindFramebuffer(f)
+	gl := c.gl
+	gl.Call("framebufferRenderbuffer", framebuffer_, depthAttachment, renderbuffer_, js.Value(r))
+	if s := gl.Call("checkFramebufferStatus", framebuffer_); s.Int() != framebufferComplete.Int() {
+		return fmt.Errorf("opengl: attaching depth buffer failed: %d", s.Int())
+	}
+	return nil
+}
+
+func (c *context) deleteRenderbuffer(r renderbuffer) {
+	c.ensureGL()
+	gl := c.gl
+	gl.Call("deleteRenderbuffer", js.Value(r))
+}
+
+func (c *context) setStencilModeImpl(mode driver.StencilMode) {
+	c.ensureGL()
+	gl := c.gl
+	switch mode {
+	case driver.StencilModeNone:
+		gl.Call("disable", stencilTest)
+		gl.Call("colorMask", true, true, true, true)
+	case driver.StencilModeWrite:
+		gl.Call("enable", stencilTest)
+		gl.Call("clear", stencilBufferBit)
+		gl.Call("stencilFunc", always, 1, 0xff)
+		gl.Call("stencilOp", keep, keep, replace)
+		gl.Call("colorMask", false, false, false, false)
+	case driver.StencilModeTest:
+		gl.Call("enable", stencilTest)
+		gl.Call("stencilFunc", equal, 1, 0xff)
+		gl.Call("stencilOp", keep, keep, keep)
+		gl.Call("colorMask", true, true, true, true)
+	case driver.StencilModeTestInverted:
+		gl.Call("enable", stencilTest)
+		gl.Call("stencilFunc", notEqual, 1, 0xff)
+		gl.Call("stencilOp", keep, keep, keep)
+		gl.Call("colorMask", true, true, true, true)
+	}
+}
+
+func (c *context) setDepthModeImpl(mode driver.DepthMode) {
+	c.ensureGL()
+	gl := c.gl
+	switch mode {
+	case driver.DepthModeNone:
+		gl.Call("disable", depthTest)
+	case driver.DepthModeClear:
+		gl.Call("disable", depthTest)
+		gl.Call("depthMask", true)
+		gl.Call("clear", depthBufferBit)
+	case driver.DepthModeTest:
+		gl.Call("enable", depthTest)
+		gl.Call("depthFunc", lequal)
+		gl.Call("depthMask", true)
+	}
+}
+
 func (c *context) deleteFramebuffer(f framebufferNative) {
 	c.ensureGL()
 	gl := c.gl
@@ -435,6 +614,8 @@ func (c *context) uniformFloats(p program, location string, v []float32) {
 	switch len(v) {
 	case 2:
 		gl.Call("uniform2f", js.Value(l), v[0], v[1])
+	case 3:
+		gl.Call("uniform3f", js.Value(l), v[0], v[1], v[2])
 	case 4:
 		gl.Call("uniform4f", js.Value(l), v[0], v[1], v[2], v[3])
 	case 16:
@@ -498,11 +679,10 @@ func (c *context) arrayBufferSubData(data []float32) {
 	gl.Call("bufferSubData", int(arrayBuffer), 0, arr)
 }
 
-func (c *context) elementArrayBufferSubData(data []uint16) {
+func (c *context) elementArrayBufferSubData(data []byte) {
 	c.ensureGL()
 	gl := c.gl
-	arr8 := jsutil.TemporaryUint8Array(len(data) * 2)
-	arr := js.Global().Get("Uint16Array").New(arr8.Get("buffer"), arr8.Get("byteOffset"), len(data))
+	arr := jsutil.TemporaryUint8Array(len(data))
 	jsutil.CopySliceToJS(arr, data)
 	gl.Call("bufferSubData", int(elementArrayBuffer), 0, arr)
 }
@@ -516,7 +696,17 @@ func (c *context) deleteBuffer(b buffer) {
 func (c *context) drawElements(len int, offsetInBytes int) {
 	c.ensureGL()
 	gl := c.gl
-	gl.Call("drawElements", triangles, len, unsignedShort, offsetInBytes)
+	t := unsignedShort
+	if c.has32BitIndices() {
+		t = unsignedInt
+	}
+	gl.Call("drawElements", triangles, len, t, offsetInBytes)
+}
+
+// isOutOfMemory reports whether the last commands failed due to the lack of memory.
+func (c *context) isOutOfMemory() bool {
+	c.ensureGL()
+	return jsutil.Equal(c.gl.Call("getError"), outOfMemory)
 }
 
 func (c *context) maxTextureSizeImpl() int {
@@ -525,6 +715,55 @@ func (c *context) maxTextureSizeImpl() int {
 	return gl.Call("getParameter", maxTextureSize).Int()
 }
 
+func (c *context) has32BitIndicesImpl() bool {
+	if isWebGL2Available {
+		return true
+	}
+	c.ensureGL()
+	// Getting the extension also enables it.
+	return !jsutil.Equal(c.gl.Call("getExtension", "OES_element_index_uint"), js.Null())
+}
+
+func (c *context) hasFloatTexturesImpl() bool {
+	// TODO: Use EXT_color_buffer_float or EXT_color_buffer_half_float.
+	// Uploading and reading pixels of floating-point textures require conversions on WebGL, which are not
+	// implemented yet.
+	return false
+}
+
+func (c *context) hasBlendMinMaxImpl() bool {
+	if isWebGL2Available {
+		return true
+	}
+	c.ensureGL()
+	return !jsutil.Equal(c.gl.Call("getExtension", "EXT_blend_minmax"), js.Null())
+}
+
+func (c *context) hasAlphaTexturesImpl() bool {
+	// TODO: Use R8 on WebGL 2. Uploading pixels to R8 textures requires the RED format, that is not implemented
+	// yet.
+	return false
+}
+
+// newAlphaTexture creates a regular texture since alpha-only textures are not available.
+func (c *context) newAlphaTexture(width, height int) (textureNative, error) {
+	return c.newTexture(width, height)
+}
+
+func (c *context) hasSRGBImpl() bool {
+	// TODO: Use SRGB8_ALPHA8 on WebGL 2.
+	return false
+}
+
+func (c *context) setFramebufferSRGBImpl(enabled bool) {
+	// Do nothing as the sRGB pipeline is not available.
+}
+
+// newFloatTexture creates a regular texture since floating-point textures are not available.
+func (c *context) newFloatTexture(width, height int) (textureNative, error) {
+	return c.newTexture(width, height)
+}
+
 func (c *context) getShaderPrecisionFormatPrecision() int {
 	c.ensureGL()
 	gl := c.gl
@@ -537,6 +776,12 @@ func (c *context) flush() {
 	gl.Call("flush")
 }
 
+func (c *context) finish() {
+	c.ensureGL()
+	gl := c.gl
+	gl.Call("finish")
+}
+
 func (c *context) needsRestoring() bool {
 	return !web.IsMobileBrowser()
 }
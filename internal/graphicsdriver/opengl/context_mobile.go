@@ -19,6 +19,8 @@ package opengl
 import (
 	"errors"
 	"fmt"
+	"image"
+	"strings"
 
 	mgl "golang.org/x/mobile/gl"
 
@@ -28,6 +30,7 @@ import (
 type (
 	textureNative     mgl.Texture
 	framebufferNative mgl.Framebuffer
+	renderbuffer      mgl.Renderbuffer
 	shader            mgl.Shader
 	program           mgl.Program
 	buffer            mgl.Buffer
@@ -90,6 +93,15 @@ const (
 	dstAlpha         = operation(mgl.DST_ALPHA)
 	oneMinusSrcAlpha = operation(mgl.ONE_MINUS_SRC_ALPHA)
 	oneMinusDstAlpha = operation(mgl.ONE_MINUS_DST_ALPHA)
+
+	funcAdd             = equation(mgl.FUNC_ADD)
+	funcSubtract        = equation(mgl.FUNC_SUBTRACT)
+	funcReverseSubtract = equation(mgl.FUNC_REVERSE_SUBTRACT)
+
+	// MIN and MAX are available with OpenGL ES 3.0 or EXT_blend_minmax. The values are the same as MIN_EXT and
+	// MAX_EXT.
+	funcMin = equation(0x8007)
+	funcMax = equation(0x8008)
 )
 
 type contextImpl struct {
@@ -103,6 +115,9 @@ func (c *context) reset() error {
 	c.lastViewportWidth = 0
 	c.lastViewportHeight = 0
 	c.lastCompositeMode = driver.CompositeModeUnknown
+	c.lastScissor = nil
+	c.lastStencilMode = driver.StencilModeNone
+	c.lastDepthMode = driver.DepthModeNone
 	c.gl.Enable(mgl.BLEND)
 	c.blendFunc(driver.CompositeModeSourceOver)
 	f := c.gl.GetInteger(mgl.FRAMEBUFFER_BINDING)
@@ -113,6 +128,7 @@ func (c *context) reset() error {
 
 func (c *context) blendFunc(mode driver.CompositeMode) {
 	gl := c.gl
+	mode = c.blendCompositeMode(mode)
 	if c.lastCompositeMode == mode {
 		return
 	}
@@ -120,6 +136,8 @@ func (c *context) blendFunc(mode driver.CompositeMode) {
 	s, d := mode.Operations()
 	s2, d2 := convertOperation(s), convertOperation(d)
 	gl.BlendFunc(mgl.Enum(s2), mgl.Enum(d2))
+	rgb, alpha := mode.Equations()
+	gl.BlendEquationSeparate(mgl.Enum(convertEquation(rgb)), mgl.Enum(convertEquation(alpha)))
 }
 
 func (c *context) newTexture(width, height int) (textureNative, error) {
@@ -136,6 +154,10 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	gl.TexParameteri(mgl.TEXTURE_2D, mgl.TEXTURE_WRAP_S, mgl.CLAMP_TO_EDGE)
 	gl.TexParameteri(mgl.TEXTURE_2D, mgl.TEXTURE_WRAP_T, mgl.CLAMP_TO_EDGE)
 	gl.TexImage2D(mgl.TEXTURE_2D, 0, mgl.RGBA, width, height, mgl.RGBA, mgl.UNSIGNED_BYTE, nil)
+	if c.isOutOfMemory() {
+		c.deleteTexture(textureNative(t))
+		return textureNative{}, &driver.OutOfMemoryError{Width: width, Height: height}
+	}
 
 	return textureNative(t), nil
 }
@@ -204,6 +226,106 @@ func (c *context) setViewportImpl(width, height int) {
 	gl.Viewport(0, 0, width, height)
 }
 
+func (c *context) setScissorImpl(clip image.Rectangle) {
+	gl := c.gl
+	if clip.Empty() {
+		gl.Disable(mgl.SCISSOR_TEST)
+		return
+	}
+	gl.Enable(mgl.SCISSOR_TEST)
+	gl.Scissor(int32(clip.Min.X), int32(clip.Min.Y), int32(clip.Dx()), int32(clip.Dy()))
+}
+
+func (c *context) newStencilBuffer(width, height, samples int) (renderbuffer, error) {
+	gl := c.gl
+	r := gl.CreateRenderbuffer()
+	if r.Value <= 0 {
+		return renderbuffer{}, errors.New("opengl: creating renderbuffer failed")
+	}
+	gl.BindRenderbuffer(mgl.RENDERBUFFER, r)
+	gl.RenderbufferStorage(mgl.RENDERBUFFER, mgl.STENCIL_INDEX8, width, height)
+	gl.BindRenderbuffer(mgl.RENDERBUFFER, mgl.Renderbuffer{})
+	return renderbuffer(r), nil
+}
+
+func (c *context) attachStencilBuffer(f framebufferNative, r renderbuffer) error {
+	c.bindFramebuffer(f)
+	gl := c.gl
+	gl.FramebufferRenderbuffer(mgl.FRAMEBUFFER, mgl.STENCIL_ATTACHMENT, mgl.RENDERBUFFER, mgl.Renderbuffer(r))
+	if s := gl.CheckFramebufferStatus(mgl.FRAMEBUFFER); s != mgl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("opengl: attaching stencil buffer failed: %v", s)
+	}
+	return nil
+}
+
+func (c *context) newDepthBuffer(width, height, samples int) (renderbuffer, error) {
+	gl := c.gl
+	r := gl.CreateRenderbuffer()
+	if r.Value <= 0 {
+		return renderbuffer{}, errors.New("opengl: creating renderbuffer failed")
+	}
+	gl.BindRenderbuffer(mgl.RENDERBUFFER, r)
+	gl.RenderbufferStorage(mgl.RENDERBUFFER, mgl.DEPTH_COMPONENT16, width, height)
+	gl.BindRenderbuffer(mgl.RENDERBUFFER, mgl.Renderbuffer{})
+	return renderbuffer(r), nil
+}
+
+func (c *context) attachDepthBuffer(f framebufferNative, r renderbuffer) error {
+	c.bindFramebuffer(f)
+	gl := c.gl
+	gl.FramebufferRenderbuffer(mgl.FRAMEBUFFER, mgl.DEPTH_ATTACHMENT, mgl.RENDERBUFFER, mgl.Renderbuffer(r))
+	if s := gl.CheckFramebufferStatus(mgl.FRAMEBUFFER); s != mgl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("opengl: attaching depth buffer failed: %v", s)
+	}
+	return nil
+}
+
+func (c *context) deleteRenderbuffer(r renderbuffer) {
+	gl := c.gl
+	gl.DeleteRenderbuffer(mgl.Renderbuffer(r))
+}
+
+func (c *context) setStencilModeImpl(mode driver.StencilMode) {
+	gl := c.gl
+	switch mode {
+	case driver.StencilModeNone:
+		gl.Disable(mgl.STENCIL_TEST)
+		gl.ColorMask(true, true, true, true)
+	case driver.StencilModeWrite:
+		gl.Enable(mgl.STENCIL_TEST)
+		gl.Clear(mgl.STENCIL_BUFFER_BIT)
+		gl.StencilFunc(mgl.ALWAYS, 1, 0xff)
+		gl.StencilOp(mgl.KEEP, mgl.KEEP, mgl.REPLACE)
+		gl.ColorMask(false, false, false, false)
+	case driver.StencilModeTest:
+		gl.Enable(mgl.STENCIL_TEST)
+		gl.StencilFunc(mgl.EQUAL, 1, 0xff)
+		gl.StencilOp(mgl.KEEP, mgl.KEEP, mgl.KEEP)
+		gl.ColorMask(true, true, true, true)
+	case driver.StencilModeTestInverted:
+		gl.Enable(mgl.STENCIL_TEST)
+		gl.StencilFunc(mgl.NOTEQUAL, 1, 0xff)
+		gl.StencilOp(mgl.KEEP, mgl.KEEP, mgl.KEEP)
+		gl.ColorMask(true, true, true, true)
+	}
+}
+
+func (c *context) setDepthModeImpl(mode driver.DepthMode) {
+	gl := c.gl
+	switch mode {
+	case driver.DepthModeNone:
+		gl.Disable(mgl.DEPTH_TEST)
+	case driver.DepthModeClear:
+		gl.Disable(mgl.DEPTH_TEST)
+		gl.DepthMask(true)
+		gl.Clear(mgl.DEPTH_BUFFER_BIT)
+	case driver.DepthModeTest:
+		gl.Enable(mgl.DEPTH_TEST)
+		gl.DepthFunc(mgl.LEQUAL)
+		gl.DepthMask(true)
+	}
+}
+
 func (c *context) deleteFramebuffer(f framebufferNative) {
 	gl := c.gl
 	if !gl.IsFramebuffer(mgl.Framebuffer(f)) {
@@ -280,11 +402,9 @@ func (c *context) deleteProgram(p program) {
 
 func (c *context) getUniformLocationImpl(p program, location string) uniformLocation {
 	gl := c.gl
-	u := uniformLocation(gl.GetUniformLocation(mgl.Program(p), location))
-	if u.Value == -1 {
-		panic("invalid uniform location: " + location)
-	}
-	return u
+	// The location is -1 when the uniform variable is not used in the program and is optimized out, which is
+	// possible with custom shaders. glUniform* functions silently ignore the location -1.
+	return uniformLocation(gl.GetUniformLocation(mgl.Program(p), location))
 }
 
 func (c *context) uniformInt(p program, location string, v int) {
@@ -303,6 +423,8 @@ func (c *context) uniformFloats(p program, location string, v []float32) {
 	switch len(v) {
 	case 2:
 		gl.Uniform2fv(l, v)
+	case 3:
+		gl.Uniform3fv(l, v)
 	case 4:
 		gl.Uniform4fv(l, v)
 	case 16:
@@ -353,9 +475,9 @@ func (c *context) arrayBufferSubData(data []float32) {
 	gl.BufferSubData(mgl.Enum(arrayBuffer), 0, float32sToBytes(data))
 }
 
-func (c *context) elementArrayBufferSubData(data []uint16) {
+func (c *context) elementArrayBufferSubData(data []byte) {
 	gl := c.gl
-	gl.BufferSubData(mgl.Enum(elementArrayBuffer), 0, uint16sToBytes(data))
+	gl.BufferSubData(mgl.Enum(elementArrayBuffer), 0, data)
 }
 
 func (c *context) deleteBuffer(b buffer) {
@@ -365,7 +487,16 @@ func (c *context) deleteBuffer(b buffer) {
 
 func (c *context) drawElements(len int, offsetInBytes int) {
 	gl := c.gl
-	gl.DrawElements(mgl.TRIANGLES, len, mgl.UNSIGNED_SHORT, offsetInBytes)
+	t := mgl.Enum(mgl.UNSIGNED_SHORT)
+	if c.has32BitIndices() {
+		t = mgl.UNSIGNED_INT
+	}
+	gl.DrawElements(mgl.TRIANGLES, len, t, offsetInBytes)
+}
+
+// isOutOfMemory reports whether the last commands failed due to the lack of memory.
+func (c *context) isOutOfMemory() bool {
+	return c.gl.GetError() == mgl.OUT_OF_MEMORY
 }
 
 func (c *context) maxTextureSizeImpl() int {
@@ -373,6 +504,53 @@ func (c *context) maxTextureSizeImpl() int {
 	return gl.GetInteger(mgl.MAX_TEXTURE_SIZE)
 }
 
+func (c *context) has32BitIndicesImpl() bool {
+	gl := c.gl
+	// OpenGL ES 2.0 requires the extension for 32-bit indices.
+	return strings.Contains(gl.GetString(mgl.EXTENSIONS), "GL_OES_element_index_uint")
+}
+
+func (c *context) hasFloatTexturesImpl() bool {
+	// TODO: Use EXT_color_buffer_float or EXT_color_buffer_half_float.
+	// Uploading and reading pixels of floating-point textures require conversions on OpenGL ES, which are not
+	// implemented yet.
+	return false
+}
+
+func (c *context) hasBlendMinMaxImpl() bool {
+	gl := c.gl
+	// MIN and MAX are core features as of OpenGL ES 3.0. OpenGL ES 2.0 requires the extension.
+	if strings.HasPrefix(gl.GetString(mgl.VERSION), "OpenGL ES 3") {
+		return true
+	}
+	return strings.Contains(gl.GetString(mgl.EXTENSIONS), "GL_EXT_blend_minmax")
+}
+
+func (c *context) hasAlphaTexturesImpl() bool {
+	// TODO: Use R8 on OpenGL ES 3. Uploading pixels to R8 textures requires the RED format, that is not implemented
+	// yet.
+	return false
+}
+
+// newAlphaTexture creates a regular texture since alpha-only textures are not available.
+func (c *context) newAlphaTexture(width, height int) (textureNative, error) {
+	return c.newTexture(width, height)
+}
+
+func (c *context) hasSRGBImpl() bool {
+	// TODO: Use SRGB8_ALPHA8 on OpenGL ES 3.
+	return false
+}
+
+func (c *context) setFramebufferSRGBImpl(enabled bool) {
+	// Do nothing as the sRGB pipeline is not available.
+}
+
+// newFloatTexture creates a regular texture since floating-point textures are not available.
+func (c *context) newFloatTexture(width, height int) (textureNative, error) {
+	return c.newTexture(width, height)
+}
+
 func (c *context) getShaderPrecisionFormatPrecision() int {
 	gl := c.gl
 	_, _, p := gl.GetShaderPrecisionFormat(mgl.FRAGMENT_SHADER, mgl.HIGH_FLOAT)
@@ -384,6 +562,11 @@ func (c *context) flush() {
 	gl.Flush()
 }
 
+func (c *context) finish() {
+	gl := c.gl
+	gl.Finish()
+}
+
 func (c *context) needsRestoring() bool {
 	return true
 }
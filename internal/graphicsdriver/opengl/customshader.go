@@ -0,0 +1,133 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	shaderprogram "github.com/hajimehoshi/ebiten/internal/shader"
+)
+
+// Shader is a custom shader program compiled from the shading language.
+type Shader struct {
+	driver  *Driver
+	program *shaderprogram.Program
+	p       program
+}
+
+func (d *Driver) newShader(prog *shaderprogram.Program) (*Shader, error) {
+	vs, err := d.context.newShader(vertexShader, vertexShaderStr(true))
+	if err != nil {
+		return nil, err
+	}
+	defer d.context.deleteShader(vs)
+
+	src := prog.GLSLFragmentShader()
+	checkGLSL(src)
+	fs, err := d.context.newShader(fragmentShader, src)
+	if err != nil {
+		return nil, err
+	}
+	defer d.context.deleteShader(fs)
+
+	p, err := d.context.newProgram([]shader{vs, fs}, theArrayBufferLayout.names())
+	if err != nil {
+		return nil, err
+	}
+	return &Shader{
+		driver:  d,
+		program: prog,
+		p:       p,
+	}, nil
+}
+
+func (s *Shader) Dispose() {
+	if s.driver.state.lastProgram.equal(s.p) {
+		s.driver.state.lastProgram = zeroProgram
+	}
+	s.driver.context.locationCache.Remove(s.p)
+	s.driver.context.deleteProgram(s.p)
+}
+
+// useShader uses the custom shader program.
+func (d *Driver) useShader(shader *Shader, uniforms [][]float32, mode driver.CompositeMode) error {
+	destination := d.state.destination
+	if destination == nil {
+		panic("destination image is not set")
+	}
+	source := d.state.source
+	if source == nil {
+		panic("source image is not set")
+	}
+
+	if err := destination.setViewport(); err != nil {
+		return err
+	}
+
+	if d.context.srgb {
+		d.context.setFramebufferSRGB(!destination.screen)
+	}
+	d.context.blendFunc(mode)
+
+	program := shader.p
+	if !d.state.lastProgram.equal(program) {
+		d.context.useProgram(program)
+		if d.state.lastProgram.equal(zeroProgram) {
+			theArrayBufferLayout.enable(&d.context, program)
+			d.context.bindBuffer(arrayBuffer, d.state.arrayBuffer)
+			d.context.bindBuffer(elementArrayBuffer, d.state.elementArrayBuffer)
+		}
+		d.context.uniformInt(program, "texture", 0)
+
+		d.state.lastProgram = program
+		d.state.lastViewportWidth = 0
+		d.state.lastViewportHeight = 0
+		d.state.lastColorMatrix = nil
+		d.state.lastColorMatrixTranslation = nil
+		d.state.lastSourceWidth = 0
+		d.state.lastSourceHeight = 0
+	}
+
+	vw := destination.framebuffer.width
+	vh := destination.framebuffer.height
+	if d.state.lastViewportWidth != vw || d.state.lastViewportHeight != vh {
+		d.context.uniformFloats(program, "viewport_size", []float32{float32(vw), float32(vh)})
+		d.state.lastViewportWidth = vw
+		d.state.lastViewportHeight = vh
+	}
+
+	sw := graphics.InternalImageSize(source.width)
+	sh := graphics.InternalImageSize(source.height)
+	if d.state.lastSourceWidth != sw || d.state.lastSourceHeight != sh {
+		d.context.uniformFloats(program, "source_size", []float32{float32(sw), float32(sh)})
+		d.state.lastSourceWidth = sw
+		d.state.lastSourceHeight = sh
+	}
+
+	for i, u := range uniforms {
+		name := shader.program.GLSLUniformName(i)
+		if len(u) == 1 {
+			d.context.uniformFloat(program, name, u[0])
+			continue
+		}
+		d.context.uniformFloats(program, name, u)
+	}
+
+	d.context.bindTexture(source.textureNative)
+
+	d.state.source = nil
+	d.state.destination = nil
+	return nil
+}
@@ -16,10 +16,12 @@ package opengl
 
 import (
 	"fmt"
+	"image"
 
 	"github.com/hajimehoshi/ebiten/internal/affine"
 	"github.com/hajimehoshi/ebiten/internal/driver"
 	"github.com/hajimehoshi/ebiten/internal/graphics"
+	shaderprogram "github.com/hajimehoshi/ebiten/internal/shader"
 	"github.com/hajimehoshi/ebiten/internal/thread"
 )
 
@@ -35,6 +37,15 @@ type Driver struct {
 
 	// drawCalled is true just after Draw is called. This holds true until ReplacePixels is called.
 	drawCalled bool
+
+	// fences is the sync objects for the frames that the GPU might not finish yet.
+	fences []uintptr
+
+	// loader is the loader to upload pixels in background. loader is nil when the loader context is not set.
+	loader *loader
+
+	// indices16 is a buffer to convert indices to 16 bits when 32-bit indices are not available.
+	indices16 []uint16
 }
 
 func (d *Driver) SetThread(thread *thread.Thread) {
@@ -51,6 +62,11 @@ func (d *Driver) End() {
 	d.context.flush()
 }
 
+// Finish blocks until all the OpenGL commands are completed.
+func (d *Driver) Finish() {
+	d.context.finish()
+}
+
 func (d *Driver) SetTransparent(transparent bool) {
 	// Do nothings.
 }
@@ -88,6 +104,68 @@ func (d *Driver) NewImage(width, height int) (driver.Image, error) {
 	return i, nil
 }
 
+// NewMultisampledImage creates a new image that is rendered with multisample anti-aliasing.
+// If multisampling is not available, NewMultisampledImage creates a regular image.
+func (d *Driver) NewMultisampledImage(width, height, samples int) (driver.Image, error) {
+	img, err := d.NewImage(width, height)
+	if err != nil {
+		return nil, err
+	}
+	i := img.(*Image)
+	m, err := d.newMultisample(graphics.InternalImageSize(width), graphics.InternalImageSize(height), samples)
+	if err != nil {
+		i.Dispose()
+		return nil, err
+	}
+	i.multisample = m
+	return i, nil
+}
+
+// NewFloatImage creates a new image whose pixels have 16-bit floating-point values.
+// If floating-point textures are not available, NewFloatImage creates a regular image.
+func (d *Driver) NewFloatImage(width, height int) (driver.Image, error) {
+	if !d.context.hasFloatTextures() {
+		return d.NewImage(width, height)
+	}
+	i := &Image{
+		driver: d,
+		width:  width,
+		height: height,
+	}
+	w := graphics.InternalImageSize(width)
+	h := graphics.InternalImageSize(height)
+	d.checkSize(w, h)
+	t, err := d.context.newFloatTexture(w, h)
+	if err != nil {
+		return nil, err
+	}
+	i.textureNative = t
+	return i, nil
+}
+
+// NewAlphaImage creates a new image whose pixels have only 8-bit alpha values.
+// If alpha-only textures are not available, NewAlphaImage creates a regular image.
+func (d *Driver) NewAlphaImage(width, height int) (driver.Image, error) {
+	if !d.context.hasAlphaTextures() {
+		return d.NewImage(width, height)
+	}
+	i := &Image{
+		driver: d,
+		width:  width,
+		height: height,
+		alpha:  true,
+	}
+	w := graphics.InternalImageSize(width)
+	h := graphics.InternalImageSize(height)
+	d.checkSize(w, h)
+	t, err := d.context.newAlphaTexture(w, h)
+	if err != nil {
+		return nil, err
+	}
+	i.textureNative = t
+	return i, nil
+}
+
 func (d *Driver) NewScreenFramebufferImage(width, height int) (driver.Image, error) {
 	d.checkSize(width, height)
 	i := &Image{
@@ -101,23 +179,56 @@ func (d *Driver) NewScreenFramebufferImage(width, height int) (driver.Image, err
 
 // Reset resets or initializes the current OpenGL state.
 func (d *Driver) Reset() error {
+	// The sync objects for the frames belong to the lost context, if any.
+	d.fences = nil
 	return d.state.reset(&d.context)
 }
 
-func (d *Driver) SetVertices(vertices []float32, indices []uint16) {
+func (d *Driver) SetVertices(vertices []float32, indices []uint32) {
 	// Note that the vertices passed to BufferSubData is not under GC management
 	// in opengl package due to unsafe-way.
 	// See BufferSubData in context_mobile.go.
 	d.context.arrayBufferSubData(vertices)
-	d.context.elementArrayBufferSubData(indices)
+	if d.context.has32BitIndices() {
+		d.context.elementArrayBufferSubData(uint32sToBytes(indices))
+		return
+	}
+
+	// The indices are within 16 bits when 32-bit indices are not available.
+	if cap(d.indices16) < len(indices) {
+		d.indices16 = make([]uint16, len(indices))
+	}
+	d.indices16 = d.indices16[:len(indices)]
+	for i, idx := range indices {
+		d.indices16[i] = uint16(idx)
+	}
+	d.context.elementArrayBufferSubData(uint16sToBytes(d.indices16))
 }
 
-func (d *Driver) Draw(indexLen int, indexOffset int, mode driver.CompositeMode, colorM *affine.ColorM, filter driver.Filter, address driver.Address) error {
+func (d *Driver) Draw(indexLen int, indexOffset int, mode driver.CompositeMode, colorM *affine.ColorM, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) error {
 	d.drawCalled = true
-	if err := d.useProgram(mode, colorM, filter, address); err != nil {
+	if stencil != driver.StencilModeNone {
+		if err := d.state.destination.ensureStencilBuffer(); err != nil {
+			return err
+		}
+	}
+	if depth.Mode == driver.DepthModeTest {
+		if err := d.state.destination.ensureDepthBuffer(); err != nil {
+			return err
+		}
+	}
+	// Check the destination before useProgram, that resets the destination.
+	depthMode := depth.Mode
+	if depthMode == driver.DepthModeClear && !d.state.destination.hasDepthBuffer() {
+		depthMode = driver.DepthModeNone
+	}
+	if err := d.useProgram(mode, colorM, filter, address, depth); err != nil {
 		return err
 	}
-	d.context.drawElements(indexLen, indexOffset*2) // 2 is uint16 size in bytes
+	d.context.setStencilMode(stencil)
+	d.context.setDepthMode(depthMode)
+	d.context.setScissor(clip)
+	d.context.drawElements(indexLen, indexOffset*d.context.indexSize())
 	// glFlush() might be necessary at least on MacBook Pro (a smilar problem at #419),
 	// but basically this pass the tests (esp. TestImageTooManyFill).
 	// As glFlush() causes performance problems, this should be avoided as much as possible.
@@ -125,6 +236,26 @@ func (d *Driver) Draw(indexLen int, indexOffset int, mode driver.CompositeMode,
 	return nil
 }
 
+func (d *Driver) HasShaders() bool {
+	return true
+}
+
+func (d *Driver) NewShader(program *shaderprogram.Program) (driver.Shader, error) {
+	return d.newShader(program)
+}
+
+func (d *Driver) DrawShader(indexLen int, indexOffset int, shader driver.Shader, uniforms [][]float32, mode driver.CompositeMode) error {
+	d.drawCalled = true
+	if err := d.useShader(shader.(*Shader), uniforms, mode); err != nil {
+		return err
+	}
+	d.context.setStencilMode(driver.StencilModeNone)
+	d.context.setDepthMode(driver.DepthModeNone)
+	d.context.setScissor(image.Rectangle{})
+	d.context.drawElements(indexLen, indexOffset*d.context.indexSize())
+	return nil
+}
+
 func (d *Driver) SetVsyncEnabled(enabled bool) {
 	// Do nothing
 }
@@ -145,6 +276,30 @@ func (d *Driver) HasHighPrecisionFloat() bool {
 	return d.context.hasHighPrecisionFloat()
 }
 
+func (d *Driver) Has32BitIndices() bool {
+	return d.context.has32BitIndices()
+}
+
+func (d *Driver) HasFloatImages() bool {
+	return d.context.hasFloatTextures()
+}
+
+func (d *Driver) HasAlphaImages() bool {
+	return d.context.hasAlphaTextures()
+}
+
+// EnableSRGB enables the sRGB-correct pipeline if available, and reports whether the pipeline is enabled.
+//
+// With the pipeline, textures are created in sRGB and decoded into linear values when sampled. Blending is done in
+// linear space and the results are encoded into sRGB again. EnableSRGB must be called before any images are created.
+func (d *Driver) EnableSRGB() bool {
+	if !d.context.hasSRGB() {
+		return false
+	}
+	d.context.srgb = true
+	return true
+}
+
 func (d *Driver) MaxImageSize() int {
 	return d.context.getMaxTextureSize()
 }
@@ -14,12 +14,30 @@
 
 package opengl
 
+import (
+	"errors"
+
+	"github.com/hajimehoshi/ebiten/internal/driver"
+)
+
 // framebuffer is a wrapper of OpenGL's framebuffer.
 type framebuffer struct {
 	driver *Driver
 	native framebufferNative
 	width  int
 	height int
+
+	// samples is the number of samples of the color attachment. samples is 0 when the color attachment is not
+	// multisampled.
+	samples int
+
+	// stencil is the stencil buffer attached to the framebuffer. stencil is valid only when hasStencil is true.
+	stencil    renderbuffer
+	hasStencil bool
+
+	// depth is the depth buffer attached to the framebuffer. depth is valid only when hasDepth is true.
+	depth    renderbuffer
+	hasDepth bool
 }
 
 // newFramebufferFromTexture creates a framebuffer from the given texture.
@@ -44,7 +62,59 @@ func newScreenFramebuffer(context *context, width, height int) *framebuffer {
 	}
 }
 
+// ensureStencilBuffer attaches a stencil buffer to the framebuffer if it doesn't have one yet.
+func (f *framebuffer) ensureStencilBuffer(context *context) error {
+	if f.hasStencil {
+		return nil
+	}
+	if f.native.equal(context.getScreenFramebuffer()) {
+		return errors.New("opengl: stencil buffers are not available on the screen framebuffer")
+	}
+	r, err := context.newStencilBuffer(f.width, f.height, f.samples)
+	if err != nil {
+		return err
+	}
+	if err := context.attachStencilBuffer(f.native, r); err != nil {
+		context.deleteRenderbuffer(r)
+		return err
+	}
+	f.stencil = r
+	f.hasStencil = true
+	return nil
+}
+
+// ensureDepthBuffer attaches a depth buffer to the framebuffer if it doesn't have one yet.
+// A newly attached depth buffer is cleared.
+func (f *framebuffer) ensureDepthBuffer(context *context) error {
+	if f.hasDepth {
+		return nil
+	}
+	if f.native.equal(context.getScreenFramebuffer()) {
+		return errors.New("opengl: depth buffers are not available on the screen framebuffer")
+	}
+	r, err := context.newDepthBuffer(f.width, f.height, f.samples)
+	if err != nil {
+		return err
+	}
+	if err := context.attachDepthBuffer(f.native, r); err != nil {
+		context.deleteRenderbuffer(r)
+		return err
+	}
+	f.depth = r
+	f.hasDepth = true
+	context.setDepthMode(driver.DepthModeClear)
+	return nil
+}
+
 func (f *framebuffer) delete(context *context) {
+	if f.hasStencil {
+		context.deleteRenderbuffer(f.stencil)
+		f.hasStencil = false
+	}
+	if f.hasDepth {
+		context.deleteRenderbuffer(f.depth)
+		f.hasDepth = false
+	}
 	if !f.native.equal(context.getScreenFramebuffer()) {
 		context.deleteFramebuffer(f.native)
 	}
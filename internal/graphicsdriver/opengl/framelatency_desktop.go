@@ -0,0 +1,81 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin freebsd linux windows
+// +build !js
+// +build !android
+// +build !ios
+
+package opengl
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/driver"
+)
+
+// WaitForQueuedFrames inserts a fence for the current frame, and blocks until the number of the frames that the GPU
+// has not finished yet becomes max or less.
+//
+// If sync objects are not available, WaitForQueuedFrames waits for the GPU to finish all the commands when max is 1,
+// and does nothing otherwise.
+//
+// If max is 0 or less, WaitForQueuedFrames doesn't limit the frames.
+func (d *Driver) WaitForQueuedFrames(max int) {
+	if max <= 0 {
+		for _, f := range d.fences {
+			d.context.deleteSync(f)
+		}
+		d.fences = nil
+		return
+	}
+
+	if !d.context.isSyncSupported() {
+		if max == 1 {
+			d.context.finish()
+		}
+		return
+	}
+
+	d.fences = append(d.fences, d.context.fenceSync())
+	for len(d.fences) > max {
+		d.context.clientWaitSync(d.fences[0])
+		d.context.deleteSync(d.fences[0])
+		d.fences = d.fences[1:]
+	}
+}
+
+// fence is a fence with a sync object.
+type fence struct {
+	context *context
+	sync    uintptr
+}
+
+func (f *fence) IsSignaled() bool {
+	return f.context.isSyncSignaled(f.sync)
+}
+
+func (f *fence) Release() {
+	f.context.deleteSync(f.sync)
+}
+
+// NewFence inserts a sync object after the commands so far and returns the fence.
+// NewFence returns nil if sync objects are not available.
+func (d *Driver) NewFence() driver.Fence {
+	if !d.context.isSyncSupported() {
+		return nil
+	}
+	return &fence{
+		context: &d.context,
+		sync:    d.context.fenceSync(),
+	}
+}
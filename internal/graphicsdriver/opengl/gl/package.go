@@ -14,6 +14,14 @@
 // Package gl implements Go bindings to OpenGL.
 package gl
 
+const (
+	SYNC_GPU_COMMANDS_COMPLETE = 0x9117
+	SYNC_FLUSH_COMMANDS_BIT    = 0x00000001
+	ALREADY_SIGNALED           = 0x911A
+	CONDITION_SATISFIED        = 0x911C
+	WAIT_FAILED                = 0x911D
+)
+
 const (
 	VERTEX_SHADEDR       = 0x8B31
 	FRAGMENT_SHADER      = 0x8B30
@@ -32,23 +40,56 @@ const (
 	ONE_MINUS_SRC_ALPHA = 0x0303
 	ONE_MINUS_DST_ALPHA = 0x0305
 
+	FUNC_ADD              = 0x8006
+	FUNC_SUBTRACT         = 0x800A
+	FUNC_REVERSE_SUBTRACT = 0x800B
+	MIN                   = 0x8007
+	MAX                   = 0x8008
+
 	FALSE = 0
 	TRUE  = 1
 
+	ALWAYS               = 0x0207
 	BLEND                = 0x0BE2
 	CLAMP_TO_EDGE        = 0x812F
 	COLOR_ATTACHMENT0    = 0x8CE0
+	COLOR_BUFFER_BIT     = 0x4000
 	COMPILE_STATUS       = 0x8B81
+	DEPTH_ATTACHMENT     = 0x8D00
+	DEPTH_BUFFER_BIT     = 0x0100
+	DEPTH_COMPONENT16    = 0x81A5
+	DEPTH_TEST           = 0x0B71
+	DRAW_FRAMEBUFFER     = 0x8CA9
+	EQUAL                = 0x0202
+	EXTENSIONS           = 0x1F03
 	FRAMEBUFFER          = 0x8D40
 	FRAMEBUFFER_BINDING  = 0x8CA6
 	FRAMEBUFFER_COMPLETE = 0x8CD5
+	FRAMEBUFFER_SRGB     = 0x8DB9
 	INFO_LOG_LENGTH      = 0x8B84
+	KEEP                 = 0x1E00
+	LEQUAL               = 0x0203
 	LINK_STATUS          = 0x8B82
+	MAX_SAMPLES          = 0x8D57
 	MAX_TEXTURE_SIZE     = 0x0D33
 	NEAREST              = 0x2600
 	NO_ERROR             = 0
+	NOTEQUAL             = 0x0205
+	OUT_OF_MEMORY        = 0x0505
+	R8                   = 0x8229
+	READ_FRAMEBUFFER     = 0x8CA8
 	READ_WRITE           = 0x88BA
+	RENDERBUFFER         = 0x8D41
+	REPLACE              = 0x1E01
 	RGBA                 = 0x1908
+	RGBA8                = 0x8058
+	RGBA16F              = 0x881A
+	SCISSOR_TEST         = 0x0C11
+	SRGB8_ALPHA8         = 0x8C43
+	STENCIL_ATTACHMENT   = 0x8D20
+	STENCIL_BUFFER_BIT   = 0x0400
+	STENCIL_INDEX8       = 0x8D48
+	STENCIL_TEST         = 0x0B90
 	TEXTURE_2D           = 0x0DE1
 	TEXTURE_MAG_FILTER   = 0x2800
 	TEXTURE_MIN_FILTER   = 0x2801
@@ -57,6 +98,7 @@ const (
 	TRIANGLES            = 0x0004
 	UNPACK_ALIGNMENT     = 0x0CF5
 	UNSIGNED_BYTE        = 0x1401
+	UNSIGNED_INT         = 0x1405
 	UNSIGNED_SHORT       = 0x1403
 	VERTEX_SHADER        = 0x8B31
 	WRITE_ONLY           = 0x88B9
@@ -1,7 +1,6 @@
 // SPDX-License-Identifier: MIT
 
-// +build !js
-// +build !windows
+// +build !js,!windows
 
 package gl
 
@@ -100,27 +99,42 @@ package gl
 // typedef void  (APIENTRYP GPBINDATTRIBLOCATION)(GLuint  program, GLuint  index, const GLchar * name);
 // typedef void  (APIENTRYP GPBINDBUFFER)(GLenum  target, GLuint  buffer);
 // typedef void  (APIENTRYP GPBINDFRAMEBUFFEREXT)(GLenum  target, GLuint  framebuffer);
+// typedef void  (APIENTRYP GPBINDRENDERBUFFER)(GLenum  target, GLuint  renderbuffer);
 // typedef void  (APIENTRYP GPBINDTEXTURE)(GLenum  target, GLuint  texture);
+// typedef void  (APIENTRYP GPBLENDEQUATIONSEPARATE)(GLenum  modeRGB, GLenum  modeAlpha);
 // typedef void  (APIENTRYP GPBLENDFUNC)(GLenum  sfactor, GLenum  dfactor);
+// typedef void  (APIENTRYP GPBLITFRAMEBUFFER)(GLint  srcX0, GLint  srcY0, GLint  srcX1, GLint  srcY1, GLint  dstX0, GLint  dstY0, GLint  dstX1, GLint  dstY1, GLbitfield  mask, GLenum  filter);
 // typedef void  (APIENTRYP GPBUFFERDATA)(GLenum  target, GLsizeiptr  size, const void * data, GLenum  usage);
 // typedef void  (APIENTRYP GPBUFFERSUBDATA)(GLenum  target, GLintptr  offset, GLsizeiptr  size, const void * data);
 // typedef GLenum  (APIENTRYP GPCHECKFRAMEBUFFERSTATUSEXT)(GLenum  target);
+// typedef void  (APIENTRYP GPCLEAR)(GLbitfield  mask);
+// typedef void  (APIENTRYP GPCOLORMASK)(GLboolean  red, GLboolean  green, GLboolean  blue, GLboolean  alpha);
 // typedef void  (APIENTRYP GPCOMPILESHADER)(GLuint  shader);
 // typedef GLuint  (APIENTRYP GPCREATEPROGRAM)();
 // typedef GLuint  (APIENTRYP GPCREATESHADER)(GLenum  type);
 // typedef void  (APIENTRYP GPDELETEBUFFERS)(GLsizei  n, const GLuint * buffers);
 // typedef void  (APIENTRYP GPDELETEFRAMEBUFFERSEXT)(GLsizei  n, const GLuint * framebuffers);
 // typedef void  (APIENTRYP GPDELETEPROGRAM)(GLuint  program);
+// typedef void  (APIENTRYP GPDELETERENDERBUFFERS)(GLsizei  n, const GLuint * renderbuffers);
 // typedef void  (APIENTRYP GPDELETESHADER)(GLuint  shader);
+// typedef GLenum  (APIENTRYP GPCLIENTWAITSYNC)(GLsync  sync, GLbitfield  flags, GLuint64  timeout);
+// typedef void  (APIENTRYP GPDELETESYNC)(GLsync  sync);
+// typedef GLsync  (APIENTRYP GPFENCESYNC)(GLenum  condition, GLbitfield  flags);
 // typedef void  (APIENTRYP GPDELETETEXTURES)(GLsizei  n, const GLuint * textures);
+// typedef void  (APIENTRYP GPDEPTHFUNC)(GLenum  func);
+// typedef void  (APIENTRYP GPDEPTHMASK)(GLboolean  flag);
+// typedef void  (APIENTRYP GPDISABLE)(GLenum  cap);
 // typedef void  (APIENTRYP GPDISABLEVERTEXATTRIBARRAY)(GLuint  index);
 // typedef void  (APIENTRYP GPDRAWELEMENTS)(GLenum  mode, GLsizei  count, GLenum  type, const uintptr_t indices);
 // typedef void  (APIENTRYP GPENABLE)(GLenum  cap);
 // typedef void  (APIENTRYP GPENABLEVERTEXATTRIBARRAY)(GLuint  index);
+// typedef void  (APIENTRYP GPFINISH)();
 // typedef void  (APIENTRYP GPFLUSH)();
+// typedef void  (APIENTRYP GPFRAMEBUFFERRENDERBUFFER)(GLenum  target, GLenum  attachment, GLenum  renderbuffertarget, GLuint  renderbuffer);
 // typedef void  (APIENTRYP GPFRAMEBUFFERTEXTURE2DEXT)(GLenum  target, GLenum  attachment, GLenum  textarget, GLuint  texture, GLint  level);
 // typedef void  (APIENTRYP GPGENBUFFERS)(GLsizei  n, GLuint * buffers);
 // typedef void  (APIENTRYP GPGENFRAMEBUFFERSEXT)(GLsizei  n, GLuint * framebuffers);
+// typedef void  (APIENTRYP GPGENRENDERBUFFERS)(GLsizei  n, GLuint * renderbuffers);
 // typedef void  (APIENTRYP GPGENTEXTURES)(GLsizei  n, GLuint * textures);
 // typedef void  (APIENTRYP GPGETDOUBLEI_V)(GLenum  target, GLuint  index, GLdouble * data);
 // typedef void  (APIENTRYP GPGETDOUBLEI_VEXT)(GLenum  pname, GLuint  index, GLdouble * params);
@@ -134,6 +148,7 @@ package gl
 // typedef void  (APIENTRYP GPGETPROGRAMIV)(GLuint  program, GLenum  pname, GLint * params);
 // typedef void  (APIENTRYP GPGETSHADERINFOLOG)(GLuint  shader, GLsizei  bufSize, GLsizei * length, GLchar * infoLog);
 // typedef void  (APIENTRYP GPGETSHADERIV)(GLuint  shader, GLenum  pname, GLint * params);
+// typedef const GLubyte * (APIENTRYP GPGETSTRING)(GLenum  name);
 // typedef void  (APIENTRYP GPGETTRANSFORMFEEDBACKI64_V)(GLuint  xfb, GLenum  pname, GLuint  index, GLint64 * param);
 // typedef void  (APIENTRYP GPGETTRANSFORMFEEDBACKI_V)(GLuint  xfb, GLenum  pname, GLuint  index, GLint * param);
 // typedef GLint  (APIENTRYP GPGETUNIFORMLOCATION)(GLuint  program, const GLchar * name);
@@ -146,13 +161,18 @@ package gl
 // typedef void  (APIENTRYP GPLINKPROGRAM)(GLuint  program);
 // typedef void  (APIENTRYP GPPIXELSTOREI)(GLenum  pname, GLint  param);
 // typedef void  (APIENTRYP GPREADPIXELS)(GLint  x, GLint  y, GLsizei  width, GLsizei  height, GLenum  format, GLenum  type, void * pixels);
+// typedef void  (APIENTRYP GPRENDERBUFFERSTORAGEMULTISAMPLE)(GLenum  target, GLsizei  samples, GLenum  internalformat, GLsizei  width, GLsizei  height);
+// typedef void  (APIENTRYP GPSCISSOR)(GLint  x, GLint  y, GLsizei  width, GLsizei  height);
 // typedef void  (APIENTRYP GPSHADERSOURCE)(GLuint  shader, GLsizei  count, const GLchar *const* string, const GLint * length);
+// typedef void  (APIENTRYP GPSTENCILFUNC)(GLenum  func, GLint  ref, GLuint  mask);
+// typedef void  (APIENTRYP GPSTENCILOP)(GLenum  fail, GLenum  zfail, GLenum  zpass);
 // typedef void  (APIENTRYP GPTEXIMAGE2D)(GLenum  target, GLint  level, GLint  internalformat, GLsizei  width, GLsizei  height, GLint  border, GLenum  format, GLenum  type, const void * pixels);
 // typedef void  (APIENTRYP GPTEXPARAMETERI)(GLenum  target, GLenum  pname, GLint  param);
 // typedef void  (APIENTRYP GPTEXSUBIMAGE2D)(GLenum  target, GLint  level, GLint  xoffset, GLint  yoffset, GLsizei  width, GLsizei  height, GLenum  format, GLenum  type, const void * pixels);
 // typedef void  (APIENTRYP GPUNIFORM1F)(GLint  location, GLfloat  v0);
 // typedef void  (APIENTRYP GPUNIFORM1I)(GLint  location, GLint  v0);
 // typedef void  (APIENTRYP GPUNIFORM2FV)(GLint  location, GLsizei  count, const GLfloat * value);
+// typedef void  (APIENTRYP GPUNIFORM3FV)(GLint  location, GLsizei  count, const GLfloat * value);
 // typedef void  (APIENTRYP GPUNIFORM4FV)(GLint  location, GLsizei  count, const GLfloat * value);
 // typedef void  (APIENTRYP GPUNIFORMMATRIX4FV)(GLint  location, GLsizei  count, GLboolean  transpose, const GLfloat * value);
 // typedef void  (APIENTRYP GPUSEPROGRAM)(GLuint  program);
@@ -171,9 +191,15 @@ package gl
 // static void  glowBindFramebufferEXT(GPBINDFRAMEBUFFEREXT fnptr, GLenum  target, GLuint  framebuffer) {
 //   (*fnptr)(target, framebuffer);
 // }
+// static void  glowBindRenderbuffer(GPBINDRENDERBUFFER fnptr, GLenum  target, GLuint  renderbuffer) {
+//   (*fnptr)(target, renderbuffer);
+// }
 // static void  glowBindTexture(GPBINDTEXTURE fnptr, GLenum  target, GLuint  texture) {
 //   (*fnptr)(target, texture);
 // }
+// static void  glowBlendEquationSeparate(GPBLENDEQUATIONSEPARATE fnptr, GLenum  modeRGB, GLenum  modeAlpha) {
+//   (*fnptr)(modeRGB, modeAlpha);
+// }
 // static void  glowBlendFunc(GPBLENDFUNC fnptr, GLenum  sfactor, GLenum  dfactor) {
 //   (*fnptr)(sfactor, dfactor);
 // }
@@ -186,6 +212,12 @@ package gl
 // static GLenum  glowCheckFramebufferStatusEXT(GPCHECKFRAMEBUFFERSTATUSEXT fnptr, GLenum  target) {
 //   return (*fnptr)(target);
 // }
+// static void  glowClear(GPCLEAR fnptr, GLbitfield  mask) {
+//   (*fnptr)(mask);
+// }
+// static void  glowColorMask(GPCOLORMASK fnptr, GLboolean  red, GLboolean  green, GLboolean  blue, GLboolean  alpha) {
+//   (*fnptr)(red, green, blue, alpha);
+// }
 // static void  glowCompileShader(GPCOMPILESHADER fnptr, GLuint  shader) {
 //   (*fnptr)(shader);
 // }
@@ -201,15 +233,36 @@ package gl
 // static void  glowDeleteFramebuffersEXT(GPDELETEFRAMEBUFFERSEXT fnptr, GLsizei  n, const GLuint * framebuffers) {
 //   (*fnptr)(n, framebuffers);
 // }
+// static void  glowDeleteRenderbuffers(GPDELETERENDERBUFFERS fnptr, GLsizei  n, const GLuint * renderbuffers) {
+//   (*fnptr)(n, renderbuffers);
+// }
 // static void  glowDeleteProgram(GPDELETEPROGRAM fnptr, GLuint  program) {
 //   (*fnptr)(program);
 // }
 // static void  glowDeleteShader(GPDELETESHADER fnptr, GLuint  shader) {
 //   (*fnptr)(shader);
 // }
+// static GLenum  glowClientWaitSync(GPCLIENTWAITSYNC fnptr, GLsync  sync, GLbitfield  flags, GLuint64  timeout) {
+//   return (*fnptr)(sync, flags, timeout);
+// }
+// static void  glowDeleteSync(GPDELETESYNC fnptr, GLsync  sync) {
+//   (*fnptr)(sync);
+// }
+// static GLsync  glowFenceSync(GPFENCESYNC fnptr, GLenum  condition, GLbitfield  flags) {
+//   return (*fnptr)(condition, flags);
+// }
 // static void  glowDeleteTextures(GPDELETETEXTURES fnptr, GLsizei  n, const GLuint * textures) {
 //   (*fnptr)(n, textures);
 // }
+// static void  glowDepthFunc(GPDEPTHFUNC fnptr, GLenum  func) {
+//   (*fnptr)(func);
+// }
+// static void  glowDepthMask(GPDEPTHMASK fnptr, GLboolean  flag) {
+//   (*fnptr)(flag);
+// }
+// static void  glowDisable(GPDISABLE fnptr, GLenum  cap) {
+//   (*fnptr)(cap);
+// }
 // static void  glowDisableVertexAttribArray(GPDISABLEVERTEXATTRIBARRAY fnptr, GLuint  index) {
 //   (*fnptr)(index);
 // }
@@ -222,9 +275,15 @@ package gl
 // static void  glowEnableVertexAttribArray(GPENABLEVERTEXATTRIBARRAY fnptr, GLuint  index) {
 //   (*fnptr)(index);
 // }
+// static void  glowFinish(GPFINISH fnptr) {
+//   (*fnptr)();
+// }
 // static void  glowFlush(GPFLUSH fnptr) {
 //   (*fnptr)();
 // }
+// static void  glowFramebufferRenderbuffer(GPFRAMEBUFFERRENDERBUFFER fnptr, GLenum  target, GLenum  attachment, GLenum  renderbuffertarget, GLuint  renderbuffer) {
+//   (*fnptr)(target, attachment, renderbuffertarget, renderbuffer);
+// }
 // static void  glowFramebufferTexture2DEXT(GPFRAMEBUFFERTEXTURE2DEXT fnptr, GLenum  target, GLenum  attachment, GLenum  textarget, GLuint  texture, GLint  level) {
 //   (*fnptr)(target, attachment, textarget, texture, level);
 // }
@@ -234,6 +293,9 @@ package gl
 // static void  glowGenFramebuffersEXT(GPGENFRAMEBUFFERSEXT fnptr, GLsizei  n, GLuint * framebuffers) {
 //   (*fnptr)(n, framebuffers);
 // }
+// static void  glowGenRenderbuffers(GPGENRENDERBUFFERS fnptr, GLsizei  n, GLuint * renderbuffers) {
+//   (*fnptr)(n, renderbuffers);
+// }
 // static void  glowGenTextures(GPGENTEXTURES fnptr, GLsizei  n, GLuint * textures) {
 //   (*fnptr)(n, textures);
 // }
@@ -273,6 +335,9 @@ package gl
 // static void  glowGetShaderiv(GPGETSHADERIV fnptr, GLuint  shader, GLenum  pname, GLint * params) {
 //   (*fnptr)(shader, pname, params);
 // }
+// static const GLubyte * glowGetString(GPGETSTRING fnptr, GLenum  name) {
+//   return (*fnptr)(name);
+// }
 // static void  glowGetTransformFeedbacki64_v(GPGETTRANSFORMFEEDBACKI64_V fnptr, GLuint  xfb, GLenum  pname, GLuint  index, GLint64 * param) {
 //   (*fnptr)(xfb, pname, index, param);
 // }
@@ -306,12 +371,27 @@ package gl
 // static void  glowPixelStorei(GPPIXELSTOREI fnptr, GLenum  pname, GLint  param) {
 //   (*fnptr)(pname, param);
 // }
+// static void  glowBlitFramebuffer(GPBLITFRAMEBUFFER fnptr, GLint  srcX0, GLint  srcY0, GLint  srcX1, GLint  srcY1, GLint  dstX0, GLint  dstY0, GLint  dstX1, GLint  dstY1, GLbitfield  mask, GLenum  filter) {
+//   (*fnptr)(srcX0, srcY0, srcX1, srcY1, dstX0, dstY0, dstX1, dstY1, mask, filter);
+// }
+// static void  glowRenderbufferStorageMultisample(GPRENDERBUFFERSTORAGEMULTISAMPLE fnptr, GLenum  target, GLsizei  samples, GLenum  internalformat, GLsizei  width, GLsizei  height) {
+//   (*fnptr)(target, samples, internalformat, width, height);
+// }
 // static void  glowReadPixels(GPREADPIXELS fnptr, GLint  x, GLint  y, GLsizei  width, GLsizei  height, GLenum  format, GLenum  type, void * pixels) {
 //   (*fnptr)(x, y, width, height, format, type, pixels);
 // }
+// static void  glowScissor(GPSCISSOR fnptr, GLint  x, GLint  y, GLsizei  width, GLsizei  height) {
+//   (*fnptr)(x, y, width, height);
+// }
 // static void  glowShaderSource(GPSHADERSOURCE fnptr, GLuint  shader, GLsizei  count, const GLchar *const* string, const GLint * length) {
 //   (*fnptr)(shader, count, string, length);
 // }
+// static void  glowStencilFunc(GPSTENCILFUNC fnptr, GLenum  func, GLint  ref, GLuint  mask) {
+//   (*fnptr)(func, ref, mask);
+// }
+// static void  glowStencilOp(GPSTENCILOP fnptr, GLenum  fail, GLenum  zfail, GLenum  zpass) {
+//   (*fnptr)(fail, zfail, zpass);
+// }
 // static void  glowTexImage2D(GPTEXIMAGE2D fnptr, GLenum  target, GLint  level, GLint  internalformat, GLsizei  width, GLsizei  height, GLint  border, GLenum  format, GLenum  type, const void * pixels) {
 //   (*fnptr)(target, level, internalformat, width, height, border, format, type, pixels);
 // }
@@ -330,6 +410,9 @@ package gl
 // static void  glowUniform2fv(GPUNIFORM2FV fnptr, GLint  location, GLsizei  count, const GLfloat * value) {
 //   (*fnptr)(location, count, value);
 // }
+// static void  glowUniform3fv(GPUNIFORM3FV fnptr, GLint  location, GLsizei  count, const GLfloat * value) {
+//   (*fnptr)(location, count, value);
+// }
 // static void  glowUniform4fv(GPUNIFORM4FV fnptr, GLint  location, GLsizei  count, const GLfloat * value) {
 //   (*fnptr)(location, count, value);
 // }
@@ -353,68 +436,89 @@ import (
 )
 
 var (
-	gpAttachShader                C.GPATTACHSHADER
-	gpBindAttribLocation          C.GPBINDATTRIBLOCATION
-	gpBindBuffer                  C.GPBINDBUFFER
-	gpBindFramebufferEXT          C.GPBINDFRAMEBUFFEREXT
-	gpBindTexture                 C.GPBINDTEXTURE
-	gpBlendFunc                   C.GPBLENDFUNC
-	gpBufferData                  C.GPBUFFERDATA
-	gpBufferSubData               C.GPBUFFERSUBDATA
-	gpCheckFramebufferStatusEXT   C.GPCHECKFRAMEBUFFERSTATUSEXT
-	gpCompileShader               C.GPCOMPILESHADER
-	gpCreateProgram               C.GPCREATEPROGRAM
-	gpCreateShader                C.GPCREATESHADER
-	gpDeleteBuffers               C.GPDELETEBUFFERS
-	gpDeleteFramebuffersEXT       C.GPDELETEFRAMEBUFFERSEXT
-	gpDeleteProgram               C.GPDELETEPROGRAM
-	gpDeleteShader                C.GPDELETESHADER
-	gpDeleteTextures              C.GPDELETETEXTURES
-	gpDisableVertexAttribArray    C.GPDISABLEVERTEXATTRIBARRAY
-	gpDrawElements                C.GPDRAWELEMENTS
-	gpEnable                      C.GPENABLE
-	gpEnableVertexAttribArray     C.GPENABLEVERTEXATTRIBARRAY
-	gpFlush                       C.GPFLUSH
-	gpFramebufferTexture2DEXT     C.GPFRAMEBUFFERTEXTURE2DEXT
-	gpGenBuffers                  C.GPGENBUFFERS
-	gpGenFramebuffersEXT          C.GPGENFRAMEBUFFERSEXT
-	gpGenTextures                 C.GPGENTEXTURES
-	gpGetDoublei_v                C.GPGETDOUBLEI_V
-	gpGetDoublei_vEXT             C.GPGETDOUBLEI_VEXT
-	gpGetError                    C.GPGETERROR
-	gpGetFloati_v                 C.GPGETFLOATI_V
-	gpGetFloati_vEXT              C.GPGETFLOATI_VEXT
-	gpGetIntegeri_v               C.GPGETINTEGERI_V
-	gpGetIntegerui64i_vNV         C.GPGETINTEGERUI64I_VNV
-	gpGetIntegerv                 C.GPGETINTEGERV
-	gpGetPointeri_vEXT            C.GPGETPOINTERI_VEXT
-	gpGetProgramiv                C.GPGETPROGRAMIV
-	gpGetShaderInfoLog            C.GPGETSHADERINFOLOG
-	gpGetShaderiv                 C.GPGETSHADERIV
-	gpGetTransformFeedbacki64_v   C.GPGETTRANSFORMFEEDBACKI64_V
-	gpGetTransformFeedbacki_v     C.GPGETTRANSFORMFEEDBACKI_V
-	gpGetUniformLocation          C.GPGETUNIFORMLOCATION
-	gpGetUnsignedBytei_vEXT       C.GPGETUNSIGNEDBYTEI_VEXT
-	gpGetVertexArrayIntegeri_vEXT C.GPGETVERTEXARRAYINTEGERI_VEXT
-	gpGetVertexArrayPointeri_vEXT C.GPGETVERTEXARRAYPOINTERI_VEXT
-	gpIsFramebufferEXT            C.GPISFRAMEBUFFEREXT
-	gpIsProgram                   C.GPISPROGRAM
-	gpIsTexture                   C.GPISTEXTURE
-	gpLinkProgram                 C.GPLINKPROGRAM
-	gpPixelStorei                 C.GPPIXELSTOREI
-	gpReadPixels                  C.GPREADPIXELS
-	gpShaderSource                C.GPSHADERSOURCE
-	gpTexImage2D                  C.GPTEXIMAGE2D
-	gpTexParameteri               C.GPTEXPARAMETERI
-	gpTexSubImage2D               C.GPTEXSUBIMAGE2D
-	gpUniform1f                   C.GPUNIFORM1F
-	gpUniform1i                   C.GPUNIFORM1I
-	gpUniform2fv                  C.GPUNIFORM2FV
-	gpUniform4fv                  C.GPUNIFORM4FV
-	gpUniformMatrix4fv            C.GPUNIFORMMATRIX4FV
-	gpUseProgram                  C.GPUSEPROGRAM
-	gpVertexAttribPointer         C.GPVERTEXATTRIBPOINTER
-	gpViewport                    C.GPVIEWPORT
+	gpAttachShader                   C.GPATTACHSHADER
+	gpBindAttribLocation             C.GPBINDATTRIBLOCATION
+	gpBindBuffer                     C.GPBINDBUFFER
+	gpBindFramebufferEXT             C.GPBINDFRAMEBUFFEREXT
+	gpBindRenderbuffer               C.GPBINDRENDERBUFFER
+	gpBindTexture                    C.GPBINDTEXTURE
+	gpBlendEquationSeparate          C.GPBLENDEQUATIONSEPARATE
+	gpBlendFunc                      C.GPBLENDFUNC
+	gpBlitFramebuffer                C.GPBLITFRAMEBUFFER
+	gpBufferData                     C.GPBUFFERDATA
+	gpBufferSubData                  C.GPBUFFERSUBDATA
+	gpCheckFramebufferStatusEXT      C.GPCHECKFRAMEBUFFERSTATUSEXT
+	gpClear                          C.GPCLEAR
+	gpColorMask                      C.GPCOLORMASK
+	gpCompileShader                  C.GPCOMPILESHADER
+	gpCreateProgram                  C.GPCREATEPROGRAM
+	gpCreateShader                   C.GPCREATESHADER
+	gpDeleteBuffers                  C.GPDELETEBUFFERS
+	gpDeleteFramebuffersEXT          C.GPDELETEFRAMEBUFFERSEXT
+	gpDeleteProgram                  C.GPDELETEPROGRAM
+	gpDeleteRenderbuffers            C.GPDELETERENDERBUFFERS
+	gpDeleteShader                   C.GPDELETESHADER
+	gpClientWaitSync                 C.GPCLIENTWAITSYNC
+	gpDeleteSync                     C.GPDELETESYNC
+	gpFenceSync                      C.GPFENCESYNC
+	gpDeleteTextures                 C.GPDELETETEXTURES
+	gpDepthFunc                      C.GPDEPTHFUNC
+	gpDepthMask                      C.GPDEPTHMASK
+	gpDisable                        C.GPDISABLE
+	gpDisableVertexAttribArray       C.GPDISABLEVERTEXATTRIBARRAY
+	gpDrawElements                   C.GPDRAWELEMENTS
+	gpEnable                         C.GPENABLE
+	gpEnableVertexAttribArray        C.GPENABLEVERTEXATTRIBARRAY
+	gpFinish                         C.GPFINISH
+	gpFlush                          C.GPFLUSH
+	gpFramebufferRenderbuffer        C.GPFRAMEBUFFERRENDERBUFFER
+	gpFramebufferTexture2DEXT        C.GPFRAMEBUFFERTEXTURE2DEXT
+	gpGenBuffers                     C.GPGENBUFFERS
+	gpGenFramebuffersEXT             C.GPGENFRAMEBUFFERSEXT
+	gpGenRenderbuffers               C.GPGENRENDERBUFFERS
+	gpGenTextures                    C.GPGENTEXTURES
+	gpGetDoublei_v                   C.GPGETDOUBLEI_V
+	gpGetDoublei_vEXT                C.GPGETDOUBLEI_VEXT
+	gpGetError                       C.GPGETERROR
+	gpGetFloati_v                    C.GPGETFLOATI_V
+	gpGetFloati_vEXT                 C.GPGETFLOATI_VEXT
+	gpGetIntegeri_v                  C.GPGETINTEGERI_V
+	gpGetIntegerui64i_vNV            C.GPGETINTEGERUI64I_VNV
+	gpGetIntegerv                    C.GPGETINTEGERV
+	gpGetPointeri_vEXT               C.GPGETPOINTERI_VEXT
+	gpGetProgramiv                   C.GPGETPROGRAMIV
+	gpGetShaderInfoLog               C.GPGETSHADERINFOLOG
+	gpGetShaderiv                    C.GPGETSHADERIV
+	gpGetString                      C.GPGETSTRING
+	gpGetTransformFeedbacki64_v      C.GPGETTRANSFORMFEEDBACKI64_V
+	gpGetTransformFeedbacki_v        C.GPGETTRANSFORMFEEDBACKI_V
+	gpGetUniformLocation             C.GPGETUNIFORMLOCATION
+	gpGetUnsignedBytei_vEXT          C.GPGETUNSIGNEDBYTEI_VEXT
+	gpGetVertexArrayIntegeri_vEXT    C.GPGETVERTEXARRAYINTEGERI_VEXT
+	gpGetVertexArrayPointeri_vEXT    C.GPGETVERTEXARRAYPOINTERI_VEXT
+	gpIsFramebufferEXT               C.GPISFRAMEBUFFEREXT
+	gpIsProgram                      C.GPISPROGRAM
+	gpIsTexture                      C.GPISTEXTURE
+	gpLinkProgram                    C.GPLINKPROGRAM
+	gpPixelStorei                    C.GPPIXELSTOREI
+	gpReadPixels                     C.GPREADPIXELS
+	gpRenderbufferStorageMultisample C.GPRENDERBUFFERSTORAGEMULTISAMPLE
+	gpScissor                        C.GPSCISSOR
+	gpShaderSource                   C.GPSHADERSOURCE
+	gpStencilFunc                    C.GPSTENCILFUNC
+	gpStencilOp                      C.GPSTENCILOP
+	gpTexImage2D                     C.GPTEXIMAGE2D
+	gpTexParameteri                  C.GPTEXPARAMETERI
+	gpTexSubImage2D                  C.GPTEXSUBIMAGE2D
+	gpUniform1f                      C.GPUNIFORM1F
+	gpUniform1i                      C.GPUNIFORM1I
+	gpUniform2fv                     C.GPUNIFORM2FV
+	gpUniform3fv                     C.GPUNIFORM3FV
+	gpUniform4fv                     C.GPUNIFORM4FV
+	gpUniformMatrix4fv               C.GPUNIFORMMATRIX4FV
+	gpUseProgram                     C.GPUSEPROGRAM
+	gpVertexAttribPointer            C.GPVERTEXATTRIBPOINTER
+	gpViewport                       C.GPVIEWPORT
 )
 
 func boolToInt(b bool) int {
@@ -440,14 +544,26 @@ func BindFramebufferEXT(target uint32, framebuffer uint32) {
 	C.glowBindFramebufferEXT(gpBindFramebufferEXT, (C.GLenum)(target), (C.GLuint)(framebuffer))
 }
 
+func BindRenderbuffer(target uint32, renderbuffer uint32) {
+	C.glowBindRenderbuffer(gpBindRenderbuffer, (C.GLenum)(target), (C.GLuint)(renderbuffer))
+}
+
 func BindTexture(target uint32, texture uint32) {
 	C.glowBindTexture(gpBindTexture, (C.GLenum)(target), (C.GLuint)(texture))
 }
 
+func BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
+	C.glowBlendEquationSeparate(gpBlendEquationSeparate, (C.GLenum)(modeRGB), (C.GLenum)(modeAlpha))
+}
+
 func BlendFunc(sfactor uint32, dfactor uint32) {
 	C.glowBlendFunc(gpBlendFunc, (C.GLenum)(sfactor), (C.GLenum)(dfactor))
 }
 
+func BlitFramebuffer(srcX0 int32, srcY0 int32, srcX1 int32, srcY1 int32, dstX0 int32, dstY0 int32, dstX1 int32, dstY1 int32, mask uint32, filter uint32) {
+	C.glowBlitFramebuffer(gpBlitFramebuffer, (C.GLint)(srcX0), (C.GLint)(srcY0), (C.GLint)(srcX1), (C.GLint)(srcY1), (C.GLint)(dstX0), (C.GLint)(dstY0), (C.GLint)(dstX1), (C.GLint)(dstY1), (C.GLbitfield)(mask), (C.GLenum)(filter))
+}
+
 func BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
 	C.glowBufferData(gpBufferData, (C.GLenum)(target), (C.GLsizeiptr)(size), data, (C.GLenum)(usage))
 }
@@ -461,6 +577,14 @@ func CheckFramebufferStatusEXT(target uint32) uint32 {
 	return (uint32)(ret)
 }
 
+func Clear(mask uint32) {
+	C.glowClear(gpClear, (C.GLbitfield)(mask))
+}
+
+func ColorMask(red bool, green bool, blue bool, alpha bool) {
+	C.glowColorMask(gpColorMask, (C.GLboolean)(boolToInt(red)), (C.GLboolean)(boolToInt(green)), (C.GLboolean)(boolToInt(blue)), (C.GLboolean)(boolToInt(alpha)))
+}
+
 func CompileShader(shader uint32) {
 	C.glowCompileShader(gpCompileShader, (C.GLuint)(shader))
 }
@@ -487,14 +611,55 @@ func DeleteProgram(program uint32) {
 	C.glowDeleteProgram(gpDeleteProgram, (C.GLuint)(program))
 }
 
+func DeleteRenderbuffers(n int32, renderbuffers *uint32) {
+	C.glowDeleteRenderbuffers(gpDeleteRenderbuffers, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(renderbuffers)))
+}
+
 func DeleteShader(shader uint32) {
 	C.glowDeleteShader(gpDeleteShader, (C.GLuint)(shader))
 }
 
+// IsMultisampleSupported reports whether the multisampled renderbuffer functions are available.
+func IsMultisampleSupported() bool {
+	return gpGenRenderbuffers != nil && gpBindRenderbuffer != nil && gpDeleteRenderbuffers != nil &&
+		gpRenderbufferStorageMultisample != nil && gpFramebufferRenderbuffer != nil && gpBlitFramebuffer != nil
+}
+
+// IsSyncSupported reports whether the sync object functions are available.
+func IsSyncSupported() bool {
+	return gpFenceSync != nil && gpClientWaitSync != nil && gpDeleteSync != nil
+}
+
+func ClientWaitSync(sync uintptr, flags uint32, timeout uint64) uint32 {
+	ret := C.glowClientWaitSync(gpClientWaitSync, (C.GLsync)(sync), (C.GLbitfield)(flags), (C.GLuint64)(timeout))
+	return uint32(ret)
+}
+
+func DeleteSync(sync uintptr) {
+	C.glowDeleteSync(gpDeleteSync, (C.GLsync)(sync))
+}
+
+func FenceSync(condition uint32, flags uint32) uintptr {
+	ret := C.glowFenceSync(gpFenceSync, (C.GLenum)(condition), (C.GLbitfield)(flags))
+	return uintptr(ret)
+}
+
 func DeleteTextures(n int32, textures *uint32) {
 	C.glowDeleteTextures(gpDeleteTextures, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(textures)))
 }
 
+func DepthFunc(xfunc uint32) {
+	C.glowDepthFunc(gpDepthFunc, (C.GLenum)(xfunc))
+}
+
+func DepthMask(flag bool) {
+	C.glowDepthMask(gpDepthMask, (C.GLboolean)(boolToInt(flag)))
+}
+
+func Disable(cap uint32) {
+	C.glowDisable(gpDisable, (C.GLenum)(cap))
+}
+
 func DisableVertexAttribArray(index uint32) {
 	C.glowDisableVertexAttribArray(gpDisableVertexAttribArray, (C.GLuint)(index))
 }
@@ -511,10 +676,18 @@ func EnableVertexAttribArray(index uint32) {
 	C.glowEnableVertexAttribArray(gpEnableVertexAttribArray, (C.GLuint)(index))
 }
 
+func Finish() {
+	C.glowFinish(gpFinish)
+}
+
 func Flush() {
 	C.glowFlush(gpFlush)
 }
 
+func FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32) {
+	C.glowFramebufferRenderbuffer(gpFramebufferRenderbuffer, (C.GLenum)(target), (C.GLenum)(attachment), (C.GLenum)(renderbuffertarget), (C.GLuint)(renderbuffer))
+}
+
 func FramebufferTexture2DEXT(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
 	C.glowFramebufferTexture2DEXT(gpFramebufferTexture2DEXT, (C.GLenum)(target), (C.GLenum)(attachment), (C.GLenum)(textarget), (C.GLuint)(texture), (C.GLint)(level))
 }
@@ -527,6 +700,10 @@ func GenFramebuffersEXT(n int32, framebuffers *uint32) {
 	C.glowGenFramebuffersEXT(gpGenFramebuffersEXT, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(framebuffers)))
 }
 
+func GenRenderbuffers(n int32, renderbuffers *uint32) {
+	C.glowGenRenderbuffers(gpGenRenderbuffers, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(renderbuffers)))
+}
+
 func GenTextures(n int32, textures *uint32) {
 	C.glowGenTextures(gpGenTextures, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(textures)))
 }
@@ -575,6 +752,11 @@ func GetShaderiv(shader uint32, pname uint32, params *int32) {
 	C.glowGetShaderiv(gpGetShaderiv, (C.GLuint)(shader), (C.GLenum)(pname), (*C.GLint)(unsafe.Pointer(params)))
 }
 
+func GetString(name uint32) *uint8 {
+	ret := C.glowGetString(gpGetString, (C.GLenum)(name))
+	return (*uint8)(ret)
+}
+
 func GetTransformFeedbacki64_v(xfb uint32, pname uint32, index uint32, param *int64) {
 	C.glowGetTransformFeedbacki64_v(gpGetTransformFeedbacki64_v, (C.GLuint)(xfb), (C.GLenum)(pname), (C.GLuint)(index), (*C.GLint64)(unsafe.Pointer(param)))
 }
@@ -624,10 +806,26 @@ func ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtyp
 	C.glowReadPixels(gpReadPixels, (C.GLint)(x), (C.GLint)(y), (C.GLsizei)(width), (C.GLsizei)(height), (C.GLenum)(format), (C.GLenum)(xtype), pixels)
 }
 
+func RenderbufferStorageMultisample(target uint32, samples int32, internalformat uint32, width int32, height int32) {
+	C.glowRenderbufferStorageMultisample(gpRenderbufferStorageMultisample, (C.GLenum)(target), (C.GLsizei)(samples), (C.GLenum)(internalformat), (C.GLsizei)(width), (C.GLsizei)(height))
+}
+
+func Scissor(x int32, y int32, width int32, height int32) {
+	C.glowScissor(gpScissor, (C.GLint)(x), (C.GLint)(y), (C.GLsizei)(width), (C.GLsizei)(height))
+}
+
 func ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
 	C.glowShaderSource(gpShaderSource, (C.GLuint)(shader), (C.GLsizei)(count), (**C.GLchar)(unsafe.Pointer(xstring)), (*C.GLint)(unsafe.Pointer(length)))
 }
 
+func StencilFunc(xfunc uint32, ref int32, mask uint32) {
+	C.glowStencilFunc(gpStencilFunc, (C.GLenum)(xfunc), (C.GLint)(ref), (C.GLuint)(mask))
+}
+
+func StencilOp(fail uint32, zfail uint32, zpass uint32) {
+	C.glowStencilOp(gpStencilOp, (C.GLenum)(fail), (C.GLenum)(zfail), (C.GLenum)(zpass))
+}
+
 func TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
 	C.glowTexImage2D(gpTexImage2D, (C.GLenum)(target), (C.GLint)(level), (C.GLint)(internalformat), (C.GLsizei)(width), (C.GLsizei)(height), (C.GLint)(border), (C.GLenum)(format), (C.GLenum)(xtype), pixels)
 }
@@ -652,6 +850,10 @@ func Uniform2fv(location int32, count int32, value *float32) {
 	C.glowUniform2fv(gpUniform2fv, (C.GLint)(location), (C.GLsizei)(count), (*C.GLfloat)(unsafe.Pointer(value)))
 }
 
+func Uniform3fv(location int32, count int32, value *float32) {
+	C.glowUniform3fv(gpUniform3fv, (C.GLint)(location), (C.GLsizei)(count), (*C.GLfloat)(unsafe.Pointer(value)))
+}
+
 func Uniform4fv(location int32, count int32, value *float32) {
 	C.glowUniform4fv(gpUniform4fv, (C.GLint)(location), (C.GLsizei)(count), (*C.GLfloat)(unsafe.Pointer(value)))
 }
@@ -688,10 +890,16 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 		return errors.New("glBindBuffer")
 	}
 	gpBindFramebufferEXT = (C.GPBINDFRAMEBUFFEREXT)(getProcAddr("glBindFramebufferEXT"))
+	gpBindRenderbuffer = (C.GPBINDRENDERBUFFER)(getProcAddr("glBindRenderbuffer"))
 	gpBindTexture = (C.GPBINDTEXTURE)(getProcAddr("glBindTexture"))
 	if gpBindTexture == nil {
 		return errors.New("glBindTexture")
 	}
+	gpBlendEquationSeparate = (C.GPBLENDEQUATIONSEPARATE)(getProcAddr("glBlendEquationSeparate"))
+	if gpBlendEquationSeparate == nil {
+		return errors.New("glBlendEquationSeparate")
+	}
+	gpBlitFramebuffer = (C.GPBLITFRAMEBUFFER)(getProcAddr("glBlitFramebuffer"))
 	gpBlendFunc = (C.GPBLENDFUNC)(getProcAddr("glBlendFunc"))
 	if gpBlendFunc == nil {
 		return errors.New("glBlendFunc")
@@ -705,6 +913,14 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 		return errors.New("glBufferSubData")
 	}
 	gpCheckFramebufferStatusEXT = (C.GPCHECKFRAMEBUFFERSTATUSEXT)(getProcAddr("glCheckFramebufferStatusEXT"))
+	gpClear = (C.GPCLEAR)(getProcAddr("glClear"))
+	if gpClear == nil {
+		return errors.New("glClear")
+	}
+	gpColorMask = (C.GPCOLORMASK)(getProcAddr("glColorMask"))
+	if gpColorMask == nil {
+		return errors.New("glColorMask")
+	}
 	gpCompileShader = (C.GPCOMPILESHADER)(getProcAddr("glCompileShader"))
 	if gpCompileShader == nil {
 		return errors.New("glCompileShader")
@@ -722,6 +938,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 		return errors.New("glDeleteBuffers")
 	}
 	gpDeleteFramebuffersEXT = (C.GPDELETEFRAMEBUFFERSEXT)(getProcAddr("glDeleteFramebuffersEXT"))
+	gpDeleteRenderbuffers = (C.GPDELETERENDERBUFFERS)(getProcAddr("glDeleteRenderbuffers"))
 	gpDeleteProgram = (C.GPDELETEPROGRAM)(getProcAddr("glDeleteProgram"))
 	if gpDeleteProgram == nil {
 		return errors.New("glDeleteProgram")
@@ -734,6 +951,18 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpDeleteTextures == nil {
 		return errors.New("glDeleteTextures")
 	}
+	gpDepthFunc = (C.GPDEPTHFUNC)(getProcAddr("glDepthFunc"))
+	if gpDepthFunc == nil {
+		return errors.New("glDepthFunc")
+	}
+	gpDepthMask = (C.GPDEPTHMASK)(getProcAddr("glDepthMask"))
+	if gpDepthMask == nil {
+		return errors.New("glDepthMask")
+	}
+	gpDisable = (C.GPDISABLE)(getProcAddr("glDisable"))
+	if gpDisable == nil {
+		return errors.New("glDisable")
+	}
 	gpDisableVertexAttribArray = (C.GPDISABLEVERTEXATTRIBARRAY)(getProcAddr("glDisableVertexAttribArray"))
 	if gpDisableVertexAttribArray == nil {
 		return errors.New("glDisableVertexAttribArray")
@@ -750,16 +979,22 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpEnableVertexAttribArray == nil {
 		return errors.New("glEnableVertexAttribArray")
 	}
+	gpFinish = (C.GPFINISH)(getProcAddr("glFinish"))
+	if gpFinish == nil {
+		return errors.New("glFinish")
+	}
 	gpFlush = (C.GPFLUSH)(getProcAddr("glFlush"))
 	if gpFlush == nil {
 		return errors.New("glFlush")
 	}
+	gpFramebufferRenderbuffer = (C.GPFRAMEBUFFERRENDERBUFFER)(getProcAddr("glFramebufferRenderbuffer"))
 	gpFramebufferTexture2DEXT = (C.GPFRAMEBUFFERTEXTURE2DEXT)(getProcAddr("glFramebufferTexture2DEXT"))
 	gpGenBuffers = (C.GPGENBUFFERS)(getProcAddr("glGenBuffers"))
 	if gpGenBuffers == nil {
 		return errors.New("glGenBuffers")
 	}
 	gpGenFramebuffersEXT = (C.GPGENFRAMEBUFFERSEXT)(getProcAddr("glGenFramebuffersEXT"))
+	gpGenRenderbuffers = (C.GPGENRENDERBUFFERS)(getProcAddr("glGenRenderbuffers"))
 	gpGenTextures = (C.GPGENTEXTURES)(getProcAddr("glGenTextures"))
 	if gpGenTextures == nil {
 		return errors.New("glGenTextures")
@@ -791,6 +1026,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpGetShaderiv == nil {
 		return errors.New("glGetShaderiv")
 	}
+	gpGetString = (C.GPGETSTRING)(getProcAddr("glGetString"))
+	if gpGetString == nil {
+		return errors.New("glGetString")
+	}
 	gpGetTransformFeedbacki64_v = (C.GPGETTRANSFORMFEEDBACKI64_V)(getProcAddr("glGetTransformFeedbacki64_v"))
 	gpGetTransformFeedbacki_v = (C.GPGETTRANSFORMFEEDBACKI_V)(getProcAddr("glGetTransformFeedbacki_v"))
 	gpGetUniformLocation = (C.GPGETUNIFORMLOCATION)(getProcAddr("glGetUniformLocation"))
@@ -817,14 +1056,27 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpPixelStorei == nil {
 		return errors.New("glPixelStorei")
 	}
+	gpRenderbufferStorageMultisample = (C.GPRENDERBUFFERSTORAGEMULTISAMPLE)(getProcAddr("glRenderbufferStorageMultisample"))
 	gpReadPixels = (C.GPREADPIXELS)(getProcAddr("glReadPixels"))
 	if gpReadPixels == nil {
 		return errors.New("glReadPixels")
 	}
+	gpScissor = (C.GPSCISSOR)(getProcAddr("glScissor"))
+	if gpScissor == nil {
+		return errors.New("glScissor")
+	}
 	gpShaderSource = (C.GPSHADERSOURCE)(getProcAddr("glShaderSource"))
 	if gpShaderSource == nil {
 		return errors.New("glShaderSource")
 	}
+	gpStencilFunc = (C.GPSTENCILFUNC)(getProcAddr("glStencilFunc"))
+	if gpStencilFunc == nil {
+		return errors.New("glStencilFunc")
+	}
+	gpStencilOp = (C.GPSTENCILOP)(getProcAddr("glStencilOp"))
+	if gpStencilOp == nil {
+		return errors.New("glStencilOp")
+	}
 	gpTexImage2D = (C.GPTEXIMAGE2D)(getProcAddr("glTexImage2D"))
 	if gpTexImage2D == nil {
 		return errors.New("glTexImage2D")
@@ -849,6 +1101,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpUniform2fv == nil {
 		return errors.New("glUniform2fv")
 	}
+	gpUniform3fv = (C.GPUNIFORM3FV)(getProcAddr("glUniform3fv"))
+	if gpUniform3fv == nil {
+		return errors.New("glUniform3fv")
+	}
 	gpUniform4fv = (C.GPUNIFORM4FV)(getProcAddr("glUniform4fv"))
 	if gpUniform4fv == nil {
 		return errors.New("glUniform4fv")
@@ -869,5 +1125,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpViewport == nil {
 		return errors.New("glViewport")
 	}
+
+	// Sync objects are optional since they are available only with OpenGL 3.2 or ARB_sync.
+	gpClientWaitSync = (C.GPCLIENTWAITSYNC)(getProcAddr("glClientWaitSync"))
+	gpDeleteSync = (C.GPDELETESYNC)(getProcAddr("glDeleteSync"))
+	gpFenceSync = (C.GPFENCESYNC)(getProcAddr("glFenceSync"))
 	return nil
 }
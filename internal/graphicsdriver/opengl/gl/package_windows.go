@@ -10,68 +10,89 @@ import (
 )
 
 var (
-	gpAttachShader                uintptr
-	gpBindAttribLocation          uintptr
-	gpBindBuffer                  uintptr
-	gpBindFramebufferEXT          uintptr
-	gpBindTexture                 uintptr
-	gpBlendFunc                   uintptr
-	gpBufferData                  uintptr
-	gpBufferSubData               uintptr
-	gpCheckFramebufferStatusEXT   uintptr
-	gpCompileShader               uintptr
-	gpCreateProgram               uintptr
-	gpCreateShader                uintptr
-	gpDeleteBuffers               uintptr
-	gpDeleteFramebuffersEXT       uintptr
-	gpDeleteProgram               uintptr
-	gpDeleteShader                uintptr
-	gpDeleteTextures              uintptr
-	gpDisableVertexAttribArray    uintptr
-	gpDrawElements                uintptr
-	gpEnable                      uintptr
-	gpEnableVertexAttribArray     uintptr
-	gpFlush                       uintptr
-	gpFramebufferTexture2DEXT     uintptr
-	gpGenBuffers                  uintptr
-	gpGenFramebuffersEXT          uintptr
-	gpGenTextures                 uintptr
-	gpGetDoublei_v                uintptr
-	gpGetDoublei_vEXT             uintptr
-	gpGetError                    uintptr
-	gpGetFloati_v                 uintptr
-	gpGetFloati_vEXT              uintptr
-	gpGetIntegeri_v               uintptr
-	gpGetIntegerui64i_vNV         uintptr
-	gpGetIntegerv                 uintptr
-	gpGetPointeri_vEXT            uintptr
-	gpGetProgramiv                uintptr
-	gpGetShaderInfoLog            uintptr
-	gpGetShaderiv                 uintptr
-	gpGetTransformFeedbacki64_v   uintptr
-	gpGetTransformFeedbacki_v     uintptr
-	gpGetUniformLocation          uintptr
-	gpGetUnsignedBytei_vEXT       uintptr
-	gpGetVertexArrayIntegeri_vEXT uintptr
-	gpGetVertexArrayPointeri_vEXT uintptr
-	gpIsFramebufferEXT            uintptr
-	gpIsProgram                   uintptr
-	gpIsTexture                   uintptr
-	gpLinkProgram                 uintptr
-	gpPixelStorei                 uintptr
-	gpReadPixels                  uintptr
-	gpShaderSource                uintptr
-	gpTexImage2D                  uintptr
-	gpTexParameteri               uintptr
-	gpTexSubImage2D               uintptr
-	gpUniform1f                   uintptr
-	gpUniform1i                   uintptr
-	gpUniform2fv                  uintptr
-	gpUniform4fv                  uintptr
-	gpUniformMatrix4fv            uintptr
-	gpUseProgram                  uintptr
-	gpVertexAttribPointer         uintptr
-	gpViewport                    uintptr
+	gpAttachShader                   uintptr
+	gpBindAttribLocation             uintptr
+	gpBindBuffer                     uintptr
+	gpBindFramebufferEXT             uintptr
+	gpBindRenderbuffer               uintptr
+	gpBindTexture                    uintptr
+	gpBlendEquationSeparate          uintptr
+	gpBlendFunc                      uintptr
+	gpBlitFramebuffer                uintptr
+	gpBufferData                     uintptr
+	gpBufferSubData                  uintptr
+	gpCheckFramebufferStatusEXT      uintptr
+	gpClear                          uintptr
+	gpColorMask                      uintptr
+	gpCompileShader                  uintptr
+	gpCreateProgram                  uintptr
+	gpCreateShader                   uintptr
+	gpDeleteBuffers                  uintptr
+	gpDeleteFramebuffersEXT          uintptr
+	gpDeleteProgram                  uintptr
+	gpDeleteRenderbuffers            uintptr
+	gpDeleteShader                   uintptr
+	gpClientWaitSync                 uintptr
+	gpDeleteSync                     uintptr
+	gpFenceSync                      uintptr
+	gpDeleteTextures                 uintptr
+	gpDepthFunc                      uintptr
+	gpDepthMask                      uintptr
+	gpDisable                        uintptr
+	gpDisableVertexAttribArray       uintptr
+	gpDrawElements                   uintptr
+	gpEnable                         uintptr
+	gpEnableVertexAttribArray        uintptr
+	gpFinish                         uintptr
+	gpFlush                          uintptr
+	gpFramebufferRenderbuffer        uintptr
+	gpFramebufferTexture2DEXT        uintptr
+	gpGenBuffers                     uintptr
+	gpGenFramebuffersEXT             uintptr
+	gpGenRenderbuffers               uintptr
+	gpGenTextures                    uintptr
+	gpGetDoublei_v                   uintptr
+	gpGetDoublei_vEXT                uintptr
+	gpGetError                       uintptr
+	gpGetFloati_v                    uintptr
+	gpGetFloati_vEXT                 uintptr
+	gpGetIntegeri_v                  uintptr
+	gpGetIntegerui64i_vNV            uintptr
+	gpGetIntegerv                    uintptr
+	gpGetPointeri_vEXT               uintptr
+	gpGetProgramiv                   uintptr
+	gpGetShaderInfoLog               uintptr
+	gpGetShaderiv                    uintptr
+	gpGetString                      uintptr
+	gpGetTransformFeedbacki64_v      uintptr
+	gpGetTransformFeedbacki_v        uintptr
+	gpGetUniformLocation             uintptr
+	gpGetUnsignedBytei_vEXT          uintptr
+	gpGetVertexArrayIntegeri_vEXT    uintptr
+	gpGetVertexArrayPointeri_vEXT    uintptr
+	gpIsFramebufferEXT               uintptr
+	gpIsProgram                      uintptr
+	gpIsTexture                      uintptr
+	gpLinkProgram                    uintptr
+	gpPixelStorei                    uintptr
+	gpReadPixels                     uintptr
+	gpRenderbufferStorageMultisample uintptr
+	gpScissor                        uintptr
+	gpShaderSource                   uintptr
+	gpStencilFunc                    uintptr
+	gpStencilOp                      uintptr
+	gpTexImage2D                     uintptr
+	gpTexParameteri                  uintptr
+	gpTexSubImage2D                  uintptr
+	gpUniform1f                      uintptr
+	gpUniform1i                      uintptr
+	gpUniform2fv                     uintptr
+	gpUniform3fv                     uintptr
+	gpUniform4fv                     uintptr
+	gpUniformMatrix4fv               uintptr
+	gpUseProgram                     uintptr
+	gpVertexAttribPointer            uintptr
+	gpViewport                       uintptr
 )
 
 func boolToUintptr(b bool) uintptr {
@@ -97,14 +118,26 @@ func BindFramebufferEXT(target uint32, framebuffer uint32) {
 	syscall.Syscall(gpBindFramebufferEXT, 2, uintptr(target), uintptr(framebuffer), 0)
 }
 
+func BindRenderbuffer(target uint32, renderbuffer uint32) {
+	syscall.Syscall(gpBindRenderbuffer, 2, uintptr(target), uintptr(renderbuffer), 0)
+}
+
 func BindTexture(target uint32, texture uint32) {
 	syscall.Syscall(gpBindTexture, 2, uintptr(target), uintptr(texture), 0)
 }
 
+func BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
+	syscall.Syscall(gpBlendEquationSeparate, 2, uintptr(modeRGB), uintptr(modeAlpha), 0)
+}
+
 func BlendFunc(sfactor uint32, dfactor uint32) {
 	syscall.Syscall(gpBlendFunc, 2, uintptr(sfactor), uintptr(dfactor), 0)
 }
 
+func BlitFramebuffer(srcX0 int32, srcY0 int32, srcX1 int32, srcY1 int32, dstX0 int32, dstY0 int32, dstX1 int32, dstY1 int32, mask uint32, filter uint32) {
+	syscall.Syscall12(gpBlitFramebuffer, 10, uintptr(srcX0), uintptr(srcY0), uintptr(srcX1), uintptr(srcY1), uintptr(dstX0), uintptr(dstY0), uintptr(dstX1), uintptr(dstY1), uintptr(mask), uintptr(filter), 0, 0)
+}
+
 func BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
 	syscall.Syscall6(gpBufferData, 4, uintptr(target), uintptr(size), uintptr(data), uintptr(usage), 0, 0)
 }
@@ -118,6 +151,14 @@ func CheckFramebufferStatusEXT(target uint32) uint32 {
 	return (uint32)(ret)
 }
 
+func Clear(mask uint32) {
+	syscall.Syscall(gpClear, 1, uintptr(mask), 0, 0)
+}
+
+func ColorMask(red bool, green bool, blue bool, alpha bool) {
+	syscall.Syscall6(gpColorMask, 4, boolToUintptr(red), boolToUintptr(green), boolToUintptr(blue), boolToUintptr(alpha), 0, 0)
+}
+
 func CompileShader(shader uint32) {
 	syscall.Syscall(gpCompileShader, 1, uintptr(shader), 0, 0)
 }
@@ -144,14 +185,61 @@ func DeleteProgram(program uint32) {
 	syscall.Syscall(gpDeleteProgram, 1, uintptr(program), 0, 0)
 }
 
+func DeleteRenderbuffers(n int32, renderbuffers *uint32) {
+	syscall.Syscall(gpDeleteRenderbuffers, 2, uintptr(n), uintptr(unsafe.Pointer(renderbuffers)), 0)
+}
+
 func DeleteShader(shader uint32) {
 	syscall.Syscall(gpDeleteShader, 1, uintptr(shader), 0, 0)
 }
 
+// IsMultisampleSupported reports whether the multisampled renderbuffer functions are available.
+func IsMultisampleSupported() bool {
+	return gpGenRenderbuffers != 0 && gpBindRenderbuffer != 0 && gpDeleteRenderbuffers != 0 &&
+		gpRenderbufferStorageMultisample != 0 && gpFramebufferRenderbuffer != 0 && gpBlitFramebuffer != 0
+}
+
+// IsSyncSupported reports whether the sync object functions are available.
+func IsSyncSupported() bool {
+	return gpFenceSync != 0 && gpClientWaitSync != 0 && gpDeleteSync != 0
+}
+
+func ClientWaitSync(sync uintptr, flags uint32, timeout uint64) uint32 {
+	var ret uintptr
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		// A 64-bit argument takes two words on 32-bit machines.
+		ret, _, _ = syscall.Syscall6(gpClientWaitSync, 4, sync, uintptr(flags), uintptr(timeout), uintptr(timeout>>32), 0, 0)
+	} else {
+		ret, _, _ = syscall.Syscall(gpClientWaitSync, 3, sync, uintptr(flags), uintptr(timeout))
+	}
+	return uint32(ret)
+}
+
+func DeleteSync(sync uintptr) {
+	syscall.Syscall(gpDeleteSync, 1, sync, 0, 0)
+}
+
+func FenceSync(condition uint32, flags uint32) uintptr {
+	ret, _, _ := syscall.Syscall(gpFenceSync, 2, uintptr(condition), uintptr(flags), 0)
+	return ret
+}
+
 func DeleteTextures(n int32, textures *uint32) {
 	syscall.Syscall(gpDeleteTextures, 2, uintptr(n), uintptr(unsafe.Pointer(textures)), 0)
 }
 
+func DepthFunc(xfunc uint32) {
+	syscall.Syscall(gpDepthFunc, 1, uintptr(xfunc), 0, 0)
+}
+
+func DepthMask(flag bool) {
+	syscall.Syscall(gpDepthMask, 1, boolToUintptr(flag), 0, 0)
+}
+
+func Disable(cap uint32) {
+	syscall.Syscall(gpDisable, 1, uintptr(cap), 0, 0)
+}
+
 func DisableVertexAttribArray(index uint32) {
 	syscall.Syscall(gpDisableVertexAttribArray, 1, uintptr(index), 0, 0)
 }
@@ -168,10 +256,18 @@ func EnableVertexAttribArray(index uint32) {
 	syscall.Syscall(gpEnableVertexAttribArray, 1, uintptr(index), 0, 0)
 }
 
+func Finish() {
+	syscall.Syscall(gpFinish, 0, 0, 0, 0)
+}
+
 func Flush() {
 	syscall.Syscall(gpFlush, 0, 0, 0, 0)
 }
 
+func FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32) {
+	syscall.Syscall6(gpFramebufferRenderbuffer, 4, uintptr(target), uintptr(attachment), uintptr(renderbuffertarget), uintptr(renderbuffer), 0, 0)
+}
+
 func FramebufferTexture2DEXT(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
 	syscall.Syscall6(gpFramebufferTexture2DEXT, 5, uintptr(target), uintptr(attachment), uintptr(textarget), uintptr(texture), uintptr(level), 0)
 }
@@ -184,6 +280,10 @@ func GenFramebuffersEXT(n int32, framebuffers *uint32) {
 	syscall.Syscall(gpGenFramebuffersEXT, 2, uintptr(n), uintptr(unsafe.Pointer(framebuffers)), 0)
 }
 
+func GenRenderbuffers(n int32, renderbuffers *uint32) {
+	syscall.Syscall(gpGenRenderbuffers, 2, uintptr(n), uintptr(unsafe.Pointer(renderbuffers)), 0)
+}
+
 func GenTextures(n int32, textures *uint32) {
 	syscall.Syscall(gpGenTextures, 2, uintptr(n), uintptr(unsafe.Pointer(textures)), 0)
 }
@@ -232,6 +332,12 @@ func GetShaderiv(shader uint32, pname uint32, params *int32) {
 	syscall.Syscall(gpGetShaderiv, 3, uintptr(shader), uintptr(pname), uintptr(unsafe.Pointer(params)))
 }
 
+func GetString(name uint32) *uint8 {
+	ret, _, _ := syscall.Syscall(gpGetString, 1, uintptr(name), 0, 0)
+	// Convert the returned address via a pointer to avoid converting a uintptr value to unsafe.Pointer directly.
+	return *(**uint8)(unsafe.Pointer(&ret))
+}
+
 func GetTransformFeedbacki64_v(xfb uint32, pname uint32, index uint32, param *int64) {
 	syscall.Syscall6(gpGetTransformFeedbacki64_v, 4, uintptr(xfb), uintptr(pname), uintptr(index), uintptr(unsafe.Pointer(param)), 0, 0)
 }
@@ -281,10 +387,26 @@ func ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtyp
 	syscall.Syscall9(gpReadPixels, 7, uintptr(x), uintptr(y), uintptr(width), uintptr(height), uintptr(format), uintptr(xtype), uintptr(pixels), 0, 0)
 }
 
+func RenderbufferStorageMultisample(target uint32, samples int32, internalformat uint32, width int32, height int32) {
+	syscall.Syscall6(gpRenderbufferStorageMultisample, 5, uintptr(target), uintptr(samples), uintptr(internalformat), uintptr(width), uintptr(height), 0)
+}
+
+func Scissor(x int32, y int32, width int32, height int32) {
+	syscall.Syscall6(gpScissor, 4, uintptr(x), uintptr(y), uintptr(width), uintptr(height), 0, 0)
+}
+
 func ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
 	syscall.Syscall6(gpShaderSource, 4, uintptr(shader), uintptr(count), uintptr(unsafe.Pointer(xstring)), uintptr(unsafe.Pointer(length)), 0, 0)
 }
 
+func StencilFunc(xfunc uint32, ref int32, mask uint32) {
+	syscall.Syscall(gpStencilFunc, 3, uintptr(xfunc), uintptr(ref), uintptr(mask))
+}
+
+func StencilOp(fail uint32, zfail uint32, zpass uint32) {
+	syscall.Syscall(gpStencilOp, 3, uintptr(fail), uintptr(zfail), uintptr(zpass))
+}
+
 func TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
 	syscall.Syscall9(gpTexImage2D, 9, uintptr(target), uintptr(level), uintptr(internalformat), uintptr(width), uintptr(height), uintptr(border), uintptr(format), uintptr(xtype), uintptr(pixels))
 }
@@ -309,6 +431,10 @@ func Uniform2fv(location int32, count int32, value *float32) {
 	syscall.Syscall(gpUniform2fv, 3, uintptr(location), uintptr(count), uintptr(unsafe.Pointer(value)))
 }
 
+func Uniform3fv(location int32, count int32, value *float32) {
+	syscall.Syscall(gpUniform3fv, 3, uintptr(location), uintptr(count), uintptr(unsafe.Pointer(value)))
+}
+
 func Uniform4fv(location int32, count int32, value *float32) {
 	syscall.Syscall(gpUniform4fv, 3, uintptr(location), uintptr(count), uintptr(unsafe.Pointer(value)))
 }
@@ -345,10 +471,16 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 		return errors.New("glBindBuffer")
 	}
 	gpBindFramebufferEXT = getProcAddr("glBindFramebufferEXT")
+	gpBindRenderbuffer = getProcAddr("glBindRenderbuffer")
 	gpBindTexture = getProcAddr("glBindTexture")
 	if gpBindTexture == 0 {
 		return errors.New("glBindTexture")
 	}
+	gpBlendEquationSeparate = getProcAddr("glBlendEquationSeparate")
+	if gpBlendEquationSeparate == 0 {
+		return errors.New("glBlendEquationSeparate")
+	}
+	gpBlitFramebuffer = getProcAddr("glBlitFramebuffer")
 	gpBlendFunc = getProcAddr("glBlendFunc")
 	if gpBlendFunc == 0 {
 		return errors.New("glBlendFunc")
@@ -362,6 +494,14 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 		return errors.New("glBufferSubData")
 	}
 	gpCheckFramebufferStatusEXT = getProcAddr("glCheckFramebufferStatusEXT")
+	gpClear = getProcAddr("glClear")
+	if gpClear == 0 {
+		return errors.New("glClear")
+	}
+	gpColorMask = getProcAddr("glColorMask")
+	if gpColorMask == 0 {
+		return errors.New("glColorMask")
+	}
 	gpCompileShader = getProcAddr("glCompileShader")
 	if gpCompileShader == 0 {
 		return errors.New("glCompileShader")
@@ -379,6 +519,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 		return errors.New("glDeleteBuffers")
 	}
 	gpDeleteFramebuffersEXT = getProcAddr("glDeleteFramebuffersEXT")
+	gpDeleteRenderbuffers = getProcAddr("glDeleteRenderbuffers")
 	gpDeleteProgram = getProcAddr("glDeleteProgram")
 	if gpDeleteProgram == 0 {
 		return errors.New("glDeleteProgram")
@@ -391,6 +532,18 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpDeleteTextures == 0 {
 		return errors.New("glDeleteTextures")
 	}
+	gpDepthFunc = getProcAddr("glDepthFunc")
+	if gpDepthFunc == 0 {
+		return errors.New("glDepthFunc")
+	}
+	gpDepthMask = getProcAddr("glDepthMask")
+	if gpDepthMask == 0 {
+		return errors.New("glDepthMask")
+	}
+	gpDisable = getProcAddr("glDisable")
+	if gpDisable == 0 {
+		return errors.New("glDisable")
+	}
 	gpDisableVertexAttribArray = getProcAddr("glDisableVertexAttribArray")
 	if gpDisableVertexAttribArray == 0 {
 		return errors.New("glDisableVertexAttribArray")
@@ -407,16 +560,22 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpEnableVertexAttribArray == 0 {
 		return errors.New("glEnableVertexAttribArray")
 	}
+	gpFinish = getProcAddr("glFinish")
+	if gpFinish == 0 {
+		return errors.New("glFinish")
+	}
 	gpFlush = getProcAddr("glFlush")
 	if gpFlush == 0 {
 		return errors.New("glFlush")
 	}
+	gpFramebufferRenderbuffer = getProcAddr("glFramebufferRenderbuffer")
 	gpFramebufferTexture2DEXT = getProcAddr("glFramebufferTexture2DEXT")
 	gpGenBuffers = getProcAddr("glGenBuffers")
 	if gpGenBuffers == 0 {
 		return errors.New("glGenBuffers")
 	}
 	gpGenFramebuffersEXT = getProcAddr("glGenFramebuffersEXT")
+	gpGenRenderbuffers = getProcAddr("glGenRenderbuffers")
 	gpGenTextures = getProcAddr("glGenTextures")
 	if gpGenTextures == 0 {
 		return errors.New("glGenTextures")
@@ -448,6 +607,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpGetShaderiv == 0 {
 		return errors.New("glGetShaderiv")
 	}
+	gpGetString = getProcAddr("glGetString")
+	if gpGetString == 0 {
+		return errors.New("glGetString")
+	}
 	gpGetTransformFeedbacki64_v = getProcAddr("glGetTransformFeedbacki64_v")
 	gpGetTransformFeedbacki_v = getProcAddr("glGetTransformFeedbacki_v")
 	gpGetUniformLocation = getProcAddr("glGetUniformLocation")
@@ -474,14 +637,27 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpPixelStorei == 0 {
 		return errors.New("glPixelStorei")
 	}
+	gpRenderbufferStorageMultisample = getProcAddr("glRenderbufferStorageMultisample")
 	gpReadPixels = getProcAddr("glReadPixels")
 	if gpReadPixels == 0 {
 		return errors.New("glReadPixels")
 	}
+	gpScissor = getProcAddr("glScissor")
+	if gpScissor == 0 {
+		return errors.New("glScissor")
+	}
 	gpShaderSource = getProcAddr("glShaderSource")
 	if gpShaderSource == 0 {
 		return errors.New("glShaderSource")
 	}
+	gpStencilFunc = getProcAddr("glStencilFunc")
+	if gpStencilFunc == 0 {
+		return errors.New("glStencilFunc")
+	}
+	gpStencilOp = getProcAddr("glStencilOp")
+	if gpStencilOp == 0 {
+		return errors.New("glStencilOp")
+	}
 	gpTexImage2D = getProcAddr("glTexImage2D")
 	if gpTexImage2D == 0 {
 		return errors.New("glTexImage2D")
@@ -506,6 +682,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpUniform2fv == 0 {
 		return errors.New("glUniform2fv")
 	}
+	gpUniform3fv = getProcAddr("glUniform3fv")
+	if gpUniform3fv == 0 {
+		return errors.New("glUniform3fv")
+	}
 	gpUniform4fv = getProcAddr("glUniform4fv")
 	if gpUniform4fv == 0 {
 		return errors.New("glUniform4fv")
@@ -526,5 +706,10 @@ func InitWithProcAddrFunc(getProcAddr func(name string) uintptr) error {
 	if gpViewport == 0 {
 		return errors.New("glViewport")
 	}
+
+	// Sync objects are optional since they are available only with OpenGL 3.2 or ARB_sync.
+	gpClientWaitSync = getProcAddr("glClientWaitSync")
+	gpDeleteSync = getProcAddr("glDeleteSync")
+	gpFenceSync = getProcAddr("glFenceSync")
 	return nil
 }
@@ -15,6 +15,8 @@
 package opengl
 
 import (
+	"fmt"
+
 	"github.com/hajimehoshi/ebiten/internal/driver"
 	"github.com/hajimehoshi/ebiten/internal/graphics"
 )
@@ -27,6 +29,30 @@ type Image struct {
 	width         int
 	height        int
 	screen        bool
+
+	// used reports whether the main context has used the image.
+	used bool
+
+	// uploading is closed when the pixels uploaded in background are available.
+	uploading chan struct{}
+
+	// multisample is the multisampled renderbuffer to render. multisample is nil when the image is not multisampled.
+	multisample *multisample
+
+	// alpha reports whether the texture has only the red channel, that holds the alpha values.
+	// The uploaded pixels of an alpha-only image are always (a, a, a, a), so the red values are the alpha values.
+	alpha bool
+}
+
+// waitForUpload blocks until the pixels uploaded in background are available, and marks the image as used.
+func (i *Image) waitForUpload() {
+	i.used = true
+	if i.uploading == nil {
+		return
+	}
+	<-i.uploading
+	i.uploading = nil
+	i.driver.resetBindings()
 }
 
 func (i *Image) IsInvalidated() bool {
@@ -34,9 +60,11 @@ func (i *Image) IsInvalidated() bool {
 }
 
 func (i *Image) Dispose() {
+	i.waitForUpload()
 	if !i.pbo.equal(*new(buffer)) {
 		i.driver.context.deleteBuffer(i.pbo)
 	}
+	i.deleteMultisample()
 	if i.framebuffer != nil {
 		i.framebuffer.delete(&i.driver.context)
 	}
@@ -46,6 +74,7 @@ func (i *Image) Dispose() {
 }
 
 func (i *Image) SetAsDestination() {
+	i.waitForUpload()
 	i.driver.state.destination = i
 }
 
@@ -53,18 +82,65 @@ func (i *Image) setViewport() error {
 	if err := i.ensureFramebuffer(); err != nil {
 		return err
 	}
+	if f := i.multisampleDestination(); f != nil {
+		i.driver.context.setViewport(f)
+		return nil
+	}
 	i.driver.context.setViewport(i.framebuffer)
 	return nil
 }
 
+// ensureStencilBuffer attaches a stencil buffer to the framebuffer to render.
+func (i *Image) ensureStencilBuffer() error {
+	if err := i.ensureFramebuffer(); err != nil {
+		return err
+	}
+	if f := i.multisampleFramebuffer(); f != nil {
+		return f.ensureStencilBuffer(&i.driver.context)
+	}
+	return i.framebuffer.ensureStencilBuffer(&i.driver.context)
+}
+
+// ensureDepthBuffer attaches a depth buffer to the framebuffer to render.
+func (i *Image) ensureDepthBuffer() error {
+	if err := i.ensureFramebuffer(); err != nil {
+		return err
+	}
+	if f := i.multisampleFramebuffer(); f != nil {
+		return f.ensureDepthBuffer(&i.driver.context)
+	}
+	return i.framebuffer.ensureDepthBuffer(&i.driver.context)
+}
+
+// hasDepthBuffer reports whether the framebuffer to render has a depth buffer.
+func (i *Image) hasDepthBuffer() bool {
+	if f := i.multisampleFramebuffer(); f != nil {
+		return f.hasDepth
+	}
+	return i.framebuffer != nil && i.framebuffer.hasDepth
+}
+
 func (i *Image) Pixels() ([]byte, error) {
+	i.waitForUpload()
 	if err := i.ensureFramebuffer(); err != nil {
 		return nil, err
 	}
+	if err := i.resolveMultisample(); err != nil {
+		return nil, err
+	}
 	p, err := i.driver.context.framebufferPixels(i.framebuffer, i.width, i.height)
 	if err != nil {
 		return nil, err
 	}
+	if i.alpha {
+		// The pixels are read as (a, 0, 0, 1). Convert them into white in premultiplied alpha.
+		for j := 0; j < len(p); j += 4 {
+			a := p[j]
+			p[j+1] = a
+			p[j+2] = a
+			p[j+3] = a
+		}
+	}
 	return p, nil
 }
 
@@ -98,6 +174,18 @@ func (i *Image) ReplacePixels(args []*driver.ReplacePixelsArgs) {
 		return
 	}
 
+	// The pixels are uploaded to the texture. The multisampled renderbuffer is synced when the image is rendered.
+	if err := i.resolveMultisample(); err != nil {
+		panic(fmt.Sprintf("opengl: resolving the multisampled renderbuffer failed: %v", err))
+	}
+	i.invalidateMultisample()
+
+	// The loader executes the uploads in order, so there is no need to wait for the previous uploads here.
+	if i.driver.uploadInBackground(i, args) {
+		return
+	}
+	i.waitForUpload()
+
 	// glFlush is necessary on Android.
 	// glTexSubImage2D didn't work without this hack at least on Nexus 5x and NuAns NEO [Reloaded] (#211).
 	if i.driver.drawCalled {
@@ -121,5 +209,9 @@ func (i *Image) ReplacePixels(args []*driver.ReplacePixelsArgs) {
 }
 
 func (i *Image) SetAsSource() {
+	i.waitForUpload()
+	if err := i.resolveMultisample(); err != nil {
+		panic(fmt.Sprintf("opengl: resolving the multisampled renderbuffer failed: %v", err))
+	}
 	i.driver.state.source = i
 }
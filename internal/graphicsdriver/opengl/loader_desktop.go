@@ -0,0 +1,165 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin freebsd linux windows
+// +build !js
+// +build !android
+// +build !ios
+
+package opengl
+
+import (
+	"runtime"
+
+	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver/opengl/gl"
+)
+
+// loaderMinBytes is the minimum size of the pixels to upload on the loader thread. Smaller pixels are uploaded on
+// the main thread since the cost of the synchronization is bigger than the upload.
+const loaderMinBytes = 256 * 1024
+
+// loader uploads pixels to textures on a dedicated thread that has an OpenGL context shared with the main context.
+type loader struct {
+	jobs chan *loaderJob
+
+	// closed is closed when the loop ends and the loader context is released.
+	closed chan struct{}
+}
+
+type loaderJob struct {
+	texture textureNative
+	args    []*driver.ReplacePixelsArgs
+	done    chan struct{}
+
+	// sync is the sync object inserted on the main context after the texture is created. sync is 0 if sync objects
+	// are not available, and then the main context has finished all the commands.
+	sync uintptr
+}
+
+func (l *loader) loop(makeCurrent func(), releaseCurrent func()) {
+	runtime.LockOSThread()
+	makeCurrent()
+	defer func() {
+		releaseCurrent()
+		runtime.UnlockOSThread()
+		close(l.closed)
+	}()
+
+	for j := range l.jobs {
+		if j.sync != 0 {
+			// Wait for the main context to create the texture object.
+			for {
+				const timeout = 1000 * 1000 * 1000 // 1 [s] in nanoseconds
+				r := gl.ClientWaitSync(j.sync, 0, timeout)
+				if r == gl.ALREADY_SIGNALED || r == gl.CONDITION_SATISFIED || r == gl.WAIT_FAILED {
+					break
+				}
+			}
+			gl.DeleteSync(j.sync)
+		}
+
+		gl.BindTexture(gl.TEXTURE_2D, uint32(j.texture))
+		for _, a := range j.args {
+			gl.TexSubImage2D(gl.TEXTURE_2D, 0, int32(a.X), int32(a.Y), int32(a.Width), int32(a.Height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(a.Pixels))
+		}
+		gl.BindTexture(gl.TEXTURE_2D, 0)
+
+		// The change is visible to the main context only after the commands are completed.
+		gl.Finish()
+		close(j.done)
+	}
+}
+
+// SetLoaderContext starts the loader thread to upload pixels in background.
+// makeCurrent is called on the loader thread, and must make an OpenGL context shared with the main context current.
+// releaseCurrent is called on the loader thread when the loader is closed, and must make the context non-current.
+//
+// SetLoaderContext must be called before any image is created.
+func (d *Driver) SetLoaderContext(makeCurrent func(), releaseCurrent func()) {
+	if d.loader != nil {
+		panic("opengl: SetLoaderContext must not be called twice")
+	}
+	d.loader = &loader{
+		jobs:   make(chan *loaderJob, 16),
+		closed: make(chan struct{}),
+	}
+	go d.loader.loop(makeCurrent, releaseCurrent)
+}
+
+// CloseLoaderContext finishes the uploads in progress, stops the loader thread and waits until the loader context is
+// released. After CloseLoaderContext, the pixels are uploaded on the main thread.
+//
+// CloseLoaderContext must not be called concurrently with uploading pixels.
+func (d *Driver) CloseLoaderContext() {
+	if d.loader == nil {
+		return
+	}
+	close(d.loader.jobs)
+	<-d.loader.closed
+	d.loader = nil
+}
+
+// HasLoader reports whether the loader thread is available.
+func (d *Driver) HasLoader() bool {
+	return d.loader != nil
+}
+
+// resetBindings resets the cache of the bindings. A texture modified by another context must be bound again to
+// reflect the change.
+func (d *Driver) resetBindings() {
+	d.context.lastTexture = invalidTexture
+	d.context.lastFramebuffer = invalidFramebuffer
+}
+
+// uploadInBackground tries to upload the pixels on the loader thread, and reports whether the upload is started.
+func (d *Driver) uploadInBackground(i *Image, args []*driver.ReplacePixelsArgs) bool {
+	if d.loader == nil {
+		return false
+	}
+	// The loader context and the main context are not synchronized. In order to avoid conflicts with the
+	// commands on the main context, only the images that the main context has never used are the targets.
+	if i.used {
+		return false
+	}
+
+	n := 0
+	for _, a := range args {
+		n += len(a.Pixels)
+	}
+	if n < loaderMinBytes {
+		return false
+	}
+
+	// Make sure the texture object is created before the loader uses it. Flushing is not enough since the commands
+	// might not be completed yet. Insert a fence and let the loader wait for it, or wait for the completion here if
+	// sync objects are not available.
+	var sync uintptr
+	if d.context.isSyncSupported() {
+		sync = d.context.fenceSync()
+		d.context.flush()
+	} else {
+		d.context.finish()
+	}
+
+	done := make(chan struct{})
+	d.loader.jobs <- &loaderJob{
+		texture: i.textureNative,
+		args:    args,
+		done:    done,
+		sync:    sync,
+	}
+	i.uploading = done
+	return true
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build android ios js
+
+package opengl
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/driver"
+)
+
+// loader is not available on this environment.
+type loader struct{}
+
+func (d *Driver) resetBindings() {
+}
+
+func (d *Driver) HasLoader() bool {
+	return false
+}
+
+func (d *Driver) uploadInBackground(i *Image, args []*driver.ReplacePixelsArgs) bool {
+	return false
+}
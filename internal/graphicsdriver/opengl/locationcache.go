@@ -38,3 +38,10 @@ func (c *locationCache) GetUniformLocation(context *context, p program, location
 	}
 	return l
 }
+
+// Remove removes the cached locations of the program.
+//
+// Remove must be called when the program is deleted since a program ID might be reused.
+func (c *locationCache) Remove(p program) {
+	delete(c.uniformLocationCache, getProgramID(p))
+}
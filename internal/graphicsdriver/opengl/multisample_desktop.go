@@ -0,0 +1,190 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin freebsd linux windows
+// +build !js
+// +build !android
+// +build !ios
+
+package opengl
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/internal/graphicsdriver/opengl/gl"
+)
+
+// multisample is a multisampled renderbuffer that is rendered instead of the texture of an image.
+// The rendering result is resolved into the texture when the image is read.
+//
+// Multisampling requires OpenGL 3.0 or ARB_framebuffer_object.
+type multisample struct {
+	renderbuffer uint32
+	framebuffer  *framebuffer
+
+	// resolved reports whether the texture has the same content as the renderbuffer.
+	resolved bool
+
+	// stale reports whether the texture has newer content than the renderbuffer, e.g., by ReplacePixels.
+	stale bool
+}
+
+// newMultisample creates a multisampled renderbuffer. newMultisample returns nil without an error when
+// multisampling is not available.
+func (d *Driver) newMultisample(width, height, samples int) (*multisample, error) {
+	if !gl.IsMultisampleSupported() {
+		return nil, nil
+	}
+
+	var r, f uint32
+	if err := d.context.t.Call(func() error {
+		max := int32(0)
+		gl.GetIntegerv(gl.MAX_SAMPLES, &max)
+		if samples > int(max) {
+			samples = int(max)
+		}
+		if samples <= 1 {
+			return nil
+		}
+
+		gl.GenRenderbuffers(1, &r)
+		if r <= 0 {
+			return errors.New("opengl: creating renderbuffer failed")
+		}
+		gl.BindRenderbuffer(gl.RENDERBUFFER, r)
+		format := uint32(gl.RGBA8)
+		if d.context.srgb {
+			format = gl.SRGB8_ALPHA8
+		}
+		gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, int32(samples), format, int32(width), int32(height))
+		gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+		gl.GenFramebuffersEXT(1, &f)
+		if f <= 0 {
+			gl.DeleteRenderbuffers(1, &r)
+			return errors.New("opengl: creating framebuffer failed")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if r == 0 {
+		return nil, nil
+	}
+
+	d.context.bindFramebuffer(framebufferNative(f))
+	if err := d.context.t.Call(func() error {
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, r)
+		if s := gl.CheckFramebufferStatusEXT(gl.FRAMEBUFFER); s != gl.FRAMEBUFFER_COMPLETE {
+			return fmt.Errorf("opengl: creating multisampled framebuffer failed: %v", s)
+		}
+		return nil
+	}); err != nil {
+		d.context.deleteFramebuffer(framebufferNative(f))
+		_ = d.context.t.Call(func() error {
+			gl.DeleteRenderbuffers(1, &r)
+			return nil
+		})
+		return nil, err
+	}
+
+	return &multisample{
+		renderbuffer: r,
+		framebuffer: &framebuffer{
+			native:  framebufferNative(f),
+			width:   width,
+			height:  height,
+			samples: samples,
+		},
+		resolved: true,
+	}, nil
+}
+
+func (m *multisample) delete(context *context) {
+	m.framebuffer.delete(context)
+	_ = context.t.Call(func() error {
+		gl.DeleteRenderbuffers(1, &m.renderbuffer)
+		return nil
+	})
+}
+
+// blitFramebuffer copies the pixels from the framebuffer src to the framebuffer dst.
+// When either is multisampled, the samples are resolved or replicated.
+func (c *context) blitFramebuffer(src, dst *framebuffer) {
+	// glBlitFramebuffer is affected by the scissor test.
+	c.setScissor(image.Rectangle{})
+	_ = c.t.Call(func() error {
+		gl.BindFramebufferEXT(gl.READ_FRAMEBUFFER, uint32(src.native))
+		gl.BindFramebufferEXT(gl.DRAW_FRAMEBUFFER, uint32(dst.native))
+		w, h := int32(src.width), int32(src.height)
+		gl.BlitFramebuffer(0, 0, w, h, 0, 0, w, h, gl.COLOR_BUFFER_BIT, gl.NEAREST)
+		return nil
+	})
+	c.lastFramebuffer = invalidFramebuffer
+}
+
+// multisampleDestination returns the multisampled framebuffer to render instead of the texture.
+// multisampleDestination returns nil when the image is not multisampled.
+func (i *Image) multisampleDestination() *framebuffer {
+	m := i.multisample
+	if m == nil {
+		return nil
+	}
+	if m.stale {
+		i.driver.context.blitFramebuffer(i.framebuffer, m.framebuffer)
+		m.stale = false
+	}
+	m.resolved = false
+	return m.framebuffer
+}
+
+// multisampleFramebuffer returns the multisampled framebuffer, or nil when the image is not multisampled.
+func (i *Image) multisampleFramebuffer() *framebuffer {
+	if i.multisample == nil {
+		return nil
+	}
+	return i.multisample.framebuffer
+}
+
+// resolveMultisample resolves the multisampled renderbuffer into the texture if needed.
+func (i *Image) resolveMultisample() error {
+	m := i.multisample
+	if m == nil || m.resolved {
+		return nil
+	}
+	if err := i.ensureFramebuffer(); err != nil {
+		return err
+	}
+	i.driver.context.blitFramebuffer(m.framebuffer, i.framebuffer)
+	m.resolved = true
+	return nil
+}
+
+// invalidateMultisample marks the multisampled renderbuffer older than the texture.
+func (i *Image) invalidateMultisample() {
+	if i.multisample == nil {
+		return
+	}
+	i.multisample.stale = true
+}
+
+func (i *Image) deleteMultisample() {
+	if i.multisample == nil {
+		return
+	}
+	i.multisample.delete(&i.driver.context)
+	i.multisample = nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build android ios js
+
+package opengl
+
+// multisample is not available on this environment.
+type multisample struct{}
+
+func (d *Driver) newMultisample(width, height, samples int) (*multisample, error) {
+	return nil, nil
+}
+
+func (i *Image) multisampleDestination() *framebuffer {
+	return nil
+}
+
+func (i *Image) multisampleFramebuffer() *framebuffer {
+	return nil
+}
+
+func (i *Image) resolveMultisample() error {
+	return nil
+}
+
+func (i *Image) invalidateMultisample() {
+}
+
+func (i *Image) deleteMultisample() {
+}
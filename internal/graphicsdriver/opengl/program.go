@@ -62,7 +62,7 @@ func (a *arrayBufferLayout) totalBytes() int {
 
 // newArrayBuffer creates OpenGL's buffer object for the array buffer.
 func (a *arrayBufferLayout) newArrayBuffer(context *context) buffer {
-	return context.newArrayBuffer(a.totalBytes() * graphics.IndicesNum)
+	return context.newArrayBuffer(a.totalBytes() * context.indicesNum())
 }
 
 // enable binds the array buffer the given program to use the array buffer.
@@ -120,6 +120,23 @@ type programKey struct {
 	useColorM bool
 	filter    driver.Filter
 	address   driver.Address
+
+	// srgb reports whether the vertex colors are decoded from sRGB since blending is done in linear space.
+	srgb bool
+
+	// encodeSRGB reports whether the colors are encoded into sRGB in the shader. This is used for the screen
+	// framebuffer, that might not be sRGB-capable.
+	encodeSRGB bool
+
+	// depthTest reports whether the depth test is enabled. Fully transparent pixels are discarded with the depth
+	// test so that they don't write the depth.
+	depthTest bool
+
+	// alphaSource reports whether the source texture has only alpha values in the red channel.
+	alphaSource bool
+
+	// alphaDestination reports whether the destination framebuffer has only alpha values in the red channel.
+	alphaDestination bool
 }
 
 // openGLState is a state for
@@ -131,8 +148,14 @@ type openGLState struct {
 	elementArrayBuffer buffer
 
 	// programs is OpenGL's program for rendering a texture.
+	// A program is compiled lazily when it is used first, since compiling all the programs at the start is slow.
 	programs map[programKey]program
 
+	// vertexShaderNative is the vertex shader shared by the programs. vertexShaderNative is valid only when
+	// hasVertexShader is true.
+	vertexShaderNative shader
+	hasVertexShader    bool
+
 	lastProgram                program
 	lastViewportWidth          int
 	lastViewportHeight         int
@@ -142,6 +165,7 @@ type openGLState struct {
 	lastSourceHeight           int
 	lastFilter                 *driver.Filter
 	lastAddress                *driver.Address
+	lastDepthZ                 *float32
 
 	source      *Image
 	destination *Image
@@ -167,6 +191,7 @@ func (s *openGLState) reset(context *context) error {
 	s.lastSourceHeight = 0
 	s.lastFilter = nil
 	s.lastAddress = nil
+	s.lastDepthZ = nil
 
 	// When context lost happens, deleting programs or buffers is not necessary.
 	// However, it is not assumed that reset is called only when context lost happens.
@@ -179,6 +204,10 @@ func (s *openGLState) reset(context *context) error {
 			delete(s.programs, k)
 		}
 	}
+	if s.hasVertexShader {
+		context.deleteShader(s.vertexShaderNative)
+		s.hasVertexShader = false
+	}
 
 	// On browsers (at least Chrome), buffers are already detached from the context
 	// and must not be deleted by DeleteBuffer.
@@ -191,56 +220,45 @@ func (s *openGLState) reset(context *context) error {
 		}
 	}
 
-	shaderVertexModelviewNative, err := context.newShader(vertexShader, vertexShaderStr())
-	if err != nil {
-		panic(fmt.Sprintf("graphics: shader compiling error:\n%s", err))
-	}
-	defer context.deleteShader(shaderVertexModelviewNative)
-
-	for _, c := range []bool{false, true} {
-		for _, a := range []driver.Address{
-			driver.AddressClampToZero,
-			driver.AddressRepeat,
-		} {
-			for _, f := range []driver.Filter{
-				driver.FilterNearest,
-				driver.FilterLinear,
-				driver.FilterScreen,
-			} {
-				shaderFragmentColorMatrixNative, err := context.newShader(fragmentShader, fragmentShaderStr(c, f, a))
-				if err != nil {
-					panic(fmt.Sprintf("graphics: shader compiling error:\n%s", err))
-				}
-				defer context.deleteShader(shaderFragmentColorMatrixNative)
-
-				program, err := context.newProgram([]shader{
-					shaderVertexModelviewNative,
-					shaderFragmentColorMatrixNative,
-				}, theArrayBufferLayout.names())
-
-				if err != nil {
-					return err
-				}
-
-				s.programs[programKey{
-					useColorM: c,
-					filter:    f,
-					address:   a,
-				}] = program
-			}
-		}
-	}
-
 	s.arrayBuffer = theArrayBufferLayout.newArrayBuffer(context)
 
 	// Note that the indices passed to NewElementArrayBuffer is not under GC management
 	// in opengl package due to unsafe-way.
 	// See NewElementArrayBuffer in context_mobile.go.
-	s.elementArrayBuffer = context.newElementArrayBuffer(graphics.IndicesNum * 2)
+	s.elementArrayBuffer = context.newElementArrayBuffer(context.indicesNum() * context.indexSize())
 
 	return nil
 }
 
+// program returns the program for the given key. If the program doesn't exist yet, program compiles it.
+func (s *openGLState) program(context *context, key programKey) (program, error) {
+	if p, ok := s.programs[key]; ok {
+		return p, nil
+	}
+
+	if !s.hasVertexShader {
+		v, err := context.newShader(vertexShader, vertexShaderStr(false))
+		if err != nil {
+			panic(fmt.Sprintf("graphics: shader compiling error:\n%s", err))
+		}
+		s.vertexShaderNative = v
+		s.hasVertexShader = true
+	}
+
+	f, err := context.newShader(fragmentShader, fragmentShaderStr(key))
+	if err != nil {
+		panic(fmt.Sprintf("graphics: shader compiling error:\n%s", err))
+	}
+	defer context.deleteShader(f)
+
+	p, err := context.newProgram([]shader{s.vertexShaderNative, f}, theArrayBufferLayout.names())
+	if err != nil {
+		return zeroProgram, err
+	}
+	s.programs[key] = p
+	return p, nil
+}
+
 // areSameFloat32Array returns a boolean indicating if a and b are deeply equal.
 func areSameFloat32Array(a, b []float32) bool {
 	if len(a) != len(b) {
@@ -255,7 +273,7 @@ func areSameFloat32Array(a, b []float32) bool {
 }
 
 // useProgram uses the program (programTexture).
-func (d *Driver) useProgram(mode driver.CompositeMode, colorM *affine.ColorM, filter driver.Filter, address driver.Address) error {
+func (d *Driver) useProgram(mode driver.CompositeMode, colorM *affine.ColorM, filter driver.Filter, address driver.Address, depth driver.Depth) error {
 	destination := d.state.destination
 	if destination == nil {
 		panic("destination image is not set")
@@ -271,13 +289,25 @@ func (d *Driver) useProgram(mode driver.CompositeMode, colorM *affine.ColorM, fi
 	dstW := destination.width
 	srcW, srcH := source.width, source.height
 
+	if d.context.srgb {
+		d.context.setFramebufferSRGB(!destination.screen)
+	}
 	d.context.blendFunc(mode)
 
-	program := d.state.programs[programKey{
-		useColorM: colorM != nil,
-		filter:    filter,
-		address:   address,
-	}]
+	program, err := d.state.program(&d.context, programKey{
+		useColorM:  colorM != nil,
+		filter:     filter,
+		address:    address,
+		srgb:       d.context.srgb,
+		encodeSRGB: d.context.srgb && destination.screen,
+		depthTest:  depth.Mode == driver.DepthModeTest,
+
+		alphaSource:      source.alpha,
+		alphaDestination: destination.alpha,
+	})
+	if err != nil {
+		return err
+	}
 	if !d.state.lastProgram.equal(program) {
 		d.context.useProgram(program)
 		if d.state.lastProgram.equal(zeroProgram) {
@@ -294,6 +324,7 @@ func (d *Driver) useProgram(mode driver.CompositeMode, colorM *affine.ColorM, fi
 		d.state.lastColorMatrixTranslation = nil
 		d.state.lastSourceWidth = 0
 		d.state.lastSourceHeight = 0
+		d.state.lastDepthZ = nil
 	}
 
 	vw := destination.framebuffer.width
@@ -318,6 +349,15 @@ func (d *Driver) useProgram(mode driver.CompositeMode, colorM *affine.ColorM, fi
 		}
 	}
 
+	if depth.Mode == driver.DepthModeTest {
+		// The depth buffer is cleared with 1, and a larger Z is nearer. Map Z in [0, 1] to the depth in [1, 0].
+		z := 1 - 2*depth.Z
+		if d.state.lastDepthZ == nil || *d.state.lastDepthZ != z {
+			d.context.uniformFloat(program, "depth_z", z)
+			d.state.lastDepthZ = &z
+		}
+	}
+
 	if filter != driver.FilterNearest {
 		sw := graphics.InternalImageSize(srcW)
 		sh := graphics.InternalImageSize(srcH)
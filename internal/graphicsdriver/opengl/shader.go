@@ -55,13 +55,16 @@ func checkGLSL(src string) {
 	}
 }
 
-func vertexShaderStr() string {
+func vertexShaderStr(usePosition bool) string {
 	src := shaderStrVertex
+	if usePosition {
+		src = "#define USE_POSITION\n" + src
+	}
 	checkGLSL(src)
 	return src
 }
 
-func fragmentShaderStr(useColorM bool, filter driver.Filter, address driver.Address) string {
+func fragmentShaderStr(key programKey) string {
 	replaces := map[string]string{
 		"{{.AddressClampToZero}}": fmt.Sprintf("%d", driver.AddressClampToZero),
 		"{{.AddressRepeat}}":      fmt.Sprintf("%d", driver.AddressRepeat),
@@ -73,11 +76,29 @@ func fragmentShaderStr(useColorM bool, filter driver.Filter, address driver.Addr
 
 	var defs []string
 
-	if useColorM {
+	if key.useColorM {
 		defs = append(defs, "#define USE_COLOR_MATRIX")
 	}
 
-	switch filter {
+	if key.srgb {
+		defs = append(defs, "#define USE_SRGB")
+	}
+	if key.encodeSRGB {
+		defs = append(defs, "#define ENCODE_SRGB")
+	}
+
+	if key.depthTest {
+		defs = append(defs, "#define DEPTH_TEST")
+	}
+
+	if key.alphaSource {
+		defs = append(defs, "#define ALPHA_SOURCE")
+	}
+	if key.alphaDestination {
+		defs = append(defs, "#define ALPHA_DESTINATION")
+	}
+
+	switch key.filter {
 	case driver.FilterNearest:
 		defs = append(defs, "#define FILTER_NEAREST")
 	case driver.FilterLinear:
@@ -85,16 +106,16 @@ func fragmentShaderStr(useColorM bool, filter driver.Filter, address driver.Addr
 	case driver.FilterScreen:
 		defs = append(defs, "#define FILTER_SCREEN")
 	default:
-		panic(fmt.Sprintf("opengl: invalid filter: %d", filter))
+		panic(fmt.Sprintf("opengl: invalid filter: %d", key.filter))
 	}
 
-	switch address {
+	switch key.address {
 	case driver.AddressClampToZero:
 		defs = append(defs, "#define ADDRESS_CLAMP_TO_ZERO")
 	case driver.AddressRepeat:
 		defs = append(defs, "#define ADDRESS_REPEAT")
 	default:
-		panic(fmt.Sprintf("opengl: invalid address: %d", address))
+		panic(fmt.Sprintf("opengl: invalid address: %d", key.address))
 	}
 
 	src = strings.Replace(src, "{{.Definitions}}", strings.Join(defs, "\n"), -1)
@@ -106,6 +127,8 @@ func fragmentShaderStr(useColorM bool, filter driver.Filter, address driver.Addr
 const (
 	shaderStrVertex = `
 uniform vec2 viewport_size;
+// depth_z is the Z value in the normalized device coordinates. This matters only when the depth test is enabled.
+uniform float depth_z;
 attribute vec2 vertex;
 attribute vec2 tex;
 attribute vec4 tex_region;
@@ -113,11 +136,17 @@ attribute vec4 color_scale;
 varying vec2 varying_tex;
 varying vec4 varying_tex_region;
 varying vec4 varying_color_scale;
+#if defined(USE_POSITION)
+varying vec2 varying_position;
+#endif
 
 void main(void) {
   varying_tex = tex;
   varying_tex_region = tex_region;
   varying_color_scale = color_scale;
+#if defined(USE_POSITION)
+  varying_position = vertex;
+#endif
 
   mat4 projection_matrix = mat4(
     vec4(2.0 / viewport_size.x, 0, 0, 0),
@@ -126,6 +155,7 @@ void main(void) {
     vec4(-1, -1, 0, 1)
   );
   gl_Position = projection_matrix * vec4(vertex, 0, 1);
+  gl_Position.z = depth_z;
 }
 `
 	shaderStrFragment = `
@@ -189,6 +219,32 @@ highp vec2 adjustTexelByAddress(highp vec2 p, highp vec4 tex_region) {
 #endif
 }
 
+// textureColor returns the color of the texture at p.
+// An alpha-only texture has the alpha values in the red channel, and the color is white in premultiplied alpha.
+vec4 textureColor(highp vec2 p) {
+#if defined(ALPHA_SOURCE)
+  return texture2D(texture, p).rrrr;
+#else
+  return texture2D(texture, p);
+#endif
+}
+
+#if defined(USE_SRGB)
+// srgbToLinear decodes an sRGB color into linear. This is used for vertex colors, that are specified in sRGB.
+vec3 srgbToLinear(vec3 c) {
+  return mix(c / 12.92, pow((c + 0.055) / 1.055, vec3(2.4)), step(0.04045, c));
+}
+#endif
+
+#if defined(ENCODE_SRGB)
+// encodeSRGB encodes a premultiplied-alpha color in linear into sRGB.
+vec4 encodeSRGB(vec4 c) {
+  vec3 rgb = clamp(c.rgb / (c.a + (1.0 - sign(c.a))), 0.0, 1.0);
+  rgb = mix(rgb * 12.92, 1.055 * pow(rgb, vec3(1.0 / 2.4)) - 0.055, step(0.0031308, rgb));
+  return vec4(rgb * c.a, c.a);
+}
+#endif
+
 void main(void) {
   highp vec2 pos = varying_tex;
 
@@ -199,7 +255,7 @@ void main(void) {
       varying_tex_region[1] <= pos.y &&
       pos.x < varying_tex_region[2] &&
       pos.y < varying_tex_region[3]) {
-    color = texture2D(texture, pos);
+    color = textureColor(pos);
   } else {
     color = vec4(0, 0, 0, 0);
   }
@@ -215,10 +271,10 @@ void main(void) {
   p0 = adjustTexelByAddress(p0, varying_tex_region);
   p1 = adjustTexelByAddress(p1, varying_tex_region);
 
-  vec4 c0 = texture2D(texture, p0);
-  vec4 c1 = texture2D(texture, vec2(p1.x, p0.y));
-  vec4 c2 = texture2D(texture, vec2(p0.x, p1.y));
-  vec4 c3 = texture2D(texture, p1);
+  vec4 c0 = textureColor(p0);
+  vec4 c1 = textureColor(vec2(p1.x, p0.y));
+  vec4 c2 = textureColor(vec2(p0.x, p1.y));
+  vec4 c3 = textureColor(p1);
   if (p0.x < varying_tex_region[0]) {
     c0 = vec4(0, 0, 0, 0);
     c2 = vec4(0, 0, 0, 0);
@@ -248,35 +304,58 @@ void main(void) {
 
   p1 = adjustTexel(p0, p1);
 
-  vec4 c0 = texture2D(texture, p0);
-  vec4 c1 = texture2D(texture, vec2(p1.x, p0.y));
-  vec4 c2 = texture2D(texture, vec2(p0.x, p1.y));
-  vec4 c3 = texture2D(texture, p1);
+  vec4 c0 = textureColor(p0);
+  vec4 c1 = textureColor(vec2(p1.x, p0.y));
+  vec4 c2 = textureColor(vec2(p0.x, p1.y));
+  vec4 c3 = textureColor(p1);
   // Texels must be in the source rect, so it is not necessary to check that like linear filter.
 
   vec2 rate_center = vec2(1.0, 1.0) - half_scaled_texel_size;
   vec2 rate = clamp(((fract(p0 * source_size) - rate_center) * scale) + rate_center, 0.0, 1.0);
   gl_FragColor = mix(mix(c0, c1, rate.x), mix(c2, c3, rate.x), rate.y);
+#if defined(ENCODE_SRGB)
+  gl_FragColor = encodeSRGB(gl_FragColor);
+#endif
   // Assume that a color matrix and color vector values are not used with FILTER_SCREEN.
 
 #else
 
+  vec4 s = varying_color_scale;
+#if defined(USE_SRGB)
+  s.rgb = srgbToLinear(s.rgb);
+#endif
+
 #if defined(USE_COLOR_MATRIX)
   // Un-premultiply alpha.
   // When the alpha is 0, 1.0 - sign(alpha) is 1.0, which means division does nothing.
   color.rgb /= color.a + (1.0 - sign(color.a));
   // Apply the color matrix or scale.
   color = (color_matrix_body * color) + color_matrix_translation;
-  color *= varying_color_scale;
+  color *= s;
   // Premultiply alpha
   color.rgb *= color.a;
 #else
-  vec4 s = varying_color_scale;
   color *= vec4(s.r, s.g, s.b, 1.0) * s.a;
 #endif
 
   color = min(color, color.a);
 
+#if defined(DEPTH_TEST)
+  // Fully transparent pixels must not hide the pixels behind them.
+  if (color.a == 0.0) {
+    discard;
+  }
+#endif
+
+#if defined(ENCODE_SRGB)
+  color = encodeSRGB(color);
+#endif
+
+#if defined(ALPHA_DESTINATION)
+  // An alpha-only framebuffer has only the red channel. Write the alpha value there.
+  color = vec4(color.a);
+#endif
+
   gl_FragColor = color;
 
 #endif
@@ -23,6 +23,7 @@ type (
 	bufferType  int
 	bufferUsage int
 	operation   int
+	equation    int
 )
 
 type dataType int
@@ -42,6 +42,60 @@ func RunBeforeUpdateHooks() error {
 	return nil
 }
 
+var onPanicHooks = []func(){}
+
+// AppendHookOnPanic appends a hook function that is run when the game panics, before the panic is re-raised.
+//
+// A hook should release resources that the OS can't reclaim well by itself, like audio devices.
+func AppendHookOnPanic(f func()) {
+	m.Lock()
+	onPanicHooks = append(onPanicHooks, f)
+	m.Unlock()
+}
+
+func RunPanicHooks() {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, f := range onPanicHooks {
+		// A hook must not prevent the other hooks from running.
+		func() {
+			defer func() {
+				_ = recover()
+			}()
+			f()
+		}()
+	}
+}
+
+// Resource represents a live resource for debugging.
+type Resource struct {
+	Kind  string
+	Name  string
+	Bytes int64
+}
+
+var resourceReporters = []func() []Resource{}
+
+// AppendResourceReporter appends a function that reports live resources for debugging.
+func AppendResourceReporter(f func() []Resource) {
+	m.Lock()
+	resourceReporters = append(resourceReporters, f)
+	m.Unlock()
+}
+
+// ReportResources returns the resources reported by all the resource reporters.
+func ReportResources() []Resource {
+	m.Lock()
+	defer m.Unlock()
+
+	var rs []Resource
+	for _, f := range resourceReporters {
+		rs = append(rs, f()...)
+	}
+	return rs
+}
+
 var (
 	audioSuspended bool
 	onSuspendAudio func()
@@ -0,0 +1,99 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lazyquery provides a cache for a value of the system that is expensive to query.
+package lazyquery
+
+import (
+	"sync"
+	"time"
+)
+
+// Query caches the result of a query of the system.
+//
+// The system is queried only when the value is requested, and the cached value is refreshed in background when it is
+// older than the TTL or is invalidated e.g. by a change notification of the system.
+type Query struct {
+	query func() interface{}
+	ttl   time.Duration
+
+	value      interface{}
+	queried    bool
+	expires    time.Time
+	refreshing bool
+
+	// generation is incremented at every Invalidate. This detects an invalidation during a refresh.
+	generation int
+
+	m sync.Mutex
+}
+
+// New returns a new Query with the given query function and TTL.
+func New(query func() interface{}, ttl time.Duration) *Query {
+	return &Query{
+		query: query,
+		ttl:   ttl,
+	}
+}
+
+// Get returns the cached value.
+//
+// The first call of Get queries the value synchronously. After that, Get returns the cached value immediately, and
+// starts refreshing the value in background if the value is expired.
+//
+// Get is concurrent-safe.
+func (q *Query) Get() interface{} {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	if !q.queried {
+		q.value = q.query()
+		q.queried = true
+		q.expires = time.Now().Add(q.ttl)
+		return q.value
+	}
+
+	if !q.refreshing && !time.Now().Before(q.expires) {
+		q.refreshing = true
+		go q.refresh(q.generation)
+	}
+	return q.value
+}
+
+func (q *Query) refresh(generation int) {
+	v := q.query()
+
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	q.value = v
+	q.refreshing = false
+	if q.generation != generation {
+		// The value was invalidated during the query. Query again at the next Get.
+		q.expires = time.Time{}
+		return
+	}
+	q.expires = time.Now().Add(q.ttl)
+}
+
+// Invalidate makes the cached value expired so that the next Get refreshes the value.
+//
+// Invalidate is concurrent-safe.
+func (q *Query) Invalidate() {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	q.expires = time.Time{}
+	q.generation++
+}
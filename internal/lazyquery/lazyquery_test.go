@@ -0,0 +1,70 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lazyquery_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/hajimehoshi/ebiten/internal/lazyquery"
+)
+
+func waitForValue(q *Query, want int) bool {
+	for i := 0; i < 100; i++ {
+		if q.Get().(int) == want {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestQueryTTL(t *testing.T) {
+	var n int32
+	q := New(func() interface{} {
+		return int(atomic.AddInt32(&n, 1))
+	}, time.Hour)
+
+	if got, want := q.Get().(int), 1; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+	for i := 0; i < 10; i++ {
+		if got, want := q.Get().(int), 1; got != want {
+			t.Errorf("got: %d, want: %d", got, want)
+		}
+	}
+	if got, want := atomic.LoadInt32(&n), int32(1); got != want {
+		t.Errorf("the number of queries: got: %d, want: %d", got, want)
+	}
+}
+
+func TestQueryInvalidate(t *testing.T) {
+	var n int32
+	q := New(func() interface{} {
+		return int(atomic.AddInt32(&n, 1))
+	}, time.Hour)
+
+	if got, want := q.Get().(int), 1; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+	q.Invalidate()
+	if !waitForValue(q, 2) {
+		t.Errorf("the value was not refreshed after Invalidate")
+	}
+	if got, want := atomic.LoadInt32(&n), int32(2); got != want {
+		t.Errorf("the number of queries: got: %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locale
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func impl() []string {
+	// The system properties are readable from applications.
+	for _, p := range []string{"persist.sys.locale", "ro.product.locale"} {
+		out, err := exec.Command("getprop", p).Output()
+		if err != nil {
+			continue
+		}
+		if l := strings.TrimSpace(string(out)); l != "" {
+			return []string{l}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin
+// +build !js
+
+package locale
+
+// #cgo CFLAGS: -x objective-c
+// #cgo LDFLAGS: -framework Foundation
+//
+// #import <Foundation/Foundation.h>
+// #include <stdlib.h>
+//
+// static char* preferredLanguages() {
+//   @autoreleasepool {
+//     NSString* langs = [[NSLocale preferredLanguages] componentsJoinedByString:@","];
+//     return strdup([langs UTF8String]);
+//   }
+// }
+import "C"
+
+import (
+	"strings"
+	"unsafe"
+)
+
+func impl() []string {
+	cstr := C.preferredLanguages()
+	defer C.free(unsafe.Pointer(cstr))
+	s := C.GoString(cstr)
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locale
+
+import (
+	"syscall/js"
+
+	"github.com/hajimehoshi/ebiten/internal/jsutil"
+)
+
+func impl() []string {
+	nav := js.Global().Get("navigator")
+	if jsutil.Equal(nav, js.Undefined()) {
+		return nil
+	}
+	if ls := nav.Get("languages"); !jsutil.Equal(ls, js.Undefined()) && !jsutil.Equal(ls, js.Null()) {
+		var langs []string
+		for i := 0; i < ls.Get("length").Int(); i++ {
+			langs = append(langs, ls.Index(i).String())
+		}
+		if len(langs) > 0 {
+			return langs
+		}
+	}
+	if l := nav.Get("language"); !jsutil.Equal(l, js.Undefined()) && !jsutil.Equal(l, js.Null()) {
+		return []string{l.String()}
+	}
+	return nil
+}
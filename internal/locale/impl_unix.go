@@ -0,0 +1,39 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build dragonfly freebsd linux netbsd openbsd solaris
+// +build !js
+// +build !android
+
+package locale
+
+import (
+	"os"
+	"strings"
+)
+
+func impl() []string {
+	var langs []string
+	// LANGUAGE is a list of the preferred languages separated by colons, and is prior to the others.
+	if l := os.Getenv("LANGUAGE"); l != "" {
+		langs = append(langs, strings.Split(l, ":")...)
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l := os.Getenv(env); l != "" {
+			langs = append(langs, l)
+			break
+		}
+	}
+	return langs
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locale
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	muiLanguageName = 0x8
+)
+
+var (
+	kernel32 = windows.NewLazySystemDLL("kernel32.dll")
+
+	procGetUserPreferredUILanguages = kernel32.NewProc("GetUserPreferredUILanguages")
+)
+
+func impl() []string {
+	var num, size uint32
+	if r, _, _ := procGetUserPreferredUILanguages.Call(muiLanguageName, uintptr(unsafe.Pointer(&num)), 0, uintptr(unsafe.Pointer(&size))); r == 0 || size == 0 {
+		return nil
+	}
+	buf := make([]uint16, size)
+	if r, _, _ := procGetUserPreferredUILanguages.Call(muiLanguageName, uintptr(unsafe.Pointer(&num)), uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size))); r == 0 {
+		return nil
+	}
+
+	// buf is a list of null-terminated strings, and terminated by an empty string.
+	var langs []string
+	for len(buf) > 0 && buf[0] != 0 {
+		n := 0
+		for n < len(buf) && buf[n] != 0 {
+			n++
+		}
+		langs = append(langs, windows.UTF16ToString(buf[:n]))
+		if n == len(buf) {
+			break
+		}
+		buf = buf[n+1:]
+	}
+	return langs
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package locale provides the language preferences of the user.
+package locale
+
+import (
+	"strings"
+)
+
+// Get returns the user's preferred languages as BCP 47 language tags in the order of the preference.
+func Get() []string {
+	var r []string
+	seen := map[string]struct{}{}
+	for _, l := range impl() {
+		l = normalize(l)
+		if l == "" {
+			continue
+		}
+		if _, ok := seen[l]; ok {
+			continue
+		}
+		seen[l] = struct{}{}
+		r = append(r, l)
+	}
+	return r
+}
+
+// normalize converts a locale name like "en_US.UTF-8" to a BCP 47 language tag like "en-US".
+func normalize(l string) string {
+	if i := strings.IndexAny(l, ".@"); i >= 0 {
+		l = l[:i]
+	}
+	l = strings.Replace(l, "_", "-", -1)
+	if l == "C" || l == "POSIX" {
+		return ""
+	}
+	return l
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locale
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		In  string
+		Out string
+	}{
+		{"en_US.UTF-8", "en-US"},
+		{"ja_JP", "ja-JP"},
+		{"de_DE@euro", "de-DE"},
+		{"zh-Hant-TW", "zh-Hant-TW"},
+		{"fr", "fr"},
+		{"C", ""},
+		{"POSIX", ""},
+		{"", ""},
+	}
+	for _, c := range cases {
+		got := normalize(c.In)
+		want := c.Out
+		if got != want {
+			t.Errorf("normalize(%q): got: %q, want: %q", c.In, got, want)
+		}
+	}
+}
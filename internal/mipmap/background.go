@@ -0,0 +1,111 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mipmap
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+)
+
+// backgroundLevelsMinBytes is the minimum size of the pixels to generate the mipmap levels in background.
+// The levels of smaller images are generated on the GPU as usual.
+const backgroundLevelsMinBytes = 1024 * 1024
+
+// maxBackgroundLevel is the maximum level generated in background. This is the same as the maximum level at DrawImage.
+const maxBackgroundLevel = 6
+
+// backgroundLevels is the pixels of the mipmap levels of a whole image, generated on another goroutine.
+//
+// When the graphics driver uploads large pixels on its loader thread, the levels of a large image are generated on
+// the CPU and uploaded without blocking the render thread. Until the generation finishes, the levels are generated
+// on the GPU as usual.
+type backgroundLevels struct {
+	done chan struct{}
+
+	// pixels[i] is the pixels of the level i+1.
+	pixels [][]byte
+}
+
+func canGenerateLevelsInBackground(m *Mipmap, pix []byte) bool {
+	if m.width == 0 || m.height == 0 || m.volatile {
+		return false
+	}
+	if len(pix) < backgroundLevelsMinBytes {
+		return false
+	}
+	if !graphicscommand.BackgroundUploadAvailable() {
+		return false
+	}
+	// With the sRGB pipeline, the GPU averages the pixels in the linear space, which the CPU doesn't emulate.
+	if graphicscommand.SRGBRequested() || graphicscommand.SRGBEnabled() {
+		return false
+	}
+	return true
+}
+
+// generateLevelsInBackground starts generating the levels from pix. pix must not be modified after this call.
+func generateLevelsInBackground(pix []byte, width, height int) *backgroundLevels {
+	l := &backgroundLevels{
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(l.done)
+		for i := 0; i < maxBackgroundLevel; i++ {
+			var ok bool
+			pix, width, height, ok = halvePixels(pix, width, height)
+			if !ok {
+				break
+			}
+			l.pixels = append(l.pixels, pix)
+		}
+	}()
+	return l
+}
+
+// halvePixels returns the pixels halved by averaging each 2x2 block. This is the same as drawing the image at the
+// scale 0.5 with the linear filter.
+func halvePixels(pix []byte, width, height int) ([]byte, int, int, bool) {
+	w, h := width/2, height/2
+	if w == 0 || h == 0 {
+		return nil, 0, 0, false
+	}
+	dst := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx0 := 4 * ((2*j)*width + 2*i)
+			idx1 := 4 * ((2*j+1)*width + 2*i)
+			for c := 0; c < 4; c++ {
+				s := int(pix[idx0+c]) + int(pix[idx0+4+c]) + int(pix[idx1+c]) + int(pix[idx1+4+c])
+				dst[4*(j*w+i)+c] = byte((s + 2) / 4)
+			}
+		}
+	}
+	return dst, w, h, true
+}
+
+// level returns the pixels of the given level if the generation has finished. level doesn't block.
+func (l *backgroundLevels) level(level int) ([]byte, bool) {
+	if l == nil {
+		return nil, false
+	}
+	select {
+	case <-l.done:
+	default:
+		return nil, false
+	}
+	if level < 1 || level > len(l.pixels) {
+		return nil, false
+	}
+	return l.pixels[level-1], true
+}
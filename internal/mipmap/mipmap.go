@@ -61,6 +61,13 @@ type Mipmap struct {
 	volatile bool
 	orig     *shareable.Image
 	imgs     map[image.Rectangle]levelToImage
+
+	// width and height are the size of a regular image. width and height are 0 for the other kinds of images.
+	width  int
+	height int
+
+	// levels is the levels of the whole image generated in background. levels can be nil.
+	levels *backgroundLevels
 }
 
 func New(width, height int, volatile bool) *Mipmap {
@@ -68,6 +75,33 @@ func New(width, height int, volatile bool) *Mipmap {
 		volatile: volatile,
 		orig:     shareable.NewImage(width, height, volatile),
 		imgs:     map[image.Rectangle]levelToImage{},
+		width:    width,
+		height:   height,
+	}
+}
+
+// NewMultisampled returns a Mipmap whose level 0 image is rendered with multisample anti-aliasing.
+func NewMultisampled(width, height, samples int) *Mipmap {
+	return &Mipmap{
+		orig: shareable.NewMultisampledImage(width, height, samples),
+		imgs: map[image.Rectangle]levelToImage{},
+	}
+}
+
+// NewFloat returns a Mipmap whose images have floating-point values.
+func NewFloat(width, height int, volatile bool) *Mipmap {
+	return &Mipmap{
+		volatile: volatile,
+		orig:     shareable.NewFloatImage(width, height, volatile),
+		imgs:     map[image.Rectangle]levelToImage{},
+	}
+}
+
+// NewAlpha returns a Mipmap whose images have only alpha values.
+func NewAlpha(width, height int) *Mipmap {
+	return &Mipmap{
+		orig: shareable.NewAlphaImage(width, height),
+		imgs: map[image.Rectangle]levelToImage{},
 	}
 }
 
@@ -90,13 +124,31 @@ func (m *Mipmap) Fill(clr color.RGBA) {
 func (m *Mipmap) ReplacePixels(pix []byte) {
 	m.orig.ReplacePixels(pix)
 	m.disposeMipmaps()
+	if canGenerateLevelsInBackground(m, pix) {
+		// pix might be reused by the caller.
+		copied := make([]byte, len(pix))
+		copy(copied, pix)
+		m.levels = generateLevelsInBackground(copied, m.width, m.height)
+	}
+}
+
+func (m *Mipmap) ReplacePixelsRegion(pix []byte, x, y, width, height int) error {
+	if err := m.orig.ReplacePixelsRegion(pix, x, y, width, height); err != nil {
+		return err
+	}
+	m.disposeMipmaps()
+	return nil
 }
 
 func (m *Mipmap) At(x, y int) (r, g, b, a byte, err error) {
 	return m.orig.At(x, y)
 }
 
-func (m *Mipmap) DrawImage(src *Mipmap, bounds image.Rectangle, geom *GeoM, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter) {
+// DrawImage draws the region bounds of src with the geometry matrix.
+//
+// srcInset is the inset of the texture coordinates in texels of the source region. srcInset is used to sample only
+// inside of the region with a linear filter.
+func (m *Mipmap) DrawImage(src *Mipmap, bounds image.Rectangle, srcInset float32, geom *GeoM, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) {
 	if det := geom.det(); det == 0 {
 		return
 	} else if math.IsNaN(float64(det)) {
@@ -148,9 +200,9 @@ func (m *Mipmap) DrawImage(src *Mipmap, bounds image.Rectangle, geom *GeoM, colo
 
 	a, b, c, d, tx, ty := geom.A, geom.B, geom.C, geom.D, geom.Tx, geom.Ty
 	if level == 0 {
-		vs := quadVertices(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Max.Y, a, b, c, d, tx, ty, cr, cg, cb, ca, screen)
+		vs := quadVertices(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Max.Y, srcInset, a, b, c, d, tx, ty, cr, cg, cb, ca, screen)
 		is := graphics.QuadIndices()
-		m.orig.DrawTriangles(src.orig, vs, is, colorm, mode, filter, driver.AddressClampToZero)
+		m.orig.DrawTriangles(src.orig, vs, is, colorm, mode, filter, driver.AddressClampToZero, clip, stencil, depth)
 	} else if buf := src.level(bounds, level); buf != nil {
 		w, h := sizeForLevel(bounds.Dx(), bounds.Dy(), level)
 		s := pow2(level)
@@ -158,15 +210,15 @@ func (m *Mipmap) DrawImage(src *Mipmap, bounds image.Rectangle, geom *GeoM, colo
 		b *= s
 		c *= s
 		d *= s
-		vs := quadVertices(0, 0, w, h, a, b, c, d, tx, ty, cr, cg, cb, ca, false)
+		vs := quadVertices(0, 0, w, h, srcInset, a, b, c, d, tx, ty, cr, cg, cb, ca, false)
 		is := graphics.QuadIndices()
-		m.orig.DrawTriangles(buf, vs, is, colorm, mode, filter, driver.AddressClampToZero)
+		m.orig.DrawTriangles(buf, vs, is, colorm, mode, filter, driver.AddressClampToZero, clip, stencil, depth)
 	}
 	m.disposeMipmaps()
 }
 
-func (m *Mipmap) DrawTriangles(src *Mipmap, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) {
-	m.orig.DrawTriangles(src.orig, vertices, indices, colorm, mode, filter, address)
+func (m *Mipmap) DrawTriangles(src *Mipmap, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) {
+	m.orig.DrawTriangles(src.orig, vertices, indices, colorm, mode, filter, address, clip, stencil, depth)
 	m.disposeMipmaps()
 }
 
@@ -188,13 +240,23 @@ func (m *Mipmap) level(r image.Rectangle, level int) *shareable.Image {
 		return img
 	}
 
+	if r == image.Rect(0, 0, m.width, m.height) {
+		if pix, ok := m.levels.level(level); ok {
+			w, h := sizeForLevel(r.Dx(), r.Dy(), level)
+			s := shareable.NewImage(w, h, false)
+			s.ReplacePixels(pix)
+			imgs[level] = s
+			return s
+		}
+	}
+
 	var src *shareable.Image
 	var vs []float32
 	var filter driver.Filter
 	switch {
 	case level == 1:
 		src = m.orig
-		vs = quadVertices(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y, 0.5, 0, 0, 0.5, 0, 0, 1, 1, 1, 1, false)
+		vs = quadVertices(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y, 0, 0.5, 0, 0, 0.5, 0, 0, 1, 1, 1, 1, false)
 		filter = driver.FilterLinear
 	case level > 1:
 		src = m.level(r, level-1)
@@ -203,11 +265,11 @@ func (m *Mipmap) level(r image.Rectangle, level int) *shareable.Image {
 			return nil
 		}
 		w, h := sizeForLevel(r.Dx(), r.Dy(), level-1)
-		vs = quadVertices(0, 0, w, h, 0.5, 0, 0, 0.5, 0, 0, 1, 1, 1, 1, false)
+		vs = quadVertices(0, 0, w, h, 0, 0.5, 0, 0, 0.5, 0, 0, 1, 1, 1, 1, false)
 		filter = driver.FilterLinear
 	case level == -1:
 		src = m.orig
-		vs = quadVertices(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y, 2, 0, 0, 2, 0, 0, 1, 1, 1, 1, false)
+		vs = quadVertices(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y, 0, 2, 0, 0, 2, 0, 0, 1, 1, 1, 1, false)
 		filter = driver.FilterNearest
 	case level < -1:
 		src = m.level(r, level+1)
@@ -216,7 +278,7 @@ func (m *Mipmap) level(r image.Rectangle, level int) *shareable.Image {
 			return nil
 		}
 		w, h := sizeForLevel(r.Dx(), r.Dy(), level+1)
-		vs = quadVertices(0, 0, w, h, 2, 0, 0, 2, 0, 0, 1, 1, 1, 1, false)
+		vs = quadVertices(0, 0, w, h, 0, 2, 0, 0, 2, 0, 0, 1, 1, 1, 1, false)
 		filter = driver.FilterNearest
 	default:
 		panic(fmt.Sprintf("ebiten: invalid level: %d", level))
@@ -228,8 +290,16 @@ func (m *Mipmap) level(r image.Rectangle, level int) *shareable.Image {
 		imgs[level] = nil
 		return nil
 	}
-	s := shareable.NewImage(w2, h2, m.volatile)
-	s.DrawTriangles(src, vs, is, nil, driver.CompositeModeCopy, filter, driver.AddressClampToZero)
+	var s *shareable.Image
+	if m.orig.IsFloat() {
+		// Keep the precision of the floating-point values on the mipmap levels.
+		s = shareable.NewFloatImage(w2, h2, m.volatile)
+	} else if m.orig.IsAlpha() {
+		s = shareable.NewAlphaImage(w2, h2)
+	} else {
+		s = shareable.NewImage(w2, h2, m.volatile)
+	}
+	s.DrawTriangles(src, vs, is, nil, driver.CompositeModeCopy, filter, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 	imgs[level] = s
 
 	return imgs[level]
@@ -270,6 +340,7 @@ func (m *Mipmap) disposeMipmaps() {
 	for k := range m.imgs {
 		delete(m.imgs, k)
 	}
+	m.levels = nil
 }
 
 // mipmapLevel returns an appropriate mipmap level for the given determinant of a geometry matrix.
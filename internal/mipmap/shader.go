@@ -0,0 +1,47 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mipmap
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/shader"
+	"github.com/hajimehoshi/ebiten/internal/shareable"
+)
+
+// Shader represents a custom shader program.
+type Shader struct {
+	shader *shareable.Shader
+}
+
+// NewShader returns a new shader.
+func NewShader(program *shader.Program) *Shader {
+	return &Shader{
+		shader: shareable.NewShader(program),
+	}
+}
+
+// Dispose disposes the shader.
+func (s *Shader) Dispose() {
+	s.shader.Dispose()
+	s.shader = nil
+}
+
+// DrawShader draws triangles with the given image and the shader.
+//
+// Mipmaps of the source image are not used.
+func (m *Mipmap) DrawShader(src *Mipmap, vertices []float32, indices []uint16, shader *Shader, uniforms [][]float32, mode driver.CompositeMode) {
+	m.orig.DrawShader(src.orig, vertices, indices, shader.shader, uniforms, mode)
+	m.disposeMipmaps()
+}
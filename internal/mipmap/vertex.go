@@ -60,11 +60,16 @@ func vertexSlice(n int, last bool) []float32 {
 	return theVerticesBackend.slice(n, last)
 }
 
-func quadVertices(sx0, sy0, sx1, sy1 int, a, b, c, d, tx, ty float32, cr, cg, cb, ca float32, last bool) []float32 {
+// quadVertices returns the vertices to render the source region (sx0, sy0)-(sx1, sy1) with the geometry matrix.
+//
+// inset is the inset of the texture coordinates in texels. The texture coordinates are moved inward by inset while
+// the source region is kept.
+func quadVertices(sx0, sy0, sx1, sy1 int, inset float32, a, b, c, d, tx, ty float32, cr, cg, cb, ca float32, last bool) []float32 {
 	x := float32(sx1 - sx0)
 	y := float32(sy1 - sy0)
 	ax, by, cx, dy := a*x, b*y, c*x, d*y
-	u0, v0, u1, v1 := float32(sx0), float32(sy0), float32(sx1), float32(sy1)
+	r0, s0, r1, s1 := float32(sx0), float32(sy0), float32(sx1), float32(sy1)
+	u0, v0, u1, v1 := r0+inset, s0+inset, r1-inset, s1-inset
 
 	// This function is very performance-sensitive and implement in a very dumb way.
 	vs := vertexSlice(4, last)
@@ -74,10 +79,10 @@ func quadVertices(sx0, sy0, sx1, sy1 int, a, b, c, d, tx, ty float32, cr, cg, cb
 	vs[1] = ty
 	vs[2] = u0
 	vs[3] = v0
-	vs[4] = u0
-	vs[5] = v0
-	vs[6] = u1
-	vs[7] = v1
+	vs[4] = r0
+	vs[5] = s0
+	vs[6] = r1
+	vs[7] = s1
 	vs[8] = cr
 	vs[9] = cg
 	vs[10] = cb
@@ -87,10 +92,10 @@ func quadVertices(sx0, sy0, sx1, sy1 int, a, b, c, d, tx, ty float32, cr, cg, cb
 	vs[13] = cx + ty
 	vs[14] = u1
 	vs[15] = v0
-	vs[16] = u0
-	vs[17] = v0
-	vs[18] = u1
-	vs[19] = v1
+	vs[16] = r0
+	vs[17] = s0
+	vs[18] = r1
+	vs[19] = s1
 	vs[20] = cr
 	vs[21] = cg
 	vs[22] = cb
@@ -100,10 +105,10 @@ func quadVertices(sx0, sy0, sx1, sy1 int, a, b, c, d, tx, ty float32, cr, cg, cb
 	vs[25] = dy + ty
 	vs[26] = u0
 	vs[27] = v1
-	vs[28] = u0
-	vs[29] = v0
-	vs[30] = u1
-	vs[31] = v1
+	vs[28] = r0
+	vs[29] = s0
+	vs[30] = r1
+	vs[31] = s1
 	vs[32] = cr
 	vs[33] = cg
 	vs[34] = cb
@@ -113,10 +118,10 @@ func quadVertices(sx0, sy0, sx1, sy1 int, a, b, c, d, tx, ty float32, cr, cg, cb
 	vs[37] = cx + dy + ty
 	vs[38] = u1
 	vs[39] = v1
-	vs[40] = u0
-	vs[41] = v0
-	vs[42] = u1
-	vs[43] = v1
+	vs[40] = r0
+	vs[41] = s0
+	vs[42] = r1
+	vs[43] = s1
 	vs[44] = cr
 	vs[45] = cg
 	vs[46] = cb
@@ -17,6 +17,7 @@ package packing
 
 import (
 	"errors"
+	"image"
 )
 
 const (
@@ -209,6 +210,21 @@ func walk(n *Node, f func(n *Node) error) error {
 	return nil
 }
 
+// UsedRegions returns the regions of the allocated nodes.
+func (p *Page) UsedRegions() []image.Rectangle {
+	if p.root == nil {
+		return nil
+	}
+	var rs []image.Rectangle
+	_ = walk(p.root, func(n *Node) error {
+		if n.used {
+			rs = append(rs, image.Rect(n.x, n.y, n.x+n.width, n.y+n.height))
+		}
+		return nil
+	})
+	return rs
+}
+
 func (p *Page) Extend(count int) bool {
 	if p.rollbackExtension != nil {
 		panic("packing: Extend cannot be called without rolling back or committing")
@@ -328,3 +328,27 @@ func TestExtend2(t *testing.T) {
 		t.Errorf("p.Alloc(%d, %d) must fail but not", s, s)
 	}
 }
+
+func TestUsedRegions(t *testing.T) {
+	p := NewPage(1024, 1024)
+	if got := p.UsedRegions(); len(got) != 0 {
+		t.Errorf("p.UsedRegions(): got: %v, want: empty", got)
+	}
+
+	p.Alloc(100, 200)
+	n := p.Alloc(300, 100)
+	p.Alloc(50, 50)
+	p.Free(n)
+
+	got := p.UsedRegions()
+	if len(got) != 2 {
+		t.Fatalf("len(p.UsedRegions()): got: %d, want: 2", len(got))
+	}
+	area := 0
+	for _, r := range got {
+		area += r.Dx() * r.Dy()
+	}
+	if want := 100*200 + 50*50; area != want {
+		t.Errorf("area of p.UsedRegions(): got: %d, want: %d", area, want)
+	}
+}
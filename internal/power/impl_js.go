@@ -0,0 +1,53 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package power
+
+import (
+	"sync"
+	"syscall/js"
+
+	"github.com/hajimehoshi/ebiten/internal/jsutil"
+)
+
+var (
+	battery     js.Value
+	batteryOnce sync.Once
+)
+
+func impl() Source {
+	batteryOnce.Do(func() {
+		// navigator.getBattery is not available on some browsers like Firefox and Safari.
+		n := js.Global().Get("navigator")
+		if jsutil.Equal(n.Get("getBattery"), js.Undefined()) {
+			return
+		}
+		var then js.Func
+		then = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			battery = args[0]
+			then.Release()
+			return nil
+		})
+		n.Call("getBattery").Call("then", then)
+	})
+
+	// Go runs on a single thread on browsers, then battery can be accessed without a lock.
+	if battery.Type() != js.TypeObject {
+		return SourceUnknown
+	}
+	if battery.Get("charging").Bool() {
+		return SourceAC
+	}
+	return SourceBattery
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin
+// +build !js
+// +build !ios
+
+package power
+
+// #cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+//
+// #include <IOKit/ps/IOPowerSources.h>
+// #include <IOKit/ps/IOPSKeys.h>
+//
+// // powerSource returns 0 for unknown, 1 for AC and 2 for battery.
+// static int powerSource() {
+//   CFTypeRef info = IOPSCopyPowerSourcesInfo();
+//   if (!info) {
+//     return 0;
+//   }
+//   int result = 0;
+//   CFStringRef type = IOPSGetProvidingPowerSourceType(info);
+//   if (type) {
+//     if (CFStringCompare(type, CFSTR(kIOPSBatteryPowerValue), 0) == kCFCompareEqualTo) {
+//       result = 2;
+//     } else {
+//       result = 1;
+//     }
+//   }
+//   CFRelease(info);
+//   return result;
+// }
+import "C"
+
+func impl() Source {
+	switch C.powerSource() {
+	case 1:
+		return SourceAC
+	case 2:
+		return SourceBattery
+	default:
+		return SourceUnknown
+	}
+}
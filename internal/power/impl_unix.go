@@ -0,0 +1,53 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build dragonfly freebsd linux netbsd openbsd solaris
+// +build !js
+// +build !android
+
+package power
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+func impl() Source {
+	// Only Linux's sysfs is supported so far. On the other systems, the directory doesn't exist.
+	dirs, err := filepath.Glob("/sys/class/power_supply/*")
+	if err != nil {
+		return SourceUnknown
+	}
+
+	hasMains := false
+	for _, dir := range dirs {
+		t, err := ioutil.ReadFile(filepath.Join(dir, "type"))
+		if err != nil || strings.TrimSpace(string(t)) != "Mains" {
+			continue
+		}
+		hasMains = true
+		online, err := ioutil.ReadFile(filepath.Join(dir, "online"))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(online)) == "1" {
+			return SourceAC
+		}
+	}
+	if hasMains {
+		return SourceBattery
+	}
+	return SourceUnknown
+}
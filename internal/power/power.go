@@ -0,0 +1,44 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package power provides the power source of the system like whether the device runs on battery.
+package power
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/internal/lazyquery"
+)
+
+type Source int
+
+const (
+	SourceUnknown Source = iota
+	SourceAC
+	SourceBattery
+)
+
+// refreshInterval is the TTL of the cached power source.
+const refreshInterval = 10 * time.Second
+
+var theQuery = lazyquery.New(func() interface{} {
+	return impl()
+}, refreshInterval)
+
+// Get returns the current power source of the system.
+//
+// The power source is queried lazily and cached.
+func Get() Source {
+	return theQuery.Get().(Source)
+}
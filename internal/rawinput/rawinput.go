@@ -0,0 +1,242 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rawinput provides the states of individual keyboards and mice, distinguished by the platform.
+package rawinput
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/internal/driver"
+)
+
+type device struct {
+	keyboard bool
+	mouse    bool
+
+	keyPressed    map[driver.Key]bool
+	buttonPressed map[driver.MouseButton]bool
+
+	// pendingDX and pendingDY are the accumulated movement since the last Update.
+	pendingDX int
+	pendingDY int
+
+	dx int
+	dy int
+}
+
+var (
+	devices  = map[int]*device{}
+	nextID   int
+	m        sync.Mutex
+	initOnce sync.Once
+)
+
+func ensureStarted() {
+	initOnce.Do(start)
+}
+
+// addDevice adds a new device and returns its ID.
+//
+// addDevice must be called with m locked.
+func addDevice(keyboard, mouse bool) int {
+	id := nextID
+	nextID++
+	devices[id] = &device{
+		keyboard:      keyboard,
+		mouse:         mouse,
+		keyPressed:    map[driver.Key]bool{},
+		buttonPressed: map[driver.MouseButton]bool{},
+	}
+	return id
+}
+
+// removeDevice removes the device.
+//
+// removeDevice must be called with m locked.
+func removeDevice(id int) {
+	delete(devices, id)
+}
+
+func ids(f func(d *device) bool) []int {
+	ensureStarted()
+
+	m.Lock()
+	defer m.Unlock()
+
+	var r []int
+	for id, d := range devices {
+		if f(d) {
+			r = append(r, id)
+		}
+	}
+	sort.Ints(r)
+	return r
+}
+
+// KeyboardIDs returns the IDs of the available keyboards.
+func KeyboardIDs() []int {
+	return ids(func(d *device) bool {
+		return d.keyboard
+	})
+}
+
+// MouseIDs returns the IDs of the available mice.
+func MouseIDs() []int {
+	return ids(func(d *device) bool {
+		return d.mouse
+	})
+}
+
+// IsKeyPressed reports whether the key of the keyboard (id) is pressed.
+func IsKeyPressed(id int, key driver.Key) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	d, ok := devices[id]
+	if !ok {
+		return false
+	}
+	return d.keyPressed[key]
+}
+
+// IsMouseButtonPressed reports whether the button of the mouse (id) is pressed.
+func IsMouseButtonPressed(id int, button driver.MouseButton) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	d, ok := devices[id]
+	if !ok {
+		return false
+	}
+	return d.buttonPressed[button]
+}
+
+// MouseMovement returns the relative movement of the mouse (id) during the last tick.
+func MouseMovement(id int) (dx, dy int) {
+	m.Lock()
+	defer m.Unlock()
+
+	d, ok := devices[id]
+	if !ok {
+		return 0, 0
+	}
+	return d.dx, d.dy
+}
+
+// Update fixes the movements of the mice for the current tick.
+//
+// Update must be called once every tick, i.e., before every call of the game's Update.
+func Update() {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, d := range devices {
+		d.dx, d.dy = d.pendingDX, d.pendingDY
+		d.pendingDX, d.pendingDY = 0, 0
+	}
+}
+
+// scancodeToKey is the table from the basic scancodes to the keys.
+//
+// The scancodes are the same as the PS/2 scancode set 1, which are used by both Raw Input on Windows and evdev on
+// Linux. Extended keys are different among platforms.
+var scancodeToKey = map[uint16]driver.Key{
+	0x01: driver.KeyEscape,
+	0x02: driver.Key1,
+	0x03: driver.Key2,
+	0x04: driver.Key3,
+	0x05: driver.Key4,
+	0x06: driver.Key5,
+	0x07: driver.Key6,
+	0x08: driver.Key7,
+	0x09: driver.Key8,
+	0x0a: driver.Key9,
+	0x0b: driver.Key0,
+	0x0c: driver.KeyMinus,
+	0x0d: driver.KeyEqual,
+	0x0e: driver.KeyBackspace,
+	0x0f: driver.KeyTab,
+	0x10: driver.KeyQ,
+	0x11: driver.KeyW,
+	0x12: driver.KeyE,
+	0x13: driver.KeyR,
+	0x14: driver.KeyT,
+	0x15: driver.KeyY,
+	0x16: driver.KeyU,
+	0x17: driver.KeyI,
+	0x18: driver.KeyO,
+	0x19: driver.KeyP,
+	0x1a: driver.KeyLeftBracket,
+	0x1b: driver.KeyRightBracket,
+	0x1c: driver.KeyEnter,
+	0x1d: driver.KeyLeftControl,
+	0x1e: driver.KeyA,
+	0x1f: driver.KeyS,
+	0x20: driver.KeyD,
+	0x21: driver.KeyF,
+	0x22: driver.KeyG,
+	0x23: driver.KeyH,
+	0x24: driver.KeyJ,
+	0x25: driver.KeyK,
+	0x26: driver.KeyL,
+	0x27: driver.KeySemicolon,
+	0x28: driver.KeyApostrophe,
+	0x29: driver.KeyGraveAccent,
+	0x2a: driver.KeyLeftShift,
+	0x2b: driver.KeyBackslash,
+	0x2c: driver.KeyZ,
+	0x2d: driver.KeyX,
+	0x2e: driver.KeyC,
+	0x2f: driver.KeyV,
+	0x30: driver.KeyB,
+	0x31: driver.KeyN,
+	0x32: driver.KeyM,
+	0x33: driver.KeyComma,
+	0x34: driver.KeyPeriod,
+	0x35: driver.KeySlash,
+	0x36: driver.KeyRightShift,
+	0x37: driver.KeyKPMultiply,
+	0x38: driver.KeyLeftAlt,
+	0x39: driver.KeySpace,
+	0x3a: driver.KeyCapsLock,
+	0x3b: driver.KeyF1,
+	0x3c: driver.KeyF2,
+	0x3d: driver.KeyF3,
+	0x3e: driver.KeyF4,
+	0x3f: driver.KeyF5,
+	0x40: driver.KeyF6,
+	0x41: driver.KeyF7,
+	0x42: driver.KeyF8,
+	0x43: driver.KeyF9,
+	0x44: driver.KeyF10,
+	0x45: driver.KeyNumLock,
+	0x46: driver.KeyScrollLock,
+	0x47: driver.KeyKP7,
+	0x48: driver.KeyKP8,
+	0x49: driver.KeyKP9,
+	0x4a: driver.KeyKPSubtract,
+	0x4b: driver.KeyKP4,
+	0x4c: driver.KeyKP5,
+	0x4d: driver.KeyKP6,
+	0x4e: driver.KeyKPAdd,
+	0x4f: driver.KeyKP1,
+	0x50: driver.KeyKP2,
+	0x51: driver.KeyKP3,
+	0x52: driver.KeyKP0,
+	0x53: driver.KeyKPDecimal,
+	0x57: driver.KeyF11,
+	0x58: driver.KeyF12,
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !android
+
+package rawinput
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/hajimehoshi/ebiten/internal/driver"
+)
+
+const (
+	evKey = 0x01
+	evRel = 0x02
+
+	relX = 0x00
+	relY = 0x01
+
+	keyA     = 30
+	keySpace = 57
+	btnLeft  = 0x110
+	btnRight = 0x111
+	btnMid   = 0x112
+
+	keyMax = 0x2ff
+	evMax  = 0x1f
+)
+
+// inputEvent represents struct input_event.
+type inputEvent struct {
+	time  syscall.Timeval
+	typ   uint16
+	code  uint16
+	value int32
+}
+
+// extendedEvdevCodeToKey is the table from the evdev key codes for extended keys to the keys.
+var extendedEvdevCodeToKey = map[uint16]driver.Key{
+	96:  driver.KeyKPEnter,
+	97:  driver.KeyRightControl,
+	98:  driver.KeyKPDivide,
+	99:  driver.KeyPrintScreen,
+	100: driver.KeyRightAlt,
+	102: driver.KeyHome,
+	103: driver.KeyUp,
+	104: driver.KeyPageUp,
+	105: driver.KeyLeft,
+	106: driver.KeyRight,
+	107: driver.KeyEnd,
+	108: driver.KeyDown,
+	109: driver.KeyPageDown,
+	110: driver.KeyInsert,
+	111: driver.KeyDelete,
+	117: driver.KeyKPEqual,
+	119: driver.KeyPause,
+	127: driver.KeyMenu,
+}
+
+// openedPaths is the set of the opened device files.
+//
+// openedPaths must be manipulated with m locked.
+var openedPaths = map[string]struct{}{}
+
+// eviocgbit returns the ioctl request number of EVIOCGBIT(ev, len).
+func eviocgbit(ev, len uintptr) uintptr {
+	const iocRead = 2
+	return iocRead<<30 | len<<16 | 'E'<<8 | (0x20 + ev)
+}
+
+func ioctlBits(f *os.File, ev uintptr, bits []byte) bool {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), eviocgbit(ev, uintptr(len(bits))), uintptr(unsafe.Pointer(&bits[0])))
+	return errno == 0
+}
+
+func hasBit(bits []byte, n int) bool {
+	return bits[n/8]&(1<<uint(n%8)) != 0
+}
+
+// deviceKind reports whether the device is a keyboard or a mouse by its capabilities.
+func deviceKind(f *os.File) (keyboard, mouse bool) {
+	evBits := make([]byte, (evMax+1)/8)
+	if !ioctlBits(f, 0, evBits) {
+		return false, false
+	}
+	keyBits := make([]byte, (keyMax+1)/8)
+	if hasBit(evBits, evKey) {
+		if !ioctlBits(f, evKey, keyBits) {
+			return false, false
+		}
+	}
+	relBits := make([]byte, 2)
+	if hasBit(evBits, evRel) {
+		if !ioctlBits(f, evRel, relBits) {
+			return false, false
+		}
+	}
+	keyboard = hasBit(keyBits, keyA) && hasBit(keyBits, keySpace)
+	mouse = hasBit(keyBits, btnLeft) && hasBit(relBits, relX) && hasBit(relBits, relY)
+	return
+}
+
+func open(path string) {
+	m.Lock()
+	_, ok := openedPaths[path]
+	m.Unlock()
+	if ok {
+		return
+	}
+
+	// Opening the device files usually requires a permission, e.g., by the input group.
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	keyboard, mouse := deviceKind(f)
+	if !keyboard && !mouse {
+		f.Close()
+		return
+	}
+
+	m.Lock()
+	openedPaths[path] = struct{}{}
+	id := addDevice(keyboard, mouse)
+	m.Unlock()
+
+	go read(f, path, id)
+}
+
+func read(f *os.File, path string, id int) {
+	defer func() {
+		f.Close()
+		m.Lock()
+		removeDevice(id)
+		delete(openedPaths, path)
+		m.Unlock()
+	}()
+
+	var events [64]inputEvent
+	size := int(unsafe.Sizeof(events[0]))
+	buf := (*[unsafe.Sizeof(events)]byte)(unsafe.Pointer(&events[0]))[:]
+	for {
+		n, err := f.Read(buf)
+		if err != nil {
+			return
+		}
+		m.Lock()
+		d, ok := devices[id]
+		if ok {
+			for _, e := range events[:n/size] {
+				handleEvent(d, &e)
+			}
+		}
+		m.Unlock()
+	}
+}
+
+// handleEvent must be called with m locked.
+func handleEvent(d *device, e *inputEvent) {
+	switch e.typ {
+	case evKey:
+		// The value is 0 for release, 1 for press and 2 for repeat.
+		pressed := e.value != 0
+		switch e.code {
+		case btnLeft:
+			d.buttonPressed[driver.MouseButtonLeft] = pressed
+			return
+		case btnRight:
+			d.buttonPressed[driver.MouseButtonRight] = pressed
+			return
+		case btnMid:
+			d.buttonPressed[driver.MouseButtonMiddle] = pressed
+			return
+		}
+		key, ok := scancodeToKey[e.code]
+		if !ok {
+			key, ok = extendedEvdevCodeToKey[e.code]
+		}
+		if ok {
+			d.keyPressed[key] = pressed
+		}
+	case evRel:
+		switch e.code {
+		case relX:
+			d.pendingDX += int(e.value)
+		case relY:
+			d.pendingDY += int(e.value)
+		}
+	}
+}
+
+// start starts reading evdev device files. The device files are scanned periodically to detect new devices.
+func start() {
+	go func() {
+		for {
+			paths, _ := filepath.Glob("/dev/input/event*")
+			for _, p := range paths {
+				open(p)
+			}
+			time.Sleep(2 * time.Second)
+		}
+	}()
+}
@@ -0,0 +1,270 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rawinput
+
+import (
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	hwndMessage = ^uintptr(2) // HWND_MESSAGE (-3)
+
+	wmInput             = 0x00ff
+	wmInputDeviceChange = 0x00fe
+
+	gidcRemoval = 2
+
+	ridInput = 0x10000003
+
+	ridevInputSink = 0x00000100
+	ridevDevNotify = 0x00002000
+
+	rimTypeMouse    = 0
+	rimTypeKeyboard = 1
+)
+
+var (
+	kernel32 = windows.NewLazySystemDLL("kernel32.dll")
+	user32   = windows.NewLazySystemDLL("user32.dll")
+
+	procGetModuleHandleW        = kernel32.NewProc("GetModuleHandleW")
+	procRegisterClassExW        = user32.NewProc("RegisterClassExW")
+	procCreateWindowExW         = user32.NewProc("CreateWindowExW")
+	procDefWindowProcW          = user32.NewProc("DefWindowProcW")
+	procGetMessageW             = user32.NewProc("GetMessageW")
+	procDispatchMessageW        = user32.NewProc("DispatchMessageW")
+	procRegisterRawInputDevices = user32.NewProc("RegisterRawInputDevices")
+	procGetRawInputData         = user32.NewProc("GetRawInputData")
+	procGetRawInputDeviceList   = user32.NewProc("GetRawInputDeviceList")
+
+	procGetRegisteredRawInputDevices = user32.NewProc("GetRegisteredRawInputDevices")
+)
+
+type wndClassEx struct {
+	cbSize        uint32
+	style         uint32
+	lpfnWndProc   uintptr
+	cbClsExtra    int32
+	cbWndExtra    int32
+	hInstance     uintptr
+	hIcon         uintptr
+	hCursor       uintptr
+	hbrBackground uintptr
+	lpszMenuName  *uint16
+	lpszClassName *uint16
+	hIconSm       uintptr
+}
+
+type msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	ptX     int32
+	ptY     int32
+}
+
+type rawInputDevice struct {
+	usUsagePage uint16
+	usUsage     uint16
+	dwFlags     uint32
+	hwndTarget  uintptr
+}
+
+type rawInputDeviceList struct {
+	hDevice uintptr
+	dwType  uint32
+}
+
+type rawInputHeader struct {
+	dwType  uint32
+	dwSize  uint32
+	hDevice uintptr
+	wParam  uintptr
+}
+
+// handleToID is the table from the device handles to the device IDs.
+//
+// handleToID must be manipulated with m locked.
+var handleToID = map[uintptr]int{}
+
+func deviceFromHandle(h uintptr, keyboard, mouse bool) *device {
+	id, ok := handleToID[h]
+	if !ok {
+		id = addDevice(keyboard, mouse)
+		handleToID[h] = id
+	}
+	return devices[id]
+}
+
+// enumerateDevices adds the devices already connected.
+func enumerateDevices() {
+	var n uint32
+	size := unsafe.Sizeof(rawInputDeviceList{})
+	if r, _, _ := procGetRawInputDeviceList.Call(0, uintptr(unsafe.Pointer(&n)), size); int32(r) < 0 || n == 0 {
+		return
+	}
+	list := make([]rawInputDeviceList, n)
+	r, _, _ := procGetRawInputDeviceList.Call(uintptr(unsafe.Pointer(&list[0])), uintptr(unsafe.Pointer(&n)), size)
+	if int32(r) < 0 {
+		return
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	for _, l := range list[:int(r)] {
+		switch l.dwType {
+		case rimTypeKeyboard:
+			deviceFromHandle(l.hDevice, true, false)
+		case rimTypeMouse:
+			deviceFromHandle(l.hDevice, false, true)
+		}
+	}
+}
+
+func wndProc(hwnd, message, wParam, lParam uintptr) uintptr {
+	switch message {
+	case wmInput:
+		handleRawInput(lParam)
+	case wmInputDeviceChange:
+		if wParam == gidcRemoval {
+			m.Lock()
+			if id, ok := handleToID[lParam]; ok {
+				removeDevice(id)
+				delete(handleToID, lParam)
+			}
+			m.Unlock()
+		}
+		return 0
+	}
+	r, _, _ := procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+	return r
+}
+
+func handleRawInput(hRawInput uintptr) {
+	// The buffer is large enough for RAWINPUT of a keyboard or a mouse.
+	// uint64 is used for alignment.
+	var buf [16]uint64
+	size := uint32(unsafe.Sizeof(buf))
+	r, _, _ := procGetRawInputData.Call(hRawInput, ridInput, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)), unsafe.Sizeof(rawInputHeader{}))
+	if int32(r) <= 0 {
+		return
+	}
+
+	header := (*rawInputHeader)(unsafe.Pointer(&buf[0]))
+	data := unsafe.Pointer(uintptr(unsafe.Pointer(&buf[0])) + unsafe.Sizeof(rawInputHeader{}))
+
+	m.Lock()
+	defer m.Unlock()
+
+	switch header.dwType {
+	case rimTypeKeyboard:
+		key, pressed, ok := keyFromRawKeyboard((*rawKeyboard)(data))
+		if !ok {
+			return
+		}
+		d := deviceFromHandle(header.hDevice, true, false)
+		d.keyPressed[key] = pressed
+	case rimTypeMouse:
+		d := deviceFromHandle(header.hDevice, false, true)
+		d.handleRawMouse((*rawMouse)(data))
+	}
+}
+
+// isRegisteredByOthers reports whether the usage is already registered with another window in this process.
+func isRegisteredByOthers(usagePage, usage uint16, hwnd uintptr) bool {
+	var n uint32
+	size := unsafe.Sizeof(rawInputDevice{})
+	if r, _, _ := procGetRegisteredRawInputDevices.Call(0, uintptr(unsafe.Pointer(&n)), size); int32(r) < 0 || n == 0 {
+		return false
+	}
+	devs := make([]rawInputDevice, n)
+	r, _, _ := procGetRegisteredRawInputDevices.Call(uintptr(unsafe.Pointer(&devs[0])), uintptr(unsafe.Pointer(&n)), size)
+	if int32(r) < 0 {
+		return false
+	}
+	for _, d := range devs[:int(r)] {
+		if d.usUsagePage == usagePage && d.usUsage == usage && d.hwndTarget != hwnd {
+			return true
+		}
+	}
+	return false
+}
+
+// start starts receiving Raw Input messages with a message-only window on a dedicated thread.
+func start() {
+	go func() {
+		runtime.LockOSThread()
+
+		className, err := windows.UTF16PtrFromString("EbitenRawInput")
+		if err != nil {
+			return
+		}
+		hInstance, _, _ := procGetModuleHandleW.Call(0)
+		wc := wndClassEx{
+			lpfnWndProc:   windows.NewCallback(wndProc),
+			hInstance:     hInstance,
+			lpszClassName: className,
+		}
+		wc.cbSize = uint32(unsafe.Sizeof(wc))
+		if r, _, _ := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); r == 0 {
+			return
+		}
+		hwnd, _, _ := procCreateWindowExW.Call(0, uintptr(unsafe.Pointer(className)), 0, 0, 0, 0, 0, 0, hwndMessage, 0, hInstance, 0)
+		if hwnd == 0 {
+			return
+		}
+
+		// RIDEV_INPUTSINK is required to receive inputs with the message-only window that is never foreground.
+		devs := []rawInputDevice{
+			{
+				usUsagePage: 0x01, // HID_USAGE_PAGE_GENERIC
+				usUsage:     0x06, // HID_USAGE_GENERIC_KEYBOARD
+				dwFlags:     ridevInputSink | ridevDevNotify,
+				hwndTarget:  hwnd,
+			},
+		}
+
+		// A registration is per process and usage, so registering the mouse usage would override the
+		// registration by GLFW for GLFW_RAW_MOUSE_MOTION, and vice versa. The GLFW driver never enables the raw
+		// mouse motion, but do not take the mouse usage over when it is already registered.
+		if !isRegisteredByOthers(0x01, 0x02, hwnd) {
+			devs = append(devs, rawInputDevice{
+				usUsagePage: 0x01, // HID_USAGE_PAGE_GENERIC
+				usUsage:     0x02, // HID_USAGE_GENERIC_MOUSE
+				dwFlags:     ridevInputSink | ridevDevNotify,
+				hwndTarget:  hwnd,
+			})
+		}
+		if r, _, _ := procRegisterRawInputDevices.Call(uintptr(unsafe.Pointer(&devs[0])), uintptr(len(devs)), unsafe.Sizeof(devs[0])); r == 0 {
+			return
+		}
+
+		enumerateDevices()
+
+		var ms msg
+		for {
+			r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&ms)), 0, 0, 0)
+			if int32(r) <= 0 {
+				return
+			}
+			procDispatchMessageW.Call(uintptr(unsafe.Pointer(&ms)))
+		}
+	}()
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rawinput
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/driver"
+)
+
+// This file has the parsing of Windows' RAWINPUT structures. This is independent from the platforms so that the
+// parsing can be tested anywhere.
+
+const (
+	riKeyBreak = 0x01
+	riKeyE0    = 0x02
+
+	mouseMoveAbsolute = 0x01
+
+	riMouseLeftButtonDown   = 0x0001
+	riMouseLeftButtonUp     = 0x0002
+	riMouseRightButtonDown  = 0x0004
+	riMouseRightButtonUp    = 0x0008
+	riMouseMiddleButtonDown = 0x0010
+	riMouseMiddleButtonUp   = 0x0020
+)
+
+type rawMouse struct {
+	usFlags            uint16
+	_                  uint16
+	usButtonFlags      uint16
+	usButtonData       uint16
+	ulRawButtons       uint32
+	lLastX             int32
+	lLastY             int32
+	ulExtraInformation uint32
+}
+
+type rawKeyboard struct {
+	makeCode         uint16
+	flags            uint16
+	reserved         uint16
+	vKey             uint16
+	message          uint32
+	extraInformation uint32
+}
+
+// extendedScancodeToKey is the table from the scancodes with the E0 prefix to the keys.
+var extendedScancodeToKey = map[uint16]driver.Key{
+	0x1c: driver.KeyKPEnter,
+	0x1d: driver.KeyRightControl,
+	0x35: driver.KeyKPDivide,
+	0x37: driver.KeyPrintScreen,
+	0x38: driver.KeyRightAlt,
+	0x47: driver.KeyHome,
+	0x48: driver.KeyUp,
+	0x49: driver.KeyPageUp,
+	0x4b: driver.KeyLeft,
+	0x4d: driver.KeyRight,
+	0x4f: driver.KeyEnd,
+	0x50: driver.KeyDown,
+	0x51: driver.KeyPageDown,
+	0x52: driver.KeyInsert,
+	0x53: driver.KeyDelete,
+	0x5d: driver.KeyMenu,
+}
+
+// keyFromRawKeyboard returns the key of the RAWKEYBOARD and whether the key is pressed.
+//
+// keyFromRawKeyboard returns false as ok if the scancode is unknown.
+func keyFromRawKeyboard(k *rawKeyboard) (key driver.Key, pressed bool, ok bool) {
+	t := scancodeToKey
+	if k.flags&riKeyE0 != 0 {
+		t = extendedScancodeToKey
+	}
+	key, ok = t[k.makeCode]
+	if !ok {
+		return 0, false, false
+	}
+	return key, k.flags&riKeyBreak == 0, true
+}
+
+// handleRawMouse updates the device states by the RAWMOUSE.
+//
+// handleRawMouse must be called with m locked.
+func (d *device) handleRawMouse(ms *rawMouse) {
+	// Absolute positions, e.g., from tablets or remote desktops, are not movements.
+	if ms.usFlags&mouseMoveAbsolute == 0 {
+		d.pendingDX += int(ms.lLastX)
+		d.pendingDY += int(ms.lLastY)
+	}
+	for _, b := range []struct {
+		down   uint16
+		up     uint16
+		button driver.MouseButton
+	}{
+		{riMouseLeftButtonDown, riMouseLeftButtonUp, driver.MouseButtonLeft},
+		{riMouseRightButtonDown, riMouseRightButtonUp, driver.MouseButtonRight},
+		{riMouseMiddleButtonDown, riMouseMiddleButtonUp, driver.MouseButtonMiddle},
+	} {
+		if ms.usButtonFlags&b.down != 0 {
+			d.buttonPressed[b.button] = true
+		}
+		if ms.usButtonFlags&b.up != 0 {
+			d.buttonPressed[b.button] = false
+		}
+	}
+}
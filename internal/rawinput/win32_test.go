@@ -0,0 +1,118 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rawinput
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/internal/driver"
+)
+
+func TestKeyFromRawKeyboard(t *testing.T) {
+	cases := []struct {
+		MakeCode uint16
+		Flags    uint16
+		Key      driver.Key
+		Pressed  bool
+		OK       bool
+	}{
+		{0x1e, 0, driver.KeyA, true, true},
+		{0x1e, riKeyBreak, driver.KeyA, false, true},
+		{0x1d, 0, driver.KeyLeftControl, true, true},
+		{0x1d, riKeyE0, driver.KeyRightControl, true, true},
+		{0x1d, riKeyE0 | riKeyBreak, driver.KeyRightControl, false, true},
+		{0x4b, 0, driver.KeyKP4, true, true},
+		{0x4b, riKeyE0, driver.KeyLeft, true, true},
+		{0x1c, riKeyE0, driver.KeyKPEnter, true, true},
+		{0x00, 0, 0, false, false},
+		{0x1e, riKeyE0, 0, false, false},
+	}
+	for _, c := range cases {
+		key, pressed, ok := keyFromRawKeyboard(&rawKeyboard{
+			makeCode: c.MakeCode,
+			flags:    c.Flags,
+		})
+		if key != c.Key || pressed != c.Pressed || ok != c.OK {
+			t.Errorf("keyFromRawKeyboard(0x%02x, 0x%x): got: (%v, %t, %t), want: (%v, %t, %t)", c.MakeCode, c.Flags, key, pressed, ok, c.Key, c.Pressed, c.OK)
+		}
+	}
+}
+
+func TestHandleRawMouse(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Inputs  []rawMouse
+		DX      int
+		DY      int
+		Buttons map[driver.MouseButton]bool
+	}{
+		{
+			Name: "relative",
+			Inputs: []rawMouse{
+				{lLastX: 3, lLastY: -2},
+				{lLastX: -1, lLastY: 5},
+			},
+			DX: 2,
+			DY: 3,
+		},
+		{
+			Name: "absolute",
+			Inputs: []rawMouse{
+				{usFlags: mouseMoveAbsolute, lLastX: 30000, lLastY: 20000},
+				{lLastX: 1, lLastY: 1},
+			},
+			DX: 1,
+			DY: 1,
+		},
+		{
+			Name: "buttons",
+			Inputs: []rawMouse{
+				{usButtonFlags: riMouseLeftButtonDown | riMouseRightButtonDown},
+				{usButtonFlags: riMouseRightButtonUp | riMouseMiddleButtonDown},
+			},
+			Buttons: map[driver.MouseButton]bool{
+				driver.MouseButtonLeft:   true,
+				driver.MouseButtonRight:  false,
+				driver.MouseButtonMiddle: true,
+			},
+		},
+		{
+			Name: "down and up",
+			Inputs: []rawMouse{
+				{usButtonFlags: riMouseLeftButtonDown | riMouseLeftButtonUp},
+			},
+			Buttons: map[driver.MouseButton]bool{
+				driver.MouseButtonLeft: false,
+			},
+		},
+	}
+	for _, c := range cases {
+		d := &device{
+			mouse:         true,
+			buttonPressed: map[driver.MouseButton]bool{},
+		}
+		for i := range c.Inputs {
+			d.handleRawMouse(&c.Inputs[i])
+		}
+		if d.pendingDX != c.DX || d.pendingDY != c.DY {
+			t.Errorf("%s: movement: got: (%d, %d), want: (%d, %d)", c.Name, d.pendingDX, d.pendingDY, c.DX, c.DY)
+		}
+		for b, want := range c.Buttons {
+			if got := d.buttonPressed[b]; got != want {
+				t.Errorf("%s: button %d: got: %t, want: %t", c.Name, b, got, want)
+			}
+		}
+	}
+}
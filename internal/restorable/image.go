@@ -16,6 +16,7 @@ package restorable
 
 import (
 	"fmt"
+	"image"
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/internal/affine"
@@ -76,6 +77,9 @@ type drawTrianglesHistoryItem struct {
 	mode     driver.CompositeMode
 	filter   driver.Filter
 	address  driver.Address
+	clip     image.Rectangle
+	stencil  driver.StencilMode
+	depth    driver.Depth
 }
 
 // Image represents an image that can be restored when GL context is lost.
@@ -97,6 +101,15 @@ type Image struct {
 	// volatile indicates whether the image is cleared whenever a frame starts.
 	volatile bool
 
+	// samples is the number of the samples per pixel for multisampling. 0 means the image is not multisampled.
+	samples int
+
+	// float indicates whether the image has floating-point values.
+	float bool
+
+	// alpha indicates whether the image has only alpha values.
+	alpha bool
+
 	// screen indicates whether the image is used as an actual screen.
 	screen bool
 
@@ -149,6 +162,74 @@ func NewImage(width, height int, volatile bool) *Image {
 	return i
 }
 
+// NewMultisampledImage creates an empty image with the given size, that is rendered with multisample anti-aliasing.
+// samples is the number of the samples per pixel.
+//
+// The returned image is cleared.
+//
+// Note that Dispose is not called automatically.
+func NewMultisampledImage(width, height, samples int) *Image {
+	i := &Image{
+		image:   graphicscommand.NewMultisampledImage(width, height, samples),
+		width:   width,
+		height:  height,
+		samples: samples,
+	}
+	fillImage(i.image, color.RGBA{})
+	theImages.add(i)
+	return i
+}
+
+// NewFloatImage creates an empty image with the given size, whose pixels have floating-point values.
+//
+// The pixels are restored only in 8-bit precision when the context is lost.
+//
+// The returned image is cleared.
+//
+// Note that Dispose is not called automatically.
+func NewFloatImage(width, height int, volatile bool) *Image {
+	i := &Image{
+		image:    graphicscommand.NewFloatImage(width, height),
+		width:    width,
+		height:   height,
+		volatile: volatile,
+		float:    true,
+	}
+	fillImage(i.image, color.RGBA{})
+	theImages.add(i)
+	return i
+}
+
+// NewAlphaImage creates an empty image with the given size, whose pixels have only alpha values.
+//
+// The returned image is cleared.
+//
+// Note that Dispose is not called automatically.
+func NewAlphaImage(width, height int) *Image {
+	i := &Image{
+		image:  graphicscommand.NewAlphaImage(width, height),
+		width:  width,
+		height: height,
+		alpha:  true,
+	}
+	fillImage(i.image, color.RGBA{})
+	theImages.add(i)
+	return i
+}
+
+func (i *Image) newGraphicsCommandImage() *graphicscommand.Image {
+	if i.samples > 0 {
+		return graphicscommand.NewMultisampledImage(i.width, i.height, i.samples)
+	}
+	if i.float {
+		return graphicscommand.NewFloatImage(i.width, i.height)
+	}
+	if i.alpha {
+		return graphicscommand.NewAlphaImage(i.width, i.height)
+	}
+	return graphicscommand.NewImage(i.width, i.height)
+}
+
 // Extend extends the image by the given size.
 // Extend creates a new image with the given size and copies the pixels of the given source image.
 // Extend disposes itself after its call.
@@ -213,6 +294,9 @@ func quadVertices(dx0, dy0, dx1, dy1, sx0, sy0, sx1, sy1, cr, cg, cb, ca float32
 
 // Fill fills the specified part of the image with a solid color.
 func (i *Image) Fill(clr color.RGBA) {
+	if i.alpha {
+		clr = color.RGBA{clr.A, clr.A, clr.A, clr.A}
+	}
 	i.basePixels = Pixels{
 		baseColor: clr,
 	}
@@ -224,6 +308,17 @@ func (i *Image) Fill(clr color.RGBA) {
 	fillImage(i.image, i.basePixels.baseColor)
 }
 
+// discardColors replaces the color values of the premultiplied-alpha pixels with the alpha values, that is white.
+// The pixels of an alpha-only image are kept in this form so that the pixels on CPU match with the pixels on GPU.
+func discardColors(pixels []byte) {
+	for i := 0; i < len(pixels); i += 4 {
+		a := pixels[i+3]
+		pixels[i] = a
+		pixels[i+1] = a
+		pixels[i+2] = a
+	}
+}
+
 func fillImage(i *graphicscommand.Image, clr color.RGBA) {
 	if i == emptyImage.image {
 		panic("restorable: fillImage cannot be called on emptyImage")
@@ -252,7 +347,8 @@ func fillImage(i *graphicscommand.Image, clr color.RGBA) {
 	vs := quadVertices(0, 0, float32(dw), float32(dh), 0, 0, float32(sw), float32(sh), rf, gf, bf, af)
 	is := graphics.QuadIndices()
 
-	i.DrawTriangles(emptyImage.image, vs, is, nil, compositemode, driver.FilterNearest, driver.AddressClampToZero)
+	// Filling an image also clears the depth buffer.
+	i.DrawTriangles(emptyImage.image, vs, is, nil, compositemode, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{Mode: driver.DepthModeClear})
 }
 
 // BasePixelsForTesting returns the image's basePixels for testing.
@@ -272,6 +368,31 @@ func (i *Image) makeStale() {
 	// the former image can be restored from the latest state of the latter image.
 }
 
+// ResolveDrawTrianglesHistory reads the pixels from GPU and replaces the whole image with them if the image is
+// rendered by DrawTriangles, so that ReplacePixels can be called for a part of the image.
+//
+// Replacing the whole image clears the DrawTriangles history, and lets the underlying image accept ReplacePixels for
+// a part again (#593, #758). If the image is not rendered, ResolveDrawTrianglesHistory does nothing.
+func (i *Image) ResolveDrawTrianglesHistory() error {
+	if len(i.drawTrianglesHistory) == 0 && !i.image.IsRenderedByDrawTriangles() {
+		return nil
+	}
+	if err := graphicscommand.FlushCommands(); err != nil {
+		return err
+	}
+	pix, err := i.image.Pixels()
+	if err != nil {
+		return err
+	}
+	// The pixels are the same as the current ones. Other images depending on this image don't have to be stale.
+	i.image.ReplacePixels(pix, 0, 0, i.width, i.height)
+	i.basePixels = Pixels{}
+	i.basePixels.AddOrReplace(pix, 0, 0, i.width, i.height)
+	i.drawTrianglesHistory = nil
+	i.stale = false
+	return nil
+}
+
 // ClearPixels clears the specified region by ReplacePixels.
 func (i *Image) ClearPixels(x, y, width, height int) {
 	i.ReplacePixels(nil, x, y, width, height)
@@ -280,6 +401,7 @@ func (i *Image) ClearPixels(x, y, width, height int) {
 // ReplacePixels replaces the image pixels with the given pixels slice.
 //
 // ReplacePixels for a part is forbidden if the image is rendered with DrawTriangles or Fill.
+// Call ResolveDrawTrianglesHistory in advance in this case.
 func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 	if width <= 0 || height <= 0 {
 		panic("restorable: width/height must be positive")
@@ -298,6 +420,9 @@ func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 	if pixels != nil {
 		copiedPixels = make([]byte, len(pixels))
 		copy(copiedPixels, pixels)
+		if i.alpha {
+			discardColors(copiedPixels)
+		}
 	}
 
 	if pixels != nil {
@@ -355,7 +480,9 @@ func (i *Image) ReplacePixels(pixels []byte, x, y, width, height int) {
 //   9:  Color G
 //   10: Color B
 //   11: Color Y
-func (i *Image) DrawTriangles(img *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) {
+//
+// clip is the region of the image to render in pixels. If clip is empty, the whole image is rendered.
+func (i *Image) DrawTriangles(img *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) {
 	if i.priority {
 		panic("restorable: DrawTriangles cannot be called on a priority image")
 	}
@@ -367,13 +494,13 @@ func (i *Image) DrawTriangles(img *Image, vertices []float32, indices []uint16,
 	if img.stale || img.volatile || i.screen || !needsRestoring() || i.volatile {
 		i.makeStale()
 	} else {
-		i.appendDrawTrianglesHistory(img, vertices, indices, colorm, mode, filter, address)
+		i.appendDrawTrianglesHistory(img, vertices, indices, colorm, mode, filter, address, clip, stencil, depth)
 	}
-	i.image.DrawTriangles(img.image, vertices, indices, colorm, mode, filter, address)
+	i.image.DrawTriangles(img.image, vertices, indices, colorm, mode, filter, address, clip, stencil, depth)
 }
 
 // appendDrawTrianglesHistory appends a draw-image history item to the image.
-func (i *Image) appendDrawTrianglesHistory(image *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) {
+func (i *Image) appendDrawTrianglesHistory(image *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) {
 	if i.stale || i.volatile || i.screen {
 		return
 	}
@@ -398,6 +525,9 @@ func (i *Image) appendDrawTrianglesHistory(image *Image, vertices []float32, ind
 		mode:     mode,
 		filter:   filter,
 		address:  address,
+		clip:     clip,
+		stencil:  stencil,
+		depth:    depth,
 	}
 	i.drawTrianglesHistory = append(i.drawTrianglesHistory, item)
 }
@@ -515,7 +645,7 @@ func (i *Image) restore() error {
 		return nil
 	}
 	if i.volatile {
-		i.image = graphicscommand.NewImage(w, h)
+		i.image = i.newGraphicsCommandImage()
 		fillImage(i.image, color.RGBA{})
 		return nil
 	}
@@ -523,7 +653,7 @@ func (i *Image) restore() error {
 		panic("restorable: pixels must not be stale when restoring")
 	}
 
-	gimg := graphicscommand.NewImage(w, h)
+	gimg := i.newGraphicsCommandImage()
 	// Clear the image explicitly.
 	if i != emptyImage {
 		// As fillImage uses emptyImage, fillImage cannot be called on emptyImage.
@@ -536,7 +666,7 @@ func (i *Image) restore() error {
 		if c.image.hasDependency() {
 			panic("restorable: all dependencies must be already resolved but not")
 		}
-		gimg.DrawTriangles(c.image.image, c.vertices, c.indices, c.colorm, c.mode, c.filter, c.address)
+		gimg.DrawTriangles(c.image.image, c.vertices, c.indices, c.colorm, c.mode, c.filter, c.address, c.clip, c.stencil, c.depth)
 	}
 
 	if len(i.drawTrianglesHistory) > 0 {
@@ -577,6 +707,11 @@ func (i *Image) isInvalidated() (bool, error) {
 	return i.image.IsInvalidated(), nil
 }
 
+// ID returns the ID of the underlying image. This is for debugging usage.
+func (i *Image) ID() int {
+	return i.image.ID()
+}
+
 func (i *Image) Dump(path string) error {
 	return i.image.Dump(path)
 }
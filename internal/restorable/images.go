@@ -16,6 +16,7 @@ package restorable
 
 import (
 	"path/filepath"
+	"sort"
 
 	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
 )
@@ -55,6 +56,7 @@ func ResolveStaleImages() error {
 	if err := graphicscommand.FlushCommands(); err != nil {
 		return err
 	}
+	graphicscommand.EndFrame()
 	if !needsRestoring() {
 		return nil
 	}
@@ -108,6 +110,45 @@ func DumpImages(dir string) error {
 	return nil
 }
 
+// ImageInfo represents the information of an image with a texture for debugging.
+type ImageInfo struct {
+	ID       int
+	Width    int
+	Height   int
+	Volatile bool
+	Screen   bool
+
+	// InternalWidth and InternalHeight are the size of the texture, which might be bigger than the image size.
+	InternalWidth  int
+	InternalHeight int
+}
+
+// ImageInfos returns the information of all the current images sorted by the IDs.
+//
+// This is for debugging usage.
+func ImageInfos() []ImageInfo {
+	var infos []ImageInfo
+	for img := range theImages.images {
+		if img.image == nil {
+			continue
+		}
+		iw, ih := img.image.InternalSize()
+		infos = append(infos, ImageInfo{
+			ID:             img.image.ID(),
+			Width:          img.width,
+			Height:         img.height,
+			Volatile:       img.volatile,
+			Screen:         img.screen,
+			InternalWidth:  iw,
+			InternalHeight: ih,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
 // add adds img to the images.
 func (i *images) add(img *Image) {
 	i.images[img] = struct{}{}
@@ -166,6 +207,12 @@ func (i *images) restore() error {
 		i.image.Dispose()
 		i.image = nil
 	}
+	// The disposal is usually deferred until the in-flight frames finish, but there are no such frames after the
+	// context is lost. Dispose the images immediately so that the IDs are not duplicated.
+	if err := graphicscommand.FlushCommands(); err != nil {
+		return err
+	}
+	graphicscommand.DisposeDeferredImages()
 
 	// Let's do topological sort based on dependencies of drawing history.
 	// It is assured that there are not loops since cyclic drawing makes images stale.
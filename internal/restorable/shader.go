@@ -0,0 +1,58 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restorable
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/internal/shader"
+)
+
+// Shader represents a custom shader program.
+//
+// A shader doesn't have to be restored explicitly since graphicscommand compiles it again when the graphics driver
+// state is reset.
+type Shader struct {
+	shader *graphicscommand.Shader
+}
+
+// NewShader returns a new shader.
+func NewShader(program *shader.Program) *Shader {
+	return &Shader{
+		shader: graphicscommand.NewShader(program),
+	}
+}
+
+// Dispose disposes the shader.
+func (s *Shader) Dispose() {
+	s.shader.Dispose()
+	s.shader = nil
+}
+
+// DrawShader draws triangles with the given image and the shader.
+//
+// The result of a shader is not recorded as a drawing history, and the image becomes stale.
+func (i *Image) DrawShader(img *Image, vertices []float32, indices []uint16, shader *Shader, uniforms [][]float32, mode driver.CompositeMode) {
+	if i.priority {
+		panic("restorable: DrawShader cannot be called on a priority image")
+	}
+	if len(vertices) == 0 {
+		return
+	}
+	theImages.makeStaleIfDependingOn(i)
+	// TODO: Record the drawing history to restore the image without reading pixels from GPU.
+	i.makeStale()
+	i.image.DrawShader(img.image, vertices, indices, shader.shader, uniforms, mode)
+}
@@ -0,0 +1,508 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shader
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+type builtinKind int
+
+const (
+	// builtinGeneric is a function whose result type is the same as the arguments, like sin or clamp.
+	builtinGeneric builtinKind = iota
+	builtinFloat
+	builtinVec3
+	builtinTexture0At
+	builtinTexture0Size
+	builtinTexture0Region
+)
+
+type builtinFunc struct {
+	glsl    string
+	kind    builtinKind
+	minArgs int
+	maxArgs int
+}
+
+var builtinFuncs = map[string]builtinFunc{
+	"radians":     {"radians", builtinGeneric, 1, 1},
+	"degrees":     {"degrees", builtinGeneric, 1, 1},
+	"sin":         {"sin", builtinGeneric, 1, 1},
+	"cos":         {"cos", builtinGeneric, 1, 1},
+	"tan":         {"tan", builtinGeneric, 1, 1},
+	"asin":        {"asin", builtinGeneric, 1, 1},
+	"acos":        {"acos", builtinGeneric, 1, 1},
+	"atan":        {"atan", builtinGeneric, 1, 1},
+	"atan2":       {"atan", builtinGeneric, 2, 2},
+	"pow":         {"pow", builtinGeneric, 2, 2},
+	"exp":         {"exp", builtinGeneric, 1, 1},
+	"log":         {"log", builtinGeneric, 1, 1},
+	"exp2":        {"exp2", builtinGeneric, 1, 1},
+	"log2":        {"log2", builtinGeneric, 1, 1},
+	"sqrt":        {"sqrt", builtinGeneric, 1, 1},
+	"inversesqrt": {"inversesqrt", builtinGeneric, 1, 1},
+	"abs":         {"abs", builtinGeneric, 1, 1},
+	"sign":        {"sign", builtinGeneric, 1, 1},
+	"floor":       {"floor", builtinGeneric, 1, 1},
+	"ceil":        {"ceil", builtinGeneric, 1, 1},
+	"fract":       {"fract", builtinGeneric, 1, 1},
+	"mod":         {"mod", builtinGeneric, 2, 2},
+	"min":         {"min", builtinGeneric, 2, 2},
+	"max":         {"max", builtinGeneric, 2, 2},
+	"clamp":       {"clamp", builtinGeneric, 3, 3},
+	"mix":         {"mix", builtinGeneric, 3, 3},
+	"step":        {"step", builtinGeneric, 2, 2},
+	"smoothstep":  {"smoothstep", builtinGeneric, 3, 3},
+	"normalize":   {"normalize", builtinGeneric, 1, 1},
+	"reflect":     {"reflect", builtinGeneric, 2, 2},
+	"length":      {"length", builtinFloat, 1, 1},
+	"distance":    {"distance", builtinFloat, 2, 2},
+	"dot":         {"dot", builtinFloat, 2, 2},
+	"cross":       {"cross", builtinVec3, 2, 2},
+
+	"texture0At":     {"texture0At", builtinTexture0At, 1, 1},
+	"texture0Size":   {"source_size", builtinTexture0Size, 0, 0},
+	"texture0Region": {"varying_tex_region", builtinTexture0Region, 0, 0},
+}
+
+// defaultType returns the type of a variable that is initialized with a value of the type t.
+func defaultType(t Type) Type {
+	switch t {
+	case typeUntypedInt:
+		return TypeInt
+	case typeUntypedFloat:
+		return TypeFloat
+	}
+	return t
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || '9' < r {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+// convert converts the value of the type from to the type to.
+//
+// Only untyped constants can be converted implicitly.
+func (c *compiler) convert(e ast.Node, str string, from, to Type) (string, error) {
+	if from == to {
+		return str, nil
+	}
+	switch {
+	case from == typeUntypedInt && to == TypeInt:
+		return str, nil
+	case from == typeUntypedInt && to == TypeFloat:
+		if isDigits(str) {
+			return str + ".0", nil
+		}
+		return "float(" + str + ")", nil
+	case from == typeUntypedFloat && to == TypeFloat:
+		return str, nil
+	}
+	return "", c.errorf(e.Pos(), "cannot use %s as %s", typeString(from), to)
+}
+
+func typeString(t Type) string {
+	switch t {
+	case typeUntypedInt:
+		return "untyped int"
+	case typeUntypedFloat:
+		return "untyped float"
+	case TypeNone:
+		return "no value"
+	}
+	return t.String()
+}
+
+// scalarOf returns the scalar type that an untyped constant should be converted to when it is used with the type t.
+func scalarOf(t Type) Type {
+	if t == TypeInt {
+		return TypeInt
+	}
+	if t.isFloatBased() {
+		return TypeFloat
+	}
+	return TypeNone
+}
+
+func (c *compiler) expr(e ast.Expr) (string, Type, error) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.INT:
+			return e.Value, typeUntypedInt, nil
+		case token.FLOAT:
+			v := e.Value
+			if !strings.ContainsAny(v, ".eE") {
+				v += ".0"
+			}
+			return v, typeUntypedFloat, nil
+		default:
+			return "", TypeNone, c.errorf(e.Pos(), "%s literals are not supported", e.Kind)
+		}
+	case *ast.Ident:
+		switch e.Name {
+		case "true", "false":
+			return e.Name, TypeBool, nil
+		}
+		if t, ok := c.lookupLocal(e.Name); ok {
+			return "l_" + e.Name, t, nil
+		}
+		if t, ok := c.uniforms[e.Name]; ok {
+			return "u_" + e.Name, t, nil
+		}
+		if k, ok := c.consts[e.Name]; ok {
+			if isDigits(k.expr) {
+				return k.expr, k.typ, nil
+			}
+			return "(" + k.expr + ")", k.typ, nil
+		}
+		return "", TypeNone, c.errorf(e.Pos(), "undefined: %s", e.Name)
+	case *ast.ParenExpr:
+		str, t, err := c.expr(e.X)
+		if err != nil {
+			return "", TypeNone, err
+		}
+		return "(" + str + ")", t, nil
+	case *ast.UnaryExpr:
+		str, t, err := c.expr(e.X)
+		if err != nil {
+			return "", TypeNone, err
+		}
+		switch e.Op {
+		case token.ADD, token.SUB:
+			if t == TypeBool {
+				return "", TypeNone, c.errorf(e.Pos(), "invalid operation: %s of bool", e.Op)
+			}
+			return e.Op.String() + str, t, nil
+		case token.NOT:
+			if t != TypeBool {
+				return "", TypeNone, c.errorf(e.Pos(), "invalid operation: ! of %s", typeString(t))
+			}
+			return "!" + str, t, nil
+		default:
+			return "", TypeNone, c.errorf(e.Pos(), "%s is not supported", e.Op)
+		}
+	case *ast.BinaryExpr:
+		l, lt, err := c.expr(e.X)
+		if err != nil {
+			return "", TypeNone, err
+		}
+		r, rt, err := c.expr(e.Y)
+		if err != nil {
+			return "", TypeNone, err
+		}
+		return c.binary(e, e.Op.String(), l, lt, r, rt)
+	case *ast.SelectorExpr:
+		str, t, err := c.expr(e.X)
+		if err != nil {
+			return "", TypeNone, err
+		}
+		if !t.isVec() {
+			return "", TypeNone, c.errorf(e.Pos(), "%s has no field %s", typeString(t), e.Sel.Name)
+		}
+		if !isSwizzle(e.Sel.Name, t.dim()) {
+			return "", TypeNone, c.errorf(e.Sel.Pos(), "invalid swizzle: %s", e.Sel.Name)
+		}
+		return str + "." + e.Sel.Name, vecType(len(e.Sel.Name)), nil
+	case *ast.IndexExpr:
+		str, t, err := c.expr(e.X)
+		if err != nil {
+			return "", TypeNone, err
+		}
+		idx, it, err := c.expr(e.Index)
+		if err != nil {
+			return "", TypeNone, err
+		}
+		idx, err = c.convert(e.Index, idx, it, TypeInt)
+		if err != nil {
+			return "", TypeNone, err
+		}
+		switch {
+		case t.isVec():
+			return str + "[" + idx + "]", TypeFloat, nil
+		case t.isMat():
+			return str + "[" + idx + "]", vecType(t.dim()), nil
+		}
+		return "", TypeNone, c.errorf(e.Pos(), "cannot index %s", typeString(t))
+	case *ast.CallExpr:
+		return c.call(e)
+	default:
+		return "", TypeNone, c.errorf(e.Pos(), "unsupported expression")
+	}
+}
+
+func isSwizzle(s string, dim int) bool {
+	if len(s) == 0 || len(s) > 4 {
+		return false
+	}
+	for _, set := range []string{"xyzw", "rgba", "stpq"} {
+		ok := true
+		for _, r := range s {
+			i := strings.IndexRune(set, r)
+			if i < 0 || i >= dim {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *compiler) binary(e ast.Node, op string, l string, lt Type, r string, rt Type) (string, Type, error) {
+	if lt == TypeNone || rt == TypeNone {
+		return "", TypeNone, c.errorf(e.Pos(), "the expression has no value")
+	}
+
+	// Convert untyped constants to the type of the other operand.
+	var err error
+	switch {
+	case lt.isUntyped() && rt.isUntyped():
+		t := typeUntypedInt
+		if lt == typeUntypedFloat || rt == typeUntypedFloat {
+			t = typeUntypedFloat
+			if l, err = c.convert(e, l, lt, TypeFloat); err != nil {
+				return "", TypeNone, err
+			}
+			if r, err = c.convert(e, r, rt, TypeFloat); err != nil {
+				return "", TypeNone, err
+			}
+		}
+		switch op {
+		case "+", "-", "*", "/":
+			return "(" + l + " " + op + " " + r + ")", t, nil
+		case "==", "!=", "<", "<=", ">", ">=":
+			return "(" + l + " " + op + " " + r + ")", TypeBool, nil
+		}
+		return "", TypeNone, c.errorf(e.Pos(), "invalid operation: operator %s not defined on %s", op, typeString(t))
+	case lt.isUntyped():
+		t := scalarOf(rt)
+		if t == TypeNone {
+			return "", TypeNone, c.errorf(e.Pos(), "invalid operation: mismatched types %s and %s", typeString(lt), rt)
+		}
+		if l, err = c.convert(e, l, lt, t); err != nil {
+			return "", TypeNone, err
+		}
+		lt = t
+	case rt.isUntyped():
+		t := scalarOf(lt)
+		if t == TypeNone {
+			return "", TypeNone, c.errorf(e.Pos(), "invalid operation: mismatched types %s and %s", lt, typeString(rt))
+		}
+		if r, err = c.convert(e, r, rt, t); err != nil {
+			return "", TypeNone, err
+		}
+		rt = t
+	}
+
+	switch op {
+	case "&&", "||":
+		if lt != TypeBool || rt != TypeBool {
+			return "", TypeNone, c.errorf(e.Pos(), "invalid operation: operator %s not defined on %s and %s", op, lt, rt)
+		}
+		return "(" + l + " " + op + " " + r + ")", TypeBool, nil
+	case "==", "!=":
+		if lt != rt {
+			return "", TypeNone, c.errorf(e.Pos(), "invalid operation: mismatched types %s and %s", lt, rt)
+		}
+		return "(" + l + " " + op + " " + r + ")", TypeBool, nil
+	case "<", "<=", ">", ">=":
+		if lt != rt || (lt != TypeInt && lt != TypeFloat) {
+			return "", TypeNone, c.errorf(e.Pos(), "invalid operation: operator %s not defined on %s and %s", op, lt, rt)
+		}
+		return "(" + l + " " + op + " " + r + ")", TypeBool, nil
+	case "+", "-", "*", "/":
+		t, ok := arithmeticType(op, lt, rt)
+		if !ok {
+			return "", TypeNone, c.errorf(e.Pos(), "invalid operation: mismatched types %s and %s", lt, rt)
+		}
+		return "(" + l + " " + op + " " + r + ")", t, nil
+	case "%":
+		if lt == TypeInt && rt == TypeInt {
+			return "", TypeNone, c.errorf(e.Pos(), "%% for int is not supported")
+		}
+		t, ok := arithmeticType(op, lt, rt)
+		if !ok || t == lt && lt != rt && rt.isVec() {
+			return "", TypeNone, c.errorf(e.Pos(), "invalid operation: mismatched types %s and %s", lt, rt)
+		}
+		return "mod(" + l + ", " + r + ")", t, nil
+	}
+	return "", TypeNone, c.errorf(e.Pos(), "%s is not supported", op)
+}
+
+// arithmeticType returns the result type of an arithmetic operation.
+func arithmeticType(op string, lt, rt Type) (Type, bool) {
+	if lt == TypeBool || rt == TypeBool {
+		return TypeNone, false
+	}
+	if lt == rt {
+		return lt, true
+	}
+	if lt == TypeFloat && (rt.isVec() || rt.isMat()) {
+		return rt, true
+	}
+	if rt == TypeFloat && (lt.isVec() || lt.isMat()) {
+		return lt, true
+	}
+	if op == "*" {
+		if lt.isMat() && rt.isVec() && lt.dim() == rt.dim() {
+			return rt, true
+		}
+		if lt.isVec() && rt.isMat() && lt.dim() == rt.dim() {
+			return lt, true
+		}
+	}
+	return TypeNone, false
+}
+
+func (c *compiler) call(e *ast.CallExpr) (string, Type, error) {
+	id, ok := e.Fun.(*ast.Ident)
+	if !ok {
+		return "", TypeNone, c.errorf(e.Pos(), "unsupported function call")
+	}
+	if e.Ellipsis.IsValid() {
+		return "", TypeNone, c.errorf(e.Ellipsis, "variadic calls are not supported")
+	}
+
+	args := make([]string, len(e.Args))
+	types := make([]Type, len(e.Args))
+	for i, a := range e.Args {
+		str, t, err := c.expr(a)
+		if err != nil {
+			return "", TypeNone, err
+		}
+		if t == TypeNone {
+			return "", TypeNone, c.errorf(a.Pos(), "the expression has no value")
+		}
+		args[i] = str
+		types[i] = t
+	}
+
+	// Conversions and constructors
+	if t, ok := typeNames[id.Name]; ok {
+		if len(args) == 0 {
+			return "", TypeNone, c.errorf(e.Pos(), "not enough arguments to %s", t)
+		}
+		n := 0
+		for i := range args {
+			if types[i].isUntyped() {
+				to := TypeFloat
+				if t == TypeInt || t == TypeBool {
+					to = defaultType(types[i])
+				}
+				str, err := c.convert(e.Args[i], args[i], types[i], to)
+				if err != nil {
+					return "", TypeNone, err
+				}
+				args[i] = str
+				types[i] = to
+			}
+			if types[i].isMat() {
+				n += types[i].FloatNum()
+			} else {
+				n += types[i].dim()
+			}
+		}
+		if len(args) > 1 && (t.isVec() && n != t.dim() || t.isMat() && n != t.FloatNum()) {
+			return "", TypeNone, c.errorf(e.Pos(), "wrong number of components for %s", t)
+		}
+		if len(args) > 1 && !t.isVec() && !t.isMat() {
+			return "", TypeNone, c.errorf(e.Pos(), "too many arguments to conversion to %s", t)
+		}
+		return t.String() + "(" + strings.Join(args, ", ") + ")", t, nil
+	}
+
+	// User-defined functions
+	if f, ok := c.funcs[id.Name]; ok {
+		if len(args) != len(f.params) {
+			return "", TypeNone, c.errorf(e.Pos(), "wrong number of arguments in call to %s", id.Name)
+		}
+		for i := range args {
+			str, err := c.convert(e.Args[i], args[i], types[i], f.params[i])
+			if err != nil {
+				return "", TypeNone, err
+			}
+			args[i] = str
+		}
+		return "f_" + id.Name + "(" + strings.Join(args, ", ") + ")", f.result, nil
+	}
+
+	b, ok := builtinFuncs[id.Name]
+	if !ok {
+		return "", TypeNone, c.errorf(e.Pos(), "undefined: %s", id.Name)
+	}
+	if len(args) < b.minArgs || b.maxArgs < len(args) {
+		return "", TypeNone, c.errorf(e.Pos(), "wrong number of arguments in call to %s", id.Name)
+	}
+
+	// Arguments of built-in functions are floats or float vectors.
+	t := TypeFloat
+	for i := range args {
+		str, err := c.convert(e.Args[i], args[i], types[i], defaultFloat(types[i]))
+		if err != nil {
+			return "", TypeNone, err
+		}
+		args[i] = str
+		types[i] = defaultFloat(types[i])
+		if !types[i].isFloatBased() || types[i].isMat() {
+			return "", TypeNone, c.errorf(e.Args[i].Pos(), "cannot use %s as an argument of %s", types[i], id.Name)
+		}
+		if types[i] != TypeFloat {
+			if t != TypeFloat && t != types[i] {
+				return "", TypeNone, c.errorf(e.Args[i].Pos(), "mismatched types %s and %s in call to %s", t, types[i], id.Name)
+			}
+			t = types[i]
+		}
+	}
+
+	switch b.kind {
+	case builtinGeneric:
+	case builtinFloat:
+		t = TypeFloat
+	case builtinVec3:
+		if types[0] != TypeVec3 || types[1] != TypeVec3 {
+			return "", TypeNone, c.errorf(e.Pos(), "the arguments of %s must be vec3", id.Name)
+		}
+		t = TypeVec3
+	case builtinTexture0At:
+		if types[0] != TypeVec2 {
+			return "", TypeNone, c.errorf(e.Pos(), "the argument of %s must be vec2", id.Name)
+		}
+		t = TypeVec4
+	case builtinTexture0Size:
+		return b.glsl, TypeVec2, nil
+	case builtinTexture0Region:
+		return b.glsl, TypeVec4, nil
+	}
+	return b.glsl + "(" + strings.Join(args, ", ") + ")", t, nil
+}
+
+// defaultFloat returns the float type for an untyped constant, or t itself otherwise.
+func defaultFloat(t Type) Type {
+	if t.isUntyped() {
+		return TypeFloat
+	}
+	return t
+}
@@ -0,0 +1,392 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shader provides a compiler of the shading language for custom shaders.
+//
+// The shading language is a subset of Go. A shader program is a Go source file that has the entry point function
+// Fragment:
+//
+//     package main
+//
+//     var Time float
+//
+//     func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+//         return texture0At(texCoord) * color
+//     }
+//
+// Package-level variables are uniform variables. The program is compiled into GLSL.
+package shader
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// Uniform represents a uniform variable of a shader program.
+type Uniform struct {
+	Name string
+	Type Type
+}
+
+// Program is a compiled shader program.
+type Program struct {
+	// Uniforms is the uniform variables in the declared order.
+	Uniforms []Uniform
+
+	glslFragment string
+}
+
+// GLSLFragmentShader returns the GLSL source of the fragment shader.
+//
+// The fragment shader uses the following inputs:
+//
+//     uniform sampler2D texture;
+//     uniform vec2 source_size;
+//     varying vec2 varying_position;
+//     varying vec2 varying_tex;
+//     varying vec4 varying_tex_region;
+//     varying vec4 varying_color_scale;
+func (p *Program) GLSLFragmentShader() string {
+	return p.glslFragment
+}
+
+// GLSLUniformName returns the name of the i-th uniform variable in the GLSL source.
+func (p *Program) GLSLUniformName(i int) string {
+	return "u_" + p.Uniforms[i].Name
+}
+
+const glslHeader = `#if defined(GL_ES)
+#if defined(GL_FRAGMENT_PRECISION_HIGH)
+precision highp float;
+#else
+precision mediump float;
+#endif
+#else
+#define lowp
+#define mediump
+#define highp
+#endif
+
+uniform sampler2D texture;
+uniform vec2 source_size;
+varying vec2 varying_position;
+varying vec2 varying_tex;
+varying vec4 varying_tex_region;
+varying vec4 varying_color_scale;
+
+vec4 texture0At(vec2 pos) {
+  if (pos.x < varying_tex_region[0] ||
+    pos.y < varying_tex_region[1] ||
+    (varying_tex_region[2] - 1.0 / 512.0 / source_size.x) <= pos.x ||
+    (varying_tex_region[3] - 1.0 / 512.0 / source_size.y) <= pos.y) {
+    return vec4(0.0);
+  }
+  return texture2D(texture, pos);
+}
+`
+
+// Compile compiles the given shader program.
+func Compile(src []byte) (*Program, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("shader: %v", err)
+	}
+
+	c := &compiler{
+		fset:     fset,
+		uniforms: map[string]Type{},
+		consts:   map[string]constant{},
+		funcs:    map[string]*function{},
+	}
+	p, err := c.compile(f)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type constant struct {
+	expr string
+	typ  Type
+}
+
+type function struct {
+	params []Type
+	result Type
+}
+
+type compiler struct {
+	fset *token.FileSet
+
+	uniforms map[string]Type
+	consts   map[string]constant
+	funcs    map[string]*function
+
+	// scopes is the stack of the local variables.
+	scopes []map[string]Type
+
+	// result is the result type of the current function.
+	result Type
+
+	lines []string
+	depth int
+}
+
+func (c *compiler) errorf(pos token.Pos, format string, args ...interface{}) error {
+	return fmt.Errorf("shader: %s: %s", c.fset.Position(pos), fmt.Sprintf(format, args...))
+}
+
+func (c *compiler) emit(format string, args ...interface{}) {
+	c.lines = append(c.lines, strings.Repeat("  ", c.depth)+fmt.Sprintf(format, args...))
+}
+
+func (c *compiler) compile(f *ast.File) (*Program, error) {
+	p := &Program{}
+	var funcs []*ast.FuncDecl
+
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				switch d.Tok {
+				case token.VAR:
+					us, err := c.uniformSpec(s.(*ast.ValueSpec))
+					if err != nil {
+						return nil, err
+					}
+					p.Uniforms = append(p.Uniforms, us...)
+				case token.CONST:
+					if err := c.constSpec(s.(*ast.ValueSpec)); err != nil {
+						return nil, err
+					}
+				default:
+					return nil, c.errorf(d.Pos(), "%s declarations are not supported", d.Tok)
+				}
+			}
+		case *ast.FuncDecl:
+			if err := c.declareFunc(d); err != nil {
+				return nil, err
+			}
+			funcs = append(funcs, d)
+		}
+	}
+
+	e, ok := c.funcs["Fragment"]
+	if !ok {
+		return nil, fmt.Errorf("shader: the entry point function Fragment is not defined")
+	}
+	if len(e.params) != 3 || e.params[0] != TypeVec2 || e.params[1] != TypeVec2 || e.params[2] != TypeVec4 || e.result != TypeVec4 {
+		return nil, fmt.Errorf("shader: the entry point function must be func Fragment(position vec2, texCoord vec2, color vec4) vec4")
+	}
+
+	c.lines = append(c.lines, glslHeader)
+	for i, u := range p.Uniforms {
+		c.emit("uniform %s %s;", u.Type, p.GLSLUniformName(i))
+	}
+	c.emit("")
+	// Declare the prototypes first so that the functions can be called regardless of the order.
+	for _, d := range funcs {
+		c.emit("%s;", c.funcSignature(d))
+	}
+	for _, d := range funcs {
+		c.emit("")
+		if err := c.funcBody(d); err != nil {
+			return nil, err
+		}
+	}
+	c.emit("")
+	c.emit("void main(void) {")
+	c.emit("  gl_FragColor = f_Fragment(varying_position, varying_tex, varying_color_scale);")
+	c.emit("}")
+
+	p.glslFragment = strings.Join(c.lines, "\n") + "\n"
+	return p, nil
+}
+
+func (c *compiler) isDeclared(name string) bool {
+	if _, ok := c.uniforms[name]; ok {
+		return true
+	}
+	if _, ok := c.consts[name]; ok {
+		return true
+	}
+	if _, ok := c.funcs[name]; ok {
+		return true
+	}
+	return false
+}
+
+func (c *compiler) checkName(id *ast.Ident) error {
+	if id.Name == "_" {
+		return c.errorf(id.Pos(), "blank identifiers are not supported")
+	}
+	if _, ok := typeNames[id.Name]; ok {
+		return c.errorf(id.Pos(), "%s is a reserved name", id.Name)
+	}
+	if _, ok := builtinFuncs[id.Name]; ok {
+		return c.errorf(id.Pos(), "%s is a reserved name", id.Name)
+	}
+	return nil
+}
+
+func (c *compiler) uniformSpec(s *ast.ValueSpec) ([]Uniform, error) {
+	if s.Type == nil {
+		return nil, c.errorf(s.Pos(), "a uniform variable must have an explicit type")
+	}
+	if len(s.Values) > 0 {
+		return nil, c.errorf(s.Pos(), "a uniform variable cannot have an initial value")
+	}
+	t, ok := typeFromExpr(s.Type)
+	if !ok {
+		return nil, c.errorf(s.Type.Pos(), "invalid type")
+	}
+	switch t {
+	case TypeFloat, TypeVec2, TypeVec3, TypeVec4, TypeMat4:
+	default:
+		return nil, c.errorf(s.Type.Pos(), "a uniform variable of %s is not supported", t)
+	}
+
+	var us []Uniform
+	for _, n := range s.Names {
+		if err := c.checkName(n); err != nil {
+			return nil, err
+		}
+		if c.isDeclared(n.Name) {
+			return nil, c.errorf(n.Pos(), "%s redeclared", n.Name)
+		}
+		c.uniforms[n.Name] = t
+		us = append(us, Uniform{Name: n.Name, Type: t})
+	}
+	return us, nil
+}
+
+func (c *compiler) constSpec(s *ast.ValueSpec) error {
+	if len(s.Names) != len(s.Values) {
+		return c.errorf(s.Pos(), "a constant must have a value")
+	}
+	for i, n := range s.Names {
+		if err := c.checkName(n); err != nil {
+			return err
+		}
+		if c.isDeclared(n.Name) {
+			return c.errorf(n.Pos(), "%s redeclared", n.Name)
+		}
+		expr, t, err := c.expr(s.Values[i])
+		if err != nil {
+			return err
+		}
+		if s.Type != nil {
+			tt, ok := typeFromExpr(s.Type)
+			if !ok {
+				return c.errorf(s.Type.Pos(), "invalid type")
+			}
+			expr, err = c.convert(s.Values[i], expr, t, tt)
+			if err != nil {
+				return err
+			}
+			t = tt
+		}
+		c.consts[n.Name] = constant{expr: expr, typ: t}
+	}
+	return nil
+}
+
+func (c *compiler) declareFunc(d *ast.FuncDecl) error {
+	if d.Recv != nil {
+		return c.errorf(d.Pos(), "methods are not supported")
+	}
+	if err := c.checkName(d.Name); err != nil {
+		return err
+	}
+	if c.isDeclared(d.Name.Name) {
+		return c.errorf(d.Name.Pos(), "%s redeclared", d.Name.Name)
+	}
+
+	f := &function{}
+	for _, p := range d.Type.Params.List {
+		t, ok := typeFromExpr(p.Type)
+		if !ok {
+			return c.errorf(p.Type.Pos(), "invalid type")
+		}
+		for range p.Names {
+			f.params = append(f.params, t)
+		}
+	}
+	if d.Type.Results != nil {
+		if d.Type.Results.NumFields() > 1 {
+			return c.errorf(d.Type.Results.Pos(), "multiple results are not supported")
+		}
+		r := d.Type.Results.List[0]
+		if len(r.Names) > 0 {
+			return c.errorf(r.Pos(), "named results are not supported")
+		}
+		t, ok := typeFromExpr(r.Type)
+		if !ok {
+			return c.errorf(r.Type.Pos(), "invalid type")
+		}
+		f.result = t
+	}
+	c.funcs[d.Name.Name] = f
+	return nil
+}
+
+func (c *compiler) funcSignature(d *ast.FuncDecl) string {
+	f := c.funcs[d.Name.Name]
+	var params []string
+	i := 0
+	for _, p := range d.Type.Params.List {
+		for _, n := range p.Names {
+			params = append(params, fmt.Sprintf("in %s l_%s", f.params[i], n.Name))
+			i++
+		}
+	}
+	r := "void"
+	if f.result != TypeNone {
+		r = f.result.String()
+	}
+	if len(params) == 0 {
+		params = []string{"void"}
+	}
+	return fmt.Sprintf("%s f_%s(%s)", r, d.Name.Name, strings.Join(params, ", "))
+}
+
+func (c *compiler) funcBody(d *ast.FuncDecl) error {
+	f := c.funcs[d.Name.Name]
+	c.result = f.result
+
+	params := map[string]Type{}
+	i := 0
+	for _, p := range d.Type.Params.List {
+		for _, n := range p.Names {
+			if err := c.checkName(n); err != nil {
+				return err
+			}
+			params[n.Name] = f.params[i]
+			i++
+		}
+	}
+
+	c.emit("%s {", c.funcSignature(d))
+	c.scopes = append(c.scopes, params)
+	if err := c.block(d.Body); err != nil {
+		return err
+	}
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	c.emit("}")
+	return nil
+}
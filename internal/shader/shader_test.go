@@ -0,0 +1,164 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shader_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/internal/shader"
+)
+
+func TestCompile(t *testing.T) {
+	const src = `package main
+
+const Amplitude = 4
+
+var Time float
+var Cursor vec2
+
+func wave(y float) float {
+	return sin(y/16 + Time) * Amplitude
+}
+
+func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+	size := texture0Size()
+	pos := texCoord + vec2(wave(position.y), 0) / size
+	c := texture0At(pos)
+	var sum vec4
+	for i := 0; i < 4; i++ {
+		if i == 2 {
+			continue
+		}
+		sum += texture0At(pos + vec2(float(i), 0) / size)
+	}
+	if d := distance(position, Cursor); d < 10 {
+		c.rgb = 1 - c.rgb
+	} else {
+		c *= 0.5
+	}
+	return (c + sum/4) * color
+}
+`
+	p, err := Compile([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Uniform{
+		{Name: "Time", Type: TypeFloat},
+		{Name: "Cursor", Type: TypeVec2},
+	}
+	if len(p.Uniforms) != len(want) {
+		t.Fatalf("len(p.Uniforms): got: %d, want: %d", len(p.Uniforms), len(want))
+	}
+	for i := range want {
+		if p.Uniforms[i] != want[i] {
+			t.Errorf("p.Uniforms[%d]: got: %v, want: %v", i, p.Uniforms[i], want[i])
+		}
+	}
+	if got, want := p.GLSLUniformName(1), "u_Cursor"; got != want {
+		t.Errorf("p.GLSLUniformName(1): got: %s, want: %s", got, want)
+	}
+
+	glsl := p.GLSLFragmentShader()
+	for _, l := range []string{
+		"uniform float u_Time;",
+		"uniform vec2 u_Cursor;",
+		"float f_wave(in float l_y);",
+		"return (sin(((l_y / 16.0) + u_Time)) * 4.0);",
+		"vec2 l_size = source_size;",
+		"vec4 l_sum = vec4(0.0);",
+		"for (int l_i = 0; (l_i < 4); l_i++) {",
+		"l_c.rgb = (1.0 - l_c.rgb);",
+		"l_c = (l_c * 0.5);",
+		"gl_FragColor = f_Fragment(varying_position, varying_tex, varying_color_scale);",
+	} {
+		if !strings.Contains(glsl, l) {
+			t.Errorf("the GLSL source must contain %q:\n%s", l, glsl)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	cases := []struct {
+		Name string
+		Src  string
+	}{
+		{
+			Name: "no entry point",
+			Src:  `package main`,
+		},
+		{
+			Name: "invalid entry point",
+			Src: `package main
+
+func Fragment(position vec2) vec4 {
+	return vec4(0)
+}`,
+		},
+		{
+			Name: "mismatched types",
+			Src: `package main
+
+func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+	return position + color
+}`,
+		},
+		{
+			Name: "assign to uniform",
+			Src: `package main
+
+var Time float
+
+func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+	Time = 1
+	return color
+}`,
+		},
+		{
+			Name: "int and float",
+			Src: `package main
+
+func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+	i := 1
+	return color * i
+}`,
+		},
+		{
+			Name: "undefined",
+			Src: `package main
+
+func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+	return foo(color)
+}`,
+		},
+		{
+			Name: "import",
+			Src: `package main
+
+import "fmt"
+
+func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+	return color
+}`,
+		},
+	}
+	for _, c := range cases {
+		if _, err := Compile([]byte(c.Src)); err == nil {
+			t.Errorf("%s: Compile must return an error", c.Name)
+		}
+	}
+}
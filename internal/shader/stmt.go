@@ -0,0 +1,366 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shader
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+func (c *compiler) lookupLocal(name string) (Type, bool) {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if t, ok := c.scopes[i][name]; ok {
+			return t, true
+		}
+	}
+	return TypeNone, false
+}
+
+func (c *compiler) declareLocal(id *ast.Ident, t Type) error {
+	if err := c.checkName(id); err != nil {
+		return err
+	}
+	s := c.scopes[len(c.scopes)-1]
+	if _, ok := s[id.Name]; ok {
+		return c.errorf(id.Pos(), "%s redeclared in this block", id.Name)
+	}
+	s[id.Name] = t
+	return nil
+}
+
+func (c *compiler) block(b *ast.BlockStmt) error {
+	c.depth++
+	c.scopes = append(c.scopes, map[string]Type{})
+	for _, s := range b.List {
+		if err := c.stmt(s); err != nil {
+			return err
+		}
+	}
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	c.depth--
+	return nil
+}
+
+func (c *compiler) stmt(s ast.Stmt) error {
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		c.emit("{")
+		if err := c.block(s); err != nil {
+			return err
+		}
+		c.emit("}")
+	case *ast.AssignStmt, *ast.IncDecStmt, *ast.ExprStmt:
+		str, err := c.simpleStmt(s)
+		if err != nil {
+			return err
+		}
+		c.emit("%s;", str)
+	case *ast.DeclStmt:
+		d, ok := s.Decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.VAR {
+			return c.errorf(s.Pos(), "only var declarations are supported in a function")
+		}
+		for _, spec := range d.Specs {
+			if err := c.varSpec(spec.(*ast.ValueSpec)); err != nil {
+				return err
+			}
+		}
+	case *ast.ReturnStmt:
+		if c.result == TypeNone {
+			if len(s.Results) > 0 {
+				return c.errorf(s.Pos(), "too many return values")
+			}
+			c.emit("return;")
+			return nil
+		}
+		if len(s.Results) != 1 {
+			return c.errorf(s.Pos(), "the number of return values must be 1")
+		}
+		str, t, err := c.expr(s.Results[0])
+		if err != nil {
+			return err
+		}
+		str, err = c.convert(s.Results[0], str, t, c.result)
+		if err != nil {
+			return err
+		}
+		c.emit("return %s;", str)
+	case *ast.IfStmt:
+		if s.Init != nil {
+			// Wrap the statement with a block for the scope of the initial statement.
+			c.emit("{")
+			c.depth++
+			c.scopes = append(c.scopes, map[string]Type{})
+			if err := c.stmt(s.Init); err != nil {
+				return err
+			}
+		}
+		if err := c.ifStmt(s, "if"); err != nil {
+			return err
+		}
+		if s.Init != nil {
+			c.scopes = c.scopes[:len(c.scopes)-1]
+			c.depth--
+			c.emit("}")
+		}
+	case *ast.ForStmt:
+		return c.forStmt(s)
+	case *ast.BranchStmt:
+		if s.Label != nil {
+			return c.errorf(s.Pos(), "labels are not supported")
+		}
+		switch s.Tok {
+		case token.BREAK:
+			c.emit("break;")
+		case token.CONTINUE:
+			c.emit("continue;")
+		default:
+			return c.errorf(s.Pos(), "%s is not supported", s.Tok)
+		}
+	case *ast.EmptyStmt:
+	default:
+		return c.errorf(s.Pos(), "unsupported statement")
+	}
+	return nil
+}
+
+func (c *compiler) ifStmt(s *ast.IfStmt, keyword string) error {
+	cond, t, err := c.expr(s.Cond)
+	if err != nil {
+		return err
+	}
+	if t != TypeBool {
+		return c.errorf(s.Cond.Pos(), "non-bool %s used as if condition", t)
+	}
+	c.emit("%s (%s) {", keyword, cond)
+	if err := c.block(s.Body); err != nil {
+		return err
+	}
+	switch e := s.Else.(type) {
+	case nil:
+		c.emit("}")
+	case *ast.IfStmt:
+		if e.Init != nil {
+			c.emit("} else {")
+			c.depth++
+			if err := c.stmt(e); err != nil {
+				return err
+			}
+			c.depth--
+			c.emit("}")
+			return nil
+		}
+		return c.ifStmt(e, "} else if")
+	case *ast.BlockStmt:
+		c.emit("} else {")
+		if err := c.block(e); err != nil {
+			return err
+		}
+		c.emit("}")
+	}
+	return nil
+}
+
+func (c *compiler) forStmt(s *ast.ForStmt) error {
+	// The scope for the initial statement.
+	c.scopes = append(c.scopes, map[string]Type{})
+	defer func() {
+		c.scopes = c.scopes[:len(c.scopes)-1]
+	}()
+
+	var init, cond, post string
+	if s.Init != nil {
+		a, ok := s.Init.(*ast.AssignStmt)
+		if !ok || a.Tok != token.DEFINE {
+			return c.errorf(s.Init.Pos(), "the initial statement of a for loop must be a short variable declaration")
+		}
+		str, err := c.simpleStmt(a)
+		if err != nil {
+			return err
+		}
+		init = str
+	}
+	if s.Cond != nil {
+		str, t, err := c.expr(s.Cond)
+		if err != nil {
+			return err
+		}
+		if t != TypeBool {
+			return c.errorf(s.Cond.Pos(), "non-bool %s used as for condition", t)
+		}
+		cond = str
+	}
+	if s.Post != nil {
+		str, err := c.simpleStmt(s.Post)
+		if err != nil {
+			return err
+		}
+		post = str
+	}
+	c.emit("for (%s; %s; %s) {", init, cond, post)
+	if err := c.block(s.Body); err != nil {
+		return err
+	}
+	c.emit("}")
+	return nil
+}
+
+func (c *compiler) varSpec(s *ast.ValueSpec) error {
+	if len(s.Values) > 0 && len(s.Values) != len(s.Names) {
+		return c.errorf(s.Pos(), "assignment mismatch: %d variables but %d values", len(s.Names), len(s.Values))
+	}
+	var t Type
+	if s.Type != nil {
+		var ok bool
+		t, ok = typeFromExpr(s.Type)
+		if !ok {
+			return c.errorf(s.Type.Pos(), "invalid type")
+		}
+	}
+	for i, n := range s.Names {
+		if len(s.Values) == 0 {
+			if err := c.declareLocal(n, t); err != nil {
+				return err
+			}
+			c.emit("%s l_%s = %s;", t, n.Name, t.zeroValue())
+			continue
+		}
+		str, vt, err := c.expr(s.Values[i])
+		if err != nil {
+			return err
+		}
+		lt := t
+		if lt == TypeNone {
+			lt = defaultType(vt)
+		}
+		str, err = c.convert(s.Values[i], str, vt, lt)
+		if err != nil {
+			return err
+		}
+		if err := c.declareLocal(n, lt); err != nil {
+			return err
+		}
+		c.emit("%s l_%s = %s;", lt, n.Name, str)
+	}
+	return nil
+}
+
+// simpleStmt returns the GLSL statement without the trailing semicolon.
+func (c *compiler) simpleStmt(s ast.Stmt) (string, error) {
+	switch s := s.(type) {
+	case *ast.AssignStmt:
+		if len(s.Lhs) != 1 || len(s.Rhs) != 1 {
+			return "", c.errorf(s.Pos(), "multiple assignment is not supported")
+		}
+		rhs, rt, err := c.expr(s.Rhs[0])
+		if err != nil {
+			return "", err
+		}
+		if s.Tok == token.DEFINE {
+			id, ok := s.Lhs[0].(*ast.Ident)
+			if !ok {
+				return "", c.errorf(s.Lhs[0].Pos(), "non-name on left side of :=")
+			}
+			t := defaultType(rt)
+			if t == TypeNone {
+				return "", c.errorf(s.Rhs[0].Pos(), "the expression has no value")
+			}
+			rhs, err = c.convert(s.Rhs[0], rhs, rt, t)
+			if err != nil {
+				return "", err
+			}
+			if err := c.declareLocal(id, t); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s l_%s = %s", t, id.Name, rhs), nil
+		}
+
+		lhs, lt, err := c.lvalue(s.Lhs[0])
+		if err != nil {
+			return "", err
+		}
+		switch s.Tok {
+		case token.ASSIGN:
+			rhs, err = c.convert(s.Rhs[0], rhs, rt, lt)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s = %s", lhs, rhs), nil
+		case token.ADD_ASSIGN, token.SUB_ASSIGN, token.MUL_ASSIGN, token.QUO_ASSIGN, token.REM_ASSIGN:
+			op := strings.TrimSuffix(s.Tok.String(), "=")
+			str, t, err := c.binary(s, op, lhs, lt, rhs, rt)
+			if err != nil {
+				return "", err
+			}
+			if t != lt {
+				return "", c.errorf(s.Pos(), "cannot assign %s to %s", t, lt)
+			}
+			return fmt.Sprintf("%s = %s", lhs, str), nil
+		default:
+			return "", c.errorf(s.Pos(), "%s is not supported", s.Tok)
+		}
+	case *ast.IncDecStmt:
+		lhs, lt, err := c.lvalue(s.X)
+		if err != nil {
+			return "", err
+		}
+		if lt != TypeInt && lt != TypeFloat {
+			return "", c.errorf(s.Pos(), "invalid operation: %s of %s", s.Tok, lt)
+		}
+		return lhs + s.Tok.String(), nil
+	case *ast.ExprStmt:
+		if _, ok := s.X.(*ast.CallExpr); !ok {
+			return "", c.errorf(s.Pos(), "the expression is evaluated but not used")
+		}
+		str, _, err := c.expr(s.X)
+		if err != nil {
+			return "", err
+		}
+		return str, nil
+	default:
+		return "", c.errorf(s.Pos(), "unsupported statement")
+	}
+}
+
+// lvalue returns the GLSL expression and its type of an assignable expression.
+func (c *compiler) lvalue(e ast.Expr) (string, Type, error) {
+	switch e := e.(type) {
+	case *ast.Ident:
+		t, ok := c.lookupLocal(e.Name)
+		if !ok {
+			if c.isDeclared(e.Name) {
+				return "", TypeNone, c.errorf(e.Pos(), "cannot assign to %s", e.Name)
+			}
+			return "", TypeNone, c.errorf(e.Pos(), "undefined: %s", e.Name)
+		}
+		return "l_" + e.Name, t, nil
+	case *ast.SelectorExpr:
+		if _, _, err := c.lvalue(e.X); err != nil {
+			return "", TypeNone, err
+		}
+		return c.expr(e)
+	case *ast.IndexExpr:
+		if _, _, err := c.lvalue(e.X); err != nil {
+			return "", TypeNone, err
+		}
+		return c.expr(e)
+	case *ast.ParenExpr:
+		return c.lvalue(e.X)
+	default:
+		return "", TypeNone, c.errorf(e.Pos(), "cannot assign to the expression")
+	}
+}
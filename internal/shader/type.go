@@ -0,0 +1,169 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shader
+
+import (
+	"go/ast"
+)
+
+// Type represents a type in the shading language.
+type Type int
+
+const (
+	TypeNone Type = iota
+	TypeBool
+	TypeInt
+	TypeFloat
+	TypeVec2
+	TypeVec3
+	TypeVec4
+	TypeMat2
+	TypeMat3
+	TypeMat4
+)
+
+// Untyped constants are typed only when they are used in a typed context, like Go.
+const (
+	typeUntypedInt Type = 100 + iota
+	typeUntypedFloat
+)
+
+var typeNames = map[string]Type{
+	"bool":  TypeBool,
+	"int":   TypeInt,
+	"float": TypeFloat,
+	"vec2":  TypeVec2,
+	"vec3":  TypeVec3,
+	"vec4":  TypeVec4,
+	"mat2":  TypeMat2,
+	"mat3":  TypeMat3,
+	"mat4":  TypeMat4,
+}
+
+func (t Type) String() string {
+	switch t {
+	case TypeBool:
+		return "bool"
+	case TypeInt, typeUntypedInt:
+		return "int"
+	case TypeFloat, typeUntypedFloat:
+		return "float"
+	case TypeVec2:
+		return "vec2"
+	case TypeVec3:
+		return "vec3"
+	case TypeVec4:
+		return "vec4"
+	case TypeMat2:
+		return "mat2"
+	case TypeMat3:
+		return "mat3"
+	case TypeMat4:
+		return "mat4"
+	default:
+		return "?"
+	}
+}
+
+// FloatNum returns the number of float values of the type.
+//
+// FloatNum returns 0 if the type does not consist of float values.
+func (t Type) FloatNum() int {
+	switch t {
+	case TypeFloat:
+		return 1
+	case TypeVec2:
+		return 2
+	case TypeVec3:
+		return 3
+	case TypeVec4, TypeMat2:
+		return 4
+	case TypeMat3:
+		return 9
+	case TypeMat4:
+		return 16
+	default:
+		return 0
+	}
+}
+
+func (t Type) isUntyped() bool {
+	return t == typeUntypedInt || t == typeUntypedFloat
+}
+
+func (t Type) isVec() bool {
+	return t == TypeVec2 || t == TypeVec3 || t == TypeVec4
+}
+
+func (t Type) isMat() bool {
+	return t == TypeMat2 || t == TypeMat3 || t == TypeMat4
+}
+
+// isFloatBased reports whether the type consists of float values.
+func (t Type) isFloatBased() bool {
+	return t == TypeFloat || t == typeUntypedFloat || t.isVec() || t.isMat()
+}
+
+// dim returns the number of components of a vector, or the number of columns of a matrix.
+func (t Type) dim() int {
+	switch t {
+	case TypeVec2, TypeMat2:
+		return 2
+	case TypeVec3, TypeMat3:
+		return 3
+	case TypeVec4, TypeMat4:
+		return 4
+	default:
+		return 1
+	}
+}
+
+func vecType(n int) Type {
+	switch n {
+	case 1:
+		return TypeFloat
+	case 2:
+		return TypeVec2
+	case 3:
+		return TypeVec3
+	case 4:
+		return TypeVec4
+	default:
+		return TypeNone
+	}
+}
+
+// zeroValue returns the GLSL expression of the zero value of the type.
+func (t Type) zeroValue() string {
+	switch t {
+	case TypeBool:
+		return "false"
+	case TypeInt:
+		return "0"
+	case TypeFloat:
+		return "0.0"
+	default:
+		return t.String() + "(0.0)"
+	}
+}
+
+func typeFromExpr(e ast.Expr) (Type, bool) {
+	id, ok := e.(*ast.Ident)
+	if !ok {
+		return TypeNone, false
+	}
+	t, ok := typeNames[id.Name]
+	return t, ok
+}
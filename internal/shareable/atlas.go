@@ -0,0 +1,148 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/internal/restorable"
+)
+
+// occupancyColors is the palette to fill the allocated regions in the occupancy images.
+var occupancyColors = []color.RGBA{
+	{0xe0, 0x60, 0x60, 0xff},
+	{0x60, 0xe0, 0x60, 0xff},
+	{0x60, 0x80, 0xe0, 0xff},
+	{0xe0, 0xe0, 0x60, 0xff},
+	{0xe0, 0x60, 0xe0, 0xff},
+	{0x60, 0xe0, 0xe0, 0xff},
+}
+
+// DumpAtlases dumps the shared textures (atlases) to the specified directory.
+//
+// For each atlas, DumpAtlases writes the texture contents as atlas_N.png, and an image showing the allocated
+// regions as atlas_N_occupancy.png. DumpAtlases also writes the summary of the sizes and the occupancies as
+// atlases.txt.
+//
+// This is for debugging usage.
+func DumpAtlases(dir string) error {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	summary, err := os.Create(filepath.Join(dir, "atlases.txt"))
+	if err != nil {
+		return err
+	}
+	defer summary.Close()
+
+	for idx, b := range theBackends {
+		if b.page == nil {
+			continue
+		}
+		if err := b.restorable.Dump(filepath.Join(dir, fmt.Sprintf("atlas_%d.png", idx))); err != nil {
+			return err
+		}
+
+		size := b.page.Size()
+		rs := b.page.UsedRegions()
+		if err := dumpOccupancy(filepath.Join(dir, fmt.Sprintf("atlas_%d_occupancy.png", idx)), size, rs); err != nil {
+			return err
+		}
+
+		used := 0
+		for _, r := range rs {
+			used += r.Dx() * r.Dy()
+		}
+		occupancy := 100 * float64(used) / float64(size*size)
+		if _, err := fmt.Fprintf(summary, "atlas_%d: %dx%d, %d images, %.1f%% used\n", idx, size, size, len(rs), occupancy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dumpOccupancy(path string, size int, regions []image.Rectangle) error {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0x20
+		img.Pix[i+1] = 0x20
+		img.Pix[i+2] = 0x20
+		img.Pix[i+3] = 0xff
+	}
+
+	for idx, r := range regions {
+		clr := occupancyColors[idx%len(occupancyColors)]
+		border := color.RGBA{clr.R / 2, clr.G / 2, clr.B / 2, 0xff}
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if x == r.Min.X || y == r.Min.Y || x == r.Max.X-1 || y == r.Max.Y-1 {
+					img.SetRGBA(x, y, border)
+					continue
+				}
+				img.SetRGBA(x, y, clr)
+			}
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
+
+// TextureInfo represents the information of a texture for debugging.
+type TextureInfo struct {
+	restorable.ImageInfo
+
+	// Atlas reports whether the texture is an atlas shared by multiple images.
+	Atlas bool
+
+	// Images is the number of the images on the atlas. Images is 0 if the texture is not an atlas.
+	Images int
+}
+
+// TextureInfos returns the information of all the current textures.
+//
+// This is for debugging usage.
+func TextureInfos() []TextureInfo {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	atlases := map[int]int{}
+	for _, b := range theBackends {
+		if b.page == nil {
+			continue
+		}
+		atlases[b.restorable.ID()] = len(b.page.UsedRegions())
+	}
+
+	var infos []TextureInfo
+	for _, i := range restorable.ImageInfos() {
+		n, ok := atlases[i.ID]
+		infos = append(infos, TextureInfo{
+			ImageInfo: i,
+			Atlas:     ok,
+			Images:    n,
+		})
+	}
+	return infos
+}
@@ -16,6 +16,7 @@ package shareable
 
 import (
 	"fmt"
+	"image"
 	"image/color"
 	"runtime"
 	"sync"
@@ -39,6 +40,22 @@ var (
 	maxSize = 0
 )
 
+// paddingSize is the size of the padding in pixels around each image on a shared texture.
+//
+// The edge pixels of an image are extruded into the padding so that sampling across the edges, e.g., with linear
+// filtering or scaling, never picks up the pixels of the neighboring images.
+var paddingSize = 1
+
+// SetPadding sets the size of the padding in pixels around each image on a shared texture.
+//
+// SetPadding must be called before the game starts.
+func SetPadding(padding int) {
+	if padding < 0 {
+		panic(fmt.Sprintf("shareable: padding must be non-negative but %d", padding))
+	}
+	paddingSize = padding
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -159,6 +176,15 @@ type Image struct {
 	volatile bool
 	screen   bool
 
+	// samples is the number of the samples per pixel for multisampling. 0 means the image is not multisampled.
+	samples int
+
+	// float indicates whether the image has floating-point values.
+	float bool
+
+	// alpha indicates whether the image has only alpha values.
+	alpha bool
+
 	backend *backend
 
 	node *packing.Node
@@ -214,7 +240,7 @@ func (i *Image) ensureNotShared() {
 		dx1, dy1, sx1, sy1, sx0, sy0, sx1, sy1, 1, 1, 1, 1,
 	}
 	is := graphics.QuadIndices()
-	newImg.DrawTriangles(i.backend.restorable, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero)
+	newImg.DrawTriangles(i.backend.restorable, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 
 	i.dispose(false)
 	i.backend = &backend{
@@ -263,7 +289,8 @@ func (i *Image) region() (x, y, width, height int) {
 	if !i.isShared() {
 		return 0, 0, i.width, i.height
 	}
-	return i.node.Region()
+	x, y, width, height = i.node.Region()
+	return x + paddingSize, y + paddingSize, width - 2*paddingSize, height - 2*paddingSize
 }
 
 // DrawTriangles draws triangles with the given image.
@@ -282,7 +309,20 @@ func (i *Image) region() (x, y, width, height int) {
 //   9:  Color G
 //   10: Color B
 //   11: Color Y
-func (i *Image) DrawTriangles(img *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address) {
+//
+// clip is the region of the image to render in pixels. If clip is empty, the whole image is rendered.
+func (i *Image) DrawTriangles(img *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth) {
+	i.drawTriangles(img, vertices, indices, colorm, mode, filter, address, clip, stencil, depth, nil, nil)
+}
+
+// DrawShader draws triangles with the given image and the shader.
+//
+// The vertex floats are the same as DrawTriangles.
+func (i *Image) DrawShader(img *Image, vertices []float32, indices []uint16, shader *Shader, uniforms [][]float32, mode driver.CompositeMode) {
+	i.drawTriangles(img, vertices, indices, nil, mode, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{}, shader, uniforms)
+}
+
+func (i *Image) drawTriangles(img *Image, vertices []float32, indices []uint16, colorm *affine.ColorM, mode driver.CompositeMode, filter driver.Filter, address driver.Address, clip image.Rectangle, stencil driver.StencilMode, depth driver.Depth, shader *Shader, uniforms [][]float32) {
 	backendsM.Lock()
 	// Do not use defer for performance.
 
@@ -316,7 +356,12 @@ func (i *Image) DrawTriangles(img *Image, vertices []float32, indices []uint16,
 		vertices[i*graphics.VertexFloatNum+7] += oyf
 	}
 
-	i.backend.restorable.DrawTriangles(img.backend.restorable, vertices, indices, colorm, mode, filter, address)
+	if shader != nil {
+		i.backend.restorable.DrawShader(img.backend.restorable, vertices, indices, shader.shader, uniforms, mode)
+	} else {
+		// The destination is not shared, so clip doesn't have to be adjusted.
+		i.backend.restorable.DrawTriangles(img.backend.restorable, vertices, indices, colorm, mode, filter, address, clip, stencil, depth)
+	}
 
 	i.nonUpdatedCount = 0
 	delete(imagesToMakeShared, i)
@@ -373,7 +418,96 @@ func (i *Image) replacePixels(p []byte) {
 			panic(fmt.Sprintf("shareable: len(p) must be %d but %d", l, len(p)))
 		}
 	}
-	i.backend.restorable.ReplacePixels(p, x, y, w, h)
+	if !i.isShared() || paddingSize == 0 {
+		i.backend.restorable.ReplacePixels(p, x, y, w, h)
+		return
+	}
+
+	pw, ph := w+2*paddingSize, h+2*paddingSize
+	if p == nil {
+		i.backend.restorable.ReplacePixels(nil, x-paddingSize, y-paddingSize, pw, ph)
+		return
+	}
+	i.backend.restorable.ReplacePixels(extrude(p, w, h, paddingSize, paddingSize, paddingSize, paddingSize), x-paddingSize, y-paddingSize, pw, ph)
+}
+
+// ReplacePixelsRegion replaces the pixels of the region (x, y)-(x+width, y+height) of the image with p.
+func (i *Image) ReplacePixelsRegion(p []byte, x, y, width, height int) error {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if i.disposed {
+		panic("shareable: the image must not be disposed at ReplacePixelsRegion")
+	}
+	if x < 0 || y < 0 || width <= 0 || height <= 0 || i.width < x+width || i.height < y+height {
+		panic(fmt.Sprintf("shareable: out of range x: %d, y: %d, width: %d, height: %d", x, y, width, height))
+	}
+	if l := 4 * width * height; len(p) != l {
+		panic(fmt.Sprintf("shareable: len(p) must be %d but %d", l, len(p)))
+	}
+	if i.backend == nil {
+		i.allocate(true)
+	}
+
+	// A part of a rendered image can't be replaced until the rendering history is resolved (#593, #758).
+	// A shared image is never rendered, then the history is always empty.
+	if err := i.backend.restorable.ResolveDrawTrianglesHistory(); err != nil {
+		return err
+	}
+
+	ox, oy, w, h := i.region()
+	if !i.isShared() || paddingSize == 0 {
+		i.backend.restorable.ReplacePixels(p, ox+x, oy+y, width, height)
+		return nil
+	}
+
+	// Update the padding too when the region touches the edges of the image.
+	var left, top, right, bottom int
+	if x == 0 {
+		left = paddingSize
+	}
+	if y == 0 {
+		top = paddingSize
+	}
+	if x+width == w {
+		right = paddingSize
+	}
+	if y+height == h {
+		bottom = paddingSize
+	}
+	pix := p
+	if left != 0 || top != 0 || right != 0 || bottom != 0 {
+		pix = extrude(p, width, height, left, top, right, bottom)
+	}
+	i.backend.restorable.ReplacePixels(pix, ox+x-left, oy+y-top, width+left+right, height+top+bottom)
+	return nil
+}
+
+// extrude returns the pixels of the width x height image p surrounded by padding pixels, where the edge pixels of p
+// are repeated.
+func extrude(p []byte, width, height, left, top, right, bottom int) []byte {
+	pw, ph := width+left+right, height+top+bottom
+	pix := make([]byte, 4*pw*ph)
+	for j := 0; j < ph; j++ {
+		sj := j - top
+		if sj < 0 {
+			sj = 0
+		}
+		if sj >= height {
+			sj = height - 1
+		}
+		for i := 0; i < pw; i++ {
+			si := i - left
+			if si < 0 {
+				si = 0
+			}
+			if si >= width {
+				si = width - 1
+			}
+			copy(pix[4*(i+j*pw):4*(i+j*pw)+4], p[4*(si+sj*width):4*(si+sj*width)+4])
+		}
+	}
+	return pix
 }
 
 func (i *Image) At(x, y int) (byte, byte, byte, byte, error) {
@@ -466,6 +600,50 @@ func NewImage(width, height int, volatile bool) *Image {
 	}
 }
 
+// NewMultisampledImage returns an image that is rendered with multisample anti-aliasing.
+// A multisampled image is never shared.
+func NewMultisampledImage(width, height, samples int) *Image {
+	// Actual allocation is done lazily, and the lock is not needed.
+	return &Image{
+		width:   width,
+		height:  height,
+		samples: samples,
+	}
+}
+
+// NewFloatImage returns an image whose pixels have floating-point values.
+// A floating-point image is never shared.
+func NewFloatImage(width, height int, volatile bool) *Image {
+	// Actual allocation is done lazily, and the lock is not needed.
+	return &Image{
+		width:    width,
+		height:   height,
+		volatile: volatile,
+		float:    true,
+	}
+}
+
+// IsFloat reports whether the image has floating-point values.
+func (i *Image) IsFloat() bool {
+	return i.float
+}
+
+// NewAlphaImage returns an image whose pixels have only alpha values.
+// An alpha-only image is never shared.
+func NewAlphaImage(width, height int) *Image {
+	// Actual allocation is done lazily, and the lock is not needed.
+	return &Image{
+		width:  width,
+		height: height,
+		alpha:  true,
+	}
+}
+
+// IsAlpha reports whether the image has only alpha values.
+func (i *Image) IsAlpha() bool {
+	return i.alpha
+}
+
 func (i *Image) shareable() bool {
 	if minSize == 0 || maxSize == 0 {
 		panic("shareable: minSize or maxSize must be initialized")
@@ -473,10 +651,19 @@ func (i *Image) shareable() bool {
 	if i.volatile {
 		return false
 	}
+	if i.samples > 0 {
+		return false
+	}
+	if i.float {
+		return false
+	}
+	if i.alpha {
+		return false
+	}
 	if i.screen {
 		return false
 	}
-	return i.width <= maxSize && i.height <= maxSize
+	return i.width+2*paddingSize <= maxSize && i.height+2*paddingSize <= maxSize
 }
 
 func (i *Image) allocate(shareable bool) {
@@ -493,6 +680,27 @@ func (i *Image) allocate(shareable bool) {
 		return
 	}
 
+	if i.samples > 0 {
+		i.backend = &backend{
+			restorable: restorable.NewMultisampledImage(i.width, i.height, i.samples),
+		}
+		return
+	}
+
+	if i.float {
+		i.backend = &backend{
+			restorable: restorable.NewFloatImage(i.width, i.height, i.volatile),
+		}
+		return
+	}
+
+	if i.alpha {
+		i.backend = &backend{
+			restorable: restorable.NewAlphaImage(i.width, i.height),
+		}
+		return
+	}
+
 	if !shareable || !i.shareable() {
 		i.backend = &backend{
 			restorable: restorable.NewImage(i.width, i.height, i.volatile),
@@ -500,15 +708,16 @@ func (i *Image) allocate(shareable bool) {
 		return
 	}
 
+	w, h := i.width+2*paddingSize, i.height+2*paddingSize
 	for _, b := range theBackends {
-		if n, ok := b.TryAlloc(i.width, i.height); ok {
+		if n, ok := b.TryAlloc(w, h); ok {
 			i.backend = b
 			i.node = n
 			return
 		}
 	}
 	size := minSize
-	for i.width > size || i.height > size {
+	for w > size || h > size {
 		if size == maxSize {
 			panic(fmt.Sprintf("shareable: the image being shared is too big: width: %d, height: %d", i.width, i.height))
 		}
@@ -521,7 +730,7 @@ func (i *Image) allocate(shareable bool) {
 	}
 	theBackends = append(theBackends, b)
 
-	n := b.page.Alloc(i.width, i.height)
+	n := b.page.Alloc(w, h)
 	if n == nil {
 		panic("shareable: Alloc result must not be nil at allocate")
 	}
@@ -16,6 +16,7 @@ package shareable_test
 
 import (
 	"errors"
+	"image"
 	"image/color"
 	"os"
 	"runtime"
@@ -104,7 +105,7 @@ func TestEnsureNotShared(t *testing.T) {
 	// img4.ensureNotShared() should be called.
 	vs := quadVertices(size/2, size/2, size/4, size/4, 1)
 	is := graphics.QuadIndices()
-	img4.DrawTriangles(img3, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero)
+	img4.DrawTriangles(img3, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 	want := false
 	if got := img4.IsSharedForTesting(); got != want {
 		t.Errorf("got: %v, want: %v", got, want)
@@ -130,7 +131,7 @@ func TestEnsureNotShared(t *testing.T) {
 
 	// Check further drawing doesn't cause panic.
 	// This bug was fixed by 03dcd948.
-	img4.DrawTriangles(img3, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero)
+	img4.DrawTriangles(img3, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 }
 
 func TestReshared(t *testing.T) {
@@ -170,7 +171,7 @@ func TestReshared(t *testing.T) {
 	// Use img1 as a render target.
 	vs := quadVertices(size, size, 0, 0, 1)
 	is := graphics.QuadIndices()
-	img1.DrawTriangles(img2, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero)
+	img1.DrawTriangles(img2, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 	if got, want := img1.IsSharedForTesting(), false; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
@@ -180,7 +181,7 @@ func TestReshared(t *testing.T) {
 		if err := MakeImagesSharedForTesting(); err != nil {
 			t.Fatal(err)
 		}
-		img0.DrawTriangles(img1, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero)
+		img0.DrawTriangles(img1, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 		if got, want := img1.IsSharedForTesting(), false; got != want {
 			t.Errorf("got: %v, want: %v", got, want)
 		}
@@ -203,7 +204,7 @@ func TestReshared(t *testing.T) {
 		}
 	}
 
-	img0.DrawTriangles(img1, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero)
+	img0.DrawTriangles(img1, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 	if got, want := img1.IsSharedForTesting(), true; got != want {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
@@ -227,7 +228,7 @@ func TestReshared(t *testing.T) {
 		if err := MakeImagesSharedForTesting(); err != nil {
 			t.Fatal(err)
 		}
-		img0.DrawTriangles(img3, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero)
+		img0.DrawTriangles(img3, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 		if got, want := img3.IsSharedForTesting(), false; got != want {
 			t.Errorf("got: %v, want: %v", got, want)
 		}
@@ -314,7 +315,7 @@ func TestReplacePixelsAfterDrawTriangles(t *testing.T) {
 
 	vs := quadVertices(w, h, 0, 0, 1)
 	is := graphics.QuadIndices()
-	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero)
+	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeCopy, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 	dst.ReplacePixels(pix)
 
 	for j := 0; j < h; j++ {
@@ -352,7 +353,7 @@ func TestSmallImages(t *testing.T) {
 
 	vs := quadVertices(w, h, 0, 0, 1)
 	is := graphics.QuadIndices()
-	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeSourceOver, driver.FilterNearest, driver.AddressClampToZero)
+	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeSourceOver, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 
 	for j := 0; j < h; j++ {
 		for i := 0; i < w; i++ {
@@ -390,7 +391,7 @@ func TestLongImages(t *testing.T) {
 	const scale = 120
 	vs := quadVertices(w, h, 0, 0, scale)
 	is := graphics.QuadIndices()
-	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeSourceOver, driver.FilterNearest, driver.AddressClampToZero)
+	dst.DrawTriangles(src, vs, is, nil, driver.CompositeModeSourceOver, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
 
 	for j := 0; j < h; j++ {
 		for i := 0; i < w*scale; i++ {
@@ -437,3 +438,41 @@ func TestExtendWithBigImage(t *testing.T) {
 }
 
 // TODO: Add tests to extend shareable image out of the main loop
+
+func TestNoBleedingWithLinearFilter(t *testing.T) {
+	const w, h = 16, 16
+	red := NewImage(w, h, false)
+	defer red.MarkDisposed()
+	green := NewImage(w, h, false)
+	defer green.MarkDisposed()
+
+	pix0 := make([]byte, 4*w*h)
+	pix1 := make([]byte, 4*w*h)
+	for i := 0; i < w*h; i++ {
+		pix0[4*i] = 0xff
+		pix0[4*i+3] = 0xff
+		pix1[4*i+1] = 0xff
+		pix1[4*i+3] = 0xff
+	}
+	red.ReplacePixels(pix0)
+	green.ReplacePixels(pix1)
+
+	const scale = 2.5
+	dst := NewImage(w*4, h*4, false)
+	defer dst.MarkDisposed()
+	vs := quadVertices(w, h, 0, 0, scale)
+	is := graphics.QuadIndices()
+	dst.DrawTriangles(red, vs, is, nil, driver.CompositeModeSourceOver, driver.FilterLinear, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{})
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w*scale; i++ {
+			_, g, _, _, err := dst.At(i, j)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g != 0 {
+				t.Errorf("At(%d, %d) green: got: %d, want: 0", i, j, g)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareable
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/restorable"
+	"github.com/hajimehoshi/ebiten/internal/shader"
+)
+
+// Shader represents a custom shader program.
+type Shader struct {
+	shader *restorable.Shader
+}
+
+// NewShader returns a new shader.
+func NewShader(program *shader.Program) *Shader {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	return &Shader{
+		shader: restorable.NewShader(program),
+	}
+}
+
+// Dispose disposes the shader.
+func (s *Shader) Dispose() {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	s.shader.Dispose()
+	s.shader = nil
+}
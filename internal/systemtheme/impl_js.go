@@ -0,0 +1,58 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemtheme
+
+import (
+	"syscall/js"
+
+	"github.com/hajimehoshi/ebiten/internal/jsutil"
+)
+
+func impl() Theme {
+	var t Theme
+
+	matchMedia := js.Global().Get("matchMedia")
+	if jsutil.Equal(matchMedia, js.Undefined()) {
+		return t
+	}
+	if js.Global().Call("matchMedia", "(prefers-color-scheme: dark)").Get("matches").Bool() {
+		t.ColorScheme = ColorSchemeDark
+	} else if js.Global().Call("matchMedia", "(prefers-color-scheme: light)").Get("matches").Bool() {
+		t.ColorScheme = ColorSchemeLight
+	}
+	// Browsers don't expose the accent color.
+	return t
+}
+
+func watch(invalidate func()) {
+	matchMedia := js.Global().Get("matchMedia")
+	if jsutil.Equal(matchMedia, js.Undefined()) {
+		return
+	}
+	f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		invalidate()
+		return nil
+	})
+	// The listener is never released since the theme is watched as long as the page lives.
+	for _, q := range []string{"(prefers-color-scheme: dark)", "(prefers-color-scheme: light)"} {
+		m := js.Global().Call("matchMedia", q)
+		// Old browsers don't have addEventListener on MediaQueryList.
+		if jsutil.Equal(m.Get("addEventListener"), js.Undefined()) {
+			m.Call("addListener", f)
+			continue
+		}
+		m.Call("addEventListener", "change", f)
+	}
+}
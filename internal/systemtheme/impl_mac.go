@@ -0,0 +1,75 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin
+// +build !js
+// +build !ios
+
+package systemtheme
+
+// #cgo CFLAGS: -x objective-c
+// #cgo LDFLAGS: -framework AppKit
+//
+// #import <AppKit/AppKit.h>
+//
+// static int isDark() {
+//   @autoreleasepool {
+//     NSString* style = [[NSUserDefaults standardUserDefaults] stringForKey:@"AppleInterfaceStyle"];
+//     return style != nil && [style caseInsensitiveCompare:@"Dark"] == NSOrderedSame;
+//   }
+// }
+//
+// static int accentColor(unsigned char* r, unsigned char* g, unsigned char* b) {
+//   @autoreleasepool {
+//     // controlAccentColor is available as of macOS 10.14.
+//     if (![NSColor respondsToSelector:@selector(controlAccentColor)]) {
+//       return 0;
+//     }
+//     NSColor* c = [[NSColor performSelector:@selector(controlAccentColor)] colorUsingColorSpace:[NSColorSpace sRGBColorSpace]];
+//     if (!c) {
+//       return 0;
+//     }
+//     CGFloat cr, cg, cb, ca;
+//     [c getRed:&cr green:&cg blue:&cb alpha:&ca];
+//     *r = (unsigned char)(cr * 255);
+//     *g = (unsigned char)(cg * 255);
+//     *b = (unsigned char)(cb * 255);
+//     return 1;
+//   }
+// }
+import "C"
+
+import (
+	"image/color"
+)
+
+func impl() Theme {
+	t := Theme{
+		ColorScheme: ColorSchemeLight,
+	}
+	if C.isDark() != 0 {
+		t.ColorScheme = ColorSchemeDark
+	}
+
+	var r, g, b C.uchar
+	if C.accentColor(&r, &g, &b) != 0 {
+		t.AccentColor = color.RGBA{uint8(r), uint8(g), uint8(b), 0xff}
+		t.HasAccentColor = true
+	}
+	return t
+}
+
+func watch(invalidate func()) {
+	// TODO: Observe AppleInterfaceThemeChangedNotification with NSDistributedNotificationCenter.
+}
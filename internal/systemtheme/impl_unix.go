@@ -0,0 +1,95 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build dragonfly freebsd linux netbsd openbsd solaris
+// +build !js
+// +build !android
+
+package systemtheme
+
+import (
+	"bufio"
+	"image/color"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// gnomeAccentColors is the table of the accent colors of GNOME.
+var gnomeAccentColors = map[string]color.RGBA{
+	"blue":   {0x35, 0x84, 0xe4, 0xff},
+	"teal":   {0x21, 0x90, 0xa4, 0xff},
+	"green":  {0x3a, 0x94, 0x4a, 0xff},
+	"yellow": {0xc8, 0x88, 0x00, 0xff},
+	"orange": {0xed, 0x5b, 0x00, 0xff},
+	"red":    {0xe6, 0x2d, 0x42, 0xff},
+	"pink":   {0xd5, 0x61, 0x99, 0xff},
+	"purple": {0x91, 0x41, 0xac, 0xff},
+	"slate":  {0x6f, 0x83, 0x96, 0xff},
+}
+
+func gsettings(key string) (string, bool) {
+	out, err := exec.Command("gsettings", "get", "org.gnome.desktop.interface", key).Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.Trim(strings.TrimSpace(string(out)), "'"), true
+}
+
+func impl() Theme {
+	var t Theme
+
+	if strings.HasSuffix(os.Getenv("GTK_THEME"), ":dark") {
+		t.ColorScheme = ColorSchemeDark
+	} else if s, ok := gsettings("color-scheme"); ok && s == "prefer-dark" {
+		t.ColorScheme = ColorSchemeDark
+	} else if s, ok := gsettings("gtk-theme"); ok {
+		// Old versions of GNOME don't have color-scheme, and the theme name indicates the dark mode.
+		if strings.Contains(strings.ToLower(s), "dark") {
+			t.ColorScheme = ColorSchemeDark
+		} else {
+			t.ColorScheme = ColorSchemeLight
+		}
+	}
+
+	if s, ok := gsettings("accent-color"); ok {
+		if c, ok := gnomeAccentColors[s]; ok {
+			t.AccentColor = c
+			t.HasAccentColor = true
+		}
+	}
+
+	return t
+}
+
+// watch invalidates the cache whenever a key of the GNOME interface settings changes.
+func watch(invalidate func()) {
+	cmd := exec.Command("gsettings", "monitor", "org.gnome.desktop.interface")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		return
+	}
+
+	go func() {
+		// gsettings monitor prints a line like "color-scheme: 'prefer-dark'" at every change.
+		s := bufio.NewScanner(out)
+		for s.Scan() {
+			invalidate()
+		}
+		_ = cmd.Wait()
+	}()
+}
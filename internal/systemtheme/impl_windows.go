@@ -0,0 +1,82 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemtheme
+
+import (
+	"image/color"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+const (
+	personalizeKey = `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`
+	dwmKey         = `Software\Microsoft\Windows\DWM`
+
+	regNotifyChangeLastSet = 0x4
+)
+
+var procRegNotifyChangeKeyValue = windows.NewLazySystemDLL("advapi32.dll").NewProc("RegNotifyChangeKeyValue")
+
+func impl() Theme {
+	var t Theme
+
+	if k, err := registry.OpenKey(registry.CURRENT_USER, personalizeKey, registry.QUERY_VALUE); err == nil {
+		if v, _, err := k.GetIntegerValue("AppsUseLightTheme"); err == nil {
+			if v == 0 {
+				t.ColorScheme = ColorSchemeDark
+			} else {
+				t.ColorScheme = ColorSchemeLight
+			}
+		}
+		k.Close()
+	}
+
+	if k, err := registry.OpenKey(registry.CURRENT_USER, dwmKey, registry.QUERY_VALUE); err == nil {
+		// AccentColor is in the AABBGGRR format.
+		if v, _, err := k.GetIntegerValue("AccentColor"); err == nil {
+			t.AccentColor = color.RGBA{
+				R: uint8(v),
+				G: uint8(v >> 8),
+				B: uint8(v >> 16),
+				A: 0xff,
+			}
+			t.HasAccentColor = true
+		}
+		k.Close()
+	}
+
+	return t
+}
+
+// watch invalidates the cache whenever a value of the registry keys for the theme changes.
+func watch(invalidate func()) {
+	for _, path := range []string{personalizeKey, dwmKey} {
+		k, err := registry.OpenKey(registry.CURRENT_USER, path, registry.NOTIFY)
+		if err != nil {
+			continue
+		}
+		go func(k registry.Key) {
+			defer k.Close()
+			for {
+				// RegNotifyChangeKeyValue without an event blocks until a change happens.
+				if r, _, _ := procRegNotifyChangeKeyValue.Call(uintptr(k), 0, regNotifyChangeLastSet, 0, 0); r != 0 {
+					return
+				}
+				invalidate()
+			}
+		}(k)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package systemtheme provides the theme settings of the system like the dark mode.
+package systemtheme
+
+import (
+	"image/color"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/internal/lazyquery"
+)
+
+type ColorScheme int
+
+const (
+	ColorSchemeUnknown ColorScheme = iota
+	ColorSchemeLight
+	ColorSchemeDark
+)
+
+type Theme struct {
+	ColorScheme    ColorScheme
+	AccentColor    color.RGBA
+	HasAccentColor bool
+}
+
+// refreshInterval is the TTL of the cached theme.
+// Querying the theme can be expensive, e.g., executing a command, then the result is cached. Where the system notifies
+// the changes, the cache is invalidated at the notifications.
+const refreshInterval = 10 * time.Second
+
+var (
+	theQuery = lazyquery.New(func() interface{} {
+		return impl()
+	}, refreshInterval)
+	watchOnce sync.Once
+)
+
+// Get returns the current theme of the system.
+//
+// The theme is queried lazily and cached.
+func Get() Theme {
+	watchOnce.Do(func() {
+		watch(theQuery.Invalidate)
+	})
+	return theQuery.Get().(Theme)
+}
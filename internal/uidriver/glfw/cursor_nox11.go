@@ -0,0 +1,33 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build dragonfly linux,wayland freebsd,wayland netbsd openbsd solaris
+// +build !js
+// +build !android
+
+package glfw
+
+import (
+	"image"
+)
+
+// clipCursor must be called from the main thread.
+func (u *UserInterface) clipCursor(r image.Rectangle) bool {
+	// There is no way to confine the cursor without X11 so far. The cursor is moved into the region instead.
+	return false
+}
+
+// unclipCursor must be called from the main thread.
+func (u *UserInterface) unclipCursor() {
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux,!wayland freebsd,!wayland
+// +build !js
+// +build !android
+
+package glfw
+
+// #cgo linux LDFLAGS: -lX11
+// #cgo freebsd CFLAGS: -I/usr/local/include
+// #cgo freebsd LDFLAGS: -L/usr/local/lib -lX11
+//
+// #include <X11/Xlib.h>
+//
+// static Window confineWindow;
+// static int pointerGrabbed;
+//
+// static int clipCursor(Display* display, Window window, int x, int y, int width, int height) {
+//   // XGrabPointer confines the cursor in a window. Put an invisible child window as the region.
+//   if (!confineWindow) {
+//     XSetWindowAttributes attrs = {0};
+//     confineWindow = XCreateWindow(display, window, x, y, width, height, 0, 0, InputOnly, CopyFromParent, 0, &attrs);
+//     XMapWindow(display, confineWindow);
+//   } else {
+//     XMoveResizeWindow(display, confineWindow, x, y, width, height);
+//   }
+//   if (!pointerGrabbed) {
+//     unsigned int mask = ButtonPressMask | ButtonReleaseMask | PointerMotionMask | EnterWindowMask | LeaveWindowMask;
+//     if (XGrabPointer(display, window, True, mask, GrabModeAsync, GrabModeAsync, confineWindow, None, CurrentTime) != GrabSuccess) {
+//       XFlush(display);
+//       return 0;
+//     }
+//     pointerGrabbed = 1;
+//   }
+//   XFlush(display);
+//   return 1;
+// }
+//
+// static void unclipCursor(Display* display) {
+//   if (!pointerGrabbed) {
+//     return;
+//   }
+//   XUngrabPointer(display, CurrentTime);
+//   XFlush(display);
+//   pointerGrabbed = 0;
+// }
+import "C"
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/internal/glfw"
+)
+
+// clipCursor confines the cursor in the region r in device-independent pixels with XGrabPointer.
+//
+// clipCursor returns false when the pointer cannot be grabbed e.g. when another client grabs the pointer.
+//
+// clipCursor must be called from the main thread.
+func (u *UserInterface) clipCursor(r image.Rectangle) bool {
+	x := C.int(u.toDeviceDependentPixel(float64(r.Min.X)))
+	y := C.int(u.toDeviceDependentPixel(float64(r.Min.Y)))
+	w := C.int(u.toDeviceDependentPixel(float64(r.Dx())))
+	h := C.int(u.toDeviceDependentPixel(float64(r.Dy())))
+	if w <= 0 || h <= 0 {
+		return false
+	}
+	return C.clipCursor((*C.Display)(glfw.GetX11Display()), C.Window(u.window.GetX11Window()), x, y, w, h) != 0
+}
+
+// unclipCursor must be called from the main thread.
+func (u *UserInterface) unclipCursor() {
+	C.unclipCursor((*C.Display)(glfw.GetX11Display()))
+}
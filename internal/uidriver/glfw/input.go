@@ -48,6 +48,7 @@ type Input struct {
 	gamepads           [16]gamePad
 	touches            map[int]pos // This is not updated until GLFW 3.3 is available (#417)
 	runeBuffer         []rune
+	context            driver.UIContext
 	ui                 *UserInterface
 }
 
@@ -68,6 +69,30 @@ func (i *Input) CursorPosition() (x, y int) {
 	return cx, cy
 }
 
+func (i *Input) LatestCursorPosition() (x, y int) {
+	if !i.ui.isRunning() {
+		return 0, 0
+	}
+	var cx, cy float64
+	var context driver.UIContext
+	_ = i.ui.t.Call(func() error {
+		if i.context == nil {
+			return nil
+		}
+		context = i.context
+		// GetCursorPos queries the current position from the OS, not from the last polled events.
+		cx, cy = i.ui.window.GetCursorPos()
+		cx = i.ui.toDeviceIndependentPixel(cx)
+		cy = i.ui.toDeviceIndependentPixel(cy)
+		return nil
+	})
+	if context == nil {
+		return 0, 0
+	}
+	cx, cy = context.AdjustPosition(cx, cy)
+	return int(cx), int(cy)
+}
+
 func (i *Input) GamepadIDs() []int {
 	if !i.ui.isRunning() {
 		return nil
@@ -177,6 +202,25 @@ func (i *Input) IsGamepadButtonPressed(id int, button driver.GamepadButton) bool
 	return r
 }
 
+func (i *Input) GamepadGyro(id int) (x, y, z float64, ok bool) {
+	// GLFW doesn't provide motion sensors.
+	return 0, 0, 0, false
+}
+
+func (i *Input) GamepadAccelerometer(id int) (x, y, z float64, ok bool) {
+	// GLFW doesn't provide motion sensors.
+	return 0, 0, 0, false
+}
+
+func (i *Input) GamepadTouchpadTouchIDs(id int) []int {
+	// GLFW doesn't provide touchpads of gamepads.
+	return nil
+}
+
+func (i *Input) GamepadTouchpadPosition(id int, touchID int) (x, y float64) {
+	return 0, 0
+}
+
 func (i *Input) TouchIDs() []int {
 	if !i.ui.isRunning() {
 		return nil
@@ -348,6 +392,7 @@ func (i *Input) update(window *glfw.Window, context driver.UIContext) {
 	cx, cy = context.AdjustPosition(cx, cy)
 
 	_ = i.ui.t.Call(func() error {
+		i.context = context
 		i.cursorX, i.cursorY = int(cx), int(cy)
 
 		for id := glfw.Joystick(0); id < glfw.Joystick(len(i.gamepads)); id++ {
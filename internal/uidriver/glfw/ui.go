@@ -23,12 +23,14 @@ import (
 	"context"
 	"fmt"
 	"image"
+	"math"
 	"os"
 	"runtime"
 	"sync"
 	"time"
 	"unsafe"
 
+	"github.com/hajimehoshi/ebiten/internal/clock"
 	"github.com/hajimehoshi/ebiten/internal/devicescale"
 	"github.com/hajimehoshi/ebiten/internal/driver"
 	"github.com/hajimehoshi/ebiten/internal/glfw"
@@ -40,6 +42,9 @@ type UserInterface struct {
 	title  string
 	window *glfw.Window
 
+	// loaderWindow is a hidden window for the OpenGL context to upload textures in background.
+	loaderWindow *glfw.Window
+
 	// windowWidth and windowHeight represents a window size.
 	// The unit is device-dependent pixels.
 	windowWidth  int
@@ -51,6 +56,58 @@ type UserInterface struct {
 	origPosY             int
 	runnableInBackground bool
 	vsync                bool
+	swapInterval         int
+	lowLatencyMode       bool
+	maxQueuedFrames      int
+	backgroundUpload     bool
+	screenSaverEnabled   bool
+	captureFriendlyMode  bool
+
+	// fullscreenVideoMode is the video mode used in fullscreen mode.
+	// The zero value means the current video mode of the monitor.
+	fullscreenVideoMode driver.VideoMode
+
+	// fullscreenMonitor is the index of the monitor used in fullscreen mode.
+	// A negative value means the monitor which the window belongs to.
+	fullscreenMonitor int
+
+	// fullscreenSpanning reports whether the window covers all the monitors in fullscreen mode.
+	fullscreenSpanning bool
+
+	// spanned reports whether the window is a borderless window covering all the monitors.
+	//
+	// spanned must be manipulated on the main thread.
+	spanned bool
+
+	// spannedDecorated is the decorated state of the window before the window was spanned.
+	//
+	// spannedDecorated must be manipulated on the main thread.
+	spannedDecorated bool
+
+	// cursorConfinement is the region to confine the cursor in device-independent pixels.
+	cursorConfinement image.Rectangle
+
+	cursorShape driver.CursorShape
+
+	// cursors is the cache of the standard cursors.
+	//
+	// cursors must be manipulated on the main thread.
+	cursors map[driver.CursorShape]*glfw.Cursor
+
+	// fullscreenIconified reports whether the window in exclusive fullscreen mode has been iconified.
+	//
+	// fullscreenIconified must be manipulated on the main thread.
+	fullscreenIconified bool
+
+	// terminated reports whether GLFW is already terminated e.g. at a panic of the game.
+	//
+	// terminated must be manipulated on the main thread.
+	terminated bool
+
+	// cursorClipped reports whether the cursor is confined by the OS.
+	//
+	// cursorClipped must be manipulated on the main thread.
+	cursorClipped bool
 
 	lastDeviceScaleFactor float64
 
@@ -68,6 +125,8 @@ type UserInterface struct {
 	initWindowHeightInDP     int
 	initScreenTransparent    bool
 	initIconImages           []image.Image
+	initVideoModes           []driver.VideoMode
+	initMonitors             []driver.Monitor
 
 	reqWidth  int
 	reqHeight int
@@ -96,6 +155,9 @@ var (
 		initWindowWidthInDP:     640,
 		initWindowHeightInDP:    480,
 		vsync:                   true,
+		swapInterval:            1,
+		screenSaverEnabled:      true,
+		fullscreenMonitor:       -1,
 	}
 )
 
@@ -117,6 +179,7 @@ func init() {
 		cacheMonitors()
 	})
 	cacheMonitors()
+	theUI.initMonitors = monitorList()
 }
 
 func initialize() error {
@@ -141,10 +204,24 @@ func initialize() error {
 	v := theUI.initMonitor.GetVideoMode()
 	theUI.initFullscreenWidthInDP = int(theUI.toDeviceIndependentPixel(float64(v.Width)))
 	theUI.initFullscreenHeightInDP = int(theUI.toDeviceIndependentPixel(float64(v.Height)))
+	theUI.initVideoModes = videoModes(theUI.initMonitor)
 
 	return nil
 }
 
+// videoModes must be called from the main thread.
+func videoModes(m *glfw.Monitor) []driver.VideoMode {
+	var vs []driver.VideoMode
+	for _, v := range m.GetVideoModes() {
+		vs = append(vs, driver.VideoMode{
+			Width:       v.Width,
+			Height:      v.Height,
+			RefreshRate: v.RefreshRate,
+		})
+	}
+	return vs
+}
+
 type cachedMonitor struct {
 	m  *glfw.Monitor
 	vm *glfw.VidMode
@@ -174,6 +251,33 @@ func cacheMonitors() {
 	}
 }
 
+// monitorList returns the list of the cached monitors.
+//
+// monitorList must be called on the main thread.
+func monitorList() []driver.Monitor {
+	var ms []driver.Monitor
+	for _, m := range monitors {
+		ms = append(ms, driver.Monitor{
+			X:      m.x,
+			Y:      m.y,
+			Width:  m.vm.Width,
+			Height: m.vm.Height,
+		})
+	}
+	return ms
+}
+
+// spanningBounds returns the region covering all the monitors in device-dependent pixels.
+//
+// spanningBounds must be called on the main thread.
+func spanningBounds() image.Rectangle {
+	var r image.Rectangle
+	for _, m := range monitors {
+		r = r.Union(image.Rect(m.x, m.y, m.x+m.vm.Width, m.y+m.vm.Height))
+	}
+	return r
+}
+
 // getCachedMonitor returns a monitor for the given window x/y
 // returns false if monitor is not found.
 //
@@ -226,6 +330,124 @@ func (u *UserInterface) setInitFullscreen(initFullscreen bool) {
 	u.m.Unlock()
 }
 
+func (u *UserInterface) getFullscreenVideoMode() driver.VideoMode {
+	u.m.RLock()
+	v := u.fullscreenVideoMode
+	u.m.RUnlock()
+	return v
+}
+
+func (u *UserInterface) setFullscreenVideoMode(mode driver.VideoMode) {
+	u.m.Lock()
+	u.fullscreenVideoMode = mode
+	u.m.Unlock()
+}
+
+func (u *UserInterface) getFullscreenMonitor() int {
+	u.m.RLock()
+	v := u.fullscreenMonitor
+	u.m.RUnlock()
+	return v
+}
+
+func (u *UserInterface) setFullscreenMonitor(index int) {
+	u.m.Lock()
+	u.fullscreenMonitor = index
+	u.m.Unlock()
+}
+
+func (u *UserInterface) isFullscreenSpanning() bool {
+	u.m.RLock()
+	v := u.fullscreenSpanning
+	u.m.RUnlock()
+	return v
+}
+
+func (u *UserInterface) setFullscreenSpanning(enabled bool) {
+	u.m.Lock()
+	u.fullscreenSpanning = enabled
+	u.m.Unlock()
+}
+
+// isExclusiveFullscreenVideoMode reports whether the fullscreen video mode switches the display mode.
+//
+// On gamescope, the video mode is never switched and the fullscreen uses the current video mode.
+func (u *UserInterface) isExclusiveFullscreenVideoMode() bool {
+	if isGamescope() {
+		return false
+	}
+	mode := u.getFullscreenVideoMode()
+	return mode.Width > 0 && mode.Height > 0
+}
+
+func (u *UserInterface) getCursorConfinement() image.Rectangle {
+	u.m.RLock()
+	r := u.cursorConfinement
+	u.m.RUnlock()
+	return r
+}
+
+func (u *UserInterface) SetCursorConfinement(region image.Rectangle) {
+	u.m.Lock()
+	u.cursorConfinement = region
+	u.m.Unlock()
+}
+
+// confineCursor confines the cursor in the confinement region by the OS.
+// If the OS cannot confine the cursor, confineCursor moves the cursor into the region instead.
+//
+// The confinement is released while the window is unfocused or the cursor is disabled.
+//
+// confineCursor must be called from the main thread.
+func (u *UserInterface) confineCursor() {
+	r := u.getCursorConfinement()
+	if r.Empty() || u.window.GetAttrib(glfw.Focused) == glfw.False || u.window.GetInputMode(glfw.CursorMode) == glfw.CursorDisabled {
+		u.releaseCursor()
+		return
+	}
+	// The OS confinement is applied every frame since the region in the screen changes when the window moves.
+	if u.clipCursor(r) {
+		u.cursorClipped = true
+		return
+	}
+	u.releaseCursor()
+
+	cx, cy := u.window.GetCursorPos()
+	x := u.toDeviceIndependentPixel(cx)
+	y := u.toDeviceIndependentPixel(cy)
+	nx := math.Max(math.Min(x, float64(r.Max.X-1)), float64(r.Min.X))
+	ny := math.Max(math.Min(y, float64(r.Max.Y-1)), float64(r.Min.Y))
+	if nx == x && ny == y {
+		return
+	}
+	u.window.SetCursorPos(u.toDeviceDependentPixel(nx), u.toDeviceDependentPixel(ny))
+}
+
+// releaseCursor releases the OS confinement of the cursor if exists.
+//
+// releaseCursor must be called from the main thread.
+func (u *UserInterface) releaseCursor() {
+	if !u.cursorClipped {
+		return
+	}
+	u.unclipCursor()
+	u.cursorClipped = false
+}
+
+func (u *UserInterface) isScreenSaverEnabled() bool {
+	u.m.RLock()
+	v := u.screenSaverEnabled
+	u.m.RUnlock()
+	return v
+}
+
+func (u *UserInterface) isCaptureFriendlyMode() bool {
+	u.m.RLock()
+	v := u.captureFriendlyMode
+	u.m.RUnlock()
+	return v
+}
+
 func (u *UserInterface) getInitCursorMode() driver.CursorMode {
 	u.m.RLock()
 	v := u.initCursorMode
@@ -239,6 +461,19 @@ func (u *UserInterface) setInitCursorMode(mode driver.CursorMode) {
 	u.m.Unlock()
 }
 
+func (u *UserInterface) getCursorShape() driver.CursorShape {
+	u.m.RLock()
+	v := u.cursorShape
+	u.m.RUnlock()
+	return v
+}
+
+func (u *UserInterface) setCursorShape(shape driver.CursorShape) {
+	u.m.Lock()
+	u.cursorShape = shape
+	u.m.Unlock()
+}
+
 func (u *UserInterface) isInitWindowDecorated() bool {
 	u.m.RLock()
 	v := u.initWindowDecorated
@@ -351,20 +586,39 @@ func (u *UserInterface) ScreenSizeInFullscreen() (int, int) {
 
 	var w, h int
 	_ = u.t.Call(func() error {
-		v := u.currentMonitor().GetVideoMode()
-		w = int(u.toDeviceIndependentPixel(float64(v.Width)))
-		h = int(u.toDeviceIndependentPixel(float64(v.Height)))
+		ww, wh := u.fullscreenSize()
+		w = int(u.toDeviceIndependentPixel(float64(ww)))
+		h = int(u.toDeviceIndependentPixel(float64(wh)))
 		return nil
 	})
 	return w, h
 }
 
+// fullscreenSize returns the size of the screen in fullscreen mode in device-dependent pixels.
+//
+// fullscreenSize must be called from the main thread.
+func (u *UserInterface) fullscreenSize() (int, int) {
+	if u.isSpanningAvailable() {
+		r := spanningBounds()
+		return r.Dx(), r.Dy()
+	}
+	v := u.fullscreenTargetMonitor().GetVideoMode()
+	return v.Width, v.Height
+}
+
+// isSpanningAvailable reports whether the window covers all the monitors in fullscreen mode.
+//
+// isSpanningAvailable must be called from the main thread.
+func (u *UserInterface) isSpanningAvailable() bool {
+	return u.isFullscreenSpanning() && len(monitors) > 1
+}
+
 // isFullscreen must be called from the main thread.
 func (u *UserInterface) isFullscreen() bool {
 	if !u.isRunning() {
 		panic("glfw: isFullscreen can't be called before the main loop starts")
 	}
-	return u.window.GetMonitor() != nil
+	return u.window.GetMonitor() != nil || u.spanned
 }
 
 func (u *UserInterface) IsFullscreen() bool {
@@ -402,6 +656,246 @@ func (u *UserInterface) SetFullscreen(fullscreen bool) {
 	u.setWindowSize(w, h, fullscreen, u.vsync)
 }
 
+func (u *UserInterface) VideoModes() []driver.VideoMode {
+	if !u.isRunning() {
+		return u.initVideoModes
+	}
+
+	var vs []driver.VideoMode
+	_ = u.t.Call(func() error {
+		vs = videoModes(u.fullscreenTargetMonitor())
+		return nil
+	})
+	return vs
+}
+
+func (u *UserInterface) FullscreenVideoMode() driver.VideoMode {
+	return u.getFullscreenVideoMode()
+}
+
+func (u *UserInterface) SetFullscreenVideoMode(mode driver.VideoMode) {
+	if u.getFullscreenVideoMode() == mode {
+		return
+	}
+	u.setFullscreenVideoMode(mode)
+	if !u.isRunning() {
+		return
+	}
+
+	_ = u.t.Call(func() error {
+		if !u.isFullscreen() {
+			return nil
+		}
+		u.setMonitorForFullscreen()
+		u.toChangeSize = true
+		return nil
+	})
+}
+
+func (u *UserInterface) Monitors() []driver.Monitor {
+	if !u.isRunning() {
+		return u.initMonitors
+	}
+
+	var ms []driver.Monitor
+	_ = u.t.Call(func() error {
+		ms = monitorList()
+		return nil
+	})
+	return ms
+}
+
+func (u *UserInterface) FullscreenMonitor() int {
+	return u.getFullscreenMonitor()
+}
+
+func (u *UserInterface) SetFullscreenMonitor(index int) {
+	if index < 0 {
+		index = -1
+	}
+	if u.getFullscreenMonitor() == index {
+		return
+	}
+	u.setFullscreenMonitor(index)
+	if !u.isRunning() {
+		return
+	}
+
+	_ = u.t.Call(func() error {
+		u.updateFullscreenMonitor()
+		return nil
+	})
+}
+
+func (u *UserInterface) IsFullscreenSpanningEnabled() bool {
+	return u.isFullscreenSpanning()
+}
+
+func (u *UserInterface) SetFullscreenSpanningEnabled(enabled bool) {
+	if u.isFullscreenSpanning() == enabled {
+		return
+	}
+	u.setFullscreenSpanning(enabled)
+	if !u.isRunning() {
+		return
+	}
+
+	_ = u.t.Call(func() error {
+		u.updateFullscreenMonitor()
+		return nil
+	})
+}
+
+// updateFullscreenMonitor moves the window in fullscreen mode to the monitor for fullscreen mode.
+//
+// updateFullscreenMonitor must be called from the main thread.
+func (u *UserInterface) updateFullscreenMonitor() {
+	if !u.isFullscreen() {
+		return
+	}
+	if u.spanned {
+		u.leaveSpannedFullscreen()
+	}
+	u.setMonitorForFullscreen()
+	u.updateSwapInterval()
+	u.toChangeSize = true
+}
+
+// fullscreenTargetMonitor returns the monitor used in fullscreen mode.
+//
+// When the fullscreen monitor index is not specified or out of range, the monitor which the window belongs to
+// is used.
+//
+// fullscreenTargetMonitor must be called from the main thread.
+func (u *UserInterface) fullscreenTargetMonitor() *glfw.Monitor {
+	if i := u.getFullscreenMonitor(); 0 <= i && i < len(monitors) {
+		return monitors[i].m
+	}
+	return u.currentMonitor()
+}
+
+// setSpannedFullscreen makes the window a borderless window covering all the monitors.
+//
+// setSpannedFullscreen must be called from the main thread.
+func (u *UserInterface) setSpannedFullscreen() {
+	r := spanningBounds()
+	if u.window.GetMonitor() != nil {
+		u.window.SetMonitor(nil, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), 0)
+	}
+	if !u.spanned {
+		u.spannedDecorated = u.window.GetAttrib(glfw.Decorated) == glfw.True
+		u.window.SetAttrib(glfw.Decorated, glfw.False)
+		u.spanned = true
+	}
+	u.window.SetPos(r.Min.X, r.Min.Y)
+	u.window.SetSize(r.Dx(), r.Dy())
+}
+
+// leaveSpannedFullscreen restores the decoration of the window covering all the monitors.
+//
+// leaveSpannedFullscreen must be called from the main thread.
+func (u *UserInterface) leaveSpannedFullscreen() {
+	v := glfw.False
+	if u.spannedDecorated {
+		v = glfw.True
+	}
+	u.window.SetAttrib(glfw.Decorated, v)
+	u.spanned = false
+}
+
+// setMonitorForFullscreen makes the window fullscreen with the fullscreen video mode.
+//
+// When the fullscreen video mode is not available on the monitor, the current video mode of the
+// monitor is used instead.
+//
+// When fullscreen spanning is enabled and there are two or more monitors, the window covers all the monitors
+// instead, and the video mode is not changed.
+//
+// setMonitorForFullscreen must be called from the main thread.
+func (u *UserInterface) setMonitorForFullscreen() {
+	if u.isSpanningAvailable() {
+		u.setSpannedFullscreen()
+		return
+	}
+
+	m := u.fullscreenTargetMonitor()
+	v := m.GetVideoMode()
+	width, height, refreshRate := v.Width, v.Height, v.RefreshRate
+	if mode := u.getFullscreenVideoMode(); u.isExclusiveFullscreenVideoMode() {
+		found := false
+		for _, vm := range m.GetVideoModes() {
+			if vm.Width != mode.Width || vm.Height != mode.Height {
+				continue
+			}
+			if mode.RefreshRate != 0 && vm.RefreshRate != mode.RefreshRate {
+				continue
+			}
+			// When the refresh rate is not specified, choose the highest one.
+			if found && vm.RefreshRate <= refreshRate {
+				continue
+			}
+			width, height, refreshRate = vm.Width, vm.Height, vm.RefreshRate
+			found = true
+		}
+	}
+	u.window.SetMonitor(m, 0, 0, width, height, refreshRate)
+
+	// Swapping buffer is necesary to prevent the image lag (#1004).
+	// TODO: This might not work when vsync is disabled.
+	if u.Graphics().IsGL() {
+		glfw.PollEvents()
+		u.swapBuffers()
+	}
+}
+
+// restoreExclusiveFullscreen sets the video mode again when the window in exclusive fullscreen mode
+// comes back from the iconified state, e.g., by alt-tab.
+//
+// GLFW iconifies a fullscreen window and restores the original video mode when the window loses
+// focus. Some platforms don't switch the video mode back when the window is restored, so this
+// does it explicitly.
+//
+// restoreExclusiveFullscreen must be called from the main thread.
+func (u *UserInterface) restoreExclusiveFullscreen() {
+	if !u.isFullscreen() || !u.isExclusiveFullscreenVideoMode() {
+		u.fullscreenIconified = false
+		return
+	}
+	if u.window.GetAttrib(glfw.Iconified) == glfw.True {
+		u.fullscreenIconified = true
+		return
+	}
+	if !u.fullscreenIconified {
+		return
+	}
+	if u.window.GetAttrib(glfw.Focused) == glfw.False {
+		return
+	}
+	u.fullscreenIconified = false
+	u.setMonitorForFullscreen()
+	u.toChangeSize = true
+}
+
+func (u *UserInterface) Announce(text string) {
+	if !u.isRunning() {
+		return
+	}
+	_ = u.t.Call(func() error {
+		u.announce(text)
+		return nil
+	})
+}
+
+func (u *UserInterface) SetInputMethodCaretPosition(x, y, height int) {
+	if !u.isRunning() {
+		return
+	}
+	_ = u.t.Call(func() error {
+		u.setInputMethodCaretPosition(x, y, height)
+		return nil
+	})
+}
+
 func (u *UserInterface) IsForeground() bool {
 	if !u.isRunning() {
 		return false
@@ -449,6 +943,143 @@ func (u *UserInterface) IsVsyncEnabled() bool {
 	return r
 }
 
+func (u *UserInterface) SwapInterval() int {
+	u.m.RLock()
+	defer u.m.RUnlock()
+	if !u.vsync {
+		return 0
+	}
+	return u.swapInterval
+}
+
+func (u *UserInterface) SetSwapInterval(interval int) {
+	if interval == 0 {
+		u.SetVsyncEnabled(false)
+		return
+	}
+	u.m.Lock()
+	changed := u.swapInterval != interval
+	u.swapInterval = interval
+	u.m.Unlock()
+
+	if !changed || !u.isRunning() {
+		u.SetVsyncEnabled(true)
+		return
+	}
+	_ = u.t.Call(func() error {
+		u.vsync = true
+		u.updateSwapInterval()
+		return nil
+	})
+}
+
+// hasSwapControlTear reports whether a negative swap interval, i.e., adaptive vsync, is available.
+//
+// hasSwapControlTear must be called from the main thread with the current context.
+func hasSwapControlTear() bool {
+	return glfw.ExtensionSupported("WGL_EXT_swap_control_tear") || glfw.ExtensionSupported("GLX_EXT_swap_control_tear")
+}
+
+// updateSwapInterval applies the vsync state and the swap interval.
+//
+// updateSwapInterval must be called from the main thread.
+func (u *UserInterface) updateSwapInterval() {
+	if u.Graphics().IsGL() {
+		// TODO: (#405) If triple buffering is needed, SwapInterval(0) should be called,
+		// but is this correct? If glfw.SwapInterval(0) and the driver doesn't support triple
+		// buffering, what will happen?
+		if u.vsync {
+			u.m.RLock()
+			interval := u.swapInterval
+			u.m.RUnlock()
+			// In the capture-friendly mode, use adaptive vsync if available. A late frame is presented
+			// immediately with tearing instead of waiting for the next vblank, so the capturing software gets
+			// frames at a steady rate.
+			if interval == 1 && u.isCaptureFriendlyMode() && hasSwapControlTear() {
+				interval = -1
+			}
+			glfw.SwapInterval(interval)
+		} else {
+			glfw.SwapInterval(0)
+		}
+	}
+	u.Graphics().SetVsyncEnabled(u.vsync)
+}
+
+func (u *UserInterface) IsBackgroundTextureUploadEnabled() bool {
+	u.m.RLock()
+	r := u.backgroundUpload
+	u.m.RUnlock()
+	return r
+}
+
+func (u *UserInterface) SetBackgroundTextureUploadEnabled(enabled bool) {
+	u.m.Lock()
+	u.backgroundUpload = enabled
+	u.m.Unlock()
+}
+
+func (u *UserInterface) IsScreenSaverEnabled() bool {
+	return u.isScreenSaverEnabled()
+}
+
+func (u *UserInterface) SetScreenSaverEnabled(enabled bool) {
+	u.m.Lock()
+	u.screenSaverEnabled = enabled
+	u.m.Unlock()
+	if !u.isRunning() {
+		return
+	}
+	_ = u.t.Call(func() error {
+		u.setScreenSaverEnabled(enabled)
+		return nil
+	})
+}
+
+func (u *UserInterface) IsCaptureFriendlyModeEnabled() bool {
+	return u.isCaptureFriendlyMode()
+}
+
+func (u *UserInterface) SetCaptureFriendlyModeEnabled(enabled bool) {
+	u.m.Lock()
+	u.captureFriendlyMode = enabled
+	u.m.Unlock()
+	if !u.isRunning() {
+		return
+	}
+	_ = u.t.Call(func() error {
+		u.setThrottlingEnabled(!enabled)
+		u.updateSwapInterval()
+		return nil
+	})
+}
+
+func (u *UserInterface) IsLowLatencyModeEnabled() bool {
+	u.m.RLock()
+	r := u.lowLatencyMode
+	u.m.RUnlock()
+	return r
+}
+
+func (u *UserInterface) SetLowLatencyModeEnabled(enabled bool) {
+	u.m.Lock()
+	u.lowLatencyMode = enabled
+	u.m.Unlock()
+}
+
+func (u *UserInterface) MaxQueuedFrames() int {
+	u.m.RLock()
+	r := u.maxQueuedFrames
+	u.m.RUnlock()
+	return r
+}
+
+func (u *UserInterface) SetMaxQueuedFrames(frames int) {
+	u.m.Lock()
+	u.maxQueuedFrames = frames
+	u.m.Unlock()
+}
+
 func (u *UserInterface) CursorMode() driver.CursorMode {
 	if !u.isRunning() {
 		return u.getInitCursorMode()
@@ -493,6 +1124,58 @@ func (u *UserInterface) SetCursorMode(mode driver.CursorMode) {
 	})
 }
 
+func (u *UserInterface) CursorShape() driver.CursorShape {
+	return u.getCursorShape()
+}
+
+func (u *UserInterface) SetCursorShape(shape driver.CursorShape) {
+	u.setCursorShape(shape)
+	if !u.isRunning() {
+		return
+	}
+	_ = u.t.Call(func() error {
+		u.updateCursorShape()
+		return nil
+	})
+}
+
+// updateCursorShape applies the current cursor shape to the window.
+//
+// updateCursorShape must be called from the main thread.
+func (u *UserInterface) updateCursorShape() {
+	shape := u.getCursorShape()
+	if shape == driver.CursorShapeDefault {
+		// nil is the default arrow cursor.
+		u.window.SetCursor(nil)
+		return
+	}
+
+	c, ok := u.cursors[shape]
+	if !ok {
+		var s glfw.StandardCursor
+		switch shape {
+		case driver.CursorShapeText:
+			s = glfw.IBeamCursor
+		case driver.CursorShapeCrosshair:
+			s = glfw.CrosshairCursor
+		case driver.CursorShapePointer:
+			s = glfw.HandCursor
+		case driver.CursorShapeEWResize:
+			s = glfw.HResizeCursor
+		case driver.CursorShapeNSResize:
+			s = glfw.VResizeCursor
+		default:
+			panic(fmt.Sprintf("glfw: invalid cursor shape: %d", shape))
+		}
+		c = glfw.CreateStandardCursor(s)
+		if u.cursors == nil {
+			u.cursors = map[driver.CursorShape]*glfw.Cursor{}
+		}
+		u.cursors[shape] = c
+	}
+	u.window.SetCursor(c)
+}
+
 func (u *UserInterface) DeviceScaleFactor() float64 {
 	if !u.isRunning() {
 		return devicescale.GetAt(u.initMonitor.GetPos())
@@ -526,6 +1209,15 @@ func (u *UserInterface) Run(uicontext driver.UIContext) error {
 	u.t = thread.New()
 	u.Graphics().SetThread(u.t)
 
+	// Tear down the window and the graphics context when the game panics, or the OS might be left with a stuck
+	// fullscreen window with the changed video mode.
+	hooks.AppendHookOnPanic(func() {
+		_ = u.t.Call(func() error {
+			u.tearDownOnPanic()
+			return nil
+		})
+	})
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	ch := make(chan error, 1)
@@ -543,6 +1235,33 @@ func (u *UserInterface) Run(uicontext driver.UIContext) error {
 	return <-ch
 }
 
+// tearDownOnPanic waits for the GPU and terminates GLFW. Terminating GLFW destroys the windows, which restores the
+// video mode and releases the cursor.
+//
+// tearDownOnPanic must be called from the main thread.
+func (u *UserInterface) tearDownOnPanic() {
+	if u.Graphics().IsGL() {
+		if g, ok := u.Graphics().(interface{ Finish() }); ok {
+			g.Finish()
+		}
+	}
+	u.terminate()
+}
+
+// terminate terminates GLFW only once.
+//
+// terminate must be called from the main thread.
+func (u *UserInterface) terminate() {
+	if u.terminated {
+		return
+	}
+	u.releaseCursor()
+	u.destroyLoaderContext()
+	clock.StopWatchingSleep()
+	glfw.Terminate()
+	u.terminated = true
+}
+
 func (u *UserInterface) RunWithoutMainLoop(width, height int, scale float64, title string, context driver.UIContext) <-chan error {
 	panic("glfw: RunWithoutMainLoop is not implemented")
 }
@@ -570,6 +1289,8 @@ func (u *UserInterface) createWindow() error {
 
 	u.window.SetInputMode(glfw.StickyMouseButtonsMode, glfw.True)
 	u.window.SetInputMode(glfw.StickyKeysMode, glfw.True)
+	// Do not enable the raw mouse motion. On Windows, the rawinput package registers the mouse usage of Raw Input
+	// with its own window, and the registration by GLFW would override it.
 
 	mode := glfw.CursorNormal
 	switch u.getInitCursorMode() {
@@ -579,6 +1300,7 @@ func (u *UserInterface) createWindow() error {
 		mode = glfw.CursorDisabled
 	}
 	u.window.SetInputMode(glfw.CursorMode, mode)
+	u.updateCursorShape()
 	u.window.SetTitle(u.title)
 	// TODO: Set icons
 
@@ -596,6 +1318,50 @@ func (u *UserInterface) createWindow() error {
 	return nil
 }
 
+// createLoaderContext creates a hidden window that has an OpenGL context shared with the main window, and passes
+// it to the graphics driver to upload textures in background.
+//
+// createLoaderContext must be called from the main thread.
+func (u *UserInterface) createLoaderContext() error {
+	if !u.Graphics().IsGL() || !u.IsBackgroundTextureUploadEnabled() {
+		return nil
+	}
+	g, ok := u.Graphics().(interface{ SetLoaderContext(func(), func()) })
+	if !ok {
+		return nil
+	}
+
+	glfw.WindowHint(glfw.Visible, glfw.False)
+	w, err := glfw.CreateWindow(1, 1, "", nil, u.window)
+	// Reset the hints so that the hints for the loader window don't affect windows created later.
+	glfw.DefaultWindowHints()
+	if err != nil {
+		return err
+	}
+	u.loaderWindow = w
+	g.SetLoaderContext(func() {
+		w.MakeContextCurrent()
+	}, func() {
+		glfw.DetachCurrentContext()
+	})
+	return nil
+}
+
+// destroyLoaderContext stops the loader thread and destroys the loader window.
+//
+// destroyLoaderContext must be called from the main thread.
+func (u *UserInterface) destroyLoaderContext() {
+	if u.loaderWindow == nil {
+		return
+	}
+	// The context must not be current on the loader thread when the window is destroyed.
+	if g, ok := u.Graphics().(interface{ CloseLoaderContext() }); ok {
+		g.CloseLoaderContext()
+	}
+	u.loaderWindow.Destroy()
+	u.loaderWindow = nil
+}
+
 func (u *UserInterface) run(context driver.UIContext) error {
 	if err := u.t.Call(func() error {
 		// The window is created at initialize().
@@ -636,6 +1402,11 @@ func (u *UserInterface) run(context driver.UIContext) error {
 		if err := u.createWindow(); err != nil {
 			return err
 		}
+		if err := u.createLoaderContext(); err != nil {
+			return err
+		}
+		// The watching is stopped at terminate.
+		clock.StartWatchingSleep()
 
 		if i := u.getInitIconImages(); i != nil {
 			u.window.SetIcon(i)
@@ -655,8 +1426,21 @@ func (u *UserInterface) run(context driver.UIContext) error {
 		u.title = u.getInitTitle()
 		u.window.SetTitle(u.title)
 		u.window.Show()
+		if !u.isScreenSaverEnabled() {
+			u.setScreenSaverEnabled(false)
+		}
+		if u.isCaptureFriendlyMode() {
+			u.setThrottlingEnabled(false)
+		}
 		return nil
 	})
+	// Enable the screen saver again when the game ends.
+	defer func() {
+		_ = u.t.Call(func() error {
+			u.setScreenSaverEnabled(true)
+			return nil
+		})
+	}()
 
 	var w unsafe.Pointer
 	_ = u.t.Call(func() error {
@@ -692,9 +1476,7 @@ func (u *UserInterface) updateSize(context driver.UIContext) {
 		_ = u.t.Call(func() error {
 			var ww, wh int
 			if u.isFullscreen() {
-				v := u.currentMonitor().GetVideoMode()
-				ww = v.Width
-				wh = v.Height
+				ww, wh = u.fullscreenSize()
 			} else {
 				ww, wh = u.windowWidth, u.windowHeight
 			}
@@ -731,13 +1513,23 @@ func (u *UserInterface) update(context driver.UIContext) error {
 
 	_ = u.t.Call(func() error {
 		glfw.PollEvents()
+		u.restoreExclusiveFullscreen()
+		u.confineCursor()
 		return nil
 	})
 	u.input.update(u.window, context)
 	_ = u.t.Call(func() error {
 		defer hooks.ResumeAudio()
 
-		for !u.isRunnableInBackground() && u.window.GetAttrib(glfw.Focused) == 0 {
+		// The wait for the focus is not a suspension of the process.
+		start := time.Now()
+		defer func() {
+			clock.AddIdleTime(time.Since(start))
+		}()
+
+		// In the capture-friendly mode, the game keeps running so that capturing software can capture the window
+		// even when the window is unfocused or occluded.
+		for !u.isRunnableInBackground() && !u.isCaptureFriendlyMode() && u.window.GetAttrib(glfw.Focused) == 0 {
 			hooks.SuspendAudio()
 			// Wait for an arbitrary period to avoid busy loop.
 			time.Sleep(time.Second / 60)
@@ -775,7 +1567,7 @@ func (u *UserInterface) update(context driver.UIContext) error {
 func (u *UserInterface) loop(context driver.UIContext) error {
 	defer func() {
 		_ = u.t.Call(func() error {
-			glfw.Terminate()
+			u.terminate()
 			return nil
 		})
 	}()
@@ -802,6 +1594,19 @@ func (u *UserInterface) loop(context driver.UIContext) error {
 			u.swapBuffers()
 			return nil
 		})
+
+		// In low latency mode, wait for the GPU to finish the frame so that the CPU doesn't run ahead
+		// and queue more frames.
+		if u.IsLowLatencyModeEnabled() {
+			if g, ok := u.Graphics().(interface{ Finish() }); ok {
+				g.Finish()
+			}
+		} else if g, ok := u.Graphics().(interface{ WaitForQueuedFrames(max int) }); ok {
+			// Limit the number of the frames the GPU is processing with fences. This is lighter than
+			// the low latency mode, which waits for the GPU to finish every frame.
+			g.WaitForQueuedFrames(u.MaxQueuedFrames())
+		}
+
 		if unfocused {
 			t2 = time.Now()
 		}
@@ -851,18 +1656,11 @@ func (u *UserInterface) setWindowSize(width, height int, fullscreen bool, vsync
 			if u.origPosX == invalidPos || u.origPosY == invalidPos {
 				u.origPosX, u.origPosY = u.window.GetPos()
 			}
-			m := u.currentMonitor()
-			v := m.GetVideoMode()
-			u.window.SetMonitor(m, 0, 0, v.Width, v.Height, v.RefreshRate)
-
-			// Swapping buffer is necesary to prevent the image lag (#1004).
-			// TODO: This might not work when vsync is disabled.
-			if u.Graphics().IsGL() {
-				glfw.PollEvents()
-				u.swapBuffers()
-			}
+			u.setMonitorForFullscreen()
 		} else {
-			if u.window.GetMonitor() != nil {
+			if u.spanned {
+				u.leaveSpannedFullscreen()
+			} else if u.window.GetMonitor() != nil {
 				if u.Graphics().IsGL() {
 					// When OpenGL is used, swapping buffer is enough to solve the image-lag
 					// issue (#1004). Rather, recreating window destroys GPU resources.
@@ -942,21 +1740,10 @@ func (u *UserInterface) setWindowSize(width, height int, fullscreen bool, vsync
 		u.windowWidth = width
 		u.windowHeight = height
 
-		if u.Graphics().IsGL() {
-			// SwapInterval is affected by the current monitor of the window.
-			// This needs to be called at least after SetMonitor.
-			// Without SwapInterval after SetMonitor, vsynch doesn't work (#375).
-			//
-			// TODO: (#405) If triple buffering is needed, SwapInterval(0) should be called,
-			// but is this correct? If glfw.SwapInterval(0) and the driver doesn't support triple
-			// buffering, what will happen?
-			if u.vsync {
-				glfw.SwapInterval(1)
-			} else {
-				glfw.SwapInterval(0)
-			}
-		}
-		u.Graphics().SetVsyncEnabled(vsync)
+		// SwapInterval is affected by the current monitor of the window.
+		// This needs to be called at least after SetMonitor.
+		// Without SwapInterval after SetMonitor, vsynch doesn't work (#375).
+		u.updateSwapInterval()
 
 		u.toChangeSize = true
 		return nil
@@ -18,9 +18,12 @@
 package glfw
 
 // #cgo CFLAGS: -x objective-c
-// #cgo LDFLAGS: -framework AppKit
+// #cgo LDFLAGS: -framework AppKit -framework IOKit
 //
 // #import <AppKit/AppKit.h>
+// #import <IOKit/pwr_mgt/IOPMLib.h>
+// #include <math.h>
+// #include <stdlib.h>
 //
 // static void currentMonitorPos(void* windowPtr, int* x, int* y) {
 //   NSScreen* screen = [NSScreen mainScreen];
@@ -39,9 +42,108 @@ package glfw
 //   *x = bounds.origin.x;
 //   *y = bounds.origin.y;
 // }
+//
+// static void announce(void* windowPtr, const char* text) {
+//   id element = NSApp;
+//   if (windowPtr) {
+//     element = (NSWindow*)windowPtr;
+//   }
+//   NSDictionary* userInfo = @{
+//     NSAccessibilityAnnouncementKey: [NSString stringWithUTF8String:text],
+//     NSAccessibilityPriorityKey: @(NSAccessibilityPriorityHigh),
+//   };
+//   NSAccessibilityPostNotificationWithUserInfo(element, NSAccessibilityAnnouncementRequestedNotification, userInfo);
+// }
+//
+// static id cursorClipMonitor;
+// static CGRect cursorClipRect;
+//
+// static void confineCursorPosition() {
+//   // CGWarpMouseCursorPosition takes the global coordinates whose origin is the upper-left corner of the main screen.
+//   CGFloat screenHeight = [[[NSScreen screens] objectAtIndex:0] frame].size.height;
+//   NSPoint p = [NSEvent mouseLocation];
+//   CGPoint pos = CGPointMake(p.x, screenHeight - p.y);
+//   CGPoint newPos = pos;
+//   newPos.x = fmax(fmin(newPos.x, CGRectGetMaxX(cursorClipRect) - 1), CGRectGetMinX(cursorClipRect));
+//   newPos.y = fmax(fmin(newPos.y, CGRectGetMaxY(cursorClipRect) - 1), CGRectGetMinY(cursorClipRect));
+//   if (CGPointEqualToPoint(pos, newPos)) {
+//     return;
+//   }
+//   CGWarpMouseCursorPosition(newPos);
+//   // Without this, the cursor doesn't move for a while after warping.
+//   CGAssociateMouseAndMouseCursorPosition(true);
+// }
+//
+// static void clipCursor(void* windowPtr, double x, double y, double width, double height) {
+//   NSWindow* window = (NSWindow*)windowPtr;
+//   NSRect content = [window contentRectForFrameRect:[window frame]];
+//   CGFloat screenHeight = [[[NSScreen screens] objectAtIndex:0] frame].size.height;
+//   cursorClipRect = CGRectMake(content.origin.x + x, screenHeight - (content.origin.y + content.size.height) + y, width, height);
+//   if (cursorClipMonitor) {
+//     return;
+//   }
+//   // Confine the cursor at every mouse event instead of every frame.
+//   NSEventMask mask = NSEventMaskMouseMoved | NSEventMaskLeftMouseDragged |
+//       NSEventMaskRightMouseDragged | NSEventMaskOtherMouseDragged;
+//   cursorClipMonitor = [[NSEvent addLocalMonitorForEventsMatchingMask:mask handler:^NSEvent*(NSEvent* event) {
+//     confineCursorPosition();
+//     return event;
+//   }] retain];
+//   confineCursorPosition();
+// }
+//
+// static void unclipCursor() {
+//   if (!cursorClipMonitor) {
+//     return;
+//   }
+//   [NSEvent removeMonitor:cursorClipMonitor];
+//   [cursorClipMonitor release];
+//   cursorClipMonitor = nil;
+// }
+//
+// static IOPMAssertionID screenSaverAssertionID;
+// static int screenSaverDisabled;
+//
+// static void setScreenSaverEnabled(int enabled) {
+//   if (enabled) {
+//     if (screenSaverDisabled) {
+//       IOPMAssertionRelease(screenSaverAssertionID);
+//       screenSaverDisabled = 0;
+//     }
+//     return;
+//   }
+//   if (screenSaverDisabled) {
+//     return;
+//   }
+//   if (IOPMAssertionCreateWithName(kIOPMAssertionTypePreventUserIdleDisplaySleep, kIOPMAssertionLevelOn,
+//       CFSTR("Ebiten game"), &screenSaverAssertionID) == kIOReturnSuccess) {
+//     screenSaverDisabled = 1;
+//   }
+// }
+//
+// static id throttlingActivity;
+//
+// static void setThrottlingEnabled(int enabled) {
+//   if (enabled) {
+//     if (throttlingActivity) {
+//       [[NSProcessInfo processInfo] endActivity:throttlingActivity];
+//       [throttlingActivity release];
+//       throttlingActivity = nil;
+//     }
+//     return;
+//   }
+//   if (throttlingActivity) {
+//     return;
+//   }
+//   // Disable App Nap, which throttles the application when its windows are occluded.
+//   throttlingActivity = [[[NSProcessInfo processInfo]
+//       beginActivityWithOptions:NSActivityUserInitiatedAllowingIdleSystemSleep | NSActivityLatencyCritical
+//                         reason:@"Presenting frames for capturing"] retain];
+// }
 import "C"
 
 import (
+	"image"
 	"unsafe"
 
 	"github.com/hajimehoshi/ebiten/internal/glfw"
@@ -51,6 +153,10 @@ func (u *UserInterface) glfwScale() float64 {
 	return 1
 }
 
+func isGamescope() bool {
+	return false
+}
+
 func adjustWindowPosition(x, y int) (int, int) {
 	return x, y
 }
@@ -75,3 +181,50 @@ func (u *UserInterface) currentMonitorFromPosition() *glfw.Monitor {
 func (u *UserInterface) nativeWindow() unsafe.Pointer {
 	return u.window.GetCocoaWindow()
 }
+
+// announce must be called from the main thread.
+func (u *UserInterface) announce(text string) {
+	var win unsafe.Pointer
+	if u.window != nil {
+		win = u.window.GetCocoaWindow()
+	}
+	t := C.CString(text)
+	defer C.free(unsafe.Pointer(t))
+	C.announce(win, t)
+}
+
+// clipCursor confines the cursor in the region r in device-independent pixels with an event monitor.
+//
+// clipCursor must be called from the main thread.
+func (u *UserInterface) clipCursor(r image.Rectangle) bool {
+	C.clipCursor(u.window.GetCocoaWindow(), C.double(r.Min.X), C.double(r.Min.Y), C.double(r.Dx()), C.double(r.Dy()))
+	return true
+}
+
+// unclipCursor must be called from the main thread.
+func (u *UserInterface) unclipCursor() {
+	C.unclipCursor()
+}
+
+// setInputMethodCaretPosition must be called from the main thread.
+func (u *UserInterface) setInputMethodCaretPosition(x, y, height int) {
+	// TODO: Implement this.
+}
+
+// setScreenSaverEnabled must be called from the main thread.
+func (u *UserInterface) setScreenSaverEnabled(enabled bool) {
+	v := C.int(0)
+	if enabled {
+		v = 1
+	}
+	C.setScreenSaverEnabled(v)
+}
+
+// setThrottlingEnabled must be called from the main thread.
+func (u *UserInterface) setThrottlingEnabled(enabled bool) {
+	v := C.int(0)
+	if enabled {
+		v = 1
+	}
+	C.setThrottlingEnabled(v)
+}
@@ -19,6 +19,9 @@
 package glfw
 
 import (
+	"os"
+	"os/exec"
+	"time"
 	"unsafe"
 
 	"github.com/hajimehoshi/ebiten/internal/glfw"
@@ -28,6 +31,14 @@ func (u *UserInterface) glfwScale() float64 {
 	return u.deviceScaleFactor()
 }
 
+// isGamescope reports whether the application runs on gamescope, the compositor used by Steam Deck.
+//
+// isGamescope only detects gamescope by the environment variables. The only adjustment for gamescope is that the
+// fullscreen video mode is not switched, as gamescope doesn't support changing video modes.
+func isGamescope() bool {
+	return os.Getenv("GAMESCOPE_WAYLAND_DISPLAY") != "" || os.Getenv("SteamDeck") == "1"
+}
+
 func adjustWindowPosition(x, y int) (int, int) {
 	return x, y
 }
@@ -44,3 +55,55 @@ func (u *UserInterface) nativeWindow() unsafe.Pointer {
 	// TODO: Implement this.
 	return nil
 }
+
+// announce must be called from the main thread.
+func (u *UserInterface) announce(text string) {
+	// Announcing requires the application to be an AT-SPI accessible object on the accessibility bus, which
+	// Ebiten doesn't provide. Do nothing.
+}
+
+// setInputMethodCaretPosition must be called from the main thread.
+func (u *UserInterface) setInputMethodCaretPosition(x, y, height int) {
+	// TODO: Implement this.
+}
+
+// screenSaverResetterDone is closed to stop resetting the screen saver.
+//
+// screenSaverResetterDone must be manipulated on the main thread.
+var screenSaverResetterDone chan struct{}
+
+// setScreenSaverEnabled must be called from the main thread.
+func (u *UserInterface) setScreenSaverEnabled(enabled bool) {
+	if enabled {
+		if screenSaverResetterDone != nil {
+			close(screenSaverResetterDone)
+			screenSaverResetterDone = nil
+		}
+		return
+	}
+	if screenSaverResetterDone != nil {
+		return
+	}
+
+	// Inhibiting via D-Bus requires keeping the connection. Instead, reset the idle timer of the screen saver
+	// periodically with xdg-screensaver, which supports major desktop environments.
+	done := make(chan struct{})
+	screenSaverResetterDone = done
+	go func() {
+		t := time.NewTicker(30 * time.Second)
+		defer t.Stop()
+		for {
+			_ = exec.Command("xdg-screensaver", "reset").Run()
+			select {
+			case <-t.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// setThrottlingEnabled must be called from the main thread.
+func (u *UserInterface) setThrottlingEnabled(enabled bool) {
+	// Do nothing.
+}
@@ -18,6 +18,8 @@ package glfw
 
 import (
 	"fmt"
+	"image"
+	"syscall"
 	"unsafe"
 
 	"golang.org/x/sys/windows"
@@ -28,6 +30,16 @@ import (
 const (
 	smCyCaption             = 4
 	monitorDefaultToNearest = 2
+
+	cfsPoint   = 0x0002
+	cfsExclude = 0x0080
+
+	esSystemRequired  = 0x00000001
+	esDisplayRequired = 0x00000002
+	esContinuous      = 0x80000000
+
+	notificationKindOther                     = 4
+	notificationProcessingImportantMostRecent = 1
 )
 
 type rect struct {
@@ -37,6 +49,38 @@ type rect struct {
 	bottom int32
 }
 
+type point struct {
+	x int32
+	y int32
+}
+
+type compositionForm struct {
+	dwStyle      uint32
+	ptCurrentPos point
+	rcArea       rect
+}
+
+type candidateForm struct {
+	dwIndex      uint32
+	dwStyle      uint32
+	ptCurrentPos point
+	rcArea       rect
+}
+
+type iUnknownVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+}
+
+type iUnknown struct {
+	vtbl *iUnknownVtbl
+}
+
+func (i *iUnknown) release() {
+	syscall.Syscall(i.vtbl.Release, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+}
+
 type monitorInfo struct {
 	cbSize    uint32
 	rcMonitor rect
@@ -46,11 +90,30 @@ type monitorInfo struct {
 
 var (
 	// user32 is defined at hideconsole_windows.go
+	// kernel32 is defined at hideconsole_windows.go
+	procSetThreadExecutionState = kernel32.NewProc("SetThreadExecutionState")
+
 	procGetSystemMetrics    = user32.NewProc("GetSystemMetrics")
 	procGetActiveWindow     = user32.NewProc("GetActiveWindow")
 	procGetForegroundWindow = user32.NewProc("GetForegroundWindow")
 	procMonitorFromWindow   = user32.NewProc("MonitorFromWindow")
 	procGetMonitorInfoW     = user32.NewProc("GetMonitorInfoW")
+	procClientToScreen      = user32.NewProc("ClientToScreen")
+	procClipCursor          = user32.NewProc("ClipCursor")
+
+	imm32                       = windows.NewLazySystemDLL("imm32.dll")
+	procImmGetContext           = imm32.NewProc("ImmGetContext")
+	procImmReleaseContext       = imm32.NewProc("ImmReleaseContext")
+	procImmSetCompositionWindow = imm32.NewProc("ImmSetCompositionWindow")
+	procImmSetCandidateWindow   = imm32.NewProc("ImmSetCandidateWindow")
+
+	oleaut32           = windows.NewLazySystemDLL("oleaut32.dll")
+	procSysAllocString = oleaut32.NewProc("SysAllocString")
+	procSysFreeString  = oleaut32.NewProc("SysFreeString")
+
+	uiautomationcore              = windows.NewLazySystemDLL("uiautomationcore.dll")
+	procUiaHostProviderFromHwnd   = uiautomationcore.NewProc("UiaHostProviderFromHwnd")
+	procUiaRaiseNotificationEvent = uiautomationcore.NewProc("UiaRaiseNotificationEvent")
 )
 
 func getSystemMetrics(nIndex int) (int, error) {
@@ -99,10 +162,65 @@ func getMonitorInfoW(hMonitor uintptr, lpmi *monitorInfo) error {
 	return nil
 }
 
+func clientToScreen(hwnd uintptr, lpPoint *point) error {
+	r, _, e := procClientToScreen.Call(hwnd, uintptr(unsafe.Pointer(lpPoint)))
+	if e != nil && e.(windows.Errno) != 0 {
+		return fmt.Errorf("ui: ClientToScreen failed: error code: %d", e)
+	}
+	if r == 0 {
+		return fmt.Errorf("ui: ClientToScreen failed: returned value: %d", r)
+	}
+	return nil
+}
+
+func clipCursor(lpRect *rect) error {
+	r, _, e := procClipCursor.Call(uintptr(unsafe.Pointer(lpRect)))
+	if e != nil && e.(windows.Errno) != 0 {
+		return fmt.Errorf("ui: ClipCursor failed: error code: %d", e)
+	}
+	if r == 0 {
+		return fmt.Errorf("ui: ClipCursor failed: returned value: %d", r)
+	}
+	return nil
+}
+
+func sysAllocString(str string) (uintptr, error) {
+	s, err := windows.UTF16PtrFromString(str)
+	if err != nil {
+		return 0, err
+	}
+	r, _, _ := procSysAllocString.Call(uintptr(unsafe.Pointer(s)))
+	if r == 0 {
+		return 0, fmt.Errorf("ui: SysAllocString failed")
+	}
+	return r, nil
+}
+
+func uiaHostProviderFromHwnd(hwnd uintptr) (*iUnknown, error) {
+	var provider *iUnknown
+	r, _, _ := procUiaHostProviderFromHwnd.Call(hwnd, uintptr(unsafe.Pointer(&provider)))
+	if r != 0 {
+		return nil, fmt.Errorf("ui: UiaHostProviderFromHwnd failed: HRESULT: %d", r)
+	}
+	return provider, nil
+}
+
+func uiaRaiseNotificationEvent(provider *iUnknown, notificationKind, notificationProcessing int, displayString, activityID uintptr) error {
+	r, _, _ := procUiaRaiseNotificationEvent.Call(uintptr(unsafe.Pointer(provider)), uintptr(notificationKind), uintptr(notificationProcessing), displayString, activityID)
+	if r != 0 {
+		return fmt.Errorf("ui: UiaRaiseNotificationEvent failed: HRESULT: %d", r)
+	}
+	return nil
+}
+
 func (u *UserInterface) glfwScale() float64 {
 	return u.deviceScaleFactor()
 }
 
+func isGamescope() bool {
+	return false
+}
+
 func adjustWindowPosition(x, y int) (int, int) {
 	// As the video width/height might be wrong,
 	// adjust x/y at least to enable to handle the window (#328)
@@ -166,3 +284,102 @@ func (u *UserInterface) currentMonitorFromPosition() *glfw.Monitor {
 func (u *UserInterface) nativeWindow() unsafe.Pointer {
 	return u.window.GetWin32Window()
 }
+
+// setInputMethodCaretPosition must be called from the main thread.
+func (u *UserInterface) setInputMethodCaretPosition(x, y, height int) {
+	hwnd := uintptr(u.nativeWindow())
+	himc, _, _ := procImmGetContext.Call(hwnd)
+	if himc == 0 {
+		return
+	}
+	defer procImmReleaseContext.Call(hwnd, himc)
+
+	pt := point{x: int32(x), y: int32(y)}
+	cf := compositionForm{
+		dwStyle:      cfsPoint,
+		ptCurrentPos: pt,
+	}
+	procImmSetCompositionWindow.Call(himc, uintptr(unsafe.Pointer(&cf)))
+
+	// Put the candidate window so that it doesn't cover the caret.
+	caf := candidateForm{
+		dwIndex:      0,
+		dwStyle:      cfsExclude,
+		ptCurrentPos: pt,
+		rcArea: rect{
+			left:   int32(x),
+			top:    int32(y),
+			right:  int32(x + 1),
+			bottom: int32(y + height),
+		},
+	}
+	procImmSetCandidateWindow.Call(himc, uintptr(unsafe.Pointer(&caf)))
+}
+
+// clipCursor confines the cursor in the region r in device-independent pixels with ClipCursor.
+//
+// clipCursor must be called from the main thread.
+func (u *UserInterface) clipCursor(r image.Rectangle) bool {
+	// ClipCursor takes the screen coordinates. Convert the client coordinates.
+	pt := point{}
+	if err := clientToScreen(uintptr(u.nativeWindow()), &pt); err != nil {
+		return false
+	}
+	rc := rect{
+		left:   pt.x + int32(u.toDeviceDependentPixel(float64(r.Min.X))),
+		top:    pt.y + int32(u.toDeviceDependentPixel(float64(r.Min.Y))),
+		right:  pt.x + int32(u.toDeviceDependentPixel(float64(r.Max.X))),
+		bottom: pt.y + int32(u.toDeviceDependentPixel(float64(r.Max.Y))),
+	}
+	return clipCursor(&rc) == nil
+}
+
+// unclipCursor must be called from the main thread.
+func (u *UserInterface) unclipCursor() {
+	// The confinement by ClipCursor is shared among the system. Release it explicitly.
+	clipCursor(nil)
+}
+
+// announce must be called from the main thread.
+func (u *UserInterface) announce(text string) {
+	// UiaRaiseNotificationEvent is available on Windows 10 version 1709 or later.
+	if procUiaRaiseNotificationEvent.Find() != nil {
+		return
+	}
+
+	// Raise the notification on the provider of the window itself, as Ebiten doesn't have its own UI element tree.
+	provider, err := uiaHostProviderFromHwnd(uintptr(u.nativeWindow()))
+	if err != nil {
+		return
+	}
+	defer provider.release()
+
+	str, err := sysAllocString(text)
+	if err != nil {
+		return
+	}
+	defer procSysFreeString.Call(str)
+
+	id, err := sysAllocString("ebiten-announcement")
+	if err != nil {
+		return
+	}
+	defer procSysFreeString.Call(id)
+
+	_ = uiaRaiseNotificationEvent(provider, notificationKindOther, notificationProcessingImportantMostRecent, str, id)
+}
+
+// setScreenSaverEnabled must be called from the main thread.
+func (u *UserInterface) setScreenSaverEnabled(enabled bool) {
+	// The execution state is associated with the thread, and the main thread lives until the game ends.
+	if enabled {
+		procSetThreadExecutionState.Call(esContinuous)
+		return
+	}
+	procSetThreadExecutionState.Call(esContinuous | esSystemRequired | esDisplayRequired)
+}
+
+// setThrottlingEnabled must be called from the main thread.
+func (u *UserInterface) setThrottlingEnabled(enabled bool) {
+	// Windows doesn't throttle a running application by its occlusion.
+}
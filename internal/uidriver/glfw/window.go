@@ -168,3 +168,28 @@ func (w *window) SetTitle(title string) {
 		return nil
 	})
 }
+
+func (w *window) IsFocused() bool {
+	return w.attrib(glfw.Focused)
+}
+
+func (w *window) IsMinimized() bool {
+	return w.attrib(glfw.Iconified)
+}
+
+func (w *window) IsHovered() bool {
+	return w.attrib(glfw.Hovered)
+}
+
+// attrib reports whether the window attribute is true. attrib returns false before the main loop starts.
+func (w *window) attrib(attrib glfw.Hint) bool {
+	if !w.ui.isRunning() {
+		return false
+	}
+	v := false
+	_ = w.ui.t.Call(func() error {
+		v = w.ui.window.GetAttrib(attrib) == glfw.True
+		return nil
+	})
+	return v
+}
@@ -17,6 +17,7 @@
 package js
 
 import (
+	"sort"
 	"syscall/js"
 	"unicode"
 
@@ -36,6 +37,12 @@ type gamePad struct {
 	axes          [16]float64
 	buttonNum     int
 	buttonPressed [256]bool
+
+	hasGyro          bool
+	gyro             [3]float64
+	hasAccelerometer bool
+	accelerometer    [3]float64
+	touchpadTouches  map[int][2]float64
 }
 
 type Input struct {
@@ -57,6 +64,11 @@ func (i *Input) CursorPosition() (x, y int) {
 	return int(xf), int(yf)
 }
 
+func (i *Input) LatestCursorPosition() (x, y int) {
+	// The cursor position is updated by events, and is always the latest one.
+	return i.CursorPosition()
+}
+
 func (i *Input) GamepadSDLID(id int) string {
 	// TODO: Implement this. See the implementation of SDL:
 	// https://github.com/spurious/SDL-mirror/blob/master/src/joystick/emscripten/SDL_sysjoystick.c
@@ -236,6 +248,98 @@ func (i *Input) setMouseCursor(x, y int) {
 	i.cursorX, i.cursorY = x, y
 }
 
+func (i *Input) GamepadGyro(id int) (x, y, z float64, ok bool) {
+	if len(i.gamepads) <= id {
+		return 0, 0, 0, false
+	}
+	g := &i.gamepads[id]
+	if !g.hasGyro {
+		return 0, 0, 0, false
+	}
+	return g.gyro[0], g.gyro[1], g.gyro[2], true
+}
+
+func (i *Input) GamepadAccelerometer(id int) (x, y, z float64, ok bool) {
+	if len(i.gamepads) <= id {
+		return 0, 0, 0, false
+	}
+	g := &i.gamepads[id]
+	if !g.hasAccelerometer {
+		return 0, 0, 0, false
+	}
+	return g.accelerometer[0], g.accelerometer[1], g.accelerometer[2], true
+}
+
+func (i *Input) GamepadTouchpadTouchIDs(id int) []int {
+	if len(i.gamepads) <= id {
+		return nil
+	}
+	var ids []int
+	for tid := range i.gamepads[id].touchpadTouches {
+		ids = append(ids, tid)
+	}
+	// Keep the order stable across frames, as the iteration order of a map is random.
+	sort.Ints(ids)
+	return ids
+}
+
+func (i *Input) GamepadTouchpadPosition(id int, touchID int) (x, y float64) {
+	if len(i.gamepads) <= id {
+		return 0, 0
+	}
+	p, ok := i.gamepads[id].touchpadTouches[touchID]
+	if !ok {
+		return 0, 0
+	}
+	return p[0], p[1]
+}
+
+func isNullOrUndefined(v js.Value) bool {
+	return jsutil.Equal(v, js.Undefined()) || jsutil.Equal(v, js.Null())
+}
+
+// float32ArrayToFloat64s copies the values of the given Float32Array to dst,
+// and reports whether the array has enough values.
+func float32ArrayToFloat64s(dst []float64, v js.Value) bool {
+	if isNullOrUndefined(v) || v.Get("length").Int() < len(dst) {
+		return false
+	}
+	for i := range dst {
+		dst[i] = v.Index(i).Float()
+	}
+	return true
+}
+
+// updateGamepadExtensions updates the motion sensors and the touchpad with the Gamepad extensions API.
+// Only some browsers like Firefox support the API.
+func (g *gamePad) updateGamepadExtensions(gamepad js.Value) {
+	g.hasGyro = false
+	g.hasAccelerometer = false
+	if pose := gamepad.Get("pose"); !isNullOrUndefined(pose) {
+		g.hasGyro = float32ArrayToFloat64s(g.gyro[:], pose.Get("angularVelocity"))
+		g.hasAccelerometer = float32ArrayToFloat64s(g.accelerometer[:], pose.Get("linearAcceleration"))
+	}
+
+	for id := range g.touchpadTouches {
+		delete(g.touchpadTouches, id)
+	}
+	touches := gamepad.Get("touchEvents")
+	if isNullOrUndefined(touches) {
+		return
+	}
+	if g.touchpadTouches == nil {
+		g.touchpadTouches = map[int][2]float64{}
+	}
+	for j := 0; j < touches.Get("length").Int(); j++ {
+		t := touches.Index(j)
+		var p [2]float64
+		if !float32ArrayToFloat64s(p[:], t.Get("position")) {
+			continue
+		}
+		g.touchpadTouches[t.Get("touchId").Int()] = p
+	}
+}
+
 func (i *Input) UpdateGamepads() {
 	nav := js.Global().Get("navigator")
 	if jsutil.Equal(nav.Get("getGamepads"), js.Undefined()) {
@@ -273,6 +377,8 @@ func (i *Input) UpdateGamepads() {
 			}
 			i.gamepads[id].buttonPressed[b] = buttons.Index(b).Get("pressed").Bool()
 		}
+
+		i.gamepads[id].updateGamepadExtensions(gamepad)
 	}
 }
 
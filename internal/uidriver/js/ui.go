@@ -17,6 +17,7 @@
 package js
 
 import (
+	"image"
 	"log"
 	"runtime"
 	"syscall/js"
@@ -32,7 +33,15 @@ import (
 type UserInterface struct {
 	runnableInBackground bool
 	vsync                bool
+	swapInterval         int
 	running              bool
+	screenSaverEnabled   bool
+	cursorShape          driver.CursorShape
+
+	// wakeLock is the WakeLockSentinel to prevent the screen from dimming.
+	wakeLock           js.Value
+	hasWakeLock        bool
+	wakeLockRequesting bool
 
 	sizeChanged bool
 	contextLost bool
@@ -44,8 +53,10 @@ type UserInterface struct {
 }
 
 var theUI = &UserInterface{
-	sizeChanged: true,
-	vsync:       true,
+	sizeChanged:        true,
+	vsync:              true,
+	swapInterval:       1,
+	screenSaverEnabled: true,
 }
 
 func init() {
@@ -60,6 +71,7 @@ var (
 	window                = js.Global().Get("window")
 	document              = js.Global().Get("document")
 	canvas                js.Value
+	liveRegion            js.Value
 	requestAnimationFrame = window.Get("requestAnimationFrame")
 	setTimeout            = window.Get("setTimeout")
 )
@@ -96,6 +108,22 @@ func (u *UserInterface) IsVsyncEnabled() bool {
 	return u.vsync
 }
 
+func (u *UserInterface) SwapInterval() int {
+	if !u.vsync {
+		return 0
+	}
+	return u.swapInterval
+}
+
+func (u *UserInterface) SetSwapInterval(interval int) {
+	if interval == 0 {
+		u.vsync = false
+		return
+	}
+	u.vsync = true
+	u.swapInterval = interval
+}
+
 func (u *UserInterface) CursorMode() driver.CursorMode {
 	if canvas.Get("style").Get("cursor").String() != "none" {
 		return driver.CursorModeVisible
@@ -115,12 +143,44 @@ func (u *UserInterface) SetCursorMode(mode driver.CursorMode) {
 	}
 
 	if visible {
-		canvas.Get("style").Set("cursor", "auto")
+		canvas.Get("style").Set("cursor", cursorShapeToCSSCursor(u.cursorShape))
 	} else {
 		canvas.Get("style").Set("cursor", "none")
 	}
 }
 
+func (u *UserInterface) CursorShape() driver.CursorShape {
+	return u.cursorShape
+}
+
+func (u *UserInterface) SetCursorShape(shape driver.CursorShape) {
+	u.cursorShape = shape
+	if u.CursorMode() != driver.CursorModeVisible {
+		return
+	}
+	canvas.Get("style").Set("cursor", cursorShapeToCSSCursor(shape))
+}
+
+func cursorShapeToCSSCursor(shape driver.CursorShape) string {
+	switch shape {
+	case driver.CursorShapeText:
+		return "text"
+	case driver.CursorShapeCrosshair:
+		return "crosshair"
+	case driver.CursorShapePointer:
+		return "pointer"
+	case driver.CursorShapeEWResize:
+		return "ew-resize"
+	case driver.CursorShapeNSResize:
+		return "ns-resize"
+	}
+	return "auto"
+}
+
+func (u *UserInterface) SetCursorConfinement(region image.Rectangle) {
+	// Browsers don't provide a way to confine the cursor except for the pointer lock.
+}
+
 func (u *UserInterface) DeviceScaleFactor() float64 {
 	return devicescale.GetAt(0, 0)
 }
@@ -180,12 +240,22 @@ func (u *UserInterface) loop(context driver.UIContext) <-chan error {
 
 	ch := make(chan error)
 	var cf js.Func
+	// skipped is the number of the animation frames skipped since the last update.
+	skipped := 0
 	f := func(this js.Value, args []js.Value) interface{} {
 		if u.contextLost {
 			requestAnimationFrame.Invoke(cf)
 			return nil
 		}
 
+		// With a swap interval more than 1, update the game once every the interval animation frames.
+		if u.vsync && skipped+1 < u.swapInterval {
+			skipped++
+			requestAnimationFrame.Invoke(cf)
+			return nil
+		}
+		skipped = 0
+
 		if err := u.update(); err != nil {
 			ch <- err
 			close(ch)
@@ -217,6 +287,7 @@ func (u *UserInterface) loop(context driver.UIContext) <-chan error {
 			} else {
 				hooks.ResumeAudio()
 			}
+			u.updateWakeLock()
 		}
 	}()
 
@@ -251,6 +322,20 @@ func init() {
 
 	document.Get("body").Call("appendChild", canvas)
 
+	// liveRegion is a visually hidden element to make screen readers announce texts.
+	liveRegion = document.Call("createElement", "div")
+	liveRegion.Call("setAttribute", "role", "status")
+	liveRegion.Call("setAttribute", "aria-live", "assertive")
+	liveRegion.Call("setAttribute", "aria-atomic", "true")
+	liveRegionStyle := liveRegion.Get("style")
+	liveRegionStyle.Set("position", "absolute")
+	liveRegionStyle.Set("width", "1px")
+	liveRegionStyle.Set("height", "1px")
+	liveRegionStyle.Set("overflow", "hidden")
+	liveRegionStyle.Set("clip", "rect(0 0 0 0)")
+	liveRegionStyle.Set("whiteSpace", "nowrap")
+	document.Get("body").Call("appendChild", liveRegion)
+
 	htmlStyle := document.Get("documentElement").Get("style")
 	htmlStyle.Set("height", "100%")
 	htmlStyle.Set("margin", "0")
@@ -435,6 +520,154 @@ func (u *UserInterface) MonitorPosition() (int, int) {
 	return 0, 0
 }
 
+func (u *UserInterface) IsCaptureFriendlyModeEnabled() bool {
+	return false
+}
+
+func (u *UserInterface) SetCaptureFriendlyModeEnabled(enabled bool) {
+	// Browsers throttle hidden documents and there is no way to avoid this.
+}
+
+func (u *UserInterface) IsLowLatencyModeEnabled() bool {
+	return false
+}
+
+func (u *UserInterface) SetLowLatencyModeEnabled(enabled bool) {
+	// Do nothing
+}
+
+func (u *UserInterface) MaxQueuedFrames() int {
+	return 0
+}
+
+func (u *UserInterface) SetMaxQueuedFrames(frames int) {
+	// Do nothing
+}
+
+func (u *UserInterface) IsBackgroundTextureUploadEnabled() bool {
+	return false
+}
+
+func (u *UserInterface) SetBackgroundTextureUploadEnabled(enabled bool) {
+	// Do nothing
+}
+
+func (u *UserInterface) IsScreenSaverEnabled() bool {
+	return u.screenSaverEnabled
+}
+
+func (u *UserInterface) SetScreenSaverEnabled(enabled bool) {
+	u.screenSaverEnabled = enabled
+	u.updateWakeLock()
+}
+
+// updateWakeLock requests or releases the screen wake lock.
+//
+// Browsers release the wake lock when the document is hidden, then updateWakeLock needs to be called regularly to
+// request the lock again.
+func (u *UserInterface) updateWakeLock() {
+	wl := js.Global().Get("navigator").Get("wakeLock")
+	if jsutil.Equal(wl, js.Undefined()) {
+		return
+	}
+
+	if u.screenSaverEnabled || !u.running {
+		if u.hasWakeLock {
+			u.wakeLock.Call("release")
+			u.wakeLock = js.Undefined()
+			u.hasWakeLock = false
+		}
+		return
+	}
+
+	if u.hasWakeLock || u.wakeLockRequesting {
+		return
+	}
+	if document.Get("visibilityState").String() != "visible" {
+		return
+	}
+
+	u.wakeLockRequesting = true
+	var then, catch js.Func
+	then = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		lock := args[0]
+		u.wakeLock = lock
+		u.hasWakeLock = true
+		u.wakeLockRequesting = false
+
+		var onRelease js.Func
+		onRelease = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			if jsutil.Equal(u.wakeLock, lock) {
+				u.wakeLock = js.Undefined()
+				u.hasWakeLock = false
+			}
+			onRelease.Release()
+			return nil
+		})
+		lock.Call("addEventListener", "release", onRelease)
+
+		then.Release()
+		catch.Release()
+		return nil
+	})
+	catch = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		// The request can be rejected, e.g., when the battery is low.
+		u.wakeLockRequesting = false
+		then.Release()
+		catch.Release()
+		return nil
+	})
+	wl.Call("request", "screen").Call("then", then).Call("catch", catch)
+}
+
+func (u *UserInterface) Announce(text string) {
+	// Clear the content first so that the same text is announced again.
+	liveRegion.Set("textContent", "")
+	var f js.Func
+	f = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		liveRegion.Set("textContent", text)
+		f.Release()
+		return nil
+	})
+	window.Call("setTimeout", f, 100)
+}
+
+func (u *UserInterface) SetInputMethodCaretPosition(x, y, height int) {
+	// TODO: Implement this.
+}
+
+func (u *UserInterface) VideoModes() []driver.VideoMode {
+	return nil
+}
+
+func (u *UserInterface) FullscreenVideoMode() driver.VideoMode {
+	return driver.VideoMode{}
+}
+
+func (u *UserInterface) SetFullscreenVideoMode(mode driver.VideoMode) {
+	// Do nothing
+}
+
+func (u *UserInterface) Monitors() []driver.Monitor {
+	return nil
+}
+
+func (u *UserInterface) FullscreenMonitor() int {
+	return -1
+}
+
+func (u *UserInterface) SetFullscreenMonitor(index int) {
+	// Do nothing
+}
+
+func (u *UserInterface) IsFullscreenSpanningEnabled() bool {
+	return false
+}
+
+func (u *UserInterface) SetFullscreenSpanningEnabled(enabled bool) {
+	// Do nothing
+}
+
 func (u *UserInterface) Input() driver.Input {
 	return &u.input
 }
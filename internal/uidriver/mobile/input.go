@@ -38,6 +38,10 @@ func (i *Input) CursorPosition() (x, y int) {
 	return i.ui.adjustPosition(i.cursorX, i.cursorY)
 }
 
+func (i *Input) LatestCursorPosition() (x, y int) {
+	return i.CursorPosition()
+}
+
 func (i *Input) GamepadIDs() []int {
 	return nil
 }
@@ -66,6 +70,22 @@ func (i *Input) IsGamepadButtonPressed(id int, button driver.GamepadButton) bool
 	return false
 }
 
+func (i *Input) GamepadGyro(id int) (x, y, z float64, ok bool) {
+	return 0, 0, 0, false
+}
+
+func (i *Input) GamepadAccelerometer(id int) (x, y, z float64, ok bool) {
+	return 0, 0, 0, false
+}
+
+func (i *Input) GamepadTouchpadTouchIDs(id int) []int {
+	return nil
+}
+
+func (i *Input) GamepadTouchpadPosition(id int, touchID int) (x, y float64) {
+	return 0, 0
+}
+
 func (i *Input) TouchIDs() []int {
 	i.ui.m.RLock()
 	defer i.ui.m.RUnlock()
@@ -19,6 +19,7 @@ package mobile
 import (
 	"context"
 	"fmt"
+	"image"
 	"runtime/debug"
 	"sync"
 
@@ -373,6 +374,18 @@ func (u *UserInterface) SetCursorMode(mode driver.CursorMode) {
 	// Do nothing
 }
 
+func (u *UserInterface) CursorShape() driver.CursorShape {
+	return driver.CursorShapeDefault
+}
+
+func (u *UserInterface) SetCursorShape(shape driver.CursorShape) {
+	// Do nothing
+}
+
+func (u *UserInterface) SetCursorConfinement(region image.Rectangle) {
+	// Do nothing
+}
+
 func (u *UserInterface) IsFullscreen() bool {
 	return false
 }
@@ -404,6 +417,14 @@ func (u *UserInterface) SetVsyncEnabled(enabled bool) {
 	// Do nothing
 }
 
+func (u *UserInterface) SwapInterval() int {
+	return 1
+}
+
+func (u *UserInterface) SetSwapInterval(interval int) {
+	// Do nothing
+}
+
 func (u *UserInterface) DeviceScaleFactor() float64 {
 	return deviceScale()
 }
@@ -420,6 +441,86 @@ func (u *UserInterface) MonitorPosition() (int, int) {
 	return 0, 0
 }
 
+func (u *UserInterface) IsBackgroundTextureUploadEnabled() bool {
+	return false
+}
+
+func (u *UserInterface) SetBackgroundTextureUploadEnabled(enabled bool) {
+	// Do nothing
+}
+
+func (u *UserInterface) IsScreenSaverEnabled() bool {
+	return true
+}
+
+func (u *UserInterface) SetScreenSaverEnabled(enabled bool) {
+	// TODO: Implement this with FLAG_KEEP_SCREEN_ON on Android and idleTimerDisabled on iOS.
+}
+
+func (u *UserInterface) IsCaptureFriendlyModeEnabled() bool {
+	return false
+}
+
+func (u *UserInterface) SetCaptureFriendlyModeEnabled(enabled bool) {
+	// Do nothing
+}
+
+func (u *UserInterface) IsLowLatencyModeEnabled() bool {
+	return false
+}
+
+func (u *UserInterface) SetLowLatencyModeEnabled(enabled bool) {
+	// Do nothing
+}
+
+func (u *UserInterface) MaxQueuedFrames() int {
+	return 0
+}
+
+func (u *UserInterface) SetMaxQueuedFrames(frames int) {
+	// Do nothing
+}
+
+func (u *UserInterface) Announce(text string) {
+	// TODO: Implement this with UIAccessibility on iOS and View.announceForAccessibility on Android.
+}
+
+func (u *UserInterface) SetInputMethodCaretPosition(x, y, height int) {
+	// TODO: Implement this.
+}
+
+func (u *UserInterface) VideoModes() []driver.VideoMode {
+	return nil
+}
+
+func (u *UserInterface) FullscreenVideoMode() driver.VideoMode {
+	return driver.VideoMode{}
+}
+
+func (u *UserInterface) SetFullscreenVideoMode(mode driver.VideoMode) {
+	// Do nothing
+}
+
+func (u *UserInterface) Monitors() []driver.Monitor {
+	return nil
+}
+
+func (u *UserInterface) FullscreenMonitor() int {
+	return -1
+}
+
+func (u *UserInterface) SetFullscreenMonitor(index int) {
+	// Do nothing
+}
+
+func (u *UserInterface) IsFullscreenSpanningEnabled() bool {
+	return false
+}
+
+func (u *UserInterface) SetFullscreenSpanningEnabled(enabled bool) {
+	// Do nothing
+}
+
 func (u *UserInterface) Input() driver.Input {
 	return &u.input
 }
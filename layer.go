@@ -0,0 +1,264 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Layer represents a named layer composited onto the screen by Ebiten.
+//
+// Each layer has its own image with the same size as the screen. The layer images are cleared every frame like the
+// screen image, and are drawn over the screen image in ascending order after the game's Update (or Draw).
+//
+// This API is experimental.
+type Layer struct {
+	name     string
+	order    int
+	seq      int
+	visible  bool
+	mode     CompositeMode
+	shader   *Shader
+	uniforms map[string]interface{}
+	image    *Image
+}
+
+type layers struct {
+	layers []*Layer
+	seq    int
+	drawn  bool
+	m      sync.Mutex
+}
+
+var theLayers = &layers{}
+
+// NewLayer creates a new visible layer with the given name and order.
+//
+// NewLayer panics if a layer with the same name already exists.
+//
+// NewLayer is concurrent-safe.
+func NewLayer(name string, order int) *Layer {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+
+	for _, l := range theLayers.layers {
+		if l.name == name {
+			panic(fmt.Sprintf("ebiten: the layer %q already exists", name))
+		}
+	}
+	l := &Layer{
+		name:    name,
+		order:   order,
+		seq:     theLayers.seq,
+		visible: true,
+	}
+	theLayers.seq++
+	theLayers.layers = append(theLayers.layers, l)
+	theLayers.sort()
+	return l
+}
+
+// LayerByName returns the layer with the given name. LayerByName returns nil if there is no such layer.
+//
+// LayerByName is concurrent-safe.
+func LayerByName(name string) *Layer {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+
+	for _, l := range theLayers.layers {
+		if l.name == name {
+			return l
+		}
+	}
+	return nil
+}
+
+// sort sorts the layers by the order. Layers with the same order are sorted by the creation order.
+func (l *layers) sort() {
+	sort.Slice(l.layers, func(i, j int) bool {
+		if l.layers[i].order != l.layers[j].order {
+			return l.layers[i].order < l.layers[j].order
+		}
+		return l.layers[i].seq < l.layers[j].seq
+	})
+}
+
+// prepare allocates and clears the layer images with the given screen size.
+//
+// prepare is called whenever the screen image is cleared.
+func (l *layers) prepare(width, height int) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	for _, layer := range l.layers {
+		if layer.image != nil {
+			if w, h := layer.image.Size(); w != width || h != height {
+				_ = layer.image.Dispose()
+				layer.image = nil
+			}
+		}
+		if layer.image == nil {
+			layer.image = newImage(width, height, FilterDefault, true)
+			continue
+		}
+		_ = layer.image.Clear()
+	}
+	l.drawn = true
+}
+
+// draw composites the visible layers onto screen.
+//
+// draw does nothing when the layer images are not updated after the last call of draw.
+func (l *layers) draw(screen *Image) {
+	l.m.Lock()
+	if !l.drawn {
+		l.m.Unlock()
+		return
+	}
+	l.drawn = false
+	ls := make([]*Layer, len(l.layers))
+	copy(ls, l.layers)
+	l.m.Unlock()
+
+	for _, layer := range ls {
+		layer.draw(screen)
+	}
+}
+
+func (l *Layer) draw(screen *Image) {
+	theLayers.m.Lock()
+	img := l.image
+	visible := l.visible
+	mode := l.mode
+	shader := l.shader
+	uniforms := l.uniforms
+	theLayers.m.Unlock()
+
+	if img == nil || !visible {
+		return
+	}
+
+	if shader != nil && shader.shader != nil {
+		w, h := img.Size()
+		op := &DrawRectShaderOptions{}
+		op.CompositeMode = mode
+		op.Uniforms = uniforms
+		op.Image = img
+		screen.DrawRectShader(w, h, shader, op)
+		return
+	}
+
+	op := &DrawImageOptions{}
+	op.CompositeMode = mode
+	_ = screen.DrawImage(img, op)
+}
+
+// Name returns the name of the layer.
+func (l *Layer) Name() string {
+	return l.name
+}
+
+// Image returns the image of the layer to draw on.
+//
+// The image has the same size as the screen, and is cleared every frame like the screen image. Image returns nil
+// before the first frame after the layer is created.
+//
+// Image is concurrent-safe.
+func (l *Layer) Image() *Image {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+	return l.image
+}
+
+// Order returns the order of the layer. A layer with a greater order is drawn over layers with smaller orders.
+//
+// Order is concurrent-safe.
+func (l *Layer) Order() int {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+	return l.order
+}
+
+// SetOrder sets the order of the layer.
+//
+// SetOrder is concurrent-safe.
+func (l *Layer) SetOrder(order int) {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+	l.order = order
+	theLayers.sort()
+}
+
+// IsVisible reports whether the layer is visible.
+//
+// IsVisible is concurrent-safe.
+func (l *Layer) IsVisible() bool {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+	return l.visible
+}
+
+// SetVisible sets whether the layer is visible. An invisible layer is not composited onto the screen.
+//
+// SetVisible is concurrent-safe.
+func (l *Layer) SetVisible(visible bool) {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+	l.visible = visible
+}
+
+// SetCompositeMode sets the composite mode to composite the layer onto the screen.
+// The default (zero) value is regular alpha blending.
+//
+// SetCompositeMode is concurrent-safe.
+func (l *Layer) SetCompositeMode(mode CompositeMode) {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+	l.mode = mode
+}
+
+// SetShader sets the shader to composite the layer onto the screen. The shader reads the layer image by
+// texture0At. If shader is nil, the layer image is drawn as it is.
+//
+// uniforms is the values of the uniform variables for the shader. See DrawRectShaderOptions.Uniforms.
+//
+// SetShader is concurrent-safe.
+func (l *Layer) SetShader(shader *Shader, uniforms map[string]interface{}) {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+	l.shader = shader
+	l.uniforms = uniforms
+}
+
+// Dispose removes the layer and disposes its image.
+//
+// Dispose is concurrent-safe.
+func (l *Layer) Dispose() {
+	theLayers.m.Lock()
+	defer theLayers.m.Unlock()
+
+	for i, layer := range theLayers.layers {
+		if layer == l {
+			theLayers.layers = append(theLayers.layers[:i], theLayers.layers[i+1:]...)
+			break
+		}
+	}
+	if l.image != nil {
+		_ = l.image.Dispose()
+		l.image = nil
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"github.com/hajimehoshi/ebiten/internal/locale"
+)
+
+// SystemLocales returns the user's preferred languages as BCP 47 language tags like "en-US" or "ja", in the order of
+// the preference.
+//
+// SystemLocales uses the preferred UI languages on Windows, NSLocale on macOS and iOS, the environment variables like
+// LANGUAGE and LANG on Linux, the system properties on Android, and navigator.languages on browsers.
+//
+// SystemLocales returns nil if the preferences are not available.
+//
+// SystemLocales is concurrent-safe.
+func SystemLocales() []string {
+	return locale.Get()
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+// Monitor represents a monitor.
+//
+// X and Y are the position of the monitor in the virtual screen, which spans all the monitors.
+// The unit of X, Y, Width and Height is device-dependent pixels.
+type Monitor struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
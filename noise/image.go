@@ -0,0 +1,100 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"image"
+)
+
+// ImageOptions represents options for NewImage.
+type ImageOptions struct {
+	// Frequency is the number of the noise cells along the width. The default (zero) value is 1.
+	Frequency int
+
+	// Octaves is the number of octaves of the fractal noise. The default (zero) value is 1.
+	Octaves int
+
+	// Persistence is the amplitude ratio between the octaves. The default (zero) value is 0.5.
+	Persistence float64
+
+	// OffsetX and OffsetY are the offsets in the noise space.
+	OffsetX float64
+	OffsetY float64
+
+	// Seamless represents whether the image tiles seamlessly.
+	// If Seamless is true, the noise must implement PeriodicNoise.
+	Seamless bool
+}
+
+// NewImage returns a grayscale image filled with the noise values.
+//
+// The noise space is scaled so that the width of the image corresponds to Frequency cells, and the cells are square.
+// When Seamless is true, the number of cells along the height is rounded to an integer.
+//
+// NewImage panics if Seamless is true and n doesn't implement PeriodicNoise.
+func NewImage(width, height int, n Noise, options *ImageOptions) *image.Gray {
+	if options == nil {
+		options = &ImageOptions{}
+	}
+	freq := options.Frequency
+	if freq <= 0 {
+		freq = 1
+	}
+	octaves := options.Octaves
+	if octaves <= 0 {
+		octaves = 1
+	}
+	persistence := options.Persistence
+	if persistence == 0 {
+		persistence = 0.5
+	}
+
+	var pn PeriodicNoise
+	if options.Seamless {
+		p, ok := n.(PeriodicNoise)
+		if !ok {
+			panic("noise: the noise must implement PeriodicNoise for a seamless image")
+		}
+		pn = p
+	}
+
+	periodX := freq
+	periodY := int(float64(freq)*float64(height)/float64(width) + 0.5)
+	if periodY < 1 {
+		periodY = 1
+	}
+	sx := float64(freq) / float64(width)
+	sy := sx
+	if options.Seamless {
+		sy = float64(periodY) / float64(height)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for j := 0; j < height; j++ {
+		for i := 0; i < width; i++ {
+			// Sample at the pixel centers.
+			x := (float64(i)+0.5)*sx + options.OffsetX
+			y := (float64(j)+0.5)*sy + options.OffsetY
+			var v float64
+			if pn != nil {
+				v = FractalPeriodic(pn, x, y, periodX, periodY, octaves, persistence)
+			} else {
+				v = Fractal(n, x, y, octaves, 2, persistence)
+			}
+			img.Pix[j*img.Stride+i] = uint8(v*255 + 0.5)
+		}
+	}
+	return img
+}
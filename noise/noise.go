@@ -0,0 +1,113 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package noise provides procedural noise generators like Perlin, simplex and Worley noise.
+//
+// The generated values can be written into images for clouds, terrains, dissolve effects and so on. For example:
+//
+//     img := noise.NewImage(256, 256, noise.NewPerlin(seed), &noise.ImageOptions{
+//         Frequency: 4,
+//         Octaves:   5,
+//         Seamless:  true,
+//     })
+//     eimg, _ := ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package noise
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Noise is a 2D noise function.
+type Noise interface {
+	// At returns the noise value at (x, y) in [0, 1].
+	At(x, y float64) float64
+}
+
+// PeriodicNoise is a Noise that can tile seamlessly.
+type PeriodicNoise interface {
+	Noise
+
+	// AtPeriodic returns the noise value at (x, y) in [0, 1], where the noise repeats with the given periods.
+	// The periods must be positive.
+	AtPeriodic(x, y float64, periodX, periodY int) float64
+}
+
+// permutation is a permutation table doubled to avoid the index wrapping.
+type permutation [512]uint8
+
+func newPermutation(seed int64) *permutation {
+	var p permutation
+	for i, v := range rand.New(rand.NewSource(seed)).Perm(256) {
+		p[i] = uint8(v)
+		p[i+256] = uint8(v)
+	}
+	return &p
+}
+
+// hash returns a hash value in [0, 255] for the lattice point (x, y).
+func (p *permutation) hash(x, y int) int {
+	return int(p[int(p[x&255])+y&255])
+}
+
+func mod(x, n int) int {
+	x %= n
+	if x < 0 {
+		x += n
+	}
+	return x
+}
+
+func clamp01(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
+
+// Fractal returns the fractal Brownian motion value at (x, y) in [0, 1] by summing octaves of n.
+//
+// Each octave multiplies the frequency by lacunarity and the amplitude by persistence. Typical values are 2 and 0.5.
+func Fractal(n Noise, x, y float64, octaves int, lacunarity, persistence float64) float64 {
+	var sum, total float64
+	freq, amp := 1.0, 1.0
+	for i := 0; i < octaves; i++ {
+		sum += amp * (n.At(x*freq, y*freq)*2 - 1)
+		total += amp
+		freq *= lacunarity
+		amp *= persistence
+	}
+	if total == 0 {
+		return 0.5
+	}
+	return clamp01((sum/total + 1) / 2)
+}
+
+// FractalPeriodic is a periodic version of Fractal.
+//
+// The lacunarity is fixed to 2 so that every octave tiles with the same periods.
+func FractalPeriodic(n PeriodicNoise, x, y float64, periodX, periodY int, octaves int, persistence float64) float64 {
+	var sum, total float64
+	freq, amp := 1, 1.0
+	for i := 0; i < octaves; i++ {
+		f := float64(freq)
+		sum += amp * (n.AtPeriodic(x*f, y*f, periodX*freq, periodY*freq)*2 - 1)
+		total += amp
+		freq *= 2
+		amp *= persistence
+	}
+	if total == 0 {
+		return 0.5
+	}
+	return clamp01((sum/total + 1) / 2)
+}
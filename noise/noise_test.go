@@ -0,0 +1,131 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise_test
+
+import (
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten/noise"
+)
+
+func noises(seed int64) map[string]Noise {
+	return map[string]Noise{
+		"Perlin":  NewPerlin(seed),
+		"Simplex": NewSimplex(seed),
+		"Worley":  NewWorley(seed),
+	}
+}
+
+func TestRange(t *testing.T) {
+	for name, n := range noises(1) {
+		min, max := 1.0, 0.0
+		for j := 0; j < 100; j++ {
+			for i := 0; i < 100; i++ {
+				v := n.At(float64(i)*0.13, float64(j)*0.17)
+				if v < 0 || v > 1 {
+					t.Fatalf("%s: At returned %f, must be in [0, 1]", name, v)
+				}
+				if v < min {
+					min = v
+				}
+				if v > max {
+					max = v
+				}
+			}
+		}
+		// The noise must not be flat.
+		if max-min < 0.3 {
+			t.Errorf("%s: the range of the values is too narrow: [%f, %f]", name, min, max)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	n0 := noises(1)
+	n1 := noises(1)
+	n2 := noises(2)
+	for name := range n0 {
+		same, diff := true, false
+		for i := 0; i < 50; i++ {
+			x, y := float64(i)*0.37, float64(i)*0.29
+			if n0[name].At(x, y) != n1[name].At(x, y) {
+				same = false
+			}
+			if n0[name].At(x, y) != n2[name].At(x, y) {
+				diff = true
+			}
+		}
+		if !same {
+			t.Errorf("%s: the same seed must generate the same noise", name)
+		}
+		if !diff {
+			t.Errorf("%s: different seeds must generate different noises", name)
+		}
+	}
+}
+
+func TestPeriodic(t *testing.T) {
+	for name, n := range map[string]PeriodicNoise{
+		"Perlin": NewPerlin(1),
+		"Worley": NewWorley(1),
+	} {
+		const px, py = 3, 5
+		for i := 0; i < 30; i++ {
+			x, y := float64(i)*0.31, float64(i)*0.23
+			v0 := n.AtPeriodic(x, y, px, py)
+			v1 := n.AtPeriodic(x+px, y-2*py, px, py)
+			if d := v0 - v1; d > 1e-9 || d < -1e-9 {
+				t.Errorf("%s: AtPeriodic(%f, %f) = %f but AtPeriodic(%f, %f) = %f", name, x, y, v0, x+px, y-2*py, v1)
+			}
+		}
+	}
+}
+
+func TestSeamlessImage(t *testing.T) {
+	const w, h = 64, 32
+	img := NewImage(w, h, NewPerlin(1), &ImageOptions{
+		Frequency: 4,
+		Octaves:   3,
+		Seamless:  true,
+	})
+	if got, want := img.Bounds().Size().X, w; got != want {
+		t.Errorf("width: got: %d, want: %d", got, want)
+	}
+
+	// The leftmost column must be continuous with the rightmost column.
+	var edge, inner int
+	abs := func(x int) int {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	for j := 0; j < h; j++ {
+		edge += abs(int(img.GrayAt(0, j).Y) - int(img.GrayAt(w-1, j).Y))
+		inner += abs(int(img.GrayAt(w/2, j).Y) - int(img.GrayAt(w/2-1, j).Y))
+	}
+	if edge > inner*2+h {
+		t.Errorf("the edges are not continuous: edge diff: %d, inner diff: %d", edge, inner)
+	}
+}
+
+func TestSeamlessImagePanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewImage must panic for a non-periodic noise with Seamless")
+		}
+	}()
+	NewImage(16, 16, NewSimplex(1), &ImageOptions{Seamless: true})
+}
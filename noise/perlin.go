@@ -0,0 +1,91 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"math"
+)
+
+// Perlin is a Perlin noise generator.
+//
+// Perlin implements PeriodicNoise.
+type Perlin struct {
+	perm *permutation
+}
+
+// NewPerlin returns a new Perlin noise generator with the given seed.
+func NewPerlin(seed int64) *Perlin {
+	return &Perlin{
+		perm: newPermutation(seed),
+	}
+}
+
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+func grad(hash int, x, y float64) float64 {
+	switch hash & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	default:
+		return -y
+	}
+}
+
+// At implements Noise.
+func (p *Perlin) At(x, y float64) float64 {
+	return p.at(x, y, 256, 256)
+}
+
+// AtPeriodic implements PeriodicNoise.
+func (p *Perlin) AtPeriodic(x, y float64, periodX, periodY int) float64 {
+	if periodX <= 0 || periodY <= 0 {
+		panic("noise: periods must be positive")
+	}
+	return p.at(x, y, periodX, periodY)
+}
+
+func (p *Perlin) at(x, y float64, periodX, periodY int) float64 {
+	fx, fy := math.Floor(x), math.Floor(y)
+	x -= fx
+	y -= fy
+	x0, y0 := mod(int(fx), periodX), mod(int(fy), periodY)
+	x1, y1 := mod(x0+1, periodX), mod(y0+1, periodY)
+
+	u, v := fade(x), fade(y)
+	n00 := grad(p.perm.hash(x0, y0), x, y)
+	n10 := grad(p.perm.hash(x1, y0), x-1, y)
+	n01 := grad(p.perm.hash(x0, y1), x, y-1)
+	n11 := grad(p.perm.hash(x1, y1), x-1, y-1)
+	n := lerp(v, lerp(u, n00, n10), lerp(u, n01, n11))
+	return clamp01((n + 1) / 2)
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"math"
+)
+
+// Worley is a Worley (cellular) noise generator. The value is the distance to the nearest feature point.
+//
+// Worley implements PeriodicNoise.
+type Worley struct {
+	perm *permutation
+}
+
+// NewWorley returns a new Worley noise generator with the given seed.
+func NewWorley(seed int64) *Worley {
+	return &Worley{
+		perm: newPermutation(seed),
+	}
+}
+
+// At implements Noise.
+func (w *Worley) At(x, y float64) float64 {
+	return w.at(x, y, 256, 256)
+}
+
+// AtPeriodic implements PeriodicNoise.
+func (w *Worley) AtPeriodic(x, y float64, periodX, periodY int) float64 {
+	if periodX <= 0 || periodY <= 0 {
+		panic("noise: periods must be positive")
+	}
+	return w.at(x, y, periodX, periodY)
+}
+
+func (w *Worley) at(x, y float64, periodX, periodY int) float64 {
+	cx, cy := int(math.Floor(x)), int(math.Floor(y))
+	min := math.Inf(1)
+	for j := -1; j <= 1; j++ {
+		for i := -1; i <= 1; i++ {
+			// Each cell has one feature point.
+			h := w.perm.hash(mod(cx+i, periodX), mod(cy+j, periodY))
+			px := float64(cx+i) + float64(h)/256
+			py := float64(cy+j) + float64(w.perm.hash(h, h+1))/256
+			dx, dy := px-x, py-y
+			if d := dx*dx + dy*dy; d < min {
+				min = d
+			}
+		}
+	}
+	return clamp01(math.Sqrt(min))
+}
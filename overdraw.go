@@ -0,0 +1,160 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+	"image/color"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+)
+
+var overdrawVisualizationEnabled int32
+
+// IsOverdrawVisualizationEnabled reports whether the overdraw visualization is enabled.
+//
+// IsOverdrawVisualizationEnabled is concurrent-safe.
+func IsOverdrawVisualizationEnabled() bool {
+	return atomic.LoadInt32(&overdrawVisualizationEnabled) != 0
+}
+
+// SetOverdrawVisualizationEnabled sets whether the overdraw visualization is enabled.
+//
+// When the overdraw visualization is enabled, the screen shows a heatmap of how many times each pixel of the screen
+// was written in the frame, instead of the game screen. Pixels written once are dark red, and pixels written more
+// times become brighter through red, yellow and white. 32 or more writes are shown as white. This is useful to find
+// fill-rate problems especially on mobile GPUs.
+//
+// All the draw calls to the screen image are counted, regardless of whether the drawn pixels are transparent.
+// Draw calls to offscreen images are not counted.
+//
+// This is a debug feature and makes rendering slower. The initial value is false.
+//
+// SetOverdrawVisualizationEnabled is concurrent-safe.
+func SetOverdrawVisualizationEnabled(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&overdrawVisualizationEnabled, v)
+}
+
+const overdrawSourceSize = 16
+
+type overdraw struct {
+	target *Image
+	heat   *Image
+	source *Image
+	colorm ColorM
+	m      sync.Mutex
+}
+
+var theOverdraw = &overdraw{}
+
+// prepare starts counting the writes to target, which is the screen image just cleared.
+func (o *overdraw) prepare(target *Image) {
+	// Stop counting while the images for the heatmap are updated, or the updates would be counted.
+	o.m.Lock()
+	o.target = nil
+	heat := o.heat
+	o.m.Unlock()
+
+	if !IsOverdrawVisualizationEnabled() {
+		if heat != nil {
+			_ = heat.Dispose()
+			o.m.Lock()
+			o.heat = nil
+			o.m.Unlock()
+		}
+		return
+	}
+
+	w, h := target.Size()
+	if heat != nil {
+		if hw, hh := heat.Size(); hw != w || hh != h {
+			_ = heat.Dispose()
+			heat = nil
+		}
+	}
+	if heat == nil {
+		heat = newImage(w, h, FilterDefault, true)
+	} else {
+		_ = heat.Clear()
+	}
+
+	if o.source == nil {
+		src, _ := NewImage(overdrawSourceSize, overdrawSourceSize, FilterDefault)
+		_ = src.Fill(color.White)
+		o.m.Lock()
+		o.source = src
+		// Each write adds a constant color. The color saturates from red to white as the writes increase.
+		o.colorm.Scale(1.0/8, 1.0/16, 1.0/32, 1)
+		o.m.Unlock()
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+	o.heat = heat
+	o.target = target
+}
+
+// heatmap returns the heatmap image of the current frame, or nil when the visualization is disabled.
+func (o *overdraw) heatmap() *Image {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.target == nil {
+		return nil
+	}
+	return o.heat
+}
+
+// record records writes to dst by the given triangles.
+//
+// Only the destination positions of vertices are used.
+func (o *overdraw) record(dst *Image, vertices []float32, indices []uint16) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.target == nil || o.target != dst {
+		return
+	}
+
+	const c = overdrawSourceSize / 2
+	n := len(vertices) / graphics.VertexFloatNum
+	vs := make([]float32, len(vertices))
+	for i := 0; i < n; i++ {
+		vs[i*graphics.VertexFloatNum] = vertices[i*graphics.VertexFloatNum]
+		vs[i*graphics.VertexFloatNum+1] = vertices[i*graphics.VertexFloatNum+1]
+		vs[i*graphics.VertexFloatNum+2] = c
+		vs[i*graphics.VertexFloatNum+3] = c
+		vs[i*graphics.VertexFloatNum+4] = 0
+		vs[i*graphics.VertexFloatNum+5] = 0
+		vs[i*graphics.VertexFloatNum+6] = overdrawSourceSize
+		vs[i*graphics.VertexFloatNum+7] = overdrawSourceSize
+		vs[i*graphics.VertexFloatNum+8] = 1
+		vs[i*graphics.VertexFloatNum+9] = 1
+		vs[i*graphics.VertexFloatNum+10] = 1
+		vs[i*graphics.VertexFloatNum+11] = 1
+	}
+	is := make([]uint16, len(indices))
+	copy(is, indices)
+	if err := o.heat.buffered.DrawTriangles(o.source.buffered, vs, is, o.colorm.impl, driver.CompositeModeLighter, driver.FilterNearest, driver.AddressClampToZero, image.Rectangle{}, driver.StencilModeNone, driver.Depth{}); err != nil {
+		theUIContext.setError(err)
+	}
+}
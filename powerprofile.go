@@ -0,0 +1,238 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/internal/power"
+)
+
+// PowerProfile represents a set of settings to balance the performance and the power consumption.
+type PowerProfile int
+
+const (
+	// PowerProfileNone means that no power profile is applied. This is the default.
+	PowerProfileNone PowerProfile = iota
+
+	// PowerProfilePerformance prefers the performance. By default, vsync is disabled.
+	PowerProfilePerformance
+
+	// PowerProfileBalanced balances the performance and the power consumption. By default, vsync is enabled.
+	PowerProfileBalanced
+
+	// PowerProfileBattery prefers the power consumption. By default, a frame is presented every other refresh of
+	// the display, TPS is 30 and the resolution is scaled by 0.75.
+	PowerProfileBattery
+)
+
+// PowerProfileSettings represents the settings applied by a power profile.
+type PowerProfileSettings struct {
+	// SwapInterval is the number of the display's refreshes to wait for before presenting a frame.
+	// 0 disables vsync, 1 is the same as vsync, and 2 presents a frame every other refresh.
+	//
+	// A swap interval more than 1 works only with OpenGL on desktops and on browsers so far. Otherwise, a swap
+	// interval more than 1 works as 1.
+	SwapInterval int
+
+	// MaxTPS is the maximum TPS. See SetMaxTPS.
+	MaxTPS int
+
+	// ResolutionScale scales the outside size passed to the game's Layout. ResolutionScale must be in (0, 1].
+	//
+	// A game whose Layout returns the outside size renders at a lower resolution, and the result is scaled up to the
+	// screen. ResolutionScale doesn't affect a game whose Layout returns a fixed size.
+	ResolutionScale float64
+}
+
+type powerProfiles struct {
+	settings map[PowerProfile]PowerProfileSettings
+	profile  PowerProfile
+	auto     bool
+
+	// applied is the profile applied actually.
+	applied PowerProfile
+
+	// dirty reports whether the settings need to be applied again.
+	dirty bool
+
+	m sync.Mutex
+}
+
+var thePowerProfiles = &powerProfiles{
+	settings: map[PowerProfile]PowerProfileSettings{
+		PowerProfilePerformance: {
+			SwapInterval:    0,
+			MaxTPS:          60,
+			ResolutionScale: 1,
+		},
+		PowerProfileBalanced: {
+			SwapInterval:    1,
+			MaxTPS:          60,
+			ResolutionScale: 1,
+		},
+		PowerProfileBattery: {
+			SwapInterval:    2,
+			MaxTPS:          30,
+			ResolutionScale: 0.75,
+		},
+	},
+}
+
+func checkPowerProfile(profile PowerProfile) {
+	switch profile {
+	case PowerProfilePerformance, PowerProfileBalanced, PowerProfileBattery:
+	default:
+		panic(fmt.Sprintf("ebiten: invalid power profile: %d", profile))
+	}
+}
+
+// SetPowerProfile sets the power profile, and applies its settings to vsync, TPS and the resolution at the next
+// frame.
+//
+// The settings applied by a power profile can be changed by other functions like SetMaxTPS later. With
+// PowerProfileNone, the current settings are kept, and the resolution is not scaled any more.
+//
+// SetPowerProfile is concurrent-safe.
+func SetPowerProfile(profile PowerProfile) {
+	if profile != PowerProfileNone {
+		checkPowerProfile(profile)
+	}
+	p := thePowerProfiles
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.profile = profile
+	p.dirty = true
+}
+
+// CurrentPowerProfile returns the power profile applied actually.
+//
+// CurrentPowerProfile returns PowerProfileBattery when the profile is switched automatically on battery.
+//
+// CurrentPowerProfile is concurrent-safe.
+func CurrentPowerProfile() PowerProfile {
+	p := thePowerProfiles
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.applied
+}
+
+// PowerProfileSettingsOf returns the settings of the given power profile.
+//
+// PowerProfileSettingsOf panics if profile is PowerProfileNone or invalid.
+//
+// PowerProfileSettingsOf is concurrent-safe.
+func PowerProfileSettingsOf(profile PowerProfile) PowerProfileSettings {
+	checkPowerProfile(profile)
+	p := thePowerProfiles
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.settings[profile]
+}
+
+// SetPowerProfileSettings sets the settings of the given power profile.
+// If the profile is applied, the new settings are applied at the next frame.
+//
+// SetPowerProfileSettings panics if profile is PowerProfileNone or invalid, or settings have invalid values.
+//
+// SetPowerProfileSettings is concurrent-safe.
+func SetPowerProfileSettings(profile PowerProfile, settings PowerProfileSettings) {
+	checkPowerProfile(profile)
+	if settings.SwapInterval < 0 {
+		panic(fmt.Sprintf("ebiten: SwapInterval must be non-negative but %d", settings.SwapInterval))
+	}
+	if settings.MaxTPS < 0 && settings.MaxTPS != UncappedTPS {
+		panic("ebiten: MaxTPS must be >= 0 or UncappedTPS")
+	}
+	if settings.ResolutionScale <= 0 || 1 < settings.ResolutionScale {
+		panic(fmt.Sprintf("ebiten: ResolutionScale must be in (0, 1] but %f", settings.ResolutionScale))
+	}
+
+	p := thePowerProfiles
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.settings[profile] = settings
+	if p.applied == profile {
+		p.dirty = true
+	}
+}
+
+// IsPowerProfileAutoSwitchEnabled reports whether the power profile is switched automatically on battery.
+//
+// IsPowerProfileAutoSwitchEnabled is concurrent-safe.
+func IsPowerProfileAutoSwitchEnabled() bool {
+	p := thePowerProfiles
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.auto
+}
+
+// SetPowerProfileAutoSwitchEnabled sets whether the power profile is switched automatically on battery.
+//
+// When enabled, PowerProfileBattery is applied while the device runs on battery, and the profile set by
+// SetPowerProfile is applied while the device runs on AC power. If the profile is PowerProfileNone,
+// PowerProfileBalanced is applied on AC power.
+//
+// The power source is available on Windows, macOS, Linux and browsers with the Battery Status API so far.
+// When the power source is unknown, the device is treated as running on AC power.
+//
+// The initial value is false.
+//
+// SetPowerProfileAutoSwitchEnabled is concurrent-safe.
+func SetPowerProfileAutoSwitchEnabled(enabled bool) {
+	p := thePowerProfiles
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.auto = enabled
+	p.dirty = true
+}
+
+// update applies the settings of the current power profile if needed. update is called every frame.
+func (p *powerProfiles) update() {
+	p.m.Lock()
+	profile := p.profile
+	if p.auto {
+		if power.Get() == power.SourceBattery {
+			profile = PowerProfileBattery
+		} else if profile == PowerProfileNone {
+			profile = PowerProfileBalanced
+		}
+	}
+	if profile == p.applied && !p.dirty {
+		p.m.Unlock()
+		return
+	}
+	p.applied = profile
+	p.dirty = false
+	s, ok := p.settings[profile]
+	p.m.Unlock()
+
+	if !ok {
+		return
+	}
+	uiDriver().SetSwapInterval(s.SwapInterval)
+	SetMaxTPS(s.MaxTPS)
+}
+
+// resolutionScale returns the scale of the outside size passed to the game's Layout.
+func (p *powerProfiles) resolutionScale() float64 {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.applied == PowerProfileNone {
+		return 1
+	}
+	return p.settings[p.applied].ResolutionScale
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	presentHook  func(screen *Image) (*Image, error)
+	presentHookM sync.Mutex
+
+	discardedTicksHook  func(ticks int)
+	discardedTicksHookM sync.Mutex
+
+	resumedHook  func(suspended time.Duration)
+	resumedHookM sync.Mutex
+)
+
+// SetPresentHook sets the function called with the final screen image right before it is presented.
+// If f is nil, the hook is removed.
+//
+// screen is the game screen with the size returned by Layout after the filters like the color vision filter are
+// applied. f can read the pixels of screen, e.g., for video encoders or remote streaming.
+// f must not modify screen.
+//
+// f returns the image to be presented instead of screen. If f returns nil, screen is presented as it is.
+// The returned image can have a different size from screen, e.g., an image upscaled by a custom scaler, and it is
+// stretched to the same region on the window as screen.
+//
+// If f returns an error, the game stops with the error.
+//
+// f is called once every frame, and is not called when there is no frame to be presented.
+//
+// SetPresentHook is concurrent-safe.
+func SetPresentHook(f func(screen *Image) (*Image, error)) {
+	presentHookM.Lock()
+	presentHook = f
+	presentHookM.Unlock()
+}
+
+// SetDiscardedTicksHook sets the function called when the game gives up catching up with the system clock and
+// some ticks are discarded. ticks is the number of the discarded ticks. If f is nil, the hook is removed.
+//
+// f is called before Update calls in the frame. f is useful to degrade the game quality, e.g., to reduce the number
+// of the particles, when the game is too heavy. See also SetMaxUpdatesPerFrame.
+//
+// SetDiscardedTicksHook is concurrent-safe.
+func SetDiscardedTicksHook(f func(ticks int)) {
+	discardedTicksHookM.Lock()
+	discardedTicksHook = f
+	discardedTicksHookM.Unlock()
+}
+
+func runDiscardedTicksHook(ticks int) {
+	discardedTicksHookM.Lock()
+	f := discardedTicksHook
+	discardedTicksHookM.Unlock()
+
+	if f == nil {
+		return
+	}
+	f(ticks)
+}
+
+// SetResumedHook sets the function called when the game resumes after the process is suspended, e.g., after the OS
+// sleeps or a debugger pauses the process. suspended is the duration of the suspension. If f is nil, the hook is
+// removed.
+//
+// The system sleep is notified by the OS where available, and otherwise a suspension is detected as a large gap of the
+// system clock between two frames. A long single frame and the wait while the window is unfocused are not regarded
+// as a suspension.
+//
+// After a suspension, Ebiten doesn't try to catch up with the lost ticks but resyncs the game time with the system
+// clock. f is useful to pause the gameplay, e.g., to show a pause menu, so that the player is not penalized by the
+// gap. f is called before Update calls in the frame.
+//
+// SetResumedHook is concurrent-safe.
+func SetResumedHook(f func(suspended time.Duration)) {
+	resumedHookM.Lock()
+	resumedHook = f
+	resumedHookM.Unlock()
+}
+
+func runResumedHook(suspended time.Duration) {
+	resumedHookM.Lock()
+	f := resumedHook
+	resumedHookM.Unlock()
+
+	if f == nil {
+		return
+	}
+	f(suspended)
+}
+
+func hasPresentHook() bool {
+	presentHookM.Lock()
+	defer presentHookM.Unlock()
+	return presentHook != nil
+}
+
+func runPresentHook(screen *Image) (*Image, error) {
+	presentHookM.Lock()
+	f := presentHook
+	presentHookM.Unlock()
+
+	if f == nil {
+		return screen, nil
+	}
+	img, err := f(screen)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil {
+		return screen, nil
+	}
+	return img, nil
+}
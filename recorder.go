@@ -0,0 +1,223 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/internal/apng"
+)
+
+// RecordingFormat represents a file format of a recorded clip.
+type RecordingFormat int
+
+const (
+	// RecordingFormatGIF represents an animated GIF. Colors are reduced to 256 colors with dithering.
+	RecordingFormatGIF RecordingFormat = iota
+
+	// RecordingFormatAPNG represents an animated PNG. Colors are kept as they are, but files tend to be bigger.
+	RecordingFormatAPNG
+)
+
+// RecordingOptions represents options for StartRecording.
+type RecordingOptions struct {
+	// Interval is the number of frames from a captured frame to the next captured frame.
+	//
+	// The default (zero) value is 3, which captures 20 frames per second at 60 FPS.
+	Interval int
+
+	// MaxFrames is the maximum number of the captured frames kept in memory.
+	// When the number of the captured frames exceeds MaxFrames, the oldest frame is discarded, then the recorded
+	// clip is always the latest part.
+	//
+	// The default (zero) value is 600, which is 30 seconds with the default interval at 60 FPS.
+	MaxFrames int
+}
+
+type recorder struct {
+	interval  int
+	maxFrames int
+	count     int
+	frames    []recordedFrame
+}
+
+type recordedFrame struct {
+	img  *image.RGBA
+	time time.Time
+}
+
+var (
+	theRecorder *recorder
+	recorderM   sync.Mutex
+)
+
+// StartRecording starts capturing the frames presented on the screen into memory.
+// The captured frames are encoded into an animated image by StopRecording.
+//
+// Frames are captured in the same way as Screenshot, so capturing is not cheap.
+// Interval of the options can reduce the cost.
+//
+// StartRecording returns an error if recording is already started.
+//
+// StartRecording is concurrent-safe.
+//
+// Note that this API is experimental.
+func StartRecording(options *RecordingOptions) error {
+	if options == nil {
+		options = &RecordingOptions{}
+	}
+	interval := options.Interval
+	if interval <= 0 {
+		interval = 3
+	}
+	maxFrames := options.MaxFrames
+	if maxFrames <= 0 {
+		maxFrames = 600
+	}
+
+	recorderM.Lock()
+	defer recorderM.Unlock()
+	if theRecorder != nil {
+		return errors.New("ebiten: recording is already started")
+	}
+	theRecorder = &recorder{
+		interval:  interval,
+		maxFrames: maxFrames,
+	}
+	return nil
+}
+
+// IsRecording reports whether recording is started by StartRecording.
+//
+// IsRecording is concurrent-safe.
+//
+// Note that this API is experimental.
+func IsRecording() bool {
+	recorderM.Lock()
+	defer recorderM.Unlock()
+	return theRecorder != nil
+}
+
+// StopRecording stops recording started by StartRecording, and writes the captured frames to w in the given
+// format. The display duration of each frame is the actual time until the next captured frame.
+//
+// StopRecording returns an error if recording is not started or no frame has been captured.
+//
+// Encoding can take a long time especially with RecordingFormatGIF.
+//
+// StopRecording is concurrent-safe.
+//
+// Note that this API is experimental.
+func StopRecording(w io.Writer, format RecordingFormat) error {
+	recorderM.Lock()
+	r := theRecorder
+	theRecorder = nil
+	recorderM.Unlock()
+
+	if r == nil {
+		return errors.New("ebiten: recording is not started")
+	}
+	if len(r.frames) == 0 {
+		return errors.New("ebiten: no frame is recorded")
+	}
+
+	delays := make([]time.Duration, len(r.frames))
+	for i := range r.frames {
+		if i < len(r.frames)-1 {
+			delays[i] = r.frames[i+1].time.Sub(r.frames[i].time)
+			continue
+		}
+		if i > 0 {
+			// There is no next frame for the last frame. Use the previous delay instead.
+			delays[i] = delays[i-1]
+			continue
+		}
+		delays[i] = time.Second / 60 * time.Duration(r.interval)
+	}
+
+	switch format {
+	case RecordingFormatGIF:
+		g := &gif.GIF{}
+		for i, f := range r.frames {
+			p := image.NewPaletted(f.img.Bounds(), palette.Plan9)
+			draw.FloydSteinberg.Draw(p, p.Bounds(), f.img, image.ZP)
+			g.Image = append(g.Image, p)
+			// The unit of GIF delays is 1/100 second.
+			g.Delay = append(g.Delay, int(delays[i]/(10*time.Millisecond)))
+		}
+		return gif.EncodeAll(w, g)
+	case RecordingFormatAPNG:
+		imgs := make([]image.Image, len(r.frames))
+		for i, f := range r.frames {
+			imgs[i] = f.img
+		}
+		return apng.Encode(w, imgs, delays)
+	default:
+		return fmt.Errorf("ebiten: invalid recording format: %d", format)
+	}
+}
+
+// recordFrame captures the last composited frame if recording is started.
+func (c *uiContext) recordFrame() {
+	recorderM.Lock()
+	defer recorderM.Unlock()
+
+	r := theRecorder
+	if r == nil {
+		return
+	}
+
+	r.count++
+	if (r.count-1)%r.interval != 0 {
+		return
+	}
+
+	img := c.lastFrameRGBA()
+	if img == nil {
+		return
+	}
+
+	// Flatten the frame onto black. The pixels are premultiplied, then just fill the alpha values.
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xff
+	}
+
+	// All the frames must have the same size. Fit the frame into the first frame when the screen size changes.
+	if len(r.frames) > 0 {
+		if b := r.frames[0].img.Bounds(); img.Bounds() != b {
+			dst := image.NewRGBA(b)
+			draw.Draw(dst, b, image.Black, image.ZP, draw.Src)
+			draw.Draw(dst, b, img, image.ZP, draw.Src)
+			img = dst
+		}
+	}
+
+	if len(r.frames) >= r.maxFrames {
+		copy(r.frames, r.frames[1:])
+		r.frames = r.frames[:len(r.frames)-1]
+	}
+	r.frames = append(r.frames, recordedFrame{
+		img:  img,
+		time: time.Now(),
+	})
+}
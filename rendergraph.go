@@ -0,0 +1,263 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+)
+
+// RenderPass represents a pass of a RenderGraph.
+type RenderPass struct {
+	// Name is the name of the pass, used for error messages.
+	Name string
+
+	// Inputs is the names of the images the pass reads.
+	Inputs []string
+
+	// Output is the name of the image the pass draws on.
+	Output string
+
+	// Width and Height are the size of the output image when the output is an intermediate image.
+	// If Width or Height is 0, the size of the first input is used.
+	Width  int
+	Height int
+
+	// Draw draws the pass onto dst. inputs are the images of Inputs in the same order.
+	//
+	// inputs is valid only during the call.
+	Draw func(dst *Image, inputs []*Image)
+}
+
+// RenderGraph is a set of rendering passes for multi-pass effects like blur, threshold and composition.
+//
+// Each pass declares the names of its input and output images. The images set by SetImage are external images,
+// e.g., the scene and the screen. The other images are intermediate images that RenderGraph manages.
+//
+// Run runs the passes in an order that satisfies the dependencies: all the passes drawing on an image run before
+// the passes reading the image, and the passes drawing on the same image run in the order they were added. Among
+// the passes that can run, the one drawing on the same image as the previous pass is preferred in order to reduce
+// switching render targets.
+//
+// Intermediate images are taken from the pool of AcquireTempImage when they are first drawn, and returned to the
+// pool after their last reads. Then, intermediate images of the same size can share one image in a graph and across
+// frames.
+//
+// Note that this API is experimental.
+type RenderGraph struct {
+	passes   []*RenderPass
+	external map[string]*Image
+
+	// order is the sorted indices of passes. order is nil when the graph is modified.
+	order []int
+
+	// lastReads is the index in order of the last pass reading each intermediate image.
+	lastReads map[string]int
+
+	inputs []*Image
+}
+
+// NewRenderGraph returns a new empty RenderGraph.
+func NewRenderGraph() *RenderGraph {
+	return &RenderGraph{
+		external: map[string]*Image{},
+	}
+}
+
+// AddPass adds a pass to the graph.
+//
+// The pass must not be modified after AddPass is called.
+//
+// AddPass panics if the pass has no output or no Draw function.
+func (g *RenderGraph) AddPass(pass *RenderPass) {
+	if pass.Output == "" {
+		panic(fmt.Sprintf("ebiten: the pass %q must have an output", pass.Name))
+	}
+	if pass.Draw == nil {
+		panic(fmt.Sprintf("ebiten: the pass %q must have a Draw function", pass.Name))
+	}
+	g.passes = append(g.passes, pass)
+	g.order = nil
+}
+
+// SetImage sets an external image with the given name. If img is nil, the external image is removed.
+//
+// External images are never released by the graph. Images drawn by no pass, like the scene to apply effects, must
+// be set as external images.
+func (g *RenderGraph) SetImage(name string, img *Image) {
+	_, exists := g.external[name]
+	if img == nil {
+		delete(g.external, name)
+	} else {
+		g.external[name] = img
+	}
+	if exists != (img != nil) {
+		g.order = nil
+	}
+}
+
+// compile sorts the passes.
+func (g *RenderGraph) compile() error {
+	// writers is the indices of the passes drawing on each image.
+	writers := map[string][]int{}
+	for i, p := range g.passes {
+		writers[p.Output] = append(writers[p.Output], i)
+	}
+
+	// deps is the indices of the passes that must run before each pass.
+	deps := make([][]int, len(g.passes))
+	for i, p := range g.passes {
+		for _, in := range p.Inputs {
+			ws, ok := writers[in]
+			if !ok {
+				if _, ok := g.external[in]; !ok {
+					return fmt.Errorf("ebiten: the image %q read by the pass %q is neither drawn by a pass nor set by SetImage", in, p.Name)
+				}
+				continue
+			}
+			for _, w := range ws {
+				if w == i {
+					return fmt.Errorf("ebiten: the pass %q reads its own output %q", p.Name, in)
+				}
+				deps[i] = append(deps[i], w)
+			}
+		}
+		// Passes drawing on the same image run in order.
+		for _, w := range writers[p.Output] {
+			if w >= i {
+				break
+			}
+			deps[i] = append(deps[i], w)
+		}
+	}
+
+	done := make([]bool, len(g.passes))
+	order := make([]int, 0, len(g.passes))
+	ready := func(i int) bool {
+		if done[i] {
+			return false
+		}
+		for _, d := range deps[i] {
+			if !done[d] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(order) < len(g.passes) {
+		next := -1
+		for i := range g.passes {
+			if !ready(i) {
+				continue
+			}
+			if next == -1 {
+				next = i
+			}
+			// Prefer the pass drawing on the same image as the previous one.
+			if len(order) > 0 && g.passes[i].Output == g.passes[order[len(order)-1]].Output {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			for i, p := range g.passes {
+				if !done[i] {
+					return fmt.Errorf("ebiten: the render graph has a cycle and the pass %q can never run", p.Name)
+				}
+			}
+		}
+		done[next] = true
+		order = append(order, next)
+	}
+
+	lastReads := map[string]int{}
+	for oi, i := range order {
+		for _, in := range g.passes[i].Inputs {
+			if _, ok := g.external[in]; ok {
+				continue
+			}
+			lastReads[in] = oi
+		}
+	}
+
+	g.order = order
+	g.lastReads = lastReads
+	return nil
+}
+
+// Run runs all the passes.
+//
+// Run returns an error when the graph is invalid, e.g., a pass reads an image that is neither drawn by any pass
+// nor set by SetImage, or the graph has a cycle.
+func (g *RenderGraph) Run() error {
+	if g.order == nil {
+		if err := g.compile(); err != nil {
+			return err
+		}
+	}
+
+	intermediates := map[string]*Image{}
+	defer func() {
+		// Release the images of the passes that are not read, or left by a panic.
+		for _, img := range intermediates {
+			ReleaseTempImage(img)
+		}
+	}()
+
+	image := func(name string) *Image {
+		if img, ok := g.external[name]; ok {
+			return img
+		}
+		return intermediates[name]
+	}
+
+	for oi, i := range g.order {
+		p := g.passes[i]
+
+		g.inputs = g.inputs[:0]
+		for _, in := range p.Inputs {
+			g.inputs = append(g.inputs, image(in))
+		}
+
+		dst := image(p.Output)
+		if dst == nil {
+			w, h := p.Width, p.Height
+			if w == 0 || h == 0 {
+				if len(g.inputs) == 0 {
+					return fmt.Errorf("ebiten: the size of the output %q of the pass %q is unknown", p.Output, p.Name)
+				}
+				w, h = g.inputs[0].Size()
+			}
+			dst = AcquireTempImage(w, h)
+			intermediates[p.Output] = dst
+		}
+
+		p.Draw(dst, g.inputs)
+
+		for j := range g.inputs {
+			g.inputs[j] = nil
+		}
+		for _, in := range p.Inputs {
+			img, ok := intermediates[in]
+			if !ok {
+				continue
+			}
+			if g.lastReads[in] == oi {
+				ReleaseTempImage(img)
+				delete(intermediates, in)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func TestRenderGraph(t *testing.T) {
+	src, _ := NewImage(16, 16, FilterDefault)
+	src.Fill(color.RGBA{0x80, 0, 0, 0xff})
+	dst, _ := NewImage(16, 16, FilterDefault)
+
+	var order []string
+	g := NewRenderGraph()
+	g.SetImage("src", src)
+	g.SetImage("dst", dst)
+
+	// Add the passes in the reversed order. RenderGraph must sort them.
+	g.AddPass(&RenderPass{
+		Name:   "composite",
+		Inputs: []string{"src", "bright"},
+		Output: "dst",
+		Draw: func(dst *Image, inputs []*Image) {
+			order = append(order, "composite")
+			dst.DrawImage(inputs[0], nil)
+			op := &DrawImageOptions{}
+			op.CompositeMode = CompositeModeLighter
+			dst.DrawImage(inputs[1], op)
+		},
+	})
+	g.AddPass(&RenderPass{
+		Name:   "threshold",
+		Inputs: []string{"src"},
+		Output: "bright",
+		Draw: func(dst *Image, inputs []*Image) {
+			order = append(order, "threshold")
+			op := &DrawImageOptions{}
+			op.ColorM.Scale(0, 0, 0, 1)
+			op.ColorM.Translate(0, 0, 0x40/255.0, 0)
+			dst.DrawImage(inputs[0], op)
+		},
+	})
+	if err := g.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(order, ","), "threshold,composite"; got != want {
+		t.Errorf("order: got: %s, want: %s", got, want)
+	}
+	got := dst.At(0, 0).(color.RGBA)
+	want := color.RGBA{0x80, 0, 0x40, 0xff}
+	if !sameColors(got, want, 1) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestRenderGraphError(t *testing.T) {
+	g := NewRenderGraph()
+	g.AddPass(&RenderPass{
+		Name:   "a",
+		Inputs: []string{"b"},
+		Output: "a",
+		Draw:   func(dst *Image, inputs []*Image) {},
+	})
+	if err := g.Run(); err == nil {
+		t.Errorf("Run must return an error when an input doesn't exist")
+	}
+
+	g.AddPass(&RenderPass{
+		Name:   "b",
+		Inputs: []string{"a"},
+		Output: "b",
+		Draw:   func(dst *Image, inputs []*Image) {},
+	})
+	if err := g.Run(); err == nil {
+		t.Errorf("Run must return an error when the graph has a cycle")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/internal/hooks"
+	"github.com/hajimehoshi/ebiten/internal/shareable"
+)
+
+type textureResource struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	InternalWidth  int    `json:"internalWidth"`
+	InternalHeight int    `json:"internalHeight"`
+	Bytes          int64  `json:"bytes"`
+	Images         int    `json:"images,omitempty"`
+}
+
+type shaderResource struct {
+	ID int `json:"id"`
+}
+
+type otherResource struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Bytes int64  `json:"bytes"`
+}
+
+type resources struct {
+	Textures     []textureResource `json:"textures"`
+	TextureBytes int64             `json:"textureBytes"`
+	Shaders      []shaderResource  `json:"shaders"`
+	Others       []otherResource   `json:"others"`
+}
+
+// DumpResources writes all the live resources of Ebiten in JSON to w.
+//
+// The JSON object has these members:
+//
+//     textures:     The textures on GPU with their IDs, names (atlas, image, volatile or screen), sizes, internal texture
+//                   sizes and bytes. For an atlas, images is the number of the images on it.
+//     textureBytes: The total bytes of the textures.
+//     shaders:      The shaders that are not disposed.
+//     others:       The other resources like audio players, with their kinds, names and bytes.
+//
+// This is useful to find resource leaks in long sessions. The format might be changed in the future.
+//
+// DumpResources must be called from the game's Update or Draw.
+//
+// This API is experimental.
+func DumpResources(w io.Writer) error {
+	rs := resources{
+		Textures: []textureResource{},
+		Shaders:  []shaderResource{},
+		Others:   []otherResource{},
+	}
+
+	for _, t := range shareable.TextureInfos() {
+		name := "image"
+		switch {
+		case t.Screen:
+			name = "screen"
+		case t.Atlas:
+			name = "atlas"
+		case t.Volatile:
+			name = "volatile"
+		}
+		b := 4 * int64(t.InternalWidth) * int64(t.InternalHeight)
+		rs.Textures = append(rs.Textures, textureResource{
+			ID:             t.ID,
+			Name:           name,
+			Width:          t.Width,
+			Height:         t.Height,
+			InternalWidth:  t.InternalWidth,
+			InternalHeight: t.InternalHeight,
+			Bytes:          b,
+			Images:         t.Images,
+		})
+		rs.TextureBytes += b
+	}
+
+	for _, id := range graphicscommand.ShaderIDs() {
+		rs.Shaders = append(rs.Shaders, shaderResource{
+			ID: id,
+		})
+	}
+
+	for _, r := range hooks.ReportResources() {
+		rs.Others = append(rs.Others, otherResource{
+			Kind:  r.Kind,
+			Name:  r.Name,
+			Bytes: r.Bytes,
+		})
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(&rs)
+}
@@ -0,0 +1,314 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Snapshotter is the interface of a game state that can be saved and restored for rewinding.
+type Snapshotter interface {
+	// Snapshot returns the serialized current state.
+	// The returned bytes must not be modified after Snapshot returns.
+	Snapshot() ([]byte, error)
+
+	// Restore restores the state from the bytes returned by Snapshot.
+	Restore(data []byte) error
+}
+
+// RewindOptions represents options for StartRewindRecording.
+type RewindOptions struct {
+	// Interval is the number of ticks from a snapshot to the next snapshot.
+	// Snapshots are taken at the ticks whose numbers are multiples of Interval.
+	//
+	// The default (zero) value is 1, which takes a snapshot every tick.
+	Interval int
+
+	// MaxSnapshots is the maximum number of the snapshots kept in memory.
+	// When the number of the snapshots exceeds MaxSnapshots, the oldest snapshot is discarded.
+	//
+	// The default (zero) value is 600, which is 10 seconds with the default interval at 60 TPS.
+	MaxSnapshots int
+}
+
+type snapshot struct {
+	tick   int64
+	states map[string][]byte
+}
+
+type rewinder struct {
+	snapshotters map[string]Snapshotter
+
+	recording    bool
+	interval     int
+	maxSnapshots int
+	snapshots    []snapshot
+
+	rewinding bool
+	rewindTo  int64
+
+	m sync.Mutex
+}
+
+var theRewinder = &rewinder{
+	snapshotters: map[string]Snapshotter{},
+}
+
+// RegisterSnapshotter registers s with the given name as a part of the game state for rewinding.
+// If a Snapshotter is already registered with the name, it is replaced. If s is nil, the Snapshotter with the name
+// is unregistered.
+//
+// The registered Snapshotters are called on the game's goroutine outside of Update, in the order of their names.
+// Snapshot and Restore must not call the functions for rewinding.
+//
+// RegisterSnapshotter is concurrent-safe.
+//
+// Note that this API is experimental.
+func RegisterSnapshotter(name string, s Snapshotter) {
+	r := theRewinder
+	r.m.Lock()
+	defer r.m.Unlock()
+	if s == nil {
+		delete(r.snapshotters, name)
+		return
+	}
+	r.snapshotters[name] = s
+}
+
+// StartRewindRecording starts taking snapshots of the registered Snapshotters into a ring buffer in memory.
+//
+// A snapshot is taken at the start of a tick, before Update is called. Then, the snapshot at a tick represents
+// the state after the Update calls of the previous ticks.
+//
+// StartRewindRecording returns an error if rewind recording is already started.
+//
+// StartRewindRecording is concurrent-safe.
+//
+// Note that this API is experimental.
+func StartRewindRecording(options *RewindOptions) error {
+	if options == nil {
+		options = &RewindOptions{}
+	}
+	interval := options.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+	maxSnapshots := options.MaxSnapshots
+	if maxSnapshots <= 0 {
+		maxSnapshots = 600
+	}
+
+	r := theRewinder
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.recording {
+		return errors.New("ebiten: rewind recording is already started")
+	}
+	r.recording = true
+	r.interval = interval
+	r.maxSnapshots = maxSnapshots
+	r.snapshots = nil
+	return nil
+}
+
+// IsRewindRecording reports whether rewind recording is started by StartRewindRecording.
+//
+// IsRewindRecording is concurrent-safe.
+//
+// Note that this API is experimental.
+func IsRewindRecording() bool {
+	r := theRewinder
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.recording
+}
+
+// StopRewindRecording stops rewind recording started by StartRewindRecording, and discards the snapshots.
+// A requested rewind that is not applied yet is canceled.
+//
+// StopRewindRecording returns an error if rewind recording is not started.
+//
+// StopRewindRecording is concurrent-safe.
+//
+// Note that this API is experimental.
+func StopRewindRecording() error {
+	r := theRewinder
+	r.m.Lock()
+	defer r.m.Unlock()
+	if !r.recording {
+		return errors.New("ebiten: rewind recording is not started")
+	}
+	r.recording = false
+	r.snapshots = nil
+	r.rewinding = false
+	return nil
+}
+
+// Rewind requests to restore the latest snapshot that is at least the given number of ticks older than the current
+// tick, and returns the tick of the snapshot.
+//
+// The snapshot is restored at the start of the next tick, before Update is called. The snapshots newer than the
+// restored one are discarded, and CurrentTick goes back to the tick of the snapshot. As the ticks are restored
+// too, inputs recorded per tick, e.g. for an instant replay, can be replayed from the returned tick consistently.
+//
+// Rewind returns an error if rewind recording is not started or there is no snapshot old enough.
+//
+// Rewind is concurrent-safe.
+//
+// Note that this API is experimental.
+func Rewind(ticks int) (int64, error) {
+	if ticks < 0 {
+		return 0, fmt.Errorf("ebiten: ticks must be non-negative but %d", ticks)
+	}
+
+	r := theRewinder
+	r.m.Lock()
+	defer r.m.Unlock()
+	if !r.recording {
+		return 0, errors.New("ebiten: rewind recording is not started")
+	}
+
+	to := CurrentTick() - int64(ticks)
+	for i := len(r.snapshots) - 1; i >= 0; i-- {
+		if s := r.snapshots[i]; s.tick <= to {
+			r.rewinding = true
+			r.rewindTo = s.tick
+			return s.tick, nil
+		}
+	}
+	return 0, fmt.Errorf("ebiten: no snapshot is old enough to rewind %d ticks", ticks)
+}
+
+// CurrentTick returns the current tick, that is the number of the game's Update calls so far.
+// CurrentTick goes back when a rewind is applied.
+//
+// CurrentTick is concurrent-safe.
+//
+// Note that this API is experimental.
+func CurrentTick() int64 {
+	return atomic.LoadInt64(&theUIContext.tick)
+}
+
+// registeredSnapshotters returns the names and the registered Snapshotters in the order of the names.
+func (r *rewinder) registeredSnapshotters() ([]string, []Snapshotter) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.registeredSnapshottersWithLock()
+}
+
+func (r *rewinder) registeredSnapshottersWithLock() ([]string, []Snapshotter) {
+	names := make([]string, 0, len(r.snapshotters))
+	for name := range r.snapshotters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	snapshotters := make([]Snapshotter, len(names))
+	for i, name := range names {
+		snapshotters[i] = r.snapshotters[name]
+	}
+	return names, snapshotters
+}
+
+// beginTick applies the requested rewind, and then takes a snapshot if needed. beginTick is called at the start of
+// a tick, before the game's Update.
+func (r *rewinder) beginTick(c *uiContext) error {
+	if err := r.applyRewind(c); err != nil {
+		return err
+	}
+
+	r.m.Lock()
+	tick := atomic.LoadInt64(&c.tick)
+	if !r.recording || tick%int64(r.interval) != 0 {
+		r.m.Unlock()
+		return nil
+	}
+	names, snapshotters := r.registeredSnapshottersWithLock()
+	r.m.Unlock()
+
+	// Call the Snapshotters without the lock, as the Snapshotters might take time.
+	s := snapshot{
+		tick:   tick,
+		states: make(map[string][]byte, len(names)),
+	}
+	for i, ss := range snapshotters {
+		data, err := ss.Snapshot()
+		if err != nil {
+			return err
+		}
+		s.states[names[i]] = data
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	// Recording might be stopped while taking the snapshot.
+	if !r.recording {
+		return nil
+	}
+	if len(r.snapshots) >= r.maxSnapshots {
+		copy(r.snapshots, r.snapshots[1:])
+		r.snapshots[len(r.snapshots)-1] = snapshot{}
+		r.snapshots = r.snapshots[:len(r.snapshots)-1]
+	}
+	r.snapshots = append(r.snapshots, s)
+	return nil
+}
+
+func (r *rewinder) applyRewind(c *uiContext) error {
+	r.m.Lock()
+	if !r.rewinding {
+		r.m.Unlock()
+		return nil
+	}
+	r.rewinding = false
+
+	idx := -1
+	for i, s := range r.snapshots {
+		if s.tick == r.rewindTo {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		// The snapshot was already discarded.
+		r.m.Unlock()
+		return nil
+	}
+	s := r.snapshots[idx]
+	// The restored snapshot is discarded too, as a snapshot at the same tick is taken again just after this.
+	for i := idx; i < len(r.snapshots); i++ {
+		r.snapshots[i] = snapshot{}
+	}
+	r.snapshots = r.snapshots[:idx]
+
+	names, snapshotters := r.registeredSnapshottersWithLock()
+	r.m.Unlock()
+
+	for i, ss := range snapshotters {
+		data, ok := s.states[names[i]]
+		if !ok {
+			// The Snapshotter was registered after the snapshot was taken.
+			continue
+		}
+		if err := ss.Restore(data); err != nil {
+			return err
+		}
+	}
+	atomic.StoreInt64(&c.tick, s.tick)
+	return nil
+}
@@ -15,6 +15,7 @@
 package ebiten
 
 import (
+	"fmt"
 	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/internal/clock"
@@ -24,8 +25,13 @@ import (
 var _ = __EBITEN_REQUIRES_GO_VERSION_1_12_OR_LATER__
 
 // Game defines necessary functions for a game.
+//
+// If the game also implements GameDrawer, the game's Draw is used for rendering instead of Update.
 type Game interface {
 	// Update updates a game by one frame.
+	//
+	// If the game implements GameDrawer, Update should update only the game logic, and should not draw anything
+	// on the given image. The image is cleared before Draw is called.
 	Update(*Image) error
 
 	// Layout accepts a native outside size in device-independent pixels and returns the game's logical screen
@@ -45,6 +51,49 @@ type Game interface {
 	Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int)
 }
 
+// GameDrawer is an optional interface of Game to separate rendering from updating.
+//
+// While Update is called every tick (TPS), Draw is called every frame (FPS). For example, when the display's
+// refresh rate is higher than TPS, Draw is called multiple times between two ticks, and you can interpolate
+// positions to render smooth motions. IsDrawingSkipped is not needed with Draw.
+type GameDrawer interface {
+	// Draw draws the game screen by one frame.
+	//
+	// The given screen is cleared before Draw is called.
+	Draw(screen *Image)
+}
+
+// GamePanic is the value of the panic re-raised when the game's Update or Draw panics.
+//
+// Before the panic is re-raised, the resources that the OS can't reclaim well by itself, like the audio devices and
+// the fullscreen window, are released.
+type GamePanic struct {
+	// Func is the name of the function that panicked like "Update" or "Draw".
+	Func string
+
+	// Tick is the number of the ticks when the panic happened.
+	Tick int64
+
+	// Value is the original value of the panic.
+	Value interface{}
+
+	// Stack is the stack trace of the panic.
+	Stack []byte
+}
+
+// Error implements error.
+func (p *GamePanic) Error() string {
+	return fmt.Sprintf("ebiten: the game's %s panicked at tick %d: %v\n\n%s", p.Func, p.Tick, p.Value, p.Stack)
+}
+
+// Unwrap returns the original value of the panic if the value is an error, or nil otherwise.
+func (p *GamePanic) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
 // TPS represents a default ticks per second, that represents how many times game updating happens in a second.
 const DefaultTPS = 60
 
@@ -65,6 +114,8 @@ func CurrentFPS() float64 {
 var (
 	isDrawingSkipped = int32(0)
 	currentMaxTPS    = int32(DefaultTPS)
+
+	currentMaxUpdatesPerFrame = int32(DefaultMaxUpdatesPerFrame)
 )
 
 func setDrawingSkipped(skipped bool) {
@@ -145,7 +196,7 @@ func IsRunningSlowly() bool {
 // The given scale is ignored on fullscreen mode or gomobile-build mode.
 //
 // On non-GopherJS environments, Run returns error when 1) OpenGL error happens, 2) audio error happens or
-// 3) f returns error. In the case of 3), Run returns the same error.
+// 3) f returns error. In the case of 3), Run returns the same error. If f returns Termination, Run returns nil.
 //
 // On GopherJS, Run returns immediately.
 // It is because the 'main' goroutine cannot be blocked on GopherJS due to the bug (gopherjs/gopherjs#826).
@@ -199,7 +250,7 @@ func Run(f func(*Image) error, width, height int, scale float64, title string) e
 // The given scale is ignored on fullscreen mode or gomobile-build mode.
 //
 // On non-GopherJS environments, RunGame returns error when 1) OpenGL error happens, 2) audio error happens or
-// 3) f returns error. In the case of 3), RunGame returns the same error.
+// 3) f returns error. In the case of 3), RunGame returns the same error. If f returns Termination, RunGame returns nil.
 //
 // On GopherJS, RunGame returns immediately.
 // It is because the 'main' goroutine cannot be blocked on GopherJS due to the bug (gopherjs/gopherjs#826).
@@ -213,10 +264,17 @@ func RunGame(game Game) error {
 	return runGame(game, 0)
 }
 
+// Termination is a special error which indicates the game should terminate without an error.
+//
+// When the game's Update returns Termination, Run and RunGame stop the main loop and return nil.
+// Use Termination instead of os.Exit to quit the game gracefully, so that deferred functions run and buffers like
+// audio and files are flushed.
+var Termination = driver.RegularTermination
+
 func runGame(game Game, scale float64) error {
 	theUIContext.set(game, scale)
 	if err := uiDriver().Run(theUIContext); err != nil {
-		if err == driver.RegularTermination {
+		if err == Termination {
 			return nil
 		}
 		return err
@@ -297,6 +355,27 @@ func SetCursorMode(mode CursorModeType) {
 	uiDriver().SetCursorMode(driver.CursorMode(mode))
 }
 
+// CursorShape returns the current cursor shape.
+//
+// CursorShape returns CursorShapeArrow on mobiles.
+//
+// CursorShape is concurrent-safe.
+func CursorShape() CursorShapeType {
+	return CursorShapeType(uiDriver().CursorShape())
+}
+
+// SetCursorShape sets the shape of the mouse cursor to one of the system's standard shapes.
+// The shape is shown only when the cursor mode is CursorModeVisible.
+//
+// The initial value is CursorShapeArrow.
+//
+// SetCursorShape does nothing on mobiles.
+//
+// SetCursorShape is concurrent-safe.
+func SetCursorShape(shape CursorShapeType) {
+	uiDriver().SetCursorShape(driver.CursorShape(shape))
+}
+
 // IsCursorVisible is deprecated as of 1.11.0-alpha. Use CursorMode instead.
 func IsCursorVisible() bool {
 	return CursorMode() == CursorModeVisible
@@ -335,7 +414,7 @@ func IsFullscreen() bool {
 // to fit with the monitor. The current scale value is ignored.
 //
 // On desktops, Ebiten uses 'windowed' fullscreen mode, which doesn't change
-// your monitor's resolution.
+// your monitor's resolution, by default. Use SetFullscreenVideoMode to use exclusive fullscreen mode.
 //
 // SetFullscreen does nothing on browsers.
 // SetFullscreen works as this as of 1.10.0-alpha.
@@ -348,6 +427,119 @@ func SetFullscreen(fullscreen bool) {
 	uiDriver().SetFullscreen(fullscreen)
 }
 
+// VideoModes returns the display modes that the monitor used in fullscreen mode supports.
+//
+// VideoModes returns nil on browsers and mobiles.
+//
+// VideoModes is concurrent-safe.
+func VideoModes() []VideoMode {
+	var ms []VideoMode
+	for _, m := range uiDriver().VideoModes() {
+		ms = append(ms, VideoMode(m))
+	}
+	return ms
+}
+
+// FullscreenVideoMode returns the display mode used in fullscreen mode.
+//
+// FullscreenVideoMode returns the zero value by default.
+//
+// FullscreenVideoMode is concurrent-safe.
+func FullscreenVideoMode() VideoMode {
+	return VideoMode(uiDriver().FullscreenVideoMode())
+}
+
+// SetFullscreenVideoMode sets the display mode used in fullscreen mode on desktops.
+//
+// If mode is the zero value, the monitor's resolution is not changed in fullscreen mode ('windowed' fullscreen).
+// This is the default.
+//
+// Otherwise, the monitor's display mode is switched to mode in fullscreen mode ('exclusive' fullscreen).
+// mode should be one of the values returned by VideoModes.
+// If RefreshRate is 0, the highest refresh rate for the resolution is used.
+// If the monitor doesn't support mode, the monitor's resolution is not changed.
+//
+// In exclusive fullscreen mode, the window is iconified and the monitor's display mode is restored
+// when the window loses focus, e.g., by alt-tab. The display mode is switched again when the window is restored.
+//
+// SetFullscreenVideoMode does nothing on browsers and mobiles.
+//
+// SetFullscreenVideoMode is concurrent-safe.
+func SetFullscreenVideoMode(mode VideoMode) {
+	uiDriver().SetFullscreenVideoMode(driver.VideoMode(mode))
+}
+
+// Monitors returns the monitors connected to the computer.
+//
+// The first monitor is the primary monitor.
+//
+// Monitors returns nil on browsers and mobiles.
+//
+// Note that this API is experimental.
+//
+// Monitors is concurrent-safe.
+func Monitors() []Monitor {
+	var ms []Monitor
+	for _, m := range uiDriver().Monitors() {
+		ms = append(ms, Monitor(m))
+	}
+	return ms
+}
+
+// FullscreenMonitor returns the index of the monitor used in fullscreen mode.
+//
+// FullscreenMonitor returns -1 by default.
+//
+// Note that this API is experimental.
+//
+// FullscreenMonitor is concurrent-safe.
+func FullscreenMonitor() int {
+	return uiDriver().FullscreenMonitor()
+}
+
+// SetFullscreenMonitor sets the monitor used in fullscreen mode on desktops.
+//
+// index is an index of the list returned by Monitors.
+// If index is negative, the window becomes fullscreen on the monitor which the window belongs to.
+// This is the default.
+// If index is out of range, e.g. when the monitor is disconnected, the default behavior is used.
+//
+// If the window is in fullscreen mode, the window is moved to the monitor immediately.
+//
+// SetFullscreenMonitor does nothing on browsers and mobiles.
+//
+// Note that this API is experimental.
+//
+// SetFullscreenMonitor is concurrent-safe.
+func SetFullscreenMonitor(index int) {
+	uiDriver().SetFullscreenMonitor(index)
+}
+
+// IsFullscreenSpanningEnabled reports whether the window covers all the monitors in fullscreen mode.
+//
+// Note that this API is experimental.
+//
+// IsFullscreenSpanningEnabled is concurrent-safe.
+func IsFullscreenSpanningEnabled() bool {
+	return uiDriver().IsFullscreenSpanningEnabled()
+}
+
+// SetFullscreenSpanningEnabled sets whether the window covers all the monitors in fullscreen mode on desktops.
+//
+// If enabled is true and two or more monitors are connected, the window in fullscreen mode becomes a borderless
+// window covering the bounding rectangle of all the monitors, which is useful for setups like cockpits and
+// arcade cabinets. The fullscreen monitor and the fullscreen video mode are ignored in this mode.
+// The initial value is false.
+//
+// SetFullscreenSpanningEnabled does nothing on browsers and mobiles.
+//
+// Note that this API is experimental.
+//
+// SetFullscreenSpanningEnabled is concurrent-safe.
+func SetFullscreenSpanningEnabled(enabled bool) {
+	uiDriver().SetFullscreenSpanningEnabled(enabled)
+}
+
 // IsForeground returns a boolean value indicating whether
 // the game is in focus or in the foreground.
 //
@@ -420,6 +612,141 @@ func SetVsyncEnabled(enabled bool) {
 	uiDriver().SetVsyncEnabled(enabled)
 }
 
+// IsLowLatencyModeEnabled returns a boolean value indicating whether the low latency mode is enabled.
+//
+// IsLowLatencyModeEnabled is concurrent-safe.
+func IsLowLatencyModeEnabled() bool {
+	return uiDriver().IsLowLatencyModeEnabled()
+}
+
+// SetLowLatencyModeEnabled sets a boolean value indicating whether the low latency mode is enabled.
+//
+// In the low latency mode, Ebiten waits for the GPU to finish rendering of each frame after swapping buffers.
+// This prevents the CPU from queuing frames ahead of the display, and reduces the latency between inputs and
+// the screen, at the cost of throughput. Combine this with LatestCursorPosition to render a cursor or an aiming
+// overlay for latency-sensitive games.
+//
+// The low latency mode works only with OpenGL so far. The initial value is false.
+//
+// SetLowLatencyModeEnabled does nothing on browsers and mobiles.
+//
+// SetLowLatencyModeEnabled is concurrent-safe.
+func SetLowLatencyModeEnabled(enabled bool) {
+	uiDriver().SetLowLatencyModeEnabled(enabled)
+}
+
+// MaxQueuedFrames returns the maximum number of the frames that the GPU can queue.
+//
+// MaxQueuedFrames is concurrent-safe.
+func MaxQueuedFrames() int {
+	return uiDriver().MaxQueuedFrames()
+}
+
+// SetMaxQueuedFrames sets the maximum number of the frames that the GPU can queue.
+//
+// After swapping buffers, Ebiten waits until the GPU finishes older frames so that the number of the frames the GPU
+// is processing doesn't exceed frames. 1 or 2 is recommended for action games: this reduces the latency between
+// inputs and the screen at the cost of a little throughput. 0 means no limit, which is the initial value.
+//
+// SetMaxQueuedFrames is lighter than the low latency mode, which always waits for the GPU to finish every frame.
+// When the low latency mode is enabled, SetMaxQueuedFrames has no effect.
+//
+// SetMaxQueuedFrames works only with OpenGL so far, and requires sync objects (OpenGL 3.2 or ARB_sync). Without
+// sync objects, 1 works like the low latency mode and the other values are ignored.
+//
+// SetMaxQueuedFrames does nothing on browsers and mobiles.
+//
+// SetMaxQueuedFrames panics if frames is negative.
+//
+// SetMaxQueuedFrames is concurrent-safe.
+func SetMaxQueuedFrames(frames int) {
+	if frames < 0 {
+		panic(fmt.Sprintf("ebiten: frames must be non-negative at SetMaxQueuedFrames but %d", frames))
+	}
+	uiDriver().SetMaxQueuedFrames(frames)
+}
+
+// IsBackgroundTextureUploadEnabled reports whether textures are uploaded on a dedicated thread.
+//
+// IsBackgroundTextureUploadEnabled is concurrent-safe.
+func IsBackgroundTextureUploadEnabled() bool {
+	return uiDriver().IsBackgroundTextureUploadEnabled()
+}
+
+// SetBackgroundTextureUploadEnabled sets whether textures are uploaded on a dedicated thread.
+//
+// When enabled, Ebiten creates an OpenGL context shared with the main context on a dedicated loader thread, and
+// uploads the pixels of big images there, e.g., by NewImageFromImage or ReplacePixels. This reduces hitches when
+// a game loads images while running, e.g., in a streaming world. The main thread waits for the upload only when
+// the image is used before the upload finishes. Small images and images already rendered are uploaded on the main
+// thread as usual.
+//
+// SetBackgroundTextureUploadEnabled works only with OpenGL on desktops. SetBackgroundTextureUploadEnabled does
+// nothing on browsers and mobiles.
+//
+// SetBackgroundTextureUploadEnabled must be called before the main loop starts, e.g., before RunGame. Calling it
+// after that has no effect. The default value is false.
+//
+// SetBackgroundTextureUploadEnabled is concurrent-safe.
+func SetBackgroundTextureUploadEnabled(enabled bool) {
+	uiDriver().SetBackgroundTextureUploadEnabled(enabled)
+}
+
+// IsScreenSaverEnabled returns a boolean value indicating whether the screen saver and the display sleep are
+// enabled during the game.
+//
+// IsScreenSaverEnabled is concurrent-safe.
+func IsScreenSaverEnabled() bool {
+	return uiDriver().IsScreenSaverEnabled()
+}
+
+// SetScreenSaverEnabled sets a boolean value indicating whether the screen saver and the display sleep are enabled
+// during the game.
+//
+// The screen saver is enabled by default. Disable it for games that can be played without a keyboard or a mouse,
+// e.g., with only gamepads, or while playing a video, so that the OS doesn't blank the screen.
+// The screen saver is enabled again when the game ends.
+//
+// On Linux, SetScreenSaverEnabled resets the screen saver periodically with xdg-screensaver.
+// On browsers, SetScreenSaverEnabled uses the Screen Wake Lock API, and does nothing if the API is not available.
+//
+// SetScreenSaverEnabled does nothing on mobiles.
+//
+// SetScreenSaverEnabled is concurrent-safe.
+func SetScreenSaverEnabled(enabled bool) {
+	uiDriver().SetScreenSaverEnabled(enabled)
+}
+
+// IsCaptureFriendlyModeEnabled returns a boolean value indicating whether the capture-friendly mode is enabled.
+//
+// IsCaptureFriendlyModeEnabled is concurrent-safe.
+func IsCaptureFriendlyModeEnabled() bool {
+	return uiDriver().IsCaptureFriendlyModeEnabled()
+}
+
+// SetCaptureFriendlyModeEnabled sets a boolean value indicating whether the capture-friendly mode is enabled.
+//
+// The capture-friendly mode is for streaming with capturing software like OBS.
+// In the capture-friendly mode, the game keeps updating and presenting frames even when the window is unfocused
+// or occluded, regardless of SetRunnableInBackground. On macOS, App Nap, which throttles occluded applications, is
+// disabled. With vsync and OpenGL, adaptive vsync is used if available: a late frame is presented immediately with
+// tearing instead of waiting for the next vblank, so that the frames are presented at a steady rate.
+//
+// Capturing software might identify the window by its title. Ebiten doesn't add any metadata to the window title,
+// so keep the title stable during streaming.
+//
+// Capturing software might capture only a black screen when the screen is transparent. Avoid SetScreenTransparent
+// for streaming.
+//
+// The capture-friendly mode is disabled by default.
+//
+// SetCaptureFriendlyModeEnabled does nothing on browsers and mobiles.
+//
+// SetCaptureFriendlyModeEnabled is concurrent-safe.
+func SetCaptureFriendlyModeEnabled(enabled bool) {
+	uiDriver().SetCaptureFriendlyModeEnabled(enabled)
+}
+
 // MaxTPS returns the current maximum TPS.
 //
 // MaxTPS is concurrent-safe.
@@ -453,6 +780,37 @@ func SetMaxTPS(tps int) {
 	atomic.StoreInt32(&currentMaxTPS, int32(tps))
 }
 
+// DefaultMaxUpdatesPerFrame is the default maximum number of Update calls in one frame.
+const DefaultMaxUpdatesPerFrame = 5
+
+// MaxUpdatesPerFrame returns the current maximum number of Update calls in one frame.
+//
+// MaxUpdatesPerFrame is concurrent-safe.
+func MaxUpdatesPerFrame() int {
+	return int(atomic.LoadInt32(&currentMaxUpdatesPerFrame))
+}
+
+// SetMaxUpdatesPerFrame sets the maximum number of Update calls in one frame.
+// The default value is DefaultMaxUpdatesPerFrame.
+//
+// When the game is behind the system clock, e.g., because Update or Draw is too heavy, Ebiten calls Update
+// multiple times in one frame to catch up. If the game is behind by more than n ticks, Ebiten gives up catching up:
+// the game time is synced with the system clock, Update is called only once in the frame, and the remaining ticks
+// are discarded. This prevents the situation that the catch-up updates make the game even slower ("spiral of death").
+//
+// If n is 1, Ebiten never calls Update more than once in one frame, and the game just runs slowly when it is heavy.
+// SetMaxUpdatesPerFrame has no effect when TPS is UncappedTPS.
+//
+// SetMaxUpdatesPerFrame panics if n is not positive.
+//
+// SetMaxUpdatesPerFrame is concurrent-safe.
+func SetMaxUpdatesPerFrame(n int) {
+	if n <= 0 {
+		panic(fmt.Sprintf("ebiten: n must be positive at SetMaxUpdatesPerFrame but %d", n))
+	}
+	atomic.StoreInt32(&currentMaxUpdatesPerFrame, int32(n))
+}
+
 // IsScreenTransparent reports whether the window is transparent.
 func IsScreenTransparent() bool {
 	return uiDriver().IsScreenTransparent()
@@ -0,0 +1,189 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/internal/shareable"
+)
+
+// PixelFormat represents a pixel format of an image.
+type PixelFormat int
+
+const (
+	// PixelFormatRGBA8 represents 8-bit RGBA. This is the default.
+	PixelFormatRGBA8 PixelFormat = iota
+
+	// PixelFormatRGB565 represents 16-bit RGB without alpha. This saves memory on memory-limited devices.
+	//
+	// The graphics drivers don't allocate 16-bit RGB images so far, and PixelFormatRGBA8 is used instead.
+	PixelFormatRGB565
+
+	// PixelFormatRGBA16F represents 16-bit floating point RGBA for high dynamic range rendering.
+	PixelFormatRGBA16F
+)
+
+// RunOptions represents options for RunGameWithOptions.
+//
+// The zero values keep the current settings, so the settings by functions like SetWindowTitle before
+// RunGameWithOptions are respected. New options might be added in the future without breaking callers.
+type RunOptions struct {
+	// WindowTitle is the title of the window. This is the same as SetWindowTitle.
+	WindowTitle string
+
+	// WindowWidth and WindowHeight are the size of the window. This is the same as SetWindowSize.
+	// If either is zero, the window size is not changed.
+	WindowWidth  int
+	WindowHeight int
+
+	// WindowIcon is the icon images of the window. This is the same as SetWindowIcon.
+	WindowIcon []image.Image
+
+	// WindowResizable makes the window resizable. This is the same as SetWindowResizable(true).
+	WindowResizable bool
+
+	// WindowUndecorated removes the decoration of the window. This is the same as SetWindowDecorated(false).
+	WindowUndecorated bool
+
+	// Fullscreen starts the game in fullscreen mode. This is the same as SetFullscreen(true).
+	Fullscreen bool
+
+	// VsyncDisabled disables vsync. This is the same as SetVsyncEnabled(false).
+	VsyncDisabled bool
+
+	// RunnableInBackground keeps the game running when the window is in background.
+	// This is the same as SetRunnableInBackground(true).
+	RunnableInBackground bool
+
+	// ScreenTransparent makes the screen transparent. This is the same as SetScreenTransparent(true).
+	ScreenTransparent bool
+
+	// ScreenPixelFormat is the pixel format of the logical screen, which is the image passed to the game's Update.
+	//
+	// With PixelFormatRGBA16F, the logical screen is an HDR image like NewHDRImage. If the format is not available
+	// on the environment, the logical screen works as PixelFormatRGBA8. Use CurrentScreenPixelFormat to get the
+	// actual format.
+	//
+	// The default (zero) value is PixelFormatRGBA8.
+	ScreenPixelFormat PixelFormat
+
+	// AtlasPadding is the size of the padding in pixels around each image packed into the internal texture atlas.
+	//
+	// The edge pixels of an image are extruded into the padding so that linear filtering or scaling never bleeds
+	// the neighboring images, e.g., tiles of a tilemap, into the image.
+	//
+	// If AtlasPadding is 0, the default padding (1 pixel) is used. If AtlasPadding is negative, no padding is used.
+	AtlasPadding int
+
+	// Warmup is a function to prepare the game, e.g., decoding assets and creating images.
+	//
+	// Warmup is called on another goroutine, concurrently with creating the window and the graphics context, which
+	// can take a long time especially on Windows. The game's Update is not called until Warmup returns.
+	// If Warmup returns an error, RunGameWithOptions returns the error.
+	//
+	// The image operations in Warmup are queued and executed in order on the game's goroutine. Reading pixels, e.g.
+	// (*Image).At, is not available in Warmup.
+	//
+	// The default (zero) value is nil, which means that no warmup is done.
+	Warmup func() error
+
+	// Splash is an image presented at the center of the screen as soon as the window is created, before the
+	// game's first update. Splash is presented until Warmup returns, or once when Warmup is nil.
+	//
+	// Splash is shrunk to fit with the screen, but is never enlarged.
+	//
+	// The default (zero) value is nil, which means that no splash image is presented.
+	Splash image.Image
+}
+
+// requestedScreenPixelFormat is the pixel format of the logical screen specified by RunOptions.
+var requestedScreenPixelFormat int32
+
+func screenPixelFormat() PixelFormat {
+	return PixelFormat(atomic.LoadInt32(&requestedScreenPixelFormat))
+}
+
+// CurrentScreenPixelFormat returns the actual pixel format of the logical screen.
+//
+// CurrentScreenPixelFormat returns PixelFormatRGBA8 until the first frame is rendered. Call this in the game's Update.
+//
+// CurrentScreenPixelFormat is concurrent-safe.
+func CurrentScreenPixelFormat() PixelFormat {
+	if screenPixelFormat() == PixelFormatRGBA16F && graphicscommand.FloatImagesAvailable() {
+		return PixelFormatRGBA16F
+	}
+	// PixelFormatRGB565 falls back to PixelFormatRGBA8 on all the graphics drivers so far.
+	return PixelFormatRGBA8
+}
+
+// RunGameWithOptions starts the main loop and runs the game with the given options.
+// options can be nil. RunGameWithOptions with nil options is the same as RunGame.
+//
+// See RunGame for the details.
+func RunGameWithOptions(game Game, options *RunOptions) error {
+	if options == nil {
+		options = &RunOptions{}
+	}
+	if options.WindowTitle != "" {
+		SetWindowTitle(options.WindowTitle)
+	}
+	if options.WindowWidth > 0 && options.WindowHeight > 0 {
+		SetWindowSize(options.WindowWidth, options.WindowHeight)
+	}
+	if options.WindowIcon != nil {
+		SetWindowIcon(options.WindowIcon)
+	}
+	if options.WindowResizable {
+		SetWindowResizable(true)
+	}
+	if options.WindowUndecorated {
+		SetWindowDecorated(false)
+	}
+	if options.Fullscreen {
+		SetFullscreen(true)
+	}
+	if options.VsyncDisabled {
+		SetVsyncEnabled(false)
+	}
+	if options.RunnableInBackground {
+		SetRunnableInBackground(true)
+	}
+	if options.ScreenTransparent {
+		SetScreenTransparent(true)
+	}
+	if options.AtlasPadding != 0 {
+		padding := options.AtlasPadding
+		if padding < 0 {
+			padding = 0
+		}
+		shareable.SetPadding(padding)
+	}
+	switch options.ScreenPixelFormat {
+	case PixelFormatRGBA8, PixelFormatRGB565, PixelFormatRGBA16F:
+		atomic.StoreInt32(&requestedScreenPixelFormat, int32(options.ScreenPixelFormat))
+	default:
+		panic("ebiten: invalid pixel format")
+	}
+	if options.Splash != nil {
+		theUIContext.setSplash(options.Splash)
+	}
+	if options.Warmup != nil {
+		theUIContext.startWarmup(options.Warmup)
+	}
+	return RunGame(game)
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+	"image/color"
+)
+
+// Screenshot returns the last frame presented on the screen.
+//
+// The returned image is the game screen after all the compositions like the color vision filter and the present
+// hooks. The size is the same as the composited image, which is usually the game screen size, and doesn't depend
+// on the window size or the device scale factor. The returned image is an independent copy and the game can keep
+// it after the next frames.
+//
+// Screenshot returns nil if no frame has been presented yet.
+//
+// Like At, Screenshot loads pixels from GPU to system memory, which means that Screenshot can be slow.
+// Screenshot can't be called outside the main loop.
+//
+// Note that this API is experimental.
+func Screenshot() image.Image {
+	img := theUIContext.lastFrameRGBA()
+	if img == nil {
+		return nil
+	}
+	return img
+}
+
+// lastFrameRGBA returns the pixels of the last composited frame, or nil if there is no frame yet.
+func (c *uiContext) lastFrameRGBA() *image.RGBA {
+	img := c.lastFrame
+	if img == nil {
+		return nil
+	}
+
+	w, h := img.Size()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			dst.SetRGBA(i, j, img.At(i, j).(color.RGBA))
+		}
+	}
+	return dst
+}
+
+// updateLastFrame copies the composited frame src for Screenshot.
+func (c *uiContext) updateLastFrame(src *Image) {
+	w, h := src.Size()
+	if c.lastFrame != nil {
+		if lw, lh := c.lastFrame.Size(); lw != w || lh != h {
+			_ = c.lastFrame.Dispose()
+			c.lastFrame = nil
+		}
+	}
+	if c.lastFrame == nil {
+		// lastFrame must keep its content over frames, then lastFrame is not volatile.
+		c.lastFrame = newImage(w, h, FilterDefault, false)
+	}
+
+	op := &DrawImageOptions{}
+	op.CompositeMode = CompositeModeCopy
+	_ = c.lastFrame.DrawImage(src, op)
+}
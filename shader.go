@@ -0,0 +1,249 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/internal/buffered"
+	"github.com/hajimehoshi/ebiten/internal/driver"
+	"github.com/hajimehoshi/ebiten/internal/graphics"
+	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/internal/shader"
+)
+
+// Shader represents a compiled shader program.
+//
+// A shader program is written in a shading language, a subset of Go. A program must have the entry point function
+// Fragment, which returns the color of each pixel as a premultiplied-alpha vec4:
+//
+//     package main
+//
+//     // Package-level variables are uniform variables, which are specified by DrawRectShaderOptions.Uniforms.
+//     var Time float
+//
+//     func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+//         // position is the destination position in pixels.
+//         // texCoord is the position on the source texture. Read the source image by texture0At.
+//         pos := texCoord + vec2(sin(position.y/16+Time)*4, 0)/texture0Size()
+//         return texture0At(pos) * color
+//     }
+//
+// The available types are bool, int, float, vec2, vec3, vec4, mat2, mat3 and mat4. Uniform variables must be float,
+// vec2, vec3, vec4 or mat4.
+//
+// The available built-in functions are the same as GLSL's, like sin, mix or clamp, with the following additions:
+//
+//     atan2(y, x float) float
+//     texture0At(pos vec2) vec4   // Returns the source color at pos. This returns a transparent color outside of the source image.
+//     texture0Size() vec2         // Returns the size of the source texture in texels.
+//     texture0Region() vec4       // Returns the region of the source image on the texture as (minX, minY, maxX, maxY).
+//
+// This API is experimental. Custom shaders are available only with OpenGL for now. With the other graphics drivers,
+// e.g., Metal, that is used on macOS 10.12 or later and iOS, NewShader returns an error. See also IsShaderAvailable.
+type Shader struct {
+	shader  *buffered.Shader
+	program *shader.Program
+}
+
+// NewShader compiles the given shader program and returns a new shader.
+//
+// NewShader returns an error when the program is invalid, or when custom shaders are not available with the
+// current graphics driver.
+func NewShader(src []byte) (*Shader, error) {
+	if !IsShaderAvailable() {
+		return nil, fmt.Errorf("ebiten: custom shaders are not available with the current graphics driver")
+	}
+	p, err := shader.Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Shader{
+		shader:  buffered.NewShader(p),
+		program: p,
+	}, nil
+}
+
+// IsShaderAvailable reports whether custom shaders are available with the current graphics driver.
+//
+// Unlike IsHDRImageAvailable, IsShaderAvailable can be called before Run.
+//
+// This API is experimental.
+//
+// IsShaderAvailable is concurrent-safe.
+func IsShaderAvailable() bool {
+	return graphicscommand.ShadersAvailable()
+}
+
+// Dispose disposes the shader program.
+//
+// After disposing, the shader must not be used.
+func (s *Shader) Dispose() {
+	if s.shader == nil {
+		return
+	}
+	s.shader.Dispose()
+	s.shader = nil
+}
+
+// uniforms converts the given uniform variable values into the values in the declared order.
+func (s *Shader) uniforms(values map[string]interface{}) [][]float32 {
+	us := make([][]float32, len(s.program.Uniforms))
+	names := map[string]struct{}{}
+	for i, u := range s.program.Uniforms {
+		names[u.Name] = struct{}{}
+		n := u.Type.FloatNum()
+		v, ok := values[u.Name]
+		if !ok {
+			us[i] = make([]float32, n)
+			continue
+		}
+		var fs []float32
+		switch v := v.(type) {
+		case float32:
+			fs = []float32{v}
+		case float64:
+			fs = []float32{float32(v)}
+		case []float32:
+			fs = make([]float32, len(v))
+			copy(fs, v)
+		case []float64:
+			fs = make([]float32, len(v))
+			for i := range v {
+				fs[i] = float32(v[i])
+			}
+		default:
+			panic(fmt.Sprintf("ebiten: the uniform variable %s must be a float or a slice of floats but %T", u.Name, v))
+		}
+		if len(fs) != n {
+			panic(fmt.Sprintf("ebiten: the uniform variable %s of %s must have %d values but %d", u.Name, u.Type, n, len(fs)))
+		}
+		us[i] = fs
+	}
+	for name := range values {
+		if _, ok := names[name]; !ok {
+			panic(fmt.Sprintf("ebiten: the uniform variable %s is not defined in the shader", name))
+		}
+	}
+	return us
+}
+
+// DrawRectShaderOptions represents options for DrawRectShader.
+//
+// This API is experimental.
+type DrawRectShaderOptions struct {
+	// GeoM is a geometry matrix to draw.
+	// The default (zero) value is identity, which draws the rectangle at (0, 0).
+	GeoM GeoM
+
+	// CompositeMode is a composite mode to draw.
+	// The default (zero) value is regular alpha blending.
+	CompositeMode CompositeMode
+
+	// Uniforms is a set of values of the uniform variables for the shader.
+	// The keys are the names of the uniform variables.
+	// The values are float32 or float64 for float, and []float32 or []float64 for vectors and matrices.
+	// A uniform variable that is not specified is zero.
+	Uniforms map[string]interface{}
+
+	// Image is the source image that the shader reads with texture0At.
+	// The size of Image must be the same as the rectangle.
+	// If Image is nil, texture0At returns a transparent color.
+	Image *Image
+}
+
+var (
+	emptyShaderSourceImage     *Image
+	emptyShaderSourceImageOnce sync.Once
+)
+
+// DrawRectShader draws a rectangle with the specified width and height with the specified shader.
+//
+// For the shader language, see Shader.
+//
+// When the given image is disposed, DrawRectShader does nothing.
+//
+// This API is experimental.
+func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawRectShaderOptions) {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return
+	}
+
+	if i.isSubImage() {
+		panic("ebiten: render to a subimage is not implemented (DrawRectShader)")
+	}
+
+	if shader.shader == nil {
+		panic("ebiten: the shader is already disposed (DrawRectShader)")
+	}
+
+	if options == nil {
+		options = &DrawRectShaderOptions{}
+	}
+
+	src := options.Image
+	if src == nil {
+		emptyShaderSourceImageOnce.Do(func() {
+			emptyShaderSourceImage, _ = NewImage(1, 1, FilterDefault)
+		})
+		src = emptyShaderSourceImage
+	} else {
+		if src.isDisposed() {
+			panic("ebiten: the source image must not be disposed (DrawRectShader)")
+		}
+		if w, h := src.Size(); w != width || h != height {
+			panic(fmt.Sprintf("ebiten: the source image size must be %d x %d but %d x %d (DrawRectShader)", width, height, w, h))
+		}
+	}
+
+	b := src.Bounds()
+	bx0 := float32(b.Min.X)
+	by0 := float32(b.Min.Y)
+	bx1 := float32(b.Max.X)
+	by1 := float32(b.Max.Y)
+	sw, sh := float32(width), float32(height)
+	if options.Image == nil {
+		// Make the source region empty so that texture0At always returns a transparent color.
+		bx1, by1 = bx0, by0
+		sw, sh = 0, 0
+	}
+
+	vs := make([]float32, 4*graphics.VertexFloatNum)
+	for idx, p := range [][2]float32{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		x, y := options.GeoM.apply32(p[0]*float32(width), p[1]*float32(height))
+		vs[idx*graphics.VertexFloatNum] = x
+		vs[idx*graphics.VertexFloatNum+1] = y
+		vs[idx*graphics.VertexFloatNum+2] = bx0 + p[0]*sw
+		vs[idx*graphics.VertexFloatNum+3] = by0 + p[1]*sh
+		vs[idx*graphics.VertexFloatNum+4] = bx0
+		vs[idx*graphics.VertexFloatNum+5] = by0
+		vs[idx*graphics.VertexFloatNum+6] = bx1
+		vs[idx*graphics.VertexFloatNum+7] = by1
+		vs[idx*graphics.VertexFloatNum+8] = 1
+		vs[idx*graphics.VertexFloatNum+9] = 1
+		vs[idx*graphics.VertexFloatNum+10] = 1
+		vs[idx*graphics.VertexFloatNum+11] = 1
+	}
+	is := graphics.QuadIndices()
+
+	mode := driver.CompositeMode(options.CompositeMode)
+	if err := i.buffered.DrawShader(src.buffered, vs, is, shader.shader, shader.uniforms(options.Uniforms), mode); err != nil {
+		theUIContext.setError(err)
+	}
+	recordTriangles(i, vs, is)
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/internal/buffered"
+	"github.com/hajimehoshi/ebiten/internal/driver"
+)
+
+// splash is an image presented before the game's first update.
+type splash struct {
+	source image.Image
+	image  *Image
+	screen *Image
+
+	// presented reports whether the splash image has been presented at least once.
+	presented bool
+}
+
+func (c *uiContext) setSplash(source image.Image) {
+	c.splash = &splash{
+		source: source,
+	}
+}
+
+// needsSplash reports whether the splash image should be presented instead of updating the game.
+func (c *uiContext) needsSplash(waitingForWarmup bool) bool {
+	if c.splash == nil {
+		return false
+	}
+	if waitingForWarmup || !c.splash.presented {
+		return true
+	}
+	c.disposeSplash()
+	return false
+}
+
+func (c *uiContext) disposeSplash() {
+	if c.splash.image != nil {
+		_ = c.splash.image.Dispose()
+	}
+	if c.splash.screen != nil {
+		_ = c.splash.screen.Dispose()
+	}
+	c.splash = nil
+}
+
+// drawSplash presents the splash image at the center of the screen.
+// The game's Layout is not called, since the game might not be ready yet.
+//
+// While waiting for the warmup, the image operations are kept delayed and executed on this goroutine, since the
+// warmup might use images on another goroutine.
+func (c *uiContext) drawSplash(waitingForWarmup bool) error {
+	s := c.splash
+
+	d := uiDriver().DeviceScaleFactor()
+	fw, fh := int(c.outsideWidth*d), int(c.outsideHeight*d)
+	if fw <= 0 || fh <= 0 {
+		return nil
+	}
+
+	if waitingForWarmup {
+		if err := buffered.BeginFrameKeepingCommandsDelayed(); err != nil {
+			return err
+		}
+	} else {
+		if err := buffered.BeginFrame(); err != nil {
+			return err
+		}
+	}
+
+	if s.screen != nil {
+		if w, h := s.screen.Size(); w != fw || h != fh {
+			_ = s.screen.Dispose()
+			s.screen = nil
+		}
+	}
+	if s.screen == nil {
+		s.screen = newScreenFramebufferImage(fw, fh)
+	}
+	if s.image == nil {
+		s.image, _ = NewImageFromImage(s.source, FilterLinear)
+	}
+
+	// The splash image is shrunk to fit with the screen, but is never enlarged.
+	w, h := s.image.Size()
+	scale := math.Min(1, math.Min(float64(fw)/float64(w), float64(fh)/float64(h)))
+
+	op := &DrawImageOptions{}
+	switch vd := uiDriver().Graphics().VDirection(); vd {
+	case driver.VDownward:
+		// The screen's Y axis is down to up, and the origin point is lower left.
+		op.GeoM.Scale(scale, -scale)
+		op.GeoM.Translate(0, float64(h)*scale)
+	case driver.VUpward:
+		op.GeoM.Scale(scale, scale)
+	default:
+		panic(fmt.Sprintf("ebiten: invalid v-direction: %d", vd))
+	}
+	op.GeoM.Translate((float64(fw)-float64(w)*scale)/2, (float64(fh)-float64(h)*scale)/2)
+
+	s.screen.Clear()
+	_ = s.screen.DrawImage(s.image, op)
+
+	if err := buffered.EndFrame(); err != nil {
+		return err
+	}
+	s.presented = true
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/internal/systemtheme"
+)
+
+// ColorScheme represents the color scheme preference of the system.
+type ColorScheme int
+
+const (
+	// ColorSchemeUnknown represents that the preference is unknown.
+	ColorSchemeUnknown ColorScheme = ColorScheme(systemtheme.ColorSchemeUnknown)
+
+	// ColorSchemeLight represents the light mode.
+	ColorSchemeLight ColorScheme = ColorScheme(systemtheme.ColorSchemeLight)
+
+	// ColorSchemeDark represents the dark mode.
+	ColorSchemeDark ColorScheme = ColorScheme(systemtheme.ColorSchemeDark)
+)
+
+var (
+	systemThemeTracked     bool
+	systemThemePrev        systemtheme.Theme
+	systemThemeJustChanged bool
+	systemThemeM           sync.Mutex
+)
+
+func trackSystemTheme() systemtheme.Theme {
+	t := systemtheme.Get()
+
+	systemThemeM.Lock()
+	defer systemThemeM.Unlock()
+	if !systemThemeTracked {
+		systemThemeTracked = true
+		systemThemePrev = t
+	}
+	return t
+}
+
+// updateSystemTheme updates the state whether the system theme is changed.
+// The system theme is not queried until any of the system theme functions is called.
+func updateSystemTheme() {
+	systemThemeM.Lock()
+	defer systemThemeM.Unlock()
+	if !systemThemeTracked {
+		return
+	}
+	t := systemtheme.Get()
+	systemThemeJustChanged = t != systemThemePrev
+	systemThemePrev = t
+}
+
+// SystemColorScheme returns the color scheme preference of the system, i.e., whether the dark mode is enabled.
+//
+// SystemColorScheme might return ColorSchemeUnknown when the platform doesn't provide the preference.
+// SystemColorScheme always returns ColorSchemeUnknown on mobiles.
+//
+// SystemColorScheme is concurrent-safe.
+func SystemColorScheme() ColorScheme {
+	return ColorScheme(trackSystemTheme().ColorScheme)
+}
+
+// SystemAccentColor returns the accent color of the system.
+//
+// SystemAccentColor returns false as the second value when the platform doesn't provide the accent color, e.g.,
+// on browsers and mobiles.
+//
+// SystemAccentColor is concurrent-safe.
+func SystemAccentColor() (color.Color, bool) {
+	t := trackSystemTheme()
+	if !t.HasAccentColor {
+		return nil, false
+	}
+	return t.AccentColor, true
+}
+
+// IsSystemThemeJustChanged returns a boolean indicating whether the color scheme or the accent color of the system
+// is changed in the current frame.
+//
+// The system theme is checked periodically, and a change might be detected with some delay.
+// IsSystemThemeJustChanged always returns false until SystemColorScheme or SystemAccentColor is called first.
+//
+// IsSystemThemeJustChanged is concurrent-safe.
+func IsSystemThemeJustChanged() bool {
+	systemThemeM.Lock()
+	defer systemThemeM.Unlock()
+	return systemThemeJustChanged
+}
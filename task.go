@@ -0,0 +1,139 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Go runs task on a background worker, and then calls done on the game thread at the start of a subsequent Update,
+// before the game's Update is called.
+//
+// Go is useful for work that takes longer than a frame like loading assets or pathfinding. done can use the
+// results of task safely without locks, as done is called in the same goroutine as the game's Update. Let task and
+// done share the results via the variables captured by the closures, e.g.:
+//
+//     var img image.Image
+//     var err error
+//     ebiten.Go(func() {
+//         img, err = loadImage("background.png")
+//     }, func() {
+//         if err != nil {
+//             // Handle the error.
+//         }
+//         // Use img.
+//     })
+//
+// Go doesn't block. The tasks are run by a pool of workers whose size is the number of the CPUs. The tasks are
+// started in the order of the calls to Go, but might finish in a different order. done is called in the order of
+// the finishes of the tasks. done can be nil.
+//
+// When the game is not running, done is called at the first Update after Run is called.
+//
+// Go panics if task is nil.
+//
+// Note that this API is experimental.
+//
+// Go is concurrent-safe.
+func Go(task func(), done func()) {
+	if task == nil {
+		panic("ebiten: task must not be nil at Go")
+	}
+	theTaskScheduler.run(task, done)
+}
+
+type scheduledTask struct {
+	task func()
+	done func()
+}
+
+type taskScheduler struct {
+	queue   []scheduledTask
+	workers int
+
+	// finished is the done functions of the tasks that have finished.
+	finished []func()
+
+	// spare is a slice to be swapped with finished to avoid allocations. spare is used only on the game thread.
+	spare []func()
+
+	cond *sync.Cond
+	m    sync.Mutex
+}
+
+var theTaskScheduler = &taskScheduler{}
+
+func init() {
+	theTaskScheduler.cond = sync.NewCond(&theTaskScheduler.m)
+}
+
+func (s *taskScheduler) run(task func(), done func()) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.queue = append(s.queue, scheduledTask{
+		task: task,
+		done: done,
+	})
+
+	// Workers are started lazily, and are never stopped.
+	if s.workers < runtime.NumCPU() && s.workers < len(s.queue) {
+		s.workers++
+		go s.loop()
+	}
+	s.cond.Signal()
+}
+
+func (s *taskScheduler) loop() {
+	for {
+		s.m.Lock()
+		for len(s.queue) == 0 {
+			s.cond.Wait()
+		}
+		t := s.queue[0]
+		s.queue[0] = scheduledTask{}
+		s.queue = s.queue[1:]
+		s.m.Unlock()
+
+		t.task()
+
+		if t.done == nil {
+			continue
+		}
+		s.m.Lock()
+		s.finished = append(s.finished, t.done)
+		s.m.Unlock()
+	}
+}
+
+// deliver calls the done functions of the finished tasks. deliver must be called on the game thread.
+func (s *taskScheduler) deliver() {
+	s.m.Lock()
+	fs := s.finished
+	if len(fs) == 0 {
+		s.m.Unlock()
+		return
+	}
+	s.finished = s.spare[:0]
+	s.m.Unlock()
+
+	// The done functions might call Go. Don't hold the lock.
+	for i, f := range fs {
+		f()
+		fs[i] = nil
+	}
+	s.spare = fs[:0]
+}
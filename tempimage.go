@@ -0,0 +1,121 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"image"
+	"sync"
+)
+
+// tempImageMaxIdleFrames is the number of frames after which an unused temporary image is disposed.
+const tempImageMaxIdleFrames = 60
+
+type tempImage struct {
+	img       *Image
+	lastFrame int
+}
+
+type tempImagePool struct {
+	free     map[image.Point][]*tempImage
+	acquired map[*Image]*tempImage
+	frame    int
+	m        sync.Mutex
+}
+
+var theTempImagePool = &tempImagePool{
+	free:     map[image.Point][]*tempImage{},
+	acquired: map[*Image]*tempImage{},
+}
+
+// AcquireTempImage returns a cleared temporary image with the given size from the pool.
+//
+// Temporary images are useful for effects that need scratch render targets every frame, like blurs. The pool
+// reuses the images of the same size, which avoids the cost of creating and disposing textures every frame.
+//
+// The returned image must be returned to the pool by ReleaseTempImage, and must not be used or disposed after
+// that. Images unused for a while in the pool are disposed automatically.
+//
+// AcquireTempImage is concurrent-safe.
+func AcquireTempImage(width, height int) *Image {
+	if width <= 0 || height <= 0 {
+		panic("ebiten: width and height must be positive")
+	}
+
+	p := theTempImagePool
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	size := image.Pt(width, height)
+	if ts := p.free[size]; len(ts) > 0 {
+		// Use the most recently used one so that the older ones can be disposed.
+		t := ts[len(ts)-1]
+		p.free[size] = ts[:len(ts)-1]
+		p.acquired[t.img] = t
+		_ = t.img.Clear()
+		return t.img
+	}
+
+	img, _ := NewImage(width, height, FilterDefault)
+	p.acquired[img] = &tempImage{
+		img: img,
+	}
+	return img
+}
+
+// ReleaseTempImage returns the temporary image acquired by AcquireTempImage to the pool.
+//
+// ReleaseTempImage panics if img is not acquired by AcquireTempImage.
+//
+// ReleaseTempImage is concurrent-safe.
+func ReleaseTempImage(img *Image) {
+	p := theTempImagePool
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	t, ok := p.acquired[img]
+	if !ok {
+		panic("ebiten: the image is not acquired by AcquireTempImage")
+	}
+	delete(p.acquired, img)
+	t.lastFrame = p.frame
+	size := image.Pt(img.Size())
+	p.free[size] = append(p.free[size], t)
+}
+
+// endFrame disposes the temporary images unused for a while.
+//
+// endFrame must be called at the end of every frame.
+func (p *tempImagePool) endFrame() {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.frame++
+	for size, ts := range p.free {
+		n := 0
+		for _, t := range ts {
+			if p.frame-t.lastFrame > tempImageMaxIdleFrames {
+				_ = t.img.Dispose()
+				continue
+			}
+			ts[n] = t
+			n++
+		}
+		if n == 0 {
+			delete(p.free, size)
+			continue
+		}
+		p.free[size] = ts[:n]
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"image/color"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func TestTempImage(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Width0         int
+		Height0        int
+		Width1         int
+		Height1        int
+		IdleFrames     int
+		ShouldBeReused bool
+	}{
+		{
+			Name:           "same size",
+			Width0:         16,
+			Height0:        16,
+			Width1:         16,
+			Height1:        16,
+			IdleFrames:     0,
+			ShouldBeReused: true,
+		},
+		{
+			Name:           "different size",
+			Width0:         16,
+			Height0:        16,
+			Width1:         8,
+			Height1:        16,
+			IdleFrames:     0,
+			ShouldBeReused: false,
+		},
+		{
+			Name:           "idle within the limit",
+			Width0:         16,
+			Height0:        16,
+			Width1:         16,
+			Height1:        16,
+			IdleFrames:     TempImageMaxIdleFrames,
+			ShouldBeReused: true,
+		},
+		{
+			Name:           "idle over the limit",
+			Width0:         16,
+			Height0:        16,
+			Width1:         16,
+			Height1:        16,
+			IdleFrames:     TempImageMaxIdleFrames + 1,
+			ShouldBeReused: false,
+		},
+	}
+	for _, c := range cases {
+		img0 := AcquireTempImage(c.Width0, c.Height0)
+		img0.Fill(color.RGBA{0xff, 0, 0, 0xff})
+		ReleaseTempImage(img0)
+		for i := 0; i < c.IdleFrames; i++ {
+			EndTempImageFrameForTesting()
+		}
+
+		img1 := AcquireTempImage(c.Width1, c.Height1)
+		if got := img1 == img0; got != c.ShouldBeReused {
+			t.Errorf("%s: reused: got: %t, want: %t", c.Name, got, c.ShouldBeReused)
+		}
+		if w, h := img1.Size(); w != c.Width1 || h != c.Height1 {
+			t.Errorf("%s: size: got: (%d, %d), want: (%d, %d)", c.Name, w, h, c.Width1, c.Height1)
+		}
+		// A reused image must be cleared.
+		if got, want := img1.At(0, 0), (color.RGBA{}); got != want {
+			t.Errorf("%s: At(0, 0): got: %v, want: %v", c.Name, got, want)
+		}
+		ReleaseTempImage(img1)
+	}
+}
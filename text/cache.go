@@ -0,0 +1,167 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+const stringCacheLimit = 256 // This is an arbitrary number.
+
+type stringCacheEntry struct {
+	image *ebiten.Image
+	atime int64
+}
+
+var stringCache = map[font.Face]map[string]*stringCacheEntry{}
+
+// CacheString returns a white image of the string s rendered with the face, and caches it.
+//
+// The image's upper-left corner corresponds to (0, -ascent) relative to the 'dot' (period) position, where ascent is
+// face.Metrics().Ascent.Ceil(). Its height covers the ascent and the descent, and parts of glyphs outside of them
+// are clipped. Use ColorM to render the image with colors.
+//
+// Drawing the image costs only one quad, while Draw costs one quad per glyph. This is useful for static labels.
+// Use DrawCached to draw a cached string at a dot position.
+//
+// The cache is limited in least-recently-used way. The returned image is disposed when it is evicted from the cache
+// or the cache is invalidated. Do not keep the image across frames, and call CacheString every frame instead.
+// It is OK in terms of performance.
+//
+// CacheString returns nil if the string has no visible size.
+//
+// CacheString is concurrent-safe.
+func CacheString(face font.Face, s string) *ebiten.Image {
+	textM.Lock()
+	defer textM.Unlock()
+	return cacheString(face, s)
+}
+
+func cacheString(face font.Face, s string) *ebiten.Image {
+	if _, ok := stringCache[face]; !ok {
+		stringCache[face] = map[string]*stringCacheEntry{}
+	}
+	if e, ok := stringCache[face][s]; ok {
+		e.atime = now()
+		return e.image
+	}
+
+	if len(stringCache[face]) >= stringCacheLimit {
+		oldest := int64(math.MaxInt64)
+		oldestKey := ""
+		for k, e := range stringCache[face] {
+			if e.atime < oldest {
+				oldestKey = k
+				oldest = e.atime
+			}
+		}
+		if e := stringCache[face][oldestKey]; e.image != nil {
+			_ = e.image.Dispose()
+		}
+		delete(stringCache[face], oldestKey)
+	}
+
+	img := renderString(face, s)
+	stringCache[face][s] = &stringCacheEntry{
+		image: img,
+		atime: now(),
+	}
+	return img
+}
+
+func renderString(face font.Face, s string) *ebiten.Image {
+	m := face.Metrics()
+	ascent := m.Ascent.Ceil()
+	w := measureRunes(face, []rune(s)).Ceil()
+	h := ascent + m.Descent.Ceil()
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	d := font.Drawer{
+		Dst:  rgba,
+		Src:  image.White,
+		Face: face,
+	}
+	var dot fixed.Int26_6
+	prevR := rune(-1)
+	for _, r := range s {
+		if prevR >= 0 {
+			dot += hintedKern(face, prevR, r)
+		}
+		d.Dot = fixed.Point26_6{X: dot, Y: fixed.I(ascent)}
+		d.DrawString(string(r))
+		dot += hintedAdvance(face, r)
+		prevR = r
+	}
+
+	img, _ := ebiten.NewImageFromImage(rgba, ebiten.FilterDefault)
+	return img
+}
+
+// DrawCached draws the string s cached by CacheString on dst.
+//
+// (x, y) represents a 'dot' (period) position as well as Draw.
+//
+// DrawCached is concurrent-safe.
+func DrawCached(dst *ebiten.Image, s string, face font.Face, x, y int, clr color.Color) {
+	textM.Lock()
+	img := cacheString(face, s)
+	colorm := colorToColorM(clr)
+	ascent := face.Metrics().Ascent.Ceil()
+	textM.Unlock()
+
+	if img == nil {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x), float64(y-ascent))
+	op.ColorM = colorm
+	_ = dst.DrawImage(img, op)
+}
+
+// InvalidateCache disposes all the images of strings rendered with the face by CacheString.
+// If face is nil, the images for all the faces are disposed.
+//
+// InvalidateCache is useful when the face's state is changed or the face is no longer used.
+//
+// InvalidateCache is concurrent-safe.
+func InvalidateCache(face font.Face) {
+	textM.Lock()
+	defer textM.Unlock()
+	invalidateStringCache(face)
+}
+
+func invalidateStringCache(face font.Face) {
+	for f, m := range stringCache {
+		if face != nil && f != face {
+			continue
+		}
+		for _, e := range m {
+			if e.image != nil {
+				_ = e.image.Dispose()
+			}
+		}
+		delete(stringCache, f)
+	}
+}
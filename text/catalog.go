@@ -0,0 +1,361 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PluralForm represents a plural category of CLDR.
+type PluralForm int
+
+const (
+	PluralOther PluralForm = iota
+	PluralZero
+	PluralOne
+	PluralTwo
+	PluralFew
+	PluralMany
+)
+
+// PluralRule returns the plural form for the number n.
+type PluralRule func(n int) PluralForm
+
+func pluralRuleOther(n int) PluralForm {
+	return PluralOther
+}
+
+func pluralRuleEnglish(n int) PluralForm {
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralRuleFrench(n int) PluralForm {
+	if n == 0 || n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralRuleRussian(n int) PluralForm {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	case 2 <= n%10 && n%10 <= 4 && (n%100 < 12 || 14 < n%100):
+		return PluralFew
+	}
+	return PluralMany
+}
+
+func pluralRulePolish(n int) PluralForm {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n == 1:
+		return PluralOne
+	case 2 <= n%10 && n%10 <= 4 && (n%100 < 12 || 14 < n%100):
+		return PluralFew
+	}
+	return PluralMany
+}
+
+func pluralRuleArabic(n int) PluralForm {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n == 0:
+		return PluralZero
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case 3 <= n%100 && n%100 <= 10:
+		return PluralFew
+	case 11 <= n%100:
+		return PluralMany
+	}
+	return PluralOther
+}
+
+var pluralRules = map[string]PluralRule{
+	"ar": pluralRuleArabic,
+	"de": pluralRuleEnglish,
+	"en": pluralRuleEnglish,
+	"es": pluralRuleEnglish,
+	"fr": pluralRuleFrench,
+	"it": pluralRuleEnglish,
+	"ja": pluralRuleOther,
+	"ko": pluralRuleOther,
+	"pl": pluralRulePolish,
+	"pt": pluralRuleEnglish,
+	"ru": pluralRuleRussian,
+	"uk": pluralRuleRussian,
+	"zh": pluralRuleOther,
+}
+
+// baseLanguage returns the language part of the given BCP 47 tag, e.g., "en" for "en-US".
+func baseLanguage(lang string) string {
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return strings.ToLower(lang)
+}
+
+// Catalog is a lightweight message catalog for localization.
+//
+// A Catalog is concurrent-safe.
+type Catalog struct {
+	lang     string
+	fallback string
+
+	messages       map[string]map[string]string
+	pluralMessages map[string]map[string]map[PluralForm]string
+	pluralRules    map[string]PluralRule
+
+	numberFormatter func(lang string, v float64) string
+	dateFormatter   func(lang string, t time.Time) string
+
+	m sync.RWMutex
+}
+
+// NewCatalog creates a new Catalog. fallback is the language used when a message is not found in the current
+// language. The current language is fallback initially.
+func NewCatalog(fallback string) *Catalog {
+	return &Catalog{
+		lang:           fallback,
+		fallback:       fallback,
+		messages:       map[string]map[string]string{},
+		pluralMessages: map[string]map[string]map[PluralForm]string{},
+		pluralRules:    map[string]PluralRule{},
+	}
+}
+
+// Language returns the current language.
+func (c *Catalog) Language() string {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.lang
+}
+
+// SetLanguage sets the current language as a BCP 47 tag like "en-US" or "ja".
+func (c *Catalog) SetLanguage(lang string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.lang = lang
+}
+
+// SetMessage sets the message for the key in the language.
+func (c *Catalog) SetMessage(lang string, key string, message string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.messages[lang]; !ok {
+		c.messages[lang] = map[string]string{}
+	}
+	c.messages[lang][key] = message
+}
+
+// SetPluralMessage sets the messages for each plural form for the key in the language.
+//
+// forms must have at least PluralOther.
+func (c *Catalog) SetPluralMessage(lang string, key string, forms map[PluralForm]string) {
+	if _, ok := forms[PluralOther]; !ok {
+		panic("text: forms must have PluralOther")
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.pluralMessages[lang]; !ok {
+		c.pluralMessages[lang] = map[string]map[PluralForm]string{}
+	}
+	fs := map[PluralForm]string{}
+	for f, m := range forms {
+		fs[f] = m
+	}
+	c.pluralMessages[lang][key] = fs
+}
+
+// SetPluralRule sets the plural rule for the language.
+//
+// Rules for some major languages are built in.
+func (c *Catalog) SetPluralRule(lang string, rule PluralRule) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.pluralRules[lang] = rule
+}
+
+// SetNumberFormatter sets the function to format numbers. If f is nil, the default formatter is used.
+func (c *Catalog) SetNumberFormatter(f func(lang string, v float64) string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.numberFormatter = f
+}
+
+// SetDateFormatter sets the function to format dates. If f is nil, the default formatter is used.
+func (c *Catalog) SetDateFormatter(f func(lang string, t time.Time) string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.dateFormatter = f
+}
+
+// languages returns the candidate languages to look up in order.
+func (c *Catalog) languages() []string {
+	return []string{c.lang, baseLanguage(c.lang), c.fallback, baseLanguage(c.fallback)}
+}
+
+// Message returns the message for the key in the current language.
+//
+// If the message is not found, Message returns the message in the fallback language.
+// If the message is not found in the fallback language either, Message returns the key.
+func (c *Catalog) Message(key string) string {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	for _, l := range c.languages() {
+		if m, ok := c.messages[l][key]; ok {
+			return m
+		}
+	}
+	return key
+}
+
+// Messagef formats the message for the key with the arguments by fmt.Sprintf.
+func (c *Catalog) Messagef(key string, args ...interface{}) string {
+	return fmt.Sprintf(c.Message(key), args...)
+}
+
+func (c *Catalog) pluralRule(lang string) PluralRule {
+	if r, ok := c.pluralRules[lang]; ok {
+		return r
+	}
+	if r, ok := c.pluralRules[baseLanguage(lang)]; ok {
+		return r
+	}
+	if r, ok := pluralRules[baseLanguage(lang)]; ok {
+		return r
+	}
+	return pluralRuleOther
+}
+
+// PluralMessage returns the message for the key and the number n in the current language.
+//
+// If the message for the plural form of n is not found, the message for PluralOther is used.
+// If the message is not found, PluralMessage returns the key.
+func (c *Catalog) PluralMessage(key string, n int) string {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	for _, l := range c.languages() {
+		fs, ok := c.pluralMessages[l][key]
+		if !ok {
+			continue
+		}
+		if m, ok := fs[c.pluralRule(l)(n)]; ok {
+			return m
+		}
+		return fs[PluralOther]
+	}
+	return key
+}
+
+// PluralMessagef formats the message for the key and the number n with the arguments by fmt.Sprintf.
+func (c *Catalog) PluralMessagef(key string, n int, args ...interface{}) string {
+	return fmt.Sprintf(c.PluralMessage(key, n), args...)
+}
+
+// numberSeparators returns the decimal separator and the group separator for the language.
+func numberSeparators(lang string) (string, string) {
+	switch baseLanguage(lang) {
+	case "de", "es", "it", "pt", "tr", "id":
+		return ",", "."
+	case "fr", "ru", "uk", "pl", "cs", "sv", "fi", "nb":
+		return ",", "\u00a0"
+	}
+	return ".", ","
+}
+
+func defaultFormatNumber(lang string, v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	dec, group := numberSeparators(lang)
+	s := strconv.FormatFloat(math.Abs(v), 'f', -1, 64)
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	var b strings.Builder
+	if v < 0 {
+		b.WriteByte('-')
+	}
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(group)
+		}
+		b.WriteRune(r)
+	}
+	if fracPart != "" {
+		b.WriteString(dec)
+		b.WriteString(fracPart)
+	}
+	return b.String()
+}
+
+func defaultFormatDate(lang string, t time.Time) string {
+	switch baseLanguage(lang) {
+	case "ja", "zh", "ko", "hu":
+		return t.Format("2006/01/02")
+	case "en":
+		if strings.EqualFold(lang, "en-US") {
+			return t.Format("01/02/2006")
+		}
+	}
+	return t.Format("02/01/2006")
+}
+
+// FormatNumber formats the number v in the current language.
+//
+// By default, FormatNumber uses the decimal and grouping separators of the language, e.g., "1,234.5" in English and
+// "1.234,5" in German. Use SetNumberFormatter to customize this.
+func (c *Catalog) FormatNumber(v float64) string {
+	c.m.RLock()
+	f, lang := c.numberFormatter, c.lang
+	c.m.RUnlock()
+	if f != nil {
+		return f(lang, v)
+	}
+	return defaultFormatNumber(lang, v)
+}
+
+// FormatDate formats the date of t in the current language.
+//
+// By default, FormatDate uses a numeric format of the language. Use SetDateFormatter to customize this.
+func (c *Catalog) FormatDate(t time.Time) string {
+	c.m.RLock()
+	f, lang := c.dateFormatter, c.lang
+	c.m.RUnlock()
+	if f != nil {
+		return f(lang, t)
+	}
+	return defaultFormatDate(lang, t)
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Glyph represents a laid-out glyph in Layout.
+type Glyph struct {
+	// Rune is the rune of the glyph.
+	Rune rune
+
+	// Index is the index of the rune in the text in bytes.
+	Index int
+
+	// Line is the line number of the glyph, starting from 0.
+	Line int
+
+	// Dot is the 'dot' (period) position of the glyph relative to the origin of the layout.
+	Dot image.Point
+
+	// Bounds is the region of Image relative to the origin of the layout.
+	Bounds image.Rectangle
+
+	// Image is the image of the glyph. Image is nil for a glyph without visible pixels like a space.
+	//
+	// The image is white and shared with other glyphs. Use ColorM to render it with colors, and don't modify it.
+	Image *ebiten.Image
+}
+
+// Layout is a laid-out text for glyph-level operations like typewriter, wave and shake effects.
+//
+// The origin of the layout is the 'dot' (period) position of the first line. The lines are separated by '\n' and
+// the line height is the face's Metrics().Height.
+//
+// Layout keeps glyph images even after they are evicted from the cache, so the layout can be reused every frame
+// without laying out again.
+type Layout struct {
+	glyphs []Glyph
+}
+
+// NewLayout lays out the text with the face.
+//
+// NewLayout is concurrent-safe.
+func NewLayout(text string, face font.Face) *Layout {
+	textM.Lock()
+	defer textM.Unlock()
+	return layoutText(text, face)
+}
+
+func layoutText(text string, face font.Face) *Layout {
+	l := &Layout{}
+	lineHeight := face.Metrics().Height.Ceil()
+	offset := 0
+	for line, str := range strings.Split(text, "\n") {
+		y := line * lineHeight
+		var keys []glyphKey
+		var xs []int
+		var indices []int
+		var runes []rune
+
+		var dot fixed.Int26_6
+		prevR := rune(-1)
+		for i, r := range str {
+			if prevR >= 0 {
+				dot += hintedKern(face, prevR, r)
+			}
+			k, x := glyphPosition(face, r, dot)
+			keys = append(keys, k)
+			xs = append(xs, x)
+			indices = append(indices, offset+i)
+			runes = append(runes, r)
+			dot += hintedAdvance(face, r)
+			prevR = r
+		}
+
+		for i, img := range getGlyphImages(face, keys) {
+			g := Glyph{
+				Rune:  runes[i],
+				Index: indices[i],
+				Line:  line,
+				Dot:   image.Pt(xs[i], y),
+			}
+			if img != nil {
+				g.Bounds = image.Rect(0, 0, img.width, img.height).Add(image.Pt(xs[i]+img.originX, y+img.originY))
+				g.Image = img.image.SubImage(img.bounds()).(*ebiten.Image)
+			}
+			l.glyphs = append(l.glyphs, g)
+		}
+		offset += len(str) + 1
+	}
+	return l
+}
+
+// Glyphs returns the laid-out glyphs in the order of the text. Newlines are not included.
+//
+// The returned slice must not be modified.
+func (l *Layout) Glyphs() []Glyph {
+	return l.glyphs
+}
+
+// Bounds returns the union of the bounds of all the glyphs relative to the origin.
+func (l *Layout) Bounds() image.Rectangle {
+	var r image.Rectangle
+	for _, g := range l.glyphs {
+		r = r.Union(g.Bounds)
+	}
+	return r
+}
+
+// Draw draws the first n glyphs of the layout on dst at the origin (x, y) with the color clr.
+// If n is negative or more than the number of the glyphs, all the glyphs are drawn.
+//
+// Draw with an increasing n is useful for a typewriter effect. For per-glyph effects like a wave, iterate Glyphs and
+// draw each Image with your own options instead.
+func (l *Layout) Draw(dst *ebiten.Image, x, y int, n int, clr color.Color) {
+	if n < 0 || n > len(l.glyphs) {
+		n = len(l.glyphs)
+	}
+
+	textM.Lock()
+	colorm := colorToColorM(clr)
+	textM.Unlock()
+
+	for _, g := range l.glyphs[:n] {
+		if g.Image == nil {
+			continue
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(x+g.Bounds.Min.X), float64(y+g.Bounds.Min.Y))
+		op.ColorM = colorm
+		_ = dst.DrawImage(g.Image, op)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// subpixelLevels is the number of horizontal subpixel positions per pixel when subpixel positioning is enabled.
+const subpixelLevels = 4
+
+// FaceOptions represents options to render glyphs of a font face.
+type FaceOptions struct {
+	// Hinting is the hinting mode of glyph positions.
+	//
+	// With font.HintingFull, the advances and the kernings are rounded to integers so that glyphs are placed on
+	// pixel boundaries. This is useful for small UI text on low-DPI displays.
+	// With font.HintingNone or font.HintingVertical, the fractional advances are kept.
+	// The baselines are always aligned to pixels in any mode.
+	//
+	// Note that the hinting of the glyph outlines depends on the face, e.g., the Hinting option of
+	// github.com/golang/freetype/truetype.
+	//
+	// The default (zero) value is font.HintingNone.
+	Hinting font.Hinting
+
+	// SubpixelPositioning represents whether glyphs are rendered at horizontal subpixel positions.
+	//
+	// If SubpixelPositioning is true, each glyph is rasterized at the quarter pixel nearest to the actual position,
+	// which makes the spacing even with fractional advances. This takes up to 4 times as much cache for glyphs.
+	// SubpixelPositioning is ignored with font.HintingFull.
+	SubpixelPositioning bool
+}
+
+var faceOptions = map[font.Face]FaceOptions{}
+
+// SetFaceOptions sets the options to render glyphs of the face. If options is nil, the default options are used.
+//
+// The strings of the face cached by CacheString are invalidated.
+//
+// SetFaceOptions is concurrent-safe.
+func SetFaceOptions(face font.Face, options *FaceOptions) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	invalidateStringCache(face)
+
+	if options == nil {
+		delete(faceOptions, face)
+		return
+	}
+	faceOptions[face] = *options
+}
+
+func isHintingFull(face font.Face) bool {
+	return faceOptions[face].Hinting == font.HintingFull
+}
+
+func subpixelLevelsOf(face font.Face) int {
+	o := faceOptions[face]
+	if !o.SubpixelPositioning || o.Hinting == font.HintingFull {
+		return 1
+	}
+	return subpixelLevels
+}
+
+// hintedAdvance returns the advance of r considering the hinting of the face.
+func hintedAdvance(face font.Face, r rune) fixed.Int26_6 {
+	a := glyphAdvance(face, r)
+	if isHintingFull(face) {
+		return fixed.I(a.Round())
+	}
+	return a
+}
+
+// hintedKern returns the kerning between r0 and r1 considering the hinting of the face.
+func hintedKern(face font.Face, r0, r1 rune) fixed.Int26_6 {
+	k := face.Kern(r0, r1)
+	if isHintingFull(face) {
+		return fixed.I(k.Round())
+	}
+	return k
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image/color"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Span represents a part of rich text with its own style.
+type Span struct {
+	// Text is the text of the span.
+	Text string
+
+	// Face is the font face of the span. If Face is nil, the default face is used.
+	Face font.Face
+
+	// Color is the color of the span. If Color is nil, the default color is used.
+	Color color.Color
+
+	// Scale is the scale of the span. The default (zero) value is 1.
+	// Note that scaled glyphs might be blurry or jaggy. Use a face of a different size for better quality.
+	Scale float64
+
+	// Image is an inline image like an item icon or a button glyph. If Image is not nil, Text is ignored.
+	//
+	// The image is centered vertically in the line of the face, and its advance is the scaled width. Color is not
+	// applied to the image.
+	Image *ebiten.Image
+}
+
+// richItem is a laid-out glyph or image.
+type richItem struct {
+	glyph *glyphImage
+	image *ebiten.Image
+	x     float64
+	y     float64
+	scale float64
+	color ebiten.ColorM
+}
+
+func (s *Span) scale() float64 {
+	if s.Scale == 0 {
+		return 1
+	}
+	return s.Scale
+}
+
+// layoutSpans lays out the spans and returns the items to draw.
+func layoutSpans(spans []Span, face font.Face, x, y int, clr color.Color) []richItem {
+	var items []richItem
+	dot := fixed.I(x)
+	var prevFace font.Face
+	prevR := rune(-1)
+
+	for _, s := range spans {
+		f := s.Face
+		if f == nil {
+			f = face
+		}
+		c := s.Color
+		if c == nil {
+			c = clr
+		}
+		scale := s.scale()
+
+		if s.Image != nil {
+			w, h := s.Image.Size()
+			m := f.Metrics()
+			sh := float64(h) * scale
+			// Center the image between the ascent and the descent.
+			center := float64(y) + fixed26_6ToFloat64(m.Descent-m.Ascent)/2
+			items = append(items, richItem{
+				image: s.Image,
+				x:     fixed26_6ToFloat64(dot),
+				y:     center - sh/2,
+				scale: scale,
+			})
+			dot += fixed.Int26_6(float64(w) * scale * (1 << 6))
+			prevR = -1
+			continue
+		}
+
+		runes := []rune(s.Text)
+		keys := make([]glyphKey, len(runes))
+		xs := make([]float64, len(runes))
+		for i, r := range runes {
+			if prevR >= 0 && prevFace == f {
+				dot += scaleFixed(hintedKern(f, prevR, r), scale)
+			}
+			if scale == 1 {
+				var ix int
+				keys[i], ix = glyphPosition(f, r, dot)
+				xs[i] = float64(ix)
+			} else {
+				keys[i] = glyphKey{rune: r}
+				xs[i] = fixed26_6ToFloat64(dot)
+			}
+			dot += scaleFixed(hintedAdvance(f, r), scale)
+			prevR = r
+		}
+		prevFace = f
+
+		colorm := colorToColorM(c)
+		for i, img := range getGlyphImages(f, keys) {
+			if img == nil {
+				continue
+			}
+			items = append(items, richItem{
+				glyph: img,
+				x:     xs[i],
+				y:     float64(y),
+				scale: scale,
+				color: colorm,
+			})
+		}
+	}
+	return items
+}
+
+func scaleFixed(x fixed.Int26_6, scale float64) fixed.Int26_6 {
+	if scale == 1 {
+		return x
+	}
+	return fixed.Int26_6(float64(x) * scale)
+}
+
+// DrawSpans draws the given spans of rich text on a given destination image dst.
+//
+// face and clr are the default font face and the default color used for spans without Face or Color.
+// (x, y) represents a 'dot' (period) position of the first span, as well as Draw.
+// All the spans share the same baseline.
+//
+// DrawSpans is concurrent-safe.
+func DrawSpans(dst *ebiten.Image, spans []Span, face font.Face, x, y int, clr color.Color) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	for _, item := range layoutSpans(spans, face, x, y, clr) {
+		if item.image != nil {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Scale(item.scale, item.scale)
+			op.GeoM.Translate(item.x, item.y)
+			_ = dst.DrawImage(item.image, op)
+			continue
+		}
+		drawGlyph(dst, item.glyph, item.x, item.y, item.scale, item.color)
+	}
+}
+
+// MeasureSpans returns the width of the given spans of rich text in pixels.
+//
+// MeasureSpans is concurrent-safe.
+func MeasureSpans(spans []Span, face font.Face) int {
+	textM.Lock()
+	defer textM.Unlock()
+
+	var w fixed.Int26_6
+	var prevFace font.Face
+	prevR := rune(-1)
+	for _, s := range spans {
+		f := s.Face
+		if f == nil {
+			f = face
+		}
+		scale := s.scale()
+		if s.Image != nil {
+			iw, _ := s.Image.Size()
+			w += fixed.Int26_6(float64(iw) * scale * (1 << 6))
+			prevR = -1
+			continue
+		}
+		for _, r := range s.Text {
+			if prevR >= 0 && prevFace == f {
+				w += scaleFixed(hintedKern(f, prevR, r), scale)
+			}
+			w += scaleFixed(hintedAdvance(f, r), scale)
+			prevR = r
+		}
+		prevFace = f
+	}
+	return w.Ceil()
+}
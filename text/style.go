@@ -0,0 +1,160 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Style represents a style of text rendering for DrawWithStyle.
+type Style struct {
+	// Color is the color of the text. If GradientColor is not nil, Color is the color at the top of the line.
+	Color color.Color
+
+	// GradientColor is the color at the bottom of the line for a vertical gradient fill.
+	// If GradientColor is nil, the text is filled with Color.
+	//
+	// The top and the bottom of the line are determined by the face's ascent and descent.
+	GradientColor color.Color
+
+	// OutlineColor is the color of the outline. If OutlineColor is nil or OutlineWidth is 0, no outline is drawn.
+	OutlineColor color.Color
+
+	// OutlineWidth is the width of the outline in pixels.
+	OutlineWidth int
+
+	// ShadowColor is the color of the shadow. If ShadowColor is nil, no shadow is drawn.
+	//
+	// The shadow includes the outline.
+	ShadowColor color.Color
+
+	// ShadowOffsetX and ShadowOffsetY are the offset of the shadow in pixels.
+	ShadowOffsetX int
+	ShadowOffsetY int
+}
+
+// outlineOffsets returns the offsets to draw a glyph multiple times for an outline of the given width.
+func outlineOffsets(width int) []image.Point {
+	var ps []image.Point
+	for j := -width; j <= width; j++ {
+		for i := -width; i <= width; i++ {
+			if i == 0 && j == 0 {
+				continue
+			}
+			// Use a rounded shape.
+			if i*i+j*j > width*width+width {
+				continue
+			}
+			ps = append(ps, image.Pt(i, j))
+		}
+	}
+	return ps
+}
+
+func lerpColor(c0, c1 color.Color, t float64) color.Color {
+	r0, g0, b0, a0 := c0.RGBA()
+	r1, g1, b1, a1 := c1.RGBA()
+	l := func(x0, x1 uint32) uint16 {
+		return uint16(float64(x0)*(1-t) + float64(x1)*t)
+	}
+	return color.RGBA64{l(r0, r1), l(g0, g1), l(b0, b1), l(a0, a1)}
+}
+
+// DrawWithStyle draws a given text on a given destination image dst with the style.
+//
+// (x, y) represents a 'dot' (period) position as well as Draw. Lines are separated by '\n' as well as NewLayout.
+//
+// The outline and the shadow are rendered by drawing the glyphs multiple times, then a wide outline is slower.
+// The gradient is rendered line by line in pixels.
+//
+// DrawWithStyle is concurrent-safe.
+func DrawWithStyle(dst *ebiten.Image, text string, face font.Face, x, y int, style *Style) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	l := layoutText(text, face)
+
+	var outline []image.Point
+	if style.OutlineColor != nil && style.OutlineWidth > 0 {
+		outline = outlineOffsets(style.OutlineWidth)
+	}
+
+	drawShape := func(dx, dy int, clr color.Color) {
+		colorm := colorToColorM(clr)
+		for _, g := range l.glyphs {
+			if g.Image == nil {
+				continue
+			}
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(x+g.Bounds.Min.X+dx), float64(y+g.Bounds.Min.Y+dy))
+			op.ColorM = colorm
+			_ = dst.DrawImage(g.Image, op)
+		}
+	}
+
+	if style.ShadowColor != nil {
+		sx, sy := style.ShadowOffsetX, style.ShadowOffsetY
+		drawShape(sx, sy, style.ShadowColor)
+		for _, p := range outline {
+			drawShape(sx+p.X, sy+p.Y, style.ShadowColor)
+		}
+	}
+	for _, p := range outline {
+		drawShape(p.X, p.Y, style.OutlineColor)
+	}
+
+	clr := style.Color
+	if clr == nil {
+		clr = color.White
+	}
+	if style.GradientColor == nil {
+		drawShape(0, 0, clr)
+		return
+	}
+
+	m := face.Metrics()
+	top := -m.Ascent.Ceil()
+	height := (m.Ascent + m.Descent).Ceil()
+	if height < 2 {
+		height = 2
+	}
+	for _, g := range l.glyphs {
+		if g.Image == nil {
+			continue
+		}
+		b := g.Image.Bounds()
+		for j := 0; j < b.Dy(); j++ {
+			// The row position relative to the top of the line.
+			ly := g.Bounds.Min.Y + j - g.Dot.Y - top
+			t := float64(ly) / float64(height-1)
+			if t < 0 {
+				t = 0
+			}
+			if t > 1 {
+				t = 1
+			}
+			row := g.Image.SubImage(image.Rect(b.Min.X, b.Min.Y+j, b.Max.X, b.Min.Y+j+1)).(*ebiten.Image)
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(x+g.Bounds.Min.X), float64(y+g.Bounds.Min.Y+j))
+			op.ColorM = colorToColorM(lerpColor(clr, style.GradientColor, t))
+			_ = dst.DrawImage(row, op)
+		}
+	}
+}
@@ -53,16 +53,19 @@ type colorMCacheEntry struct {
 	atime int64
 }
 
-func drawGlyph(dst *ebiten.Image, face font.Face, r rune, img *glyphImage, x, y fixed.Int26_6, clr ebiten.ColorM) {
+func drawGlyph(dst *ebiten.Image, img *glyphImage, x, y float64, scale float64, clr ebiten.ColorM) {
 	if img == nil {
 		return
 	}
 
-	b := getGlyphBounds(face, r)
 	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(fixed26_6ToFloat64(x+b.Min.X), fixed26_6ToFloat64(y+b.Min.Y))
+	op.GeoM.Translate(float64(img.originX), float64(img.originY))
+	if scale != 1 {
+		op.GeoM.Scale(scale, scale)
+	}
+	op.GeoM.Translate(x, y)
 	op.ColorM = clr
-	_ = dst.DrawImage(img.image.SubImage(image.Rect(img.x, img.y, img.x+img.width, img.y+img.height)).(*ebiten.Image), op)
+	_ = dst.DrawImage(img.image.SubImage(img.bounds()).(*ebiten.Image), op)
 }
 
 var (
@@ -82,12 +85,50 @@ func getGlyphBounds(face font.Face, r rune) *fixed.Rectangle26_6 {
 	return &b
 }
 
+// glyphKey represents a glyph rasterized at a horizontal subpixel position.
+type glyphKey struct {
+	rune rune
+
+	// subpixel is the horizontal subpixel position in [0, subpixelLevels).
+	subpixel int
+}
+
+// glyphPosition returns the glyph key and the integer position to render the rune r at the dot position x.
+func glyphPosition(face font.Face, r rune, x fixed.Int26_6) (glyphKey, int) {
+	n := subpixelLevelsOf(face)
+	ix := x.Floor()
+	s := (int(x-fixed.I(ix))*n + (1 << 5)) >> 6
+	if s == n {
+		ix++
+		s = 0
+	}
+	return glyphKey{rune: r, subpixel: s}, ix
+}
+
+// glyphRect returns the region of the glyph image relative to the dot position, and the subpixel offset to rasterize
+// the glyph.
+//
+// The region is aligned to pixels so that the baseline is always on a pixel boundary.
+func glyphRect(face font.Face, key glyphKey) (image.Rectangle, fixed.Int26_6) {
+	b := getGlyphBounds(face, key.rune)
+	offset := fixed.Int26_6(key.subpixel * (1 << 6) / subpixelLevels)
+	return image.Rect(b.Min.X.Floor(), b.Min.Y.Floor(), (b.Max.X + offset).Ceil(), b.Max.Y.Ceil()), offset
+}
+
 type glyphImage struct {
 	image  *ebiten.Image
 	x      int
 	y      int
 	width  int
 	height int
+
+	// originX and originY are the position of the glyph image relative to the dot position.
+	originX int
+	originY int
+}
+
+func (g *glyphImage) bounds() image.Rectangle {
+	return image.Rect(g.x, g.y, g.x+g.width, g.y+g.height)
 }
 
 type glyphImageCacheEntry struct {
@@ -96,100 +137,98 @@ type glyphImageCacheEntry struct {
 }
 
 var (
-	glyphImageCache = map[font.Face]map[rune]*glyphImageCacheEntry{}
+	glyphImageCache = map[font.Face]map[glyphKey]*glyphImageCacheEntry{}
 	emptyGlyphs     = map[font.Face]map[rune]struct{}{}
 )
 
-func getGlyphImages(face font.Face, runes []rune) []*glyphImage {
+func getGlyphImages(face font.Face, keys []glyphKey) []*glyphImage {
 	if _, ok := emptyGlyphs[face]; !ok {
 		emptyGlyphs[face] = map[rune]struct{}{}
 	}
 	if _, ok := glyphImageCache[face]; !ok {
-		glyphImageCache[face] = map[rune]*glyphImageCacheEntry{}
+		glyphImageCache[face] = map[glyphKey]*glyphImageCacheEntry{}
 	}
 
-	imgs := make([]*glyphImage, len(runes))
-	glyphBounds := map[rune]*fixed.Rectangle26_6{}
-	neededGlyphIndices := map[int]rune{}
-	for i, r := range runes {
-		if _, ok := emptyGlyphs[face][r]; ok {
+	imgs := make([]*glyphImage, len(keys))
+	glyphRects := map[glyphKey]image.Rectangle{}
+	neededGlyphIndices := map[int]glyphKey{}
+	for i, k := range keys {
+		if _, ok := emptyGlyphs[face][k.rune]; ok {
 			continue
 		}
 
-		if e, ok := glyphImageCache[face][r]; ok {
+		if e, ok := glyphImageCache[face][k]; ok {
 			e.atime = now()
 			imgs[i] = e.image
 			continue
 		}
 
-		b := getGlyphBounds(face, r)
-		w, h := (b.Max.X - b.Min.X).Ceil(), (b.Max.Y - b.Min.Y).Ceil()
-		if w == 0 || h == 0 {
-			emptyGlyphs[face][r] = struct{}{}
+		b := getGlyphBounds(face, k.rune)
+		if b.Max.X == b.Min.X || b.Max.Y == b.Min.Y {
+			emptyGlyphs[face][k.rune] = struct{}{}
 			continue
 		}
 
-		// TODO: What if len(runes) > cacheLimit?
+		// TODO: What if len(keys) > cacheLimit?
 		if len(glyphImageCache[face]) > cacheLimit {
 			oldest := int64(math.MaxInt64)
-			oldestKey := rune(-1)
-			for r, e := range glyphImageCache[face] {
+			oldestKey := glyphKey{rune: -1}
+			for k, e := range glyphImageCache[face] {
 				if e.atime < oldest {
-					oldestKey = r
+					oldestKey = k
 					oldest = e.atime
 				}
 			}
 			delete(glyphImageCache[face], oldestKey)
 		}
 
-		glyphBounds[r] = b
-		neededGlyphIndices[i] = r
+		r, _ := glyphRect(face, k)
+		glyphRects[k] = r
+		neededGlyphIndices[i] = k
 	}
 
 	if len(neededGlyphIndices) > 0 {
 		// TODO: What if w2 is too big (e.g. > 4096)?
 		w2 := 0
 		h2 := 0
-		for _, b := range glyphBounds {
-			w, h := (b.Max.X - b.Min.X).Ceil(), (b.Max.Y - b.Min.Y).Ceil()
-			w2 += w
-			if h2 < h {
-				h2 = h
+		for _, r := range glyphRects {
+			w2 += r.Dx()
+			if h2 < r.Dy() {
+				h2 = r.Dy()
 			}
 		}
 		rgba := image.NewRGBA(image.Rect(0, 0, w2, h2))
 
 		x := 0
-		xs := map[rune]int{}
-		for r, b := range glyphBounds {
-			w := (b.Max.X - b.Min.X).Ceil()
-
+		xs := map[glyphKey]int{}
+		for k, r := range glyphRects {
+			_, offset := glyphRect(face, k)
 			d := font.Drawer{
 				Dst:  rgba,
 				Src:  image.White,
 				Face: face,
 			}
-			d.Dot = fixed.Point26_6{X: fixed.I(x) - b.Min.X, Y: -b.Min.Y}
-			d.DrawString(string(r))
-			xs[r] = x
+			d.Dot = fixed.Point26_6{X: fixed.I(x-r.Min.X) + offset, Y: fixed.I(-r.Min.Y)}
+			d.DrawString(string(k.rune))
+			xs[k] = x
 
-			x += w
+			x += r.Dx()
 		}
 
 		img, _ := ebiten.NewImageFromImage(rgba, ebiten.FilterDefault)
-		for i, r := range neededGlyphIndices {
-			b := glyphBounds[r]
-			w, h := (b.Max.X - b.Min.X).Ceil(), (b.Max.Y - b.Min.Y).Ceil()
-
+		for i, k := range neededGlyphIndices {
+			r := glyphRects[k]
 			g := &glyphImage{
-				image:  img,
-				x:      xs[r],
-				y:      0,
-				width:  w,
-				height: h,
+				image:   img,
+				x:       xs[k],
+				y:       0,
+				width:   r.Dx(),
+				height:  r.Dy(),
+				originX: r.Min.X,
+				originY: r.Min.Y,
 			}
-			if _, ok := glyphImageCache[face][r]; !ok {
-				glyphImageCache[face][r] = &glyphImageCacheEntry{
+			if _, ok := glyphImageCache[face][k]; !ok {
+				glyphImageCache[face][k] = &glyphImageCacheEntry{
 					image: g,
 					atime: now(),
 				}
@@ -267,6 +306,8 @@ func colorToColorM(clr color.Color) ebiten.ColorM {
 // Be careful that the passed font face is held by this package and is never released.
 // This is a known issue (#498).
 //
+// Use SetFaceOptions to adjust the hinting and the subpixel positioning.
+//
 // Draw is concurrent-safe.
 func Draw(dst *ebiten.Image, text string, face font.Face, x, y int, clr color.Color) {
 	textM.Lock()
@@ -275,18 +316,23 @@ func Draw(dst *ebiten.Image, text string, face font.Face, x, y int, clr color.Co
 	prevR := rune(-1)
 
 	runes := []rune(text)
-	glyphImgs := getGlyphImages(face, runes)
-	colorm := colorToColorM(clr)
-
+	keys := make([]glyphKey, len(runes))
+	xs := make([]int, len(runes))
 	for i, r := range runes {
 		if prevR >= 0 {
-			fx += face.Kern(prevR, r)
+			fx += hintedKern(face, prevR, r)
 		}
-		drawGlyph(dst, face, r, glyphImgs[i], fx, fixed.I(y), colorm)
-		fx += glyphAdvance(face, r)
+		keys[i], xs[i] = glyphPosition(face, r, fx)
+		fx += hintedAdvance(face, r)
 
 		prevR = r
 	}
 
+	glyphImgs := getGlyphImages(face, keys)
+	colorm := colorToColorM(clr)
+	for i := range runes {
+		drawGlyph(dst, glyphImgs[i], float64(xs[i]), float64(y), 1, colorm)
+	}
+
 	textM.Unlock()
 }
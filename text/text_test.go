@@ -16,11 +16,15 @@ package text_test
 
 import (
 	"errors"
+	"image"
 	"image/color"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/hajimehoshi/bitmapfont"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
 
 	"github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/internal/testflock"
@@ -68,3 +72,305 @@ func TestTextColor(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestWrap(t *testing.T) {
+	cases := []struct {
+		Text  string
+		Width int
+		Want  []string
+	}{
+		{
+			Text:  "Hello world foo",
+			Width: 60,
+			Want:  []string{"Hello", "world foo"},
+		},
+		{
+			Text:  "Hello\nworld",
+			Width: 60,
+			Want:  []string{"Hello", "world"},
+		},
+		{
+			Text:  "abcdefghijkl",
+			Width: 30,
+			Want:  []string{"abcde", "fghij", "kl"},
+		},
+		{
+			// A line must not start with "。".
+			Text:  "あいうえお。",
+			Width: 60,
+			Want:  []string{"あいうえ", "お。"},
+		},
+		{
+			// A line must not end with "「".
+			Text:  "あいう「えお」",
+			Width: 48,
+			Want:  []string{"あいう", "「えお」"},
+		},
+	}
+	for _, c := range cases {
+		got := Wrap(c.Text, bitmapfont.Gothic12r, c.Width)
+		if !reflect.DeepEqual(got, c.Want) {
+			t.Errorf("Wrap(%q, %d): got: %q, want: %q", c.Text, c.Width, got, c.Want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		Text  string
+		Width int
+		Want  string
+	}{
+		{
+			Text:  "Hello world",
+			Width: 66,
+			Want:  "Hello world",
+		},
+		{
+			Text:  "Hello world",
+			Width: 48,
+			Want:  "Hello...",
+		},
+		{
+			Text:  "Hello world",
+			Width: 54,
+			Want:  "Hello...",
+		},
+		{
+			Text:  "あいうえお",
+			Width: 42,
+			Want:  "あい...",
+		},
+	}
+	for _, c := range cases {
+		got := Truncate(c.Text, bitmapfont.Gothic12r, c.Width, "...")
+		if got != c.Want {
+			t.Errorf("Truncate(%q, %d): got: %q, want: %q", c.Text, c.Width, got, c.Want)
+		}
+	}
+}
+
+func TestCatalogPluralMessage(t *testing.T) {
+	c := NewCatalog("en")
+	c.SetPluralMessage("en", "apples", map[PluralForm]string{
+		PluralOne:   "%d apple",
+		PluralOther: "%d apples",
+	})
+	c.SetPluralMessage("ru", "apples", map[PluralForm]string{
+		PluralOne:  "%d яблоко",
+		PluralFew:  "%d яблока",
+		PluralMany: "%d яблок",
+		// PluralOther is required.
+		PluralOther: "%d яблока",
+	})
+
+	cases := []struct {
+		Lang string
+		N    int
+		Want string
+	}{
+		{"en", 1, "1 apple"},
+		{"en", 2, "2 apples"},
+		{"en-US", 0, "0 apples"},
+		{"ru", 1, "1 яблоко"},
+		{"ru", 3, "3 яблока"},
+		{"ru", 5, "5 яблок"},
+		{"ru", 11, "11 яблок"},
+		{"ru", 21, "21 яблоко"},
+		// Fallback to English.
+		{"ja", 2, "2 apples"},
+	}
+	for _, tc := range cases {
+		c.SetLanguage(tc.Lang)
+		if got := c.PluralMessagef("apples", tc.N, tc.N); got != tc.Want {
+			t.Errorf("PluralMessagef (%s, %d): got: %q, want: %q", tc.Lang, tc.N, got, tc.Want)
+		}
+	}
+}
+
+func TestCatalogFormatNumber(t *testing.T) {
+	cases := []struct {
+		Lang  string
+		Value float64
+		Want  string
+	}{
+		{"en", 1234567.5, "1,234,567.5"},
+		{"en", -1234, "-1,234"},
+		{"en", 123, "123"},
+		{"de", 1234567.5, "1.234.567,5"},
+		{"fr", 1234, "1\u00a0234"},
+	}
+	c := NewCatalog("en")
+	for _, tc := range cases {
+		c.SetLanguage(tc.Lang)
+		if got := c.FormatNumber(tc.Value); got != tc.Want {
+			t.Errorf("FormatNumber (%s, %v): got: %q, want: %q", tc.Lang, tc.Value, got, tc.Want)
+		}
+	}
+}
+
+// fractionalFace is a font.Face with fractional advances.
+type fractionalFace struct{}
+
+func (fractionalFace) Close() error {
+	return nil
+}
+
+func (fractionalFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return image.Rectangle{}, nil, image.Point{}, fixed.I(11) / 2, true
+}
+
+func (fractionalFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return fixed.Rectangle26_6{}, fixed.I(11) / 2, true
+}
+
+func (fractionalFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return fixed.I(11) / 2, true
+}
+
+func (fractionalFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return 0
+}
+
+func (fractionalFace) Metrics() font.Metrics {
+	return font.Metrics{}
+}
+
+func TestFaceOptionsHinting(t *testing.T) {
+	face := fractionalFace{}
+	if got, want := Measure("aaaa", face), 22; got != want {
+		t.Errorf("Measure without hinting: got: %d, want: %d", got, want)
+	}
+
+	SetFaceOptions(face, &FaceOptions{
+		Hinting: font.HintingFull,
+	})
+	defer SetFaceOptions(face, nil)
+	// Each advance 5.5 is rounded to 6.
+	if got, want := Measure("aaaa", face), 24; got != want {
+		t.Errorf("Measure with full hinting: got: %d, want: %d", got, want)
+	}
+}
+
+func TestMeasureSpans(t *testing.T) {
+	icon, _ := ebiten.NewImage(10, 10, ebiten.FilterDefault)
+	spans := []Span{
+		{Text: "Press "},
+		{Image: icon, Scale: 1.5},
+		{Text: "!!", Color: color.RGBA{0xff, 0, 0, 0xff}},
+	}
+	want := Measure("Press ", bitmapfont.Gothic12r) + 15 + Measure("!!", bitmapfont.Gothic12r)
+	if got := MeasureSpans(spans, bitmapfont.Gothic12r); got != want {
+		t.Errorf("MeasureSpans: got: %d, want: %d", got, want)
+	}
+
+	dst, _ := ebiten.NewImage(100, 30, ebiten.FilterDefault)
+	DrawSpans(dst, spans, bitmapfont.Gothic12r, 0, 12, color.White)
+	// The red span must be drawn after the icon.
+	foundRed := false
+	for j := 0; j < 30; j++ {
+		for i := want - Measure("!!", bitmapfont.Gothic12r); i < 100; i++ {
+			if r, g, _, a := dst.At(i, j).RGBA(); a > 0 && r > 0 && g == 0 {
+				foundRed = true
+			}
+		}
+	}
+	if !foundRed {
+		t.Errorf("the colored span must be drawn")
+	}
+}
+
+func TestLayout(t *testing.T) {
+	face := bitmapfont.Gothic12r
+	l := NewLayout("a b\nc", face)
+	gs := l.Glyphs()
+	if got, want := len(gs), 4; got != want {
+		t.Fatalf("len(Glyphs()): got: %d, want: %d", got, want)
+	}
+
+	c := gs[3]
+	if got, want := c.Rune, 'c'; got != want {
+		t.Errorf("Rune: got: %q, want: %q", got, want)
+	}
+	if got, want := c.Index, 4; got != want {
+		t.Errorf("Index: got: %d, want: %d", got, want)
+	}
+	if got, want := c.Line, 1; got != want {
+		t.Errorf("Line: got: %d, want: %d", got, want)
+	}
+	if got, want := c.Dot, image.Pt(0, face.Metrics().Height.Ceil()); got != want {
+		t.Errorf("Dot: got: %v, want: %v", got, want)
+	}
+	if got, want := gs[2].Dot.X, Measure("a ", face); got != want {
+		t.Errorf("Dot.X: got: %d, want: %d", got, want)
+	}
+	if gs[1].Image != nil {
+		t.Errorf("the image of a space must be nil")
+	}
+	if gs[0].Image == nil {
+		t.Errorf("the image of 'a' must not be nil")
+	}
+}
+
+func TestDrawWithStyle(t *testing.T) {
+	dst, _ := ebiten.NewImage(30, 30, ebiten.FilterDefault)
+	DrawWithStyle(dst, "H", bitmapfont.Gothic12r, 8, 16, &Style{
+		Color:        color.White,
+		OutlineColor: color.RGBA{0xff, 0, 0, 0xff},
+		OutlineWidth: 1,
+	})
+
+	var white, red int
+	w, h := dst.Size()
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			switch dst.At(i, j) {
+			case color.RGBA{0xff, 0xff, 0xff, 0xff}:
+				white++
+			case color.RGBA{0xff, 0, 0, 0xff}:
+				red++
+			}
+		}
+	}
+	if white == 0 {
+		t.Errorf("the fill must be drawn")
+	}
+	// The outline surrounds the fill, then there must be more outline pixels than fill pixels for a thin glyph.
+	if red <= white {
+		t.Errorf("the outline must be drawn: red: %d, white: %d", red, white)
+	}
+}
+
+func TestMeasureVertical(t *testing.T) {
+	face := bitmapfont.Gothic12r
+	w, h := MeasureVertical("あいう\nえ", face)
+	if got, want := w, face.Metrics().Height.Ceil()*2; got != want {
+		t.Errorf("width: got: %d, want: %d", got, want)
+	}
+	if got, want := h, Measure("あいう", face); got != want {
+		t.Errorf("height: got: %d, want: %d", got, want)
+	}
+}
+
+func TestCacheString(t *testing.T) {
+	face := bitmapfont.Gothic12r
+	img0 := CacheString(face, "Score")
+	if img0 == nil {
+		t.Fatal("CacheString must return an image")
+	}
+	if got, want := img0.Bounds().Dx(), Measure("Score", face); got != want {
+		t.Errorf("width: got: %d, want: %d", got, want)
+	}
+	if img1 := CacheString(face, "Score"); img1 != img0 {
+		t.Errorf("CacheString must return the cached image")
+	}
+
+	InvalidateCache(face)
+	if img2 := CacheString(face, "Score"); img2 == img0 {
+		t.Errorf("CacheString must return a new image after invalidation")
+	}
+
+	if img := CacheString(face, ""); img != nil {
+		t.Errorf("CacheString must return nil for an empty string")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image/color"
+	"math"
+	"strings"
+
+	"golang.org/x/image/font"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// verticalRotated is the set of wide runes that are rotated 90 degrees clockwise in vertical text.
+const verticalRotated = "ー〜～…‥—―（）［］｛｝〔〕〈〉《》「」『』【】〘〙〖〗＜＞＝－"
+
+// verticalShifted is the set of punctuation that is moved to the upper right in vertical text.
+const verticalShifted = "、。，．"
+
+type verticalOrientation int
+
+const (
+	verticalUpright verticalOrientation = iota
+	verticalUprightShifted
+	verticalSideways
+)
+
+// verticalOrientationOf returns the orientation of r in vertical text.
+//
+// This is a simplified version of Unicode Standard Annex #50: wide runes like CJK ideographs and kana are upright,
+// and other runes like Latin letters are rotated.
+func verticalOrientationOf(r rune) verticalOrientation {
+	switch {
+	case strings.ContainsRune(verticalShifted, r):
+		return verticalUprightShifted
+	case strings.ContainsRune(verticalRotated, r):
+		return verticalSideways
+	case isWideRune(r):
+		return verticalUpright
+	}
+	return verticalSideways
+}
+
+type verticalItem struct {
+	glyph   *glyphImage
+	dotX    float64
+	dotY    float64
+	rotated bool
+}
+
+// layoutVertical lays out the text vertically. (x, y) is the upper right corner.
+// layoutVertical returns the items and the size of the text.
+func layoutVertical(text string, face font.Face, x, y int) ([]verticalItem, int, int) {
+	m := face.Metrics()
+	ascent := fixed26_6ToFloat64(m.Ascent)
+	descent := fixed26_6ToFloat64(m.Descent)
+	columnWidth := fixed26_6ToFloat64(m.Height)
+
+	var items []verticalItem
+	var height float64
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		// The lines progress from right to left.
+		cx := float64(x) - columnWidth*(float64(i)+0.5)
+		cy := float64(y)
+
+		runes := []rune(line)
+		keys := make([]glyphKey, len(runes))
+		for j, r := range runes {
+			keys[j] = glyphKey{rune: r}
+		}
+		imgs := getGlyphImages(face, keys)
+		for j, r := range runes {
+			a := fixed26_6ToFloat64(hintedAdvance(face, r))
+			item := verticalItem{
+				glyph: imgs[j],
+			}
+			switch verticalOrientationOf(r) {
+			case verticalUpright, verticalUprightShifted:
+				// Center the glyph in the square cell.
+				item.dotX = cx - a/2
+				item.dotY = cy + a/2 + (ascent-descent)/2
+				if verticalOrientationOf(r) == verticalUprightShifted {
+					item.dotX += a * 0.6
+					item.dotY -= a * 0.6
+				}
+			case verticalSideways:
+				// The top of the glyph points to the right.
+				item.dotX = cx - (ascent-descent)/2
+				item.dotY = cy
+				item.rotated = true
+			}
+			if item.glyph != nil {
+				items = append(items, item)
+			}
+			cy += a
+		}
+		if h := cy - float64(y); height < h {
+			height = h
+		}
+	}
+	return items, int(math.Ceil(columnWidth * float64(len(lines)))), int(math.Ceil(height))
+}
+
+// DrawVertical draws a given text vertically on a given destination image dst.
+//
+// The text is laid out from top to bottom, and the lines separated by '\n' progress from right to left.
+// (x, y) represents the upper right corner of the text. The width of each line is the face's Metrics().Height.
+//
+// Wide runes like CJK ideographs and kana are upright. Other runes like Latin letters and some punctuation like
+// brackets and 'ー' are rotated 90 degrees clockwise. '、' and '。' are moved to the upper right.
+// The vertical advance of a rune is the same as its horizontal advance.
+//
+// DrawVertical is concurrent-safe.
+func DrawVertical(dst *ebiten.Image, text string, face font.Face, x, y int, clr color.Color) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	items, _, _ := layoutVertical(text, face, x, y)
+	colorm := colorToColorM(clr)
+	for _, item := range items {
+		img := item.glyph
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(img.originX), float64(img.originY))
+		if item.rotated {
+			op.GeoM.Rotate(math.Pi / 2)
+		}
+		op.GeoM.Translate(item.dotX, item.dotY)
+		op.ColorM = colorm
+		_ = dst.DrawImage(img.image.SubImage(img.bounds()).(*ebiten.Image), op)
+	}
+}
+
+// MeasureVertical returns the width and the height of the given text laid out vertically in pixels.
+//
+// MeasureVertical is concurrent-safe.
+func MeasureVertical(text string, face font.Face) (width, height int) {
+	textM.Lock()
+	defer textM.Unlock()
+
+	_, w, h := layoutVertical(text, face, 0, 0)
+	return w, h
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// noLineStart is the set of runes that must not be at the beginning of a line (kinsoku).
+// This includes closing punctuation of Latin, CJK and right-to-left scripts.
+const noLineStart = ",.!?:;)]}%'\"" +
+	"、。，．：；？！）］｝〕〉》」』】〙〗〟’”" +
+	"ゝゞヽヾーァィゥェォッャュョヮヵヶぁぃぅぇぉっゃゅょゎゕゖㇰㇱㇲㇳㇴㇵㇶㇷㇸㇹㇺㇻㇼㇽㇾㇿ々〻‐゠–〜～‼⁇⁈⁉・…‥" +
+	"،؛؟۔"
+
+// noLineEnd is the set of runes that must not be at the end of a line.
+const noLineEnd = "([{" + "（［｛〔〈《「『【〘〖〝‘“"
+
+func isNoLineStart(r rune) bool {
+	return strings.ContainsRune(noLineStart, r)
+}
+
+func isNoLineEnd(r rune) bool {
+	return strings.ContainsRune(noLineEnd, r)
+}
+
+// isWideRune reports whether r belongs to a script that can be broken between any characters, like CJK.
+func isWideRune(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r) ||
+		(0x3000 <= r && r <= 0x303f) || // CJK Symbols and Punctuation
+		(0xff00 <= r && r <= 0xffef) // Halfwidth and Fullwidth Forms
+}
+
+// canBreakBefore reports whether a line can be broken between runes[i-1] and runes[i].
+func canBreakBefore(runes []rune, i int) bool {
+	if i <= 0 || i >= len(runes) {
+		return false
+	}
+	prev, cur := runes[i-1], runes[i]
+	if isNoLineStart(cur) || isNoLineEnd(prev) {
+		return false
+	}
+	if unicode.IsSpace(prev) {
+		return true
+	}
+	return isWideRune(prev) || isWideRune(cur)
+}
+
+func measureRunes(face font.Face, runes []rune) fixed.Int26_6 {
+	var w fixed.Int26_6
+	prevR := rune(-1)
+	for _, r := range runes {
+		if prevR >= 0 {
+			w += hintedKern(face, prevR, r)
+		}
+		w += hintedAdvance(face, r)
+		prevR = r
+	}
+	return w
+}
+
+// Measure returns the width of the given text rendered with the face in pixels.
+//
+// Measure is concurrent-safe.
+func Measure(text string, face font.Face) int {
+	textM.Lock()
+	defer textM.Unlock()
+	return measureRunes(face, []rune(text)).Ceil()
+}
+
+// Truncate truncates the given text so that the width with the ellipsis doesn't exceed width in pixels, and appends
+// the ellipsis. If the text fits in width, Truncate returns the text as it is.
+//
+// Truncate never breaks a rune.
+//
+// Truncate is concurrent-safe.
+func Truncate(text string, face font.Face, width int, ellipsis string) string {
+	textM.Lock()
+	defer textM.Unlock()
+
+	runes := []rune(text)
+	if measureRunes(face, runes).Ceil() <= width {
+		return text
+	}
+	max := fixed.I(width) - measureRunes(face, []rune(ellipsis))
+	n := len(runes)
+	for n > 0 && measureRunes(face, runes[:n]) > max {
+		n--
+	}
+	// Trailing spaces before the ellipsis look odd.
+	for n > 0 && unicode.IsSpace(runes[n-1]) {
+		n--
+	}
+	return string(runes[:n]) + ellipsis
+}
+
+// Wrap breaks the given text into lines so that each line width doesn't exceed width in pixels.
+//
+// Lines are broken at spaces for Latin scripts, and between any characters for CJK scripts.
+// Line breaking rules (kinsoku) are respected, e.g., a line never starts with a closing punctuation like "。" or "،".
+// A word longer than width is broken at the rune boundary. Explicit newlines are kept.
+//
+// Wrap is concurrent-safe.
+func Wrap(text string, face font.Face, width int) []string {
+	textM.Lock()
+	defer textM.Unlock()
+
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		lines = append(lines, wrapLine([]rune(para), face, fixed.I(width))...)
+	}
+	return lines
+}
+
+func wrapLine(runes []rune, face font.Face, width fixed.Int26_6) []string {
+	var lines []string
+	for len(runes) > 0 {
+		if measureRunes(face, runes) <= width {
+			lines = append(lines, string(runes))
+			break
+		}
+
+		// Find the longest prefix that fits.
+		n := 1
+		for n < len(runes) && measureRunes(face, runes[:n+1]) <= width {
+			n++
+		}
+		// Find the last break opportunity in the prefix.
+		brk := -1
+		for i := n; i > 0; i-- {
+			if canBreakBefore(runes, i) {
+				brk = i
+				break
+			}
+		}
+		if brk <= 0 {
+			brk = n
+		}
+
+		lines = append(lines, strings.TrimRightFunc(string(runes[:brk]), unicode.IsSpace))
+		runes = runes[brk:]
+		for len(runes) > 0 && runes[0] == ' ' {
+			runes = runes[1:]
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "")
+	}
+	return lines
+}
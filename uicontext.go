@@ -17,8 +17,10 @@ package ebiten
 import (
 	"fmt"
 	"math"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/internal/buffered"
 	"github.com/hajimehoshi/ebiten/internal/clock"
@@ -26,6 +28,7 @@ import (
 	"github.com/hajimehoshi/ebiten/internal/graphicscommand"
 	"github.com/hajimehoshi/ebiten/internal/hooks"
 	"github.com/hajimehoshi/ebiten/internal/mipmap"
+	"github.com/hajimehoshi/ebiten/internal/rawinput"
 	"github.com/hajimehoshi/ebiten/internal/shareable"
 )
 
@@ -55,10 +58,20 @@ func (d *defaultGame) Layout(outsideWidth, outsideHeight int) (screenWidth, scre
 }
 
 type uiContext struct {
+	// tick is the number of the game's updates so far. tick is accessed atomically.
+	// tick must be the first field for the 64-bit alignment on 32-bit platforms.
+	tick int64
+
 	game      Game
 	offscreen *Image
 	screen    *Image
 
+	// filtered is an intermediate image to apply the color vision filter.
+	filtered *Image
+
+	// lastFrame is a copy of the last composited frame for Screenshot.
+	lastFrame *Image
+
 	// scaleForWindow is the scale of a window. This doesn't represent the scale on fullscreen. This value works
 	// only on desktops.
 	//
@@ -70,6 +83,13 @@ type uiContext struct {
 	outsideWidth       float64
 	outsideHeight      float64
 
+	// warmup receives the result of the warmup function. warmup is nil when there is no warmup in progress.
+	warmup chan error
+
+	// splash is the splash image to present before the game's first update. splash is nil when there is no
+	// splash image to present.
+	splash *splash
+
 	err atomic.Value
 
 	m sync.Mutex
@@ -88,6 +108,29 @@ func (c *uiContext) set(game Game, scaleForWindow float64) {
 	}
 }
 
+// startWarmup runs f on another goroutine. The game is not updated until f finishes.
+func (c *uiContext) startWarmup(f func() error) {
+	ch := make(chan error, 1)
+	c.warmup = ch
+	go func() {
+		ch <- f()
+	}()
+}
+
+// waitingForWarmup reports whether the warmup is still in progress.
+func (c *uiContext) waitingForWarmup() (bool, error) {
+	if c.warmup == nil {
+		return false, nil
+	}
+	select {
+	case err := <-c.warmup:
+		c.warmup = nil
+		return false, err
+	default:
+		return true, nil
+	}
+}
+
 func (c *uiContext) setError(err error) {
 	c.err.Store(err)
 }
@@ -159,7 +202,13 @@ func (c *uiContext) Layout(outsideWidth, outsideHeight float64) {
 }
 
 func (c *uiContext) updateOffscreen() {
-	sw, sh := c.game.Layout(int(c.outsideWidth), int(c.outsideHeight))
+	// The power profile can lower the resolution of a game whose Layout returns the outside size.
+	ow, oh := int(c.outsideWidth), int(c.outsideHeight)
+	if s := thePowerProfiles.resolutionScale(); s < 1 {
+		ow = int(math.Max(1, c.outsideWidth*s))
+		oh = int(math.Max(1, c.outsideHeight*s))
+	}
+	sw, sh := c.game.Layout(ow, oh)
 	if sw <= 0 || sh <= 0 {
 		panic("ebiten: Layout must return positive numbers")
 	}
@@ -182,8 +231,17 @@ func (c *uiContext) updateOffscreen() {
 			c.offscreen = nil
 		}
 	}
+	if c.filtered != nil {
+		_ = c.filtered.Dispose()
+		c.filtered = nil
+	}
 	if c.offscreen == nil {
-		c.offscreen = newImage(sw, sh, FilterDefault, true)
+		if screenPixelFormat() == PixelFormatRGBA16F {
+			// If HDR images are not available, the graphics driver creates a regular image instead.
+			c.offscreen = newHDRImage(sw, sh, true)
+		} else {
+			c.offscreen = newImage(sw, sh, FilterDefault, true)
+		}
 	}
 
 	// The window size is automatically adjusted when Run is used.
@@ -243,37 +301,86 @@ func (c *uiContext) Update(afterFrameUpdate func()) error {
 	if err, ok := c.err.Load().(error); ok && err != nil {
 		return err
 	}
+	waiting, err := c.waitingForWarmup()
+	if err != nil {
+		return err
+	}
+	if c.needsSplash(waiting) {
+		return c.drawSplash(waiting)
+	}
+	if waiting {
+		return nil
+	}
+	thePowerProfiles.update()
+	c.updateCursorConfinement()
+
+	theFrameGraph.begin()
 	if err := buffered.BeginFrame(); err != nil {
 		return err
 	}
 	if err := c.update(afterFrameUpdate); err != nil {
 		return err
 	}
+	start := time.Now()
 	if err := buffered.EndFrame(); err != nil {
 		return err
 	}
+	theFrameGraph.record("flush", []string{"blit"}, 0, 0, start)
+	theFrameGraph.end()
+	theTempImagePool.endFrame()
+	clock.EndFrame()
 
 	return nil
 }
 
 func (c *uiContext) update(afterFrameUpdate func()) error {
-	updateCount := clock.Update(MaxTPS())
+	updateCount, discarded := clock.Update(MaxTPS(), MaxUpdatesPerFrame())
+	if d := clock.Suspended(); d > 0 {
+		runResumedHook(d)
+	}
+	if discarded > 0 {
+		runDiscardedTicksHook(discarded)
+	}
 	for i := 0; i < updateCount; i++ {
 		c.updateOffscreen()
 
+		start := time.Now()
+
 		// Mipmap images should be disposed by Clear.
 		c.offscreen.Clear()
+		theLayers.prepare(c.offscreen.Size())
+		theOverdraw.prepare(c.offscreen)
+		theDrawDebugger.prepare(c.offscreen)
 
 		setDrawingSkipped(i < updateCount-1)
 
+		rawinput.Update()
+		updateInjectedInput()
+		updateSystemTheme()
+		if err := updateGamepadProvider(); err != nil {
+			return err
+		}
+		theTaskScheduler.deliver()
 		if err := hooks.RunBeforeUpdateHooks(); err != nil {
 			return err
 		}
-		if err := c.game.Update(c.offscreen); err != nil {
+		if err := theRewinder.beginTick(c); err != nil {
+			return err
+		}
+		if err := theDeterminismAuditor.beginTick(c); err != nil {
+			return err
+		}
+		if err := c.callGame("Update", func() error {
+			return c.game.Update(c.offscreen)
+		}); err != nil {
 			return err
 		}
+		atomic.AddInt64(&c.tick, 1)
 		uiDriver().Input().ResetForFrame()
 		afterFrameUpdate()
+
+		w, h := c.offscreen.Size()
+		theFrameGraph.record("update", nil, w, h, start)
 	}
 
 	// c.screen might be nil when updateCount is 0 in the initial state (#1039).
@@ -281,21 +388,91 @@ func (c *uiContext) update(afterFrameUpdate func()) error {
 		return nil
 	}
 
+	// When the game has Draw, the game is drawn once every frame regardless of the number of the updates.
+	// If no update happens in this frame, the rendering result can still change e.g. by interpolation.
+	if d, ok := c.game.(GameDrawer); ok {
+		c.updateOffscreen()
+		start := time.Now()
+		c.offscreen.Clear()
+		theLayers.prepare(c.offscreen.Size())
+		theOverdraw.prepare(c.offscreen)
+		theDrawDebugger.prepare(c.offscreen)
+		setDrawingSkipped(false)
+		_ = c.callGame("Draw", func() error {
+			d.Draw(c.offscreen)
+			return nil
+		})
+		w, h := c.offscreen.Size()
+		theFrameGraph.record("draw", []string{"update"}, w, h, start)
+	}
+
+	theLayers.draw(c.offscreen)
+
 	// This clear is needed for fullscreen mode or some mobile platforms (#622).
 	c.screen.Clear()
 
+	src := c.offscreen
+	srcPass := "update"
+	if _, ok := c.game.(GameDrawer); ok {
+		srcPass = "draw"
+	}
+
+	if h := theOverdraw.heatmap(); h != nil {
+		src = h
+	}
+	src = theDrawDebugger.compose(src)
+
+	// A color matrix doesn't work with filterScreen. Apply the color vision filter to an intermediate image.
+	if f := ColorVisionFilter(); f != ColorVisionFilterNone {
+		start := time.Now()
+		if c.filtered == nil {
+			w, h := c.offscreen.Size()
+			c.filtered = newImage(w, h, FilterDefault, true)
+		}
+		op := &DrawImageOptions{}
+		op.ColorM = f.colorM()
+		op.CompositeMode = CompositeModeCopy
+		_ = c.filtered.DrawImage(src, op)
+		src = c.filtered
+
+		w, h := c.filtered.Size()
+		theFrameGraph.record("colorvision", []string{srcPass}, w, h, start)
+		srcPass = "colorvision"
+	}
+
+	start := time.Now()
+	hooked, err := runPresentHook(src)
+	if err != nil {
+		return err
+	}
+	if hasPresentHook() {
+		w, h := hooked.Size()
+		theFrameGraph.record("presenthook", []string{srcPass}, w, h, start)
+		srcPass = "presenthook"
+	}
+	src = hooked
+
+	c.updateLastFrame(src)
+	c.recordFrame()
+
+	start = time.Now()
+
 	op := &DrawImageOptions{}
 
+	// The image given by the present hook might have a different size. Stretch it to the same region.
 	s := c.screenScale()
+	sw, sh := c.offscreen.Size()
+	w, h := src.Size()
+	sx := s * float64(sw) / float64(w)
+	sy := s * float64(sh) / float64(h)
 	switch vd := uiDriver().Graphics().VDirection(); vd {
 	case driver.VDownward:
 		// c.screen is special: its Y axis is down to up,
 		// and the origin point is lower left.
-		op.GeoM.Scale(s, -s)
-		_, h := c.offscreen.Size()
-		op.GeoM.Translate(0, float64(h)*s)
+		op.GeoM.Scale(sx, -sy)
+		op.GeoM.Translate(0, float64(h)*sy)
 	case driver.VUpward:
-		op.GeoM.Scale(s, s)
+		op.GeoM.Scale(sx, sy)
 	default:
 		panic(fmt.Sprintf("ebiten: invalid v-direction: %d", vd))
 	}
@@ -305,15 +482,48 @@ func (c *uiContext) update(afterFrameUpdate func()) error {
 
 	// filterScreen works with >=1 scale, but does not well with <1 scale.
 	// Use regular FilterLinear instead so far (#669).
-	if s >= 1 {
+	if sx >= 1 && sy >= 1 {
 		op.Filter = filterScreen
 	} else {
 		op.Filter = FilterLinear
 	}
-	_ = c.screen.DrawImage(c.offscreen, op)
+	_ = c.screen.DrawImage(src, op)
+
+	fw, fh := c.screen.Size()
+	theFrameGraph.record("blit", []string{srcPass}, fw, fh, start)
 	return nil
 }
 
+// callGame calls f, which calls the game's function of the given name.
+//
+// If f panics, callGame runs the panic hooks to release resources like audio devices, and re-raises the panic
+// with the context of the frame. The window and the graphics resources are released by the UI driver while the
+// panic unwinds.
+func (c *uiContext) callGame(name string, f func() error) error {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		hooks.RunPanicHooks()
+		panic(&GamePanic{
+			Func:  name,
+			Tick:  atomic.LoadInt64(&c.tick),
+			Value: r,
+			Stack: debug.Stack(),
+		})
+	}()
+	return f()
+}
+
+// framebufferPosition converts the position on the game screen to the position on the screen framebuffer in
+// device-dependent pixels. This is the inverse of AdjustPosition.
+func (c *uiContext) framebufferPosition(x, y float64) (float64, float64) {
+	ox, oy := c.offsets()
+	s := c.screenScale()
+	return x*s + ox, y*s + oy
+}
+
 func (c *uiContext) AdjustPosition(x, y float64) (float64, float64) {
 	d := uiDriver().DeviceScaleFactor()
 	ox, oy := c.offsets()
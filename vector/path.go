@@ -18,6 +18,7 @@
 package vector
 
 import (
+	"fmt"
 	"image/color"
 	"math"
 
@@ -32,29 +33,132 @@ func init() {
 	emptyImage.Fill(color.White)
 }
 
+// Direction represents the direction of an arc.
+type Direction int
+
+const (
+	// Clockwise represents the clockwise direction on the screen, where the angle increases.
+	Clockwise Direction = iota
+
+	// CounterClockwise represents the counter-clockwise direction on the screen, where the angle decreases.
+	CounterClockwise
+)
+
+type subpath struct {
+	points []triangulate.Point
+	closed bool
+}
+
 // Path represents a collection of path segments.
 type Path struct {
-	segs [][]triangulate.Point
-	cur  triangulate.Point
+	subpaths []subpath
+	cur      triangulate.Point
 }
 
 // MoveTo skips the current position of the path to the given position (x, y) without adding any strokes.
 func (p *Path) MoveTo(x, y float32) {
 	p.cur = triangulate.Point{X: x, Y: y}
-	p.segs = append(p.segs, []triangulate.Point{p.cur})
+	p.subpaths = append(p.subpaths, subpath{
+		points: []triangulate.Point{p.cur},
+	})
 }
 
 // LineTo adds a line segument to the path, which starts from the current position and ends to the given position (x, y).
 //
 // LineTo updates the current position to (x, y).
 func (p *Path) LineTo(x, y float32) {
-	if len(p.segs) == 0 {
-		p.segs = append(p.segs, []triangulate.Point{p.cur})
+	if len(p.subpaths) == 0 || p.subpaths[len(p.subpaths)-1].closed {
+		p.subpaths = append(p.subpaths, subpath{
+			points: []triangulate.Point{p.cur},
+		})
 	}
-	p.segs[len(p.segs)-1] = append(p.segs[len(p.segs)-1], triangulate.Point{X: x, Y: y})
+	sp := &p.subpaths[len(p.subpaths)-1]
+	sp.points = append(sp.points, triangulate.Point{X: x, Y: y})
 	p.cur = triangulate.Point{X: x, Y: y}
 }
 
+// Close closes the current sub-path by connecting the current position and the start position of the sub-path.
+//
+// Close updates the current position to the start position of the sub-path. A following LineTo starts a new
+// sub-path from there.
+func (p *Path) Close() {
+	if len(p.subpaths) == 0 {
+		return
+	}
+	sp := &p.subpaths[len(p.subpaths)-1]
+	if sp.closed {
+		return
+	}
+	sp.closed = true
+	p.cur = sp.points[0]
+}
+
+func arcSegments(radius, sweep float32) int {
+	if radius < 0 {
+		radius = -radius
+	}
+	if sweep < 0 {
+		sweep = -sweep
+	}
+	// One segment per 2 pixels, and at least 32 segments for a full circle.
+	n := int(math.Ceil(float64(sweep * radius / 2)))
+	if m := int(math.Ceil(float64(sweep) / (math.Pi / 16))); n < m {
+		n = m
+	}
+	return n
+}
+
+func positiveMod(x, y float32) float32 {
+	v := float32(math.Mod(float64(x), float64(y)))
+	if v < 0 {
+		v += y
+	}
+	return v
+}
+
+// Arc adds an arc to the path. The arc is a part of the circle centered at (x, y) with the radius, and starts at
+// startAngle and ends at endAngle in radians in the given direction.
+//
+// If the path has a current sub-path, Arc adds a line segment from the current position to the start point of the
+// arc. Otherwise, Arc starts a new sub-path from the start point.
+//
+// Arc updates the current position to the end point of the arc.
+func (p *Path) Arc(x, y, radius, startAngle, endAngle float32, dir Direction) {
+	const twoPi = 2 * math.Pi
+
+	sweep := endAngle - startAngle
+	switch dir {
+	case Clockwise:
+		if sweep >= twoPi {
+			sweep = twoPi
+		} else {
+			sweep = positiveMod(sweep, twoPi)
+		}
+	case CounterClockwise:
+		if sweep <= -twoPi {
+			sweep = -twoPi
+		} else {
+			sweep = -positiveMod(-sweep, twoPi)
+		}
+	default:
+		panic(fmt.Sprintf("vector: invalid direction: %d", dir))
+	}
+
+	sx := x + radius*float32(math.Cos(float64(startAngle)))
+	sy := y + radius*float32(math.Sin(float64(startAngle)))
+	if len(p.subpaths) == 0 || p.subpaths[len(p.subpaths)-1].closed {
+		p.MoveTo(sx, sy)
+	} else {
+		p.LineTo(sx, sy)
+	}
+
+	n := arcSegments(radius, sweep)
+	for i := 1; i <= n; i++ {
+		a := float64(startAngle + sweep*float32(i)/float32(n))
+		p.LineTo(x+radius*float32(math.Cos(a)), y+radius*float32(math.Sin(a)))
+	}
+}
+
 func nseg(x0, y0, x1, y1 float32) int {
 	distx := x1 - x0
 	if distx < 0 {
@@ -92,6 +196,10 @@ func (p *Path) CubicTo(cp0x, cp0y, cp1x, cp1y, x, y float32) {
 	}
 }
 
+// Fill fills the region of the path with the given color.
+//
+// Fill assumes that the sub-paths are simple polygons that don't overlap with each other. Fill doesn't do
+// antialiasing. Use FillWithOptions for paths with holes or self-intersections, or for antialiasing.
 func (p *Path) Fill(dst *ebiten.Image, clr color.Color) {
 	var vertices []ebiten.Vertex
 	var indices []uint16
@@ -106,7 +214,8 @@ func (p *Path) Fill(dst *ebiten.Image, clr color.Color) {
 	}
 
 	var base uint16
-	for _, seg := range p.segs {
+	for _, sp := range p.subpaths {
+		seg := sp.points
 		for _, pt := range seg {
 			vertices = append(vertices, ebiten.Vertex{
 				DstX:   pt.X,
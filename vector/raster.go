@@ -0,0 +1,267 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/vector/internal/triangulate"
+)
+
+// FillRule represents the rule to determine whether a point is inside the path.
+type FillRule int
+
+const (
+	// FillRuleNonZero fills the region where the winding number of the path is not zero.
+	FillRuleNonZero FillRule = iota
+
+	// FillRuleEvenOdd fills the region where the winding number of the path is odd.
+	FillRuleEvenOdd
+)
+
+// FillOptions represents options to fill a path.
+type FillOptions struct {
+	// FillRule is the rule to determine the region to fill.
+	//
+	// The default (zero) value is FillRuleNonZero.
+	FillRule FillRule
+
+	// Antialias specifies whether the edges are antialiased.
+	//
+	// The default (zero) value is false.
+	Antialias bool
+}
+
+// The maximum number of the sub-samples in one direction for antialiasing.
+const maxSamples = 4
+
+// maskSize is the maximum size of the mask images.
+const maskSize = 4096
+
+// masks is the set of the offscreen images to rasterize paths.
+type masks struct {
+	// winding is the image to accumulate the winding numbers. The alpha value 0x80 represents zero.
+	winding *ebiten.Image
+
+	// coverage is the image that represents the region to fill.
+	coverage *ebiten.Image
+
+	size int
+	m    sync.Mutex
+}
+
+var theMasks = &masks{}
+
+func (m *masks) ensure(width, height int) {
+	s := 16
+	for s < width || s < height {
+		s *= 2
+	}
+	if s <= m.size {
+		return
+	}
+	if m.winding != nil {
+		m.winding.Dispose()
+		m.coverage.Dispose()
+	}
+	m.winding, _ = ebiten.NewImage(s, s, ebiten.FilterDefault)
+	m.coverage, _ = ebiten.NewImage(s, s, ebiten.FilterDefault)
+	m.size = s
+}
+
+// rasterizer converts the coordinates on the destination image to the coordinates on the mask images.
+type rasterizer struct {
+	bounds  image.Rectangle
+	samples int
+}
+
+func newRasterizer(dst *ebiten.Image, min, max triangulate.Point, antialias bool) (*rasterizer, bool) {
+	b := image.Rect(
+		int(math.Floor(float64(min.X))),
+		int(math.Floor(float64(min.Y))),
+		int(math.Ceil(float64(max.X))),
+		int(math.Ceil(float64(max.Y)))).Intersect(dst.Bounds())
+	if b.Empty() {
+		return nil, false
+	}
+
+	s := 1
+	if antialias {
+		s = maxSamples
+		for s > 1 && (b.Dx()*s > maskSize || b.Dy()*s > maskSize) {
+			s /= 2
+		}
+	}
+	return &rasterizer{
+		bounds:  b,
+		samples: s,
+	}, true
+}
+
+func (r *rasterizer) vertex(pt triangulate.Point, alpha float32) ebiten.Vertex {
+	s := float32(r.samples)
+	return ebiten.Vertex{
+		DstX:   (pt.X - float32(r.bounds.Min.X)) * s,
+		DstY:   (pt.Y - float32(r.bounds.Min.Y)) * s,
+		ColorR: 1,
+		ColorG: 1,
+		ColorB: 1,
+		ColorA: alpha,
+	}
+}
+
+// triangles returns the vertices and the indices of the given triangles.
+func (r *rasterizer) triangles(pts []triangulate.Point, alpha float32) ([]ebiten.Vertex, []uint16) {
+	var vs []ebiten.Vertex
+	var is []uint16
+	for _, pt := range pts {
+		vs = append(vs, r.vertex(pt, alpha))
+		is = append(is, uint16(len(is)))
+	}
+	return vs, is
+}
+
+func (r *rasterizer) drawTriangles(dst *ebiten.Image, pts []triangulate.Point, alpha float32, mode ebiten.CompositeMode) {
+	// The number of the indices is limited by uint16.
+	const maxVertices = (math.MaxUint16 + 1) / 3 * 3
+	for len(pts) > 0 {
+		n := len(pts)
+		if n > maxVertices {
+			n = maxVertices
+		}
+		vs, is := r.triangles(pts[:n], alpha)
+		op := &ebiten.DrawTrianglesOptions{}
+		op.CompositeMode = mode
+		dst.DrawTriangles(vs, is, emptyImage, op)
+		pts = pts[n:]
+	}
+}
+
+// cover draws the coverage image onto dst with the color.
+func (r *rasterizer) cover(dst *ebiten.Image, coverage *ebiten.Image, clr color.Color) {
+	cr, cg, cb, ca := clr.RGBA()
+	if ca == 0 {
+		return
+	}
+
+	s := r.samples
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(1/float64(s), 1/float64(s))
+	op.GeoM.Translate(float64(r.bounds.Min.X), float64(r.bounds.Min.Y))
+	op.ColorM.Scale(0, 0, 0, float64(ca)/0xffff)
+	op.ColorM.Translate(float64(cr)/float64(ca), float64(cg)/float64(ca), float64(cb)/float64(ca), 0)
+	if s > 1 {
+		op.Filter = ebiten.FilterLinear
+	} else {
+		op.Filter = ebiten.FilterNearest
+	}
+	dst.DrawImage(coverage.SubImage(image.Rect(0, 0, r.bounds.Dx()*s, r.bounds.Dy()*s)).(*ebiten.Image), op)
+}
+
+func bounds(pts []triangulate.Point) (triangulate.Point, triangulate.Point) {
+	min := triangulate.Point{X: float32(math.Inf(1)), Y: float32(math.Inf(1))}
+	max := triangulate.Point{X: float32(math.Inf(-1)), Y: float32(math.Inf(-1))}
+	for _, pt := range pts {
+		min.X = float32(math.Min(float64(min.X), float64(pt.X)))
+		min.Y = float32(math.Min(float64(min.Y), float64(pt.Y)))
+		max.X = float32(math.Max(float64(max.X), float64(pt.X)))
+		max.Y = float32(math.Max(float64(max.Y), float64(pt.Y)))
+	}
+	return min, max
+}
+
+// fanTriangles returns the triangles of the fans from the first points of the sub-paths. The triangles are split
+// into the two groups by their orientations.
+//
+// The sum of the signed triangles is the winding number at any point, even when the sub-paths intersect with each
+// other or themselves.
+func (p *Path) fanTriangles() (positive, negative []triangulate.Point) {
+	for _, sp := range p.subpaths {
+		pts := sp.points
+		for i := 1; i < len(pts)-1; i++ {
+			p0, p1, p2 := pts[0], pts[i], pts[i+1]
+			c := (p1.X-p0.X)*(p2.Y-p0.Y) - (p1.Y-p0.Y)*(p2.X-p0.X)
+			switch {
+			case c > 0:
+				positive = append(positive, p0, p1, p2)
+			case c < 0:
+				negative = append(negative, p0, p1, p2)
+			}
+		}
+	}
+	return
+}
+
+// FillWithOptions fills the region of the path with the given color.
+//
+// Unlike Fill, FillWithOptions can fill any paths including ones with holes or self-intersections, based on the
+// fill rule. FillWithOptions renders the path via offscreen images, and is slower than Fill.
+//
+// If options is nil, the default options are used.
+func (p *Path) FillWithOptions(dst *ebiten.Image, clr color.Color, options *FillOptions) {
+	if options == nil {
+		options = &FillOptions{}
+	}
+
+	pos, neg := p.fanTriangles()
+	if len(pos) == 0 && len(neg) == 0 {
+		return
+	}
+	min, max := bounds(append(pos, neg...))
+	r, ok := newRasterizer(dst, min, max, options.Antialias)
+	if !ok {
+		return
+	}
+
+	theMasks.m.Lock()
+	defer theMasks.m.Unlock()
+
+	s := r.samples
+	theMasks.ensure(r.bounds.Dx()*s, r.bounds.Dy()*s)
+	coverage := theMasks.coverage
+	coverage.Clear()
+
+	switch options.FillRule {
+	case FillRuleNonZero:
+		winding := theMasks.winding
+		winding.Fill(color.RGBA{0x80, 0x80, 0x80, 0x80})
+		r.drawTriangles(winding, pos, 1.0/0xff, ebiten.CompositeModeLighter)
+		r.drawTriangles(winding, neg, 1.0/0xff, ebiten.CompositeModeSubtract)
+
+		// Convert the winding numbers into the coverage. The alpha values are clamped to [0, 1] when rendering.
+		sub := winding.SubImage(image.Rect(0, 0, r.bounds.Dx()*s, r.bounds.Dy()*s)).(*ebiten.Image)
+		op := &ebiten.DrawImageOptions{}
+		op.ColorM.Scale(1, 1, 1, 0xff)
+		op.ColorM.Translate(0, 0, 0, -0x80)
+		coverage.DrawImage(sub, op)
+
+		op = &ebiten.DrawImageOptions{}
+		op.ColorM.Scale(1, 1, 1, -0xff)
+		op.ColorM.Translate(0, 0, 0, 0x80)
+		op.CompositeMode = ebiten.CompositeModeLighter
+		coverage.DrawImage(sub, op)
+	case FillRuleEvenOdd:
+		r.drawTriangles(coverage, append(pos, neg...), 1, ebiten.CompositeModeXor)
+	default:
+		panic(fmt.Sprintf("vector: invalid fill rule: %d", options.FillRule))
+	}
+
+	r.cover(dst, coverage, clr)
+}
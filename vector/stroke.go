@@ -0,0 +1,287 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/vector/internal/triangulate"
+)
+
+// LineCap represents the shape of the ends of a stroke.
+type LineCap int
+
+const (
+	// LineCapButt ends a stroke at the end point.
+	LineCapButt LineCap = iota
+
+	// LineCapRound ends a stroke with a semicircle.
+	LineCapRound
+
+	// LineCapSquare ends a stroke with a square that extends beyond the end point by the half of the width.
+	LineCapSquare
+)
+
+// LineJoin represents the shape of the corners of a stroke.
+type LineJoin int
+
+const (
+	// LineJoinMiter joins the segments with a sharp corner. If the miter length exceeds MiterLimit, the corner is
+	// beveled instead.
+	LineJoinMiter LineJoin = iota
+
+	// LineJoinBevel joins the segments with a cut-off corner.
+	LineJoinBevel
+
+	// LineJoinRound joins the segments with a round corner.
+	LineJoinRound
+)
+
+// StrokeOptions represents options to stroke a path.
+type StrokeOptions struct {
+	// Width is the width of the stroke in pixels.
+	//
+	// The default (zero) value is 1.
+	Width float32
+
+	// LineCap is the shape of the ends of the stroke.
+	//
+	// The default (zero) value is LineCapButt.
+	LineCap LineCap
+
+	// LineJoin is the shape of the corners of the stroke.
+	//
+	// The default (zero) value is LineJoinMiter.
+	LineJoin LineJoin
+
+	// MiterLimit is the limit of the ratio of the miter length to the half of the width.
+	//
+	// The default (zero) value is 10.
+	MiterLimit float32
+
+	// Antialias specifies whether the edges are antialiased.
+	//
+	// The default (zero) value is false.
+	Antialias bool
+}
+
+func add(p0, p1 triangulate.Point) triangulate.Point {
+	return triangulate.Point{X: p0.X + p1.X, Y: p0.Y + p1.Y}
+}
+
+func sub(p0, p1 triangulate.Point) triangulate.Point {
+	return triangulate.Point{X: p0.X - p1.X, Y: p0.Y - p1.Y}
+}
+
+func scale(p triangulate.Point, s float32) triangulate.Point {
+	return triangulate.Point{X: p.X * s, Y: p.Y * s}
+}
+
+func dot(p0, p1 triangulate.Point) float32 {
+	return p0.X*p1.X + p0.Y*p1.Y
+}
+
+// normalize returns the unit vector of p.
+func normalize(p triangulate.Point) triangulate.Point {
+	l := float32(math.Hypot(float64(p.X), float64(p.Y)))
+	return triangulate.Point{X: p.X / l, Y: p.Y / l}
+}
+
+// stroker generates the triangles of a stroke.
+type stroker struct {
+	halfWidth  float32
+	lineCap    LineCap
+	lineJoin   LineJoin
+	miterLimit float32
+
+	triangles []triangulate.Point
+}
+
+func (s *stroker) quad(p0, p1, p2, p3 triangulate.Point) {
+	s.triangles = append(s.triangles, p0, p1, p2, p0, p2, p3)
+}
+
+func (s *stroker) circle(center triangulate.Point) {
+	n := arcSegments(s.halfWidth, 2*math.Pi)
+	prev := add(center, triangulate.Point{X: s.halfWidth})
+	for i := 1; i <= n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		pt := add(center, triangulate.Point{
+			X: s.halfWidth * float32(math.Cos(a)),
+			Y: s.halfWidth * float32(math.Sin(a)),
+		})
+		s.triangles = append(s.triangles, center, prev, pt)
+		prev = pt
+	}
+}
+
+// normal returns the normal vector of the segment from p0 to p1 with the length of the half width.
+func (s *stroker) normal(p0, p1 triangulate.Point) triangulate.Point {
+	d := normalize(sub(p1, p0))
+	return triangulate.Point{X: -d.Y * s.halfWidth, Y: d.X * s.halfWidth}
+}
+
+func (s *stroker) segment(p0, p1 triangulate.Point) {
+	n := s.normal(p0, p1)
+	s.quad(add(p0, n), add(p1, n), sub(p1, n), sub(p0, n))
+}
+
+// join adds the corner at p between the segment from p0 to p and the segment from p to p1.
+func (s *stroker) join(p0, p, p1 triangulate.Point) {
+	if s.lineJoin == LineJoinRound {
+		s.circle(p)
+		return
+	}
+
+	d0 := normalize(sub(p, p0))
+	d1 := normalize(sub(p1, p))
+	n0 := s.normal(p0, p)
+	n1 := s.normal(p, p1)
+
+	// The outer side of the corner is the opposite side of the turn.
+	side := dot(n0, d1)
+	if side == 0 {
+		return
+	}
+	if side > 0 {
+		n0 = scale(n0, -1)
+		n1 = scale(n1, -1)
+	}
+
+	if s.lineJoin == LineJoinMiter {
+		if c := 1 + dot(d0, d1); c > 0 {
+			// The ratio of the miter length to the half width is 1/cos(θ/2) = sqrt(2/(1+cos(θ))).
+			if ratio := float32(math.Sqrt(float64(2 / c))); ratio <= s.miterLimit {
+				m := add(p, scale(add(n0, n1), 1/c))
+				s.quad(p, add(p, n0), m, add(p, n1))
+				return
+			}
+		}
+	}
+	s.triangles = append(s.triangles, p, add(p, n0), add(p, n1))
+}
+
+// cap adds the end of the stroke at p, where d is the unit vector from the inside of the stroke to p.
+func (s *stroker) cap(p, d triangulate.Point) {
+	switch s.lineCap {
+	case LineCapButt:
+	case LineCapRound:
+		s.circle(p)
+	case LineCapSquare:
+		n := triangulate.Point{X: -d.Y * s.halfWidth, Y: d.X * s.halfWidth}
+		e := add(p, scale(d, s.halfWidth))
+		s.quad(add(p, n), add(e, n), sub(e, n), sub(p, n))
+	default:
+		panic(fmt.Sprintf("vector: invalid line cap: %d", s.lineCap))
+	}
+}
+
+func (s *stroker) subpath(sp subpath) {
+	// Remove the duplicated points, which don't have directions.
+	var pts []triangulate.Point
+	for _, pt := range sp.points {
+		if len(pts) > 0 && pts[len(pts)-1] == pt {
+			continue
+		}
+		pts = append(pts, pt)
+	}
+	if sp.closed && len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+		pts = pts[:len(pts)-1]
+	}
+
+	if len(pts) == 0 {
+		return
+	}
+	if len(pts) == 1 {
+		switch s.lineCap {
+		case LineCapRound:
+			s.circle(pts[0])
+		case LineCapSquare:
+			p := pts[0]
+			hw := triangulate.Point{X: s.halfWidth, Y: s.halfWidth}
+			s.quad(sub(p, hw), triangulate.Point{X: p.X + s.halfWidth, Y: p.Y - s.halfWidth}, add(p, hw), triangulate.Point{X: p.X - s.halfWidth, Y: p.Y + s.halfWidth})
+		}
+		return
+	}
+
+	for i := 0; i < len(pts)-1; i++ {
+		s.segment(pts[i], pts[i+1])
+	}
+	for i := 1; i < len(pts)-1; i++ {
+		s.join(pts[i-1], pts[i], pts[i+1])
+	}
+
+	if sp.closed && len(pts) > 2 {
+		first, last := pts[0], pts[len(pts)-1]
+		s.segment(last, first)
+		s.join(pts[len(pts)-2], last, first)
+		s.join(last, first, pts[1])
+		return
+	}
+
+	s.cap(pts[0], normalize(sub(pts[0], pts[1])))
+	s.cap(pts[len(pts)-1], normalize(sub(pts[len(pts)-1], pts[len(pts)-2])))
+}
+
+// Stroke draws the outline of the path with the given color.
+//
+// The overlapping parts of the stroke are rendered only once, so the stroke with a translucent color looks even.
+//
+// If options is nil, the default options are used.
+func (p *Path) Stroke(dst *ebiten.Image, clr color.Color, options *StrokeOptions) {
+	if options == nil {
+		options = &StrokeOptions{}
+	}
+	if options.Width < 0 {
+		panic(fmt.Sprintf("vector: width must be non-negative but %f", options.Width))
+	}
+
+	s := &stroker{
+		halfWidth:  options.Width / 2,
+		lineCap:    options.LineCap,
+		lineJoin:   options.LineJoin,
+		miterLimit: options.MiterLimit,
+	}
+	if s.halfWidth == 0 {
+		s.halfWidth = 0.5
+	}
+	if s.miterLimit == 0 {
+		s.miterLimit = 10
+	}
+	for _, sp := range p.subpaths {
+		s.subpath(sp)
+	}
+	if len(s.triangles) == 0 {
+		return
+	}
+
+	min, max := bounds(s.triangles)
+	r, ok := newRasterizer(dst, min, max, options.Antialias)
+	if !ok {
+		return
+	}
+
+	theMasks.m.Lock()
+	defer theMasks.m.Unlock()
+
+	theMasks.ensure(r.bounds.Dx()*r.samples, r.bounds.Dy()*r.samples)
+	coverage := theMasks.coverage
+	coverage.Clear()
+	r.drawTriangles(coverage, s.triangles, 1, ebiten.CompositeModeSourceOver)
+	r.cover(dst, coverage, clr)
+}
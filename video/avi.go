@@ -0,0 +1,268 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package video
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// segment represents a region of the source.
+type segment struct {
+	offset int64
+	size   int64
+}
+
+type aviStream struct {
+	kind    string
+	handler string
+	scale   uint32
+	rate    uint32
+	format  []byte
+}
+
+// aviInfo is the result of parsing an AVI file.
+type aviInfo struct {
+	width         int
+	height        int
+	frameDuration time.Duration
+	frames        []segment
+
+	// audioFormat is WAVEFORMATEX of the audio stream. audioFormat is nil if there is no audio stream.
+	audioFormat []byte
+	audio       []segment
+}
+
+type aviParser struct {
+	r       io.ReadSeeker
+	streams []*aviStream
+
+	microSecPerFrame uint32
+	width            int
+	height           int
+
+	// chunks is the chunks in 'movi' lists for each stream.
+	chunks map[int][]segment
+}
+
+func (p *aviParser) readAt(buf []byte, offset int64) error {
+	if _, err := p.r.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(p.r, buf); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *aviParser) readChunkHeader(offset int64) (string, int64, error) {
+	var buf [8]byte
+	if err := p.readAt(buf[:], offset); err != nil {
+		return "", 0, err
+	}
+	return string(buf[:4]), int64(binary.LittleEndian.Uint32(buf[4:])), nil
+}
+
+// parseAVI parses the structure of the AVI file. The frame data is not read.
+func parseAVI(r io.ReadSeeker) (*aviInfo, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &aviParser{
+		r:      r,
+		chunks: map[int][]segment{},
+	}
+
+	// An OpenDML (AVI 2.0) file has additional 'RIFF' lists with the 'AVIX' type after the first one.
+	for offset := int64(0); offset+12 <= size; {
+		id, s, err := p.readChunkHeader(offset)
+		if err != nil {
+			return nil, err
+		}
+		var typ [4]byte
+		if err := p.readAt(typ[:], offset+8); err != nil {
+			return nil, err
+		}
+		if id != "RIFF" || (offset == 0 && string(typ[:]) != "AVI ") {
+			if offset == 0 {
+				return nil, fmt.Errorf("video: not an AVI file: only Motion JPEG in AVI is supported")
+			}
+			break
+		}
+		end := offset + 8 + s
+		if end > size {
+			end = size
+		}
+		if err := p.parseList(offset+12, end, false); err != nil {
+			return nil, err
+		}
+		offset = end + s&1
+	}
+
+	return p.info()
+}
+
+func (p *aviParser) parseList(start, end int64, inMovi bool) error {
+	for offset := start; offset+8 <= end; {
+		id, size, err := p.readChunkHeader(offset)
+		if err != nil {
+			return err
+		}
+		body := offset + 8
+		if body+size > end {
+			// The file might be truncated.
+			size = end - body
+		}
+
+		switch id {
+		case "LIST":
+			if size < 4 {
+				break
+			}
+			var typ [4]byte
+			if err := p.readAt(typ[:], body); err != nil {
+				return err
+			}
+			if err := p.parseList(body+4, body+size, inMovi || string(typ[:]) == "movi"); err != nil {
+				return err
+			}
+		case "avih":
+			if size < 40 {
+				return fmt.Errorf("video: invalid 'avih' chunk")
+			}
+			buf := make([]byte, 40)
+			if err := p.readAt(buf, body); err != nil {
+				return err
+			}
+			p.microSecPerFrame = binary.LittleEndian.Uint32(buf[0:4])
+			p.width = int(binary.LittleEndian.Uint32(buf[32:36]))
+			p.height = int(binary.LittleEndian.Uint32(buf[36:40]))
+		case "strh":
+			if size < 28 {
+				return fmt.Errorf("video: invalid 'strh' chunk")
+			}
+			buf := make([]byte, 28)
+			if err := p.readAt(buf, body); err != nil {
+				return err
+			}
+			p.streams = append(p.streams, &aviStream{
+				kind:    string(buf[0:4]),
+				handler: string(buf[4:8]),
+				scale:   binary.LittleEndian.Uint32(buf[20:24]),
+				rate:    binary.LittleEndian.Uint32(buf[24:28]),
+			})
+		case "strf":
+			if len(p.streams) == 0 {
+				return fmt.Errorf("video: 'strf' chunk without 'strh' chunk")
+			}
+			buf := make([]byte, size)
+			if err := p.readAt(buf, body); err != nil {
+				return err
+			}
+			p.streams[len(p.streams)-1].format = buf
+		default:
+			if !inMovi {
+				break
+			}
+			// The ID of a data chunk consists of the stream number and the type, e.g., '00dc'.
+			n, err := strconv.Atoi(id[:2])
+			if err != nil {
+				break
+			}
+			p.chunks[n] = append(p.chunks[n], segment{offset: body, size: size})
+		}
+
+		offset = body + size + size&1
+	}
+	return nil
+}
+
+func isMJPEG(s *aviStream) bool {
+	if s.handler == "MJPG" || s.handler == "mjpg" {
+		return true
+	}
+	// The compression field of BITMAPINFOHEADER.
+	if len(s.format) >= 20 {
+		if c := string(s.format[16:20]); c == "MJPG" || c == "mjpg" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *aviParser) info() (*aviInfo, error) {
+	info := &aviInfo{
+		width:  p.width,
+		height: p.height,
+	}
+
+	videoFound := false
+	audioFound := false
+	for i, s := range p.streams {
+		switch s.kind {
+		case "vids":
+			if videoFound {
+				continue
+			}
+			if !isMJPEG(s) {
+				return nil, fmt.Errorf("video: unsupported video codec: %q: only Motion JPEG is supported", s.handler)
+			}
+			videoFound = true
+			if len(s.format) >= 12 {
+				// BITMAPINFOHEADER's biHeight can be negative for top-down images.
+				info.width = int(int32(binary.LittleEndian.Uint32(s.format[4:8])))
+				info.height = int(int32(binary.LittleEndian.Uint32(s.format[8:12])))
+				if info.height < 0 {
+					info.height = -info.height
+				}
+			}
+			if s.scale != 0 && s.rate != 0 {
+				info.frameDuration = time.Duration(int64(time.Second) * int64(s.scale) / int64(s.rate))
+			} else {
+				info.frameDuration = time.Duration(p.microSecPerFrame) * time.Microsecond
+			}
+			info.frames = p.chunks[i]
+		case "auds":
+			if audioFound {
+				continue
+			}
+			audioFound = true
+			if len(s.format) < 16 {
+				return nil, fmt.Errorf("video: invalid audio format")
+			}
+			if tag := binary.LittleEndian.Uint16(s.format[0:2]); tag != 1 {
+				return nil, fmt.Errorf("video: unsupported audio format: %d: only linear PCM is supported", tag)
+			}
+			info.audioFormat = s.format
+			info.audio = p.chunks[i]
+		}
+	}
+
+	if !videoFound {
+		return nil, fmt.Errorf("video: video stream not found")
+	}
+	if info.width <= 0 || info.height <= 0 {
+		return nil, fmt.Errorf("video: invalid video size: %dx%d", info.width, info.height)
+	}
+	if info.frameDuration <= 0 {
+		return nil, fmt.Errorf("video: invalid frame rate")
+	}
+	return info, nil
+}
@@ -0,0 +1,233 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func chunk(id string, data []byte) []byte {
+	b := []byte(id)
+	b = appendUint32(b, uint32(len(data)))
+	b = append(b, data...)
+	if len(data)%2 == 1 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+func list(typ string, chunks ...[]byte) []byte {
+	data := []byte(typ)
+	for _, c := range chunks {
+		data = append(data, c...)
+	}
+	return chunk("LIST", data)
+}
+
+func le32(vs ...uint32) []byte {
+	var b []byte
+	for _, v := range vs {
+		b = appendUint32(b, v)
+	}
+	return b
+}
+
+func le16(vs ...uint16) []byte {
+	var b []byte
+	for _, v := range vs {
+		var buf [2]byte
+		binary.LittleEndian.PutUint16(buf[:], v)
+		b = append(b, buf[:]...)
+	}
+	return b
+}
+
+func encodeJPEG(t *testing.T, w, h int, clr color.Color) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			img.Set(i, j, clr)
+		}
+	}
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func createAVI(frames [][]byte, audio [][]byte) []byte {
+	const (
+		w = 16
+		h = 8
+	)
+	avih := make([]byte, 56)
+	copy(avih, le32(40000))
+	copy(avih[32:], le32(w, h))
+
+	vstrh := make([]byte, 56)
+	copy(vstrh, "vidsMJPG")
+	copy(vstrh[20:], le32(1, 25))
+	vstrf := le32(40, w, h)
+	vstrf = append(vstrf, le16(1, 24)...)
+	vstrf = append(vstrf, "MJPG"...)
+	vstrf = append(vstrf, make([]byte, 20)...)
+
+	astrh := make([]byte, 56)
+	copy(astrh, "auds")
+	copy(astrh[20:], le32(1, 22050))
+	astrf := le16(1, 1)
+	astrf = append(astrf, le32(22050, 44100)...)
+	astrf = append(astrf, le16(2, 16)...)
+
+	var movi [][]byte
+	for i := range frames {
+		movi = append(movi, chunk("00dc", frames[i]))
+		if i < len(audio) {
+			movi = append(movi, chunk("01wb", audio[i]))
+		}
+	}
+
+	hdrl := list("hdrl",
+		chunk("avih", avih),
+		list("strl", chunk("strh", vstrh), chunk("strf", vstrf)),
+		list("strl", chunk("strh", astrh), chunk("strf", astrf)),
+	)
+	body := []byte("AVI ")
+	body = append(body, hdrl...)
+	body = append(body, list("movi", movi...)...)
+	body = append(body, chunk("idx1", nil)...)
+	return chunk("RIFF", body)
+}
+
+func TestParseAVI(t *testing.T) {
+	frames := [][]byte{[]byte("frame0"), []byte("frame01"), {}}
+	audio := [][]byte{[]byte("ab"), []byte("cdef"), []byte("g")}
+	data := createAVI(frames, audio)
+
+	info, err := parseAVI(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.width != 16 || info.height != 8 {
+		t.Errorf("size: got: %dx%d, want: 16x8", info.width, info.height)
+	}
+	if got, want := info.frameDuration, 40*time.Millisecond; got != want {
+		t.Errorf("frameDuration: got: %v, want: %v", got, want)
+	}
+	if got, want := len(info.frames), len(frames); got != want {
+		t.Fatalf("len(frames): got: %d, want: %d", got, want)
+	}
+	for i, f := range info.frames {
+		if got, want := string(data[f.offset:f.offset+f.size]), string(frames[i]); got != want {
+			t.Errorf("frames[%d]: got: %q, want: %q", i, got, want)
+		}
+	}
+	if got, want := len(info.audioFormat), 16; got != want {
+		t.Errorf("len(audioFormat): got: %d, want: %d", got, want)
+	}
+
+	// The audio chunks are read as a WAV stream.
+	var size int64
+	for _, s := range info.audio {
+		size += s.size
+	}
+	src := &source{r: bytes.NewReader(data)}
+	r := newSegmentReader(src, wavHeader(info.audioFormat, size), info.audio)
+	wav, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(wav[:4]), "RIFF"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if got, want := string(wav[len(wav)-7:]), "abcdefg"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+	if _, err := r.Seek(-3, 2); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(rest), "efg"; got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+func TestParseAVIUnsupported(t *testing.T) {
+	data := createAVI([][]byte{[]byte("frame")}, nil)
+	i := bytes.Index(data, []byte("vidsMJPG"))
+	copy(data[i+4:], "VP90")
+	j := bytes.LastIndex(data, []byte("MJPG"))
+	copy(data[j:], "VP90")
+	if _, err := parseAVI(bytes.NewReader(data)); err == nil {
+		t.Errorf("parseAVI must return an error for an unsupported codec")
+	}
+
+	if _, err := parseAVI(bytes.NewReader([]byte("not an AVI file"))); err == nil {
+		t.Errorf("parseAVI must return an error for a non-AVI file")
+	}
+}
+
+func removeHuffmanTables(data []byte) []byte {
+	r := append([]byte{}, data[:2]...)
+	for i := 2; i+4 <= len(data) && data[i] == 0xff; {
+		if data[i+1] == 0xda {
+			return append(r, data[i:]...)
+		}
+		next := i + 2 + (int(data[i+2])<<8 | int(data[i+3]))
+		if data[i+1] != 0xc4 {
+			r = append(r, data[i:next]...)
+		}
+		i = next
+	}
+	return r
+}
+
+func TestWithHuffmanTables(t *testing.T) {
+	want := color.RGBA{0xff, 0x80, 0x00, 0xff}
+	data := removeHuffmanTables(encodeJPEG(t, 16, 16, want))
+	if bytes.Contains(data[:20], []byte{0xff, 0xc4}) {
+		t.Fatal("the Huffman tables must be removed")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err == nil {
+		t.Fatal("decoding a JPEG without Huffman tables must fail")
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(withHuffmanTables(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, g, b, _ := img.At(8, 8).RGBA()
+	got := color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xff}
+	diff := func(a, b uint8) int {
+		if a > b {
+			return int(a - b)
+		}
+		return int(b - a)
+	}
+	if diff(got.R, want.R) > 4 || diff(got.G, want.G) > 4 || diff(got.B, want.B) > 4 {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
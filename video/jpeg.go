@@ -0,0 +1,86 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package video
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"sync"
+)
+
+var (
+	defaultHuffmanTables     []byte
+	defaultHuffmanTablesOnce sync.Once
+)
+
+// withHuffmanTables returns a JPEG data with the Huffman tables.
+//
+// Motion JPEG frames might omit the Huffman tables (DHT), and use the default tables defined in the JPEG
+// specification instead. image/jpeg doesn't treat such data, then the default tables are inserted.
+func withHuffmanTables(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return data
+	}
+
+	// Walk the marker segments until the SOS marker.
+	for i := 2; i+4 <= len(data) && data[i] == 0xff; {
+		marker := data[i+1]
+		if marker == 0xc4 {
+			return data
+		}
+		if marker == 0xda {
+			break
+		}
+		i += 2 + (int(data[i+2])<<8 | int(data[i+3]))
+	}
+
+	defaultHuffmanTablesOnce.Do(func() {
+		defaultHuffmanTables = extractHuffmanTables()
+	})
+	if defaultHuffmanTables == nil {
+		return data
+	}
+	r := make([]byte, 0, len(data)+len(defaultHuffmanTables))
+	r = append(r, data[:2]...)
+	r = append(r, defaultHuffmanTables...)
+	r = append(r, data[2:]...)
+	return r
+}
+
+// extractHuffmanTables returns the DHT segments generated by image/jpeg's encoder, that always uses the default
+// tables.
+func extractHuffmanTables() []byte {
+	// Use a color image so that the chrominance tables are also written.
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		return nil
+	}
+
+	data := b.Bytes()
+	var r []byte
+	for i := 2; i+4 <= len(data) && data[i] == 0xff; {
+		marker := data[i+1]
+		if marker == 0xda {
+			break
+		}
+		next := i + 2 + (int(data[i+2])<<8 | int(data[i+3]))
+		if marker == 0xc4 {
+			r = append(r, data[i:next]...)
+		}
+		i = next
+	}
+	return r
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package video
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// source is a concurrent-safe wrapper of io.ReadSeeker.
+//
+// The audio stream is read on a different goroutine from the video stream.
+type source struct {
+	r io.ReadSeeker
+	m sync.Mutex
+}
+
+func (s *source) readAt(buf []byte, offset int64) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, err := s.r.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(s.r, buf); err != nil {
+		return err
+	}
+	return nil
+}
+
+// segmentReader is an io.ReadSeeker that reads the concatenation of the header and the segments of the source.
+type segmentReader struct {
+	src      *source
+	header   []byte
+	segments []segment
+
+	// starts is the start positions of the segments in the stream.
+	starts []int64
+	size   int64
+	pos    int64
+}
+
+func newSegmentReader(src *source, header []byte, segments []segment) *segmentReader {
+	s := &segmentReader{
+		src:      src,
+		header:   header,
+		segments: segments,
+	}
+	pos := int64(len(header))
+	for _, seg := range segments {
+		s.starts = append(s.starts, pos)
+		pos += seg.size
+	}
+	s.size = pos
+	return s
+}
+
+func (s *segmentReader) Read(buf []byte) (int, error) {
+	if s.pos >= s.size {
+		return 0, io.EOF
+	}
+	if s.pos < int64(len(s.header)) {
+		n := copy(buf, s.header[s.pos:])
+		s.pos += int64(n)
+		return n, nil
+	}
+
+	i := sort.Search(len(s.starts), func(i int) bool {
+		return s.starts[i]+s.segments[i].size > s.pos
+	})
+	seg := s.segments[i]
+	offset := s.pos - s.starts[i]
+	if rest := seg.size - offset; int64(len(buf)) > rest {
+		buf = buf[:rest]
+	}
+	if err := s.src.readAt(buf, seg.offset+offset); err != nil {
+		return 0, err
+	}
+	s.pos += int64(len(buf))
+	return len(buf), nil
+}
+
+func (s *segmentReader) Seek(offset int64, whence int) (int64, error) {
+	next := int64(0)
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = s.pos + offset
+	case io.SeekEnd:
+		next = s.size + offset
+	default:
+		return 0, fmt.Errorf("video: invalid whence: %d", whence)
+	}
+	if next < 0 {
+		return 0, fmt.Errorf("video: invalid offset: %d", next)
+	}
+	s.pos = next
+	return s.pos, nil
+}
+
+func (s *segmentReader) Close() error {
+	return nil
+}
+
+// wavHeader returns a WAV header for the given WAVEFORMATEX and the data size.
+func wavHeader(format []byte, dataSize int64) []byte {
+	b := make([]byte, 0, 20+len(format)+8)
+	b = append(b, "RIFF"...)
+	b = appendUint32(b, uint32(4+8+len(format)+8+int(dataSize)))
+	b = append(b, "WAVE"...)
+	b = append(b, "fmt "...)
+	b = appendUint32(b, uint32(len(format)))
+	b = append(b, format...)
+	b = append(b, "data"...)
+	b = appendUint32(b, uint32(dataSize))
+	return b
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
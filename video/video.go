@@ -0,0 +1,353 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package video provides a video player that renders frames onto an ebiten.Image.
+//
+// Only Motion JPEG in AVI container is supported. The audio stream must be linear PCM, and is played via the audio
+// package. The other containers like MP4 or WebM and the other codecs like H.264, Theora or VP9 are not supported,
+// and NewPlayer returns an error for them.
+//
+// Here is an example:
+//
+//     p, err := video.NewPlayer(audioContext, f)
+//     if err != nil {
+//         return err
+//     }
+//     if err := p.Play(); err != nil {
+//         return err
+//     }
+//
+//     func update(screen *ebiten.Image) error {
+//         if err := p.Update(); err != nil {
+//             return err
+//         }
+//         screen.DrawImage(p.Image(), nil)
+//         return nil
+//     }
+//
+// This package is under experiments and the API might be changed with breaking backward compatibility.
+package video
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"time"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/audio"
+	"github.com/hajimehoshi/ebiten/audio/wav"
+	"github.com/hajimehoshi/ebiten/yuv"
+)
+
+// Player is a video player.
+type Player struct {
+	src  *source
+	info *aviInfo
+
+	audioPlayer *audio.Player
+
+	image *ebiten.Image
+	rgba  *image.RGBA
+	buf   []byte
+
+	// yuvShader reports whether Y'CbCr frames are converted into RGB on GPU.
+	yuvShader bool
+	ycbcr     *yuv.Image
+
+	// frame is the index of the frame currently rendered on the image. -1 means no frame is rendered.
+	frame int
+
+	playing bool
+
+	// offset and startTime are used to determine the current position when the audio is not played.
+	offset    time.Duration
+	startTime time.Time
+
+	closed bool
+}
+
+// NewPlayer creates a new video player for src.
+//
+// audioContext can be nil. If audioContext is nil, the audio stream is ignored.
+//
+// NewPlayer returns error when decoding the header fails, the format is not supported, or IO error happens.
+//
+// NewPlayer takes the ownership of src. src must not be used by others while the player is used.
+// If src is an io.Closer, Close closes src.
+func NewPlayer(audioContext *audio.Context, src io.ReadSeeker) (*Player, error) {
+	info, err := parseAVI(src)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := ebiten.NewImage(info.width, info.height, ebiten.FilterDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Player{
+		src:   &source{r: src},
+		info:  info,
+		image: img,
+		rgba:  image.NewRGBA(image.Rect(0, 0, info.width, info.height)),
+		frame: -1,
+	}
+
+	if audioContext != nil && info.audioFormat != nil {
+		var size int64
+		for _, s := range info.audio {
+			size += s.size
+		}
+		r := newSegmentReader(p.src, wavHeader(info.audioFormat, size), info.audio)
+		s, err := wav.Decode(audioContext, r)
+		if err != nil {
+			return nil, err
+		}
+		ap, err := audio.NewPlayer(audioContext, s)
+		if err != nil {
+			return nil, err
+		}
+		p.audioPlayer = ap
+	}
+
+	return p, nil
+}
+
+// Play starts playing the video.
+//
+// Play does nothing when the video is already played or reaches the end.
+func (p *Player) Play() error {
+	if p.playing {
+		return nil
+	}
+	if p.Current() >= p.Duration() {
+		return nil
+	}
+	p.playing = true
+	p.startTime = time.Now()
+	if p.audioPlayer != nil {
+		if err := p.audioPlayer.Play(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pause pauses the video.
+func (p *Player) Pause() error {
+	if !p.playing {
+		return nil
+	}
+	p.offset = p.Current()
+	p.playing = false
+	if p.audioPlayer != nil {
+		if err := p.audioPlayer.Pause(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsPlaying reports whether the video is being played.
+func (p *Player) IsPlaying() bool {
+	return p.playing
+}
+
+// Seek seeks the position to the given offset.
+//
+// The image is updated at the next Update.
+func (p *Player) Seek(offset time.Duration) error {
+	if offset < 0 {
+		offset = 0
+	}
+	if d := p.Duration(); offset > d {
+		offset = d
+	}
+	p.offset = offset
+	p.startTime = time.Now()
+	if p.audioPlayer != nil {
+		if err := p.audioPlayer.Seek(offset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Current returns the current position.
+//
+// When the video has an audio stream, the position follows the audio player so that the video and the audio are
+// synchronized.
+func (p *Player) Current() time.Duration {
+	var c time.Duration
+	switch {
+	case p.audioPlayer != nil && (!p.playing || p.audioPlayer.IsPlaying()):
+		c = p.audioPlayer.Current()
+	case p.playing:
+		// The audio stream might be shorter than the video stream.
+		c = p.offset + time.Since(p.startTime)
+	default:
+		c = p.offset
+	}
+	if d := p.Duration(); c > d {
+		c = d
+	}
+	return c
+}
+
+// Duration returns the duration of the video.
+func (p *Player) Duration() time.Duration {
+	return time.Duration(len(p.info.frames)) * p.info.frameDuration
+}
+
+// Size returns the size of the video frames.
+func (p *Player) Size() (width, height int) {
+	return p.info.width, p.info.height
+}
+
+// Image returns the image the current frame is rendered on.
+//
+// The returned image is always the same and its content is updated at Update.
+func (p *Player) Image() *ebiten.Image {
+	return p.image
+}
+
+// Update updates the image with the frame at the current position.
+//
+// Update should be called every frame. Only the frame at the current position is decoded, and frames are skipped
+// when the game is slower than the video.
+//
+// Update returns error when decoding the frame fails or IO error happens.
+func (p *Player) Update() error {
+	if p.closed {
+		return nil
+	}
+
+	c := p.Current()
+	if p.playing && c >= p.Duration() {
+		if err := p.Pause(); err != nil {
+			return err
+		}
+	}
+
+	n := len(p.info.frames)
+	if n == 0 {
+		return nil
+	}
+	f := int(c / p.info.frameDuration)
+	if f >= n {
+		f = n - 1
+	}
+	if f == p.frame {
+		return nil
+	}
+	if err := p.renderFrame(f); err != nil {
+		return err
+	}
+	p.frame = f
+	return nil
+}
+
+func (p *Player) renderFrame(index int) error {
+	// An empty chunk means the previous frame is repeated.
+	for index > 0 && p.info.frames[index].size == 0 {
+		index--
+	}
+	seg := p.info.frames[index]
+	if seg.size == 0 {
+		return nil
+	}
+
+	if int64(cap(p.buf)) < seg.size {
+		p.buf = make([]byte, seg.size)
+	}
+	buf := p.buf[:seg.size]
+	if err := p.src.readAt(buf, seg.offset); err != nil {
+		return err
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(withHuffmanTables(buf)))
+	if err != nil {
+		return err
+	}
+	if y, ok := img.(*image.YCbCr); ok && p.yuvShader && y.Rect.Size() == p.rgba.Rect.Size() {
+		return p.renderYCbCr(y)
+	}
+	draw.Draw(p.rgba, p.rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return p.image.ReplacePixels(p.rgba.Pix)
+}
+
+func (p *Player) renderYCbCr(img *image.YCbCr) error {
+	if p.ycbcr != nil && p.ycbcr.SubsampleRatio() != img.SubsampleRatio {
+		p.ycbcr.Dispose()
+		p.ycbcr = nil
+	}
+	if p.ycbcr == nil {
+		w, h := img.Rect.Dx(), img.Rect.Dy()
+		y, err := yuv.NewImage(w, h, img.SubsampleRatio)
+		if err != nil {
+			return err
+		}
+		p.ycbcr = y
+	}
+	if err := p.ycbcr.ReplacePixels(img); err != nil {
+		return err
+	}
+	p.ycbcr.Draw(p.image, &yuv.DrawOptions{
+		CompositeMode: ebiten.CompositeModeCopy,
+	})
+	return nil
+}
+
+// IsYUVShaderEnabled reports whether the frames are converted into RGB by a shader.
+func (p *Player) IsYUVShaderEnabled() bool {
+	return p.yuvShader
+}
+
+// SetYUVShaderEnabled sets whether the frames are converted from Y'CbCr into RGB by a shader on GPU.
+//
+// When enabled, the decoded Y'CbCr planes are uploaded as they are and no color conversion is done on CPU.
+// See the yuv package for details. Custom shaders are available only with OpenGL for now.
+//
+// The initial value is false.
+func (p *Player) SetYUVShaderEnabled(enabled bool) {
+	p.yuvShader = enabled
+}
+
+// Close stops the video and releases the resources.
+func (p *Player) Close() error {
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	p.playing = false
+	if p.audioPlayer != nil {
+		if err := p.audioPlayer.Close(); err != nil {
+			return err
+		}
+	}
+	if err := p.image.Dispose(); err != nil {
+		return err
+	}
+	if p.ycbcr != nil {
+		p.ycbcr.Dispose()
+		p.ycbcr = nil
+	}
+	if c, ok := p.src.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image"
+)
+
+// Standard aspect ratios, width divided by height.
+const (
+	AspectRatio4x3   = 4.0 / 3.0
+	AspectRatio16x9  = 16.0 / 9.0
+	AspectRatio16x10 = 16.0 / 10.0
+	AspectRatio21x9  = 21.0 / 9.0
+)
+
+// AspectMode represents how a virtual resolution is fitted to the outside size of a game.
+type AspectMode int
+
+const (
+	// AspectModeKeep keeps the virtual resolution. Bars are shown at the sides or at the top and the bottom when
+	// the aspect ratio of the outside doesn't match.
+	AspectModeKeep AspectMode = iota
+
+	// AspectModeExpand expands the screen either horizontally or vertically so that no bars are shown.
+	// The whole virtual resolution is always visible.
+	AspectModeExpand
+
+	// AspectModeExpandVertically keeps the width and adjusts the height to the aspect ratio of the outside.
+	// The height can be less than the virtual resolution's.
+	AspectModeExpandVertically
+
+	// AspectModeExpandHorizontally keeps the height and adjusts the width to the aspect ratio of the outside.
+	// The width can be less than the virtual resolution's.
+	AspectModeExpandHorizontally
+)
+
+// VirtualResolution represents a resolution that a game is designed for, and how it is fitted to the outside.
+//
+// A typical usage is to call Layout in the game's Layout, and to draw the world translated by the negated Min of
+// VisibleRect:
+//
+//     var res = ebiten.VirtualResolution{Width: 320, Height: 180, Mode: ebiten.AspectModeExpand}
+//
+//     func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+//         return res.Layout(outsideWidth, outsideHeight)
+//     }
+type VirtualResolution struct {
+	Width  int
+	Height int
+	Mode   AspectMode
+}
+
+// Layout returns the screen size for the given outside size. Layout can be used as the game's Layout.
+//
+// Layout panics if Width or Height is not positive, or Mode is invalid.
+func (v VirtualResolution) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	if v.Width <= 0 || v.Height <= 0 {
+		panic(fmt.Sprintf("ebiten: the virtual resolution must be positive but (%d, %d)", v.Width, v.Height))
+	}
+	if outsideWidth <= 0 || outsideHeight <= 0 {
+		return v.Width, v.Height
+	}
+
+	// Compare the aspect ratios with integers to avoid rounding errors.
+	wider := outsideWidth*v.Height >= outsideHeight*v.Width
+	switch v.Mode {
+	case AspectModeKeep:
+		return v.Width, v.Height
+	case AspectModeExpand:
+		if wider {
+			return ceilDiv(outsideWidth*v.Height, outsideHeight), v.Height
+		}
+		return v.Width, ceilDiv(outsideHeight*v.Width, outsideWidth)
+	case AspectModeExpandVertically:
+		return v.Width, ceilDiv(outsideHeight*v.Width, outsideWidth)
+	case AspectModeExpandHorizontally:
+		return ceilDiv(outsideWidth*v.Height, outsideHeight), v.Height
+	default:
+		panic(fmt.Sprintf("ebiten: invalid aspect mode: %d", v.Mode))
+	}
+}
+
+// VisibleRect returns the visible region of the world, where the virtual resolution is the region from (0, 0) to
+// (Width, Height) at the center of the screen. screenWidth and screenHeight are the screen size returned by
+// Layout.
+//
+// Translate the world by the negated Min of the returned rectangle to draw it onto the screen.
+func (v VirtualResolution) VisibleRect(screenWidth, screenHeight int) image.Rectangle {
+	x := (screenWidth - v.Width) / 2
+	y := (screenHeight - v.Height) / 2
+	return image.Rect(-x, -y, screenWidth-x, screenHeight-y)
+}
+
+func ceilDiv(x, y int) int {
+	return (x + y - 1) / y
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"image"
+	"testing"
+
+	. "github.com/hajimehoshi/ebiten"
+)
+
+func TestVirtualResolutionLayout(t *testing.T) {
+	cases := []struct {
+		Mode          AspectMode
+		OutsideWidth  int
+		OutsideHeight int
+		Width         int
+		Height        int
+		VisibleRect   image.Rectangle
+	}{
+		{AspectModeKeep, 1920, 1080, 320, 180, image.Rect(0, 0, 320, 180)},
+		{AspectModeKeep, 800, 600, 320, 180, image.Rect(0, 0, 320, 180)},
+		{AspectModeExpand, 1920, 1080, 320, 180, image.Rect(0, 0, 320, 180)},
+		{AspectModeExpand, 800, 600, 320, 240, image.Rect(0, -30, 320, 210)},
+		{AspectModeExpand, 2560, 1080, 427, 180, image.Rect(-53, 0, 374, 180)},
+		{AspectModeExpandVertically, 800, 600, 320, 240, image.Rect(0, -30, 320, 210)},
+		{AspectModeExpandVertically, 2560, 1080, 320, 135, image.Rect(0, 22, 320, 157)},
+		{AspectModeExpandHorizontally, 800, 600, 240, 180, image.Rect(40, 0, 280, 180)},
+		{AspectModeExpandHorizontally, 0, 0, 320, 180, image.Rect(0, 0, 320, 180)},
+	}
+	for _, c := range cases {
+		v := VirtualResolution{Width: 320, Height: 180, Mode: c.Mode}
+		w, h := v.Layout(c.OutsideWidth, c.OutsideHeight)
+		if w != c.Width || h != c.Height {
+			t.Errorf("mode: %d, outside: (%d, %d): got: (%d, %d), want: (%d, %d)", c.Mode, c.OutsideWidth, c.OutsideHeight, w, h, c.Width, c.Height)
+		}
+		if got := v.VisibleRect(w, h); got != c.VisibleRect {
+			t.Errorf("mode: %d, outside: (%d, %d): VisibleRect: got: %v, want: %v", c.Mode, c.OutsideWidth, c.OutsideHeight, got, c.VisibleRect)
+		}
+	}
+}
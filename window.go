@@ -246,3 +246,38 @@ func SetWindowSize(width, height int) {
 		w.SetSize(width, height)
 	}
 }
+
+// IsWindowFocused reports whether the window has input focus on desktops.
+// On the other environments, IsWindowFocused returns the same value as IsForeground.
+//
+// IsWindowFocused is useful e.g. to mute audio when the player switches to another window.
+//
+// IsWindowFocused is concurrent-safe.
+func IsWindowFocused() bool {
+	if w := uiDriver().Window(); w != nil {
+		return w.IsFocused()
+	}
+	return IsForeground()
+}
+
+// IsWindowMinimized reports whether the window is minimized (iconified) on desktops.
+// IsWindowMinimized always returns false on other environments.
+//
+// IsWindowMinimized is concurrent-safe.
+func IsWindowMinimized() bool {
+	if w := uiDriver().Window(); w != nil {
+		return w.IsMinimized()
+	}
+	return false
+}
+
+// IsWindowHovered reports whether the mouse cursor is over the window's content area on desktops.
+// IsWindowHovered always returns false on other environments.
+//
+// IsWindowHovered is concurrent-safe.
+func IsWindowHovered() bool {
+	if w := uiDriver().Window(); w != nil {
+		return w.IsHovered()
+	}
+	return false
+}
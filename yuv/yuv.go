@@ -0,0 +1,317 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package yuv provides images that hold Y'CbCr (YUV) planes on GPU and convert them into RGB with a shader.
+//
+// The planes are uploaded as they are, and then no color conversion is done on CPU. This is useful for video
+// frames and camera feeds, that are usually in Y'CbCr.
+//
+// Custom shaders are available only with OpenGL for now, and so is this package.
+//
+// Note that this package is experimental.
+package yuv
+
+import (
+	"fmt"
+	"image"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// ColorSpace represents how Y'CbCr values are converted into RGB values.
+type ColorSpace int
+
+const (
+	// ColorSpaceJPEG is the full-range BT.601 color space, used by JPEG.
+	// This is the same conversion as color.YCbCrToRGB.
+	ColorSpaceJPEG ColorSpace = iota
+
+	// ColorSpaceBT601 is the limited-range (16-235) BT.601 color space, used by SD videos.
+	ColorSpaceBT601
+
+	// ColorSpaceBT709 is the limited-range (16-235) BT.709 color space, used by HD videos.
+	ColorSpaceBT709
+)
+
+const shaderSrc = `package main
+
+// Size is the size of the planes texture in texels.
+var Size vec2
+
+// Frame is the size of the frame in pixels.
+var Frame vec2
+
+// Subsample is the chroma subsampling ratio.
+var Subsample vec2
+
+// Chroma is the height of a chroma plane in texels.
+var Chroma float
+
+// Matrix converts Y'CbCr values into RGB values.
+var Matrix mat4
+
+// plane returns the byte value at (x, y) of the planes. Each texel has 4 bytes.
+func plane(x float, y float) float {
+	tx := floor(x / 4)
+	r := texture0Region()
+	c := texture0At(r.xy + (vec2(tx, y)+0.5)*(r.zw-r.xy)/Size)
+	m := 1 - step(0.5, abs(vec4(0, 1, 2, 3)-(x-tx*4)))
+	return dot(c, m)
+}
+
+func Fragment(position vec2, texCoord vec2, color vec4) vec4 {
+	r := texture0Region()
+	p := floor((texCoord - r.xy) / (r.zw - r.xy) * vec2(Size.x*4, Frame.y))
+	if p.x >= Frame.x {
+		// This is the padding of the rows.
+		return vec4(0)
+	}
+	cp := floor(p / Subsample)
+	y := plane(p.x, p.y)
+	cb := plane(cp.x, Frame.y+cp.y)
+	cr := plane(cp.x, Frame.y+Chroma+cp.y)
+	rgb := Matrix * vec4(y, cb, cr, 1)
+	return vec4(clamp(rgb.xyz, 0, 1), 1) * color
+}
+`
+
+var (
+	theShader     *ebiten.Shader
+	theShaderErr  error
+	theShaderOnce sync.Once
+)
+
+func compiledShader() (*ebiten.Shader, error) {
+	theShaderOnce.Do(func() {
+		theShader, theShaderErr = ebiten.NewShader([]byte(shaderSrc))
+	})
+	return theShader, theShaderErr
+}
+
+// Image is a Y'CbCr image on GPU.
+type Image struct {
+	width      int
+	height     int
+	ratio      image.YCbCrSubsampleRatio
+	colorSpace ColorSpace
+
+	// planes is the texture that has the Y, Cb and Cr planes in this order from the top.
+	// The rows of each plane is padded to the multiple of 4 bytes, and each texel has 4 bytes.
+	planes *ebiten.Image
+
+	buf []byte
+}
+
+// NewImage returns a new Y'CbCr image with the given size in pixels and the chroma subsampling ratio.
+//
+// The initial color space is ColorSpaceJPEG.
+//
+// NewImage returns an error when compiling the shader fails.
+func NewImage(width, height int, ratio image.YCbCrSubsampleRatio) (*Image, error) {
+	if width <= 0 || height <= 0 {
+		panic(fmt.Sprintf("yuv: width and height must be positive but %d x %d", width, height))
+	}
+	if _, _, ok := subsample(ratio); !ok {
+		panic(fmt.Sprintf("yuv: unsupported subsample ratio: %v", ratio))
+	}
+	if _, err := compiledShader(); err != nil {
+		return nil, err
+	}
+
+	i := &Image{
+		width:  width,
+		height: height,
+		ratio:  ratio,
+	}
+	w, h := i.planesSize()
+	p, err := ebiten.NewImage(w, h, ebiten.FilterNearest)
+	if err != nil {
+		return nil, err
+	}
+	i.planes = p
+	i.buf = make([]byte, 4*w*h)
+	return i, nil
+}
+
+// Size returns the size of the image in pixels.
+func (i *Image) Size() (width, height int) {
+	return i.width, i.height
+}
+
+// SubsampleRatio returns the chroma subsampling ratio of the image.
+func (i *Image) SubsampleRatio() image.YCbCrSubsampleRatio {
+	return i.ratio
+}
+
+// ColorSpace returns the color space of the image.
+func (i *Image) ColorSpace() ColorSpace {
+	return i.colorSpace
+}
+
+// SetColorSpace sets the color space of the image.
+func (i *Image) SetColorSpace(colorSpace ColorSpace) {
+	i.colorSpace = colorSpace
+}
+
+// ReplacePixels replaces the planes of the image with src's.
+//
+// The size of src's Rect and src's SubsampleRatio must be the same as the image's. Otherwise, ReplacePixels panics.
+//
+// The planes are only copied into one buffer and uploaded. No color conversion is done on CPU.
+func (i *Image) ReplacePixels(src *image.YCbCr) error {
+	if s := src.Rect.Size(); s.X != i.width || s.Y != i.height {
+		panic(fmt.Sprintf("yuv: the source size must be %d x %d but %d x %d", i.width, i.height, s.X, s.Y))
+	}
+	if src.SubsampleRatio != i.ratio {
+		panic(fmt.Sprintf("yuv: the source subsample ratio must be %v but %v", i.ratio, src.SubsampleRatio))
+	}
+	i.pack(src)
+	return i.planes.ReplacePixels(i.buf)
+}
+
+// DrawOptions represents options for Draw.
+type DrawOptions struct {
+	// GeoM is a geometry matrix to draw.
+	// The default (zero) value is identity, which draws the image at (0, 0).
+	GeoM ebiten.GeoM
+
+	// CompositeMode is a composite mode to draw.
+	// The default (zero) value is regular alpha blending.
+	CompositeMode ebiten.CompositeMode
+}
+
+// Draw converts the image into RGB and draws it onto dst.
+//
+// The frame is sampled with the nearest filter. To scale the frame smoothly, draw the image onto an intermediate
+// image with the same size first.
+func (i *Image) Draw(dst *ebiten.Image, options *DrawOptions) {
+	if options == nil {
+		options = &DrawOptions{}
+	}
+	s, err := compiledShader()
+	if err != nil {
+		// NewImage already succeeded in compiling the shader.
+		panic(fmt.Sprintf("yuv: compiling the shader failed: %v", err))
+	}
+
+	w, h := i.planesSize()
+	sx, sy, _ := subsample(i.ratio)
+	m := colorMatrix(i.colorSpace)
+
+	op := &ebiten.DrawRectShaderOptions{}
+	// The planes texture is taller and narrower than the frame. Stretch the rectangle to the frame size.
+	op.GeoM.Scale(4, float64(i.height)/float64(h))
+	op.GeoM.Concat(options.GeoM)
+	op.CompositeMode = options.CompositeMode
+	op.Image = i.planes
+	op.Uniforms = map[string]interface{}{
+		"Size":      []float32{float32(w), float32(h)},
+		"Frame":     []float32{float32(i.width), float32(i.height)},
+		"Subsample": []float32{float32(sx), float32(sy)},
+		"Chroma":    float32(i.chromaHeight()),
+		"Matrix":    m[:],
+	}
+	dst.DrawRectShader(w, h, s, op)
+}
+
+// Dispose disposes the image.
+func (i *Image) Dispose() {
+	_ = i.planes.Dispose()
+}
+
+// planesSize returns the size of the planes texture in texels.
+func (i *Image) planesSize() (width, height int) {
+	return (i.width + 3) / 4, i.height + 2*i.chromaHeight()
+}
+
+func (i *Image) chromaWidth() int {
+	sx, _, _ := subsample(i.ratio)
+	return (i.width + sx - 1) / sx
+}
+
+func (i *Image) chromaHeight() int {
+	_, sy, _ := subsample(i.ratio)
+	return (i.height + sy - 1) / sy
+}
+
+// pack copies the planes of src into i.buf.
+func (i *Image) pack(src *image.YCbCr) {
+	stride := 4 * ((i.width + 3) / 4)
+	cw, ch := i.chromaWidth(), i.chromaHeight()
+
+	yo := src.YOffset(src.Rect.Min.X, src.Rect.Min.Y)
+	for j := 0; j < i.height; j++ {
+		copy(i.buf[j*stride:j*stride+i.width], src.Y[yo+j*src.YStride:])
+	}
+	co := src.COffset(src.Rect.Min.X, src.Rect.Min.Y)
+	for j := 0; j < ch; j++ {
+		cb := (i.height + j) * stride
+		cr := (i.height + ch + j) * stride
+		copy(i.buf[cb:cb+cw], src.Cb[co+j*src.CStride:])
+		copy(i.buf[cr:cr+cw], src.Cr[co+j*src.CStride:])
+	}
+}
+
+// subsample returns the horizontal and vertical chroma subsampling factors.
+func subsample(ratio image.YCbCrSubsampleRatio) (int, int, bool) {
+	switch ratio {
+	case image.YCbCrSubsampleRatio444:
+		return 1, 1, true
+	case image.YCbCrSubsampleRatio422:
+		return 2, 1, true
+	case image.YCbCrSubsampleRatio420:
+		return 2, 2, true
+	case image.YCbCrSubsampleRatio440:
+		return 1, 2, true
+	case image.YCbCrSubsampleRatio411:
+		return 4, 1, true
+	case image.YCbCrSubsampleRatio410:
+		return 4, 2, true
+	}
+	return 0, 0, false
+}
+
+// colorMatrix returns the matrix to convert normalized (Y', Cb, Cr, 1) into (R, G, B, 1) in column-major order.
+func colorMatrix(colorSpace ColorSpace) [16]float32 {
+	kr, kb := 0.299, 0.114
+	ys, yo, cs := 1.0, 0.0, 1.0
+	switch colorSpace {
+	case ColorSpaceBT601:
+		ys, yo, cs = 255.0/219, 16.0/255, 255.0/224
+	case ColorSpaceBT709:
+		kr, kb = 0.2126, 0.0722
+		ys, yo, cs = 255.0/219, 16.0/255, 255.0/224
+	}
+	kg := 1 - kr - kb
+
+	rcr := 2 * (1 - kr) * cs
+	gcb := -2 * kb * (1 - kb) / kg * cs
+	gcr := -2 * kr * (1 - kr) / kg * cs
+	bcb := 2 * (1 - kb) * cs
+
+	rows := [4][4]float64{
+		{ys, 0, rcr, -ys*yo - rcr*0.5},
+		{ys, gcb, gcr, -ys*yo - (gcb+gcr)*0.5},
+		{ys, bcb, 0, -ys*yo - bcb*0.5},
+		{0, 0, 0, 1},
+	}
+	var m [16]float32
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			m[c*4+r] = float32(rows[r][c])
+		}
+	}
+	return m
+}
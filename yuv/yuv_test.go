@@ -0,0 +1,99 @@
+// Copyright 2020 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yuv
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/internal/shader"
+)
+
+func TestShader(t *testing.T) {
+	if _, err := shader.Compile([]byte(shaderSrc)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestColorMatrixJPEG(t *testing.T) {
+	m := colorMatrix(ColorSpaceJPEG)
+	for _, c := range []color.YCbCr{
+		{0, 128, 128},
+		{255, 128, 128},
+		{76, 85, 255},
+		{150, 44, 21},
+		{29, 255, 107},
+		{100, 200, 50},
+	} {
+		v := [4]float64{float64(c.Y) / 255, float64(c.Cb) / 255, float64(c.Cr) / 255, 1}
+		var got [3]uint8
+		for r := 0; r < 3; r++ {
+			var x float64
+			for k := 0; k < 4; k++ {
+				x += float64(m[k*4+r]) * v[k]
+			}
+			got[r] = uint8(math.Round(math.Max(0, math.Min(1, x)) * 255))
+		}
+		r, g, b := color.YCbCrToRGB(c.Y, c.Cb, c.Cr)
+		want := [3]uint8{r, g, b}
+		for k := range got {
+			if d := int(got[k]) - int(want[k]); d < -1 || 1 < d {
+				t.Errorf("%v: got: %v, want: %v", c, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestPack(t *testing.T) {
+	const w, h = 5, 3
+	src := image.NewYCbCr(image.Rect(0, 0, w, h), image.YCbCrSubsampleRatio420)
+	for i := range src.Y {
+		src.Y[i] = byte(i + 1)
+	}
+	for i := range src.Cb {
+		src.Cb[i] = byte(0x40 + i)
+		src.Cr[i] = byte(0x80 + i)
+	}
+
+	i := &Image{
+		width:  w,
+		height: h,
+		ratio:  image.YCbCrSubsampleRatio420,
+	}
+	pw, ph := i.planesSize()
+	if pw != 2 || ph != 7 {
+		t.Fatalf("planesSize(): got: (%d, %d), want: (2, 7)", pw, ph)
+	}
+	i.buf = make([]byte, 4*pw*ph)
+	i.pack(src)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if got, want := i.buf[y*8+x], src.Y[src.YOffset(x, y)]; got != want {
+				t.Errorf("Y at (%d, %d): got: %d, want: %d", x, y, got, want)
+			}
+			co := src.COffset(x, y)
+			if got, want := i.buf[(h+y/2)*8+x/2], src.Cb[co]; got != want {
+				t.Errorf("Cb at (%d, %d): got: %d, want: %d", x, y, got, want)
+			}
+			if got, want := i.buf[(h+2+y/2)*8+x/2], src.Cr[co]; got != want {
+				t.Errorf("Cr at (%d, %d): got: %d, want: %d", x, y, got, want)
+			}
+		}
+	}
+}